@@ -0,0 +1,37 @@
+// Command mpesa-simulator posts sample M-Pesa C2B callbacks to a running storeHouse server.
+//
+//	go run ./cmd/mpesa-simulator -msisdn 254712345678 -amount 1500 -ref TITHE
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"storeHouse/mpesa"
+)
+
+func main() {
+	baseURL := flag.String("url", "http://localhost:8080", "base URL of the storeHouse API")
+	shortCode := flag.String("shortcode", os.Getenv("MPESA_SHORTCODE"), "Paybill short code")
+	token := flag.String("token", os.Getenv("MPESA_CALLBACK_TOKEN"), "callback token, if the server requires one")
+	msisdn := flag.String("msisdn", "254712345678", "payer phone number")
+	amount := flag.Float64("amount", 1000, "amount paid")
+	ref := flag.String("ref", "", "account reference (BillRefNumber) entered by the payer")
+	count := flag.Int("count", 1, "number of payments to send")
+	flag.Parse()
+
+	sim := mpesa.NewSimulator(*baseURL, *shortCode, *token)
+
+	for i := 0; i < *count; i++ {
+		callback := sim.SampleCallback(*msisdn, *amount, *ref)
+
+		validation, confirmation, err := sim.Pay(callback)
+		if err != nil {
+			log.Fatalf("❌ %s: %v", callback.TransID, err)
+		}
+
+		log.Printf("📨 %s: validation %s (%s), confirmation %s (%s)", callback.TransID,
+			validation.ResultCode, validation.ResultDesc, confirmation.ResultCode, confirmation.ResultDesc)
+	}
+}
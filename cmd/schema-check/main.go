@@ -0,0 +1,32 @@
+// Command schema-check compares the database schema with the models and repository
+// queries, the same check the server runs before it starts, and lists every difference.
+//
+//	go run ./cmd/schema-check -migrate
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"storeHouse/database"
+	"storeHouse/repository"
+)
+
+func main() {
+	migrate := flag.Bool("migrate", false, "apply pending migrations before checking")
+	flag.Parse()
+
+	db := database.ConnectDB()
+	defer db.Close()
+
+	if *migrate {
+		database.ApplyMigrations(db)
+	}
+
+	if err := repository.CheckSchema(context.Background(), db); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	log.Println("✅ Database schema matches the models and queries")
+}
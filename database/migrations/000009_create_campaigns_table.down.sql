@@ -0,0 +1,2 @@
+-- Rollback: Drop campaigns table
+DROP TABLE IF EXISTS campaigns CASCADE;
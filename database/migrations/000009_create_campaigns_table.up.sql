@@ -0,0 +1,18 @@
+-- Fundraising campaigns (e.g. building projects) tied to an income account
+CREATE TABLE campaigns (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    campaign_name VARCHAR(100) NOT NULL,
+    income_account UUID NOT NULL REFERENCES accounts(id),
+    target_amount NUMERIC(12,2),
+    start_date DATE NOT NULL DEFAULT CURRENT_DATE,
+    end_date DATE,
+    notes TEXT,
+    is_active BOOLEAN DEFAULT true,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_campaigns_income_account ON campaigns(income_account);
+
+COMMENT ON TABLE campaigns IS 'Fundraising campaigns that members pledge towards';
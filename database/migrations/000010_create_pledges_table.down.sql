@@ -0,0 +1,3 @@
+-- Rollback: Drop pledge tables
+DROP TABLE IF EXISTS pledge_payments;
+DROP TABLE IF EXISTS pledges CASCADE;
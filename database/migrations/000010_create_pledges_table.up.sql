@@ -0,0 +1,35 @@
+-- Member pledges towards a campaign, paid over a schedule
+CREATE TABLE pledges (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    member UUID NOT NULL REFERENCES members(id),
+    amount NUMERIC(12,2) NOT NULL CHECK (amount > 0),
+    frequency VARCHAR(20) NOT NULL DEFAULT 'once'
+        CHECK (frequency IN ('once', 'weekly', 'monthly', 'quarterly', 'annually')),
+    installments INTEGER NOT NULL DEFAULT 1 CHECK (installments > 0),
+    start_date DATE NOT NULL DEFAULT CURRENT_DATE,
+    status VARCHAR(20) NOT NULL DEFAULT 'open'
+        CHECK (status IN ('open', 'fulfilled', 'cancelled')),
+    notes TEXT,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_pledges_campaign ON pledges(campaign_id);
+CREATE INDEX idx_pledges_member ON pledges(member);
+
+-- Receipt amounts applied against pledges
+CREATE TABLE pledge_payments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    pledge_id UUID NOT NULL REFERENCES pledges(id) ON DELETE CASCADE,
+    receipt_id UUID NOT NULL REFERENCES receipts(id) ON DELETE CASCADE,
+    amount NUMERIC(12,2) NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_pledge_payments_pledge ON pledge_payments(pledge_id);
+CREATE INDEX idx_pledge_payments_receipt ON pledge_payments(receipt_id);
+
+COMMENT ON TABLE pledges IS 'Member pledges towards fundraising campaigns';
+COMMENT ON TABLE pledge_payments IS 'Receipts matched against open pledges';
@@ -0,0 +1,2 @@
+-- Rollback: Drop budgets table
+DROP TABLE IF EXISTS budgets CASCADE;
@@ -0,0 +1,17 @@
+-- Monthly budget lines for Income and Expense accounts
+CREATE TABLE budgets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    fiscal_year INT NOT NULL,
+    month INT NOT NULL CHECK (month BETWEEN 1 AND 12),
+    amount NUMERIC(12,2) NOT NULL DEFAULT 0 CHECK (amount >= 0),
+    notes TEXT,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (account, fiscal_year, month)
+);
+
+CREATE INDEX idx_budgets_fiscal_year ON budgets(fiscal_year);
+
+COMMENT ON TABLE budgets IS 'Annual account budgets broken down by month';
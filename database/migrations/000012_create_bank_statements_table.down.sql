@@ -0,0 +1,3 @@
+-- Rollback: Drop bank statement tables
+DROP TABLE IF EXISTS bank_statement_lines CASCADE;
+DROP TABLE IF EXISTS bank_statements CASCADE;
@@ -0,0 +1,40 @@
+-- Imported bank statements and their lines for reconciling Bank accounts
+CREATE TABLE bank_statements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    bank_account UUID NOT NULL REFERENCES accounts(id),
+    file_name VARCHAR(255),
+    format VARCHAR(10) NOT NULL CHECK (format IN ('csv', 'ofx')),
+    period_start DATE,
+    period_end DATE,
+    opening_balance NUMERIC(12,2),
+    closing_balance NUMERIC(12,2),
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE bank_statement_lines (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    statement_id UUID NOT NULL REFERENCES bank_statements(id) ON DELETE CASCADE,
+    bank_account UUID NOT NULL REFERENCES accounts(id),
+    line_date DATE NOT NULL,
+    description VARCHAR(255),
+    reference VARCHAR(100),
+    amount NUMERIC(12,2) NOT NULL,
+    balance NUMERIC(12,2),
+    fit_id VARCHAR(255),
+    transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+    match_type VARCHAR(10) CHECK (match_type IN ('auto', 'manual')),
+    matched_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_bank_statements_bank_account ON bank_statements(bank_account);
+CREATE INDEX idx_bank_statement_lines_statement ON bank_statement_lines(statement_id);
+CREATE INDEX idx_bank_statement_lines_bank_account ON bank_statement_lines(bank_account, line_date);
+CREATE UNIQUE INDEX idx_bank_statement_lines_fit_id ON bank_statement_lines(bank_account, fit_id) WHERE fit_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_bank_statement_lines_transaction ON bank_statement_lines(bank_account, transaction_id) WHERE transaction_id IS NOT NULL;
+
+COMMENT ON TABLE bank_statements IS 'Bank statements imported for reconciliation';
+COMMENT ON TABLE bank_statement_lines IS 'Bank statement lines, matched to the transactions they clear';
@@ -0,0 +1,2 @@
+-- Rollback: Drop mpesa_transactions table
+DROP TABLE IF EXISTS mpesa_transactions CASCADE;
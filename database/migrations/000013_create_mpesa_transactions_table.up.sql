@@ -0,0 +1,26 @@
+-- M-Pesa C2B payments received through Paybill callbacks
+CREATE TABLE mpesa_transactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trans_id VARCHAR(20) NOT NULL UNIQUE,
+    trans_type VARCHAR(50),
+    trans_time TIMESTAMP NOT NULL,
+    amount NUMERIC(12,2) NOT NULL,
+    business_short_code VARCHAR(20),
+    bill_ref_number VARCHAR(50),
+    msisdn VARCHAR(100),
+    payer_name VARCHAR(150),
+    member UUID REFERENCES members(id),
+    income_account UUID REFERENCES accounts(id),
+    transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'received'
+        CHECK (status IN ('received', 'posted', 'failed')),
+    error TEXT,
+    payload JSONB,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_mpesa_transactions_status ON mpesa_transactions(status);
+CREATE INDEX idx_mpesa_transactions_msisdn ON mpesa_transactions(msisdn);
+
+COMMENT ON TABLE mpesa_transactions IS 'M-Pesa C2B payments received through Paybill callbacks';
@@ -0,0 +1,3 @@
+-- Rollback: Drop mobile money import tables
+DROP TABLE IF EXISTS mobile_money_lines CASCADE;
+DROP TABLE IF EXISTS mobile_money_imports CASCADE;
@@ -0,0 +1,42 @@
+-- Mobile money (M-Pesa, Airtel Money) statement imports awaiting review before posting
+CREATE TABLE mobile_money_imports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    provider VARCHAR(20) NOT NULL CHECK (provider IN ('mpesa', 'airtel')),
+    file_name VARCHAR(255),
+    receiving_account UUID NOT NULL REFERENCES accounts(id),
+    income_account UUID REFERENCES accounts(id),
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE mobile_money_lines (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    import_id UUID NOT NULL REFERENCES mobile_money_imports(id) ON DELETE CASCADE,
+    provider VARCHAR(20) NOT NULL,
+    trans_code VARCHAR(30) NOT NULL,
+    trans_time TIMESTAMP NOT NULL,
+    amount NUMERIC(12,2) NOT NULL CHECK (amount > 0),
+    msisdn VARCHAR(100),
+    payer_name VARCHAR(150),
+    account_ref VARCHAR(100),
+    details TEXT,
+    suggested_member UUID REFERENCES members(id),
+    member UUID REFERENCES members(id),
+    income_account UUID REFERENCES accounts(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'duplicate', 'posted', 'rejected')),
+    transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+    notes TEXT,
+    reviewed_by UUID REFERENCES users(id),
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (provider, trans_code)
+);
+
+CREATE INDEX idx_mobile_money_lines_import ON mobile_money_lines(import_id);
+CREATE INDEX idx_mobile_money_lines_status ON mobile_money_lines(status);
+
+COMMENT ON TABLE mobile_money_imports IS 'Mobile money statement imports';
+COMMENT ON TABLE mobile_money_lines IS 'Mobile money statement lines queued for treasurer review';
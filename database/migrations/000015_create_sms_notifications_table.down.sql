@@ -0,0 +1,3 @@
+-- Rollback: Drop sms_notifications table and member opt-out
+DROP TABLE IF EXISTS sms_notifications CASCADE;
+ALTER TABLE members DROP COLUMN IF EXISTS sms_opt_out;
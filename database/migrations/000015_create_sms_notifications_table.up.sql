@@ -0,0 +1,30 @@
+-- SMS notifications sent to members, such as receipt acknowledgements
+ALTER TABLE members ADD COLUMN sms_opt_out BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE sms_notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind VARCHAR(30) NOT NULL DEFAULT 'receipt',
+    member UUID REFERENCES members(id) ON DELETE CASCADE,
+    phone_number VARCHAR(20) NOT NULL,
+    transaction_id UUID REFERENCES transactions(id) ON DELETE CASCADE,
+    message TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued'
+        CHECK (status IN ('queued', 'sent', 'delivered', 'failed', 'skipped')),
+    provider VARCHAR(30),
+    provider_message_id VARCHAR(100),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    send_after TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP,
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One acknowledgement per receipt transaction, however many receipt lines it has
+CREATE UNIQUE INDEX idx_sms_notifications_transaction ON sms_notifications(transaction_id, kind)
+    WHERE transaction_id IS NOT NULL;
+CREATE INDEX idx_sms_notifications_status ON sms_notifications(status, send_after);
+CREATE INDEX idx_sms_notifications_provider_id ON sms_notifications(provider_message_id);
+
+COMMENT ON TABLE sms_notifications IS 'SMS notifications sent to members with delivery status';
@@ -0,0 +1,3 @@
+-- Rollback: Drop email_attachments and email_outbox tables
+DROP TABLE IF EXISTS email_attachments CASCADE;
+DROP TABLE IF EXISTS email_outbox CASCADE;
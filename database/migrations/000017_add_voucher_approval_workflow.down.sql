@@ -0,0 +1,8 @@
+-- Rollback: Drop voucher_approvals table and transaction workflow columns
+DROP TABLE IF EXISTS voucher_approvals CASCADE;
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS posted_at,
+    DROP COLUMN IF EXISTS posted_by,
+    DROP COLUMN IF EXISTS submitted_at,
+    DROP COLUMN IF EXISTS submitted_by,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,24 @@
+-- Maker-checker workflow for expense, withdrawal and transfer vouchers. Existing
+-- transactions and receipts are already in the books, so they start as posted.
+ALTER TABLE transactions
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'posted'
+        CHECK (status IN ('draft', 'submitted', 'approved', 'rejected', 'posted')),
+    ADD COLUMN submitted_by UUID REFERENCES users(id),
+    ADD COLUMN submitted_at TIMESTAMP,
+    ADD COLUMN posted_by UUID REFERENCES users(id),
+    ADD COLUMN posted_at TIMESTAMP;
+
+CREATE TABLE voucher_approvals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    approver UUID NOT NULL REFERENCES users(id),
+    decision VARCHAR(20) NOT NULL CHECK (decision IN ('approved', 'rejected')),
+    comment TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (transaction_id, approver)
+);
+
+CREATE INDEX idx_transactions_status ON transactions(status);
+CREATE INDEX idx_voucher_approvals_transaction ON voucher_approvals(transaction_id);
+
+COMMENT ON TABLE voucher_approvals IS 'Approve and reject decisions recorded against submitted vouchers';
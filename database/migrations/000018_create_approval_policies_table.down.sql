@@ -0,0 +1,10 @@
+-- Rollback: Drop approval_policies table, approval requirements and the BoardChair role
+DROP TABLE IF EXISTS approval_policies CASCADE;
+ALTER TABLE voucher_approvals DROP COLUMN IF EXISTS approver_role;
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS required_roles,
+    DROP COLUMN IF EXISTS required_approvals;
+UPDATE users SET role = 'Treasurer' WHERE role = 'BoardChair';
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check
+    CHECK (role IN ('Admin', 'Treasurer', 'Clerk'));
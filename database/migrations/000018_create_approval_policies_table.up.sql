@@ -0,0 +1,39 @@
+-- Approval policies decide how many approvals, and from which roles, a voucher needs
+-- before it can be posted. They are evaluated when the voucher is submitted.
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check
+    CHECK (role IN ('Admin', 'Treasurer', 'Clerk', 'BoardChair'));
+
+CREATE TABLE approval_policies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    transaction_type VARCHAR(20) CHECK (transaction_type IN ('withdrawal', 'expenses', 'transfer')),
+    account UUID REFERENCES accounts(id) ON DELETE CASCADE,
+    amount_over NUMERIC(15,2) NOT NULL DEFAULT 0 CHECK (amount_over >= 0),
+    amount_up_to NUMERIC(15,2),
+    required_approvals INTEGER NOT NULL DEFAULT 1 CHECK (required_approvals >= 1),
+    required_roles TEXT[] NOT NULL DEFAULT '{}',
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (amount_up_to IS NULL OR amount_up_to > amount_over)
+);
+
+-- What the policies required when the voucher was submitted, so later edits do not
+-- change vouchers already awaiting approval
+ALTER TABLE transactions
+    ADD COLUMN required_approvals INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN required_roles TEXT[] NOT NULL DEFAULT '{}';
+
+ALTER TABLE voucher_approvals ADD COLUMN approver_role VARCHAR(50);
+
+CREATE INDEX idx_approval_policies_active ON approval_policies(is_active);
+
+-- Board rules: two signatories over 50,000 and the board chair over 200,000
+INSERT INTO approval_policies (name, description, amount_over, required_approvals, required_roles) VALUES
+    ('Two signatories over 50,000', 'Payments over KES 50,000 need two signatories', 50000, 2, '{}'),
+    ('Board chair over 200,000', 'Payments over KES 200,000 need board chair approval', 200000, 2, '{BoardChair}');
+
+COMMENT ON TABLE approval_policies IS 'Rules deciding the approvals a voucher needs by transaction type, account and amount';
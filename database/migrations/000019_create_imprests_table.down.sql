@@ -0,0 +1,4 @@
+-- Rollback: Drop petty_cash_vouchers, imprest_replenishments and imprests tables
+DROP TABLE IF EXISTS petty_cash_vouchers CASCADE;
+DROP TABLE IF EXISTS imprest_replenishments CASCADE;
+DROP TABLE IF EXISTS imprests CASCADE;
@@ -0,0 +1,58 @@
+-- Petty cash imprests: a cash account held at a fixed float, the vouchers paid out of it
+-- and the replenishments that top it back up from a Bank account
+CREATE TABLE imprests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    account UUID NOT NULL UNIQUE REFERENCES accounts(id),
+    float_amount NUMERIC(15,2) NOT NULL CHECK (float_amount > 0),
+    replenish_from UUID REFERENCES accounts(id),
+    custodian UUID REFERENCES users(id),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE imprest_replenishments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    imprest_id UUID NOT NULL REFERENCES imprests(id) ON DELETE CASCADE,
+    bank_account UUID NOT NULL REFERENCES accounts(id),
+    vouchers_total NUMERIC(15,2) NOT NULL DEFAULT 0,
+    amount NUMERIC(15,2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'posted', 'rejected')),
+    transfer_transaction UUID REFERENCES transactions(id) ON DELETE SET NULL,
+    notes TEXT,
+    requested_by UUID REFERENCES users(id),
+    reviewed_by UUID REFERENCES users(id),
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE petty_cash_vouchers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    imprest_id UUID NOT NULL REFERENCES imprests(id) ON DELETE CASCADE,
+    voucher_number VARCHAR(20) NOT NULL,
+    voucher_date TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expense_account UUID NOT NULL REFERENCES accounts(id),
+    particulars VARCHAR(255) NOT NULL,
+    paid_to VARCHAR(200),
+    amount NUMERIC(15,2) NOT NULL CHECK (amount > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'open'
+        CHECK (status IN ('open', 'claimed', 'replenished')),
+    replenishment_id UUID REFERENCES imprest_replenishments(id) ON DELETE SET NULL,
+    expense_transaction UUID REFERENCES transactions(id) ON DELETE SET NULL,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (imprest_id, voucher_number)
+);
+
+CREATE INDEX idx_petty_cash_vouchers_imprest ON petty_cash_vouchers(imprest_id, status);
+CREATE INDEX idx_petty_cash_vouchers_replenishment ON petty_cash_vouchers(replenishment_id);
+CREATE INDEX idx_imprest_replenishments_imprest ON imprest_replenishments(imprest_id, status);
+
+COMMENT ON TABLE imprests IS 'Petty cash accounts kept at a fixed float';
+COMMENT ON TABLE petty_cash_vouchers IS 'Small expenses paid out of a petty cash imprest';
+COMMENT ON TABLE imprest_replenishments IS 'Top-ups restoring an imprest to its float from a Bank account';
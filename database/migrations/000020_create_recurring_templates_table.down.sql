@@ -0,0 +1,4 @@
+-- Rollback: Drop recurring_runs, recurring_template_lines and recurring_templates tables
+DROP TABLE IF EXISTS recurring_runs CASCADE;
+DROP TABLE IF EXISTS recurring_template_lines CASCADE;
+DROP TABLE IF EXISTS recurring_templates CASCADE;
@@ -0,0 +1,55 @@
+-- Recurring transaction templates: standing entries such as rent, utilities and stipends
+-- that the scheduler turns into transactions on each due date, with one run row per
+-- occurrence so a date is never generated twice
+CREATE TABLE recurring_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    transaction_type VARCHAR(20) NOT NULL
+        CHECK (transaction_type IN ('receipts', 'withdrawal', 'expenses', 'transfer')),
+    debit_account UUID NOT NULL REFERENCES accounts(id),
+    member UUID REFERENCES members(id),
+    notes TEXT,
+    schedule VARCHAR(100) NOT NULL,
+    start_date TIMESTAMP NOT NULL,
+    end_date TIMESTAMP,
+    post_mode VARCHAR(10) NOT NULL DEFAULT 'draft'
+        CHECK (post_mode IN ('draft', 'posted')),
+    next_run_at TIMESTAMP,
+    last_run_at TIMESTAMP,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date IS NULL OR end_date >= start_date)
+);
+
+CREATE TABLE recurring_template_lines (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    template_id UUID NOT NULL REFERENCES recurring_templates(id) ON DELETE CASCADE,
+    account UUID NOT NULL REFERENCES accounts(id),
+    particulars VARCHAR(255) NOT NULL,
+    amount NUMERIC(15,2) NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE recurring_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    template_id UUID NOT NULL REFERENCES recurring_templates(id) ON DELETE CASCADE,
+    scheduled_for TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'created', 'failed')),
+    transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+    error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (template_id, scheduled_for)
+);
+
+CREATE INDEX idx_recurring_templates_due ON recurring_templates(is_active, next_run_at);
+CREATE INDEX idx_recurring_template_lines_template ON recurring_template_lines(template_id);
+CREATE INDEX idx_recurring_runs_template ON recurring_runs(template_id, scheduled_for DESC);
+
+COMMENT ON TABLE recurring_templates IS 'Standing transactions generated on a cron schedule';
+COMMENT ON TABLE recurring_template_lines IS 'Receipt, expenditure or transfer lines copied onto each generated transaction';
+COMMENT ON TABLE recurring_runs IS 'One row per scheduled occurrence of a recurring template';
@@ -0,0 +1,2 @@
+-- Rollback: Drop attachments table
+DROP TABLE IF EXISTS attachments CASCADE;
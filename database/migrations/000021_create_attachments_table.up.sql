@@ -0,0 +1,26 @@
+-- Attachments: scanned invoices, signed vouchers and other supporting documents linked to
+-- a transaction, an expenditure line or a transfer line. The file itself lives in the
+-- attachment store under storage_key.
+CREATE TABLE attachments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id UUID REFERENCES transactions(id) ON DELETE CASCADE,
+    expenditure_id UUID REFERENCES expenditure(id) ON DELETE CASCADE,
+    transfer_id UUID REFERENCES transfers(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL CHECK (size_bytes > 0),
+    checksum_sha256 CHAR(64) NOT NULL,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    description VARCHAR(255),
+    uploaded_by UUID REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (num_nonnulls(transaction_id, expenditure_id, transfer_id) = 1)
+);
+
+CREATE INDEX idx_attachments_transaction ON attachments(transaction_id);
+CREATE INDEX idx_attachments_expenditure ON attachments(expenditure_id);
+CREATE INDEX idx_attachments_transfer ON attachments(transfer_id);
+CREATE INDEX idx_attachments_checksum ON attachments(checksum_sha256);
+
+COMMENT ON TABLE attachments IS 'Supporting documents attached to transactions, expenditures and transfers';
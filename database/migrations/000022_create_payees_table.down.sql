@@ -0,0 +1,4 @@
+-- Rollback: Drop the expenditure payee column and the payees table
+DROP INDEX IF EXISTS idx_expenditure_payee;
+ALTER TABLE expenditure DROP COLUMN IF EXISTS payee;
+DROP TABLE IF EXISTS payees CASCADE;
@@ -0,0 +1,31 @@
+-- Payees: the contractors, suppliers and staff the church pays, with their KRA PIN and
+-- bank or M-Pesa details. Expenditure lines reference the payee they paid; particulars
+-- stay as the line description.
+CREATE TABLE payees (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(200) NOT NULL,
+    phone_number VARCHAR(20),
+    email VARCHAR(100),
+    kra_pin VARCHAR(11),
+    bank_name VARCHAR(100),
+    bank_branch VARCHAR(100),
+    bank_account_number VARCHAR(50),
+    mpesa_phone VARCHAR(20),
+    mpesa_paybill VARCHAR(20),
+    mpesa_account VARCHAR(50),
+    mpesa_till VARCHAR(20),
+    notes TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_payees_kra_pin ON payees(kra_pin) WHERE kra_pin IS NOT NULL;
+CREATE INDEX idx_payees_name ON payees(name);
+
+ALTER TABLE expenditure ADD COLUMN payee UUID REFERENCES payees(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_expenditure_payee ON expenditure(payee);
+
+COMMENT ON TABLE payees IS 'Contractors, suppliers and staff paid through expenditures';
@@ -0,0 +1,6 @@
+-- Rollback: Drop the cheques table and the transaction payment columns
+DROP TABLE IF EXISTS cheques CASCADE;
+DROP INDEX IF EXISTS idx_transactions_payment_reference;
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS payment_reference,
+    DROP COLUMN IF EXISTS payment_method;
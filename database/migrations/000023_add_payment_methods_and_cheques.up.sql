@@ -0,0 +1,37 @@
+-- How money moved on each transaction, and a register of the cheques the church issues
+-- so outstanding cheques can be reported per Bank account
+ALTER TABLE transactions
+    ADD COLUMN payment_method VARCHAR(20)
+        CHECK (payment_method IN ('cash', 'cheque', 'mpesa', 'bank_transfer', 'card')),
+    ADD COLUMN payment_reference VARCHAR(100);
+
+CREATE INDEX idx_transactions_payment_reference ON transactions(payment_method, payment_reference);
+
+CREATE TABLE cheques (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    cheque_number VARCHAR(20) NOT NULL,
+    bank_account UUID NOT NULL REFERENCES accounts(id),
+    transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+    payee UUID REFERENCES payees(id) ON DELETE SET NULL,
+    payee_name VARCHAR(200) NOT NULL,
+    amount NUMERIC(15,2) NOT NULL CHECK (amount > 0),
+    issue_date TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'issued'
+        CHECK (status IN ('issued', 'presented', 'cancelled', 'stale')),
+    presented_date TIMESTAMP,
+    cancelled_at TIMESTAMP,
+    cancel_reason TEXT,
+    notes TEXT,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (bank_account, cheque_number)
+);
+
+CREATE INDEX idx_cheques_bank_account ON cheques(bank_account, status);
+CREATE INDEX idx_cheques_transaction ON cheques(transaction_id);
+CREATE INDEX idx_cheques_payee ON cheques(payee);
+
+COMMENT ON COLUMN transactions.payment_method IS 'How the money moved: cash, cheque, mpesa, bank_transfer or card';
+COMMENT ON COLUMN transactions.payment_reference IS 'Cheque number, M-Pesa code, bank reference or card slip number';
+COMMENT ON TABLE cheques IS 'Register of cheques issued from Bank accounts';
@@ -0,0 +1,6 @@
+-- Rollback: Drop the collection session tables and the transaction link
+DROP INDEX IF EXISTS idx_transactions_collection_session;
+ALTER TABLE transactions DROP COLUMN IF EXISTS collection_session;
+DROP TABLE IF EXISTS collection_signoffs CASCADE;
+DROP TABLE IF EXISTS collection_denominations CASCADE;
+DROP TABLE IF EXISTS collection_sessions CASCADE;
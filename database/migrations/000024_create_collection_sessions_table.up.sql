@@ -0,0 +1,44 @@
+-- Offering collection sessions: the receipts collected in one service, the cash counted by
+-- denomination, and the sign-offs of the two people who counted it
+CREATE TABLE collection_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    service_name VARCHAR(100) NOT NULL,
+    service_date TIMESTAMP NOT NULL,
+    bank_account UUID NOT NULL REFERENCES accounts(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'open'
+        CHECK (status IN ('open', 'posted', 'cancelled')),
+    notes TEXT,
+    created_by UUID REFERENCES users(id),
+    posted_at TIMESTAMP,
+    cancelled_by UUID REFERENCES users(id),
+    cancelled_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE collection_denominations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES collection_sessions(id) ON DELETE CASCADE,
+    denomination NUMERIC(10,2) NOT NULL CHECK (denomination > 0),
+    quantity INTEGER NOT NULL CHECK (quantity >= 0),
+    UNIQUE (session_id, denomination)
+);
+
+CREATE TABLE collection_signoffs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES collection_sessions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    cash_total NUMERIC(15,2) NOT NULL,
+    receipts_total NUMERIC(15,2) NOT NULL,
+    signed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (session_id, user_id)
+);
+
+ALTER TABLE transactions ADD COLUMN collection_session UUID REFERENCES collection_sessions(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_collection_sessions_service_date ON collection_sessions(service_date);
+CREATE INDEX idx_transactions_collection_session ON transactions(collection_session);
+
+COMMENT ON TABLE collection_sessions IS 'Offering collected in one service, posted once two counters sign off';
+COMMENT ON TABLE collection_denominations IS 'Cash count of a collection session by note and coin';
+COMMENT ON TABLE collection_signoffs IS 'Totals confirmed by each person who counted a collection session';
@@ -0,0 +1,8 @@
+-- Rollback: Drop the fund columns on the line tables and the funds table
+DROP INDEX IF EXISTS idx_transfers_fund;
+DROP INDEX IF EXISTS idx_expenditure_fund;
+DROP INDEX IF EXISTS idx_receipts_fund;
+ALTER TABLE transfers DROP COLUMN IF EXISTS fund;
+ALTER TABLE expenditure DROP COLUMN IF EXISTS fund;
+ALTER TABLE receipts DROP COLUMN IF EXISTS fund;
+DROP TABLE IF EXISTS funds CASCADE;
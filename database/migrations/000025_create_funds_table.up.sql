@@ -0,0 +1,24 @@
+-- Funds: a dimension on receipt, expenditure and transfer lines so money given for a
+-- restricted purpose (building fund, mission offering) is tracked apart from general funds.
+-- A line without a fund belongs to the unrestricted general fund.
+CREATE TABLE funds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(20) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    is_restricted BOOLEAN NOT NULL DEFAULT TRUE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE receipts ADD COLUMN fund UUID REFERENCES funds(id);
+ALTER TABLE expenditure ADD COLUMN fund UUID REFERENCES funds(id);
+ALTER TABLE transfers ADD COLUMN fund UUID REFERENCES funds(id);
+
+CREATE INDEX idx_receipts_fund ON receipts(fund);
+CREATE INDEX idx_expenditure_fund ON expenditure(fund);
+CREATE INDEX idx_transfers_fund ON transfers(fund);
+
+COMMENT ON TABLE funds IS 'Funds that receipts, expenditures and transfers are tracked against';
+COMMENT ON COLUMN funds.is_restricted IS 'Restricted funds cannot be spent below zero';
@@ -0,0 +1,6 @@
+-- Rollback: Drop the account code and hierarchy columns
+DROP INDEX IF EXISTS idx_accounts_parent;
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_parent_not_self;
+ALTER TABLE accounts DROP COLUMN IF EXISTS is_header;
+ALTER TABLE accounts DROP COLUMN IF EXISTS parent;
+ALTER TABLE accounts DROP COLUMN IF EXISTS code;
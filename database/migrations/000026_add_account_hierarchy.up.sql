@@ -0,0 +1,12 @@
+-- Chart of accounts: account codes and a parent/child hierarchy. Header accounts group
+-- their children for reporting and cannot be posted to; totals roll up through parents.
+ALTER TABLE accounts ADD COLUMN code VARCHAR(20) UNIQUE;
+ALTER TABLE accounts ADD COLUMN parent UUID REFERENCES accounts(id);
+ALTER TABLE accounts ADD COLUMN is_header BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE accounts ADD CONSTRAINT accounts_parent_not_self CHECK (parent IS NULL OR parent <> id);
+
+CREATE INDEX idx_accounts_parent ON accounts(parent);
+
+COMMENT ON COLUMN accounts.code IS 'Chart of accounts code, e.g. 4000 or 4100';
+COMMENT ON COLUMN accounts.parent IS 'Header account this account rolls up into';
+COMMENT ON COLUMN accounts.is_header IS 'Header accounts group child accounts and cannot be posted to';
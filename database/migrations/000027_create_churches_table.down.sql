@@ -0,0 +1,38 @@
+-- Rollback: Drop the church policies and columns, then the churches tables
+DROP POLICY IF EXISTS users_delete ON users;
+DROP POLICY IF EXISTS users_update ON users;
+DROP POLICY IF EXISTS users_select ON users;
+DROP POLICY IF EXISTS users_insert ON users;
+ALTER TABLE users NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE users DISABLE ROW LEVEL SECURITY;
+
+DROP INDEX IF EXISTS idx_payees_kra_pin;
+ALTER TABLE funds DROP CONSTRAINT IF EXISTS funds_church_code_key;
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_church_code_key;
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'members_groups', 'members', 'accounts', 'transactions', 'expenditure', 'transfers', 'receipts',
+        'campaigns', 'pledges', 'pledge_payments', 'budgets', 'bank_statements', 'bank_statement_lines',
+        'mpesa_transactions', 'mobile_money_imports', 'mobile_money_lines', 'sms_notifications',
+        'email_outbox', 'email_attachments', 'voucher_approvals', 'approval_policies', 'imprests',
+        'imprest_replenishments', 'petty_cash_vouchers', 'recurring_templates', 'recurring_template_lines',
+        'recurring_runs', 'attachments', 'payees', 'cheques', 'collection_sessions',
+        'collection_denominations', 'collection_signoffs', 'funds'
+    ] LOOP
+        EXECUTE format('DROP POLICY IF EXISTS church_isolation ON %I', t);
+        EXECUTE format('ALTER TABLE %I NO FORCE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I DISABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I DROP COLUMN IF EXISTS church_id', t);
+    END LOOP;
+END $$;
+
+ALTER TABLE accounts ADD CONSTRAINT accounts_code_key UNIQUE (code);
+ALTER TABLE funds ADD CONSTRAINT funds_code_key UNIQUE (code);
+CREATE UNIQUE INDEX idx_payees_kra_pin ON payees(kra_pin) WHERE kra_pin IS NOT NULL;
+
+DROP TABLE IF EXISTS church_users CASCADE;
+DROP TABLE IF EXISTS churches CASCADE;
@@ -0,0 +1,107 @@
+-- Churches: one deployment serves several churches. Every church-owned table carries a
+-- church_id that defaults to the church of the connection (the app.church_id setting) and
+-- is protected by a row level security policy, so a connection only ever sees its church.
+CREATE TABLE churches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    code VARCHAR(20) NOT NULL UNIQUE,
+    mpesa_shortcode VARCHAR(20) UNIQUE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE church_users (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    church_id UUID NOT NULL REFERENCES churches(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) CHECK (role IN ('Admin', 'Treasurer', 'Clerk', 'BoardChair')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (church_id, user_id)
+);
+
+CREATE INDEX idx_church_users_user ON church_users(user_id);
+
+-- Data recorded before churches existed belongs to a first church
+INSERT INTO churches (name, code)
+SELECT 'Main Church', 'MAIN'
+WHERE EXISTS (SELECT 1 FROM users) OR EXISTS (SELECT 1 FROM accounts) OR EXISTS (SELECT 1 FROM members);
+
+INSERT INTO church_users (church_id, user_id)
+SELECT c.id, u.id FROM churches c CROSS JOIN users u WHERE c.code = 'MAIN';
+
+DO $$
+DECLARE
+    t TEXT;
+    main_church UUID;
+BEGIN
+    SELECT id INTO main_church FROM churches WHERE code = 'MAIN';
+
+    FOREACH t IN ARRAY ARRAY[
+        'members_groups', 'members', 'accounts', 'transactions', 'expenditure', 'transfers', 'receipts',
+        'campaigns', 'pledges', 'pledge_payments', 'budgets', 'bank_statements', 'bank_statement_lines',
+        'mpesa_transactions', 'mobile_money_imports', 'mobile_money_lines', 'sms_notifications',
+        'email_outbox', 'email_attachments', 'voucher_approvals', 'approval_policies', 'imprests',
+        'imprest_replenishments', 'petty_cash_vouchers', 'recurring_templates', 'recurring_template_lines',
+        'recurring_runs', 'attachments', 'payees', 'cheques', 'collection_sessions',
+        'collection_denominations', 'collection_signoffs', 'funds'
+    ] LOOP
+        EXECUTE format('ALTER TABLE %I ADD COLUMN church_id UUID REFERENCES churches(id)', t);
+        IF main_church IS NOT NULL THEN
+            EXECUTE format('UPDATE %I SET church_id = %L', t, main_church);
+        END IF;
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN church_id SET DEFAULT NULLIF(current_setting(''app.church_id'', true), '''')::uuid', t);
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN church_id SET NOT NULL', t);
+        EXECUTE format('CREATE INDEX idx_%s_church ON %I(church_id)', t, t);
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', t);
+        EXECUTE format('CREATE POLICY church_isolation ON %I USING (church_id::text = current_setting(''app.church_id'', true)) WITH CHECK (church_id::text = current_setting(''app.church_id'', true))', t);
+    END LOOP;
+END $$;
+
+-- Codes only need to be unique within a church
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_code_key;
+ALTER TABLE accounts ADD CONSTRAINT accounts_church_code_key UNIQUE (church_id, code);
+ALTER TABLE funds DROP CONSTRAINT IF EXISTS funds_code_key;
+ALTER TABLE funds ADD CONSTRAINT funds_church_code_key UNIQUE (church_id, code);
+DROP INDEX IF EXISTS idx_payees_kra_pin;
+CREATE UNIQUE INDEX idx_payees_kra_pin ON payees(church_id, kra_pin) WHERE kra_pin IS NOT NULL;
+
+-- Users are shared between churches; a church connection only sees its own users, while
+-- connections without a church (sign-in, setup) see them all
+ALTER TABLE users ENABLE ROW LEVEL SECURITY;
+ALTER TABLE users FORCE ROW LEVEL SECURITY;
+CREATE POLICY users_insert ON users FOR INSERT WITH CHECK (true);
+CREATE POLICY users_select ON users FOR SELECT USING (
+    COALESCE(current_setting('app.church_id', true), '') = ''
+    OR id IN (SELECT user_id FROM church_users WHERE church_id::text = current_setting('app.church_id', true))
+);
+CREATE POLICY users_update ON users FOR UPDATE USING (
+    COALESCE(current_setting('app.church_id', true), '') = ''
+    OR id IN (SELECT user_id FROM church_users WHERE church_id::text = current_setting('app.church_id', true))
+);
+CREATE POLICY users_delete ON users FOR DELETE USING (
+    COALESCE(current_setting('app.church_id', true), '') = ''
+    OR id IN (SELECT user_id FROM church_users WHERE church_id::text = current_setting('app.church_id', true))
+);
+
+-- Superusers and table owners with BYPASSRLS ignore the policies above. Church connections
+-- switch to this role so the policies still apply when the application connects as one.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'storehouse_tenant') THEN
+        CREATE ROLE storehouse_tenant NOLOGIN;
+    END IF;
+    EXECUTE format('GRANT storehouse_tenant TO %I', current_user);
+    GRANT USAGE ON SCHEMA public TO storehouse_tenant;
+    GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO storehouse_tenant;
+    GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA public TO storehouse_tenant;
+    ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO storehouse_tenant;
+    ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT USAGE, SELECT ON SEQUENCES TO storehouse_tenant;
+EXCEPTION WHEN insufficient_privilege THEN
+    RAISE NOTICE 'storehouse_tenant role not created: %', SQLERRM;
+END $$;
+
+COMMENT ON TABLE churches IS 'Churches served by this deployment';
+COMMENT ON TABLE church_users IS 'Users who can work in each church, with an optional role for that church';
+COMMENT ON COLUMN churches.mpesa_shortcode IS 'Paybill short code whose C2B callbacks belong to this church';
@@ -0,0 +1,5 @@
+-- Rollback: Detach churches from districts and drop the districts tables
+DROP INDEX IF EXISTS idx_churches_district;
+ALTER TABLE churches DROP COLUMN IF EXISTS district_id;
+DROP TABLE IF EXISTS district_users;
+DROP TABLE IF EXISTS districts;
@@ -0,0 +1,28 @@
+-- Districts: a district oversees a group of churches. Its users read consolidated reports
+-- across the district's churches without working in them.
+CREATE TABLE districts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    code VARCHAR(20) NOT NULL UNIQUE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE district_users (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    district_id UUID NOT NULL REFERENCES districts(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('DistrictAdmin', 'DistrictTreasurer')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (district_id, user_id)
+);
+
+CREATE INDEX idx_district_users_user ON district_users(user_id);
+
+ALTER TABLE churches ADD COLUMN district_id UUID REFERENCES districts(id) ON DELETE SET NULL;
+CREATE INDEX idx_churches_district ON churches(district_id);
+
+COMMENT ON TABLE districts IS 'Districts overseeing groups of churches';
+COMMENT ON TABLE district_users IS 'Users with a district role: DistrictAdmin manages the district, DistrictTreasurer reads its reports';
+COMMENT ON COLUMN churches.district_id IS 'District the church reports to';
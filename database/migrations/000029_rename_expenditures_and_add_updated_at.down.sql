@@ -0,0 +1,6 @@
+-- Rollback: Drop the updated_at columns and restore the expenditure table name
+ALTER TABLE receipts DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE transfers DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE expenditures DROP COLUMN IF EXISTS updated_at;
+
+ALTER TABLE expenditures RENAME TO expenditure;
@@ -0,0 +1,7 @@
+-- Bring the schema in line with the models: expenditure lines live in expenditures like
+-- every other plural table, and the line tables record when they were last changed
+ALTER TABLE expenditure RENAME TO expenditures;
+
+ALTER TABLE expenditures ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE transfers ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE receipts ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
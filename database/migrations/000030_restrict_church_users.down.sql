@@ -0,0 +1,26 @@
+-- Rollback: Restore the church-less users policies and the optional church role
+DROP POLICY IF EXISTS users_delete ON users;
+DROP POLICY IF EXISTS users_update ON users;
+DROP POLICY IF EXISTS users_select ON users;
+CREATE POLICY users_select ON users FOR SELECT USING (
+    COALESCE(current_setting('app.church_id', true), '') = ''
+    OR id IN (SELECT user_id FROM church_users WHERE church_id::text = current_setting('app.church_id', true))
+);
+CREATE POLICY users_update ON users FOR UPDATE USING (
+    COALESCE(current_setting('app.church_id', true), '') = ''
+    OR id IN (SELECT user_id FROM church_users WHERE church_id::text = current_setting('app.church_id', true))
+);
+CREATE POLICY users_delete ON users FOR DELETE USING (
+    COALESCE(current_setting('app.church_id', true), '') = ''
+    OR id IN (SELECT user_id FROM church_users WHERE church_id::text = current_setting('app.church_id', true))
+);
+
+DROP POLICY IF EXISTS church_isolation ON church_users;
+ALTER TABLE church_users NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE church_users DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS church_isolation ON churches;
+ALTER TABLE churches NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE churches DISABLE ROW LEVEL SECURITY;
+
+ALTER TABLE users DROP COLUMN IF EXISTS created_in;
+ALTER TABLE church_users ALTER COLUMN role DROP NOT NULL;
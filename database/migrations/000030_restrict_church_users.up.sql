@@ -0,0 +1,46 @@
+-- Every church user works under a role of their own in that church, rather than falling
+-- back to the role on their user
+UPDATE church_users cu SET role = u.role FROM users u WHERE u.id = cu.user_id AND cu.role IS NULL;
+ALTER TABLE church_users ALTER COLUMN role SET NOT NULL;
+
+-- The church a user was created through; its admins may give them access again later
+ALTER TABLE users ADD COLUMN created_in UUID REFERENCES churches(id) ON DELETE SET NULL
+    DEFAULT NULLIF(current_setting('app.church_id', true), '')::uuid;
+
+-- Church connections (the tenant role) only see their own church and its users, even when
+-- the church setting is missing. Only the shared connection (sign-in, setup, churches)
+-- sees every church.
+ALTER TABLE churches ENABLE ROW LEVEL SECURITY;
+ALTER TABLE churches FORCE ROW LEVEL SECURITY;
+CREATE POLICY church_isolation ON churches
+    USING ((current_user <> 'storehouse_tenant' AND COALESCE(current_setting('app.church_id', true), '') = '')
+        OR id::text = current_setting('app.church_id', true))
+    WITH CHECK ((current_user <> 'storehouse_tenant' AND COALESCE(current_setting('app.church_id', true), '') = '')
+        OR id::text = current_setting('app.church_id', true));
+
+ALTER TABLE church_users ENABLE ROW LEVEL SECURITY;
+ALTER TABLE church_users FORCE ROW LEVEL SECURITY;
+CREATE POLICY church_isolation ON church_users
+    USING ((current_user <> 'storehouse_tenant' AND COALESCE(current_setting('app.church_id', true), '') = '')
+        OR church_id::text = current_setting('app.church_id', true))
+    WITH CHECK ((current_user <> 'storehouse_tenant' AND COALESCE(current_setting('app.church_id', true), '') = '')
+        OR church_id::text = current_setting('app.church_id', true));
+
+DROP POLICY IF EXISTS users_select ON users;
+DROP POLICY IF EXISTS users_update ON users;
+DROP POLICY IF EXISTS users_delete ON users;
+CREATE POLICY users_select ON users FOR SELECT USING (
+    (current_user <> 'storehouse_tenant' AND COALESCE(current_setting('app.church_id', true), '') = '')
+    OR id IN (SELECT user_id FROM church_users WHERE church_id::text = current_setting('app.church_id', true))
+);
+CREATE POLICY users_update ON users FOR UPDATE USING (
+    (current_user <> 'storehouse_tenant' AND COALESCE(current_setting('app.church_id', true), '') = '')
+    OR id IN (SELECT user_id FROM church_users WHERE church_id::text = current_setting('app.church_id', true))
+);
+CREATE POLICY users_delete ON users FOR DELETE USING (
+    (current_user <> 'storehouse_tenant' AND COALESCE(current_setting('app.church_id', true), '') = '')
+    OR id IN (SELECT user_id FROM church_users WHERE church_id::text = current_setting('app.church_id', true))
+);
+
+COMMENT ON COLUMN church_users.role IS 'Role the user works under in this church';
+COMMENT ON COLUMN users.created_in IS 'Church the user was created through, if any';
@@ -0,0 +1,198 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// TenantRole is the role church connections switch to when the application's own database
+// user would bypass row level security
+const TenantRole = "storehouse_tenant"
+
+// Tenants hands out one connection pool per church. Every connection in a church's pool
+// carries the app.church_id setting, so the row level security policies only let it see
+// and write that church's rows.
+type Tenants struct {
+	DB *sqlx.DB
+
+	dsn      string
+	mu       sync.Mutex
+	pools    map[string]*sqlx.DB
+	starters []func(churchID string, db *sqlx.DB)
+
+	checkOnce  sync.Once
+	switchRole bool
+	checkErr   error
+}
+
+// NewTenants creates the church pools on top of the shared connection
+func NewTenants(db *sqlx.DB) *Tenants {
+	return &Tenants{
+		DB:    db,
+		dsn:   os.Getenv("DATABASE_URL"),
+		pools: make(map[string]*sqlx.DB),
+	}
+}
+
+// CheckIsolation makes sure the row level security policies apply to church connections:
+// either the database user is subject to them, or it can switch to the tenant role
+func (t *Tenants) CheckIsolation() error {
+	t.checkOnce.Do(func() {
+		var bypass bool
+		err := t.DB.Get(&bypass, "SELECT rolsuper OR rolbypassrls FROM pg_roles WHERE rolname = current_user")
+		if err != nil {
+			t.checkErr = err
+			return
+		}
+		if !bypass {
+			return
+		}
+
+		var member bool
+		query := `SELECT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = $1) AND pg_has_role(current_user, $1, 'MEMBER')`
+		if err := t.DB.Get(&member, query, TenantRole); err != nil {
+			t.checkErr = err
+			return
+		}
+		if !member {
+			t.checkErr = errors.New("the database user bypasses row level security and cannot switch to the " + TenantRole + " role")
+			return
+		}
+		t.switchRole = true
+	})
+
+	return t.checkErr
+}
+
+// ForChurch returns the connection pool scoped to a church, opening it on first use
+func (t *Tenants) ForChurch(churchID string) (*sqlx.DB, error) {
+	if _, err := uuid.Parse(churchID); err != nil {
+		return nil, errors.New("invalid church id")
+	}
+	if err := t.CheckIsolation(); err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if db, ok := t.pools[churchID]; ok {
+		return db, nil
+	}
+
+	dsn, err := t.churchDSN(churchID)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sqlx.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	// Church pools stay small; a deployment holds one per church
+	db.SetMaxOpenConns(5)
+	db.SetMaxIdleConns(2)
+	db.SetConnMaxLifetime(5 * time.Minute)
+
+	t.pools[churchID] = db
+	return db, nil
+}
+
+// ChurchIDs returns the active churches
+func (t *Tenants) ChurchIDs() ([]string, error) {
+	var ids []string
+	err := t.DB.Select(&ids, "SELECT id FROM churches WHERE is_active = true ORDER BY created_at")
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// OnChurch runs start for every active church now, and for every church activated later;
+// it is how per-church background jobs are started
+func (t *Tenants) OnChurch(start func(churchID string, db *sqlx.DB)) {
+	t.mu.Lock()
+	t.starters = append(t.starters, start)
+	t.mu.Unlock()
+
+	ids, err := t.ChurchIDs()
+	if err != nil {
+		log.Printf("⚠️  Could not list churches: %v", err)
+		return
+	}
+	for _, id := range ids {
+		db, err := t.ForChurch(id)
+		if err != nil {
+			log.Printf("⚠️  Church %s: %v", id, err)
+			continue
+		}
+		start(id, db)
+	}
+}
+
+// Activate starts the background jobs of a newly created church
+func (t *Tenants) Activate(churchID string) error {
+	db, err := t.ForChurch(churchID)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	starters := append([]func(string, *sqlx.DB){}, t.starters...)
+	t.mu.Unlock()
+
+	for _, start := range starters {
+		start(churchID, db)
+	}
+	return nil
+}
+
+// Close closes every church pool
+func (t *Tenants) Close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for id, db := range t.pools {
+		db.Close()
+		delete(t.pools, id)
+	}
+}
+
+// churchDSN adds the church setting, and the tenant role when needed, to the startup
+// options of the shared connection string
+func (t *Tenants) churchDSN(churchID string) (string, error) {
+	if t.dsn == "" {
+		return "", errors.New("DATABASE_URL environment variable not set")
+	}
+
+	options := fmt.Sprintf("-c app.church_id=%s", churchID)
+	if t.switchRole {
+		options += " -c role=" + TenantRole
+	}
+
+	if strings.HasPrefix(t.dsn, "postgres://") || strings.HasPrefix(t.dsn, "postgresql://") {
+		u, err := url.Parse(t.dsn)
+		if err != nil {
+			return "", err
+		}
+		q := u.Query()
+		if existing := q.Get("options"); existing != "" {
+			options = existing + " " + options
+		}
+		q.Set("options", options)
+		u.RawQuery = q.Encode()
+		return u.String(), nil
+	}
+
+	return t.dsn + " options='" + options + "'", nil
+}
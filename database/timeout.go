@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+)
+
+// Timeouts bounds how long a request may run and how long each repository call it makes
+// may take
+type Timeouts struct {
+	Request time.Duration
+	Query   time.Duration
+}
+
+type queryTimeoutKey struct{}
+
+// LoadTimeouts reads the default timeouts from REQUEST_TIMEOUT and QUERY_TIMEOUT, e.g. "30s"
+func LoadTimeouts() Timeouts {
+	timeouts := Timeouts{Request: 30 * time.Second, Query: 10 * time.Second}
+	if d, err := time.ParseDuration(os.Getenv("REQUEST_TIMEOUT")); err == nil && d > 0 {
+		timeouts.Request = d
+	}
+	if d, err := time.ParseDuration(os.Getenv("QUERY_TIMEOUT")); err == nil && d > 0 {
+		timeouts.Query = d
+	}
+	return timeouts
+}
+
+var defaultTimeouts = sync.OnceValue(LoadTimeouts)
+
+// DefaultTimeouts returns the timeouts used where a route does not set its own. They are
+// read from the environment once, on first use.
+func DefaultTimeouts() Timeouts {
+	return defaultTimeouts()
+}
+
+// WithQueryTimeout returns a copy of ctx whose repository calls may each take at most d
+func WithQueryTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, queryTimeoutKey{}, d)
+}
+
+// QueryContext bounds one repository call by the query timeout set on ctx, or the default
+// one. The call is also cancelled with ctx, e.g. when the client goes away.
+func QueryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	d, ok := ctx.Value(queryTimeoutKey{}).(time.Duration)
+	if !ok || d <= 0 {
+		d = DefaultTimeouts().Query
+	}
+	return context.WithTimeout(ctx, d)
+}
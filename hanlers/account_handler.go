@@ -3,7 +3,9 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	appmw "storeHouse/middleware"
 	"storeHouse/models"
+	"storeHouse/repository"
 	"storeHouse/services"
 
 	"github.com/go-chi/chi/v5"
@@ -16,34 +18,28 @@ type AccountHandler struct {
 
 func NewAccountHandler(db *sqlx.DB) *AccountHandler {
 	return &AccountHandler{
-		accountService: services.NewAccountService(db),
+		accountService: services.NewAccountService(repository.NewPostgres(db)),
 	}
 }
 
 // CreateAccount handles account creation
 func (h *AccountHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateAccountRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if !appmw.DecodeJSON(w, r, &req) {
 		return
 	}
 
 	// Validate request
 	if err := req.Validate(); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
-	// For now, using a default user ID - in real app, get from authentication
-	createdBy := "system"
+	createdBy := appmw.GetUserFromContext(r).ID
 
-	account, err := h.accountService.CreateAccount(req, createdBy)
+	account, err := h.accountService.CreateAccount(r.Context(), req, createdBy)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -56,11 +52,9 @@ func (h *AccountHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 func (h *AccountHandler) GetAccount(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	account, err := h.accountService.GetAccount(id)
+	account, err := h.accountService.GetAccount(r.Context(), id)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -70,11 +64,9 @@ func (h *AccountHandler) GetAccount(w http.ResponseWriter, r *http.Request) {
 
 // GetAllAccounts handles getting all accounts
 func (h *AccountHandler) GetAllAccounts(w http.ResponseWriter, r *http.Request) {
-	accounts, err := h.accountService.GetAllAccounts()
+	accounts, err := h.accountService.GetAllAccounts(r.Context())
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -82,25 +74,32 @@ func (h *AccountHandler) GetAllAccounts(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(accounts)
 }
 
+// GetAccountTree handles getting the chart of accounts as a tree
+func (h *AccountHandler) GetAccountTree(w http.ResponseWriter, r *http.Request) {
+	includeInactive := r.URL.Query().Get("include_inactive") == "true"
+
+	tree, err := h.accountService.GetAccountTree(r.Context(), includeInactive)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tree)
+}
+
 // UpdateAccount handles updating account details
 func (h *AccountHandler) UpdateAccount(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	var req models.UpdateAccountRequest
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if !appmw.DecodeJSON(w, r, &req) {
 		return
 	}
 
-	account, err := h.accountService.UpdateAccount(id, req)
+	account, err := h.accountService.UpdateAccount(r.Context(), id, req)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		if err.Error() == "account not found" {
-			w.WriteHeader(http.StatusNotFound)
-		} else {
-			w.WriteHeader(http.StatusBadRequest)
-		}
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -112,11 +111,9 @@ func (h *AccountHandler) UpdateAccount(w http.ResponseWriter, r *http.Request) {
 func (h *AccountHandler) DeactivateAccount(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	err := h.accountService.DeactivateAccount(id)
+	err := h.accountService.DeactivateAccount(r.Context(), id)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
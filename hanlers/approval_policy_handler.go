@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	appmw "storeHouse/middleware"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"storeHouse/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+type ApprovalPolicyHandler struct {
+	approvalPolicyService *services.ApprovalPolicyService
+}
+
+func NewApprovalPolicyHandler(db *sqlx.DB) *ApprovalPolicyHandler {
+	return &ApprovalPolicyHandler{
+		approvalPolicyService: services.NewApprovalPolicyService(repository.NewPostgres(db)),
+	}
+}
+
+// CreatePolicy handles approval policy creation
+func (h *ApprovalPolicyHandler) CreatePolicy(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateApprovalPolicyRequest
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	policy, err := h.approvalPolicyService.CreatePolicy(r.Context(), req, appmw.GetUserFromContext(r).ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(policy)
+}
+
+// GetPolicy handles getting an approval policy by ID
+func (h *ApprovalPolicyHandler) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	policy, err := h.approvalPolicyService.GetPolicy(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(policy)
+}
+
+// GetAllPolicies handles listing approval policies
+func (h *ApprovalPolicyHandler) GetAllPolicies(w http.ResponseWriter, r *http.Request) {
+	policies, err := h.approvalPolicyService.GetAllPolicies(r.Context())
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(policies)
+}
+
+// UpdatePolicy handles updating an approval policy
+func (h *ApprovalPolicyHandler) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req models.UpdateApprovalPolicyRequest
+
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	policy, err := h.approvalPolicyService.UpdatePolicy(r.Context(), id, req)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(policy)
+}
+
+// DeletePolicy handles deleting an approval policy
+func (h *ApprovalPolicyHandler) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	err := h.approvalPolicyService.DeletePolicy(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.SuccessResponse{Message: "Approval policy deleted successfully"})
+}
+
+// EvaluatePolicies handles previewing the approvals a voucher would need
+func (h *ApprovalPolicyHandler) EvaluatePolicies(w http.ResponseWriter, r *http.Request) {
+	var req models.EvaluateApprovalPolicyRequest
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	requirement, err := h.approvalPolicyService.EvaluateRequest(r.Context(), req)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(requirement)
+}
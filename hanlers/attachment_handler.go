@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	appmw "storeHouse/middleware"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"storeHouse/services"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+// maxAttachmentMemory caps the memory used to parse an upload; larger parts spill to disk
+const maxAttachmentMemory = 10 << 20
+
+type AttachmentHandler struct {
+	attachmentService *services.AttachmentService
+}
+
+func NewAttachmentHandler(db *sqlx.DB) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: services.NewAttachmentService(repository.NewPostgres(db)),
+	}
+}
+
+// UploadFor handles uploading a file to a transaction, expenditure or transfer as a
+// multipart form with the fields file and optionally description
+func (h *AttachmentHandler) UploadFor(entity models.AttachmentEntity) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Leave room for the form fields around the file itself
+		r.Body = http.MaxBytesReader(w, r.Body, h.attachmentService.MaxSize()+1<<20)
+		if err := r.ParseMultipartForm(maxAttachmentMemory); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				appmw.WriteError(w, models.NewError(http.StatusRequestEntityTooLarge, models.CodeTooLarge, "file is larger than the upload limit"))
+				return
+			}
+			appmw.WriteError(w, models.Invalid("Invalid multipart form"))
+			return
+		}
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			appmw.WriteError(w, models.Invalid("File is required"))
+			return
+		}
+		defer file.Close()
+
+		req := models.UploadAttachmentRequest{
+			EntityType: string(entity),
+			EntityID:   chi.URLParam(r, "id"),
+			FileName:   header.Filename,
+		}
+		if description := strings.TrimSpace(r.FormValue("description")); description != "" {
+			req.Description = &description
+		}
+
+		var uploadedBy *string
+		if user := appmw.GetUserFromContext(r); user != nil {
+			uploadedBy = &user.ID
+		}
+
+		attachment, err := h.attachmentService.UploadAttachment(r.Context(), req, file, uploadedBy)
+		if err != nil {
+			appmw.WriteError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(attachment)
+	}
+}
+
+// ListFor handles listing the files attached to a transaction, expenditure or transfer
+func (h *AttachmentHandler) ListFor(entity models.AttachmentEntity) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		attachments, err := h.attachmentService.GetAttachments(r.Context(), string(entity), chi.URLParam(r, "id"))
+		if err != nil {
+			appmw.WriteError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(attachments)
+	}
+}
+
+// GetAttachment handles getting an attachment's details by ID
+func (h *AttachmentHandler) GetAttachment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	attachment, err := h.attachmentService.GetAttachment(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(attachment)
+}
+
+// DownloadAttachment handles streaming an attachment's file
+func (h *AttachmentHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	attachment, content, err := h.attachmentService.OpenAttachment(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+	defer content.Close()
+
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName})
+	if disposition == "" {
+		disposition = "attachment"
+	}
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Disposition", disposition)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.SizeBytes, 10))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("X-Checksum-SHA256", attachment.ChecksumSHA256)
+	w.Header().Set("ETag", `"`+attachment.ChecksumSHA256+`"`)
+	if _, err := io.Copy(w, content); err != nil {
+		log.Printf("⚠️  Failed to send attachment %s: %v", attachment.ID, err)
+	}
+}
+
+// DeleteAttachment handles deleting an attachment
+func (h *AttachmentHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := h.attachmentService.DeleteAttachment(r.Context(), id); err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.SuccessResponse{Message: "Attachment deleted successfully"})
+}
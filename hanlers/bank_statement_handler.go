@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	appmw "storeHouse/middleware"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"storeHouse/services"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+// maxStatementUploadSize caps the memory used to parse a statement upload
+const maxStatementUploadSize = 10 << 20
+
+type BankStatementHandler struct {
+	bankStatementService *services.BankStatementService
+}
+
+func NewBankStatementHandler(db *sqlx.DB) *BankStatementHandler {
+	return &BankStatementHandler{
+		bankStatementService: services.NewBankStatementService(repository.NewPostgres(db)),
+	}
+}
+
+// ImportStatement handles uploading a CSV or OFX bank statement as a multipart form with
+// the fields file, bank_account_id and optionally format, opening_balance, closing_balance
+// and window_days
+func (h *BankStatementHandler) ImportStatement(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(maxStatementUploadSize); err != nil {
+		appmw.WriteError(w, models.Invalid("Invalid multipart form"))
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		appmw.WriteError(w, models.Invalid("Statement file is required"))
+		return
+	}
+	defer file.Close()
+
+	req := models.ImportBankStatementRequest{
+		BankAccountID: r.FormValue("bank_account_id"),
+		Format:        r.FormValue("format"),
+		FileName:      header.Filename,
+	}
+
+	// Parse optional numeric fields
+	for name, target := range map[string]**float64{
+		"opening_balance": &req.OpeningBalance,
+		"closing_balance": &req.ClosingBalance,
+	} {
+		if value := strings.TrimSpace(r.FormValue(name)); value != "" {
+			amount, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				appmw.WriteError(w, models.Invalid("invalid "+name))
+				return
+			}
+			*target = &amount
+		}
+	}
+	if value := r.FormValue("window_days"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil {
+			appmw.WriteError(w, models.Invalid("invalid window_days"))
+			return
+		}
+		req.WindowDays = days
+	}
+
+	createdBy := appmw.GetUserFromContext(r).ID
+
+	statement, err := h.bankStatementService.ImportStatement(r.Context(), req, file, createdBy)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(statement)
+}
+
+// GetStatement handles getting a statement with its lines
+func (h *BankStatementHandler) GetStatement(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	statement, err := h.bankStatementService.GetStatement(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statement)
+}
+
+// GetStatementsByAccount handles getting statements imported for a bank account
+func (h *BankStatementHandler) GetStatementsByAccount(w http.ResponseWriter, r *http.Request) {
+	accountID := chi.URLParam(r, "accountID")
+
+	statements, err := h.bankStatementService.GetStatementsByAccount(r.Context(), accountID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statements)
+}
+
+// DeleteStatement handles deleting a statement and its lines
+func (h *BankStatementHandler) DeleteStatement(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	err := h.bankStatementService.DeleteStatement(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.SuccessResponse{Message: "Bank statement deleted successfully"})
+}
+
+// AutoMatch handles re-running auto-matching over a statement's unmatched lines
+func (h *BankStatementHandler) AutoMatch(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	windowDays := 0
+	if value := r.URL.Query().Get("window_days"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil {
+			appmw.WriteError(w, models.Invalid("invalid window_days"))
+			return
+		}
+		windowDays = days
+	}
+
+	result, err := h.bankStatementService.AutoMatch(r.Context(), id, windowDays)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// MatchLine handles manually matching a statement line to a transaction
+func (h *BankStatementHandler) MatchLine(w http.ResponseWriter, r *http.Request) {
+	lineID := chi.URLParam(r, "lineID")
+	var req models.MatchStatementLineRequest
+
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	// Validate request
+	if err := req.Validate(); err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	line, err := h.bankStatementService.MatchLine(r.Context(), lineID, req)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(line)
+}
+
+// UnmatchLine handles clearing the match on a statement line
+func (h *BankStatementHandler) UnmatchLine(w http.ResponseWriter, r *http.Request) {
+	lineID := chi.URLParam(r, "lineID")
+
+	line, err := h.bankStatementService.UnmatchLine(r.Context(), lineID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(line)
+}
+
+// GetReconciliation handles the bank reconciliation report for an account
+func (h *BankStatementHandler) GetReconciliation(w http.ResponseWriter, r *http.Request) {
+	accountID := chi.URLParam(r, "accountID")
+
+	asOf, ok := parseAsOf(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.bankStatementService.GetReconciliation(r.Context(), accountID, asOf)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
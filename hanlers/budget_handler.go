@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	appmw "storeHouse/middleware"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"storeHouse/services"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+type BudgetHandler struct {
+	budgetService *services.BudgetService
+}
+
+func NewBudgetHandler(db *sqlx.DB) *BudgetHandler {
+	return &BudgetHandler{
+		budgetService: services.NewBudgetService(repository.NewPostgres(db)),
+	}
+}
+
+// SetBudget handles setting an account's budget for a year
+func (h *BudgetHandler) SetBudget(w http.ResponseWriter, r *http.Request) {
+	var req models.SetBudgetRequest
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	// Validate request
+	if err := req.Validate(); err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	createdBy := appmw.GetUserFromContext(r).ID
+
+	budget, err := h.budgetService.SetBudget(r.Context(), req, createdBy)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(budget)
+}
+
+// ImportBudgets handles importing budgets from a CSV file, sent either as the
+// "file" field of a multipart form or as the raw request body
+func (h *BudgetHandler) ImportBudgets(w http.ResponseWriter, r *http.Request) {
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			appmw.WriteError(w, models.Invalid("CSV file is required"))
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	createdBy := appmw.GetUserFromContext(r).ID
+
+	result, err := h.budgetService.ImportBudgetsCSV(r.Context(), body, createdBy)
+	if err != nil {
+		if result != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(result)
+			return
+		}
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
+
+// GetBudgetsByYear handles getting all account budgets for a year
+func (h *BudgetHandler) GetBudgetsByYear(w http.ResponseWriter, r *http.Request) {
+	year, ok := parseBudgetYear(w, r)
+	if !ok {
+		return
+	}
+
+	budgets, err := h.budgetService.GetBudgetsByYear(r.Context(), year)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(budgets)
+}
+
+// GetAccountBudget handles getting an account's budget for a year
+func (h *BudgetHandler) GetAccountBudget(w http.ResponseWriter, r *http.Request) {
+	accountID := chi.URLParam(r, "accountID")
+
+	year, ok := parseBudgetYear(w, r)
+	if !ok {
+		return
+	}
+
+	budget, err := h.budgetService.GetAccountBudget(r.Context(), accountID, year)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(budget)
+}
+
+// UpdateBudget handles updating a single monthly budget line
+func (h *BudgetHandler) UpdateBudget(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req models.UpdateBudgetRequest
+
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	budget, err := h.budgetService.UpdateBudget(r.Context(), id, req)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(budget)
+}
+
+// DeleteAccountBudget handles deleting an account's budget for a year
+func (h *BudgetHandler) DeleteAccountBudget(w http.ResponseWriter, r *http.Request) {
+	accountID := chi.URLParam(r, "accountID")
+
+	year, ok := parseBudgetYear(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.budgetService.DeleteAccountBudget(r.Context(), accountID, year)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.SuccessResponse{Message: "Budget deleted successfully"})
+}
+
+// GetVarianceReport handles the budget-vs-actual variance report
+func (h *BudgetHandler) GetVarianceReport(w http.ResponseWriter, r *http.Request) {
+	year, ok := parseBudgetYear(w, r)
+	if !ok {
+		return
+	}
+
+	month := 0
+	if monthStr := r.URL.Query().Get("month"); monthStr != "" {
+		m, err := strconv.Atoi(monthStr)
+		if err != nil {
+			appmw.WriteError(w, models.Invalid("invalid month"))
+			return
+		}
+		month = m
+	}
+
+	report, err := h.budgetService.GetVarianceReport(r.Context(), year, month)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// parseBudgetYear reads the optional year query parameter, defaulting to the current year
+func parseBudgetYear(w http.ResponseWriter, r *http.Request) (int, bool) {
+	yearStr := r.URL.Query().Get("year")
+	if yearStr == "" {
+		return time.Now().Year(), true
+	}
+
+	year, err := strconv.Atoi(yearStr)
+	if err != nil {
+		appmw.WriteError(w, models.Invalid("invalid year"))
+		return 0, false
+	}
+
+	return year, true
+}
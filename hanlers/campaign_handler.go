@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	appmw "storeHouse/middleware"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"storeHouse/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+type CampaignHandler struct {
+	campaignService *services.CampaignService
+}
+
+func NewCampaignHandler(db *sqlx.DB) *CampaignHandler {
+	return &CampaignHandler{
+		campaignService: services.NewCampaignService(repository.NewPostgres(db)),
+	}
+}
+
+// CreateCampaign handles campaign creation
+func (h *CampaignHandler) CreateCampaign(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateCampaignRequest
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	// Validate request
+	if err := req.Validate(); err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	createdBy := appmw.GetUserFromContext(r).ID
+
+	campaign, err := h.campaignService.CreateCampaign(r.Context(), req, createdBy)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(campaign)
+}
+
+// GetCampaign handles getting campaign by ID
+func (h *CampaignHandler) GetCampaign(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	campaign, err := h.campaignService.GetCampaign(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(campaign)
+}
+
+// GetAllCampaigns handles getting all campaigns
+func (h *CampaignHandler) GetAllCampaigns(w http.ResponseWriter, r *http.Request) {
+	campaigns, err := h.campaignService.GetAllCampaigns(r.Context())
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(campaigns)
+}
+
+// UpdateCampaign handles updating campaign details
+func (h *CampaignHandler) UpdateCampaign(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req models.UpdateCampaignRequest
+
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	campaign, err := h.campaignService.UpdateCampaign(r.Context(), id, req)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(campaign)
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	appmw "storeHouse/middleware"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"storeHouse/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+type ChequeHandler struct {
+	chequeService *services.ChequeService
+}
+
+func NewChequeHandler(db *sqlx.DB) *ChequeHandler {
+	return &ChequeHandler{
+		chequeService: services.NewChequeService(repository.NewPostgres(db)),
+	}
+}
+
+// IssueCheque handles recording an issued cheque
+func (h *ChequeHandler) IssueCheque(w http.ResponseWriter, r *http.Request) {
+	var req models.IssueChequeRequest
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	var createdBy *string
+	if user := appmw.GetUserFromContext(r); user != nil {
+		createdBy = &user.ID
+	}
+
+	cheque, err := h.chequeService.IssueCheque(r.Context(), req, createdBy)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(cheque)
+}
+
+// GetCheques handles listing cheques, filtered by bank_account_id and status
+func (h *ChequeHandler) GetCheques(w http.ResponseWriter, r *http.Request) {
+	cheques, err := h.chequeService.GetCheques(r.Context(), r.URL.Query().Get("bank_account_id"), r.URL.Query().Get("status"))
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cheques)
+}
+
+// GetCheque handles getting a cheque by ID
+func (h *ChequeHandler) GetCheque(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	cheque, err := h.chequeService.GetCheque(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cheque)
+}
+
+// PresentCheque handles marking a cheque as presented
+func (h *ChequeHandler) PresentCheque(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req models.PresentChequeRequest
+
+	if r.ContentLength != 0 {
+		if !appmw.DecodeJSON(w, r, &req) {
+			return
+		}
+	}
+
+	cheque, err := h.chequeService.PresentCheque(r.Context(), id, req)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cheque)
+}
+
+// CancelCheque handles cancelling a cheque
+func (h *ChequeHandler) CancelCheque(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req models.CancelChequeRequest
+
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	cheque, err := h.chequeService.CancelCheque(r.Context(), id, req)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cheque)
+}
+
+// GetOutstandingCheques handles the outstanding cheques report per Bank account
+func (h *ChequeHandler) GetOutstandingCheques(w http.ResponseWriter, r *http.Request) {
+	asOf, ok := parseAsOf(w, r)
+	if !ok {
+		return
+	}
+
+	reports, err := h.chequeService.GetOutstandingCheques(r.Context(), r.URL.Query().Get("bank_account_id"), asOf)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reports)
+}
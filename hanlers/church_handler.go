@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"storeHouse/database"
+	appmw "storeHouse/middleware"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"storeHouse/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+type ChurchHandler struct {
+	churchService *services.ChurchService
+	tenants       *database.Tenants
+}
+
+func NewChurchHandler(db *sqlx.DB, tenants *database.Tenants) *ChurchHandler {
+	return &ChurchHandler{
+		churchService: services.NewChurchService(repository.NewPostgres(db)),
+		tenants:       tenants,
+	}
+}
+
+// GetMyChurches handles listing the churches the signed-in user works in
+func (h *ChurchHandler) GetMyChurches(w http.ResponseWriter, r *http.Request) {
+	churches, err := h.churchService.GetMyChurches(r.Context(), appmw.GetUserFromContext(r).ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(churches)
+}
+
+// CreateChurch handles adding a church
+func (h *ChurchHandler) CreateChurch(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateChurchRequest
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	church, err := h.churchService.CreateChurch(r.Context(), req, appmw.GetUserFromContext(r).ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	// Start the new church's background jobs
+	if err := h.tenants.Activate(church.ID); err != nil {
+		log.Printf("⚠️  Church %s: %v", church.ID, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(church)
+}
+
+// GetChurch handles getting a church by ID
+func (h *ChurchHandler) GetChurch(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	church, err := h.churchService.GetChurch(r.Context(), id, appmw.GetUserFromContext(r).ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(church)
+}
+
+// UpdateChurch handles updating a church
+func (h *ChurchHandler) UpdateChurch(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req models.UpdateChurchRequest
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	church, err := h.churchService.UpdateChurch(r.Context(), id, req, appmw.GetUserFromContext(r).ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(church)
+}
+
+// GetChurchUsers handles listing the users who work in a church
+func (h *ChurchHandler) GetChurchUsers(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	users, err := h.churchService.GetChurchUsers(r.Context(), id, appmw.GetUserFromContext(r).ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(users)
+}
+
+// SaveChurchUser handles giving a user access to a church or changing their role there
+func (h *ChurchHandler) SaveChurchUser(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req models.AddChurchUserRequest
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	users, err := h.churchService.SaveChurchUser(r.Context(), id, req, appmw.GetUserFromContext(r).ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(users)
+}
+
+// RemoveChurchUser handles taking a user's access to a church away
+func (h *ChurchHandler) RemoveChurchUser(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	userID := chi.URLParam(r, "userID")
+
+	if err := h.churchService.RemoveChurchUser(r.Context(), id, userID, appmw.GetUserFromContext(r).ID); err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.SuccessResponse{Message: "User removed from church successfully"})
+}
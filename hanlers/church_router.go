@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"storeHouse/database"
+	appmw "storeHouse/middleware"
+	"storeHouse/models"
+	"storeHouse/mpesa"
+	"storeHouse/repository"
+	"storeHouse/services"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+// churchRouters serves each request with the API of the church it works in, built once per
+// church on that church's connection so every query is limited to the church's rows
+type churchRouters struct {
+	db      *sqlx.DB
+	tenants *database.Tenants
+
+	mu      sync.Mutex
+	routers map[string]*chi.Mux
+}
+
+func newChurchRouters(db *sqlx.DB, tenants *database.Tenants) *churchRouters {
+	return &churchRouters{
+		db:      db,
+		tenants: tenants,
+		routers: make(map[string]*chi.Mux),
+	}
+}
+
+// ServeHTTP hands the request to the router of the church in its context
+func (c *churchRouters) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	church := appmw.GetChurchFromContext(r)
+	if church == nil {
+		appmw.WriteError(w, models.Invalid(appmw.ChurchHeader+" header required"))
+		return
+	}
+
+	router, err := c.routerFor(church.ID)
+	if err != nil {
+		log.Printf("⚠️  Church %s: %v", church.ID, err)
+		appmw.WriteError(w, models.Internal("church database unavailable"))
+		return
+	}
+
+	router.ServeHTTP(w, r)
+}
+
+// ServeAt hands the request to the church's route at path, for routes that are not mounted
+func (c *churchRouters) ServeAt(path string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			rctx.RoutePath = path
+		}
+		c.ServeHTTP(w, r)
+	}
+}
+
+func (c *churchRouters) routerFor(churchID string) (*chi.Mux, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if router, ok := c.routers[churchID]; ok {
+		return router, nil
+	}
+
+	db, err := c.tenants.ForChurch(churchID)
+	if err != nil {
+		return nil, err
+	}
+	router := churchRoutes(db)
+	c.routers[churchID] = router
+	return router, nil
+}
+
+// MpesaChurch picks the church of a C2B callback by its paybill short code, falling back
+// to the only church when there is just one
+func (c *churchRouters) MpesaChurch(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			writeC2BResponse(w, mpesa.Rejected(mpesa.ResultOtherError, "Invalid request"))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var callback mpesa.C2BCallback
+		if err := json.Unmarshal(body, &callback); err != nil {
+			writeC2BResponse(w, mpesa.Rejected(mpesa.ResultOtherError, "Invalid JSON"))
+			return
+		}
+
+		churches := repository.NewPostgres(c.db)
+		church, err := churches.GetChurchByShortcode(r.Context(), callback.BusinessShortCode)
+		if err != nil {
+			active, listErr := churches.GetActiveChurches(r.Context())
+			if listErr != nil || len(active) != 1 {
+				writeC2BResponse(w, mpesa.Rejected(mpesa.ResultInvalidShortCode, "Unknown short code"))
+				return
+			}
+			church = active[0]
+		}
+
+		next.ServeHTTP(w, appmw.WithChurch(r, appmw.Church{ID: church.ID, Code: church.Code, Name: church.Name}))
+	})
+}
+
+// SMSDeliveryReport handles the Africa's Talking delivery report callback, posted as a
+// form. The gateway account is shared, so the message is looked up in every church.
+func (c *churchRouters) SMSDeliveryReport(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		appmw.WriteError(w, models.Invalid("Invalid form"))
+		return
+	}
+
+	id := r.FormValue("id")
+	if id == "" {
+		appmw.WriteError(w, models.Invalid("id is required"))
+		return
+	}
+
+	churchIDs, err := c.tenants.ChurchIDs()
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	// Unknown message IDs are acknowledged so the gateway stops retrying them
+	for _, churchID := range churchIDs {
+		db, err := c.tenants.ForChurch(churchID)
+		if err != nil {
+			continue
+		}
+		_, err = services.NewSMSService(repository.NewPostgres(db)).RecordDeliveryReport(r.Context(), id, r.FormValue("status"), r.FormValue("failureReason"))
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, models.ErrSMSNotFound) {
+			appmw.WriteError(w, err)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
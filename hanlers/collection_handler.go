@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	appmw "storeHouse/middleware"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"storeHouse/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+type CollectionHandler struct {
+	collectionService *services.CollectionService
+}
+
+func NewCollectionHandler(db *sqlx.DB) *CollectionHandler {
+	return &CollectionHandler{
+		collectionService: services.NewCollectionService(repository.NewPostgres(db)),
+	}
+}
+
+// CreateSession handles opening a collection session
+func (h *CollectionHandler) CreateSession(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateCollectionSessionRequest
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	user := appmw.GetUserFromContext(r)
+	session, err := h.collectionService.CreateSession(r.Context(), req, &user.ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(session)
+}
+
+// GetSessions handles listing collection sessions, filtered by status
+func (h *CollectionHandler) GetSessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := h.collectionService.GetSessions(r.Context(), r.URL.Query().Get("status"))
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sessions)
+}
+
+// GetSession handles getting a collection session by ID
+func (h *CollectionHandler) GetSession(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	session, err := h.collectionService.GetSession(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
+
+// AddReceipt handles adding an envelope or the loose offering to a session
+func (h *CollectionHandler) AddReceipt(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req models.AddCollectionReceiptRequest
+
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	user := appmw.GetUserFromContext(r)
+	receipt, err := h.collectionService.AddReceipt(r.Context(), id, req, user.ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(receipt)
+}
+
+// RemoveReceipt handles taking a receipt out of a session
+func (h *CollectionHandler) RemoveReceipt(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	transactionID := chi.URLParam(r, "transactionID")
+
+	if err := h.collectionService.RemoveReceipt(r.Context(), id, transactionID); err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.SuccessResponse{Message: "Receipt removed successfully"})
+}
+
+// SetDenominations handles recording the cash count
+func (h *CollectionHandler) SetDenominations(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req models.SetDenominationsRequest
+
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	session, err := h.collectionService.SetDenominations(r.Context(), id, req)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
+
+// SignOff handles a counter confirming a session's totals
+func (h *CollectionHandler) SignOff(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req models.SignOffCollectionRequest
+
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	user := appmw.GetUserFromContext(r)
+	session, err := h.collectionService.SignOff(r.Context(), id, req, user.ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
+
+// CancelSession handles abandoning an open session
+func (h *CollectionHandler) CancelSession(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	user := appmw.GetUserFromContext(r)
+	session, err := h.collectionService.CancelSession(r.Context(), id, &user.ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"storeHouse/database"
+	appmw "storeHouse/middleware"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"storeHouse/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+type DistrictHandler struct {
+	districtService *services.DistrictService
+}
+
+func NewDistrictHandler(db *sqlx.DB, tenants *database.Tenants) *DistrictHandler {
+	return &DistrictHandler{
+		districtService: services.NewDistrictService(repository.NewPostgres(db), func(churchID string) (repository.Store, error) {
+			churchDB, err := tenants.ForChurch(churchID)
+			if err != nil {
+				return nil, err
+			}
+			return repository.NewPostgres(churchDB), nil
+		}),
+	}
+}
+
+// GetMyDistricts handles listing the districts the signed-in user holds a role in
+func (h *DistrictHandler) GetMyDistricts(w http.ResponseWriter, r *http.Request) {
+	districts, err := h.districtService.GetMyDistricts(r.Context(), appmw.GetUserFromContext(r).ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(districts)
+}
+
+// CreateDistrict handles adding a district
+func (h *DistrictHandler) CreateDistrict(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateDistrictRequest
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	district, err := h.districtService.CreateDistrict(r.Context(), req, appmw.GetUserFromContext(r).ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(district)
+}
+
+// GetDistrict handles getting a district with its churches
+func (h *DistrictHandler) GetDistrict(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	district, err := h.districtService.GetDistrict(r.Context(), id, appmw.GetUserFromContext(r).ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(district)
+}
+
+// AddChurch handles placing a church in a district
+func (h *DistrictHandler) AddChurch(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req models.AddDistrictChurchRequest
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	district, err := h.districtService.AddChurch(r.Context(), id, req, appmw.GetUserFromContext(r).ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(district)
+}
+
+// RemoveChurch handles taking a church out of a district
+func (h *DistrictHandler) RemoveChurch(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	churchID := chi.URLParam(r, "churchID")
+
+	if err := h.districtService.RemoveChurch(r.Context(), id, churchID, appmw.GetUserFromContext(r).ID); err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.SuccessResponse{Message: "Church removed from district successfully"})
+}
+
+// GetDistrictUsers handles listing the users with a role in a district
+func (h *DistrictHandler) GetDistrictUsers(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	users, err := h.districtService.GetDistrictUsers(r.Context(), id, appmw.GetUserFromContext(r).ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(users)
+}
+
+// SaveDistrictUser handles giving a user a role in a district
+func (h *DistrictHandler) SaveDistrictUser(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req models.AddDistrictUserRequest
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	users, err := h.districtService.SaveDistrictUser(r.Context(), id, req, appmw.GetUserFromContext(r).ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(users)
+}
+
+// RemoveDistrictUser handles taking a user's district role away
+func (h *DistrictHandler) RemoveDistrictUser(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	userID := chi.URLParam(r, "userID")
+
+	if err := h.districtService.RemoveDistrictUser(r.Context(), id, userID, appmw.GetUserFromContext(r).ID); err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.SuccessResponse{Message: "User removed from district successfully"})
+}
+
+// GetConsolidatedReport handles the district's consolidated income and remittance report
+func (h *DistrictHandler) GetConsolidatedReport(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	startDate, endDate, ok := parseReportPeriod(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.districtService.GetConsolidatedReport(r.Context(), id, appmw.GetUserFromContext(r).ID, startDate, endDate)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
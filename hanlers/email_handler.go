@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	appmw "storeHouse/middleware"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"storeHouse/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+type EmailHandler struct {
+	emailService *services.EmailService
+}
+
+func NewEmailHandler(db *sqlx.DB) *EmailHandler {
+	return &EmailHandler{
+		emailService: services.NewEmailService(repository.NewPostgres(db)),
+	}
+}
+
+// SendReceipt handles emailing a receipt to the member on a receipts transaction
+func (h *EmailHandler) SendReceipt(w http.ResponseWriter, r *http.Request) {
+	transactionID := chi.URLParam(r, "transactionID")
+
+	createdBy := appmw.GetUserFromContext(r).ID
+
+	email, err := h.emailService.SendReceipt(r.Context(), transactionID, createdBy)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(email)
+}
+
+// SendStatement handles emailing a member their giving statement for a period
+func (h *EmailHandler) SendStatement(w http.ResponseWriter, r *http.Request) {
+	memberID := chi.URLParam(r, "id")
+	var req models.SendStatementRequest
+
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	createdBy := appmw.GetUserFromContext(r).ID
+
+	email, err := h.emailService.SendStatement(r.Context(), memberID, req, createdBy)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(email)
+}
+
+// SendMonthlyReport handles emailing the monthly treasurer report
+func (h *EmailHandler) SendMonthlyReport(w http.ResponseWriter, r *http.Request) {
+	var req models.SendMonthlyReportRequest
+
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	createdBy := appmw.GetUserFromContext(r).ID
+
+	emails, err := h.emailService.SendMonthlyReport(r.Context(), req, createdBy)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(emails)
+}
+
+// GetEmails handles listing the outbox, optionally by status
+func (h *EmailHandler) GetEmails(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+
+	emails, err := h.emailService.GetEmails(r.Context(), status)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(emails)
+}
+
+// GetEmail handles getting an outbox email by ID
+func (h *EmailHandler) GetEmail(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	email, err := h.emailService.GetEmail(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(email)
+}
+
+// RetryEmail handles re-sending a failed or bounced email
+func (h *EmailHandler) RetryEmail(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	email, err := h.emailService.RetryEmail(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(email)
+}
+
+// MarkBounced handles recording a bounce reported after delivery
+func (h *EmailHandler) MarkBounced(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req models.MarkBouncedRequest
+
+	if r.ContentLength != 0 {
+		if !appmw.DecodeJSON(w, r, &req) {
+			return
+		}
+	}
+
+	email, err := h.emailService.MarkBounced(r.Context(), id, req)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(email)
+}
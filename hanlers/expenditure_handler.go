@@ -3,7 +3,9 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	appmw "storeHouse/middleware"
 	"storeHouse/models"
+	"storeHouse/repository"
 	"storeHouse/services"
 
 	"github.com/go-chi/chi/v5"
@@ -16,26 +18,22 @@ type ExpenditureHandler struct {
 
 func NewExpenditureHandler(db *sqlx.DB) *ExpenditureHandler {
 	return &ExpenditureHandler{
-		expenditureService: services.NewExpenditureService(db),
+		expenditureService: services.NewExpenditureService(repository.NewPostgres(db)),
 	}
 }
 
 // CreateExpenditure handles expenditure creation
 func (h *ExpenditureHandler) CreateExpenditure(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateExpenditureRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if !appmw.DecodeJSON(w, r, &req) {
 		return
 	}
 
-	// For now, using a default user ID - in real app, get from authentication
-	createdBy := "system"
+	createdBy := appmw.GetUserFromContext(r).ID
 
-	expenditure, err := h.expenditureService.CreateExpenditure(req, createdBy)
+	expenditure, err := h.expenditureService.CreateExpenditure(r.Context(), req, createdBy)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -48,11 +46,9 @@ func (h *ExpenditureHandler) CreateExpenditure(w http.ResponseWriter, r *http.Re
 func (h *ExpenditureHandler) GetExpenditure(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	expenditure, err := h.expenditureService.GetExpenditure(id)
+	expenditure, err := h.expenditureService.GetExpenditure(r.Context(), id)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -62,11 +58,9 @@ func (h *ExpenditureHandler) GetExpenditure(w http.ResponseWriter, r *http.Reque
 
 // GetAllExpenditures handles getting all expenditures
 func (h *ExpenditureHandler) GetAllExpenditures(w http.ResponseWriter, r *http.Request) {
-	expenditures, err := h.expenditureService.GetAllExpenditures()
+	expenditures, err := h.expenditureService.GetAllExpenditures(r.Context())
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -78,11 +72,9 @@ func (h *ExpenditureHandler) GetAllExpenditures(w http.ResponseWriter, r *http.R
 func (h *ExpenditureHandler) GetExpendituresByTransaction(w http.ResponseWriter, r *http.Request) {
 	transactionID := chi.URLParam(r, "transactionID")
 
-	expenditures, err := h.expenditureService.GetExpendituresByTransaction(transactionID)
+	expenditures, err := h.expenditureService.GetExpendituresByTransaction(r.Context(), transactionID)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -95,20 +87,13 @@ func (h *ExpenditureHandler) UpdateExpenditure(w http.ResponseWriter, r *http.Re
 	id := chi.URLParam(r, "id")
 	var req models.UpdateExpenditureRequest
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if !appmw.DecodeJSON(w, r, &req) {
 		return
 	}
 
-	expenditure, err := h.expenditureService.UpdateExpenditure(id, req)
+	expenditure, err := h.expenditureService.UpdateExpenditure(r.Context(), id, req)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		if err.Error() == "expenditure not found" {
-			w.WriteHeader(http.StatusNotFound)
-		} else {
-			w.WriteHeader(http.StatusBadRequest)
-		}
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -120,11 +105,9 @@ func (h *ExpenditureHandler) UpdateExpenditure(w http.ResponseWriter, r *http.Re
 func (h *ExpenditureHandler) DeleteExpenditure(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	err := h.expenditureService.DeleteExpenditure(id)
+	err := h.expenditureService.DeleteExpenditure(r.Context(), id)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	appmw "storeHouse/middleware"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"storeHouse/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+type FundHandler struct {
+	fundService *services.FundService
+}
+
+func NewFundHandler(db *sqlx.DB) *FundHandler {
+	return &FundHandler{
+		fundService: services.NewFundService(repository.NewPostgres(db)),
+	}
+}
+
+// CreateFund handles fund creation
+func (h *FundHandler) CreateFund(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateFundRequest
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	fund, err := h.fundService.CreateFund(r.Context(), req)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(fund)
+}
+
+// GetAllFunds handles listing funds
+func (h *FundHandler) GetAllFunds(w http.ResponseWriter, r *http.Request) {
+	funds, err := h.fundService.GetAllFunds(r.Context())
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(funds)
+}
+
+// GetFund handles getting a fund by ID
+func (h *FundHandler) GetFund(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	fund, err := h.fundService.GetFund(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(fund)
+}
+
+// UpdateFund handles updating a fund
+func (h *FundHandler) UpdateFund(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req models.UpdateFundRequest
+
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	fund, err := h.fundService.UpdateFund(r.Context(), id, req)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(fund)
+}
+
+// GetBalances handles the fund balances report
+func (h *FundHandler) GetBalances(w http.ResponseWriter, r *http.Request) {
+	asOf, ok := parseAsOf(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.fundService.GetBalances(r.Context(), asOf)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// GetBalance handles the balance of one fund
+func (h *FundHandler) GetBalance(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	asOf, ok := parseAsOf(w, r)
+	if !ok {
+		return
+	}
+
+	balance, err := h.fundService.GetBalance(r.Context(), id, asOf)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(balance)
+}
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	appmw "storeHouse/middleware"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"storeHouse/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+type ImprestHandler struct {
+	imprestService *services.ImprestService
+}
+
+func NewImprestHandler(db *sqlx.DB) *ImprestHandler {
+	return &ImprestHandler{
+		imprestService: services.NewImprestService(repository.NewPostgres(db)),
+	}
+}
+
+// CreateImprest handles setting up a petty cash imprest
+func (h *ImprestHandler) CreateImprest(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateImprestRequest
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	imprest, err := h.imprestService.CreateImprest(r.Context(), req, &appmw.GetUserFromContext(r).ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(imprest)
+}
+
+// GetAllImprests handles listing imprests with their cash position
+func (h *ImprestHandler) GetAllImprests(w http.ResponseWriter, r *http.Request) {
+	imprests, err := h.imprestService.GetAllImprests(r.Context())
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(imprests)
+}
+
+// GetImprest handles getting an imprest by ID
+func (h *ImprestHandler) GetImprest(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	imprest, err := h.imprestService.GetImprest(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(imprest)
+}
+
+// UpdateImprest handles updating an imprest
+func (h *ImprestHandler) UpdateImprest(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req models.UpdateImprestRequest
+
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	imprest, err := h.imprestService.UpdateImprest(r.Context(), id, req)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(imprest)
+}
+
+// CreateVoucher handles recording a petty cash payment
+func (h *ImprestHandler) CreateVoucher(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req models.CreatePettyCashVoucherRequest
+
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	var createdBy *string
+	if user := appmw.GetUserFromContext(r); user != nil {
+		createdBy = &user.ID
+	}
+
+	voucher, err := h.imprestService.CreateVoucher(r.Context(), id, req, createdBy)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(voucher)
+}
+
+// GetVouchers handles listing an imprest's vouchers, optionally by status
+func (h *ImprestHandler) GetVouchers(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	status := r.URL.Query().Get("status")
+
+	vouchers, err := h.imprestService.GetVouchers(r.Context(), id, status)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(vouchers)
+}
+
+// DeleteVoucher handles deleting an open petty cash voucher
+func (h *ImprestHandler) DeleteVoucher(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "voucherID")
+
+	if err := h.imprestService.DeleteVoucher(r.Context(), id); err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.SuccessResponse{Message: "Petty cash voucher deleted successfully"})
+}
+
+// RequestReplenishment handles asking for an imprest to be topped up to its float
+func (h *ImprestHandler) RequestReplenishment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req models.RequestReplenishmentRequest
+
+	if r.ContentLength != 0 {
+		if !appmw.DecodeJSON(w, r, &req) {
+			return
+		}
+	}
+
+	replenishment, err := h.imprestService.RequestReplenishment(r.Context(), id, req, appmw.GetUserFromContext(r).ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(replenishment)
+}
+
+// GetReplenishments handles listing an imprest's replenishments
+func (h *ImprestHandler) GetReplenishments(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	replenishments, err := h.imprestService.GetReplenishments(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(replenishments)
+}
+
+// GetReplenishment handles getting a replenishment with its vouchers
+func (h *ImprestHandler) GetReplenishment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "replenishmentID")
+
+	replenishment, err := h.imprestService.GetReplenishment(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(replenishment)
+}
+
+// ApproveReplenishment handles a treasurer or admin posting a replenishment
+func (h *ImprestHandler) ApproveReplenishment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "replenishmentID")
+
+	user := appmw.GetUserFromContext(r)
+	replenishment, err := h.imprestService.ApproveReplenishment(r.Context(), id, user.ID, user.Role)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(replenishment)
+}
+
+// RejectReplenishment handles a treasurer or admin turning a replenishment down
+func (h *ImprestHandler) RejectReplenishment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "replenishmentID")
+
+	req, ok := decodeVoucherReview(w, r)
+	if !ok {
+		return
+	}
+
+	replenishment, err := h.imprestService.RejectReplenishment(r.Context(), id, appmw.GetUserFromContext(r).ID, req)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(replenishment)
+}
@@ -3,7 +3,9 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	appmw "storeHouse/middleware"
 	"storeHouse/models"
+	"storeHouse/repository"
 	"storeHouse/services"
 
 	"github.com/go-chi/chi/v5"
@@ -16,26 +18,22 @@ type MemberHandler struct {
 
 func NewMemberHandler(db *sqlx.DB) *MemberHandler {
 	return &MemberHandler{
-		memberService: services.NewMemberService(db),
+		memberService: services.NewMemberService(repository.NewPostgres(db)),
 	}
 }
 
 // CreateMember handles member creation
 func (h *MemberHandler) CreateMember(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateMemberRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if !appmw.DecodeJSON(w, r, &req) {
 		return
 	}
 
-	// For now, using a default user ID - in real app, get from authentication
-	createdBy := "system"
+	createdBy := appmw.GetUserFromContext(r).ID
 
-	member, err := h.memberService.CreateMember(req, createdBy)
+	member, err := h.memberService.CreateMember(r.Context(), req, createdBy)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -48,11 +46,9 @@ func (h *MemberHandler) CreateMember(w http.ResponseWriter, r *http.Request) {
 func (h *MemberHandler) GetMember(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	member, err := h.memberService.GetMember(id)
+	member, err := h.memberService.GetMember(r.Context(), id)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -64,11 +60,9 @@ func (h *MemberHandler) GetMember(w http.ResponseWriter, r *http.Request) {
 func (h *MemberHandler) GetMemberByPhone(w http.ResponseWriter, r *http.Request) {
 	phoneNumber := chi.URLParam(r, "phone")
 
-	member, err := h.memberService.GetMemberByPhone(phoneNumber)
+	member, err := h.memberService.GetMemberByPhone(r.Context(), phoneNumber)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -80,11 +74,9 @@ func (h *MemberHandler) GetMemberByPhone(w http.ResponseWriter, r *http.Request)
 func (h *MemberHandler) GetMemberByEmail(w http.ResponseWriter, r *http.Request) {
 	email := chi.URLParam(r, "email")
 
-	member, err := h.memberService.GetMemberByEmail(email)
+	member, err := h.memberService.GetMemberByEmail(r.Context(), email)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -94,11 +86,9 @@ func (h *MemberHandler) GetMemberByEmail(w http.ResponseWriter, r *http.Request)
 
 // GetAllMembers handles getting all members
 func (h *MemberHandler) GetAllMembers(w http.ResponseWriter, r *http.Request) {
-	members, err := h.memberService.GetAllMembers()
+	members, err := h.memberService.GetAllMembers(r.Context())
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -111,20 +101,13 @@ func (h *MemberHandler) UpdateMember(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	var req models.UpdateMemberRequest
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if !appmw.DecodeJSON(w, r, &req) {
 		return
 	}
 
-	member, err := h.memberService.UpdateMember(id, req)
+	member, err := h.memberService.UpdateMember(r.Context(), id, req)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		if err.Error() == "member not found" {
-			w.WriteHeader(http.StatusNotFound)
-		} else {
-			w.WriteHeader(http.StatusBadRequest)
-		}
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -136,11 +119,9 @@ func (h *MemberHandler) UpdateMember(w http.ResponseWriter, r *http.Request) {
 func (h *MemberHandler) DeleteMember(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	err := h.memberService.DeleteMember(id)
+	err := h.memberService.DeleteMember(r.Context(), id)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -152,11 +133,9 @@ func (h *MemberHandler) DeleteMember(w http.ResponseWriter, r *http.Request) {
 func (h *MemberHandler) GetMembersByGroup(w http.ResponseWriter, r *http.Request) {
 	groupID := chi.URLParam(r, "groupID")
 
-	members, err := h.memberService.GetMembersByGroup(groupID)
+	members, err := h.memberService.GetMembersByGroup(r.Context(), groupID)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -169,17 +148,13 @@ func (h *MemberHandler) SearchMembers(w http.ResponseWriter, r *http.Request) {
 	searchTerm := r.URL.Query().Get("q")
 
 	if searchTerm == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: "search term 'q' query parameter is required"})
+		appmw.WriteError(w, models.Invalid("search term 'q' query parameter is required"))
 		return
 	}
 
-	members, err := h.memberService.SearchMembers(searchTerm)
+	members, err := h.memberService.SearchMembers(r.Context(), searchTerm)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
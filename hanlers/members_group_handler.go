@@ -3,7 +3,9 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	appmw "storeHouse/middleware"
 	"storeHouse/models"
+	"storeHouse/repository"
 	"storeHouse/services"
 
 	"github.com/go-chi/chi/v5"
@@ -16,26 +18,22 @@ type MembersGroupHandler struct {
 
 func NewMembersGroupHandler(db *sqlx.DB) *MembersGroupHandler {
 	return &MembersGroupHandler{
-		groupService: services.NewMembersGroupService(db),
+		groupService: services.NewMembersGroupService(repository.NewPostgres(db)),
 	}
 }
 
 // CreateGroup handles group creation
 func (h *MembersGroupHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateGroupRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if !appmw.DecodeJSON(w, r, &req) {
 		return
 	}
 
-	// For now, using a default user ID - in real app, get from authentication
-	createdBy := "system"
+	createdBy := appmw.GetUserFromContext(r).ID
 
-	group, err := h.groupService.CreateGroup(req, createdBy)
+	group, err := h.groupService.CreateGroup(r.Context(), req, createdBy)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -48,11 +46,9 @@ func (h *MembersGroupHandler) CreateGroup(w http.ResponseWriter, r *http.Request
 func (h *MembersGroupHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	group, err := h.groupService.GetGroup(id)
+	group, err := h.groupService.GetGroup(r.Context(), id)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -64,11 +60,9 @@ func (h *MembersGroupHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
 func (h *MembersGroupHandler) GetGroupByName(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
 
-	group, err := h.groupService.GetGroupByName(name)
+	group, err := h.groupService.GetGroupByName(r.Context(), name)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -78,11 +72,9 @@ func (h *MembersGroupHandler) GetGroupByName(w http.ResponseWriter, r *http.Requ
 
 // GetAllGroups handles getting all groups
 func (h *MembersGroupHandler) GetAllGroups(w http.ResponseWriter, r *http.Request) {
-	groups, err := h.groupService.GetAllGroups()
+	groups, err := h.groupService.GetAllGroups(r.Context())
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -92,11 +84,9 @@ func (h *MembersGroupHandler) GetAllGroups(w http.ResponseWriter, r *http.Reques
 
 // GetGroupsWithMemberCount handles getting all groups with their member counts
 func (h *MembersGroupHandler) GetGroupsWithMemberCount(w http.ResponseWriter, r *http.Request) {
-	groups, err := h.groupService.GetGroupsWithMemberCount()
+	groups, err := h.groupService.GetGroupsWithMemberCount(r.Context())
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -108,11 +98,9 @@ func (h *MembersGroupHandler) GetGroupsWithMemberCount(w http.ResponseWriter, r
 func (h *MembersGroupHandler) GetGroupMemberCount(w http.ResponseWriter, r *http.Request) {
 	groupID := chi.URLParam(r, "id")
 
-	count, err := h.groupService.GetGroupMemberCount(groupID)
+	count, err := h.groupService.GetGroupMemberCount(r.Context(), groupID)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -125,20 +113,13 @@ func (h *MembersGroupHandler) UpdateGroup(w http.ResponseWriter, r *http.Request
 	id := chi.URLParam(r, "id")
 	var req models.UpdateGroupRequest
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if !appmw.DecodeJSON(w, r, &req) {
 		return
 	}
 
-	group, err := h.groupService.UpdateGroup(id, req)
+	group, err := h.groupService.UpdateGroup(r.Context(), id, req)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		if err.Error() == "group not found" {
-			w.WriteHeader(http.StatusNotFound)
-		} else {
-			w.WriteHeader(http.StatusBadRequest)
-		}
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -150,15 +131,9 @@ func (h *MembersGroupHandler) UpdateGroup(w http.ResponseWriter, r *http.Request
 func (h *MembersGroupHandler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	err := h.groupService.DeleteGroup(id)
+	err := h.groupService.DeleteGroup(r.Context(), id)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		if err.Error() == "group not found" {
-			w.WriteHeader(http.StatusNotFound)
-		} else {
-			w.WriteHeader(http.StatusBadRequest)
-		}
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
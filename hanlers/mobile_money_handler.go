@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	appmw "storeHouse/middleware"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"storeHouse/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+type MobileMoneyHandler struct {
+	mobileMoneyService *services.MobileMoneyService
+}
+
+func NewMobileMoneyHandler(db *sqlx.DB) *MobileMoneyHandler {
+	return &MobileMoneyHandler{
+		mobileMoneyService: services.NewMobileMoneyService(repository.NewPostgres(db)),
+	}
+}
+
+// ImportStatement handles uploading an M-Pesa or Airtel Money statement CSV as a multipart
+// form with the fields file, provider, receiving_account_id and optionally income_account_id
+func (h *MobileMoneyHandler) ImportStatement(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(maxStatementUploadSize); err != nil {
+		appmw.WriteError(w, models.Invalid("Invalid multipart form"))
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		appmw.WriteError(w, models.Invalid("Statement file is required"))
+		return
+	}
+	defer file.Close()
+
+	req := models.ImportMobileMoneyRequest{
+		Provider:           r.FormValue("provider"),
+		FileName:           header.Filename,
+		ReceivingAccountID: r.FormValue("receiving_account_id"),
+		IncomeAccountID:    r.FormValue("income_account_id"),
+	}
+
+	createdBy := appmw.GetUserFromContext(r).ID
+
+	statement, err := h.mobileMoneyService.ImportStatement(r.Context(), req, file, createdBy)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(statement)
+}
+
+// GetImports handles listing mobile money imports
+func (h *MobileMoneyHandler) GetImports(w http.ResponseWriter, r *http.Request) {
+	imports, err := h.mobileMoneyService.GetImports(r.Context())
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(imports)
+}
+
+// GetImport handles getting an import with its lines
+func (h *MobileMoneyHandler) GetImport(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	statement, err := h.mobileMoneyService.GetImport(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statement)
+}
+
+// GetLines handles the review queue, filtered by status (pending by default)
+func (h *MobileMoneyHandler) GetLines(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+
+	lines, err := h.mobileMoneyService.GetLines(r.Context(), status)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(lines)
+}
+
+// UpdateLine handles reassigning the member or income account of a pending line
+func (h *MobileMoneyHandler) UpdateLine(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req models.UpdateMobileMoneyLineRequest
+
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	line, err := h.mobileMoneyService.UpdateLine(r.Context(), id, req)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(line)
+}
+
+// ConfirmLine handles a treasurer confirming a pending line, posting it as a receipt
+func (h *MobileMoneyHandler) ConfirmLine(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req, ok := decodeReviewRequest(w, r)
+	if !ok {
+		return
+	}
+
+	line, err := h.mobileMoneyService.ConfirmLine(r.Context(), id, req, appmw.GetUserFromContext(r).ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(line)
+}
+
+// RejectLine handles a treasurer rejecting a pending line
+func (h *MobileMoneyHandler) RejectLine(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req, ok := decodeReviewRequest(w, r)
+	if !ok {
+		return
+	}
+
+	line, err := h.mobileMoneyService.RejectLine(r.Context(), id, req, appmw.GetUserFromContext(r).ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(line)
+}
+
+// decodeReviewRequest reads the optional review body; an empty body confirms or rejects
+// the line as it stands
+func decodeReviewRequest(w http.ResponseWriter, r *http.Request) (models.ReviewMobileMoneyLineRequest, bool) {
+	var req models.ReviewMobileMoneyLineRequest
+	if r.ContentLength == 0 {
+		return req, true
+	}
+	if !appmw.DecodeJSON(w, r, &req) {
+		return req, false
+	}
+	return req, true
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net/http"
+	appmw "storeHouse/middleware"
+	"storeHouse/models"
+	"storeHouse/mpesa"
+	"storeHouse/repository"
+	"storeHouse/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+type MpesaHandler struct {
+	mpesaService  *services.MpesaService
+	callbackToken string
+}
+
+func NewMpesaHandler(db *sqlx.DB) *MpesaHandler {
+	cfg := mpesa.LoadConfig()
+	return &MpesaHandler{
+		mpesaService:  services.NewMpesaService(repository.NewPostgres(db), cfg),
+		callbackToken: cfg.CallbackToken,
+	}
+}
+
+// ValidateC2B handles the Daraja C2B validation callback
+func (h *MpesaHandler) ValidateC2B(w http.ResponseWriter, r *http.Request) {
+	if !h.authorised(w, r) {
+		return
+	}
+
+	var callback mpesa.C2BCallback
+	if err := json.NewDecoder(r.Body).Decode(&callback); err != nil {
+		writeC2BResponse(w, mpesa.Rejected(mpesa.ResultOtherError, "Invalid JSON"))
+		return
+	}
+
+	writeC2BResponse(w, h.mpesaService.ValidatePayment(r.Context(), callback))
+}
+
+// ConfirmC2B handles the Daraja C2B confirmation callback. Payments that cannot be posted
+// are still acknowledged and kept as failed for a treasurer to retry.
+func (h *MpesaHandler) ConfirmC2B(w http.ResponseWriter, r *http.Request) {
+	if !h.authorised(w, r) {
+		return
+	}
+
+	var callback mpesa.C2BCallback
+	if err := json.NewDecoder(r.Body).Decode(&callback); err != nil {
+		writeC2BResponse(w, mpesa.Rejected(mpesa.ResultOtherError, "Invalid JSON"))
+		return
+	}
+
+	// Callbacks are not authenticated as a user; the church's M-Pesa user records them
+	createdBy, err := h.mpesaService.CallbackUser(r.Context(), appmw.GetChurchFromContext(r).Code)
+	if err != nil {
+		log.Printf("⚠️  Failed to get the M-Pesa user for payment %s: %v", callback.TransID, err)
+		writeC2BResponse(w, mpesa.Rejected(mpesa.ResultOtherError, "Service unavailable"))
+		return
+	}
+
+	if _, err := h.mpesaService.ConfirmPayment(r.Context(), callback, createdBy); err != nil {
+		log.Printf("⚠️  Failed to record M-Pesa payment %s: %v", callback.TransID, err)
+		writeC2BResponse(w, mpesa.Rejected(mpesa.ResultOtherError, "Service unavailable"))
+		return
+	}
+
+	writeC2BResponse(w, mpesa.Accepted())
+}
+
+// GetMpesaTransactions handles listing received M-Pesa payments, optionally by status
+func (h *MpesaHandler) GetMpesaTransactions(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+
+	payments, err := h.mpesaService.GetMpesaTransactions(r.Context(), status)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payments)
+}
+
+// GetMpesaTransaction handles getting an M-Pesa payment by ID
+func (h *MpesaHandler) GetMpesaTransaction(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	payment, err := h.mpesaService.GetMpesaTransaction(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payment)
+}
+
+// RetryMpesaTransaction handles re-posting a failed M-Pesa payment
+func (h *MpesaHandler) RetryMpesaTransaction(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	payment, err := h.mpesaService.RetryPayment(r.Context(), id, appmw.GetUserFromContext(r).ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payment)
+}
+
+// authorised checks the callback token. Callbacks are not served without one, since they
+// post receipts without a signed-in user.
+func (h *MpesaHandler) authorised(w http.ResponseWriter, r *http.Request) bool {
+	if h.callbackToken == "" {
+		appmw.WriteError(w, models.Unavailable("M-Pesa callbacks are disabled until MPESA_CALLBACK_TOKEN is set"))
+		return false
+	}
+
+	token := r.URL.Query().Get("token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(h.callbackToken)) == 1 {
+		return true
+	}
+
+	appmw.WriteError(w, models.Unauthorized("invalid callback token"))
+	return false
+}
+
+// writeC2BResponse writes a Daraja acknowledgement
+func writeC2BResponse(w http.ResponseWriter, resp mpesa.C2BResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	appmw "storeHouse/middleware"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"storeHouse/services"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+type PayeeHandler struct {
+	payeeService *services.PayeeService
+}
+
+func NewPayeeHandler(db *sqlx.DB) *PayeeHandler {
+	return &PayeeHandler{
+		payeeService: services.NewPayeeService(repository.NewPostgres(db)),
+	}
+}
+
+// CreatePayee handles payee creation
+func (h *PayeeHandler) CreatePayee(w http.ResponseWriter, r *http.Request) {
+	var req models.CreatePayeeRequest
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	var createdBy *string
+	if user := appmw.GetUserFromContext(r); user != nil {
+		createdBy = &user.ID
+	}
+
+	payee, err := h.payeeService.CreatePayee(r.Context(), req, createdBy)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(payee)
+}
+
+// GetAllPayees handles listing payees
+func (h *PayeeHandler) GetAllPayees(w http.ResponseWriter, r *http.Request) {
+	payees, err := h.payeeService.GetAllPayees(r.Context())
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payees)
+}
+
+// SearchPayees handles searching payees by name, phone, KRA PIN or email
+func (h *PayeeHandler) SearchPayees(w http.ResponseWriter, r *http.Request) {
+	searchTerm := r.URL.Query().Get("q")
+
+	if searchTerm == "" {
+		appmw.WriteError(w, models.Invalid("search term 'q' query parameter is required"))
+		return
+	}
+
+	payees, err := h.payeeService.SearchPayees(r.Context(), searchTerm)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payees)
+}
+
+// GetPayee handles getting a payee by ID
+func (h *PayeeHandler) GetPayee(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	payee, err := h.payeeService.GetPayee(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payee)
+}
+
+// UpdatePayee handles updating a payee
+func (h *PayeeHandler) UpdatePayee(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req models.UpdatePayeeRequest
+
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	payee, err := h.payeeService.UpdatePayee(r.Context(), id, req)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payee)
+}
+
+// DeletePayee handles deleting a payee
+func (h *PayeeHandler) DeletePayee(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := h.payeeService.DeletePayee(r.Context(), id); err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.SuccessResponse{Message: "Payee deleted successfully"})
+}
+
+// GetSpendSummary handles the spend-by-payee report
+func (h *PayeeHandler) GetSpendSummary(w http.ResponseWriter, r *http.Request) {
+	startDate, endDate, ok := parseReportPeriod(w, r)
+	if !ok {
+		return
+	}
+
+	summary, err := h.payeeService.GetSpendSummary(r.Context(), startDate, endDate)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
+
+// GetSpendReport handles the spend report for one payee
+func (h *PayeeHandler) GetSpendReport(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	startDate, endDate, ok := parseReportPeriod(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.payeeService.GetSpendReport(r.Context(), id, startDate, endDate)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// parseReportPeriod reads the optional start_date and end_date query parameters,
+// defaulting to the start of the current year and now
+func parseReportPeriod(w http.ResponseWriter, r *http.Request) (time.Time, time.Time, bool) {
+	now := time.Now()
+	startDate := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+	endDate := now
+
+	for name, target := range map[string]*time.Time{"start_date": &startDate, "end_date": &endDate} {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			appmw.WriteError(w, models.Invalid("invalid "+name+" format, use RFC3339"))
+			return time.Time{}, time.Time{}, false
+		}
+		*target = parsed
+	}
+
+	return startDate, endDate, true
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	appmw "storeHouse/middleware"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"storeHouse/services"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+type PledgeHandler struct {
+	pledgeService *services.PledgeService
+}
+
+func NewPledgeHandler(db *sqlx.DB) *PledgeHandler {
+	return &PledgeHandler{
+		pledgeService: services.NewPledgeService(repository.NewPostgres(db)),
+	}
+}
+
+// CreatePledge handles pledge creation
+func (h *PledgeHandler) CreatePledge(w http.ResponseWriter, r *http.Request) {
+	var req models.CreatePledgeRequest
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	// Validate request
+	if err := req.Validate(); err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	createdBy := appmw.GetUserFromContext(r).ID
+
+	pledge, err := h.pledgeService.CreatePledge(r.Context(), req, createdBy)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(pledge)
+}
+
+// GetPledge handles getting pledge by ID
+func (h *PledgeHandler) GetPledge(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	pledge, err := h.pledgeService.GetPledge(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pledge)
+}
+
+// GetPledgesByCampaign handles getting pledges for a specific campaign
+func (h *PledgeHandler) GetPledgesByCampaign(w http.ResponseWriter, r *http.Request) {
+	campaignID := chi.URLParam(r, "id")
+
+	pledges, err := h.pledgeService.GetPledgesByCampaign(r.Context(), campaignID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pledges)
+}
+
+// GetPledgesByMember handles getting pledges for a specific member
+func (h *PledgeHandler) GetPledgesByMember(w http.ResponseWriter, r *http.Request) {
+	memberID := chi.URLParam(r, "memberID")
+
+	pledges, err := h.pledgeService.GetPledgesByMember(r.Context(), memberID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pledges)
+}
+
+// GetPledgePayments handles getting the receipts applied to a pledge
+func (h *PledgeHandler) GetPledgePayments(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	payments, err := h.pledgeService.GetPledgePayments(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payments)
+}
+
+// UpdatePledge handles updating pledge details
+func (h *PledgeHandler) UpdatePledge(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req models.UpdatePledgeRequest
+
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	pledge, err := h.pledgeService.UpdatePledge(r.Context(), id, req)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pledge)
+}
+
+// DeletePledge handles deleting a pledge
+func (h *PledgeHandler) DeletePledge(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	err := h.pledgeService.DeletePledge(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.SuccessResponse{Message: "Pledge deleted successfully"})
+}
+
+// GetFulfilmentByMember handles the per-member pledge fulfilment report
+func (h *PledgeHandler) GetFulfilmentByMember(w http.ResponseWriter, r *http.Request) {
+	campaignID := chi.URLParam(r, "id")
+
+	asOf, ok := parseAsOf(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.pledgeService.GetFulfilmentByMember(r.Context(), campaignID, asOf)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// GetFulfilmentByGroup handles the per-group pledge fulfilment report
+func (h *PledgeHandler) GetFulfilmentByGroup(w http.ResponseWriter, r *http.Request) {
+	campaignID := chi.URLParam(r, "id")
+
+	asOf, ok := parseAsOf(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.pledgeService.GetFulfilmentByGroup(r.Context(), campaignID, asOf)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// parseAsOf reads the optional as_of query parameter, defaulting to now
+func parseAsOf(w http.ResponseWriter, r *http.Request) (time.Time, bool) {
+	asOfStr := r.URL.Query().Get("as_of")
+	if asOfStr == "" {
+		return time.Now(), true
+	}
+
+	asOf, err := time.Parse(time.RFC3339, asOfStr)
+	if err != nil {
+		appmw.WriteError(w, models.Invalid("invalid as_of format, use RFC3339"))
+		return time.Time{}, false
+	}
+
+	return asOf, true
+}
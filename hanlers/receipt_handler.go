@@ -3,7 +3,9 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	appmw "storeHouse/middleware"
 	"storeHouse/models"
+	"storeHouse/repository"
 	"storeHouse/services"
 	"time"
 
@@ -17,23 +19,20 @@ type ReceiptHandler struct {
 
 func NewReceiptHandler(db *sqlx.DB) *ReceiptHandler {
 	return &ReceiptHandler{
-		receiptService: services.NewReceiptService(db),
+		receiptService: services.NewReceiptService(repository.NewPostgres(db)),
 	}
 }
 
 // CreateReceipt handles receipt creation
 func (h *ReceiptHandler) CreateReceipt(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateReceiptRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if !appmw.DecodeJSON(w, r, &req) {
 		return
 	}
 
-	receipt, err := h.receiptService.CreateReceipt(req)
+	receipt, err := h.receiptService.CreateReceipt(r.Context(), req)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -46,11 +45,9 @@ func (h *ReceiptHandler) CreateReceipt(w http.ResponseWriter, r *http.Request) {
 func (h *ReceiptHandler) GetReceipt(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	receipt, err := h.receiptService.GetReceipt(id)
+	receipt, err := h.receiptService.GetReceipt(r.Context(), id)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -60,11 +57,9 @@ func (h *ReceiptHandler) GetReceipt(w http.ResponseWriter, r *http.Request) {
 
 // GetAllReceipts handles getting all receipts
 func (h *ReceiptHandler) GetAllReceipts(w http.ResponseWriter, r *http.Request) {
-	receipts, err := h.receiptService.GetAllReceipts()
+	receipts, err := h.receiptService.GetAllReceipts(r.Context())
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -76,11 +71,9 @@ func (h *ReceiptHandler) GetAllReceipts(w http.ResponseWriter, r *http.Request)
 func (h *ReceiptHandler) GetReceiptsByTransaction(w http.ResponseWriter, r *http.Request) {
 	transactionID := chi.URLParam(r, "transactionID")
 
-	receipts, err := h.receiptService.GetReceiptsByTransaction(transactionID)
+	receipts, err := h.receiptService.GetReceiptsByTransaction(r.Context(), transactionID)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -92,11 +85,9 @@ func (h *ReceiptHandler) GetReceiptsByTransaction(w http.ResponseWriter, r *http
 func (h *ReceiptHandler) GetReceiptsByAccount(w http.ResponseWriter, r *http.Request) {
 	accountID := chi.URLParam(r, "accountID")
 
-	receipts, err := h.receiptService.GetReceiptsByAccount(accountID)
+	receipts, err := h.receiptService.GetReceiptsByAccount(r.Context(), accountID)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -108,11 +99,9 @@ func (h *ReceiptHandler) GetReceiptsByAccount(w http.ResponseWriter, r *http.Req
 func (h *ReceiptHandler) GetTotalReceiptsByAccount(w http.ResponseWriter, r *http.Request) {
 	accountID := chi.URLParam(r, "accountID")
 
-	total, err := h.receiptService.GetTotalReceiptsByAccount(accountID)
+	total, err := h.receiptService.GetTotalReceiptsByAccount(r.Context(), accountID)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -126,33 +115,25 @@ func (h *ReceiptHandler) GetReceiptsByDateRange(w http.ResponseWriter, r *http.R
 	endDateStr := r.URL.Query().Get("end_date")
 
 	if startDateStr == "" || endDateStr == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: "start_date and end_date query parameters are required"})
+		appmw.WriteError(w, models.Invalid("start_date and end_date query parameters are required"))
 		return
 	}
 
 	startDate, err := time.Parse(time.RFC3339, startDateStr)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: "invalid start_date format, use RFC3339"})
+		appmw.WriteError(w, models.Invalid("invalid start_date format, use RFC3339"))
 		return
 	}
 
 	endDate, err := time.Parse(time.RFC3339, endDateStr)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: "invalid end_date format, use RFC3339"})
+		appmw.WriteError(w, models.Invalid("invalid end_date format, use RFC3339"))
 		return
 	}
 
-	receipts, err := h.receiptService.GetReceiptsByDateRange(startDate, endDate)
+	receipts, err := h.receiptService.GetReceiptsByDateRange(r.Context(), startDate, endDate)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -167,33 +148,25 @@ func (h *ReceiptHandler) GetTotalReceiptsByDateRange(w http.ResponseWriter, r *h
 	endDateStr := r.URL.Query().Get("end_date")
 
 	if startDateStr == "" || endDateStr == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: "start_date and end_date query parameters are required"})
+		appmw.WriteError(w, models.Invalid("start_date and end_date query parameters are required"))
 		return
 	}
 
 	startDate, err := time.Parse(time.RFC3339, startDateStr)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: "invalid start_date format, use RFC3339"})
+		appmw.WriteError(w, models.Invalid("invalid start_date format, use RFC3339"))
 		return
 	}
 
 	endDate, err := time.Parse(time.RFC3339, endDateStr)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: "invalid end_date format, use RFC3339"})
+		appmw.WriteError(w, models.Invalid("invalid end_date format, use RFC3339"))
 		return
 	}
 
-	total, err := h.receiptService.GetTotalReceiptsByDateRange(accountID, startDate, endDate)
+	total, err := h.receiptService.GetTotalReceiptsByDateRange(r.Context(), accountID, startDate, endDate)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -206,20 +179,13 @@ func (h *ReceiptHandler) UpdateReceipt(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	var req models.UpdateReceiptRequest
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if !appmw.DecodeJSON(w, r, &req) {
 		return
 	}
 
-	receipt, err := h.receiptService.UpdateReceipt(id, req)
+	receipt, err := h.receiptService.UpdateReceipt(r.Context(), id, req)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		if err.Error() == "receipt not found" {
-			w.WriteHeader(http.StatusNotFound)
-		} else {
-			w.WriteHeader(http.StatusBadRequest)
-		}
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -231,11 +197,9 @@ func (h *ReceiptHandler) UpdateReceipt(w http.ResponseWriter, r *http.Request) {
 func (h *ReceiptHandler) DeleteReceipt(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	err := h.receiptService.DeleteReceipt(id)
+	err := h.receiptService.DeleteReceipt(r.Context(), id)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
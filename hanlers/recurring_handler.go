@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	appmw "storeHouse/middleware"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"storeHouse/services"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+type RecurringHandler struct {
+	recurringService *services.RecurringService
+}
+
+func NewRecurringHandler(db *sqlx.DB) *RecurringHandler {
+	return &RecurringHandler{
+		recurringService: services.NewRecurringService(repository.NewPostgres(db)),
+	}
+}
+
+// CreateTemplate handles recurring template creation
+func (h *RecurringHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateRecurringTemplateRequest
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	template, err := h.recurringService.CreateTemplate(r.Context(), req, appmw.GetUserFromContext(r).ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(template)
+}
+
+// GetAllTemplates handles listing recurring templates
+func (h *RecurringHandler) GetAllTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.recurringService.GetAllTemplates(r.Context())
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(templates)
+}
+
+// GetTemplate handles getting a recurring template by ID
+func (h *RecurringHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	template, err := h.recurringService.GetTemplate(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(template)
+}
+
+// UpdateTemplate handles updating a recurring template
+func (h *RecurringHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req models.UpdateRecurringTemplateRequest
+
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	template, err := h.recurringService.UpdateTemplate(r.Context(), id, req)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(template)
+}
+
+// DeleteTemplate handles deleting a recurring template
+func (h *RecurringHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := h.recurringService.DeleteTemplate(r.Context(), id); err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.SuccessResponse{Message: "Recurring template deleted successfully"})
+}
+
+// GetRuns handles listing a template's run history
+func (h *RecurringHandler) GetRuns(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	runs, err := h.recurringService.GetRuns(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(runs)
+}
+
+// RunDue handles generating every due occurrence straight away instead of waiting for the scheduler
+func (h *RecurringHandler) RunDue(w http.ResponseWriter, r *http.Request) {
+	summary, err := h.recurringService.RunDue(r.Context(), time.Now())
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
+
+// RetryRun handles generating a failed run's transaction again
+func (h *RecurringHandler) RetryRun(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "runID")
+
+	run, err := h.recurringService.RetryRun(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(run)
+}
@@ -4,19 +4,117 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"storeHouse/database"
+	appmw "storeHouse/middleware"
+	"storeHouse/models"
+	"storeHouse/mpesa"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/jmoiron/sqlx"
 )
 
-// Router sets up the HTTP router with all handlers
-func Router(db *sqlx.DB) *chi.Mux {
+// routeTimeouts gives the routes that read or write much more than most, such as reports,
+// imports and uploads, longer than the default timeouts
+var routeTimeouts = []appmw.RouteTimeout{
+	{Pattern: "GET /api/v1/districts/{id}/reports/*", Timeouts: database.Timeouts{Request: 2 * time.Minute, Query: time.Minute}},
+	{Pattern: "POST /api/v1/setup", Timeouts: database.Timeouts{Request: time.Minute, Query: 30 * time.Second}},
+	{Pattern: "POST /api/v1/budgets/import", Timeouts: database.Timeouts{Request: 2 * time.Minute, Query: 30 * time.Second}},
+	{Pattern: "POST /api/v1/bank-statements", Timeouts: database.Timeouts{Request: 2 * time.Minute, Query: 30 * time.Second}},
+	{Pattern: "POST /api/v1/mobile-money/imports", Timeouts: database.Timeouts{Request: 2 * time.Minute, Query: 30 * time.Second}},
+	{Pattern: "POST /api/v1/recurring-templates/run", Timeouts: database.Timeouts{Request: 2 * time.Minute, Query: 30 * time.Second}},
+	{Pattern: "POST /api/v1/emails/reports/monthly", Timeouts: database.Timeouts{Request: time.Minute, Query: 30 * time.Second}},
+	{Pattern: "POST /api/v1/{entity}/{id}/attachments", Timeouts: database.Timeouts{Request: 5 * time.Minute, Query: 30 * time.Second}},
+}
+
+// Router sets up the HTTP router with all handlers. Setup, sign-in, churches and provider
+// callbacks are served on the shared connection; everything else works in one church.
+func Router(db *sqlx.DB, tenants *database.Tenants) *chi.Mux {
 	router := chi.NewRouter()
 
 	// Add middleware
 	router.Use(middleware.Logger)
 	router.Use(middleware.Recoverer)
+	router.Use(appmw.Timeout(database.DefaultTimeouts(), routeTimeouts...))
+
+	// Initialize handlers
+	churchAPI := newChurchRouters(db, tenants)
+	setupHandler := NewSetupHandler(db, tenants)
+	churchHandler := NewChurchHandler(db, tenants)
+	districtHandler := NewDistrictHandler(db, tenants)
+	userHandler := NewUserHandler(db)
+
+	// API routes
+	router.Route("/api/v1", func(r chi.Router) {
+		// Setup (seeds a new installation; only open until the first user exists)
+		r.Route("/setup", func(r chi.Router) {
+			r.Get("/", setupHandler.GetStatus)
+			r.Post("/", setupHandler.Setup)
+			r.Get("/templates", setupHandler.GetTemplates)
+			r.Get("/templates/{key}", setupHandler.GetTemplate)
+		})
+
+		// Sign-in (before a church is chosen)
+		r.Post("/users/authenticate", userHandler.AuthenticateUser)
+
+		// Churches (the churches a user works in, and who works in each)
+		r.Route("/churches", func(r chi.Router) {
+			r.Use(appmw.AuthMiddleware(db), appmw.RequireAnyRole)
+			r.Get("/", churchHandler.GetMyChurches)
+			r.With(appmw.RequireRole(appmw.RoleAdmin)).Post("/", churchHandler.CreateChurch)
+			r.Get("/{id}", churchHandler.GetChurch)
+			r.Put("/{id}", churchHandler.UpdateChurch)
+			r.Get("/{id}/users", churchHandler.GetChurchUsers)
+			r.Put("/{id}/users", churchHandler.SaveChurchUser)
+			r.Delete("/{id}/users/{userID}", churchHandler.RemoveChurchUser)
+		})
+
+		// Districts (groups of churches, with consolidated reports for their district roles)
+		r.Route("/districts", func(r chi.Router) {
+			r.Use(appmw.AuthMiddleware(db), appmw.RequireAnyRole)
+			r.Get("/", districtHandler.GetMyDistricts)
+			r.With(appmw.RequireRole(appmw.RoleAdmin)).Post("/", districtHandler.CreateDistrict)
+			r.Get("/{id}", districtHandler.GetDistrict)
+			r.Put("/{id}/churches", districtHandler.AddChurch)
+			r.Delete("/{id}/churches/{churchID}", districtHandler.RemoveChurch)
+			r.Get("/{id}/users", districtHandler.GetDistrictUsers)
+			r.Put("/{id}/users", districtHandler.SaveDistrictUser)
+			r.Delete("/{id}/users/{userID}", districtHandler.RemoveDistrictUser)
+			r.Get("/{id}/reports/consolidated", districtHandler.GetConsolidatedReport)
+		})
+
+		// Provider callbacks (M-Pesa finds its church by paybill short code; SMS delivery
+		// reports are matched against every church's messages). The M-Pesa callbacks post
+		// receipts, so they are only mounted when a callback token protects them.
+		if mpesa.LoadConfig().CallbackToken != "" {
+			r.With(churchAPI.MpesaChurch).Post("/mpesa/c2b/validation", churchAPI.ServeAt("/mpesa/c2b/validation"))
+			r.With(churchAPI.MpesaChurch).Post("/mpesa/c2b/confirmation", churchAPI.ServeAt("/mpesa/c2b/confirmation"))
+		} else {
+			log.Println("⚠️  MPESA_CALLBACK_TOKEN is not set; M-Pesa callbacks are disabled")
+		}
+		r.Post("/notifications/sms/delivery-reports", churchAPI.SMSDeliveryReport)
+
+		// Everything else works in the church chosen by the X-Church-ID header
+		r.Group(func(r chi.Router) {
+			r.Use(appmw.AuthMiddleware(db), appmw.ChurchMiddleware(db))
+			r.Mount("/", churchAPI)
+		})
+	})
+
+	// Health check endpoint
+	router.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	})
+
+	return router
+}
+
+// churchRoutes sets up the API of one church on that church's connection
+func churchRoutes(db *sqlx.DB) *chi.Mux {
+	router := chi.NewRouter()
 
 	// Initialize handlers
 	accountHandler := NewAccountHandler(db)
@@ -27,13 +125,31 @@ func Router(db *sqlx.DB) *chi.Mux {
 	transferHandler := NewTransferHandler(db)
 	receiptHandler := NewReceiptHandler(db)
 	membersGroupHandler := NewMembersGroupHandler(db)
+	campaignHandler := NewCampaignHandler(db)
+	pledgeHandler := NewPledgeHandler(db)
+	budgetHandler := NewBudgetHandler(db)
+	bankStatementHandler := NewBankStatementHandler(db)
+	mpesaHandler := NewMpesaHandler(db)
+	mobileMoneyHandler := NewMobileMoneyHandler(db)
+	smsHandler := NewSMSHandler(db)
+	emailHandler := NewEmailHandler(db)
+	voucherHandler := NewVoucherHandler(db)
+	approvalPolicyHandler := NewApprovalPolicyHandler(db)
+	imprestHandler := NewImprestHandler(db)
+	recurringHandler := NewRecurringHandler(db)
+	attachmentHandler := NewAttachmentHandler(db)
+	payeeHandler := NewPayeeHandler(db)
+	chequeHandler := NewChequeHandler(db)
+	collectionHandler := NewCollectionHandler(db)
+	fundHandler := NewFundHandler(db)
 
 	// API routes
-	router.Route("/api/v1", func(r chi.Router) {
+	router.Group(func(r chi.Router) {
 		// Accounts
 		r.Route("/accounts", func(r chi.Router) {
 			r.Get("/", accountHandler.GetAllAccounts)
 			r.Post("/", accountHandler.CreateAccount)
+			r.Get("/tree", accountHandler.GetAccountTree)
 			r.Get("/{id}", accountHandler.GetAccount)
 			r.Put("/{id}", accountHandler.UpdateAccount)
 			r.Delete("/{id}", accountHandler.DeactivateAccount)
@@ -42,7 +158,7 @@ func Router(db *sqlx.DB) *chi.Mux {
 		// Transactions
 		r.Route("/transactions", func(r chi.Router) {
 			r.Get("/", transactionHandler.GetAllTransactions)
-			r.Post("/", transactionHandler.CreateTransaction)
+			r.With(appmw.AuthIfPresent(db)).Post("/", transactionHandler.CreateTransaction)
 			r.Get("/{id}", transactionHandler.GetTransaction)
 			r.Get("/ref/{ref}", transactionHandler.GetTransactionByRef)
 			r.Get("/account/{accountID}", transactionHandler.GetTransactionsByAccount)
@@ -51,6 +167,11 @@ func Router(db *sqlx.DB) *chi.Mux {
 			r.Get("/date-range", transactionHandler.GetTransactionsByDateRange)
 			r.Put("/{id}", transactionHandler.UpdateTransaction)
 			r.Delete("/{id}", transactionHandler.DeleteTransaction)
+			r.Get("/{id}/attachments", attachmentHandler.ListFor(models.AttachmentTransaction))
+			r.With(appmw.AuthIfPresent(db)).Post("/{id}/attachments", attachmentHandler.UploadFor(models.AttachmentTransaction))
+
+			// Payment details can be corrected after posting, so only treasurers and admins set them
+			r.With(appmw.AuthMiddleware(db), appmw.RequireAdminOrTreasurer).Put("/{id}/payment", transactionHandler.UpdatePayment)
 		})
 
 		// Members
@@ -64,6 +185,8 @@ func Router(db *sqlx.DB) *chi.Mux {
 			r.Get("/group/{groupID}", memberHandler.GetMembersByGroup)
 			r.Put("/{id}", memberHandler.UpdateMember)
 			r.Delete("/{id}", memberHandler.DeleteMember)
+			r.Get("/{id}/notifications", smsHandler.GetMemberNotifications)
+			r.Put("/{id}/sms-opt-out", smsHandler.SetMemberOptOut)
 		})
 
 		// Users
@@ -71,15 +194,20 @@ func Router(db *sqlx.DB) *chi.Mux {
 			r.Get("/", userHandler.GetAllUsers)
 			r.Get("/active", userHandler.GetActiveUsers)
 			r.Get("/role/{role}", userHandler.GetUsersByRole)
-			r.Post("/", userHandler.CreateUser)
 			r.Get("/{id}", userHandler.GetUser)
 			r.Get("/username/{username}", userHandler.GetUserByUsername)
 			r.Get("/email/{email}", userHandler.GetUserByEmail)
-			r.Put("/{id}", userHandler.UpdateUser)
-			r.Delete("/{id}", userHandler.DeleteUser)
-			r.Post("/{id}/deactivate", userHandler.DeactivateUser)
-			r.Post("/authenticate", userHandler.AuthenticateUser)
-			r.Post("/{id}/change-password", userHandler.ChangePassword)
+
+			// Users sign in to every church they work in, so only the church's admins manage them;
+			// a user may still change their own password
+			r.Group(func(r chi.Router) {
+				r.Use(appmw.AuthMiddleware(db), appmw.RequireRole(appmw.RoleAdmin))
+				r.Post("/", userHandler.CreateUser)
+				r.Put("/{id}", userHandler.UpdateUser)
+				r.Delete("/{id}", userHandler.DeleteUser)
+				r.Post("/{id}/deactivate", userHandler.DeactivateUser)
+			})
+			r.With(appmw.AuthMiddleware(db), appmw.ResourceOwner).Post("/{id}/change-password", userHandler.ChangePassword)
 		})
 
 		// Expenditures
@@ -90,6 +218,8 @@ func Router(db *sqlx.DB) *chi.Mux {
 			r.Get("/transaction/{transactionID}", expenditureHandler.GetExpendituresByTransaction)
 			r.Put("/{id}", expenditureHandler.UpdateExpenditure)
 			r.Delete("/{id}", expenditureHandler.DeleteExpenditure)
+			r.Get("/{id}/attachments", attachmentHandler.ListFor(models.AttachmentExpenditure))
+			r.With(appmw.AuthIfPresent(db)).Post("/{id}/attachments", attachmentHandler.UploadFor(models.AttachmentExpenditure))
 		})
 
 		// Transfers
@@ -104,6 +234,158 @@ func Router(db *sqlx.DB) *chi.Mux {
 			r.Get("/date-range/{accountID}", transferHandler.GetTotalTransfersByDateRange)
 			r.Put("/{id}", transferHandler.UpdateTransfer)
 			r.Delete("/{id}", transferHandler.DeleteTransfer)
+			r.Get("/{id}/attachments", attachmentHandler.ListFor(models.AttachmentTransfer))
+			r.With(appmw.AuthIfPresent(db)).Post("/{id}/attachments", attachmentHandler.UploadFor(models.AttachmentTransfer))
+		})
+
+		// Payees (contractors, suppliers and staff paid through expenditures)
+		r.Route("/payees", func(r chi.Router) {
+			r.Get("/", payeeHandler.GetAllPayees)
+			r.Get("/search", payeeHandler.SearchPayees)
+			r.Get("/spend", payeeHandler.GetSpendSummary)
+			r.Get("/{id}", payeeHandler.GetPayee)
+			r.Get("/{id}/spend", payeeHandler.GetSpendReport)
+
+			// Payees carry bank and M-Pesa details, so only treasurers and admins maintain them
+			r.Group(func(r chi.Router) {
+				r.Use(appmw.AuthMiddleware(db), appmw.RequireAdminOrTreasurer)
+				r.Post("/", payeeHandler.CreatePayee)
+				r.Put("/{id}", payeeHandler.UpdatePayee)
+				r.Delete("/{id}", payeeHandler.DeletePayee)
+			})
+		})
+
+		// Funds (restricted and designated funds tracked on receipt, expenditure and transfer lines)
+		r.Route("/funds", func(r chi.Router) {
+			r.Get("/", fundHandler.GetAllFunds)
+			r.Get("/balances", fundHandler.GetBalances)
+			r.Get("/{id}", fundHandler.GetFund)
+			r.Get("/{id}/balance", fundHandler.GetBalance)
+
+			r.Group(func(r chi.Router) {
+				r.Use(appmw.AuthMiddleware(db), appmw.RequireAdminOrTreasurer)
+				r.Post("/", fundHandler.CreateFund)
+				r.Put("/{id}", fundHandler.UpdateFund)
+			})
+		})
+
+		// Collection sessions (offering counted by two people before it is posted)
+		r.Route("/collection-sessions", func(r chi.Router) {
+			r.Use(appmw.AuthMiddleware(db), appmw.RequireAnyRole)
+			r.Get("/", collectionHandler.GetSessions)
+			r.Post("/", collectionHandler.CreateSession)
+			r.Get("/{id}", collectionHandler.GetSession)
+			r.Post("/{id}/receipts", collectionHandler.AddReceipt)
+			r.Delete("/{id}/receipts/{transactionID}", collectionHandler.RemoveReceipt)
+			r.Put("/{id}/denominations", collectionHandler.SetDenominations)
+			r.Post("/{id}/sign-off", collectionHandler.SignOff)
+			r.With(appmw.RequireAdminOrTreasurer).Post("/{id}/cancel", collectionHandler.CancelSession)
+		})
+
+		// Cheques (register of issued cheques and outstanding cheque reports)
+		r.Route("/cheques", func(r chi.Router) {
+			r.Get("/", chequeHandler.GetCheques)
+			r.Get("/outstanding", chequeHandler.GetOutstandingCheques)
+			r.Get("/{id}", chequeHandler.GetCheque)
+
+			r.Group(func(r chi.Router) {
+				r.Use(appmw.AuthMiddleware(db), appmw.RequireAdminOrTreasurer)
+				r.Post("/", chequeHandler.IssueCheque)
+				r.Post("/{id}/present", chequeHandler.PresentCheque)
+				r.Post("/{id}/cancel", chequeHandler.CancelCheque)
+			})
+		})
+
+		// Vouchers (maker-checker approval of expenses, withdrawals and transfers)
+		r.Route("/vouchers", func(r chi.Router) {
+			r.Get("/", voucherHandler.GetVouchers)
+			r.Get("/{id}", voucherHandler.GetVoucher)
+
+			r.Group(func(r chi.Router) {
+				r.Use(appmw.AuthMiddleware(db))
+				r.Post("/{id}/submit", voucherHandler.SubmitVoucher)
+			})
+
+			// Signatories approve or reject; the board chair signs off large payments
+			r.Group(func(r chi.Router) {
+				r.Use(appmw.AuthMiddleware(db), appmw.RequireRole(appmw.RoleAdmin, appmw.RoleTreasurer, appmw.RoleBoardChair))
+				r.Post("/{id}/approve", voucherHandler.ApproveVoucher)
+				r.Post("/{id}/reject", voucherHandler.RejectVoucher)
+			})
+
+			// Posting is reserved for treasurers and admins
+			r.Group(func(r chi.Router) {
+				r.Use(appmw.AuthMiddleware(db), appmw.RequireAdminOrTreasurer)
+				r.Post("/{id}/post", voucherHandler.PostVoucher)
+			})
+		})
+
+		// Approval policies
+		r.Route("/approval-policies", func(r chi.Router) {
+			r.Get("/", approvalPolicyHandler.GetAllPolicies)
+			r.Post("/evaluate", approvalPolicyHandler.EvaluatePolicies)
+			r.Get("/{id}", approvalPolicyHandler.GetPolicy)
+
+			// Only admins change the rules
+			r.Group(func(r chi.Router) {
+				r.Use(appmw.AuthMiddleware(db), appmw.RequireAdmin)
+				r.Post("/", approvalPolicyHandler.CreatePolicy)
+				r.Put("/{id}", approvalPolicyHandler.UpdatePolicy)
+				r.Delete("/{id}", approvalPolicyHandler.DeletePolicy)
+			})
+		})
+
+		// Petty cash imprests
+		r.Route("/imprests", func(r chi.Router) {
+			r.Get("/", imprestHandler.GetAllImprests)
+			r.Get("/{id}", imprestHandler.GetImprest)
+			r.Get("/{id}/vouchers", imprestHandler.GetVouchers)
+			r.With(appmw.AuthIfPresent(db)).Post("/{id}/vouchers", imprestHandler.CreateVoucher)
+			r.Delete("/vouchers/{voucherID}", imprestHandler.DeleteVoucher)
+			r.Get("/{id}/replenishments", imprestHandler.GetReplenishments)
+			r.Get("/replenishments/{replenishmentID}", imprestHandler.GetReplenishment)
+
+			r.Group(func(r chi.Router) {
+				r.Use(appmw.AuthMiddleware(db))
+				r.Post("/{id}/replenishments", imprestHandler.RequestReplenishment)
+			})
+
+			// Setting floats and posting top-ups are reserved for treasurers and admins
+			r.Group(func(r chi.Router) {
+				r.Use(appmw.AuthMiddleware(db), appmw.RequireAdminOrTreasurer)
+				r.Post("/", imprestHandler.CreateImprest)
+				r.Put("/{id}", imprestHandler.UpdateImprest)
+				r.Post("/replenishments/{replenishmentID}/approve", imprestHandler.ApproveReplenishment)
+				r.Post("/replenishments/{replenishmentID}/reject", imprestHandler.RejectReplenishment)
+			})
+		})
+
+		// Attachments (supporting documents for transactions, expenditures and transfers)
+		r.Route("/attachments", func(r chi.Router) {
+			r.Get("/{id}", attachmentHandler.GetAttachment)
+			r.Get("/{id}/download", attachmentHandler.DownloadAttachment)
+
+			r.Group(func(r chi.Router) {
+				r.Use(appmw.AuthMiddleware(db), appmw.RequireAdminOrTreasurer)
+				r.Delete("/{id}", attachmentHandler.DeleteAttachment)
+			})
+		})
+
+		// Recurring transaction templates
+		r.Route("/recurring-templates", func(r chi.Router) {
+			r.Get("/", recurringHandler.GetAllTemplates)
+			r.Get("/{id}", recurringHandler.GetTemplate)
+			r.Get("/{id}/runs", recurringHandler.GetRuns)
+
+			// Standing entries are set up and run by treasurers and admins
+			r.Group(func(r chi.Router) {
+				r.Use(appmw.AuthMiddleware(db), appmw.RequireAdminOrTreasurer)
+				r.Post("/", recurringHandler.CreateTemplate)
+				r.Put("/{id}", recurringHandler.UpdateTemplate)
+				r.Delete("/{id}", recurringHandler.DeleteTemplate)
+				r.Post("/run", recurringHandler.RunDue)
+				r.Post("/runs/{runID}/retry", recurringHandler.RetryRun)
+			})
 		})
 
 		// Receipts
@@ -131,21 +413,101 @@ func Router(db *sqlx.DB) *chi.Mux {
 			r.Put("/{id}", membersGroupHandler.UpdateGroup)
 			r.Delete("/{id}", membersGroupHandler.DeleteGroup)
 		})
-	})
 
-	// Health check endpoint
-	router.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+		// Campaigns
+		r.Route("/campaigns", func(r chi.Router) {
+			r.Get("/", campaignHandler.GetAllCampaigns)
+			r.Post("/", campaignHandler.CreateCampaign)
+			r.Get("/{id}", campaignHandler.GetCampaign)
+			r.Get("/{id}/pledges", pledgeHandler.GetPledgesByCampaign)
+			r.Get("/{id}/fulfilment/members", pledgeHandler.GetFulfilmentByMember)
+			r.Get("/{id}/fulfilment/groups", pledgeHandler.GetFulfilmentByGroup)
+			r.Put("/{id}", campaignHandler.UpdateCampaign)
+		})
+
+		// Pledges
+		r.Route("/pledges", func(r chi.Router) {
+			r.Post("/", pledgeHandler.CreatePledge)
+			r.Get("/{id}", pledgeHandler.GetPledge)
+			r.Get("/{id}/payments", pledgeHandler.GetPledgePayments)
+			r.Get("/member/{memberID}", pledgeHandler.GetPledgesByMember)
+			r.Put("/{id}", pledgeHandler.UpdatePledge)
+			r.Delete("/{id}", pledgeHandler.DeletePledge)
+		})
+
+		// Budgets
+		r.Route("/budgets", func(r chi.Router) {
+			r.Get("/", budgetHandler.GetBudgetsByYear)
+			r.Post("/", budgetHandler.SetBudget)
+			r.Post("/import", budgetHandler.ImportBudgets)
+			r.Get("/variance", budgetHandler.GetVarianceReport)
+			r.Get("/account/{accountID}", budgetHandler.GetAccountBudget)
+			r.Delete("/account/{accountID}", budgetHandler.DeleteAccountBudget)
+			r.Put("/{id}", budgetHandler.UpdateBudget)
+		})
+
+		// Bank statements and reconciliation
+		r.Route("/bank-statements", func(r chi.Router) {
+			r.Post("/", bankStatementHandler.ImportStatement)
+			r.Get("/account/{accountID}", bankStatementHandler.GetStatementsByAccount)
+			r.Get("/reconciliation/{accountID}", bankStatementHandler.GetReconciliation)
+			r.Post("/lines/{lineID}/match", bankStatementHandler.MatchLine)
+			r.Delete("/lines/{lineID}/match", bankStatementHandler.UnmatchLine)
+			r.Get("/{id}", bankStatementHandler.GetStatement)
+			r.Post("/{id}/auto-match", bankStatementHandler.AutoMatch)
+			r.Delete("/{id}", bankStatementHandler.DeleteStatement)
+		})
+
+		// M-Pesa Paybill
+		r.Route("/mpesa", func(r chi.Router) {
+			r.Post("/c2b/validation", mpesaHandler.ValidateC2B)
+			r.Post("/c2b/confirmation", mpesaHandler.ConfirmC2B)
+			r.Get("/transactions", mpesaHandler.GetMpesaTransactions)
+			r.Get("/transactions/{id}", mpesaHandler.GetMpesaTransaction)
+			r.Post("/transactions/{id}/retry", mpesaHandler.RetryMpesaTransaction)
+		})
+
+		// Mobile money statement imports
+		r.Route("/mobile-money", func(r chi.Router) {
+			r.Get("/imports", mobileMoneyHandler.GetImports)
+			r.Post("/imports", mobileMoneyHandler.ImportStatement)
+			r.Get("/imports/{id}", mobileMoneyHandler.GetImport)
+			r.Get("/lines", mobileMoneyHandler.GetLines)
+			r.Put("/lines/{id}", mobileMoneyHandler.UpdateLine)
+
+			// Posting to the ledger is reserved for treasurers and admins
+			r.Group(func(r chi.Router) {
+				r.Use(appmw.AuthMiddleware(db), appmw.RequireAdminOrTreasurer)
+				r.Post("/lines/{id}/confirm", mobileMoneyHandler.ConfirmLine)
+				r.Post("/lines/{id}/reject", mobileMoneyHandler.RejectLine)
+			})
+		})
+
+		// SMS notifications
+		r.Route("/notifications/sms", func(r chi.Router) {
+			r.Get("/", smsHandler.GetNotifications)
+			r.Get("/{id}", smsHandler.GetNotification)
+			r.Post("/{id}/retry", smsHandler.RetryNotification)
+		})
+
+		// Email outbox
+		r.Route("/emails", func(r chi.Router) {
+			r.Get("/", emailHandler.GetEmails)
+			r.Post("/receipts/{transactionID}", emailHandler.SendReceipt)
+			r.Post("/members/{id}/statement", emailHandler.SendStatement)
+			r.Post("/reports/monthly", emailHandler.SendMonthlyReport)
+			r.Get("/{id}", emailHandler.GetEmail)
+			r.Post("/{id}/retry", emailHandler.RetryEmail)
+			r.Post("/{id}/bounce", emailHandler.MarkBounced)
+		})
 	})
 
 	return router
 }
 
 // StartServer starts the HTTP server
-func StartServer(db *sqlx.DB) {
-	router := Router(db)
+func StartServer(db *sqlx.DB, tenants *database.Tenants) {
+	router := Router(db, tenants)
 
 	port := ":8080"
 	log.Printf("🚀 Server starting on port %s", port)
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"storeHouse/database"
+	appmw "storeHouse/middleware"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"storeHouse/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+type SetupHandler struct {
+	setupService *services.SetupService
+	tenants      *database.Tenants
+}
+
+func NewSetupHandler(db *sqlx.DB, tenants *database.Tenants) *SetupHandler {
+	return &SetupHandler{
+		setupService: services.NewSetupService(repository.NewPostgres(db)),
+		tenants:      tenants,
+	}
+}
+
+// GetStatus handles reporting whether setup has been completed
+func (h *SetupHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.setupService.GetStatus(r.Context())
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// GetTemplates handles listing the chart of accounts templates
+func (h *SetupHandler) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.setupService.GetTemplates())
+}
+
+// GetTemplate handles getting one chart of accounts template
+func (h *SetupHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+
+	template, err := h.setupService.GetTemplate(key)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(template)
+}
+
+// Setup handles seeding a new installation
+func (h *SetupHandler) Setup(w http.ResponseWriter, r *http.Request) {
+	var req models.SetupRequest
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	result, err := h.setupService.Setup(r.Context(), req)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	// Start the new church's background jobs
+	if err := h.tenants.Activate(result.Church.ID); err != nil {
+		log.Printf("⚠️  Church %s: %v", result.Church.ID, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	appmw "storeHouse/middleware"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"storeHouse/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+type SMSHandler struct {
+	smsService *services.SMSService
+}
+
+func NewSMSHandler(db *sqlx.DB) *SMSHandler {
+	return &SMSHandler{
+		smsService: services.NewSMSService(repository.NewPostgres(db)),
+	}
+}
+
+// GetNotifications handles listing SMS notifications, optionally by status
+func (h *SMSHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+
+	list, err := h.smsService.GetNotifications(r.Context(), status)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// GetNotification handles getting an SMS notification by ID
+func (h *SMSHandler) GetNotification(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	notification, err := h.smsService.GetNotification(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(notification)
+}
+
+// RetryNotification handles re-sending a failed SMS
+func (h *SMSHandler) RetryNotification(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	notification, err := h.smsService.RetryNotification(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(notification)
+}
+
+// GetMemberNotifications handles listing the messages sent to a member
+func (h *SMSHandler) GetMemberNotifications(w http.ResponseWriter, r *http.Request) {
+	memberID := chi.URLParam(r, "id")
+
+	list, err := h.smsService.GetMemberNotifications(r.Context(), memberID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// SetMemberOptOut handles opting a member in or out of SMS notifications
+func (h *SMSHandler) SetMemberOptOut(w http.ResponseWriter, r *http.Request) {
+	memberID := chi.URLParam(r, "id")
+	var req models.SetSMSOptOutRequest
+
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	member, err := h.smsService.SetMemberOptOut(r.Context(), memberID, req)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(member)
+}
@@ -3,7 +3,9 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	appmw "storeHouse/middleware"
 	"storeHouse/models"
+	"storeHouse/repository"
 	"storeHouse/services"
 	"time"
 
@@ -17,26 +19,22 @@ type TransactionHandler struct {
 
 func NewTransactionHandler(db *sqlx.DB) *TransactionHandler {
 	return &TransactionHandler{
-		transactionService: services.NewTransactionService(db),
+		transactionService: services.NewTransactionService(repository.NewPostgres(db)),
 	}
 }
 
 // CreateTransaction handles transaction creation
 func (h *TransactionHandler) CreateTransaction(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateTransactionRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if !appmw.DecodeJSON(w, r, &req) {
 		return
 	}
 
-	// For now, using a default user ID - in real app, get from authentication
-	createdBy := "system"
+	createdBy := appmw.GetUserFromContext(r).ID
 
-	transaction, err := h.transactionService.CreateTransaction(req, createdBy)
+	transaction, err := h.transactionService.CreateTransaction(r.Context(), req, createdBy)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -49,11 +47,9 @@ func (h *TransactionHandler) CreateTransaction(w http.ResponseWriter, r *http.Re
 func (h *TransactionHandler) GetTransaction(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	transaction, err := h.transactionService.GetTransaction(id)
+	transaction, err := h.transactionService.GetTransaction(r.Context(), id)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -65,11 +61,9 @@ func (h *TransactionHandler) GetTransaction(w http.ResponseWriter, r *http.Reque
 func (h *TransactionHandler) GetTransactionByRef(w http.ResponseWriter, r *http.Request) {
 	ref := chi.URLParam(r, "ref")
 
-	transaction, err := h.transactionService.GetTransactionByRef(ref)
+	transaction, err := h.transactionService.GetTransactionByRef(r.Context(), ref)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -79,11 +73,9 @@ func (h *TransactionHandler) GetTransactionByRef(w http.ResponseWriter, r *http.
 
 // GetAllTransactions handles getting all transactions
 func (h *TransactionHandler) GetAllTransactions(w http.ResponseWriter, r *http.Request) {
-	transactions, err := h.transactionService.GetAllTransactions()
+	transactions, err := h.transactionService.GetAllTransactions(r.Context())
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -96,20 +88,32 @@ func (h *TransactionHandler) UpdateTransaction(w http.ResponseWriter, r *http.Re
 	id := chi.URLParam(r, "id")
 	var req models.UpdateTransactionRequest
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if !appmw.DecodeJSON(w, r, &req) {
 		return
 	}
 
-	transaction, err := h.transactionService.UpdateTransaction(id, req)
+	transaction, err := h.transactionService.UpdateTransaction(r.Context(), id, req)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		if err.Error() == "transaction not found" {
-			w.WriteHeader(http.StatusNotFound)
-		} else {
-			w.WriteHeader(http.StatusBadRequest)
-		}
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transaction)
+}
+
+// UpdatePayment handles setting how a transaction was paid, whatever its status
+func (h *TransactionHandler) UpdatePayment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req models.UpdateTransactionPaymentRequest
+
+	if !appmw.DecodeJSON(w, r, &req) {
+		return
+	}
+
+	transaction, err := h.transactionService.UpdatePayment(r.Context(), id, req)
+	if err != nil {
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -121,11 +125,9 @@ func (h *TransactionHandler) UpdateTransaction(w http.ResponseWriter, r *http.Re
 func (h *TransactionHandler) DeleteTransaction(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	err := h.transactionService.DeleteTransaction(id)
+	err := h.transactionService.DeleteTransaction(r.Context(), id)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -137,11 +139,9 @@ func (h *TransactionHandler) DeleteTransaction(w http.ResponseWriter, r *http.Re
 func (h *TransactionHandler) GetTransactionsByAccount(w http.ResponseWriter, r *http.Request) {
 	accountID := chi.URLParam(r, "accountID")
 
-	transactions, err := h.transactionService.GetTransactionsByAccount(accountID)
+	transactions, err := h.transactionService.GetTransactionsByAccount(r.Context(), accountID)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -153,11 +153,9 @@ func (h *TransactionHandler) GetTransactionsByAccount(w http.ResponseWriter, r *
 func (h *TransactionHandler) GetTransactionsByMember(w http.ResponseWriter, r *http.Request) {
 	memberID := chi.URLParam(r, "memberID")
 
-	transactions, err := h.transactionService.GetTransactionsByMember(memberID)
+	transactions, err := h.transactionService.GetTransactionsByMember(r.Context(), memberID)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -169,11 +167,9 @@ func (h *TransactionHandler) GetTransactionsByMember(w http.ResponseWriter, r *h
 func (h *TransactionHandler) GetTransactionsByType(w http.ResponseWriter, r *http.Request) {
 	transactionType := chi.URLParam(r, "type")
 
-	transactions, err := h.transactionService.GetTransactionsByType(transactionType)
+	transactions, err := h.transactionService.GetTransactionsByType(r.Context(), transactionType)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -187,33 +183,25 @@ func (h *TransactionHandler) GetTransactionsByDateRange(w http.ResponseWriter, r
 	endDateStr := r.URL.Query().Get("end_date")
 
 	if startDateStr == "" || endDateStr == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: "start_date and end_date query parameters are required"})
+		appmw.WriteError(w, models.Invalid("start_date and end_date query parameters are required"))
 		return
 	}
 
 	startDate, err := time.Parse(time.RFC3339, startDateStr)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: "invalid start_date format, use RFC3339"})
+		appmw.WriteError(w, models.Invalid("invalid start_date format, use RFC3339"))
 		return
 	}
 
 	endDate, err := time.Parse(time.RFC3339, endDateStr)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: "invalid end_date format, use RFC3339"})
+		appmw.WriteError(w, models.Invalid("invalid end_date format, use RFC3339"))
 		return
 	}
 
-	transactions, err := h.transactionService.GetTransactionsByDateRange(startDate, endDate)
+	transactions, err := h.transactionService.GetTransactionsByDateRange(r.Context(), startDate, endDate)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
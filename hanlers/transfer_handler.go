@@ -3,7 +3,9 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	appmw "storeHouse/middleware"
 	"storeHouse/models"
+	"storeHouse/repository"
 	"storeHouse/services"
 	"time"
 
@@ -17,23 +19,20 @@ type TransferHandler struct {
 
 func NewTransferHandler(db *sqlx.DB) *TransferHandler {
 	return &TransferHandler{
-		transferService: services.NewTransferService(db),
+		transferService: services.NewTransferService(repository.NewPostgres(db)),
 	}
 }
 
 // CreateTransfer handles transfer creation
 func (h *TransferHandler) CreateTransfer(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateTransferRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if !appmw.DecodeJSON(w, r, &req) {
 		return
 	}
 
-	transfer, err := h.transferService.CreateTransfer(req)
+	transfer, err := h.transferService.CreateTransfer(r.Context(), req)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -46,11 +45,9 @@ func (h *TransferHandler) CreateTransfer(w http.ResponseWriter, r *http.Request)
 func (h *TransferHandler) GetTransfer(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	transfer, err := h.transferService.GetTransfer(id)
+	transfer, err := h.transferService.GetTransfer(r.Context(), id)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -60,11 +57,9 @@ func (h *TransferHandler) GetTransfer(w http.ResponseWriter, r *http.Request) {
 
 // GetAllTransfers handles getting all transfers
 func (h *TransferHandler) GetAllTransfers(w http.ResponseWriter, r *http.Request) {
-	transfers, err := h.transferService.GetAllTransfers()
+	transfers, err := h.transferService.GetAllTransfers(r.Context())
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -76,11 +71,9 @@ func (h *TransferHandler) GetAllTransfers(w http.ResponseWriter, r *http.Request
 func (h *TransferHandler) GetTransfersByTransaction(w http.ResponseWriter, r *http.Request) {
 	transactionID := chi.URLParam(r, "transactionID")
 
-	transfers, err := h.transferService.GetTransfersByTransaction(transactionID)
+	transfers, err := h.transferService.GetTransfersByTransaction(r.Context(), transactionID)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -92,11 +85,9 @@ func (h *TransferHandler) GetTransfersByTransaction(w http.ResponseWriter, r *ht
 func (h *TransferHandler) GetTransfersByCreditAccount(w http.ResponseWriter, r *http.Request) {
 	accountID := chi.URLParam(r, "accountID")
 
-	transfers, err := h.transferService.GetTransfersByCreditAccount(accountID)
+	transfers, err := h.transferService.GetTransfersByCreditAccount(r.Context(), accountID)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -108,11 +99,9 @@ func (h *TransferHandler) GetTransfersByCreditAccount(w http.ResponseWriter, r *
 func (h *TransferHandler) GetTotalTransfersByCreditAccount(w http.ResponseWriter, r *http.Request) {
 	accountID := chi.URLParam(r, "accountID")
 
-	total, err := h.transferService.GetTotalTransfersByCreditAccount(accountID)
+	total, err := h.transferService.GetTotalTransfersByCreditAccount(r.Context(), accountID)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -126,33 +115,25 @@ func (h *TransferHandler) GetTransfersByDateRange(w http.ResponseWriter, r *http
 	endDateStr := r.URL.Query().Get("end_date")
 
 	if startDateStr == "" || endDateStr == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: "start_date and end_date query parameters are required"})
+		appmw.WriteError(w, models.Invalid("start_date and end_date query parameters are required"))
 		return
 	}
 
 	startDate, err := time.Parse(time.RFC3339, startDateStr)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: "invalid start_date format, use RFC3339"})
+		appmw.WriteError(w, models.Invalid("invalid start_date format, use RFC3339"))
 		return
 	}
 
 	endDate, err := time.Parse(time.RFC3339, endDateStr)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: "invalid end_date format, use RFC3339"})
+		appmw.WriteError(w, models.Invalid("invalid end_date format, use RFC3339"))
 		return
 	}
 
-	transfers, err := h.transferService.GetTransfersByDateRange(startDate, endDate)
+	transfers, err := h.transferService.GetTransfersByDateRange(r.Context(), startDate, endDate)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -167,33 +148,25 @@ func (h *TransferHandler) GetTotalTransfersByDateRange(w http.ResponseWriter, r
 	endDateStr := r.URL.Query().Get("end_date")
 
 	if startDateStr == "" || endDateStr == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: "start_date and end_date query parameters are required"})
+		appmw.WriteError(w, models.Invalid("start_date and end_date query parameters are required"))
 		return
 	}
 
 	startDate, err := time.Parse(time.RFC3339, startDateStr)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: "invalid start_date format, use RFC3339"})
+		appmw.WriteError(w, models.Invalid("invalid start_date format, use RFC3339"))
 		return
 	}
 
 	endDate, err := time.Parse(time.RFC3339, endDateStr)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: "invalid end_date format, use RFC3339"})
+		appmw.WriteError(w, models.Invalid("invalid end_date format, use RFC3339"))
 		return
 	}
 
-	total, err := h.transferService.GetTotalTransfersByDateRange(accountID, startDate, endDate)
+	total, err := h.transferService.GetTotalTransfersByDateRange(r.Context(), accountID, startDate, endDate)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -206,20 +179,13 @@ func (h *TransferHandler) UpdateTransfer(w http.ResponseWriter, r *http.Request)
 	id := chi.URLParam(r, "id")
 	var req models.UpdateTransferRequest
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if !appmw.DecodeJSON(w, r, &req) {
 		return
 	}
 
-	transfer, err := h.transferService.UpdateTransfer(id, req)
+	transfer, err := h.transferService.UpdateTransfer(r.Context(), id, req)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		if err.Error() == "transfer not found" {
-			w.WriteHeader(http.StatusNotFound)
-		} else {
-			w.WriteHeader(http.StatusBadRequest)
-		}
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -231,11 +197,9 @@ func (h *TransferHandler) UpdateTransfer(w http.ResponseWriter, r *http.Request)
 func (h *TransferHandler) DeleteTransfer(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	err := h.transferService.DeleteTransfer(id)
+	err := h.transferService.DeleteTransfer(r.Context(), id)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+		appmw.WriteError(w, err)
 		return
 	}
 
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	appmw "storeHouse/middleware"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"storeHouse/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+type VoucherHandler struct {
+	voucherService *services.VoucherService
+}
+
+func NewVoucherHandler(db *sqlx.DB) *VoucherHandler {
+	return &VoucherHandler{
+		voucherService: services.NewVoucherService(repository.NewPostgres(db)),
+	}
+}
+
+// GetVouchers handles listing vouchers, optionally by status
+func (h *VoucherHandler) GetVouchers(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+
+	vouchers, err := h.voucherService.GetVouchers(r.Context(), status)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(vouchers)
+}
+
+// GetVoucher handles getting a voucher with its approval trail
+func (h *VoucherHandler) GetVoucher(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	voucher, err := h.voucherService.GetVoucher(r.Context(), id)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(voucher)
+}
+
+// SubmitVoucher handles sending a draft voucher for approval
+func (h *VoucherHandler) SubmitVoucher(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	voucher, err := h.voucherService.SubmitVoucher(r.Context(), id, appmw.GetUserFromContext(r).ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(voucher)
+}
+
+// ApproveVoucher handles an approver signing off a submitted voucher
+func (h *VoucherHandler) ApproveVoucher(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req, ok := decodeVoucherReview(w, r)
+	if !ok {
+		return
+	}
+
+	user := appmw.GetUserFromContext(r)
+	voucher, err := h.voucherService.ApproveVoucher(r.Context(), id, user.ID, user.Role, req)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(voucher)
+}
+
+// RejectVoucher handles an approver rejecting a submitted voucher
+func (h *VoucherHandler) RejectVoucher(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req, ok := decodeVoucherReview(w, r)
+	if !ok {
+		return
+	}
+
+	user := appmw.GetUserFromContext(r)
+	voucher, err := h.voucherService.RejectVoucher(r.Context(), id, user.ID, user.Role, req)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(voucher)
+}
+
+// PostVoucher handles posting an approved voucher to the books
+func (h *VoucherHandler) PostVoucher(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	voucher, err := h.voucherService.PostVoucher(r.Context(), id, appmw.GetUserFromContext(r).ID)
+	if err != nil {
+		appmw.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(voucher)
+}
+
+// decodeVoucherReview reads the optional review comment
+func decodeVoucherReview(w http.ResponseWriter, r *http.Request) (models.ReviewVoucherRequest, bool) {
+	var req models.ReviewVoucherRequest
+	if r.ContentLength == 0 {
+		return req, true
+	}
+	if !appmw.DecodeJSON(w, r, &req) {
+		return req, false
+	}
+	return req, true
+}
@@ -0,0 +1,82 @@
+package mailer
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// TLS modes for the SMTP connection
+const (
+	// TLSNone sends in plain text, for local stand-ins such as Mailpit or MailHog
+	TLSNone = "none"
+	// TLSStartTLS upgrades a plain connection, usually on port 587
+	TLSStartTLS = "starttls"
+	// TLSImplicit connects over TLS from the start, usually on port 465
+	TLSImplicit = "tls"
+)
+
+// DefaultMaxAttempts is how many times an email is tried before it is marked failed
+const DefaultMaxAttempts = 5
+
+// Config holds the SMTP settings
+type Config struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// From is the sender address, e.g. treasurer@church.org
+	From string
+	// FromName is the display name shown with the sender address
+	FromName string
+	// TLS is one of none, starttls or tls
+	TLS string
+	// MaxAttempts is how many times an email is tried before it is marked failed
+	MaxAttempts int
+}
+
+// LoadConfig reads the SMTP settings from environment variables. With only SMTP_HOST set
+// to localhost it talks to a local stand-in on port 1025 without TLS.
+func LoadConfig() Config {
+	cfg := Config{
+		Host:        strings.TrimSpace(os.Getenv("SMTP_HOST")),
+		Port:        587,
+		Username:    strings.TrimSpace(os.Getenv("SMTP_USERNAME")),
+		Password:    os.Getenv("SMTP_PASSWORD"),
+		From:        strings.TrimSpace(os.Getenv("SMTP_FROM")),
+		FromName:    strings.TrimSpace(os.Getenv("SMTP_FROM_NAME")),
+		TLS:         strings.ToLower(strings.TrimSpace(os.Getenv("SMTP_TLS"))),
+		MaxAttempts: DefaultMaxAttempts,
+	}
+
+	local := cfg.Host == "localhost" || cfg.Host == "127.0.0.1"
+	if local {
+		cfg.Port = 1025
+	}
+	if port, err := strconv.Atoi(os.Getenv("SMTP_PORT")); err == nil && port > 0 {
+		cfg.Port = port
+	}
+	if cfg.TLS == "" {
+		switch {
+		case local:
+			cfg.TLS = TLSNone
+		case cfg.Port == 465:
+			cfg.TLS = TLSImplicit
+		default:
+			cfg.TLS = TLSStartTLS
+		}
+	}
+	if attempts, err := strconv.Atoi(os.Getenv("MAIL_MAX_ATTEMPTS")); err == nil && attempts > 0 {
+		cfg.MaxAttempts = attempts
+	}
+	if cfg.From == "" {
+		cfg.From = "storehouse@localhost"
+	}
+
+	return cfg
+}
+
+// Enabled reports whether an SMTP server is configured
+func (c Config) Enabled() bool {
+	return c.Host != ""
+}
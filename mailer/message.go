@@ -0,0 +1,141 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Attachment is a file sent with an email
+type Attachment struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
+
+// Message is an email ready to send
+type Message struct {
+	From        string
+	FromName    string
+	To          string
+	Subject     string
+	Text        string
+	HTML        string
+	Attachments []Attachment
+}
+
+// Bytes renders the message as RFC 5322 / MIME: a text and HTML alternative, wrapped in a
+// mixed part when there are attachments
+func (m Message) Bytes() ([]byte, error) {
+	from := (&mail.Address{Name: m.FromName, Address: m.From}).String()
+	to, err := mail.ParseAddress(m.To)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient %q", m.To)
+	}
+
+	var buf bytes.Buffer
+	writeHeader(&buf, "From", from)
+	writeHeader(&buf, "To", to.String())
+	writeHeader(&buf, "Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	writeHeader(&buf, "Date", time.Now().Format(time.RFC1123Z))
+	writeHeader(&buf, "Message-ID", fmt.Sprintf("<%s@%s>", randomToken(), domainOf(m.From)))
+	writeHeader(&buf, "MIME-Version", "1.0")
+
+	body := alternativePart(m.Text, m.HTML)
+	if len(m.Attachments) == 0 {
+		buf.Write(body)
+		return buf.Bytes(), nil
+	}
+
+	boundary := "mixed-" + randomToken()
+	writeHeader(&buf, "Content-Type", fmt.Sprintf("multipart/mixed; boundary=%q", boundary))
+	buf.WriteString("\r\n")
+
+	fmt.Fprintf(&buf, "--%s\r\n", boundary)
+	buf.Write(body)
+	for _, a := range m.Attachments {
+		contentType := a.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		fmt.Fprintf(&buf, "\r\n--%s\r\n", boundary)
+		writeHeader(&buf, "Content-Type", fmt.Sprintf("%s; name=%q", contentType, a.FileName))
+		writeHeader(&buf, "Content-Transfer-Encoding", "base64")
+		writeHeader(&buf, "Content-Disposition", fmt.Sprintf("attachment; filename=%q", a.FileName))
+		buf.WriteString("\r\n")
+		writeBase64(&buf, a.Data)
+	}
+	fmt.Fprintf(&buf, "\r\n--%s--\r\n", boundary)
+
+	return buf.Bytes(), nil
+}
+
+// alternativePart renders the text body, with an HTML alternative when there is one,
+// including its own Content-Type header
+func alternativePart(text, html string) []byte {
+	var buf bytes.Buffer
+	if html == "" {
+		writeHeader(&buf, "Content-Type", "text/plain; charset=utf-8")
+		writeHeader(&buf, "Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		writeQuotedPrintable(&buf, text)
+		return buf.Bytes()
+	}
+
+	boundary := "alt-" + randomToken()
+	writeHeader(&buf, "Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", boundary))
+	buf.WriteString("\r\n")
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", html},
+	} {
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		writeHeader(&buf, "Content-Type", part.contentType)
+		writeHeader(&buf, "Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		writeQuotedPrintable(&buf, part.body)
+		buf.WriteString("\r\n")
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+	return buf.Bytes()
+}
+
+func writeHeader(buf *bytes.Buffer, name, value string) {
+	fmt.Fprintf(buf, "%s: %s\r\n", name, value)
+}
+
+func writeQuotedPrintable(buf *bytes.Buffer, s string) {
+	w := quotedprintable.NewWriter(buf)
+	w.Write([]byte(strings.ReplaceAll(s, "\n", "\r\n")))
+	w.Close()
+}
+
+// writeBase64 writes data base64 encoded in 76 character lines
+func writeBase64(buf *bytes.Buffer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+}
+
+func randomToken() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func domainOf(address string) string {
+	if i := strings.LastIndex(address, "@"); i >= 0 {
+		return address[i+1:]
+	}
+	return "localhost"
+}
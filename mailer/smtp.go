@@ -0,0 +1,107 @@
+package mailer
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+)
+
+// Mailer sends email messages
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// SMTPMailer sends email through an SMTP server
+type SMTPMailer struct {
+	Config  Config
+	Timeout time.Duration
+}
+
+// NewSMTPMailer creates an SMTP mailer
+func NewSMTPMailer(cfg Config) *SMTPMailer {
+	return &SMTPMailer{Config: cfg, Timeout: 30 * time.Second}
+}
+
+// Send delivers a message to the SMTP server
+func (m *SMTPMailer) Send(msg Message) error {
+	if msg.From == "" {
+		msg.From = m.Config.From
+	}
+	if msg.FromName == "" {
+		msg.FromName = m.Config.FromName
+	}
+
+	data, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	client, err := m.dial()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if m.Config.TLS == TLSStartTLS {
+		if err := client.StartTLS(&tls.Config{ServerName: m.Config.Host}); err != nil {
+			return err
+		}
+	}
+	if m.Config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.Config.Username, m.Config.Password, m.Config.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(msg.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func (m *SMTPMailer) dial() (*smtp.Client, error) {
+	addr := net.JoinHostPort(m.Config.Host, strconv.Itoa(m.Config.Port))
+	dialer := &net.Dialer{Timeout: m.Timeout}
+
+	var conn net.Conn
+	var err error
+	if m.Config.TLS == TLSImplicit {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: m.Config.Host})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot reach SMTP server %s: %v", addr, err)
+	}
+
+	return smtp.NewClient(conn, m.Config.Host)
+}
+
+// IsPermanent reports whether the server rejected the message for good (a 5xx reply, such
+// as an unknown mailbox), meaning it bounced and retrying will not help
+func IsPermanent(err error) bool {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		return protoErr.Code >= 500 && protoErr.Code < 600
+	}
+	return false
+}
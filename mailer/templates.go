@@ -0,0 +1,193 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"storeHouse/notifications"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// Template names
+const (
+	TemplateReceipt       = "receipt"
+	TemplateStatement     = "statement"
+	TemplateMonthlyReport = "monthly_report"
+)
+
+// Template is the subject, plain text and HTML of an email. Each is a Go template.
+type Template struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Rendered is a template filled in with data
+type Rendered struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// AmountLine is an amount against a named account
+type AmountLine struct {
+	Date    *time.Time
+	Ref     string
+	Account string
+	Amount  float64
+}
+
+// ReceiptEmail is the data for the receipt template
+type ReceiptEmail struct {
+	MemberName string
+	Reference  string
+	Date       time.Time
+	Total      float64
+	Lines      []AmountLine
+	ChurchName string
+}
+
+// StatementEmail is the data for the statement template
+type StatementEmail struct {
+	MemberName string
+	StartDate  time.Time
+	EndDate    time.Time
+	Total      float64
+	Lines      []AmountLine
+	ChurchName string
+}
+
+// ReportLine is an account's budget and actual for the monthly report
+type ReportLine struct {
+	Account  string
+	Budget   float64
+	Actual   float64
+	Variance float64
+}
+
+// MonthlyReportEmail is the data for the monthly treasurer report template
+type MonthlyReportEmail struct {
+	Period        string
+	Income        []ReportLine
+	Expenses      []ReportLine
+	TotalIncome   float64
+	TotalExpenses float64
+	Net           float64
+	ChurchName    string
+}
+
+// Templates are the built-in email templates
+var Templates = map[string]Template{
+	TemplateReceipt: {
+		Subject: `Receipt {{.Reference}} - {{.ChurchName}}`,
+		Text: `Dear {{.MemberName}},
+
+Thank you for your giving. We acknowledge receipt of KES {{money .Total}} on {{date .Date}}:
+{{range .Lines}}
+  {{.Account}}: KES {{money .Amount}}{{end}}
+
+Reference: {{.Reference}}
+
+God bless you,
+{{.ChurchName}}
+`,
+		HTML: `<p>Dear {{.MemberName}},</p>
+<p>Thank you for your giving. We acknowledge receipt of <strong>KES {{money .Total}}</strong> on {{date .Date}}:</p>
+<table cellpadding="4">{{range .Lines}}
+<tr><td>{{.Account}}</td><td align="right">KES {{money .Amount}}</td></tr>{{end}}
+</table>
+<p>Reference: {{.Reference}}</p>
+<p>God bless you,<br>{{.ChurchName}}</p>
+`,
+	},
+	TemplateStatement: {
+		Subject: `Your giving statement {{date .StartDate}} - {{date .EndDate}}`,
+		Text: `Dear {{.MemberName}},
+
+Please find attached your giving statement for {{date .StartDate}} to {{date .EndDate}}.
+Total given: KES {{money .Total}} across {{len .Lines}} receipt line(s).
+
+God bless you,
+{{.ChurchName}}
+`,
+		HTML: `<p>Dear {{.MemberName}},</p>
+<p>Please find attached your giving statement for {{date .StartDate}} to {{date .EndDate}}.</p>
+<p>Total given: <strong>KES {{money .Total}}</strong> across {{len .Lines}} receipt line(s).</p>
+<p>God bless you,<br>{{.ChurchName}}</p>
+`,
+	},
+	TemplateMonthlyReport: {
+		Subject: `Treasurer's report for {{.Period}}`,
+		Text: `Treasurer's report for {{.Period}}
+
+Income: KES {{money .TotalIncome}}
+Expenses: KES {{money .TotalExpenses}}
+Net: KES {{money .Net}}
+
+The budget-vs-actual detail is attached as PDF and CSV.
+
+{{.ChurchName}}
+`,
+		HTML: `<h3>Treasurer's report for {{.Period}}</h3>
+<table cellpadding="4">
+<tr><td>Income</td><td align="right">KES {{money .TotalIncome}}</td></tr>
+<tr><td>Expenses</td><td align="right">KES {{money .TotalExpenses}}</td></tr>
+<tr><td><strong>Net</strong></td><td align="right"><strong>KES {{money .Net}}</strong></td></tr>
+</table>
+<p>The budget-vs-actual detail is attached as PDF and CSV.</p>
+<p>{{.ChurchName}}</p>
+`,
+	},
+}
+
+var textFuncs = texttemplate.FuncMap{"money": notifications.FormatMoney, "date": formatDate}
+var htmlFuncs = htmltemplate.FuncMap{"money": notifications.FormatMoney, "date": formatDate}
+
+// Render fills in the named built-in template
+func Render(name string, data interface{}) (Rendered, error) {
+	tmpl, ok := Templates[name]
+	if !ok {
+		return Rendered{}, fmt.Errorf("unknown email template %q", name)
+	}
+
+	var out Rendered
+	var err error
+	if out.Subject, err = renderText(name+".subject", tmpl.Subject, data); err != nil {
+		return Rendered{}, err
+	}
+	if out.Text, err = renderText(name+".text", tmpl.Text, data); err != nil {
+		return Rendered{}, err
+	}
+
+	if tmpl.HTML != "" {
+		t, err := htmltemplate.New(name + ".html").Funcs(htmlFuncs).Parse(tmpl.HTML)
+		if err != nil {
+			return Rendered{}, err
+		}
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, data); err != nil {
+			return Rendered{}, err
+		}
+		out.HTML = buf.String()
+	}
+
+	return out, nil
+}
+
+func renderText(name, text string, data interface{}) (string, error) {
+	t, err := texttemplate.New(name).Funcs(textFuncs).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+func formatDate(t time.Time) string {
+	return t.Format("02 Jan 2006")
+}
@@ -1,10 +1,16 @@
 package main
 
 import (
+	"context"
+	"log"
 	"storeHouse/database"
 	hanlers "storeHouse/hanlers"
+	"storeHouse/repository"
+	"storeHouse/services"
+	"time"
 
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/jmoiron/sqlx"
 )
 
 func main() {
@@ -13,6 +19,29 @@ func main() {
 
 	database.ApplyMigrations(db)
 
+	// Refuse to start on a schema the models and queries do not match
+	if err := repository.CheckSchema(context.Background(), db); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	// Every church works on its own connection pool, limited to its rows
+	tenants := database.NewTenants(db)
+	defer tenants.Close()
+	if err := tenants.CheckIsolation(); err != nil {
+		log.Fatalf("❌ Church data isolation unavailable: %v", err)
+	}
+
+	tenants.OnChurch(func(churchID string, churchDB *sqlx.DB) {
+		// Send queued SMS notifications in the background
+		services.NewSMSService(repository.NewPostgres(churchDB)).StartDispatcher(15 * time.Second)
+
+		// Send queued emails in the background
+		services.NewEmailService(repository.NewPostgres(churchDB)).StartDispatcher(30 * time.Second)
+
+		// Generate recurring transactions as they fall due, catching up missed runs first
+		services.NewRecurringService(repository.NewPostgres(churchDB)).StartScheduler(time.Minute)
+	})
+
 	// Start the HTTP server
-	hanlers.StartServer(db)
+	hanlers.StartServer(db, tenants)
 }
@@ -2,8 +2,8 @@ package middleware
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
+	"storeHouse/models"
 	"strings"
 
 	"github.com/jmoiron/sqlx"
@@ -30,34 +30,45 @@ func AuthMiddleware(db *sqlx.DB) func(next http.Handler) http.Handler {
 			// Get token from Authorization header
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
-				http.Error(w, "Authorization header required", http.StatusUnauthorized)
+				WriteError(w, models.Unauthorized("Authorization header required"))
 				return
 			}
 
 			const tokenPrefix = "Bearer "
 			if !strings.HasPrefix(authHeader, tokenPrefix) {
-				http.Error(w, "Invalid authorization format", http.StatusUnauthorized)
+				WriteError(w, models.Unauthorized("Invalid authorization format"))
 				return
 			}
 
 			token := strings.TrimPrefix(authHeader, tokenPrefix)
 			if token == "" {
-				http.Error(w, "Token required", http.StatusUnauthorized)
+				WriteError(w, models.Unauthorized("Token required"))
 				return
 			}
 
 			// For this example, we'll validate against database
 			// In a real implementation, you'd verify JWT signature
 			var user User
-			err := db.Get(&user,
+			err := db.GetContext(r.Context(), &user,
 				"SELECT id, username, email, role FROM users WHERE id = $1 AND is_active = true",
 				token)
 
 			if err != nil {
-				http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+				WriteError(w, models.Unauthorized("Invalid or expired token"))
 				return
 			}
 
+			// Within a church the user works under their role there
+			if church := GetChurchFromContext(r); church != nil {
+				err := db.GetContext(r.Context(), &user.Role,
+					"SELECT role FROM church_users WHERE church_id = $1 AND user_id = $2",
+					church.ID, user.ID)
+				if err != nil {
+					WriteError(w, models.Forbidden("You do not have access to this church"))
+					return
+				}
+			}
+
 			// Add user to request context
 			ctx := context.WithValue(r.Context(), AuthContextKey, user)
 			next.ServeHTTP(w, r.WithContext(ctx))
@@ -78,11 +89,7 @@ func RequireAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		user := GetUserFromContext(r)
 		if user == nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusUnauthorized)
-			json.NewEncoder(w).Encode(map[string]string{
-				"error": "Authentication required",
-			})
+			WriteError(w, models.Unauthorized("Authentication required"))
 			return
 		}
 		next.ServeHTTP(w, r)
@@ -101,3 +108,18 @@ func OptionalAuth(next http.Handler) http.Handler {
 		}
 	})
 }
+
+// AuthIfPresent authenticates requests that carry an Authorization header and lets
+// anonymous requests through, so handlers can record who acted when it is known
+func AuthIfPresent(db *sqlx.DB) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		authenticated := AuthMiddleware(db)(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			authenticated.ServeHTTP(w, r)
+		})
+	}
+}
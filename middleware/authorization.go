@@ -1,8 +1,8 @@
 package middleware
 
 import (
-	"encoding/json"
 	"net/http"
+	"storeHouse/models"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
@@ -12,9 +12,10 @@ import (
 type Role string
 
 const (
-	RoleAdmin     Role = "Admin"
-	RoleTreasurer Role = "Treasurer"
-	RoleClerk     Role = "Clerk"
+	RoleAdmin      Role = "Admin"
+	RoleTreasurer  Role = "Treasurer"
+	RoleClerk      Role = "Clerk"
+	RoleBoardChair Role = "BoardChair"
 )
 
 // RequireRole middleware ensures the authenticated user has the required role
@@ -23,24 +24,14 @@ func RequireRole(allowedRoles ...Role) func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			user := GetUserFromContext(r)
 			if user == nil {
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusUnauthorized)
-				json.NewEncoder(w).Encode(map[string]string{
-					"error": "Authentication required",
-				})
+				WriteError(w, models.Unauthorized("Authentication required"))
 				return
 			}
 
 			// Check if user's role is in the allowed roles
 			userRole := Role(user.Role)
 			if !containsRole(allowedRoles, userRole) {
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusForbidden)
-				json.NewEncoder(w).Encode(map[string]string{
-					"error":          "Insufficient permissions",
-					"required_roles": rolesToString(allowedRoles),
-					"user_role":      string(userRole),
-				})
+				WriteError(w, models.Forbidden("Insufficient permissions: requires "+rolesToString(allowedRoles)))
 				return
 			}
 
@@ -64,11 +55,7 @@ func RequireAnyRole(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		user := GetUserFromContext(r)
 		if user == nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusUnauthorized)
-			json.NewEncoder(w).Encode(map[string]string{
-				"error": "Authentication required",
-			})
+			WriteError(w, models.Unauthorized("Authentication required"))
 			return
 		}
 		next.ServeHTTP(w, r)
@@ -81,11 +68,7 @@ func ResourceOwner(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		user := GetUserFromContext(r)
 		if user == nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusUnauthorized)
-			json.NewEncoder(w).Encode(map[string]string{
-				"error": "Authentication required",
-			})
+			WriteError(w, models.Unauthorized("Authentication required"))
 			return
 		}
 
@@ -98,21 +81,13 @@ func ResourceOwner(next http.Handler) http.Handler {
 		// Get resource ID from URL parameter (assumes {id} parameter)
 		resourceID := chi.URLParam(r, "id")
 		if resourceID == "" {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			json.NewEncoder(w).Encode(map[string]string{
-				"error": "Resource ID required",
-			})
+			WriteError(w, models.Invalid("Resource ID required"))
 			return
 		}
 
 		// Check if user owns the resource
 		if resourceID != user.ID {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusForbidden)
-			json.NewEncoder(w).Encode(map[string]string{
-				"error": "Access denied: can only access your own resources",
-			})
+			WriteError(w, models.Forbidden("Access denied: can only access your own resources"))
 			return
 		}
 
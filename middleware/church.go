@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"storeHouse/models"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ChurchContextKey is the key for storing the selected church in context
+const ChurchContextKey contextKey = "church"
+
+// ChurchHeader names the request header that selects the church to work in
+const ChurchHeader = "X-Church-ID"
+
+// Church represents the church a request works in
+type Church struct {
+	ID   string `json:"id" db:"id"`
+	Code string `json:"code" db:"code"`
+	Name string `json:"name" db:"name"`
+	Role string `json:"role" db:"role"`
+}
+
+// ChurchMiddleware selects the church an authenticated request works in, from the
+// X-Church-ID header (a church ID or code) or the user's only church. The user must be
+// one of the church's users, and works under their role in that church.
+func ChurchMiddleware(db *sqlx.DB) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := GetUserFromContext(r)
+			if user == nil {
+				WriteError(w, models.Unauthorized("Authentication required"))
+				return
+			}
+
+			var churches []Church
+			query := `SELECT c.id, c.code, c.name, cu.role
+					  FROM church_users cu
+					  JOIN churches c ON c.id = cu.church_id
+					  WHERE cu.user_id = $1 AND c.is_active = true`
+			args := []interface{}{user.ID}
+			if ref := strings.TrimSpace(r.Header.Get(ChurchHeader)); ref != "" {
+				query += " AND (c.id::text = $2 OR c.code = $3)"
+				args = append(args, ref, strings.ToUpper(ref))
+			}
+
+			if err := db.SelectContext(r.Context(), &churches, query, args...); err != nil {
+				WriteError(w, models.Internal("Could not load churches"))
+				return
+			}
+
+			switch {
+			case len(churches) == 1:
+			case r.Header.Get(ChurchHeader) != "":
+				WriteError(w, models.Forbidden("You do not have access to this church"))
+				return
+			case len(churches) == 0:
+				WriteError(w, models.Forbidden("You do not belong to any church"))
+				return
+			default:
+				WriteError(w, models.Invalid(ChurchHeader+" header required"))
+				return
+			}
+
+			church := churches[0]
+			user.Role = church.Role
+
+			ctx := context.WithValue(r.Context(), ChurchContextKey, church)
+			ctx = context.WithValue(ctx, AuthContextKey, *user)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetChurchFromContext extracts the selected church from request context
+func GetChurchFromContext(r *http.Request) *Church {
+	if church, ok := r.Context().Value(ChurchContextKey).(Church); ok {
+		return &church
+	}
+	return nil
+}
+
+// WithChurch returns a copy of the request that works in the given church, for requests
+// such as provider callbacks that identify their church without a signed-in user
+func WithChurch(r *http.Request, church Church) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), ChurchContextKey, church))
+}
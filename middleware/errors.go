@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"storeHouse/models"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// WriteError writes err in the standard JSON error envelope with the HTTP status it maps to.
+// Every API error response goes through here.
+func WriteError(w http.ResponseWriter, err error) {
+	appErr := AsAppError(err)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(appErr.Status)
+	json.NewEncoder(w).Encode(models.ErrorResponse{
+		Error:  appErr.Message,
+		Code:   appErr.Code,
+		Fields: appErr.Fields,
+	})
+}
+
+// AsAppError maps any error to the AppError it is reported as. Typed errors keep their
+// status; Postgres constraint violations become 409 or 422; anything else is an internal
+// error whose details are logged rather than returned.
+func AsAppError(err error) *models.AppError {
+	var appErr *models.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		if mapped := fromPostgres(pqErr); mapped != nil {
+			return mapped
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return timedOut(err)
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.NotFound("record not found")
+	}
+
+	log.Printf("⚠️  Internal error: %v", err)
+	return models.Internal("internal server error")
+}
+
+var (
+	keyDetail        = regexp.MustCompile(`^Key \((.+)\)=\((.*)\) already exists\.?$`)
+	missingDetail    = regexp.MustCompile(`^Key \((.+)\)=\((.*)\) is not present in table "(.+)"\.?$`)
+	referencedDetail = regexp.MustCompile(`is still referenced from table "(.+)"\.?$`)
+)
+
+// fromPostgres maps the constraint violations a request can cause to client errors
+func fromPostgres(pqErr *pq.Error) *models.AppError {
+	switch pqErr.Code.Name() {
+	case "unique_violation":
+		message := "a record with the same values already exists"
+		var fields []models.FieldError
+		if m := keyDetail.FindStringSubmatch(pqErr.Detail); m != nil {
+			message = m[1] + " " + m[2] + " already exists"
+			fields = columnFields(m[1], "already exists")
+		}
+		return &models.AppError{Status: http.StatusConflict, Code: models.CodeConflict, Message: message, Fields: fields, Err: pqErr}
+
+	case "foreign_key_violation":
+		if m := referencedDetail.FindStringSubmatch(pqErr.Detail); m != nil {
+			return &models.AppError{Status: http.StatusConflict, Code: models.CodeConflict,
+				Message: "record is still referenced by " + m[1], Err: pqErr}
+		}
+		message := "a referenced record does not exist"
+		var fields []models.FieldError
+		if m := missingDetail.FindStringSubmatch(pqErr.Detail); m != nil {
+			message = m[1] + " " + m[2] + " does not exist"
+			fields = columnFields(m[1], "does not exist")
+		}
+		return &models.AppError{Status: http.StatusUnprocessableEntity, Code: models.CodeUnprocessable, Message: message, Fields: fields, Err: pqErr}
+
+	case "not_null_violation":
+		return &models.AppError{Status: http.StatusUnprocessableEntity, Code: models.CodeUnprocessable,
+			Message: pqErr.Column + " is required", Fields: columnFields(pqErr.Column, "is required"), Err: pqErr}
+
+	case "check_violation", "string_data_right_truncation", "numeric_value_out_of_range":
+		message := "a value is out of range"
+		if pqErr.Constraint != "" {
+			message = "value violates " + pqErr.Constraint
+		}
+		return &models.AppError{Status: http.StatusUnprocessableEntity, Code: models.CodeUnprocessable, Message: message, Err: pqErr}
+
+	case "query_canceled":
+		return timedOut(pqErr)
+
+	case "invalid_text_representation", "invalid_datetime_format":
+		return &models.AppError{Status: http.StatusBadRequest, Code: models.CodeInvalidRequest, Message: "malformed value: " + pqErr.Message, Err: pqErr}
+	}
+
+	return nil
+}
+
+// timedOut reports a request that ran out of time, or whose client went away, before its
+// queries finished
+func timedOut(err error) *models.AppError {
+	return &models.AppError{Status: http.StatusGatewayTimeout, Code: models.CodeTimeout, Message: "request timed out", Err: err}
+}
+
+// columnFields lists the field errors for the columns named in a constraint detail
+func columnFields(columns, message string) []models.FieldError {
+	var fields []models.FieldError
+	for _, column := range strings.Split(columns, ",") {
+		column = strings.TrimSpace(column)
+		fields = append(fields, models.FieldError{Field: column, Message: column + " " + message})
+	}
+	return fields
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"storeHouse/models"
 	"sync"
 	"time"
 )
@@ -40,9 +41,7 @@ func RateLimit(limiter *RateLimiter) func(next http.Handler) http.Handler {
 			clientIP := getClientIP(r)
 
 			if !limiter.allowRequest(clientIP) {
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(limiter.statusCode)
-				w.Write([]byte(`{"error":"` + limiter.message + `"}`))
+				WriteError(w, models.NewError(limiter.statusCode, models.CodeRateLimited, limiter.message))
 				return
 			}
 
@@ -152,9 +151,7 @@ func (p *PerEndpointRateLimiter) RateLimitPerEndpoint(next http.Handler) http.Ha
 		clientIP := getClientIP(r)
 
 		if !limiter.allowRequest(clientIP) {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusTooManyRequests)
-			w.Write([]byte(`{"error":"Rate limit exceeded for this endpoint"}`))
+			WriteError(w, models.NewError(http.StatusTooManyRequests, models.CodeRateLimited, "Rate limit exceeded for this endpoint"))
 			return
 		}
 
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"storeHouse/models"
 	"strconv"
 	"strings"
 )
@@ -74,7 +75,7 @@ func RequestSizeLimit(maxBytes int64) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			if r.ContentLength > maxBytes {
-				http.Error(w, "Request too large", http.StatusRequestEntityTooLarge)
+				WriteError(w, models.NewError(http.StatusRequestEntityTooLarge, models.CodeTooLarge, "Request too large"))
 				return
 			}
 			next.ServeHTTP(w, r)
@@ -94,7 +95,7 @@ func ContentTypeValidation(allowedTypes []string) func(next http.Handler) http.H
 					baseType = strings.TrimSpace(baseType)
 
 					if !containsString(allowedTypes, baseType) {
-						http.Error(w, "Unsupported content type", http.StatusUnsupportedMediaType)
+						WriteError(w, models.NewError(http.StatusUnsupportedMediaType, models.CodeUnsupportedMedia, "Unsupported content type"))
 						return
 					}
 				}
@@ -111,7 +112,7 @@ func IPWhitelist(allowedIPs []string) func(next http.Handler) http.Handler {
 			clientIP := getClientIP(r)
 
 			if !containsString(allowedIPs, clientIP) && !containsString(allowedIPs, "*") {
-				http.Error(w, "Access denied from this IP", http.StatusForbidden)
+				WriteError(w, models.Forbidden("Access denied from this IP"))
 				return
 			}
 
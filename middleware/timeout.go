@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"storeHouse/database"
+	"strings"
+)
+
+// RouteTimeout gives the requests matching Pattern their own timeouts. A pattern is a path
+// in the router's syntax, optionally led by a method, e.g. "POST /api/v1/budgets/import":
+// {name} matches one path segment and a trailing /* matches the rest of the path.
+type RouteTimeout struct {
+	Pattern string
+	database.Timeouts
+}
+
+// Timeout bounds each request by the timeouts of the first route that matches it, or by
+// the defaults. The request's context is cancelled when its time is up or the client goes
+// away, which cancels the queries it is running; each query is also held to the query
+// timeout.
+func Timeout(defaults database.Timeouts, routes ...RouteTimeout) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timeouts := defaults
+			for _, route := range routes {
+				if matchRoute(route.Pattern, r) {
+					timeouts = route.Timeouts
+					break
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeouts.Request)
+			defer cancel()
+			ctx = database.WithQueryTimeout(ctx, timeouts.Query)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// matchRoute reports whether a request matches a route pattern
+func matchRoute(pattern string, r *http.Request) bool {
+	if method, path, ok := strings.Cut(pattern, " "); ok {
+		if !strings.EqualFold(method, r.Method) {
+			return false
+		}
+		pattern = path
+	}
+
+	want := strings.Split(strings.Trim(pattern, "/"), "/")
+	got := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	for i, segment := range want {
+		if segment == "*" && i == len(want)-1 {
+			return true
+		}
+		if i >= len(got) {
+			return false
+		}
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			continue
+		}
+		if segment != got[i] {
+			return false
+		}
+	}
+	return len(got) == len(want)
+}
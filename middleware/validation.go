@@ -1,9 +1,12 @@
 package middleware
 
 import (
+	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
 	"regexp"
+	"storeHouse/models"
 	"strconv"
 	"strings"
 )
@@ -19,6 +22,17 @@ type ValidationRule struct {
 	ErrorMessage string
 }
 
+// DecodeJSON decodes a handler's JSON request body into req, rejecting unknown fields, and
+// checks it against its binding tags. Every field at fault is written back in the error
+// envelope and false is returned, so the handler only has to return.
+func DecodeJSON(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	if err := models.DecodeRequest(r.Body, req); err != nil {
+		WriteError(w, err)
+		return false
+	}
+	return true
+}
+
 // ValidateRequest validates incoming requests based on rules
 func ValidateRequest(rules []ValidationRule) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -33,13 +47,13 @@ func ValidateRequest(rules []ValidationRule) func(next http.Handler) http.Handle
 			} else {
 				// For POST, PUT, PATCH, validate JSON body
 				if !strings.Contains(r.Header.Get("Content-Type"), "application/json") {
-					http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+					WriteError(w, models.NewError(http.StatusUnsupportedMediaType, models.CodeUnsupportedMedia, "Content-Type must be application/json"))
 					return
 				}
 
-				var data map[string]interface{}
-				if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
-					http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+				data, err := readJSONBody(r)
+				if err != nil {
+					WriteError(w, models.Invalid("Invalid JSON format"))
 					return
 				}
 
@@ -60,27 +74,22 @@ func ValidateJSON(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == "POST" || r.Method == "PUT" || r.Method == "PATCH" {
 			if r.Body == nil {
-				http.Error(w, "Request body is required", http.StatusBadRequest)
+				WriteError(w, models.Invalid("Request body is required"))
 				return
 			}
 
 			// Check content type
 			contentType := r.Header.Get("Content-Type")
 			if !strings.Contains(contentType, "application/json") {
-				http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+				WriteError(w, models.NewError(http.StatusUnsupportedMediaType, models.CodeUnsupportedMedia, "Content-Type must be application/json"))
 				return
 			}
 
 			// Try to decode JSON
-			var data map[string]interface{}
-			decoder := json.NewDecoder(r.Body)
-			if err := decoder.Decode(&data); err != nil {
-				http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+			if _, err := readJSONBody(r); err != nil {
+				WriteError(w, models.Invalid("Invalid JSON format"))
 				return
 			}
-
-			// Re-encode to reset the body for the next handler
-			r.Body = &rewindableBody{data: data}
 		}
 
 		next.ServeHTTP(w, r)
@@ -95,27 +104,24 @@ func ValidateRequiredFields(fields ...string) func(next http.Handler) http.Handl
 				// Validate query parameters
 				for _, field := range fields {
 					if r.URL.Query().Get(field) == "" {
-						http.Error(w, "Field '"+field+"' is required", http.StatusBadRequest)
+						WriteError(w, models.Invalid("Field '"+field+"' is required"))
 						return
 					}
 				}
 			} else {
 				// Validate JSON body
-				var data map[string]interface{}
-				if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
-					http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+				data, err := readJSONBody(r)
+				if err != nil {
+					WriteError(w, models.Invalid("Invalid JSON format"))
 					return
 				}
 
 				for _, field := range fields {
 					if _, exists := data[field]; !exists || data[field] == nil || data[field] == "" {
-						http.Error(w, "Field '"+field+"' is required", http.StatusBadRequest)
+						WriteError(w, models.Invalid("Field '"+field+"' is required"))
 						return
 					}
 				}
-
-				// Re-encode to reset the body
-				r.Body = &rewindableBody{data: data}
 			}
 
 			next.ServeHTTP(w, r)
@@ -127,21 +133,18 @@ func ValidateRequiredFields(fields ...string) func(next http.Handler) http.Handl
 func ValidateEmail(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == "POST" || r.Method == "PUT" || r.Method == "PATCH" {
-			var data map[string]interface{}
-			if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
-				http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+			data, err := readJSONBody(r)
+			if err != nil {
+				WriteError(w, models.Invalid("Invalid JSON format"))
 				return
 			}
 
 			emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 
 			if email, ok := data["email"].(string); ok && !emailRegex.MatchString(email) {
-				http.Error(w, "Invalid email format", http.StatusBadRequest)
+				WriteError(w, models.Invalid("Invalid email format"))
 				return
 			}
-
-			// Re-encode to reset the body
-			r.Body = &rewindableBody{data: data}
 		}
 
 		next.ServeHTTP(w, r)
@@ -152,21 +155,18 @@ func ValidateEmail(next http.Handler) http.Handler {
 func ValidateAmount(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == "POST" || r.Method == "PUT" || r.Method == "PATCH" {
-			var data map[string]interface{}
-			if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
-				http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+			data, err := readJSONBody(r)
+			if err != nil {
+				WriteError(w, models.Invalid("Invalid JSON format"))
 				return
 			}
 
 			if amount, ok := data["amount"].(string); ok {
 				if _, err := strconv.ParseFloat(amount, 64); err != nil {
-					http.Error(w, "Amount must be a valid number", http.StatusBadRequest)
+					WriteError(w, models.Invalid("Amount must be a valid number"))
 					return
 				}
 			}
-
-			// Re-encode to reset the body
-			r.Body = &rewindableBody{data: data}
 		}
 
 		next.ServeHTTP(w, r)
@@ -187,7 +187,7 @@ func PaginationMiddleware(next http.Handler) http.Handler {
 
 		if limitStr != "" {
 			if l, err := strconv.Atoi(limitStr); err != nil || l <= 0 || l > 100 {
-				http.Error(w, "Limit must be between 1 and 100", http.StatusBadRequest)
+				WriteError(w, models.Invalid("Limit must be between 1 and 100"))
 				return
 			} else {
 				limit = l
@@ -196,14 +196,14 @@ func PaginationMiddleware(next http.Handler) http.Handler {
 
 		if pageStr != "" {
 			if p, err := strconv.Atoi(pageStr); err != nil || p <= 0 {
-				http.Error(w, "Page must be a positive integer", http.StatusBadRequest)
+				WriteError(w, models.Invalid("Page must be a positive integer"))
 				return
 			} else {
 				offset = (p - 1) * limit
 			}
 		} else if offsetStr != "" {
 			if o, err := strconv.Atoi(offsetStr); err != nil || o < 0 {
-				http.Error(w, "Offset must be a non-negative integer", http.StatusBadRequest)
+				WriteError(w, models.Invalid("Offset must be a non-negative integer"))
 				return
 			} else {
 				offset = o
@@ -220,14 +220,14 @@ func PaginationMiddleware(next http.Handler) http.Handler {
 }
 
 // validateQueryParams validates query parameters
-func validateQueryParams(query map[string][]string, rules []ValidationRule) []string {
-	var errors []string
+func validateQueryParams(query map[string][]string, rules []ValidationRule) []models.FieldError {
+	var errors []models.FieldError
 
 	for _, rule := range rules {
 		values := query[rule.Field]
 		if len(values) == 0 {
 			if rule.Required {
-				errors = append(errors, "Field '"+rule.Field+"' is required")
+				errors = append(errors, models.FieldError{Field: rule.Field, Message: "Field '" + rule.Field + "' is required"})
 			}
 			continue
 		}
@@ -241,22 +241,22 @@ func validateQueryParams(query map[string][]string, rules []ValidationRule) []st
 }
 
 // validateJSONBody validates JSON body fields
-func validateJSONBody(data map[string]interface{}, rules []ValidationRule) []string {
-	var errors []string
+func validateJSONBody(data map[string]interface{}, rules []ValidationRule) []models.FieldError {
+	var errors []models.FieldError
 
 	for _, rule := range rules {
 		value, exists := data[rule.Field]
 
 		if !exists || value == nil {
 			if rule.Required {
-				errors = append(errors, "Field '"+rule.Field+"' is required")
+				errors = append(errors, models.FieldError{Field: rule.Field, Message: "Field '" + rule.Field + "' is required"})
 			}
 			continue
 		}
 
 		valueStr, ok := value.(string)
 		if !ok {
-			errors = append(errors, "Field '"+rule.Field+"' must be a string")
+			errors = append(errors, models.FieldError{Field: rule.Field, Message: "Field '" + rule.Field + "' must be a string"})
 			continue
 		}
 
@@ -268,15 +268,15 @@ func validateJSONBody(data map[string]interface{}, rules []ValidationRule) []str
 }
 
 // validateField validates a single field against rules
-func validateField(value string, rule ValidationRule) []string {
-	var errors []string
+func validateField(value string, rule ValidationRule) []models.FieldError {
+	var errors []models.FieldError
 
 	if rule.MinLength > 0 && len(value) < rule.MinLength {
-		errors = append(errors, "Field '"+rule.Field+"' must be at least "+string(rune(rule.MinLength))+" characters")
+		errors = append(errors, models.FieldError{Field: rule.Field, Message: "Field '" + rule.Field + "' must be at least " + strconv.Itoa(rule.MinLength) + " characters"})
 	}
 
 	if rule.MaxLength > 0 && len(value) > rule.MaxLength {
-		errors = append(errors, "Field '"+rule.Field+"' must be at most "+string(rune(rule.MaxLength))+" characters")
+		errors = append(errors, models.FieldError{Field: rule.Field, Message: "Field '" + rule.Field + "' must be at most " + strconv.Itoa(rule.MaxLength) + " characters"})
 	}
 
 	if rule.Pattern != "" {
@@ -286,7 +286,7 @@ func validateField(value string, rule ValidationRule) []string {
 			if errorMsg == "" {
 				errorMsg = "Field '" + rule.Field + "' format is invalid"
 			}
-			errors = append(errors, errorMsg)
+			errors = append(errors, models.FieldError{Field: rule.Field, Message: errorMsg})
 		}
 	}
 
@@ -295,34 +295,33 @@ func validateField(value string, rule ValidationRule) []string {
 		if errorMsg == "" {
 			errorMsg = "Field '" + rule.Field + "' is invalid"
 		}
-		errors = append(errors, errorMsg)
+		errors = append(errors, models.FieldError{Field: rule.Field, Message: errorMsg})
 	}
 
 	return errors
 }
 
 // sendValidationError sends validation errors as JSON response
-func sendValidationError(w http.ResponseWriter, errors []string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusBadRequest)
-
-	response := map[string]interface{}{
-		"error":   "Validation failed",
-		"details": errors,
-	}
-
-	json.NewEncoder(w).Encode(response)
-}
-
-// rewindableBody is a wrapper to allow re-reading the request body
-type rewindableBody struct {
-	data map[string]interface{}
+func sendValidationError(w http.ResponseWriter, errors []models.FieldError) {
+	WriteError(w, models.ValidationFailed(errors))
 }
 
-func (rb *rewindableBody) Read(p []byte) (n int, err error) {
-	return 0, nil
-}
+// readJSONBody decodes a JSON object body and puts the body back so the next handler can
+// read it again
+func readJSONBody(r *http.Request) (map[string]interface{}, error) {
+	if r.Body == nil {
+		return nil, io.EOF
+	}
+	raw, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(raw))
 
-func (rb *rewindableBody) Close() error {
-	return nil
+	var data map[string]interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
 }
@@ -1,15 +1,20 @@
 package models
 
 import (
-	"errors"
+	"regexp"
+	"strings"
 	"time"
 )
 
 // Account represents transaction accounts
 type Account struct {
 	ID          string    `json:"id" db:"id"`
+	ChurchID    string    `json:"-" db:"church_id"`
 	AccountName string    `json:"account_name" db:"account_name" binding:"required,max=100"`
 	AccountType string    `json:"account_type" db:"account_type" binding:"required"`
+	Code        *string   `json:"code" db:"code" binding:"max=20"`
+	ParentID    *string   `json:"parent_id" db:"parent"`
+	IsHeader    bool      `json:"is_header" db:"is_header"`
 	LocalShare  *float64  `json:"local_share" db:"local_share"`
 	Notes       *string   `json:"notes" db:"notes"`
 	IsActive    bool      `json:"is_active" db:"is_active"`
@@ -39,10 +44,24 @@ func (a *Account) ValidateAccountType() error {
 	}
 }
 
+var accountCodePattern = regexp.MustCompile(`^[A-Z0-9.-]{1,20}$`)
+
+// NormalizeAccountCode upper-cases an account code and checks its characters
+func NormalizeAccountCode(code string) (string, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(code))
+	if !accountCodePattern.MatchString(normalized) {
+		return "", Invalid("code must be 1 to 20 letters, digits, dots or dashes")
+	}
+	return normalized, nil
+}
+
 // CreateAccountRequest represents the request for creating a new account
 type CreateAccountRequest struct {
 	AccountName string   `json:"account_name" binding:"required,max=100"`
 	AccountType string   `json:"account_type" binding:"required"`
+	Code        *string  `json:"code" binding:"max=20"`
+	ParentID    *string  `json:"parent_id"`
+	IsHeader    bool     `json:"is_header"`
 	LocalShare  *float64 `json:"local_share"`
 	Notes       *string  `json:"notes"`
 }
@@ -50,10 +69,15 @@ type CreateAccountRequest struct {
 // Validate validates the CreateAccountRequest
 func (req *CreateAccountRequest) Validate() error {
 	if req.AccountName == "" {
-		return errors.New("account name is required")
+		return Invalid("account name is required")
 	}
 	if req.AccountType == "" {
-		return errors.New("account type is required")
+		return Invalid("account type is required")
+	}
+	if req.Code != nil {
+		if _, err := NormalizeAccountCode(*req.Code); err != nil {
+			return err
+		}
 	}
 
 	// Validate account type
@@ -61,10 +85,14 @@ func (req *CreateAccountRequest) Validate() error {
 	return account.ValidateAccountType()
 }
 
-// UpdateAccountRequest represents the request for updating an account
+// UpdateAccountRequest represents the request for updating an account. An empty parent_id
+// moves the account to the top of the chart.
 type UpdateAccountRequest struct {
 	AccountName *string  `json:"account_name" binding:"max=100"`
 	AccountType *string  `json:"account_type"`
+	Code        *string  `json:"code" binding:"max=20"`
+	ParentID    *string  `json:"parent_id"`
+	IsHeader    *bool    `json:"is_header"`
 	LocalShare  *float64 `json:"local_share"`
 	Notes       *string  `json:"notes"`
 	IsActive    *bool    `json:"is_active"`
@@ -75,6 +103,9 @@ type AccountResponse struct {
 	ID          string    `json:"id"`
 	AccountName string    `json:"account_name"`
 	AccountType string    `json:"account_type"`
+	Code        *string   `json:"code"`
+	ParentID    *string   `json:"parent_id"`
+	IsHeader    bool      `json:"is_header"`
 	LocalShare  *float64  `json:"local_share"`
 	Notes       *string   `json:"notes"`
 	IsActive    bool      `json:"is_active"`
@@ -88,6 +119,9 @@ func (a *Account) ToResponse() *AccountResponse {
 		ID:          a.ID,
 		AccountName: a.AccountName,
 		AccountType: a.AccountType,
+		Code:        a.Code,
+		ParentID:    a.ParentID,
+		IsHeader:    a.IsHeader,
 		LocalShare:  a.LocalShare,
 		Notes:       a.Notes,
 		IsActive:    a.IsActive,
@@ -95,3 +129,9 @@ func (a *Account) ToResponse() *AccountResponse {
 		UpdatedAt:   a.UpdatedAt,
 	}
 }
+
+// AccountNode represents an account in the chart of accounts tree with its children
+type AccountNode struct {
+	AccountResponse
+	Children []AccountNode `json:"children"`
+}
@@ -0,0 +1,179 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ApprovalPolicy represents a rule deciding the approvals a voucher needs. A policy
+// applies to vouchers over AmountOver, up to AmountUpTo when set, of its transaction
+// type and touching its account or an account under it; an empty type or account matches
+// any.
+type ApprovalPolicy struct {
+	ID                string         `json:"id" db:"id"`
+	ChurchID          string         `json:"-" db:"church_id"`
+	Name              string         `json:"name" db:"name" binding:"required,max=100"`
+	Description       *string        `json:"description" db:"description"`
+	TransactionType   *string        `json:"transaction_type" db:"transaction_type"`
+	AccountID         *string        `json:"account_id" db:"account"`
+	AmountOver        float64        `json:"amount_over" db:"amount_over"`
+	AmountUpTo        *float64       `json:"amount_up_to" db:"amount_up_to"`
+	RequiredApprovals int            `json:"required_approvals" db:"required_approvals" binding:"required,min=1"`
+	RequiredRoles     pq.StringArray `json:"required_roles" db:"required_roles"`
+	IsActive          bool           `json:"is_active" db:"is_active"`
+	CreatedBy         *string        `json:"created_by" db:"created_by"`
+	CreatedAt         time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// Applies reports whether the policy covers a voucher of the given type and amount that
+// touches the given accounts. Subtree holds the policy's account and every account under it.
+func (p *ApprovalPolicy) Applies(transactionType string, accounts []string, amount float64, subtree []string) bool {
+	if !p.IsActive {
+		return false
+	}
+	if p.TransactionType != nil && *p.TransactionType != transactionType {
+		return false
+	}
+	if amount <= p.AmountOver {
+		return false
+	}
+	if p.AmountUpTo != nil && amount > *p.AmountUpTo {
+		return false
+	}
+	if p.AccountID == nil {
+		return true
+	}
+	for _, a := range accounts {
+		for _, covered := range subtree {
+			if a == covered {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Validate checks the policy's type, amount range, approval count and roles
+func (p *ApprovalPolicy) Validate() error {
+	if p.Name == "" {
+		return Invalid("name is required")
+	}
+	if p.TransactionType != nil {
+		txn := Transaction{TransactionType: *p.TransactionType}
+		if !txn.IsVoucher() {
+			return Invalid("transaction_type must be expenses, withdrawal or transfer")
+		}
+	}
+	if p.AmountOver < 0 {
+		return Invalid("amount_over must not be negative")
+	}
+	if p.AmountUpTo != nil && *p.AmountUpTo <= p.AmountOver {
+		return Invalid("amount_up_to must be greater than amount_over")
+	}
+	if p.RequiredApprovals < 1 {
+		return Invalid("required_approvals must be at least 1")
+	}
+	if len(p.RequiredRoles) > p.RequiredApprovals {
+		return Invalid("required_approvals must cover every required role")
+	}
+	for _, role := range p.RequiredRoles {
+		if !IsApproverRole(role) {
+			return Invalid("required_roles may only contain Admin, Treasurer or BoardChair")
+		}
+	}
+	return nil
+}
+
+// IsApproverRole reports whether users with the role may approve vouchers
+func IsApproverRole(role string) bool {
+	switch role {
+	case string(RoleAdmin), string(RoleTreasurer), string(RoleBoardChair):
+		return true
+	default:
+		return false
+	}
+}
+
+// ApprovalRequirement represents what the matching policies require of a voucher
+type ApprovalRequirement struct {
+	RequiredApprovals int      `json:"required_approvals"`
+	RequiredRoles     []string `json:"required_roles"`
+	Policies          []string `json:"policies"`
+}
+
+// CreateApprovalPolicyRequest represents the request for creating an approval policy
+type CreateApprovalPolicyRequest struct {
+	Name              string   `json:"name" binding:"required,max=100"`
+	Description       *string  `json:"description"`
+	TransactionType   *string  `json:"transaction_type"`
+	AccountID         *string  `json:"account_id"`
+	AmountOver        float64  `json:"amount_over"`
+	AmountUpTo        *float64 `json:"amount_up_to"`
+	RequiredApprovals int      `json:"required_approvals" binding:"required,min=1"`
+	RequiredRoles     []string `json:"required_roles"`
+	IsActive          *bool    `json:"is_active"`
+}
+
+// UpdateApprovalPolicyRequest represents the request for updating an approval policy.
+// An empty transaction_type or account_id clears it so the policy matches any.
+type UpdateApprovalPolicyRequest struct {
+	Name              *string  `json:"name" binding:"max=100"`
+	Description       *string  `json:"description"`
+	TransactionType   *string  `json:"transaction_type"`
+	AccountID         *string  `json:"account_id"`
+	AmountOver        *float64 `json:"amount_over"`
+	AmountUpTo        *float64 `json:"amount_up_to"`
+	RequiredApprovals *int     `json:"required_approvals" binding:"min=1"`
+	RequiredRoles     []string `json:"required_roles"`
+	IsActive          *bool    `json:"is_active"`
+}
+
+// EvaluateApprovalPolicyRequest represents a voucher to check against the policies
+type EvaluateApprovalPolicyRequest struct {
+	TransactionType string   `json:"transaction_type" binding:"required"`
+	AccountIDs      []string `json:"account_ids"`
+	Amount          float64  `json:"amount" binding:"required"`
+}
+
+// ApprovalPolicyResponse represents the approval policy response
+type ApprovalPolicyResponse struct {
+	ID                string    `json:"id"`
+	Name              string    `json:"name"`
+	Description       *string   `json:"description"`
+	TransactionType   *string   `json:"transaction_type"`
+	AccountID         *string   `json:"account_id"`
+	AmountOver        float64   `json:"amount_over"`
+	AmountUpTo        *float64  `json:"amount_up_to"`
+	RequiredApprovals int       `json:"required_approvals"`
+	RequiredRoles     []string  `json:"required_roles"`
+	IsActive          bool      `json:"is_active"`
+	CreatedBy         *string   `json:"created_by"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// ToResponse converts ApprovalPolicy to ApprovalPolicyResponse
+func (p *ApprovalPolicy) ToResponse() *ApprovalPolicyResponse {
+	roles := []string(p.RequiredRoles)
+	if roles == nil {
+		roles = []string{}
+	}
+
+	return &ApprovalPolicyResponse{
+		ID:                p.ID,
+		Name:              p.Name,
+		Description:       p.Description,
+		TransactionType:   p.TransactionType,
+		AccountID:         p.AccountID,
+		AmountOver:        p.AmountOver,
+		AmountUpTo:        p.AmountUpTo,
+		RequiredApprovals: p.RequiredApprovals,
+		RequiredRoles:     roles,
+		IsActive:          p.IsActive,
+		CreatedBy:         p.CreatedBy,
+		CreatedAt:         p.CreatedAt,
+		UpdatedAt:         p.UpdatedAt,
+	}
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"time"
+)
+
+// Attachment represents a supporting document linked to one transaction, expenditure or transfer
+type Attachment struct {
+	ID             string    `json:"id" db:"id"`
+	ChurchID       string    `json:"-" db:"church_id"`
+	TransactionID  *string   `json:"transaction_id" db:"transaction_id"`
+	ExpenditureID  *string   `json:"expenditure_id" db:"expenditure_id"`
+	TransferID     *string   `json:"transfer_id" db:"transfer_id"`
+	FileName       string    `json:"file_name" db:"file_name"`
+	ContentType    string    `json:"content_type" db:"content_type"`
+	SizeBytes      int64     `json:"size_bytes" db:"size_bytes"`
+	ChecksumSHA256 string    `json:"checksum_sha256" db:"checksum_sha256"`
+	StorageKey     string    `json:"-" db:"storage_key"`
+	Description    *string   `json:"description" db:"description" binding:"max=255"`
+	UploadedBy     *string   `json:"uploaded_by" db:"uploaded_by"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// AttachmentEntity represents the kind of record a file is attached to
+type AttachmentEntity string
+
+const (
+	AttachmentTransaction AttachmentEntity = "transaction"
+	AttachmentExpenditure AttachmentEntity = "expenditure"
+	AttachmentTransfer    AttachmentEntity = "transfer"
+)
+
+// Column returns the attachments column linking to the entity
+func (e AttachmentEntity) Column() string {
+	return string(e) + "_id"
+}
+
+// ValidateAttachmentEntity checks if the entity type can hold attachments
+func ValidateAttachmentEntity(entityType string) error {
+	switch AttachmentEntity(entityType) {
+	case AttachmentTransaction, AttachmentExpenditure, AttachmentTransfer:
+		return nil
+	default:
+		return Invalid("entity_type must be transaction, expenditure or transfer")
+	}
+}
+
+// EntityType returns the kind of record the attachment belongs to
+func (a *Attachment) EntityType() AttachmentEntity {
+	switch {
+	case a.ExpenditureID != nil:
+		return AttachmentExpenditure
+	case a.TransferID != nil:
+		return AttachmentTransfer
+	default:
+		return AttachmentTransaction
+	}
+}
+
+// EntityID returns the ID of the record the attachment belongs to
+func (a *Attachment) EntityID() string {
+	for _, id := range []*string{a.TransactionID, a.ExpenditureID, a.TransferID} {
+		if id != nil {
+			return *id
+		}
+	}
+	return ""
+}
+
+// UploadAttachmentRequest represents the form fields sent with an uploaded file
+type UploadAttachmentRequest struct {
+	EntityType  string  `json:"entity_type" binding:"required"`
+	EntityID    string  `json:"entity_id" binding:"required"`
+	FileName    string  `json:"file_name" binding:"required,max=255"`
+	Description *string `json:"description" binding:"max=255"`
+}
+
+// Validate validates the UploadAttachmentRequest
+func (req *UploadAttachmentRequest) Validate() error {
+	if err := ValidateAttachmentEntity(req.EntityType); err != nil {
+		return err
+	}
+	if req.EntityID == "" {
+		return Invalid("entity_id is required")
+	}
+	if req.Description != nil && len(*req.Description) > 255 {
+		return Invalid("description must be at most 255 characters")
+	}
+	return nil
+}
+
+// AttachmentResponse represents the attachment response
+type AttachmentResponse struct {
+	ID             string    `json:"id"`
+	EntityType     string    `json:"entity_type"`
+	EntityID       string    `json:"entity_id"`
+	FileName       string    `json:"file_name"`
+	ContentType    string    `json:"content_type"`
+	SizeBytes      int64     `json:"size_bytes"`
+	ChecksumSHA256 string    `json:"checksum_sha256"`
+	Description    *string   `json:"description"`
+	DownloadURL    string    `json:"download_url"`
+	UploadedBy     *string   `json:"uploaded_by"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// ToResponse converts Attachment to AttachmentResponse
+func (a *Attachment) ToResponse() *AttachmentResponse {
+	return &AttachmentResponse{
+		ID:             a.ID,
+		EntityType:     string(a.EntityType()),
+		EntityID:       a.EntityID(),
+		FileName:       a.FileName,
+		ContentType:    a.ContentType,
+		SizeBytes:      a.SizeBytes,
+		ChecksumSHA256: a.ChecksumSHA256,
+		Description:    a.Description,
+		DownloadURL:    "/api/v1/attachments/" + a.ID + "/download",
+		UploadedBy:     a.UploadedBy,
+		CreatedAt:      a.CreatedAt,
+		UpdatedAt:      a.UpdatedAt,
+	}
+}
@@ -0,0 +1,208 @@
+package models
+
+import (
+	"time"
+)
+
+// BankStatement represents a statement imported from the bank for a Bank account
+type BankStatement struct {
+	ID             string     `json:"id" db:"id"`
+	ChurchID       string     `json:"-" db:"church_id"`
+	BankAccountID  string     `json:"bank_account_id" db:"bank_account" binding:"required"`
+	BankAccount    *Account   `json:"bank_account,omitempty" db:"-"`
+	FileName       *string    `json:"file_name" db:"file_name"`
+	Format         string     `json:"format" db:"format" binding:"required"`
+	PeriodStart    *time.Time `json:"period_start" db:"period_start"`
+	PeriodEnd      *time.Time `json:"period_end" db:"period_end"`
+	OpeningBalance *float64   `json:"opening_balance" db:"opening_balance"`
+	ClosingBalance *float64   `json:"closing_balance" db:"closing_balance"`
+	CreatedBy      string     `json:"created_by" db:"created_by"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// StatementFormat represents the supported statement file formats
+type StatementFormat string
+
+const (
+	StatementCSV StatementFormat = "csv"
+	StatementOFX StatementFormat = "ofx"
+)
+
+// ValidateFormat checks if the statement format is supported
+func (s *BankStatement) ValidateFormat() error {
+	switch s.Format {
+	case string(StatementCSV), string(StatementOFX):
+		return nil
+	default:
+		return ErrInvalidStatementFormat
+	}
+}
+
+// BankStatementLine represents a single line on a bank statement. Amount is positive for
+// money into the account and negative for money out.
+type BankStatementLine struct {
+	ID            string     `json:"id" db:"id"`
+	ChurchID      string     `json:"-" db:"church_id"`
+	StatementID   string     `json:"statement_id" db:"statement_id"`
+	BankAccountID string     `json:"bank_account_id" db:"bank_account"`
+	LineDate      time.Time  `json:"line_date" db:"line_date"`
+	Description   *string    `json:"description" db:"description"`
+	Reference     *string    `json:"reference" db:"reference"`
+	Amount        float64    `json:"amount" db:"amount"`
+	Balance       *float64   `json:"balance" db:"balance"`
+	FitID         *string    `json:"fit_id" db:"fit_id"`
+	TransactionID *string    `json:"transaction_id" db:"transaction_id"`
+	MatchType     *string    `json:"match_type" db:"match_type"`
+	MatchedAt     *time.Time `json:"matched_at" db:"matched_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// MatchType represents how a statement line was matched to a transaction
+type MatchType string
+
+const (
+	MatchAuto   MatchType = "auto"
+	MatchManual MatchType = "manual"
+)
+
+// IsMatched reports whether the line has been matched to a transaction
+func (l *BankStatementLine) IsMatched() bool {
+	return l.TransactionID != nil && *l.TransactionID != ""
+}
+
+// ImportBankStatementRequest represents the form fields sent with a statement upload
+type ImportBankStatementRequest struct {
+	BankAccountID  string   `json:"bank_account_id" binding:"required"`
+	Format         string   `json:"format"`
+	FileName       string   `json:"file_name"`
+	OpeningBalance *float64 `json:"opening_balance"`
+	ClosingBalance *float64 `json:"closing_balance"`
+	WindowDays     int      `json:"window_days"`
+}
+
+// Validate validates the ImportBankStatementRequest
+func (req *ImportBankStatementRequest) Validate() error {
+	if req.BankAccountID == "" {
+		return Invalid("bank account is required")
+	}
+	if req.WindowDays < 0 {
+		return Invalid("window days cannot be negative")
+	}
+	statement := BankStatement{Format: req.Format}
+	return statement.ValidateFormat()
+}
+
+// MatchStatementLineRequest represents the request for manually matching a statement line
+type MatchStatementLineRequest struct {
+	TransactionID string `json:"transaction_id" binding:"required"`
+}
+
+// Validate validates the MatchStatementLineRequest
+func (req *MatchStatementLineRequest) Validate() error {
+	if req.TransactionID == "" {
+		return Invalid("transaction is required")
+	}
+	return nil
+}
+
+// BankStatementResponse represents the bank statement response
+type BankStatementResponse struct {
+	ID             string                      `json:"id"`
+	BankAccountID  string                      `json:"bank_account_id"`
+	FileName       *string                     `json:"file_name"`
+	Format         string                      `json:"format"`
+	PeriodStart    *time.Time                  `json:"period_start"`
+	PeriodEnd      *time.Time                  `json:"period_end"`
+	OpeningBalance *float64                    `json:"opening_balance"`
+	ClosingBalance *float64                    `json:"closing_balance"`
+	LineCount      int                         `json:"line_count"`
+	MatchedCount   int                         `json:"matched_count"`
+	SkippedCount   int                         `json:"skipped_count,omitempty"`
+	Lines          []BankStatementLineResponse `json:"lines,omitempty"`
+	CreatedAt      time.Time                   `json:"created_at"`
+	UpdatedAt      time.Time                   `json:"updated_at"`
+}
+
+// ToResponse converts BankStatement to BankStatementResponse
+func (s *BankStatement) ToResponse() *BankStatementResponse {
+	return &BankStatementResponse{
+		ID:             s.ID,
+		BankAccountID:  s.BankAccountID,
+		FileName:       s.FileName,
+		Format:         s.Format,
+		PeriodStart:    s.PeriodStart,
+		PeriodEnd:      s.PeriodEnd,
+		OpeningBalance: s.OpeningBalance,
+		ClosingBalance: s.ClosingBalance,
+		CreatedAt:      s.CreatedAt,
+		UpdatedAt:      s.UpdatedAt,
+	}
+}
+
+// BankStatementLineResponse represents the bank statement line response
+type BankStatementLineResponse struct {
+	ID            string     `json:"id"`
+	StatementID   string     `json:"statement_id"`
+	LineDate      time.Time  `json:"line_date"`
+	Description   *string    `json:"description"`
+	Reference     *string    `json:"reference"`
+	Amount        float64    `json:"amount"`
+	Balance       *float64   `json:"balance"`
+	TransactionID *string    `json:"transaction_id"`
+	MatchType     *string    `json:"match_type"`
+	MatchedAt     *time.Time `json:"matched_at"`
+}
+
+// ToResponse converts BankStatementLine to BankStatementLineResponse
+func (l *BankStatementLine) ToResponse() *BankStatementLineResponse {
+	return &BankStatementLineResponse{
+		ID:            l.ID,
+		StatementID:   l.StatementID,
+		LineDate:      l.LineDate,
+		Description:   l.Description,
+		Reference:     l.Reference,
+		Amount:        l.Amount,
+		Balance:       l.Balance,
+		TransactionID: l.TransactionID,
+		MatchType:     l.MatchType,
+		MatchedAt:     l.MatchedAt,
+	}
+}
+
+// BankBookEntry is the net effect of one transaction on a Bank account as recorded in the
+// books. Amount is positive for money into the account and negative for money out.
+type BankBookEntry struct {
+	TransactionID   string    `json:"transaction_id" db:"transaction_id"`
+	TransactionRef  *string   `json:"transaction_ref" db:"transaction_ref"`
+	TransactionDate time.Time `json:"transaction_date" db:"transaction_date"`
+	TransactionType string    `json:"transaction_type" db:"transaction_type"`
+	Notes           *string   `json:"notes" db:"notes"`
+	Amount          float64   `json:"amount" db:"amount"`
+}
+
+// AutoMatchResult summarises an auto-matching run over a statement
+type AutoMatchResult struct {
+	Matched   int `json:"matched"`
+	Unmatched int `json:"unmatched"`
+}
+
+// BankReconciliation represents the reconciliation of a Bank account's books against its statements
+type BankReconciliation struct {
+	AccountID               string                      `json:"account_id"`
+	AccountName             string                      `json:"account_name"`
+	AsOf                    time.Time                   `json:"as_of"`
+	StatementID             *string                     `json:"statement_id"`
+	StatementBalance        float64                     `json:"statement_balance"`
+	DepositsInTransit       float64                     `json:"deposits_in_transit"`
+	OutstandingPayments     float64                     `json:"outstanding_payments"`
+	ReconciledBalance       float64                     `json:"reconciled_balance"`
+	BookBalance             float64                     `json:"book_balance"`
+	UnrecordedBankItems     float64                     `json:"unrecorded_bank_items"`
+	AdjustedBookBalance     float64                     `json:"adjusted_book_balance"`
+	Difference              float64                     `json:"difference"`
+	IsReconciled            bool                        `json:"is_reconciled"`
+	UnclearedItems          []BankBookEntry             `json:"uncleared_items"`
+	UnmatchedStatementLines []BankStatementLineResponse `json:"unmatched_statement_lines"`
+}
@@ -0,0 +1,340 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/mail"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ValidateBindings checks a request against the binding tags on its fields and returns every
+// field that fails, named by its JSON path (e.g. lines[0].amount). The rules are:
+//   - required: the field is set; strings must not be blank and, unless sent as a pointer,
+//     numbers must not be zero
+//   - min=N, max=N: the length of a string or list, or the value of a number
+//   - email: the string is an email address
+//
+// Optional fields that are left empty are not checked further. Nested structs and lists of
+// structs are checked too.
+func ValidateBindings(req interface{}) []FieldError {
+	v := reflect.ValueOf(req)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	var fields []FieldError
+	validateStruct(v, "", &fields)
+	return fields
+}
+
+// DecodeRequest decodes a JSON request body into req strictly and validates it against its
+// binding tags. Unknown fields, trailing data and values of the wrong type are rejected. The
+// error lists every field at fault where it can.
+func DecodeRequest(body io.Reader, req interface{}) error {
+	if body == nil {
+		return Invalid("Request body is required")
+	}
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return NewError(http.StatusRequestEntityTooLarge, CodeTooLarge, "request body is too large")
+		}
+		return Invalid("Could not read request body")
+	}
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return Invalid("Request body is required")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(req); err != nil {
+		return decodeError(err, raw, req)
+	}
+	if decoder.More() {
+		return Invalid("Request body must hold a single JSON object")
+	}
+
+	if fields := ValidateBindings(req); len(fields) > 0 {
+		return ValidationFailed(fields)
+	}
+	return nil
+}
+
+// decodeError describes why a request body could not be decoded
+func decodeError(err error, raw []byte, req interface{}) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		field := typeErr.Field
+		if field == "" {
+			return Invalid("Request body must be " + jsonType(typeErr.Type))
+		}
+		return ValidationFailed([]FieldError{{Field: field, Message: field + " must be " + jsonType(typeErr.Type)}})
+	}
+
+	var timeErr *time.ParseError
+	if errors.As(err, &timeErr) {
+		return Invalid("invalid date " + timeErr.Value + ", use RFC3339")
+	}
+
+	if strings.HasPrefix(err.Error(), "json: unknown field ") {
+		unknown := unknownFields(raw, req)
+		if len(unknown) == 0 {
+			name := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+			unknown = []string{name}
+		}
+		fields := make([]FieldError, 0, len(unknown))
+		for _, name := range unknown {
+			fields = append(fields, FieldError{Field: name, Message: name + " is not a known field"})
+		}
+		return ValidationFailed(fields)
+	}
+
+	return Invalid("Invalid JSON")
+}
+
+// unknownFields lists the top-level keys of a JSON object that the request does not have
+func unknownFields(raw []byte, req interface{}) []string {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil
+	}
+
+	t := reflect.TypeOf(req)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	known := make(map[string]bool)
+	collectJSONNames(t, known)
+
+	var unknown []string
+	for key := range object {
+		if !known[strings.ToLower(key)] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// collectJSONNames adds the JSON names of a struct's fields, including embedded ones. Names
+// are lowercased as encoding/json matches them case-insensitively.
+func collectJSONNames(t reflect.Type, known map[string]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			collectJSONNames(field.Type, known)
+			continue
+		}
+		if name := jsonName(field); name != "" && field.IsExported() {
+			known[strings.ToLower(name)] = true
+		}
+	}
+}
+
+// jsonType describes a Go type the way a client sees it in JSON
+func jsonType(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a whole number"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "a list"
+	}
+	if t == timeType {
+		return "an RFC3339 date"
+	}
+	return "an object"
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// validateStruct checks each field of a struct, including embedded ones
+func validateStruct(v reflect.Value, prefix string, fields *[]FieldError) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			validateStruct(v.Field(i), prefix, fields)
+			continue
+		}
+
+		name := jsonName(field)
+		if name == "" {
+			continue
+		}
+		validateField(v.Field(i), prefix+name, field.Tag.Get("binding"), fields)
+	}
+}
+
+// validateField checks one value against its binding rules, then the values nested in it
+func validateField(v reflect.Value, name, tag string, fields *[]FieldError) {
+	rules := parseRules(tag)
+	_, required := rules["required"]
+
+	// A pointer that was sent is checked even when it points to zero, e.g. {"count": 0}
+	sent := false
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			if required {
+				*fields = append(*fields, FieldError{Field: name, Message: name + " is required"})
+			}
+			return
+		}
+		v = v.Elem()
+		sent = true
+	}
+
+	if isBlank(v) {
+		// A pointer that was sent satisfies required unless it is a blank string
+		if required && (!sent || v.Kind() == reflect.String) {
+			*fields = append(*fields, FieldError{Field: name, Message: name + " is required"})
+			return
+		}
+		if _, _, number := measureNumber(v); !sent || !number {
+			return
+		}
+	}
+
+	if message := checkRules(v, name, rules); message != "" {
+		*fields = append(*fields, FieldError{Field: name, Message: message})
+		return
+	}
+
+	// Check nested requests
+	switch {
+	case v.Kind() == reflect.Struct && v.Type() != timeType:
+		validateStruct(v, name+".", fields)
+	case v.Kind() == reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			item := v.Index(i)
+			if item.Kind() == reflect.Ptr && !item.IsNil() {
+				item = item.Elem()
+			}
+			if item.Kind() == reflect.Struct && item.Type() != timeType {
+				validateStruct(item, name+"["+strconv.Itoa(i)+"].", fields)
+			}
+		}
+	}
+}
+
+// checkRules returns the message for the first rule the value breaks, or ""
+func checkRules(v reflect.Value, name string, rules map[string]string) string {
+	if _, ok := rules["email"]; ok && v.Kind() == reflect.String {
+		if _, err := mail.ParseAddress(v.String()); err != nil {
+			return name + " must be a valid email address"
+		}
+	}
+
+	for _, rule := range []string{"min", "max"} {
+		param, ok := rules[rule]
+		if !ok {
+			continue
+		}
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			continue
+		}
+
+		size, unit, ok := measure(v)
+		if !ok {
+			continue
+		}
+		if rule == "min" && size < limit {
+			return name + " must be at least " + param + unit
+		}
+		if rule == "max" && size > limit {
+			return name + " must be at most " + param + unit
+		}
+	}
+	return ""
+}
+
+// measure returns what min and max compare against: the length of a string or list, or the
+// value of a number, with the unit it is reported in
+func measure(v reflect.Value) (float64, string, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), " characters", true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), " items", true
+	}
+	return measureNumber(v)
+}
+
+// measureNumber returns the value of a number
+func measureNumber(v reflect.Value) (float64, string, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), "", true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), "", true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), "", true
+	}
+	return 0, "", false
+}
+
+// isBlank reports whether a value was left empty; strings of only spaces count as empty
+func isBlank(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(v.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// parseRules splits a binding tag such as "required,max=100" into its rules
+func parseRules(tag string) map[string]string {
+	rules := make(map[string]string)
+	for _, rule := range strings.Split(tag, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		key, param, _ := strings.Cut(rule, "=")
+		rules[key] = param
+	}
+	return rules
+}
+
+// jsonName returns the name a struct field has in JSON, or "" if it is not decoded
+func jsonName(field reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name
+	}
+	return field.Name
+}
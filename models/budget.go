@@ -0,0 +1,147 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// Budget represents the budgeted amount for an account in one month of a fiscal year
+type Budget struct {
+	ID        string    `json:"id" db:"id"`
+	ChurchID  string    `json:"-" db:"church_id"`
+	AccountID string    `json:"account_id" db:"account" binding:"required"`
+	Account   *Account  `json:"account,omitempty" db:"-"`
+	Year      int       `json:"year" db:"fiscal_year" binding:"required"`
+	Month     int       `json:"month" db:"month" binding:"required,min=1,max=12"`
+	Amount    float64   `json:"amount" db:"amount"`
+	Notes     *string   `json:"notes" db:"notes"`
+	CreatedBy string    `json:"created_by" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// SetBudgetRequest represents the request for setting an account's budget for a year.
+// Either the twelve monthly amounts or an annual amount (spread evenly) must be given.
+type SetBudgetRequest struct {
+	AccountID    string    `json:"account_id" binding:"required"`
+	Year         int       `json:"year" binding:"required"`
+	AnnualAmount *float64  `json:"annual_amount"`
+	Months       []float64 `json:"months"`
+	Notes        *string   `json:"notes"`
+}
+
+// Validate validates the SetBudgetRequest
+func (req *SetBudgetRequest) Validate() error {
+	if req.AccountID == "" {
+		return Invalid("account is required")
+	}
+	if req.Year < 2000 || req.Year > 2100 {
+		return Invalid("invalid budget year")
+	}
+	if req.AnnualAmount == nil && len(req.Months) == 0 {
+		return Invalid("either annual amount or monthly amounts are required")
+	}
+	if req.AnnualAmount != nil && len(req.Months) > 0 {
+		return Invalid("provide either annual amount or monthly amounts, not both")
+	}
+	if req.AnnualAmount != nil && *req.AnnualAmount < 0 {
+		return Invalid("budget amount cannot be negative")
+	}
+	if len(req.Months) > 0 && len(req.Months) != 12 {
+		return Invalid("monthly amounts must cover all 12 months")
+	}
+	for _, amount := range req.Months {
+		if amount < 0 {
+			return Invalid("budget amount cannot be negative")
+		}
+	}
+	return nil
+}
+
+// MonthlyAmounts returns the budget for each month, spreading an annual amount evenly
+// and putting any rounding difference in December
+func (req *SetBudgetRequest) MonthlyAmounts() []float64 {
+	if len(req.Months) == 12 {
+		return req.Months
+	}
+
+	months := make([]float64, 12)
+	monthly := math.Floor(*req.AnnualAmount*100/12) / 100
+	for i := range months {
+		months[i] = monthly
+	}
+	months[11] = math.Round((*req.AnnualAmount-monthly*11)*100) / 100
+	return months
+}
+
+// UpdateBudgetRequest represents the request for updating a single monthly budget line
+type UpdateBudgetRequest struct {
+	Amount *float64 `json:"amount"`
+	Notes  *string  `json:"notes"`
+}
+
+// BudgetResponse represents a monthly budget line response
+type BudgetResponse struct {
+	ID        string    `json:"id"`
+	AccountID string    `json:"account_id"`
+	Year      int       `json:"year"`
+	Month     int       `json:"month"`
+	Amount    float64   `json:"amount"`
+	Notes     *string   `json:"notes"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ToResponse converts Budget to BudgetResponse
+func (b *Budget) ToResponse() *BudgetResponse {
+	return &BudgetResponse{
+		ID:        b.ID,
+		AccountID: b.AccountID,
+		Year:      b.Year,
+		Month:     b.Month,
+		Amount:    b.Amount,
+		Notes:     b.Notes,
+		CreatedAt: b.CreatedAt,
+		UpdatedAt: b.UpdatedAt,
+	}
+}
+
+// AccountBudgetResponse represents an account's budget for a whole year
+type AccountBudgetResponse struct {
+	AccountID   string           `json:"account_id"`
+	AccountName string           `json:"account_name"`
+	AccountType string           `json:"account_type"`
+	Year        int              `json:"year"`
+	Annual      float64          `json:"annual"`
+	Months      []BudgetResponse `json:"months"`
+}
+
+// BudgetImportResult summarises a CSV budget import
+type BudgetImportResult struct {
+	Imported int      `json:"imported"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// BudgetVariance compares budgeted and actual amounts for an account over a period
+type BudgetVariance struct {
+	AccountID       string  `json:"account_id"`
+	AccountName     string  `json:"account_name"`
+	AccountType     string  `json:"account_type"`
+	Code            *string `json:"code"`
+	ParentID        *string `json:"parent_id"`
+	IsHeader        bool    `json:"is_header"`
+	Budget          float64 `json:"budget"`
+	Actual          float64 `json:"actual"`
+	Variance        float64 `json:"variance"`
+	Remaining       float64 `json:"remaining"`
+	PercentConsumed float64 `json:"percent_consumed"`
+	OverBudget      bool    `json:"over_budget"`
+}
+
+// BudgetVarianceReport represents the budget-vs-actual report for a period
+type BudgetVarianceReport struct {
+	Year     int              `json:"year"`
+	Month    int              `json:"month,omitempty"`
+	Income   []BudgetVariance `json:"income"`
+	Expenses []BudgetVariance `json:"expenses"`
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"time"
+)
+
+// Campaign represents a fundraising campaign (e.g. a building project) tied to an income account
+type Campaign struct {
+	ID              string     `json:"id" db:"id"`
+	ChurchID        string     `json:"-" db:"church_id"`
+	CampaignName    string     `json:"campaign_name" db:"campaign_name" binding:"required,max=100"`
+	IncomeAccountID string     `json:"income_account_id" db:"income_account" binding:"required"`
+	IncomeAccount   *Account   `json:"income_account,omitempty" db:"-"`
+	TargetAmount    *float64   `json:"target_amount" db:"target_amount"`
+	StartDate       time.Time  `json:"start_date" db:"start_date"`
+	EndDate         *time.Time `json:"end_date" db:"end_date"`
+	Notes           *string    `json:"notes" db:"notes"`
+	IsActive        bool       `json:"is_active" db:"is_active"`
+	CreatedBy       string     `json:"created_by" db:"created_by"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CreateCampaignRequest represents the request for creating a new campaign
+type CreateCampaignRequest struct {
+	CampaignName    string     `json:"campaign_name" binding:"required,max=100"`
+	IncomeAccountID string     `json:"income_account_id" binding:"required"`
+	TargetAmount    *float64   `json:"target_amount"`
+	StartDate       *time.Time `json:"start_date"`
+	EndDate         *time.Time `json:"end_date"`
+	Notes           *string    `json:"notes"`
+}
+
+// Validate validates the CreateCampaignRequest
+func (req *CreateCampaignRequest) Validate() error {
+	if req.CampaignName == "" {
+		return Invalid("campaign name is required")
+	}
+	if req.IncomeAccountID == "" {
+		return Invalid("income account is required")
+	}
+	if req.TargetAmount != nil && *req.TargetAmount <= 0 {
+		return Invalid("target amount must be greater than zero")
+	}
+	if req.StartDate != nil && req.EndDate != nil && req.EndDate.Before(*req.StartDate) {
+		return Invalid("end date must be after start date")
+	}
+	return nil
+}
+
+// UpdateCampaignRequest represents the request for updating a campaign
+type UpdateCampaignRequest struct {
+	CampaignName *string    `json:"campaign_name" binding:"max=100"`
+	TargetAmount *float64   `json:"target_amount"`
+	StartDate    *time.Time `json:"start_date"`
+	EndDate      *time.Time `json:"end_date"`
+	Notes        *string    `json:"notes"`
+	IsActive     *bool      `json:"is_active"`
+}
+
+// CampaignResponse represents the campaign response
+type CampaignResponse struct {
+	ID              string           `json:"id"`
+	CampaignName    string           `json:"campaign_name"`
+	IncomeAccountID string           `json:"income_account_id"`
+	IncomeAccount   *AccountResponse `json:"income_account,omitempty"`
+	TargetAmount    *float64         `json:"target_amount"`
+	StartDate       time.Time        `json:"start_date"`
+	EndDate         *time.Time       `json:"end_date"`
+	Notes           *string          `json:"notes"`
+	IsActive        bool             `json:"is_active"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
+
+// ToResponse converts Campaign to CampaignResponse
+func (c *Campaign) ToResponse() *CampaignResponse {
+	var incomeAccountResp *AccountResponse
+	if c.IncomeAccount != nil {
+		incomeAccountResp = c.IncomeAccount.ToResponse()
+	}
+
+	return &CampaignResponse{
+		ID:              c.ID,
+		CampaignName:    c.CampaignName,
+		IncomeAccountID: c.IncomeAccountID,
+		IncomeAccount:   incomeAccountResp,
+		TargetAmount:    c.TargetAmount,
+		StartDate:       c.StartDate,
+		EndDate:         c.EndDate,
+		Notes:           c.Notes,
+		IsActive:        c.IsActive,
+		CreatedAt:       c.CreatedAt,
+		UpdatedAt:       c.UpdatedAt,
+	}
+}
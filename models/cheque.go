@@ -0,0 +1,157 @@
+package models
+
+import (
+	"time"
+)
+
+// Cheque represents a cheque issued from a Bank account
+type Cheque struct {
+	ID            string     `json:"id" db:"id"`
+	ChurchID      string     `json:"-" db:"church_id"`
+	ChequeNumber  string     `json:"cheque_number" db:"cheque_number" binding:"required,max=20"`
+	BankAccountID string     `json:"bank_account_id" db:"bank_account" binding:"required"`
+	TransactionID *string    `json:"transaction_id" db:"transaction_id"`
+	PayeeID       *string    `json:"payee_id" db:"payee"`
+	PayeeName     string     `json:"payee_name" db:"payee_name" binding:"required,max=200"`
+	Amount        float64    `json:"amount" db:"amount" binding:"required"`
+	IssueDate     time.Time  `json:"issue_date" db:"issue_date"`
+	Status        string     `json:"status" db:"status"`
+	PresentedDate *time.Time `json:"presented_date" db:"presented_date"`
+	CancelledAt   *time.Time `json:"cancelled_at" db:"cancelled_at"`
+	CancelReason  *string    `json:"cancel_reason" db:"cancel_reason"`
+	Notes         *string    `json:"notes" db:"notes"`
+	CreatedBy     *string    `json:"created_by" db:"created_by"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// ChequeStatus represents where a cheque is between issue and clearing
+type ChequeStatus string
+
+const (
+	ChequeIssued    ChequeStatus = "issued"
+	ChequePresented ChequeStatus = "presented"
+	ChequeCancelled ChequeStatus = "cancelled"
+	ChequeStale     ChequeStatus = "stale"
+)
+
+// ChequeStaleAfterMonths is how long a cheque can be banked before it goes stale
+const ChequeStaleAfterMonths = 6
+
+// StaleDate returns the day the cheque can no longer be banked
+func (c *Cheque) StaleDate() time.Time {
+	return c.IssueDate.AddDate(0, ChequeStaleAfterMonths, 0)
+}
+
+// OutstandingAt reports whether the cheque had been issued but not yet presented or
+// cancelled at the given time
+func (c *Cheque) OutstandingAt(asOf time.Time) bool {
+	if c.IssueDate.After(asOf) {
+		return false
+	}
+	if c.PresentedDate != nil && !c.PresentedDate.After(asOf) {
+		return false
+	}
+	if c.CancelledAt != nil && !c.CancelledAt.After(asOf) {
+		return false
+	}
+	return true
+}
+
+// IssueChequeRequest represents the request for recording an issued cheque. With a
+// transaction the bank account and amount default to the voucher's, and the transaction
+// is marked as paid by this cheque.
+type IssueChequeRequest struct {
+	ChequeNumber  string     `json:"cheque_number" binding:"required,max=20"`
+	BankAccountID string     `json:"bank_account_id"`
+	TransactionID *string    `json:"transaction_id"`
+	PayeeID       *string    `json:"payee_id"`
+	PayeeName     string     `json:"payee_name" binding:"max=200"`
+	Amount        float64    `json:"amount"`
+	IssueDate     *time.Time `json:"issue_date"`
+	Notes         *string    `json:"notes"`
+}
+
+// Validate validates the IssueChequeRequest
+func (req *IssueChequeRequest) Validate() error {
+	if req.ChequeNumber == "" {
+		return Invalid("cheque_number is required")
+	}
+	if len(req.ChequeNumber) > 20 {
+		return Invalid("cheque_number must be at most 20 characters")
+	}
+	if len(req.PayeeName) > 200 {
+		return Invalid("payee_name must be at most 200 characters")
+	}
+	if req.Amount < 0 {
+		return Invalid("amount must be greater than zero")
+	}
+	return nil
+}
+
+// PresentChequeRequest represents the request for marking a cheque as presented
+type PresentChequeRequest struct {
+	PresentedDate *time.Time `json:"presented_date"`
+}
+
+// CancelChequeRequest represents the request for cancelling a cheque
+type CancelChequeRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// ChequeResponse represents the cheque response
+type ChequeResponse struct {
+	ID            string     `json:"id"`
+	ChequeNumber  string     `json:"cheque_number"`
+	BankAccountID string     `json:"bank_account_id"`
+	TransactionID *string    `json:"transaction_id"`
+	PayeeID       *string    `json:"payee_id"`
+	PayeeName     string     `json:"payee_name"`
+	Amount        float64    `json:"amount"`
+	IssueDate     time.Time  `json:"issue_date"`
+	StaleDate     time.Time  `json:"stale_date"`
+	Status        string     `json:"status"`
+	PresentedDate *time.Time `json:"presented_date"`
+	CancelledAt   *time.Time `json:"cancelled_at"`
+	CancelReason  *string    `json:"cancel_reason"`
+	Notes         *string    `json:"notes"`
+	CreatedBy     *string    `json:"created_by"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// OutstandingChequesReport represents the cheques not yet presented on one Bank account
+type OutstandingChequesReport struct {
+	BankAccountID    string           `json:"bank_account_id"`
+	BankAccountName  string           `json:"bank_account_name"`
+	AsOf             time.Time        `json:"as_of"`
+	OutstandingCount int              `json:"outstanding_count"`
+	OutstandingTotal float64          `json:"outstanding_total"`
+	StaleCount       int              `json:"stale_count"`
+	StaleTotal       float64          `json:"stale_total"`
+	Cheques          []ChequeResponse `json:"cheques"`
+	StaleCheques     []ChequeResponse `json:"stale_cheques"`
+}
+
+// ToResponse converts Cheque to ChequeResponse
+func (c *Cheque) ToResponse() *ChequeResponse {
+	return &ChequeResponse{
+		ID:            c.ID,
+		ChequeNumber:  c.ChequeNumber,
+		BankAccountID: c.BankAccountID,
+		TransactionID: c.TransactionID,
+		PayeeID:       c.PayeeID,
+		PayeeName:     c.PayeeName,
+		Amount:        c.Amount,
+		IssueDate:     c.IssueDate,
+		StaleDate:     c.StaleDate(),
+		Status:        c.Status,
+		PresentedDate: c.PresentedDate,
+		CancelledAt:   c.CancelledAt,
+		CancelReason:  c.CancelReason,
+		Notes:         c.Notes,
+		CreatedBy:     c.CreatedBy,
+		CreatedAt:     c.CreatedAt,
+		UpdatedAt:     c.UpdatedAt,
+	}
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Church represents one church served by the deployment. Everything else the API stores
+// belongs to exactly one church.
+type Church struct {
+	ID             string    `json:"id" db:"id"`
+	Name           string    `json:"name" db:"name" binding:"required,max=100"`
+	Code           string    `json:"code" db:"code" binding:"required,max=20"`
+	MpesaShortcode *string   `json:"mpesa_shortcode" db:"mpesa_shortcode" binding:"max=20"`
+	DistrictID     *string   `json:"district_id" db:"district_id"`
+	IsActive       bool      `json:"is_active" db:"is_active"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ChurchUser represents a user's membership of a church, with the role they work under
+// there
+type ChurchUser struct {
+	ID        string    `json:"id" db:"id"`
+	ChurchID  string    `json:"church_id" db:"church_id"`
+	UserID    string    `json:"user_id" db:"user_id"`
+	Role      string    `json:"role" db:"role"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+var churchCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{1,20}$`)
+
+// NormalizeChurchCode upper-cases a church code and checks its characters
+func NormalizeChurchCode(code string) (string, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(code))
+	if !churchCodePattern.MatchString(normalized) {
+		return "", Invalid("code must be 1 to 20 letters, digits, dashes or underscores")
+	}
+	return normalized, nil
+}
+
+// CreateChurchRequest represents the request for adding a church
+type CreateChurchRequest struct {
+	Name           string  `json:"name" binding:"required,max=100"`
+	Code           string  `json:"code" binding:"required,max=20"`
+	MpesaShortcode *string `json:"mpesa_shortcode" binding:"max=20"`
+}
+
+// Validate validates the CreateChurchRequest
+func (req *CreateChurchRequest) Validate() error {
+	if strings.TrimSpace(req.Name) == "" {
+		return Invalid("name is required")
+	}
+	if len(req.Name) > 100 {
+		return Invalid("name must be at most 100 characters")
+	}
+	_, err := NormalizeChurchCode(req.Code)
+	return err
+}
+
+// UpdateChurchRequest represents the request for updating a church; the code is fixed
+type UpdateChurchRequest struct {
+	Name           *string `json:"name" binding:"max=100"`
+	MpesaShortcode *string `json:"mpesa_shortcode" binding:"max=20"`
+	IsActive       *bool   `json:"is_active"`
+}
+
+// AddChurchUserRequest represents the request for giving a user access to a church
+type AddChurchUserRequest struct {
+	UserID string  `json:"user_id" binding:"required"`
+	Role   *string `json:"role"`
+}
+
+// Validate validates the AddChurchUserRequest
+func (req *AddChurchUserRequest) Validate() error {
+	if req.UserID == "" {
+		return Invalid("user_id is required")
+	}
+	if req.Role != nil {
+		return (&User{Role: *req.Role}).ValidateRole()
+	}
+	return nil
+}
+
+// ChurchResponse represents the church response, with the caller's role in it when known
+type ChurchResponse struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Code           string    `json:"code"`
+	MpesaShortcode *string   `json:"mpesa_shortcode"`
+	DistrictID     *string   `json:"district_id"`
+	IsActive       bool      `json:"is_active"`
+	Role           string    `json:"role,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// ToResponse converts Church to ChurchResponse
+func (c *Church) ToResponse() *ChurchResponse {
+	return &ChurchResponse{
+		ID:             c.ID,
+		Name:           c.Name,
+		Code:           c.Code,
+		MpesaShortcode: c.MpesaShortcode,
+		DistrictID:     c.DistrictID,
+		IsActive:       c.IsActive,
+		CreatedAt:      c.CreatedAt,
+		UpdatedAt:      c.UpdatedAt,
+	}
+}
+
+// ChurchUserResponse represents a user who can work in a church
+type ChurchUserResponse struct {
+	UserID   string `json:"user_id" db:"user_id"`
+	Username string `json:"username" db:"username"`
+	FullName string `json:"full_name" db:"full_name"`
+	Role     string `json:"role" db:"role"`
+	IsActive bool   `json:"is_active" db:"is_active"`
+}
+
+// UserChurch represents a church a user can work in, with their role there
+type UserChurch struct {
+	Church
+	Role string `db:"role"`
+}
@@ -0,0 +1,205 @@
+package models
+
+import (
+	"time"
+)
+
+// CollectionSession represents the offering collected in one service. Its receipts are
+// held as drafts until two different counters sign off on the count.
+type CollectionSession struct {
+	ID            string     `json:"id" db:"id"`
+	ChurchID      string     `json:"-" db:"church_id"`
+	ServiceName   string     `json:"service_name" db:"service_name" binding:"required,max=100"`
+	ServiceDate   time.Time  `json:"service_date" db:"service_date" binding:"required"`
+	BankAccountID string     `json:"bank_account_id" db:"bank_account" binding:"required"`
+	Status        string     `json:"status" db:"status"`
+	Notes         *string    `json:"notes" db:"notes"`
+	CreatedBy     *string    `json:"created_by" db:"created_by"`
+	PostedAt      *time.Time `json:"posted_at" db:"posted_at"`
+	CancelledBy   *string    `json:"cancelled_by" db:"cancelled_by"`
+	CancelledAt   *time.Time `json:"cancelled_at" db:"cancelled_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CollectionDenomination represents how many of one note or coin were counted
+type CollectionDenomination struct {
+	ID           string  `json:"id" db:"id"`
+	ChurchID     string  `json:"-" db:"church_id"`
+	SessionID    string  `json:"session_id" db:"session_id"`
+	Denomination float64 `json:"denomination" db:"denomination"`
+	Quantity     int     `json:"quantity" db:"quantity"`
+}
+
+// CollectionSignOff represents the totals one counter confirmed for a session
+type CollectionSignOff struct {
+	ID            string    `json:"id" db:"id"`
+	ChurchID      string    `json:"-" db:"church_id"`
+	SessionID     string    `json:"session_id" db:"session_id"`
+	UserID        string    `json:"user_id" db:"user_id"`
+	CashTotal     float64   `json:"cash_total" db:"cash_total"`
+	ReceiptsTotal float64   `json:"receipts_total" db:"receipts_total"`
+	SignedAt      time.Time `json:"signed_at" db:"signed_at"`
+}
+
+// CollectionSessionStatus represents where a collection session is
+type CollectionSessionStatus string
+
+const (
+	CollectionOpen      CollectionSessionStatus = "open"
+	CollectionPosted    CollectionSessionStatus = "posted"
+	CollectionCancelled CollectionSessionStatus = "cancelled"
+)
+
+// CollectionSignOffsRequired is how many different people must confirm a count
+const CollectionSignOffsRequired = 2
+
+// Denominations lists the Kenya shilling notes and coins accepted in a cash count
+var Denominations = []float64{1000, 500, 200, 100, 50, 20, 10, 5, 1}
+
+// ValidateDenomination checks that the value is a note or coin in circulation
+func ValidateDenomination(value float64) error {
+	for _, d := range Denominations {
+		if d == value {
+			return nil
+		}
+	}
+	return Invalid("denomination must be one of 1000, 500, 200, 100, 50, 20, 10, 5 or 1")
+}
+
+// CreateCollectionSessionRequest represents the request for opening a collection session
+type CreateCollectionSessionRequest struct {
+	ServiceName   string     `json:"service_name" binding:"required,max=100"`
+	ServiceDate   *time.Time `json:"service_date"`
+	BankAccountID string     `json:"bank_account_id" binding:"required"`
+	Notes         *string    `json:"notes"`
+}
+
+// Validate validates the CreateCollectionSessionRequest
+func (req *CreateCollectionSessionRequest) Validate() error {
+	if req.ServiceName == "" {
+		return Invalid("service_name is required")
+	}
+	if len(req.ServiceName) > 100 {
+		return Invalid("service_name must be at most 100 characters")
+	}
+	if req.BankAccountID == "" {
+		return Invalid("bank_account_id is required")
+	}
+	return nil
+}
+
+// CollectionReceiptLine represents one income account on a session receipt
+type CollectionReceiptLine struct {
+	IncomeAccountID string  `json:"income_account_id" binding:"required"`
+	FundID          *string `json:"fund_id"`
+	Amount          float64 `json:"amount" binding:"required"`
+}
+
+// AddCollectionReceiptRequest represents an envelope or the loose offering added to a
+// session. Leave out member_id for loose offering; payment_method defaults to cash.
+type AddCollectionReceiptRequest struct {
+	MemberID         *string                 `json:"member_id"`
+	PaymentMethod    *string                 `json:"payment_method"`
+	PaymentReference *string                 `json:"payment_reference" binding:"max=100"`
+	Notes            *string                 `json:"notes"`
+	Lines            []CollectionReceiptLine `json:"lines" binding:"required"`
+}
+
+// Validate validates the AddCollectionReceiptRequest
+func (req *AddCollectionReceiptRequest) Validate() error {
+	if len(req.Lines) == 0 {
+		return Invalid("at least one line is required")
+	}
+	for _, line := range req.Lines {
+		if line.IncomeAccountID == "" {
+			return Invalid("income_account_id is required on every line")
+		}
+		if line.Amount <= 0 {
+			return Invalid("amount must be greater than zero on every line")
+		}
+	}
+	return nil
+}
+
+// DenominationCount represents one row of a cash count
+type DenominationCount struct {
+	Denomination float64 `json:"denomination" binding:"required"`
+	Quantity     int     `json:"quantity"`
+}
+
+// SetDenominationsRequest represents the request for recording the cash count
+type SetDenominationsRequest struct {
+	Denominations []DenominationCount `json:"denominations" binding:"required"`
+}
+
+// Validate validates the SetDenominationsRequest
+func (req *SetDenominationsRequest) Validate() error {
+	seen := make(map[float64]bool)
+	for _, d := range req.Denominations {
+		if err := ValidateDenomination(d.Denomination); err != nil {
+			return err
+		}
+		if d.Quantity < 0 {
+			return Invalid("quantity must not be negative")
+		}
+		if seen[d.Denomination] {
+			return Invalid("each denomination can only be listed once")
+		}
+		seen[d.Denomination] = true
+	}
+	return nil
+}
+
+// SignOffCollectionRequest represents the totals a counter confirms
+type SignOffCollectionRequest struct {
+	CashTotal     float64 `json:"cash_total"`
+	ReceiptsTotal float64 `json:"receipts_total"`
+}
+
+// CollectionSessionResponse represents a collection session with its receipts, cash count
+// and sign-offs
+type CollectionSessionResponse struct {
+	ID             string                   `json:"id"`
+	ServiceName    string                   `json:"service_name"`
+	ServiceDate    time.Time                `json:"service_date"`
+	BankAccountID  string                   `json:"bank_account_id"`
+	Status         string                   `json:"status"`
+	Notes          *string                  `json:"notes"`
+	CreatedBy      *string                  `json:"created_by"`
+	PostedAt       *time.Time               `json:"posted_at"`
+	CancelledBy    *string                  `json:"cancelled_by"`
+	CancelledAt    *time.Time               `json:"cancelled_at"`
+	ReceiptsTotal  float64                  `json:"receipts_total"`
+	CashReceipts   float64                  `json:"cash_receipts"`
+	CashCounted    float64                  `json:"cash_counted"`
+	CashVariance   float64                  `json:"cash_variance"`
+	Receipts       []TransactionResponse    `json:"receipts"`
+	Denominations  []CollectionDenomination `json:"denominations"`
+	SignOffs       []CollectionSignOff      `json:"sign_offs"`
+	SignOffsNeeded int                      `json:"sign_offs_needed"`
+	CreatedAt      time.Time                `json:"created_at"`
+	UpdatedAt      time.Time                `json:"updated_at"`
+}
+
+// ToResponse converts CollectionSession to CollectionSessionResponse; the totals, receipts,
+// count and sign-offs are filled in by the service
+func (c *CollectionSession) ToResponse() *CollectionSessionResponse {
+	return &CollectionSessionResponse{
+		ID:            c.ID,
+		ServiceName:   c.ServiceName,
+		ServiceDate:   c.ServiceDate,
+		BankAccountID: c.BankAccountID,
+		Status:        c.Status,
+		Notes:         c.Notes,
+		CreatedBy:     c.CreatedBy,
+		PostedAt:      c.PostedAt,
+		CancelledBy:   c.CancelledBy,
+		CancelledAt:   c.CancelledAt,
+		Receipts:      []TransactionResponse{},
+		Denominations: []CollectionDenomination{},
+		SignOffs:      []CollectionSignOff{},
+		CreatedAt:     c.CreatedAt,
+		UpdatedAt:     c.UpdatedAt,
+	}
+}
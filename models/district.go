@@ -0,0 +1,169 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// District represents a group of churches overseen together, e.g. by a conference district
+type District struct {
+	ID        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name" binding:"required,max=100"`
+	Code      string    `json:"code" db:"code" binding:"required,max=20"`
+	IsActive  bool      `json:"is_active" db:"is_active"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// DistrictUser represents a user's role in a district
+type DistrictUser struct {
+	ID         string    `json:"id" db:"id"`
+	DistrictID string    `json:"district_id" db:"district_id"`
+	UserID     string    `json:"user_id" db:"user_id"`
+	Role       string    `json:"role" db:"role"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// DistrictRole represents the roles a user can hold in a district
+type DistrictRole string
+
+const (
+	// DistrictAdmin manages the district's churches and users, and reads its reports
+	DistrictAdmin DistrictRole = "DistrictAdmin"
+	// DistrictTreasurer reads the district's reports
+	DistrictTreasurer DistrictRole = "DistrictTreasurer"
+)
+
+// ValidateDistrictRole checks if the district role is valid
+func ValidateDistrictRole(role string) error {
+	switch role {
+	case string(DistrictAdmin), string(DistrictTreasurer):
+		return nil
+	default:
+		return Invalid("role must be DistrictAdmin or DistrictTreasurer")
+	}
+}
+
+// CreateDistrictRequest represents the request for adding a district
+type CreateDistrictRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+	Code string `json:"code" binding:"required,max=20"`
+}
+
+// Validate validates the CreateDistrictRequest
+func (req *CreateDistrictRequest) Validate() error {
+	if strings.TrimSpace(req.Name) == "" {
+		return Invalid("name is required")
+	}
+	if len(req.Name) > 100 {
+		return Invalid("name must be at most 100 characters")
+	}
+	_, err := NormalizeChurchCode(req.Code)
+	return err
+}
+
+// AddDistrictChurchRequest represents the request for placing a church in a district
+type AddDistrictChurchRequest struct {
+	ChurchID string `json:"church_id" binding:"required"`
+}
+
+// AddDistrictUserRequest represents the request for giving a user a role in a district
+type AddDistrictUserRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+	Role   string `json:"role" binding:"required"`
+}
+
+// Validate validates the AddDistrictUserRequest
+func (req *AddDistrictUserRequest) Validate() error {
+	if req.UserID == "" {
+		return Invalid("user_id is required")
+	}
+	return ValidateDistrictRole(req.Role)
+}
+
+// DistrictResponse represents the district response, with its churches and the caller's
+// role in it when known
+type DistrictResponse struct {
+	ID        string           `json:"id"`
+	Name      string           `json:"name"`
+	Code      string           `json:"code"`
+	IsActive  bool             `json:"is_active"`
+	Role      string           `json:"role,omitempty"`
+	Churches  []ChurchResponse `json:"churches,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// ToResponse converts District to DistrictResponse
+func (d *District) ToResponse() *DistrictResponse {
+	return &DistrictResponse{
+		ID:        d.ID,
+		Name:      d.Name,
+		Code:      d.Code,
+		IsActive:  d.IsActive,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
+}
+
+// DistrictUserResponse represents a user with a role in a district
+type DistrictUserResponse struct {
+	UserID   string `json:"user_id" db:"user_id"`
+	Username string `json:"username" db:"username"`
+	FullName string `json:"full_name" db:"full_name"`
+	Role     string `json:"role" db:"role"`
+	IsActive bool   `json:"is_active" db:"is_active"`
+}
+
+// UserDistrict represents a district a user holds a role in
+type UserDistrict struct {
+	District
+	Role string `db:"role"`
+}
+
+// IncomeLine represents the posted income to one account over a period, with the top-level
+// account it rolls up to as its category
+type IncomeLine struct {
+	AccountID    string   `json:"account_id" db:"account_id"`
+	Code         *string  `json:"code" db:"code"`
+	AccountName  string   `json:"account_name" db:"account_name"`
+	LocalShare   *float64 `json:"local_share" db:"local_share"`
+	CategoryID   string   `json:"category_id" db:"category_id"`
+	CategoryCode *string  `json:"category_code" db:"category_code"`
+	CategoryName string   `json:"category_name" db:"category_name"`
+	Amount       float64  `json:"amount" db:"amount"`
+}
+
+// DistrictCategoryTotal represents the income to one account category, split into what the
+// church keeps and what it remits
+type DistrictCategoryTotal struct {
+	Code     *string `json:"code"`
+	Name     string  `json:"name"`
+	Income   float64 `json:"income"`
+	Retained float64 `json:"retained"`
+	Remitted float64 `json:"remitted"`
+}
+
+// DistrictChurchReport represents one church's income over the period
+type DistrictChurchReport struct {
+	ChurchID   string                  `json:"church_id"`
+	Code       string                  `json:"code"`
+	Name       string                  `json:"name"`
+	Income     float64                 `json:"income"`
+	Retained   float64                 `json:"retained"`
+	Remitted   float64                 `json:"remitted"`
+	Categories []DistrictCategoryTotal `json:"categories"`
+}
+
+// DistrictReport represents the consolidated income of a district's churches over a period.
+// Retained is the local share of income; remitted is the rest, due to the conference.
+type DistrictReport struct {
+	District   DistrictResponse        `json:"district"`
+	StartDate  time.Time               `json:"start_date"`
+	EndDate    time.Time               `json:"end_date"`
+	Income     float64                 `json:"income"`
+	Retained   float64                 `json:"retained"`
+	Remitted   float64                 `json:"remitted"`
+	Categories []DistrictCategoryTotal `json:"categories"`
+	Churches   []DistrictChurchReport  `json:"churches"`
+}
@@ -0,0 +1,152 @@
+package models
+
+import (
+	"time"
+)
+
+// Email represents a message in the outbox
+type Email struct {
+	ID            string     `json:"id" db:"id"`
+	ChurchID      string     `json:"-" db:"church_id"`
+	Kind          string     `json:"kind" db:"kind"`
+	MemberID      *string    `json:"member_id" db:"member"`
+	Recipient     string     `json:"recipient" db:"recipient"`
+	Subject       string     `json:"subject" db:"subject"`
+	BodyText      string     `json:"body_text" db:"body_text"`
+	BodyHTML      *string    `json:"body_html" db:"body_html"`
+	Status        string     `json:"status" db:"status"`
+	Attempts      int        `json:"attempts" db:"attempts"`
+	LastError     *string    `json:"last_error" db:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" db:"next_attempt_at"`
+	SentAt        *time.Time `json:"sent_at" db:"sent_at"`
+	BouncedAt     *time.Time `json:"bounced_at" db:"bounced_at"`
+	CreatedBy     string     `json:"created_by" db:"created_by"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// EmailAttachment represents a file attached to an outbox email
+type EmailAttachment struct {
+	ID          string    `json:"id" db:"id"`
+	ChurchID    string    `json:"-" db:"church_id"`
+	EmailID     string    `json:"email_id" db:"email_id"`
+	FileName    string    `json:"file_name" db:"file_name"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Data        []byte    `json:"-" db:"data"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// EmailKind represents what an email carries
+type EmailKind string
+
+const (
+	EmailReceipt       EmailKind = "receipt"
+	EmailStatement     EmailKind = "statement"
+	EmailMonthlyReport EmailKind = "monthly_report"
+)
+
+// EmailStatus represents the delivery state of an email
+type EmailStatus string
+
+const (
+	EmailQueued  EmailStatus = "queued"
+	EmailSent    EmailStatus = "sent"
+	EmailFailed  EmailStatus = "failed"
+	EmailBounced EmailStatus = "bounced"
+)
+
+// SendStatementRequest represents the request for emailing a member their giving statement
+type SendStatementRequest struct {
+	StartDate time.Time `json:"start_date" binding:"required"`
+	EndDate   time.Time `json:"end_date" binding:"required"`
+}
+
+// Validate validates the SendStatementRequest
+func (req *SendStatementRequest) Validate() error {
+	if req.StartDate.IsZero() || req.EndDate.IsZero() {
+		return Invalid("start_date and end_date are required")
+	}
+	if req.EndDate.Before(req.StartDate) {
+		return Invalid("end_date must not be before start_date")
+	}
+	return nil
+}
+
+// SendMonthlyReportRequest represents the request for emailing the monthly treasurer report.
+// Without recipients it goes to every active Treasurer.
+type SendMonthlyReportRequest struct {
+	Year       int      `json:"year" binding:"required"`
+	Month      int      `json:"month" binding:"required,min=1,max=12"`
+	Recipients []string `json:"recipients"`
+}
+
+// Validate validates the SendMonthlyReportRequest
+func (req *SendMonthlyReportRequest) Validate() error {
+	if req.Year < 2000 || req.Year > 2100 {
+		return Invalid("year must be between 2000 and 2100")
+	}
+	if req.Month < 1 || req.Month > 12 {
+		return Invalid("month must be between 1 and 12")
+	}
+	return nil
+}
+
+// MarkBouncedRequest represents a bounce reported after the email was accepted
+type MarkBouncedRequest struct {
+	Reason string `json:"reason"`
+}
+
+// EmailAttachmentResponse represents the attachment metadata returned with an email
+type EmailAttachmentResponse struct {
+	ID          string `json:"id"`
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Size        int    `json:"size"`
+}
+
+// EmailResponse represents the outbox email response
+type EmailResponse struct {
+	ID            string                    `json:"id"`
+	Kind          string                    `json:"kind"`
+	MemberID      *string                   `json:"member_id"`
+	Recipient     string                    `json:"recipient"`
+	Subject       string                    `json:"subject"`
+	Status        string                    `json:"status"`
+	Attempts      int                       `json:"attempts"`
+	LastError     *string                   `json:"last_error"`
+	NextAttemptAt time.Time                 `json:"next_attempt_at"`
+	SentAt        *time.Time                `json:"sent_at"`
+	BouncedAt     *time.Time                `json:"bounced_at"`
+	Attachments   []EmailAttachmentResponse `json:"attachments,omitempty"`
+	CreatedAt     time.Time                 `json:"created_at"`
+	UpdatedAt     time.Time                 `json:"updated_at"`
+}
+
+// ToResponse converts Email to EmailResponse
+func (e *Email) ToResponse() *EmailResponse {
+	return &EmailResponse{
+		ID:            e.ID,
+		Kind:          e.Kind,
+		MemberID:      e.MemberID,
+		Recipient:     e.Recipient,
+		Subject:       e.Subject,
+		Status:        e.Status,
+		Attempts:      e.Attempts,
+		LastError:     e.LastError,
+		NextAttemptAt: e.NextAttemptAt,
+		SentAt:        e.SentAt,
+		BouncedAt:     e.BouncedAt,
+		CreatedAt:     e.CreatedAt,
+		UpdatedAt:     e.UpdatedAt,
+	}
+}
+
+// ToResponse converts EmailAttachment to EmailAttachmentResponse
+func (a *EmailAttachment) ToResponse() *EmailAttachmentResponse {
+	return &EmailAttachmentResponse{
+		ID:          a.ID,
+		FileName:    a.FileName,
+		ContentType: a.ContentType,
+		Size:        len(a.Data),
+	}
+}
@@ -1,30 +1,177 @@
 package models
 
-import "errors"
+import "net/http"
+
+// ErrorCode is a stable, machine-readable identifier for a kind of error
+type ErrorCode string
+
+const (
+	CodeInvalidRequest   ErrorCode = "invalid_request"
+	CodeValidationFailed ErrorCode = "validation_failed"
+	CodeUnauthorized     ErrorCode = "unauthorized"
+	CodeForbidden        ErrorCode = "forbidden"
+	CodeNotFound         ErrorCode = "not_found"
+	CodeConflict         ErrorCode = "conflict"
+	CodeTooLarge         ErrorCode = "too_large"
+	CodeUnsupportedMedia ErrorCode = "unsupported_media_type"
+	CodeUnprocessable    ErrorCode = "unprocessable"
+	CodeRateLimited      ErrorCode = "rate_limited"
+	CodeTimeout          ErrorCode = "timeout"
+	CodeInternal         ErrorCode = "internal_error"
+	CodeUnavailable      ErrorCode = "unavailable"
+)
+
+// FieldError describes what is wrong with one request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// AppError is an error the API reports to clients: a stable code, a message, the HTTP
+// status it is returned with and, for validation errors, the fields at fault. Err is the
+// sentinel or cause it wraps, so errors.Is keeps working through it.
+type AppError struct {
+	Status  int
+	Code    ErrorCode
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+func (e *AppError) Error() string {
+	return e.Message
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// NewError creates an AppError
+func NewError(status int, code ErrorCode, message string) *AppError {
+	return &AppError{Status: status, Code: code, Message: message}
+}
+
+// Invalid reports a request that cannot be carried out as asked
+func Invalid(message string) *AppError {
+	return NewError(http.StatusBadRequest, CodeInvalidRequest, message)
+}
+
+// NotFound reports a record that does not exist
+func NotFound(message string) *AppError {
+	return NewError(http.StatusNotFound, CodeNotFound, message)
+}
+
+// Conflict reports a request that clashes with the current state of a record
+func Conflict(message string) *AppError {
+	return NewError(http.StatusConflict, CodeConflict, message)
+}
+
+// Forbidden reports an action the user is not allowed to take
+func Forbidden(message string) *AppError {
+	return NewError(http.StatusForbidden, CodeForbidden, message)
+}
+
+// Unauthorized reports a request whose credentials are missing or wrong
+func Unauthorized(message string) *AppError {
+	return NewError(http.StatusUnauthorized, CodeUnauthorized, message)
+}
+
+// Unavailable reports a feature that is not configured on this server
+func Unavailable(message string) *AppError {
+	return NewError(http.StatusServiceUnavailable, CodeUnavailable, message)
+}
+
+// Internal reports a failure on the server's side
+func Internal(message string) *AppError {
+	return NewError(http.StatusInternalServerError, CodeInternal, message)
+}
+
+// ValidationFailed reports every field that failed validation
+func ValidationFailed(fields []FieldError) *AppError {
+	message := "validation failed"
+	if len(fields) == 1 {
+		message = fields[0].Message
+	}
+	return &AppError{Status: http.StatusBadRequest, Code: CodeValidationFailed, Message: message, Fields: fields}
+}
+
+// Wrap gives a more specific message to a sentinel error, keeping its status and code, and
+// keeping it matchable with errors.Is
+func Wrap(sentinel *AppError, message string) *AppError {
+	return &AppError{Status: sentinel.Status, Code: sentinel.Code, Message: message, Err: sentinel}
+}
 
 // Common errors for the models package
 var (
-	ErrInvalidRole         = errors.New("invalid user role")
-	ErrUserNotFound        = errors.New("user not found")
-	ErrMemberNotFound      = errors.New("member not found")
-	ErrGroupNotFound       = errors.New("group not found")
-	ErrAccountNotFound     = errors.New("account not found")
-	ErrTransactionNotFound = errors.New("transaction not found")
-	ErrReceiptNotFound     = errors.New("receipt not found")
-	ErrExpenditureNotFound = errors.New("expenditure not found")
-	ErrTransferNotFound    = errors.New("transfer not found")
+	ErrInvalidRole              = Invalid("invalid user role")
+	ErrUserNotFound             = NotFound("user not found")
+	ErrMemberNotFound           = NotFound("member not found")
+	ErrGroupNotFound            = NotFound("group not found")
+	ErrAccountNotFound          = NotFound("account not found")
+	ErrTransactionNotFound      = NotFound("transaction not found")
+	ErrReceiptNotFound          = NotFound("receipt not found")
+	ErrExpenditureNotFound      = NotFound("expenditure not found")
+	ErrTransferNotFound         = NotFound("transfer not found")
+	ErrCampaignNotFound         = NotFound("campaign not found")
+	ErrPledgeNotFound           = NotFound("pledge not found")
+	ErrBudgetNotFound           = NotFound("budget not found")
+	ErrStatementNotFound        = NotFound("bank statement not found")
+	ErrStatementLineNotFound    = NotFound("statement line not found")
+	ErrChequeNotFound           = NotFound("cheque not found")
+	ErrChurchNotFound           = NotFound("church not found")
+	ErrDistrictNotFound         = NotFound("district not found")
+	ErrPayeeNotFound            = NotFound("payee not found")
+	ErrFundNotFound             = NotFound("fund not found")
+	ErrImprestNotFound          = NotFound("imprest not found")
+	ErrReplenishmentNotFound    = NotFound("replenishment not found")
+	ErrPettyCashVoucherNotFound = NotFound("petty cash voucher not found")
+	ErrVoucherNotFound          = NotFound("voucher not found")
+	ErrApprovalPolicyNotFound   = NotFound("approval policy not found")
+	ErrAttachmentNotFound       = NotFound("attachment not found")
+	ErrRecurringNotFound        = NotFound("recurring template not found")
+	ErrRecurringRunNotFound     = NotFound("recurring run not found")
+	ErrCollectionNotFound       = NotFound("collection session not found")
+	ErrMpesaNotFound            = NotFound("mpesa transaction not found")
+	ErrImportNotFound           = NotFound("mobile money import not found")
+	ErrImportLineNotFound       = NotFound("mobile money line not found")
+	ErrSMSNotFound              = NotFound("sms notification not found")
+	ErrEmailNotFound            = NotFound("email not found")
+	ErrTemplateNotFound         = NotFound("template not found")
+
+	// Access errors
+	ErrBadCredentials    = Unauthorized("invalid username or password")
+	ErrChurchAdminOnly   = Forbidden("only the church's admins can manage it")
+	ErrDistrictAdminOnly = Forbidden("only the district's admins can manage it")
+	ErrUserOfOtherChurch = Forbidden("only a deployment admin can give a user of another church access")
+
+	// Voucher workflow errors
+	ErrVoucherNotEditable = Conflict("voucher can only be changed while it is a draft")
+	ErrSelfApproval       = Forbidden("a voucher cannot be approved by the user who created it")
+
+	// Collection session errors
+	ErrCollectionReceipt = Conflict("receipts of a collection session can only be changed through the session")
+
+	// Recurring template errors
+	ErrRecurringNoCreator = Conflict("recurring template has no creator to record its transactions; recreate it")
 
 	// Validation errors
-	ErrInvalidEmail           = errors.New("invalid email format")
-	ErrInvalidPhone           = errors.New("invalid phone number format")
-	ErrInvalidAmount          = errors.New("invalid amount")
-	ErrInvalidAccountType     = errors.New("invalid account type")
-	ErrInvalidTransactionType = errors.New("invalid transaction type")
+	ErrInvalidEmail           = Invalid("invalid email format")
+	ErrInvalidPhone           = Invalid("invalid phone number format")
+	ErrInvalidAmount          = Invalid("invalid amount")
+	ErrInvalidAccountType     = Invalid("invalid account type")
+	ErrInvalidTransactionType = Invalid("invalid transaction type")
+	ErrInvalidPledgeFrequency = Invalid("invalid pledge frequency")
+	ErrInvalidStatementFormat = Invalid("invalid statement format, use csv or ofx")
+	ErrInvalidProvider        = Invalid("invalid mobile money provider, use mpesa or airtel")
+	ErrInvalidPaymentMethod   = Invalid("invalid payment method, use cash, cheque, mpesa, bank_transfer or card")
 )
 
-// ErrorResponse represents a standard error response
+// ErrorResponse represents a standard error response: the message, a stable code and,
+// for validation errors, the fields at fault
 type ErrorResponse struct {
-	Error string `json:"error"`
+	Error  string       `json:"error"`
+	Code   ErrorCode    `json:"code,omitempty"`
+	Fields []FieldError `json:"fields,omitempty"`
 }
 
 // SuccessResponse represents a standard success response
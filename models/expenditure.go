@@ -7,9 +7,12 @@ import (
 // Expenditure represents expenses and withdrawals from accounts
 type Expenditure struct {
 	ID            string      `json:"id" db:"id"`
+	ChurchID      string      `json:"-" db:"church_id"`
 	TransactionID string      `json:"transaction_id" db:"transaction_id" binding:"required"`
 	Transaction   *Transaction `json:"transaction,omitempty" db:"-"`
-	Particulars   string      `json:"particulars" db:"perticulars" binding:"required,max=255"`
+	Particulars   string      `json:"particulars" db:"particulars" binding:"required,max=255"`
+	PayeeID       *string     `json:"payee_id" db:"payee"`
+	FundID        *string     `json:"fund_id" db:"fund"`
 	BankAccountID string      `json:"bank_account_id" db:"bank_account" binding:"required"`
 	BankAccount   *Account    `json:"bank_account,omitempty" db:"-"`
 	Amount        float64     `json:"amount" db:"amount" binding:"required"`
@@ -22,6 +25,8 @@ type CreateExpenditureRequest struct {
 	TransactionID string  `json:"transaction_id" binding:"required"`
 	Particulars   string  `json:"particulars" binding:"required,max=255"`
 	BankAccountID string  `json:"bank_account_id" binding:"required"`
+	PayeeID       *string `json:"payee_id"`
+	FundID        *string `json:"fund_id"`
 	Amount        float64 `json:"amount" binding:"required"`
 }
 
@@ -29,6 +34,8 @@ type CreateExpenditureRequest struct {
 type UpdateExpenditureRequest struct {
 	Particulars   *string  `json:"particulars" binding:"max=255"`
 	BankAccountID *string  `json:"bank_account_id"`
+	PayeeID       *string  `json:"payee_id"`
+	FundID        *string  `json:"fund_id"`
 	Amount        *float64 `json:"amount"`
 }
 
@@ -38,9 +45,12 @@ type ExpenditureResponse struct {
 	TransactionID string             `json:"transaction_id"`
 	Transaction   *TransactionResponse `json:"transaction,omitempty"`
 	Particulars   string             `json:"particulars"`
+	PayeeID       *string            `json:"payee_id"`
+	FundID        *string            `json:"fund_id"`
 	BankAccountID string             `json:"bank_account_id"`
 	BankAccount   *AccountResponse   `json:"bank_account,omitempty"`
 	Amount        float64            `json:"amount"`
+	BudgetWarning *string            `json:"budget_warning,omitempty"`
 	CreatedAt     time.Time          `json:"created_at"`
 	UpdatedAt     time.Time          `json:"updated_at"`
 }
@@ -62,6 +72,8 @@ func (e *Expenditure) ToResponse() *ExpenditureResponse {
 		TransactionID:  e.TransactionID,
 		Transaction:    transactionResp,
 		Particulars:    e.Particulars,
+		PayeeID:        e.PayeeID,
+		FundID:         e.FundID,
 		BankAccountID:  e.BankAccountID,
 		BankAccount:    bankAccountResp,
 		Amount:         e.Amount,
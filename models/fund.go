@@ -0,0 +1,131 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Fund represents a pool of money given for a purpose, such as the building fund or the
+// mission offering. Receipt, expenditure and transfer lines without a fund belong to the
+// unrestricted general fund.
+type Fund struct {
+	ID           string    `json:"id" db:"id"`
+	ChurchID     string    `json:"-" db:"church_id"`
+	Code         string    `json:"code" db:"code" binding:"required,max=20"`
+	Name         string    `json:"name" db:"name" binding:"required,max=100"`
+	Description  *string   `json:"description" db:"description"`
+	IsRestricted bool      `json:"is_restricted" db:"is_restricted"`
+	IsActive     bool      `json:"is_active" db:"is_active"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+var fundCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{1,20}$`)
+
+// NormalizeFundCode upper-cases a fund code and checks its characters
+func NormalizeFundCode(code string) (string, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(code))
+	if !fundCodePattern.MatchString(normalized) {
+		return "", Invalid("code must be 1 to 20 letters, digits, dashes or underscores")
+	}
+	return normalized, nil
+}
+
+// CreateFundRequest represents the request for creating a new fund
+type CreateFundRequest struct {
+	Code         string  `json:"code" binding:"required,max=20"`
+	Name         string  `json:"name" binding:"required,max=100"`
+	Description  *string `json:"description"`
+	IsRestricted *bool   `json:"is_restricted"`
+}
+
+// Validate validates the CreateFundRequest
+func (req *CreateFundRequest) Validate() error {
+	if strings.TrimSpace(req.Name) == "" {
+		return Invalid("name is required")
+	}
+	if len(req.Name) > 100 {
+		return Invalid("name must be at most 100 characters")
+	}
+	_, err := NormalizeFundCode(req.Code)
+	return err
+}
+
+// UpdateFundRequest represents the request for updating a fund
+type UpdateFundRequest struct {
+	Name         *string `json:"name" binding:"max=100"`
+	Description  *string `json:"description"`
+	IsRestricted *bool   `json:"is_restricted"`
+	IsActive     *bool   `json:"is_active"`
+}
+
+// FundResponse represents the fund response
+type FundResponse struct {
+	ID           string    `json:"id"`
+	Code         string    `json:"code"`
+	Name         string    `json:"name"`
+	Description  *string   `json:"description"`
+	IsRestricted bool      `json:"is_restricted"`
+	IsActive     bool      `json:"is_active"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ToResponse converts Fund to FundResponse
+func (f *Fund) ToResponse() *FundResponse {
+	return &FundResponse{
+		ID:           f.ID,
+		Code:         f.Code,
+		Name:         f.Name,
+		Description:  f.Description,
+		IsRestricted: f.IsRestricted,
+		IsActive:     f.IsActive,
+		CreatedAt:    f.CreatedAt,
+		UpdatedAt:    f.UpdatedAt,
+	}
+}
+
+// FundAccountBalance represents how much of a fund is held in one account
+type FundAccountBalance struct {
+	AccountID   string  `json:"account_id" db:"account_id"`
+	AccountName string  `json:"account_name" db:"account_name"`
+	Balance     float64 `json:"balance" db:"balance"`
+}
+
+// FundTotals represents the posted receipts and expenditures of one fund; FundID is empty
+// for the general fund
+type FundTotals struct {
+	FundID       *string `db:"fund_id"`
+	Receipts     float64 `db:"receipts"`
+	Expenditures float64 `db:"expenditures"`
+}
+
+// FundBalance represents the money received into and spent from one fund
+type FundBalance struct {
+	FundID       *string              `json:"fund_id"`
+	Code         string               `json:"code"`
+	Name         string               `json:"name"`
+	IsRestricted bool                 `json:"is_restricted"`
+	Receipts     float64              `json:"receipts"`
+	Expenditures float64              `json:"expenditures"`
+	Balance      float64              `json:"balance"`
+	Committed    float64              `json:"committed"`
+	Available    float64              `json:"available"`
+	Accounts     []FundAccountBalance `json:"accounts"`
+}
+
+// FundBalancesReport represents the balance of every fund at a date
+type FundBalancesReport struct {
+	AsOf              time.Time     `json:"as_of"`
+	Funds             []FundBalance `json:"funds"`
+	RestrictedTotal   float64       `json:"restricted_total"`
+	UnrestrictedTotal float64       `json:"unrestricted_total"`
+	Total             float64       `json:"total"`
+}
+
+// GeneralFundCode and GeneralFundName label the lines that carry no fund
+const (
+	GeneralFundCode = "GENERAL"
+	GeneralFundName = "General Fund"
+)
@@ -0,0 +1,268 @@
+package models
+
+import (
+	"time"
+)
+
+// Imprest represents a petty cash account kept at a fixed float
+type Imprest struct {
+	ID              string    `json:"id" db:"id"`
+	ChurchID        string    `json:"-" db:"church_id"`
+	Name            string    `json:"name" db:"name" binding:"required,max=100"`
+	AccountID       string    `json:"account_id" db:"account" binding:"required"`
+	FloatAmount     float64   `json:"float_amount" db:"float_amount" binding:"required"`
+	ReplenishFromID *string   `json:"replenish_from_id" db:"replenish_from"`
+	CustodianID     *string   `json:"custodian_id" db:"custodian"`
+	IsActive        bool      `json:"is_active" db:"is_active"`
+	CreatedBy       *string   `json:"created_by" db:"created_by"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// PettyCashVoucher represents a small expense paid out of an imprest
+type PettyCashVoucher struct {
+	ID                   string    `json:"id" db:"id"`
+	ChurchID             string    `json:"-" db:"church_id"`
+	ImprestID            string    `json:"imprest_id" db:"imprest_id"`
+	VoucherNumber        string    `json:"voucher_number" db:"voucher_number"`
+	VoucherDate          time.Time `json:"voucher_date" db:"voucher_date"`
+	ExpenseAccountID     string    `json:"expense_account_id" db:"expense_account" binding:"required"`
+	Particulars          string    `json:"particulars" db:"particulars" binding:"required,max=255"`
+	PaidTo               *string   `json:"paid_to" db:"paid_to" binding:"max=200"`
+	Amount               float64   `json:"amount" db:"amount" binding:"required"`
+	Status               string    `json:"status" db:"status"`
+	ReplenishmentID      *string   `json:"replenishment_id" db:"replenishment_id"`
+	ExpenseTransactionID *string   `json:"expense_transaction_id" db:"expense_transaction"`
+	CreatedBy            *string   `json:"created_by" db:"created_by"`
+	CreatedAt            time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ImprestReplenishment represents a top-up restoring an imprest to its float
+type ImprestReplenishment struct {
+	ID                    string     `json:"id" db:"id"`
+	ChurchID              string     `json:"-" db:"church_id"`
+	ImprestID             string     `json:"imprest_id" db:"imprest_id"`
+	BankAccountID         string     `json:"bank_account_id" db:"bank_account"`
+	VouchersTotal         float64    `json:"vouchers_total" db:"vouchers_total"`
+	Amount                float64    `json:"amount" db:"amount"`
+	Status                string     `json:"status" db:"status"`
+	TransferTransactionID *string    `json:"transfer_transaction_id" db:"transfer_transaction"`
+	Notes                 *string    `json:"notes" db:"notes"`
+	RequestedBy           *string    `json:"requested_by" db:"requested_by"`
+	ReviewedBy            *string    `json:"reviewed_by" db:"reviewed_by"`
+	ReviewedAt            *time.Time `json:"reviewed_at" db:"reviewed_at"`
+	CreatedAt             time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// PettyCashVoucherStatus represents where a petty cash voucher is in the imprest cycle
+type PettyCashVoucherStatus string
+
+const (
+	PettyCashOpen        PettyCashVoucherStatus = "open"
+	PettyCashClaimed     PettyCashVoucherStatus = "claimed"
+	PettyCashReplenished PettyCashVoucherStatus = "replenished"
+)
+
+// ReplenishmentStatus represents the state of an imprest replenishment
+type ReplenishmentStatus string
+
+const (
+	ReplenishmentPending  ReplenishmentStatus = "pending"
+	ReplenishmentPosted   ReplenishmentStatus = "posted"
+	ReplenishmentRejected ReplenishmentStatus = "rejected"
+)
+
+// ReplenishmentPosting is everything an approved replenishment writes to the books: an
+// expenses transaction per Expense account paying its vouchers, and the top-up from the
+// bank. Every transaction carries the approval on its voucher trail.
+type ReplenishmentPosting struct {
+	Replenishment ImprestReplenishment
+	Expenses      []ReplenishmentExpense
+	TopUp         Transaction
+	TopUpLine     Transfer
+	Approval      VoucherApproval
+}
+
+// ReplenishmentExpense is an expenses transaction with a line for each voucher it pays
+type ReplenishmentExpense struct {
+	Transaction  Transaction
+	Expenditures []Expenditure
+	Vouchers     []PettyCashVoucher
+}
+
+// CreateImprestRequest represents the request for setting up an imprest
+type CreateImprestRequest struct {
+	Name            string  `json:"name" binding:"required,max=100"`
+	AccountID       string  `json:"account_id" binding:"required"`
+	FloatAmount     float64 `json:"float_amount" binding:"required"`
+	ReplenishFromID *string `json:"replenish_from_id"`
+	CustodianID     *string `json:"custodian_id"`
+}
+
+// Validate validates the CreateImprestRequest
+func (req *CreateImprestRequest) Validate() error {
+	if req.Name == "" {
+		return Invalid("name is required")
+	}
+	if req.AccountID == "" {
+		return Invalid("account_id is required")
+	}
+	if req.FloatAmount <= 0 {
+		return Invalid("float_amount must be greater than zero")
+	}
+	return nil
+}
+
+// UpdateImprestRequest represents the request for updating an imprest
+type UpdateImprestRequest struct {
+	Name            *string  `json:"name" binding:"max=100"`
+	FloatAmount     *float64 `json:"float_amount"`
+	ReplenishFromID *string  `json:"replenish_from_id"`
+	CustodianID     *string  `json:"custodian_id"`
+	IsActive        *bool    `json:"is_active"`
+}
+
+// CreatePettyCashVoucherRequest represents the request for recording a petty cash payment
+type CreatePettyCashVoucherRequest struct {
+	VoucherDate      *time.Time `json:"voucher_date"`
+	ExpenseAccountID string     `json:"expense_account_id" binding:"required"`
+	Particulars      string     `json:"particulars" binding:"required,max=255"`
+	PaidTo           *string    `json:"paid_to" binding:"max=200"`
+	Amount           float64    `json:"amount" binding:"required"`
+}
+
+// Validate validates the CreatePettyCashVoucherRequest
+func (req *CreatePettyCashVoucherRequest) Validate() error {
+	if req.ExpenseAccountID == "" {
+		return Invalid("expense_account_id is required")
+	}
+	if req.Particulars == "" {
+		return Invalid("particulars is required")
+	}
+	if len(req.Particulars) > 255 {
+		return Invalid("particulars must be at most 255 characters")
+	}
+	if req.Amount <= 0 {
+		return Invalid("amount must be greater than zero")
+	}
+	return nil
+}
+
+// RequestReplenishmentRequest represents the request for topping up an imprest. Without
+// a bank account the imprest's replenish_from account is used.
+type RequestReplenishmentRequest struct {
+	BankAccountID *string `json:"bank_account_id"`
+	Notes         *string `json:"notes"`
+}
+
+// ImprestResponse represents the imprest response with its current position
+type ImprestResponse struct {
+	ID                string    `json:"id"`
+	Name              string    `json:"name"`
+	AccountID         string    `json:"account_id"`
+	FloatAmount       float64   `json:"float_amount"`
+	ReplenishFromID   *string   `json:"replenish_from_id"`
+	CustodianID       *string   `json:"custodian_id"`
+	IsActive          bool      `json:"is_active"`
+	BookBalance       float64   `json:"book_balance"`
+	UnclaimedVouchers float64   `json:"unclaimed_vouchers"`
+	ClaimedVouchers   float64   `json:"claimed_vouchers"`
+	CashOnHand        float64   `json:"cash_on_hand"`
+	TopUpDue          float64   `json:"top_up_due"`
+	CreatedBy         *string   `json:"created_by"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// PettyCashVoucherResponse represents the petty cash voucher response
+type PettyCashVoucherResponse struct {
+	ID                   string    `json:"id"`
+	ImprestID            string    `json:"imprest_id"`
+	VoucherNumber        string    `json:"voucher_number"`
+	VoucherDate          time.Time `json:"voucher_date"`
+	ExpenseAccountID     string    `json:"expense_account_id"`
+	Particulars          string    `json:"particulars"`
+	PaidTo               *string   `json:"paid_to"`
+	Amount               float64   `json:"amount"`
+	Status               string    `json:"status"`
+	ReplenishmentID      *string   `json:"replenishment_id"`
+	ExpenseTransactionID *string   `json:"expense_transaction_id"`
+	CreatedBy            *string   `json:"created_by"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// ImprestReplenishmentResponse represents the replenishment response with its vouchers
+type ImprestReplenishmentResponse struct {
+	ID                    string                     `json:"id"`
+	ImprestID             string                     `json:"imprest_id"`
+	BankAccountID         string                     `json:"bank_account_id"`
+	VouchersTotal         float64                    `json:"vouchers_total"`
+	Amount                float64                    `json:"amount"`
+	Status                string                     `json:"status"`
+	TransferTransactionID *string                    `json:"transfer_transaction_id"`
+	Notes                 *string                    `json:"notes"`
+	RequestedBy           *string                    `json:"requested_by"`
+	ReviewedBy            *string                    `json:"reviewed_by"`
+	ReviewedAt            *time.Time                 `json:"reviewed_at"`
+	Vouchers              []PettyCashVoucherResponse `json:"vouchers,omitempty"`
+	CreatedAt             time.Time                  `json:"created_at"`
+	UpdatedAt             time.Time                  `json:"updated_at"`
+}
+
+// ToResponse converts Imprest to ImprestResponse
+func (i *Imprest) ToResponse() *ImprestResponse {
+	return &ImprestResponse{
+		ID:              i.ID,
+		Name:            i.Name,
+		AccountID:       i.AccountID,
+		FloatAmount:     i.FloatAmount,
+		ReplenishFromID: i.ReplenishFromID,
+		CustodianID:     i.CustodianID,
+		IsActive:        i.IsActive,
+		CreatedBy:       i.CreatedBy,
+		CreatedAt:       i.CreatedAt,
+		UpdatedAt:       i.UpdatedAt,
+	}
+}
+
+// ToResponse converts PettyCashVoucher to PettyCashVoucherResponse
+func (v *PettyCashVoucher) ToResponse() *PettyCashVoucherResponse {
+	return &PettyCashVoucherResponse{
+		ID:                   v.ID,
+		ImprestID:            v.ImprestID,
+		VoucherNumber:        v.VoucherNumber,
+		VoucherDate:          v.VoucherDate,
+		ExpenseAccountID:     v.ExpenseAccountID,
+		Particulars:          v.Particulars,
+		PaidTo:               v.PaidTo,
+		Amount:               v.Amount,
+		Status:               v.Status,
+		ReplenishmentID:      v.ReplenishmentID,
+		ExpenseTransactionID: v.ExpenseTransactionID,
+		CreatedBy:            v.CreatedBy,
+		CreatedAt:            v.CreatedAt,
+		UpdatedAt:            v.UpdatedAt,
+	}
+}
+
+// ToResponse converts ImprestReplenishment to ImprestReplenishmentResponse
+func (r *ImprestReplenishment) ToResponse() *ImprestReplenishmentResponse {
+	return &ImprestReplenishmentResponse{
+		ID:                    r.ID,
+		ImprestID:             r.ImprestID,
+		BankAccountID:         r.BankAccountID,
+		VouchersTotal:         r.VouchersTotal,
+		Amount:                r.Amount,
+		Status:                r.Status,
+		TransferTransactionID: r.TransferTransactionID,
+		Notes:                 r.Notes,
+		RequestedBy:           r.RequestedBy,
+		ReviewedBy:            r.ReviewedBy,
+		ReviewedAt:            r.ReviewedAt,
+		CreatedAt:             r.CreatedAt,
+		UpdatedAt:             r.UpdatedAt,
+	}
+}
@@ -7,11 +7,13 @@ import (
 // Member represents a church member who makes offerings and contributions
 type Member struct {
 	ID          string        `json:"id" db:"id"`
+	ChurchID    string        `json:"-" db:"church_id"`
 	FullName    string        `json:"full_name" db:"full_name" binding:"required,max=100"`
 	PhoneNumber string        `json:"phone_number" db:"phone_number" binding:"required,max=20"`
 	Email       *string       `json:"email" db:"email"`
 	Notes       *string       `json:"notes" db:"notes"`
 	GroupID     *string       `json:"group_id" db:"group_id"`
+	SMSOptOut   bool          `json:"sms_opt_out" db:"sms_opt_out"`
 	Group       *MembersGroup `json:"group,omitempty" db:"-"`
 	CreatedBy   string        `json:"created_by" db:"created_by"`
 	CreatedAt   time.Time     `json:"created_at" db:"created_at"`
@@ -44,6 +46,7 @@ type MemberResponse struct {
 	Email       *string        `json:"email"`
 	Notes       *string        `json:"notes"`
 	GroupID     *string        `json:"group_id"`
+	SMSOptOut   bool           `json:"sms_opt_out"`
 	Group       *GroupResponse `json:"group,omitempty"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
@@ -63,6 +66,7 @@ func (m *Member) ToResponse() *MemberResponse {
 		Email:       m.Email,
 		Notes:       m.Notes,
 		GroupID:     m.GroupID,
+		SMSOptOut:   m.SMSOptOut,
 		Group:       groupResp,
 		CreatedAt:   m.CreatedAt,
 		UpdatedAt:   m.UpdatedAt,
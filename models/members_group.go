@@ -7,6 +7,7 @@ import (
 // MembersGroup represents church member groups or estates
 type MembersGroup struct {
 	ID          string    `json:"id" db:"id"`
+	ChurchID    string    `json:"-" db:"church_id"`
 	GroupName   string    `json:"group_name" db:"group_name" binding:"required,max=50"`
 	Notes       *string   `json:"notes" db:"notes"`
 	CreatedBy   string    `json:"created_by" db:"created_by"`
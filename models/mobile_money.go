@@ -0,0 +1,182 @@
+package models
+
+import (
+	"time"
+)
+
+// MobileMoneyImport represents a mobile money statement uploaded for review
+type MobileMoneyImport struct {
+	ID                 string    `json:"id" db:"id"`
+	ChurchID           string    `json:"-" db:"church_id"`
+	Provider           string    `json:"provider" db:"provider" binding:"required"`
+	FileName           *string   `json:"file_name" db:"file_name"`
+	ReceivingAccountID string    `json:"receiving_account_id" db:"receiving_account" binding:"required"`
+	IncomeAccountID    *string   `json:"income_account_id" db:"income_account"`
+	CreatedBy          string    `json:"created_by" db:"created_by"`
+	CreatedAt          time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// MobileMoneyProvider represents the supported mobile money providers
+type MobileMoneyProvider string
+
+const (
+	ProviderMpesa  MobileMoneyProvider = "mpesa"
+	ProviderAirtel MobileMoneyProvider = "airtel"
+)
+
+// ValidateProvider checks if the mobile money provider is supported
+func (m *MobileMoneyImport) ValidateProvider() error {
+	switch m.Provider {
+	case string(ProviderMpesa), string(ProviderAirtel):
+		return nil
+	default:
+		return ErrInvalidProvider
+	}
+}
+
+// MobileMoneyLine represents a payment received on a mobile money statement. It waits in
+// the review queue until a treasurer confirms it (posting a receipt) or rejects it.
+type MobileMoneyLine struct {
+	ID                string     `json:"id" db:"id"`
+	ChurchID          string     `json:"-" db:"church_id"`
+	ImportID          string     `json:"import_id" db:"import_id"`
+	Provider          string     `json:"provider" db:"provider"`
+	TransCode         string     `json:"trans_code" db:"trans_code"`
+	TransTime         time.Time  `json:"trans_time" db:"trans_time"`
+	Amount            float64    `json:"amount" db:"amount"`
+	MSISDN            *string    `json:"msisdn" db:"msisdn"`
+	PayerName         *string    `json:"payer_name" db:"payer_name"`
+	AccountRef        *string    `json:"account_ref" db:"account_ref"`
+	Details           *string    `json:"details" db:"details"`
+	SuggestedMemberID *string    `json:"suggested_member_id" db:"suggested_member"`
+	MemberID          *string    `json:"member_id" db:"member"`
+	IncomeAccountID   *string    `json:"income_account_id" db:"income_account"`
+	Status            string     `json:"status" db:"status"`
+	TransactionID     *string    `json:"transaction_id" db:"transaction_id"`
+	Notes             *string    `json:"notes" db:"notes"`
+	ReviewedBy        *string    `json:"reviewed_by" db:"reviewed_by"`
+	ReviewedAt        *time.Time `json:"reviewed_at" db:"reviewed_at"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// MobileMoneyLineStatus represents the review state of a statement line
+type MobileMoneyLineStatus string
+
+const (
+	LinePending   MobileMoneyLineStatus = "pending"
+	LineDuplicate MobileMoneyLineStatus = "duplicate"
+	LinePosted    MobileMoneyLineStatus = "posted"
+	LineRejected  MobileMoneyLineStatus = "rejected"
+)
+
+// ImportMobileMoneyRequest represents the form fields sent with a mobile money statement upload
+type ImportMobileMoneyRequest struct {
+	Provider           string `json:"provider" binding:"required"`
+	FileName           string `json:"file_name"`
+	ReceivingAccountID string `json:"receiving_account_id" binding:"required"`
+	IncomeAccountID    string `json:"income_account_id"`
+}
+
+// Validate validates the ImportMobileMoneyRequest
+func (req *ImportMobileMoneyRequest) Validate() error {
+	if req.ReceivingAccountID == "" {
+		return Invalid("receiving account is required")
+	}
+	statement := MobileMoneyImport{Provider: req.Provider}
+	return statement.ValidateProvider()
+}
+
+// UpdateMobileMoneyLineRequest represents the request for reassigning a pending line.
+// An empty member_id clears the member so the receipt is posted as anonymous.
+type UpdateMobileMoneyLineRequest struct {
+	MemberID        *string `json:"member_id"`
+	IncomeAccountID *string `json:"income_account_id"`
+	Notes           *string `json:"notes"`
+}
+
+// ReviewMobileMoneyLineRequest represents a treasurer's confirmation or rejection of a line,
+// optionally reassigning it at the same time
+type ReviewMobileMoneyLineRequest struct {
+	MemberID        *string `json:"member_id"`
+	IncomeAccountID *string `json:"income_account_id"`
+	Notes           *string `json:"notes"`
+}
+
+// MobileMoneyImportResponse represents the mobile money import response
+type MobileMoneyImportResponse struct {
+	ID                 string                    `json:"id"`
+	Provider           string                    `json:"provider"`
+	FileName           *string                   `json:"file_name"`
+	ReceivingAccountID string                    `json:"receiving_account_id"`
+	IncomeAccountID    *string                   `json:"income_account_id"`
+	LineCount          int                       `json:"line_count"`
+	PendingCount       int                       `json:"pending_count"`
+	DuplicateCount     int                       `json:"duplicate_count"`
+	PostedCount        int                       `json:"posted_count"`
+	RejectedCount      int                       `json:"rejected_count"`
+	SkippedCount       int                       `json:"skipped_count,omitempty"`
+	Lines              []MobileMoneyLineResponse `json:"lines,omitempty"`
+	CreatedAt          time.Time                 `json:"created_at"`
+	UpdatedAt          time.Time                 `json:"updated_at"`
+}
+
+// ToResponse converts MobileMoneyImport to MobileMoneyImportResponse
+func (m *MobileMoneyImport) ToResponse() *MobileMoneyImportResponse {
+	return &MobileMoneyImportResponse{
+		ID:                 m.ID,
+		Provider:           m.Provider,
+		FileName:           m.FileName,
+		ReceivingAccountID: m.ReceivingAccountID,
+		IncomeAccountID:    m.IncomeAccountID,
+		CreatedAt:          m.CreatedAt,
+		UpdatedAt:          m.UpdatedAt,
+	}
+}
+
+// MobileMoneyLineResponse represents the mobile money line response
+type MobileMoneyLineResponse struct {
+	ID                string     `json:"id"`
+	ImportID          string     `json:"import_id"`
+	Provider          string     `json:"provider"`
+	TransCode         string     `json:"trans_code"`
+	TransTime         time.Time  `json:"trans_time"`
+	Amount            float64    `json:"amount"`
+	MSISDN            *string    `json:"msisdn"`
+	PayerName         *string    `json:"payer_name"`
+	AccountRef        *string    `json:"account_ref"`
+	Details           *string    `json:"details"`
+	SuggestedMemberID *string    `json:"suggested_member_id"`
+	MemberID          *string    `json:"member_id"`
+	IncomeAccountID   *string    `json:"income_account_id"`
+	Status            string     `json:"status"`
+	TransactionID     *string    `json:"transaction_id"`
+	Notes             *string    `json:"notes"`
+	ReviewedBy        *string    `json:"reviewed_by"`
+	ReviewedAt        *time.Time `json:"reviewed_at"`
+}
+
+// ToResponse converts MobileMoneyLine to MobileMoneyLineResponse
+func (l *MobileMoneyLine) ToResponse() *MobileMoneyLineResponse {
+	return &MobileMoneyLineResponse{
+		ID:                l.ID,
+		ImportID:          l.ImportID,
+		Provider:          l.Provider,
+		TransCode:         l.TransCode,
+		TransTime:         l.TransTime,
+		Amount:            l.Amount,
+		MSISDN:            l.MSISDN,
+		PayerName:         l.PayerName,
+		AccountRef:        l.AccountRef,
+		Details:           l.Details,
+		SuggestedMemberID: l.SuggestedMemberID,
+		MemberID:          l.MemberID,
+		IncomeAccountID:   l.IncomeAccountID,
+		Status:            l.Status,
+		TransactionID:     l.TransactionID,
+		Notes:             l.Notes,
+		ReviewedBy:        l.ReviewedBy,
+		ReviewedAt:        l.ReviewedAt,
+	}
+}
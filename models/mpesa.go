@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+)
+
+// MpesaTransaction represents an M-Pesa C2B payment received through a Paybill callback
+type MpesaTransaction struct {
+	ID                string    `json:"id" db:"id"`
+	ChurchID          string    `json:"-" db:"church_id"`
+	TransID           string    `json:"trans_id" db:"trans_id"`
+	TransType         *string   `json:"trans_type" db:"trans_type"`
+	TransTime         time.Time `json:"trans_time" db:"trans_time"`
+	Amount            float64   `json:"amount" db:"amount"`
+	BusinessShortCode *string   `json:"business_short_code" db:"business_short_code"`
+	BillRefNumber     *string   `json:"bill_ref_number" db:"bill_ref_number"`
+	MSISDN            *string   `json:"msisdn" db:"msisdn"`
+	PayerName         *string   `json:"payer_name" db:"payer_name"`
+	MemberID          *string   `json:"member_id" db:"member"`
+	IncomeAccountID   *string   `json:"income_account_id" db:"income_account"`
+	TransactionID     *string   `json:"transaction_id" db:"transaction_id"`
+	Status            string    `json:"status" db:"status"`
+	Error             *string   `json:"error" db:"error"`
+	Payload           *string   `json:"-" db:"payload"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// MpesaStatus represents the processing state of an M-Pesa payment
+type MpesaStatus string
+
+const (
+	MpesaReceived MpesaStatus = "received"
+	MpesaPosted   MpesaStatus = "posted"
+	MpesaFailed   MpesaStatus = "failed"
+)
+
+// MpesaTransactionResponse represents the M-Pesa payment response
+type MpesaTransactionResponse struct {
+	ID                string    `json:"id"`
+	TransID           string    `json:"trans_id"`
+	TransType         *string   `json:"trans_type"`
+	TransTime         time.Time `json:"trans_time"`
+	Amount            float64   `json:"amount"`
+	BusinessShortCode *string   `json:"business_short_code"`
+	BillRefNumber     *string   `json:"bill_ref_number"`
+	MSISDN            *string   `json:"msisdn"`
+	PayerName         *string   `json:"payer_name"`
+	MemberID          *string   `json:"member_id"`
+	IncomeAccountID   *string   `json:"income_account_id"`
+	TransactionID     *string   `json:"transaction_id"`
+	Status            string    `json:"status"`
+	Error             *string   `json:"error"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// ToResponse converts MpesaTransaction to MpesaTransactionResponse
+func (m *MpesaTransaction) ToResponse() *MpesaTransactionResponse {
+	return &MpesaTransactionResponse{
+		ID:                m.ID,
+		TransID:           m.TransID,
+		TransType:         m.TransType,
+		TransTime:         m.TransTime,
+		Amount:            m.Amount,
+		BusinessShortCode: m.BusinessShortCode,
+		BillRefNumber:     m.BillRefNumber,
+		MSISDN:            m.MSISDN,
+		PayerName:         m.PayerName,
+		MemberID:          m.MemberID,
+		IncomeAccountID:   m.IncomeAccountID,
+		TransactionID:     m.TransactionID,
+		Status:            m.Status,
+		Error:             m.Error,
+		CreatedAt:         m.CreatedAt,
+		UpdatedAt:         m.UpdatedAt,
+	}
+}
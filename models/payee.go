@@ -0,0 +1,179 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// kraPinPattern matches a KRA PIN: a letter, nine digits and a letter, e.g. A123456789B
+var kraPinPattern = regexp.MustCompile(`^[A-Z][0-9]{9}[A-Z]$`)
+
+// Payee represents a contractor, supplier or staff member the church pays
+type Payee struct {
+	ID                string    `json:"id" db:"id"`
+	ChurchID          string    `json:"-" db:"church_id"`
+	Name              string    `json:"name" db:"name" binding:"required,max=200"`
+	PhoneNumber       *string   `json:"phone_number" db:"phone_number" binding:"max=20"`
+	Email             *string   `json:"email" db:"email" binding:"max=100"`
+	KRAPin            *string   `json:"kra_pin" db:"kra_pin" binding:"max=11"`
+	BankName          *string   `json:"bank_name" db:"bank_name" binding:"max=100"`
+	BankBranch        *string   `json:"bank_branch" db:"bank_branch" binding:"max=100"`
+	BankAccountNumber *string   `json:"bank_account_number" db:"bank_account_number" binding:"max=50"`
+	MpesaPhone        *string   `json:"mpesa_phone" db:"mpesa_phone" binding:"max=20"`
+	MpesaPaybill      *string   `json:"mpesa_paybill" db:"mpesa_paybill" binding:"max=20"`
+	MpesaAccount      *string   `json:"mpesa_account" db:"mpesa_account" binding:"max=50"`
+	MpesaTill         *string   `json:"mpesa_till" db:"mpesa_till" binding:"max=20"`
+	Notes             *string   `json:"notes" db:"notes"`
+	IsActive          bool      `json:"is_active" db:"is_active"`
+	CreatedBy         *string   `json:"created_by" db:"created_by"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// NormalizeKRAPin upper-cases a KRA PIN and checks its format
+func NormalizeKRAPin(pin string) (string, error) {
+	pin = strings.ToUpper(strings.TrimSpace(pin))
+	if !kraPinPattern.MatchString(pin) {
+		return "", Invalid("kra_pin must be a letter, nine digits and a letter, e.g. A123456789B")
+	}
+	return pin, nil
+}
+
+// CreatePayeeRequest represents the request for creating a new payee
+type CreatePayeeRequest struct {
+	Name              string  `json:"name" binding:"required,max=200"`
+	PhoneNumber       *string `json:"phone_number" binding:"max=20"`
+	Email             *string `json:"email" binding:"max=100"`
+	KRAPin            *string `json:"kra_pin" binding:"max=11"`
+	BankName          *string `json:"bank_name" binding:"max=100"`
+	BankBranch        *string `json:"bank_branch" binding:"max=100"`
+	BankAccountNumber *string `json:"bank_account_number" binding:"max=50"`
+	MpesaPhone        *string `json:"mpesa_phone" binding:"max=20"`
+	MpesaPaybill      *string `json:"mpesa_paybill" binding:"max=20"`
+	MpesaAccount      *string `json:"mpesa_account" binding:"max=50"`
+	MpesaTill         *string `json:"mpesa_till" binding:"max=20"`
+	Notes             *string `json:"notes"`
+}
+
+// Validate validates the CreatePayeeRequest
+func (req *CreatePayeeRequest) Validate() error {
+	if strings.TrimSpace(req.Name) == "" {
+		return Invalid("name is required")
+	}
+	if len(req.Name) > 200 {
+		return Invalid("name must be at most 200 characters")
+	}
+	return nil
+}
+
+// UpdatePayeeRequest represents the request for updating a payee. An empty string clears
+// an optional field.
+type UpdatePayeeRequest struct {
+	Name              *string `json:"name" binding:"max=200"`
+	PhoneNumber       *string `json:"phone_number" binding:"max=20"`
+	Email             *string `json:"email" binding:"max=100"`
+	KRAPin            *string `json:"kra_pin" binding:"max=11"`
+	BankName          *string `json:"bank_name" binding:"max=100"`
+	BankBranch        *string `json:"bank_branch" binding:"max=100"`
+	BankAccountNumber *string `json:"bank_account_number" binding:"max=50"`
+	MpesaPhone        *string `json:"mpesa_phone" binding:"max=20"`
+	MpesaPaybill      *string `json:"mpesa_paybill" binding:"max=20"`
+	MpesaAccount      *string `json:"mpesa_account" binding:"max=50"`
+	MpesaTill         *string `json:"mpesa_till" binding:"max=20"`
+	Notes             *string `json:"notes"`
+	IsActive          *bool   `json:"is_active"`
+}
+
+// PayeeResponse represents the payee response
+type PayeeResponse struct {
+	ID                string    `json:"id"`
+	Name              string    `json:"name"`
+	PhoneNumber       *string   `json:"phone_number"`
+	Email             *string   `json:"email"`
+	KRAPin            *string   `json:"kra_pin"`
+	BankName          *string   `json:"bank_name"`
+	BankBranch        *string   `json:"bank_branch"`
+	BankAccountNumber *string   `json:"bank_account_number"`
+	MpesaPhone        *string   `json:"mpesa_phone"`
+	MpesaPaybill      *string   `json:"mpesa_paybill"`
+	MpesaAccount      *string   `json:"mpesa_account"`
+	MpesaTill         *string   `json:"mpesa_till"`
+	Notes             *string   `json:"notes"`
+	IsActive          bool      `json:"is_active"`
+	CreatedBy         *string   `json:"created_by"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// PayeeSpend represents what was paid to one payee over a period
+type PayeeSpend struct {
+	PayeeID      string     `json:"payee_id" db:"payee_id"`
+	PayeeName    string     `json:"payee_name" db:"payee_name"`
+	KRAPin       *string    `json:"kra_pin" db:"kra_pin"`
+	Payments     int        `json:"payments" db:"payments"`
+	Total        float64    `json:"total" db:"total"`
+	LastPaidDate *time.Time `json:"last_paid_date" db:"last_paid_date"`
+}
+
+// PayeeAccountSpend represents what was paid to a payee against one expense account
+type PayeeAccountSpend struct {
+	AccountID   string  `json:"account_id" db:"account_id"`
+	AccountName string  `json:"account_name" db:"account_name"`
+	Payments    int     `json:"payments" db:"payments"`
+	Total       float64 `json:"total" db:"total"`
+}
+
+// PayeePayment represents one expenditure line paid to a payee
+type PayeePayment struct {
+	ExpenditureID   string    `json:"expenditure_id" db:"expenditure_id"`
+	TransactionID   string    `json:"transaction_id" db:"transaction_id"`
+	TransactionRef  *string   `json:"transaction_ref" db:"transaction_ref"`
+	TransactionDate time.Time `json:"transaction_date" db:"transaction_date"`
+	AccountID       string    `json:"account_id" db:"account_id"`
+	AccountName     string    `json:"account_name" db:"account_name"`
+	Particulars     string    `json:"particulars" db:"particulars"`
+	Amount          float64   `json:"amount" db:"amount"`
+}
+
+// PayeeSpendReport represents a payee's posted expenditures over a period
+type PayeeSpendReport struct {
+	Payee     *PayeeResponse      `json:"payee"`
+	StartDate time.Time           `json:"start_date"`
+	EndDate   time.Time           `json:"end_date"`
+	Payments  int                 `json:"payments"`
+	Total     float64             `json:"total"`
+	ByAccount []PayeeAccountSpend `json:"by_account"`
+	Lines     []PayeePayment      `json:"lines"`
+}
+
+// PayeeSpendSummary represents every payee's posted expenditures over a period
+type PayeeSpendSummary struct {
+	StartDate time.Time    `json:"start_date"`
+	EndDate   time.Time    `json:"end_date"`
+	Total     float64      `json:"total"`
+	Payees    []PayeeSpend `json:"payees"`
+}
+
+// ToResponse converts Payee to PayeeResponse
+func (p *Payee) ToResponse() *PayeeResponse {
+	return &PayeeResponse{
+		ID:                p.ID,
+		Name:              p.Name,
+		PhoneNumber:       p.PhoneNumber,
+		Email:             p.Email,
+		KRAPin:            p.KRAPin,
+		BankName:          p.BankName,
+		BankBranch:        p.BankBranch,
+		BankAccountNumber: p.BankAccountNumber,
+		MpesaPhone:        p.MpesaPhone,
+		MpesaPaybill:      p.MpesaPaybill,
+		MpesaAccount:      p.MpesaAccount,
+		MpesaTill:         p.MpesaTill,
+		Notes:             p.Notes,
+		IsActive:          p.IsActive,
+		CreatedBy:         p.CreatedBy,
+		CreatedAt:         p.CreatedAt,
+		UpdatedAt:         p.UpdatedAt,
+	}
+}
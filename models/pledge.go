@@ -0,0 +1,225 @@
+package models
+
+import (
+	"time"
+)
+
+// Pledge represents a member's commitment to pay an amount towards a campaign
+type Pledge struct {
+	ID           string    `json:"id" db:"id"`
+	ChurchID     string    `json:"-" db:"church_id"`
+	CampaignID   string    `json:"campaign_id" db:"campaign_id" binding:"required"`
+	Campaign     *Campaign `json:"campaign,omitempty" db:"-"`
+	MemberID     string    `json:"member_id" db:"member" binding:"required"`
+	Member       *Member   `json:"member,omitempty" db:"-"`
+	Amount       float64   `json:"amount" db:"amount" binding:"required"`
+	AmountPaid   float64   `json:"amount_paid" db:"amount_paid"`
+	Frequency    string    `json:"frequency" db:"frequency"`
+	Installments int       `json:"installments" db:"installments"`
+	StartDate    time.Time `json:"start_date" db:"start_date"`
+	Status       string    `json:"status" db:"status"`
+	Notes        *string   `json:"notes" db:"notes"`
+	CreatedBy    string    `json:"created_by" db:"created_by"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// PledgeFrequency represents how often pledge installments fall due
+type PledgeFrequency string
+
+const (
+	PledgeOnce      PledgeFrequency = "once"
+	PledgeWeekly    PledgeFrequency = "weekly"
+	PledgeMonthly   PledgeFrequency = "monthly"
+	PledgeQuarterly PledgeFrequency = "quarterly"
+	PledgeAnnually  PledgeFrequency = "annually"
+)
+
+// PledgeStatus represents the lifecycle state of a pledge
+type PledgeStatus string
+
+const (
+	PledgeOpen      PledgeStatus = "open"
+	PledgeFulfilled PledgeStatus = "fulfilled"
+	PledgeCancelled PledgeStatus = "cancelled"
+)
+
+// ValidateFrequency checks if the pledge frequency is valid
+func (p *Pledge) ValidateFrequency() error {
+	switch p.Frequency {
+	case string(PledgeOnce), string(PledgeWeekly), string(PledgeMonthly), string(PledgeQuarterly), string(PledgeAnnually):
+		return nil
+	default:
+		return ErrInvalidPledgeFrequency
+	}
+}
+
+// Balance returns the amount still outstanding on the pledge
+func (p *Pledge) Balance() float64 {
+	if p.AmountPaid >= p.Amount {
+		return 0
+	}
+	return p.Amount - p.AmountPaid
+}
+
+// InstallmentsDue returns how many installments have fallen due as of the given date
+func (p *Pledge) InstallmentsDue(asOf time.Time) int {
+	if asOf.Before(p.StartDate) {
+		return 0
+	}
+
+	due := 1
+	switch PledgeFrequency(p.Frequency) {
+	case PledgeWeekly:
+		due += int(asOf.Sub(p.StartDate).Hours() / (24 * 7))
+	case PledgeMonthly:
+		due += monthsBetween(p.StartDate, asOf)
+	case PledgeQuarterly:
+		due += monthsBetween(p.StartDate, asOf) / 3
+	case PledgeAnnually:
+		due += monthsBetween(p.StartDate, asOf) / 12
+	default:
+		return p.Installments
+	}
+
+	if due > p.Installments {
+		due = p.Installments
+	}
+	return due
+}
+
+// ExpectedToDate returns the amount that should have been paid by the given date
+func (p *Pledge) ExpectedToDate(asOf time.Time) float64 {
+	if p.Installments <= 0 {
+		return p.Amount
+	}
+	return p.Amount * float64(p.InstallmentsDue(asOf)) / float64(p.Installments)
+}
+
+// monthsBetween returns the number of whole months between two dates
+func monthsBetween(from, to time.Time) int {
+	months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+	if to.Day() < from.Day() {
+		months--
+	}
+	if months < 0 {
+		return 0
+	}
+	return months
+}
+
+// CreatePledgeRequest represents the request for creating a new pledge
+type CreatePledgeRequest struct {
+	CampaignID   string     `json:"campaign_id" binding:"required"`
+	MemberID     string     `json:"member_id" binding:"required"`
+	Amount       float64    `json:"amount" binding:"required"`
+	Frequency    string     `json:"frequency"`
+	Installments int        `json:"installments"`
+	StartDate    *time.Time `json:"start_date"`
+	Notes        *string    `json:"notes"`
+}
+
+// Validate validates the CreatePledgeRequest
+func (req *CreatePledgeRequest) Validate() error {
+	if req.CampaignID == "" {
+		return Invalid("campaign is required")
+	}
+	if req.MemberID == "" {
+		return Invalid("member is required")
+	}
+	if req.Amount <= 0 {
+		return Invalid("amount must be greater than zero")
+	}
+	if req.Installments < 0 {
+		return Invalid("installments must be greater than zero")
+	}
+	if req.Frequency != "" {
+		pledge := Pledge{Frequency: req.Frequency}
+		return pledge.ValidateFrequency()
+	}
+	return nil
+}
+
+// UpdatePledgeRequest represents the request for updating a pledge
+type UpdatePledgeRequest struct {
+	Amount       *float64   `json:"amount"`
+	Frequency    *string    `json:"frequency"`
+	Installments *int       `json:"installments"`
+	StartDate    *time.Time `json:"start_date"`
+	Status       *string    `json:"status"`
+	Notes        *string    `json:"notes"`
+}
+
+// PledgeResponse represents the pledge response
+type PledgeResponse struct {
+	ID           string          `json:"id"`
+	CampaignID   string          `json:"campaign_id"`
+	MemberID     string          `json:"member_id"`
+	Member       *MemberResponse `json:"member,omitempty"`
+	Amount       float64         `json:"amount"`
+	AmountPaid   float64         `json:"amount_paid"`
+	Balance      float64         `json:"balance"`
+	Frequency    string          `json:"frequency"`
+	Installments int             `json:"installments"`
+	StartDate    time.Time       `json:"start_date"`
+	Status       string          `json:"status"`
+	Notes        *string         `json:"notes"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+// ToResponse converts Pledge to PledgeResponse
+func (p *Pledge) ToResponse() *PledgeResponse {
+	var memberResp *MemberResponse
+	if p.Member != nil {
+		memberResp = p.Member.ToResponse()
+	}
+
+	return &PledgeResponse{
+		ID:           p.ID,
+		CampaignID:   p.CampaignID,
+		MemberID:     p.MemberID,
+		Member:       memberResp,
+		Amount:       p.Amount,
+		AmountPaid:   p.AmountPaid,
+		Balance:      p.Balance(),
+		Frequency:    p.Frequency,
+		Installments: p.Installments,
+		StartDate:    p.StartDate,
+		Status:       p.Status,
+		Notes:        p.Notes,
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+	}
+}
+
+// PledgePayment links a receipt (or part of it) to the pledge it fulfils
+type PledgePayment struct {
+	ID        string    `json:"id" db:"id"`
+	ChurchID  string    `json:"-" db:"church_id"`
+	PledgeID  string    `json:"pledge_id" db:"pledge_id"`
+	ReceiptID string    `json:"receipt_id" db:"receipt_id"`
+	Amount    float64   `json:"amount" db:"amount"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// PledgeDetail is a pledge joined with its member and group for reporting
+type PledgeDetail struct {
+	Pledge
+	MemberName string  `json:"member_name" db:"member_name"`
+	GroupID    *string `json:"group_id" db:"group_id"`
+	GroupName  *string `json:"group_name" db:"group_name"`
+}
+
+// PledgeFulfilment summarises pledged vs paid amounts for a member or group
+type PledgeFulfilment struct {
+	ID             string  `json:"id"`
+	Name           string  `json:"name"`
+	Pledges        int     `json:"pledges"`
+	Pledged        float64 `json:"pledged"`
+	Paid           float64 `json:"paid"`
+	ExpectedToDate float64 `json:"expected_to_date"`
+	Balance        float64 `json:"balance"`
+	Arrears        float64 `json:"arrears"`
+	PercentPaid    float64 `json:"percent_paid"`
+}
@@ -7,10 +7,12 @@ import (
 // Receipt represents income/receipts from offerings
 type Receipt struct {
 	ID            string     `json:"id" db:"id"`
+	ChurchID      string     `json:"-" db:"church_id"`
 	TransactionID string     `json:"transaction_id" db:"transaction_id" binding:"required"`
 	Transaction   *Transaction `json:"transaction,omitempty" db:"-"`
 	IncomeAccountID string   `json:"income_account_id" db:"income_account" binding:"required"`
 	IncomeAccount *Account   `json:"income_account,omitempty" db:"-"`
+	FundID        *string    `json:"fund_id" db:"fund"`
 	Amount        float64    `json:"amount" db:"amount" binding:"required"`
 	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
@@ -20,12 +22,14 @@ type Receipt struct {
 type CreateReceiptRequest struct {
 	TransactionID string  `json:"transaction_id" binding:"required"`
 	IncomeAccountID string `json:"income_account_id" binding:"required"`
+	FundID        *string `json:"fund_id"`
 	Amount        float64 `json:"amount" binding:"required"`
 }
 
 // UpdateReceiptRequest represents the request for updating a receipt
 type UpdateReceiptRequest struct {
 	IncomeAccountID *string  `json:"income_account_id"`
+	FundID          *string  `json:"fund_id"`
 	Amount          *float64 `json:"amount"`
 }
 
@@ -36,6 +40,7 @@ type ReceiptResponse struct {
 	Transaction   *TransactionResponse `json:"transaction,omitempty"`
 	IncomeAccountID string        `json:"income_account_id"`
 	IncomeAccount *AccountResponse `json:"income_account,omitempty"`
+	FundID        *string         `json:"fund_id"`
 	Amount        float64         `json:"amount"`
 	CreatedAt     time.Time       `json:"created_at"`
 	UpdatedAt     time.Time       `json:"updated_at"`
@@ -59,8 +64,18 @@ func (r *Receipt) ToResponse() *ReceiptResponse {
 		Transaction:      transactionResp,
 		IncomeAccountID:  r.IncomeAccountID,
 		IncomeAccount:    incomeAccountResp,
+		FundID:           r.FundID,
 		Amount:           r.Amount,
 		CreatedAt:        r.CreatedAt,
 		UpdatedAt:        r.UpdatedAt,
 	}
-}
\ No newline at end of file
+}
+// MemberGivingLine represents one receipt line in a member's giving statement
+type MemberGivingLine struct {
+	TransactionID   string    `json:"transaction_id" db:"transaction_id"`
+	TransactionRef  *string   `json:"transaction_ref" db:"transaction_ref"`
+	TransactionDate time.Time `json:"transaction_date" db:"transaction_date"`
+	IncomeAccountID string    `json:"income_account_id" db:"income_account"`
+	AccountName     string    `json:"account_name" db:"account_name"`
+	Amount          float64   `json:"amount" db:"amount"`
+}
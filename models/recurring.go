@@ -0,0 +1,246 @@
+package models
+
+import (
+	"time"
+)
+
+// RecurringTemplate represents a standing transaction generated on a schedule
+type RecurringTemplate struct {
+	ID              string     `json:"id" db:"id"`
+	ChurchID        string     `json:"-" db:"church_id"`
+	Name            string     `json:"name" db:"name" binding:"required,max=100"`
+	TransactionType string     `json:"transaction_type" db:"transaction_type" binding:"required"`
+	DebitAccountID  string     `json:"debit_account_id" db:"debit_account" binding:"required"`
+	MemberID        *string    `json:"member_id" db:"member"`
+	Notes           *string    `json:"notes" db:"notes"`
+	Schedule        string     `json:"schedule" db:"schedule" binding:"required,max=100"`
+	StartDate       time.Time  `json:"start_date" db:"start_date" binding:"required"`
+	EndDate         *time.Time `json:"end_date" db:"end_date"`
+	PostMode        string     `json:"post_mode" db:"post_mode"`
+	NextRunAt       *time.Time `json:"next_run_at" db:"next_run_at"`
+	LastRunAt       *time.Time `json:"last_run_at" db:"last_run_at"`
+	IsActive        bool       `json:"is_active" db:"is_active"`
+	CreatedBy       *string    `json:"created_by" db:"created_by"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// RecurringTemplateLine represents a line copied onto each generated transaction. The
+// account is the income account for receipts, the bank paid from for expenses and
+// withdrawals, and the source account for transfers.
+type RecurringTemplateLine struct {
+	ID          string    `json:"id" db:"id"`
+	ChurchID    string    `json:"-" db:"church_id"`
+	TemplateID  string    `json:"template_id" db:"template_id"`
+	AccountID   string    `json:"account_id" db:"account" binding:"required"`
+	Particulars string    `json:"particulars" db:"particulars" binding:"required,max=255"`
+	Amount      float64   `json:"amount" db:"amount" binding:"required"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// RecurringRun represents one scheduled occurrence of a template
+type RecurringRun struct {
+	ID            string    `json:"id" db:"id"`
+	ChurchID      string    `json:"-" db:"church_id"`
+	TemplateID    string    `json:"template_id" db:"template_id"`
+	ScheduledFor  time.Time `json:"scheduled_for" db:"scheduled_for"`
+	Status        string    `json:"status" db:"status"`
+	TransactionID *string   `json:"transaction_id" db:"transaction_id"`
+	Error         *string   `json:"error" db:"error"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// RecurringPostMode represents the status generated transactions are left in
+type RecurringPostMode string
+
+const (
+	RecurringDraft  RecurringPostMode = "draft"
+	RecurringPosted RecurringPostMode = "posted"
+)
+
+// RecurringRunStatus represents the outcome of a scheduled occurrence
+type RecurringRunStatus string
+
+const (
+	RecurringRunPending RecurringRunStatus = "pending"
+	RecurringRunCreated RecurringRunStatus = "created"
+	RecurringRunFailed  RecurringRunStatus = "failed"
+)
+
+// RecurringTemplateLineRequest represents a template line in create and update requests
+type RecurringTemplateLineRequest struct {
+	AccountID   string  `json:"account_id" binding:"required"`
+	Particulars string  `json:"particulars" binding:"required,max=255"`
+	Amount      float64 `json:"amount" binding:"required"`
+}
+
+// Validate validates the RecurringTemplateLineRequest
+func (req *RecurringTemplateLineRequest) Validate() error {
+	if req.AccountID == "" {
+		return Invalid("line account_id is required")
+	}
+	if req.Particulars == "" {
+		return Invalid("line particulars is required")
+	}
+	if len(req.Particulars) > 255 {
+		return Invalid("line particulars must be at most 255 characters")
+	}
+	if req.Amount <= 0 {
+		return Invalid("line amount must be greater than zero")
+	}
+	return nil
+}
+
+// CreateRecurringTemplateRequest represents the request for creating a recurring template
+type CreateRecurringTemplateRequest struct {
+	Name            string                         `json:"name" binding:"required,max=100"`
+	TransactionType string                         `json:"transaction_type" binding:"required"`
+	DebitAccountID  string                         `json:"debit_account_id" binding:"required"`
+	MemberID        *string                        `json:"member_id"`
+	Notes           *string                        `json:"notes"`
+	Schedule        string                         `json:"schedule" binding:"required,max=100"`
+	StartDate       time.Time                      `json:"start_date" binding:"required"`
+	EndDate         *time.Time                     `json:"end_date"`
+	PostMode        string                         `json:"post_mode"`
+	Lines           []RecurringTemplateLineRequest `json:"lines" binding:"required"`
+}
+
+// Validate validates the CreateRecurringTemplateRequest
+func (req *CreateRecurringTemplateRequest) Validate() error {
+	if req.Name == "" {
+		return Invalid("name is required")
+	}
+	if len(req.Name) > 100 {
+		return Invalid("name must be at most 100 characters")
+	}
+	if req.DebitAccountID == "" {
+		return Invalid("debit_account_id is required")
+	}
+	if req.Schedule == "" {
+		return Invalid("schedule is required")
+	}
+	if req.StartDate.IsZero() {
+		return Invalid("start_date is required")
+	}
+	if len(req.Lines) == 0 {
+		return Invalid("at least one line is required")
+	}
+	for i := range req.Lines {
+		if err := req.Lines[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateRecurringTemplateRequest represents the request for updating a recurring template.
+// Lines, when given, replace the template's lines.
+type UpdateRecurringTemplateRequest struct {
+	Name           *string                        `json:"name" binding:"max=100"`
+	DebitAccountID *string                        `json:"debit_account_id"`
+	MemberID       *string                        `json:"member_id"`
+	Notes          *string                        `json:"notes"`
+	Schedule       *string                        `json:"schedule" binding:"max=100"`
+	StartDate      *time.Time                     `json:"start_date"`
+	EndDate        *time.Time                     `json:"end_date"`
+	PostMode       *string                        `json:"post_mode"`
+	IsActive       *bool                          `json:"is_active"`
+	Lines          []RecurringTemplateLineRequest `json:"lines"`
+}
+
+// RecurringTemplateResponse represents the recurring template response with its lines
+type RecurringTemplateResponse struct {
+	ID              string                          `json:"id"`
+	Name            string                          `json:"name"`
+	TransactionType string                          `json:"transaction_type"`
+	DebitAccountID  string                          `json:"debit_account_id"`
+	MemberID        *string                         `json:"member_id"`
+	Notes           *string                         `json:"notes"`
+	Schedule        string                          `json:"schedule"`
+	StartDate       time.Time                       `json:"start_date"`
+	EndDate         *time.Time                      `json:"end_date"`
+	PostMode        string                          `json:"post_mode"`
+	NextRunAt       *time.Time                      `json:"next_run_at"`
+	LastRunAt       *time.Time                      `json:"last_run_at"`
+	IsActive        bool                            `json:"is_active"`
+	Amount          float64                         `json:"amount"`
+	Lines           []RecurringTemplateLineResponse `json:"lines"`
+	CreatedBy       *string                         `json:"created_by"`
+	CreatedAt       time.Time                       `json:"created_at"`
+	UpdatedAt       time.Time                       `json:"updated_at"`
+}
+
+// RecurringTemplateLineResponse represents the recurring template line response
+type RecurringTemplateLineResponse struct {
+	ID          string  `json:"id"`
+	AccountID   string  `json:"account_id"`
+	Particulars string  `json:"particulars"`
+	Amount      float64 `json:"amount"`
+}
+
+// RecurringRunResponse represents the recurring run response
+type RecurringRunResponse struct {
+	ID            string    `json:"id"`
+	TemplateID    string    `json:"template_id"`
+	ScheduledFor  time.Time `json:"scheduled_for"`
+	Status        string    `json:"status"`
+	TransactionID *string   `json:"transaction_id"`
+	Error         *string   `json:"error"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// RecurringRunSummary reports what a pass of the scheduler generated
+type RecurringRunSummary struct {
+	Created int                    `json:"created"`
+	Failed  int                    `json:"failed"`
+	Runs    []RecurringRunResponse `json:"runs"`
+}
+
+// ToResponse converts RecurringTemplate to RecurringTemplateResponse
+func (t *RecurringTemplate) ToResponse() *RecurringTemplateResponse {
+	return &RecurringTemplateResponse{
+		ID:              t.ID,
+		Name:            t.Name,
+		TransactionType: t.TransactionType,
+		DebitAccountID:  t.DebitAccountID,
+		MemberID:        t.MemberID,
+		Notes:           t.Notes,
+		Schedule:        t.Schedule,
+		StartDate:       t.StartDate,
+		EndDate:         t.EndDate,
+		PostMode:        t.PostMode,
+		NextRunAt:       t.NextRunAt,
+		LastRunAt:       t.LastRunAt,
+		IsActive:        t.IsActive,
+		CreatedBy:       t.CreatedBy,
+		CreatedAt:       t.CreatedAt,
+		UpdatedAt:       t.UpdatedAt,
+	}
+}
+
+// ToResponse converts RecurringTemplateLine to RecurringTemplateLineResponse
+func (l *RecurringTemplateLine) ToResponse() *RecurringTemplateLineResponse {
+	return &RecurringTemplateLineResponse{
+		ID:          l.ID,
+		AccountID:   l.AccountID,
+		Particulars: l.Particulars,
+		Amount:      l.Amount,
+	}
+}
+
+// ToResponse converts RecurringRun to RecurringRunResponse
+func (r *RecurringRun) ToResponse() *RecurringRunResponse {
+	return &RecurringRunResponse{
+		ID:            r.ID,
+		TemplateID:    r.TemplateID,
+		ScheduledFor:  r.ScheduledFor,
+		Status:        r.Status,
+		TransactionID: r.TransactionID,
+		Error:         r.Error,
+		CreatedAt:     r.CreatedAt,
+		UpdatedAt:     r.UpdatedAt,
+	}
+}
@@ -0,0 +1,173 @@
+package models
+
+import (
+	"strings"
+)
+
+// ChartTemplate represents a built-in chart of accounts a new church can start from
+type ChartTemplate struct {
+	Key         string            `json:"key"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Accounts    []TemplateAccount `json:"accounts"`
+}
+
+// TemplateAccount represents one account in a chart template. Parents are listed before
+// their children and referred to by code.
+type TemplateAccount struct {
+	Code       string   `json:"code"`
+	Name       string   `json:"account_name"`
+	Type       string   `json:"account_type"`
+	ParentCode string   `json:"parent_code,omitempty"`
+	IsHeader   bool     `json:"is_header"`
+	LocalShare *float64 `json:"local_share,omitempty"`
+	Notes      string   `json:"notes,omitempty"`
+}
+
+func share(value float64) *float64 {
+	return &value
+}
+
+// ChartTemplates lists the built-in charts of accounts. local_share is the fraction of an
+// income line the local church keeps; the rest is remitted to the conference.
+var ChartTemplates = []ChartTemplate{
+	{
+		Key:         "denominational",
+		Name:        "Denominational church",
+		Description: "Tithe and combined offering shared with the conference, with a local church budget kept in full",
+		Accounts: []TemplateAccount{
+			{Code: "1000", Name: "Cash and Bank", Type: string(AccountBank), IsHeader: true},
+			{Code: "1010", Name: "Cash at Hand", Type: string(AccountBank), ParentCode: "1000"},
+			{Code: "1020", Name: "Main Bank Account", Type: string(AccountBank), ParentCode: "1000"},
+			{Code: "1030", Name: "M-Pesa Paybill", Type: string(AccountBank), ParentCode: "1000"},
+			{Code: "2000", Name: "Amounts Due to Conference", Type: string(AccountLiability), IsHeader: true},
+			{Code: "2010", Name: "Tithe Payable", Type: string(AccountLiability), ParentCode: "2000"},
+			{Code: "2020", Name: "Offerings Payable", Type: string(AccountLiability), ParentCode: "2000"},
+			{Code: "4000", Name: "Tithes and Offerings", Type: string(AccountIncome), IsHeader: true},
+			{Code: "4010", Name: "Tithe", Type: string(AccountIncome), ParentCode: "4000", LocalShare: share(0), Notes: "Remitted in full to the conference"},
+			{Code: "4020", Name: "Combined Offering", Type: string(AccountIncome), ParentCode: "4000", LocalShare: share(0.5), Notes: "Shared equally between the local church and the conference"},
+			{Code: "4030", Name: "Local Church Budget", Type: string(AccountIncome), ParentCode: "4000", LocalShare: share(1), Notes: "Kept in full by the local church"},
+			{Code: "4100", Name: "Special Offerings", Type: string(AccountIncome), IsHeader: true},
+			{Code: "4110", Name: "Building Fund", Type: string(AccountIncome), ParentCode: "4100", LocalShare: share(1)},
+			{Code: "4120", Name: "Thanksgiving", Type: string(AccountIncome), ParentCode: "4100", LocalShare: share(1)},
+			{Code: "5000", Name: "Church Operations", Type: string(AccountExpense), IsHeader: true},
+			{Code: "5010", Name: "Utilities", Type: string(AccountExpense), ParentCode: "5000"},
+			{Code: "5020", Name: "Church Maintenance", Type: string(AccountExpense), ParentCode: "5000"},
+			{Code: "5030", Name: "Office and Stationery", Type: string(AccountExpense), ParentCode: "5000"},
+			{Code: "5100", Name: "Ministries", Type: string(AccountExpense), IsHeader: true},
+			{Code: "5110", Name: "Welfare", Type: string(AccountExpense), ParentCode: "5100"},
+			{Code: "5120", Name: "Evangelism", Type: string(AccountExpense), ParentCode: "5100"},
+			{Code: "5130", Name: "Youth and Children", Type: string(AccountExpense), ParentCode: "5100"},
+			{Code: "5200", Name: "Conference Remittances", Type: string(AccountExpense), IsHeader: true},
+			{Code: "5210", Name: "Tithe Remitted", Type: string(AccountExpense), ParentCode: "5200"},
+			{Code: "5220", Name: "Offerings Remitted", Type: string(AccountExpense), ParentCode: "5200"},
+		},
+	},
+	{
+		Key:         "independent",
+		Name:        "Independent church",
+		Description: "A simple chart for a church that keeps everything it receives",
+		Accounts: []TemplateAccount{
+			{Code: "1000", Name: "Cash and Bank", Type: string(AccountBank), IsHeader: true},
+			{Code: "1010", Name: "Cash at Hand", Type: string(AccountBank), ParentCode: "1000"},
+			{Code: "1020", Name: "Main Bank Account", Type: string(AccountBank), ParentCode: "1000"},
+			{Code: "4000", Name: "Giving", Type: string(AccountIncome), IsHeader: true},
+			{Code: "4010", Name: "Tithes", Type: string(AccountIncome), ParentCode: "4000", LocalShare: share(1)},
+			{Code: "4020", Name: "Offerings", Type: string(AccountIncome), ParentCode: "4000", LocalShare: share(1)},
+			{Code: "4030", Name: "Building Fund", Type: string(AccountIncome), ParentCode: "4000", LocalShare: share(1)},
+			{Code: "5000", Name: "Expenses", Type: string(AccountExpense), IsHeader: true},
+			{Code: "5010", Name: "Utilities", Type: string(AccountExpense), ParentCode: "5000"},
+			{Code: "5020", Name: "Church Maintenance", Type: string(AccountExpense), ParentCode: "5000"},
+			{Code: "5030", Name: "Ministries", Type: string(AccountExpense), ParentCode: "5000"},
+			{Code: "5040", Name: "Staff and Honoraria", Type: string(AccountExpense), ParentCode: "5000"},
+		},
+	},
+}
+
+// FindChartTemplate returns the built-in template with the given key
+func FindChartTemplate(key string) (ChartTemplate, bool) {
+	for _, t := range ChartTemplates {
+		if t.Key == key {
+			return t, true
+		}
+	}
+	return ChartTemplate{}, false
+}
+
+// SetupAdmin represents the first admin user created during setup
+type SetupAdmin struct {
+	Username    string `json:"username" binding:"required,max=50"`
+	Email       string `json:"email" binding:"required,email"`
+	Password    string `json:"password" binding:"required,min=8"`
+	FullName    string `json:"full_name" binding:"required,max=200"`
+	PhoneNumber string `json:"phone_number" binding:"max=12"`
+}
+
+// SetupChurch represents the first church created during setup
+type SetupChurch struct {
+	Name           string  `json:"name" binding:"required,max=100"`
+	Code           string  `json:"code" binding:"required,max=20"`
+	MpesaShortcode *string `json:"mpesa_shortcode" binding:"max=20"`
+}
+
+// SetupRequest represents the request for setting up a new installation with its first
+// church. Leave out template to start with an empty chart of accounts.
+type SetupRequest struct {
+	Template string      `json:"template"`
+	Groups   []string    `json:"groups"`
+	Church   SetupChurch `json:"church" binding:"required"`
+	Admin    SetupAdmin  `json:"admin" binding:"required"`
+}
+
+// Validate validates the SetupRequest
+func (req *SetupRequest) Validate() error {
+	if req.Template != "" {
+		if _, ok := FindChartTemplate(req.Template); !ok {
+			return Invalid("unknown template " + req.Template)
+		}
+	}
+	church := CreateChurchRequest{Name: req.Church.Name, Code: req.Church.Code}
+	if err := church.Validate(); err != nil {
+		return Invalid("church " + err.Error())
+	}
+	if req.Admin.Username == "" {
+		return Invalid("admin username is required")
+	}
+	if req.Admin.Email == "" || !strings.Contains(req.Admin.Email, "@") {
+		return Invalid("a valid admin email is required")
+	}
+	if req.Admin.FullName == "" {
+		return Invalid("admin full_name is required")
+	}
+	seen := make(map[string]bool)
+	for _, g := range req.Groups {
+		name := strings.TrimSpace(g)
+		if name == "" {
+			return Invalid("group names must not be empty")
+		}
+		if len(name) > 50 {
+			return Invalid("group names must be at most 50 characters")
+		}
+		if seen[strings.ToLower(name)] {
+			return Invalid("group " + name + " is listed twice")
+		}
+		seen[strings.ToLower(name)] = true
+	}
+	return nil
+}
+
+// SetupStatus reports whether the installation has been set up
+type SetupStatus struct {
+	Completed bool `json:"completed"`
+	Users     int  `json:"users" db:"users"`
+	Churches  int  `json:"churches" db:"churches"`
+}
+
+// SetupResponse represents what setup created
+type SetupResponse struct {
+	Template string            `json:"template,omitempty"`
+	Church   ChurchResponse    `json:"church"`
+	Admin    UserResponse      `json:"admin"`
+	Groups   []GroupResponse   `json:"groups"`
+	Accounts []AccountResponse `json:"accounts"`
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+)
+
+// SMSNotification represents a text message sent, or queued to be sent, to a member
+type SMSNotification struct {
+	ID                string     `json:"id" db:"id"`
+	ChurchID          string     `json:"-" db:"church_id"`
+	Kind              string     `json:"kind" db:"kind"`
+	MemberID          *string    `json:"member_id" db:"member"`
+	PhoneNumber       string     `json:"phone_number" db:"phone_number"`
+	TransactionID     *string    `json:"transaction_id" db:"transaction_id"`
+	Message           *string    `json:"message" db:"message"`
+	Status            string     `json:"status" db:"status"`
+	Provider          *string    `json:"provider" db:"provider"`
+	ProviderMessageID *string    `json:"provider_message_id" db:"provider_message_id"`
+	Attempts          int        `json:"attempts" db:"attempts"`
+	LastError         *string    `json:"last_error" db:"last_error"`
+	SendAfter         time.Time  `json:"send_after" db:"send_after"`
+	SentAt            *time.Time `json:"sent_at" db:"sent_at"`
+	DeliveredAt       *time.Time `json:"delivered_at" db:"delivered_at"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// SMSKind represents what a notification is about
+type SMSKind string
+
+const (
+	SMSReceipt SMSKind = "receipt"
+)
+
+// SMSStatus represents the delivery state of a notification
+type SMSStatus string
+
+const (
+	SMSQueued    SMSStatus = "queued"
+	SMSSent      SMSStatus = "sent"
+	SMSDelivered SMSStatus = "delivered"
+	SMSFailed    SMSStatus = "failed"
+	SMSSkipped   SMSStatus = "skipped"
+)
+
+// SetSMSOptOutRequest represents the request for changing a member's SMS preference
+type SetSMSOptOutRequest struct {
+	OptOut *bool `json:"opt_out" binding:"required"`
+}
+
+// Validate validates the SetSMSOptOutRequest
+func (req *SetSMSOptOutRequest) Validate() error {
+	if req.OptOut == nil {
+		return Invalid("opt_out is required")
+	}
+	return nil
+}
+
+// SMSNotificationResponse represents the SMS notification response
+type SMSNotificationResponse struct {
+	ID                string     `json:"id"`
+	Kind              string     `json:"kind"`
+	MemberID          *string    `json:"member_id"`
+	PhoneNumber       string     `json:"phone_number"`
+	TransactionID     *string    `json:"transaction_id"`
+	Message           *string    `json:"message"`
+	Status            string     `json:"status"`
+	Provider          *string    `json:"provider"`
+	ProviderMessageID *string    `json:"provider_message_id"`
+	Attempts          int        `json:"attempts"`
+	LastError         *string    `json:"last_error"`
+	SendAfter         time.Time  `json:"send_after"`
+	SentAt            *time.Time `json:"sent_at"`
+	DeliveredAt       *time.Time `json:"delivered_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// ToResponse converts SMSNotification to SMSNotificationResponse
+func (n *SMSNotification) ToResponse() *SMSNotificationResponse {
+	return &SMSNotificationResponse{
+		ID:                n.ID,
+		Kind:              n.Kind,
+		MemberID:          n.MemberID,
+		PhoneNumber:       n.PhoneNumber,
+		TransactionID:     n.TransactionID,
+		Message:           n.Message,
+		Status:            n.Status,
+		Provider:          n.Provider,
+		ProviderMessageID: n.ProviderMessageID,
+		Attempts:          n.Attempts,
+		LastError:         n.LastError,
+		SendAfter:         n.SendAfter,
+		SentAt:            n.SentAt,
+		DeliveredAt:       n.DeliveredAt,
+		CreatedAt:         n.CreatedAt,
+		UpdatedAt:         n.UpdatedAt,
+	}
+}
@@ -2,11 +2,14 @@ package models
 
 import (
 	"time"
+
+	"github.com/lib/pq"
 )
 
 // Transaction represents main transaction records
 type Transaction struct {
 	ID              string        `json:"id" db:"id"`
+	ChurchID        string        `json:"-" db:"church_id"`
 	TransactionRef  *string       `json:"transaction_ref" db:"transaction_ref" binding:"max=20"`
 	TransactionDate time.Time     `json:"transaction_date" db:"transaction_date"`
 	TransactionType string        `json:"transaction_type" db:"transaction_type" binding:"required"`
@@ -16,6 +19,16 @@ type Transaction struct {
 	DebitAccount    *Account      `json:"debit_account,omitempty" db:"-"`
 	MemberID        *string       `json:"member_id" db:"member"`
 	Member          *Member       `json:"member,omitempty" db:"-"`
+	PaymentMethod   *string       `json:"payment_method" db:"payment_method"`
+	PaymentReference *string      `json:"payment_reference" db:"payment_reference" binding:"max=100"`
+	CollectionSessionID *string   `json:"collection_session_id" db:"collection_session"`
+	Status          string        `json:"status" db:"status"`
+	SubmittedBy     *string       `json:"submitted_by" db:"submitted_by"`
+	SubmittedAt     *time.Time    `json:"submitted_at" db:"submitted_at"`
+	PostedBy        *string       `json:"posted_by" db:"posted_by"`
+	PostedAt        *time.Time    `json:"posted_at" db:"posted_at"`
+	RequiredApprovals int          `json:"required_approvals" db:"required_approvals"`
+	RequiredRoles   pq.StringArray `json:"required_roles" db:"required_roles"`
 	CreatedBy       string        `json:"created_by" db:"created_by" binding:"required"`
 	CreatedAt       time.Time     `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time     `json:"updated_at" db:"updated_at"`
@@ -31,6 +44,49 @@ const (
 	TransactionTransfer   TransactionType = "transfer"
 )
 
+// PaymentMethod represents how the money moved
+type PaymentMethod string
+
+const (
+	PaymentCash         PaymentMethod = "cash"
+	PaymentCheque       PaymentMethod = "cheque"
+	PaymentMpesa        PaymentMethod = "mpesa"
+	PaymentBankTransfer PaymentMethod = "bank_transfer"
+	PaymentCard         PaymentMethod = "card"
+)
+
+// ValidatePaymentMethod checks if the payment method is valid
+func ValidatePaymentMethod(method string) error {
+	switch PaymentMethod(method) {
+	case PaymentCash, PaymentCheque, PaymentMpesa, PaymentBankTransfer, PaymentCard:
+		return nil
+	default:
+		return ErrInvalidPaymentMethod
+	}
+}
+
+// TransactionStatus represents where a transaction is in the approval workflow.
+// Receipts are posted straight away; vouchers move from draft to posted.
+type TransactionStatus string
+
+const (
+	TransactionDraft     TransactionStatus = "draft"
+	TransactionSubmitted TransactionStatus = "submitted"
+	TransactionApproved  TransactionStatus = "approved"
+	TransactionRejected  TransactionStatus = "rejected"
+	TransactionPosted    TransactionStatus = "posted"
+)
+
+// IsVoucher reports whether the transaction pays money out and so needs approval before posting
+func (t *Transaction) IsVoucher() bool {
+	switch t.TransactionType {
+	case string(TransactionExpenses), string(TransactionWithdrawal), string(TransactionTransfer):
+		return true
+	default:
+		return false
+	}
+}
+
 // ValidateTransactionType checks if the transaction type is valid
 func (t *Transaction) ValidateTransactionType() error {
 	switch t.TransactionType {
@@ -50,6 +106,8 @@ type CreateTransactionRequest struct {
 	Notes           *string   `json:"notes"`
 	DebitAccountID  string    `json:"debit_account_id" binding:"required"`
 	MemberID        *string   `json:"member_id"`
+	PaymentMethod   *string   `json:"payment_method"`
+	PaymentReference *string  `json:"payment_reference" binding:"max=100"`
 }
 
 // UpdateTransactionRequest represents the request for updating a transaction
@@ -61,6 +119,14 @@ type UpdateTransactionRequest struct {
 	Notes           *string  `json:"notes"`
 	DebitAccountID  *string  `json:"debit_account_id"`
 	MemberID        *string  `json:"member_id"`
+	PaymentMethod   *string  `json:"payment_method"`
+	PaymentReference *string `json:"payment_reference" binding:"max=100"`
+}
+
+// UpdateTransactionPaymentRequest represents the request for recording how a transaction was paid
+type UpdateTransactionPaymentRequest struct {
+	PaymentMethod    *string `json:"payment_method"`
+	PaymentReference *string `json:"payment_reference" binding:"max=100"`
 }
 
 // TransactionResponse represents the transaction response
@@ -75,6 +141,14 @@ type TransactionResponse struct {
 	DebitAccount    *AccountResponse `json:"debit_account,omitempty"`
 	MemberID        *string         `json:"member_id"`
 	Member          *MemberResponse  `json:"member,omitempty"`
+	PaymentMethod   *string         `json:"payment_method"`
+	PaymentReference *string        `json:"payment_reference"`
+	CollectionSessionID *string     `json:"collection_session_id"`
+	Status          string          `json:"status"`
+	SubmittedBy     *string         `json:"submitted_by,omitempty"`
+	SubmittedAt     *time.Time      `json:"submitted_at,omitempty"`
+	PostedBy        *string         `json:"posted_by,omitempty"`
+	PostedAt        *time.Time      `json:"posted_at,omitempty"`
 	CreatedBy       string          `json:"created_by"`
 	CreatedAt       time.Time       `json:"created_at"`
 	UpdatedAt       time.Time       `json:"updated_at"`
@@ -103,6 +177,14 @@ func (t *Transaction) ToResponse() *TransactionResponse {
 		DebitAccount:    debitAccountResp,
 		MemberID:        t.MemberID,
 		Member:          memberResp,
+		PaymentMethod:   t.PaymentMethod,
+		PaymentReference: t.PaymentReference,
+		CollectionSessionID: t.CollectionSessionID,
+		Status:          t.Status,
+		SubmittedBy:     t.SubmittedBy,
+		SubmittedAt:     t.SubmittedAt,
+		PostedBy:        t.PostedBy,
+		PostedAt:        t.PostedAt,
 		CreatedBy:       t.CreatedBy,
 		CreatedAt:       t.CreatedAt,
 		UpdatedAt:       t.UpdatedAt,
@@ -7,11 +7,13 @@ import (
 // Transfer represents account transfers
 type Transfer struct {
 	ID            string      `json:"id" db:"id"`
+	ChurchID      string      `json:"-" db:"church_id"`
 	TransactionID string      `json:"transaction_id" db:"transaction_id" binding:"required"`
 	Transaction   *Transaction `json:"transaction,omitempty" db:"-"`
-	Particulars   string      `json:"particulars" db:"perticulars" binding:"required,max=255"`
+	Particulars   string      `json:"particulars" db:"particulars" binding:"required,max=255"`
 	CreditAccountID string    `json:"credit_account_id" db:"credit_account" binding:"required"`
 	CreditAccount *Account    `json:"credit_account,omitempty" db:"-"`
+	FundID        *string     `json:"fund_id" db:"fund"`
 	Amount        float64     `json:"amount" db:"amount" binding:"required"`
 	CreatedAt     time.Time   `json:"created_at" db:"created_at"`
 	UpdatedAt     time.Time   `json:"updated_at" db:"updated_at"`
@@ -22,6 +24,7 @@ type CreateTransferRequest struct {
 	TransactionID    string  `json:"transaction_id" binding:"required"`
 	Particulars      string  `json:"particulars" binding:"required,max=255"`
 	CreditAccountID  string  `json:"credit_account_id" binding:"required"`
+	FundID           *string `json:"fund_id"`
 	Amount           float64 `json:"amount" binding:"required"`
 }
 
@@ -29,6 +32,7 @@ type CreateTransferRequest struct {
 type UpdateTransferRequest struct {
 	Particulars      *string  `json:"particulars" binding:"max=255"`
 	CreditAccountID  *string  `json:"credit_account_id"`
+	FundID           *string  `json:"fund_id"`
 	Amount           *float64 `json:"amount"`
 }
 
@@ -40,6 +44,7 @@ type TransferResponse struct {
 	Particulars      string             `json:"particulars"`
 	CreditAccountID  string             `json:"credit_account_id"`
 	CreditAccount    *AccountResponse   `json:"credit_account,omitempty"`
+	FundID           *string            `json:"fund_id"`
 	Amount           float64            `json:"amount"`
 	CreatedAt        time.Time          `json:"created_at"`
 	UpdatedAt        time.Time          `json:"updated_at"`
@@ -64,6 +69,7 @@ func (t *Transfer) ToResponse() *TransferResponse {
 		Particulars:      t.Particulars,
 		CreditAccountID:  t.CreditAccountID,
 		CreditAccount:    creditAccountResp,
+		FundID:           t.FundID,
 		Amount:           t.Amount,
 		CreatedAt:        t.CreatedAt,
 		UpdatedAt:        t.UpdatedAt,
@@ -15,6 +15,7 @@ type User struct {
 	PhoneNumber string    `json:"phone_number" db:"phone_number" binding:"max=12"`
 	IsActive    bool      `json:"is_active" db:"is_active"`
 	LastLogin   *time.Time `json:"last_login" db:"last_login"`
+	CreatedIn   *string   `json:"-" db:"created_in"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -23,15 +24,16 @@ type User struct {
 type UserRole string
 
 const (
-	RoleAdmin     UserRole = "Admin"
-	RoleTreasurer UserRole = "Treasurer"
-	RoleClerk     UserRole = "Clerk"
+	RoleAdmin      UserRole = "Admin"
+	RoleTreasurer  UserRole = "Treasurer"
+	RoleClerk      UserRole = "Clerk"
+	RoleBoardChair UserRole = "BoardChair"
 )
 
 // ValidateRole checks if the user role is valid
 func (u *User) ValidateRole() error {
 	switch u.Role {
-	case string(RoleAdmin), string(RoleTreasurer), string(RoleClerk):
+	case string(RoleAdmin), string(RoleTreasurer), string(RoleClerk), string(RoleBoardChair):
 		return nil
 	default:
 		return ErrInvalidRole
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+)
+
+// VoucherApproval represents an approver's decision on a submitted voucher
+type VoucherApproval struct {
+	ID            string    `json:"id" db:"id"`
+	ChurchID      string    `json:"-" db:"church_id"`
+	TransactionID string    `json:"transaction_id" db:"transaction_id"`
+	ApproverID    string    `json:"approver_id" db:"approver"`
+	ApproverRole  *string   `json:"approver_role" db:"approver_role"`
+	Decision      string    `json:"decision" db:"decision"`
+	Comment       *string   `json:"comment" db:"comment"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// VoucherDecision represents the outcome an approver records
+type VoucherDecision string
+
+const (
+	VoucherApproved VoucherDecision = "approved"
+	VoucherRejected VoucherDecision = "rejected"
+)
+
+// ReviewVoucherRequest represents the request for approving or rejecting a voucher
+type ReviewVoucherRequest struct {
+	Comment *string `json:"comment"`
+}
+
+// VoucherApprovalResponse represents the voucher approval response
+type VoucherApprovalResponse struct {
+	ID            string    `json:"id"`
+	TransactionID string    `json:"transaction_id"`
+	ApproverID    string    `json:"approver_id"`
+	ApproverRole  *string   `json:"approver_role"`
+	Decision      string    `json:"decision"`
+	Comment       *string   `json:"comment"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// VoucherResponse represents a voucher with the total of its lines, what its approval
+// policies require and its approval trail
+type VoucherResponse struct {
+	*TransactionResponse
+	LinesTotal         float64                   `json:"lines_total"`
+	RequiredApprovals  int                       `json:"required_approvals"`
+	RequiredRoles      []string                  `json:"required_roles"`
+	ApprovalsRemaining int                       `json:"approvals_remaining"`
+	MissingRoles       []string                  `json:"missing_roles"`
+	Approvals          []VoucherApprovalResponse `json:"approvals"`
+}
+
+// ToResponse converts VoucherApproval to VoucherApprovalResponse
+func (a *VoucherApproval) ToResponse() *VoucherApprovalResponse {
+	return &VoucherApprovalResponse{
+		ID:            a.ID,
+		TransactionID: a.TransactionID,
+		ApproverID:    a.ApproverID,
+		ApproverRole:  a.ApproverRole,
+		Decision:      a.Decision,
+		Comment:       a.Comment,
+		CreatedAt:     a.CreatedAt,
+	}
+}
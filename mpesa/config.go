@@ -0,0 +1,30 @@
+package mpesa
+
+import (
+	"os"
+	"strings"
+)
+
+// Config holds the Paybill settings used when posting C2B payments to the ledger
+type Config struct {
+	// ShortCode is the Paybill number callbacks must be addressed to; empty accepts any
+	ShortCode string
+	// IncomeAccount is the Income account (ID or name) receipts are credited to when the
+	// account reference does not name another Income account
+	IncomeAccount string
+	// ReceivingAccount is the Bank account (ID or name) the Paybill settles into
+	ReceivingAccount string
+	// CallbackToken must be passed as the token query parameter on callbacks; without it
+	// the callbacks are not served
+	CallbackToken string
+}
+
+// LoadConfig reads the M-Pesa settings from environment variables
+func LoadConfig() Config {
+	return Config{
+		ShortCode:        strings.TrimSpace(os.Getenv("MPESA_SHORTCODE")),
+		IncomeAccount:    strings.TrimSpace(os.Getenv("MPESA_INCOME_ACCOUNT")),
+		ReceivingAccount: strings.TrimSpace(os.Getenv("MPESA_RECEIVING_ACCOUNT")),
+		CallbackToken:    strings.TrimSpace(os.Getenv("MPESA_CALLBACK_TOKEN")),
+	}
+}
@@ -0,0 +1,102 @@
+package mpesa
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// C2BCallback is the payload Daraja posts to the C2B validation and confirmation URLs
+type C2BCallback struct {
+	TransactionType   string `json:"TransactionType"`
+	TransID           string `json:"TransID"`
+	TransTime         string `json:"TransTime"`
+	TransAmount       string `json:"TransAmount"`
+	BusinessShortCode string `json:"BusinessShortCode"`
+	BillRefNumber     string `json:"BillRefNumber"`
+	InvoiceNumber     string `json:"InvoiceNumber"`
+	OrgAccountBalance string `json:"OrgAccountBalance"`
+	ThirdPartyTransID string `json:"ThirdPartyTransID"`
+	MSISDN            string `json:"MSISDN"`
+	FirstName         string `json:"FirstName"`
+	MiddleName        string `json:"MiddleName"`
+	LastName          string `json:"LastName"`
+}
+
+// C2BResponse is the acknowledgement returned to Daraja
+type C2BResponse struct {
+	ResultCode string `json:"ResultCode"`
+	ResultDesc string `json:"ResultDesc"`
+}
+
+// Daraja C2B validation result codes
+const (
+	ResultAccepted         = "0"
+	ResultInvalidMSISDN    = "C2B00011"
+	ResultInvalidAccount   = "C2B00012"
+	ResultInvalidAmount    = "C2B00013"
+	ResultInvalidShortCode = "C2B00015"
+	ResultOtherError       = "C2B00016"
+)
+
+// Accepted returns the response that accepts a validation request or acknowledges a confirmation
+func Accepted() C2BResponse {
+	return C2BResponse{ResultCode: ResultAccepted, ResultDesc: "Accepted"}
+}
+
+// Rejected returns a validation response rejecting the payment with the given code
+func Rejected(code, desc string) C2BResponse {
+	return C2BResponse{ResultCode: code, ResultDesc: desc}
+}
+
+// eastAfrica is the timezone Daraja reports TransTime in
+var eastAfrica = time.FixedZone("EAT", 3*60*60)
+
+// ParseTransTime parses a Daraja TransTime such as 20250131143005
+func ParseTransTime(value string) (time.Time, error) {
+	t, err := time.ParseInLocation("20060102150405", strings.TrimSpace(value), eastAfrica)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid TransTime %q", value)
+	}
+	return t, nil
+}
+
+// FormatTransTime formats a time the way Daraja reports TransTime
+func FormatTransTime(t time.Time) string {
+	return t.In(eastAfrica).Format("20060102150405")
+}
+
+// PayerName joins the payer's name parts
+func (c *C2BCallback) PayerName() string {
+	parts := make([]string, 0, 3)
+	for _, p := range []string{c.FirstName, c.MiddleName, c.LastName} {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+var nonDigits = regexp.MustCompile(`\D`)
+
+// PhoneVariants returns the ways a Kenyan mobile number may have been stored for a member,
+// e.g. 254712345678 gives 254712345678, +254712345678, 0712345678 and 712345678.
+// Values that are not a recognisable mobile number (such as hashed MSISDNs) give none.
+func PhoneVariants(phone string) []string {
+	digits := nonDigits.ReplaceAllString(phone, "")
+
+	var local string
+	switch {
+	case len(digits) == 12 && strings.HasPrefix(digits, "254"):
+		local = digits[3:]
+	case len(digits) == 10 && strings.HasPrefix(digits, "0"):
+		local = digits[1:]
+	case len(digits) == 9:
+		local = digits
+	default:
+		return nil
+	}
+
+	return []string{"254" + local, "+254" + local, "0" + local, local}
+}
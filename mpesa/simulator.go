@@ -0,0 +1,90 @@
+package mpesa
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Simulator posts sample Daraja C2B callbacks to a running server for local testing
+type Simulator struct {
+	BaseURL   string
+	ShortCode string
+	Token     string
+	Client    *http.Client
+}
+
+// NewSimulator creates a simulator targeting the API at baseURL, e.g. http://localhost:8080
+func NewSimulator(baseURL, shortCode, token string) *Simulator {
+	return &Simulator{
+		BaseURL:   strings.TrimRight(baseURL, "/"),
+		ShortCode: shortCode,
+		Token:     token,
+		Client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// SampleCallback builds a Paybill payment callback like the ones Daraja sends
+func (s *Simulator) SampleCallback(msisdn string, amount float64, billRef string) C2BCallback {
+	return C2BCallback{
+		TransactionType:   "Pay Bill",
+		TransID:           randomTransID(),
+		TransTime:         FormatTransTime(time.Now()),
+		TransAmount:       fmt.Sprintf("%.2f", amount),
+		BusinessShortCode: s.ShortCode,
+		BillRefNumber:     billRef,
+		MSISDN:            msisdn,
+		FirstName:         "JOHN",
+		MiddleName:        "",
+		LastName:          "DOE",
+	}
+}
+
+// Pay runs a full C2B flow: validation, then confirmation if the payment was accepted
+func (s *Simulator) Pay(callback C2BCallback) (validation, confirmation C2BResponse, err error) {
+	validation, err = s.post("/api/v1/mpesa/c2b/validation", callback)
+	if err != nil || validation.ResultCode != ResultAccepted {
+		return validation, confirmation, err
+	}
+
+	confirmation, err = s.post("/api/v1/mpesa/c2b/confirmation", callback)
+	return validation, confirmation, err
+}
+
+func (s *Simulator) post(path string, callback C2BCallback) (C2BResponse, error) {
+	body, err := json.Marshal(callback)
+	if err != nil {
+		return C2BResponse{}, err
+	}
+
+	url := s.BaseURL + path
+	if s.Token != "" {
+		url += "?token=" + s.Token
+	}
+
+	resp, err := s.Client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return C2BResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	var result C2BResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return C2BResponse{}, fmt.Errorf("unexpected response (%s): %v", resp.Status, err)
+	}
+	return result, nil
+}
+
+// randomTransID generates an M-Pesa style receipt number such as RKT3XYZ12A
+func randomTransID() string {
+	const chars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	id := make([]byte, 10)
+	for i := range id {
+		id[i] = chars[rand.Intn(len(chars))]
+	}
+	return string(id)
+}
@@ -0,0 +1,133 @@
+package notifications
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Africa's Talking messaging endpoints
+const (
+	africasTalkingURL        = "https://api.africastalking.com/version1/messaging"
+	africasTalkingSandboxURL = "https://api.sandbox.africastalking.com/version1/messaging"
+)
+
+// AfricasTalkingConfig holds the Africa's Talking credentials
+type AfricasTalkingConfig struct {
+	// Username is the application username; "sandbox" uses the sandbox API
+	Username string
+	APIKey   string
+	// SenderID is the registered alphanumeric sender ID or short code, if any
+	SenderID string
+}
+
+// AfricasTalking sends SMS through the Africa's Talking bulk messaging API
+type AfricasTalking struct {
+	Config  AfricasTalkingConfig
+	BaseURL string
+	Client  *http.Client
+}
+
+// NewAfricasTalking creates an Africa's Talking provider
+func NewAfricasTalking(cfg AfricasTalkingConfig) *AfricasTalking {
+	baseURL := africasTalkingURL
+	if cfg.Username == "sandbox" {
+		baseURL = africasTalkingSandboxURL
+	}
+	return &AfricasTalking{
+		Config:  cfg,
+		BaseURL: baseURL,
+		Client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name identifies the provider
+func (a *AfricasTalking) Name() string {
+	return ProviderAfricasTalking
+}
+
+// africasTalkingResponse is the messaging API response body
+type africasTalkingResponse struct {
+	SMSMessageData struct {
+		Message    string `json:"Message"`
+		Recipients []struct {
+			StatusCode int    `json:"statusCode"`
+			Number     string `json:"number"`
+			Status     string `json:"status"`
+			Cost       string `json:"cost"`
+			MessageID  string `json:"messageId"`
+		} `json:"Recipients"`
+	} `json:"SMSMessageData"`
+}
+
+// Send posts a single message to the messaging API
+func (a *AfricasTalking) Send(to, message string) (SendResult, error) {
+	if a.Config.Username == "" || a.Config.APIKey == "" {
+		return SendResult{}, errors.New("africa's talking username and API key are required")
+	}
+
+	form := url.Values{}
+	form.Set("username", a.Config.Username)
+	form.Set("to", to)
+	form.Set("message", message)
+	if a.Config.SenderID != "" {
+		form.Set("from", a.Config.SenderID)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, a.BaseURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return SendResult{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("apiKey", a.Config.APIKey)
+
+	resp, err := a.Client.Do(req)
+	if err != nil {
+		return SendResult{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return SendResult{}, fmt.Errorf("africa's talking returned %s", resp.Status)
+	}
+
+	var body africasTalkingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return SendResult{}, fmt.Errorf("invalid africa's talking response: %v", err)
+	}
+	if len(body.SMSMessageData.Recipients) == 0 {
+		return SendResult{}, fmt.Errorf("message not sent: %s", body.SMSMessageData.Message)
+	}
+
+	recipient := body.SMSMessageData.Recipients[0]
+	result := SendResult{
+		MessageID: recipient.MessageID,
+		Status:    recipient.Status,
+		Cost:      recipient.Cost,
+	}
+
+	// 100 Processed, 101 Sent and 102 Queued are accepted; anything else is a failure
+	if recipient.StatusCode < 100 || recipient.StatusCode > 102 {
+		return result, fmt.Errorf("message rejected: %s", recipient.Status)
+	}
+
+	return result, nil
+}
+
+// DeliveryStatus maps an Africa's Talking delivery report status to whether the message
+// reached the handset (delivered), failed for good (failed) or is still in flight
+func DeliveryStatus(status string) string {
+	switch status {
+	case "Success":
+		return "delivered"
+	case "Failed", "Rejected", "AbsentSubscriber", "Expired":
+		return "failed"
+	default:
+		return "sent"
+	}
+}
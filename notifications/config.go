@@ -0,0 +1,74 @@
+package notifications
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported SMS providers
+const (
+	ProviderAfricasTalking = "africastalking"
+	ProviderFile           = "file"
+)
+
+// Defaults used when the environment does not override them
+const (
+	DefaultMaxAttempts = 3
+	DefaultSendDelay   = 30 * time.Second
+	DefaultChurchName  = "StoreHouse"
+)
+
+// Config holds the SMS notification settings
+type Config struct {
+	// Provider selects the SMS gateway: africastalking, file, or empty to disable SMS
+	Provider string
+	// AfricasTalking holds the Africa's Talking credentials
+	AfricasTalking AfricasTalkingConfig
+	// LogFile is where the file provider writes messages; empty logs them instead
+	LogFile string
+	// MaxAttempts is how many times a message is tried before it is marked failed
+	MaxAttempts int
+	// SendDelay holds a receipt acknowledgement back so every receipt line on the
+	// transaction is listed in one message
+	SendDelay time.Duration
+	// ChurchName signs the messages
+	ChurchName string
+	// ReceiptTemplate overrides the receipt acknowledgement template
+	ReceiptTemplate string
+}
+
+// LoadConfig reads the SMS settings from environment variables
+func LoadConfig() Config {
+	cfg := Config{
+		Provider: strings.ToLower(strings.TrimSpace(os.Getenv("SMS_PROVIDER"))),
+		AfricasTalking: AfricasTalkingConfig{
+			Username: strings.TrimSpace(os.Getenv("AT_USERNAME")),
+			APIKey:   strings.TrimSpace(os.Getenv("AT_API_KEY")),
+			SenderID: strings.TrimSpace(os.Getenv("AT_SENDER_ID")),
+		},
+		LogFile:         strings.TrimSpace(os.Getenv("SMS_LOG_FILE")),
+		MaxAttempts:     DefaultMaxAttempts,
+		SendDelay:       DefaultSendDelay,
+		ChurchName:      strings.TrimSpace(os.Getenv("CHURCH_NAME")),
+		ReceiptTemplate: os.Getenv("SMS_RECEIPT_TEMPLATE"),
+	}
+
+	if attempts, err := strconv.Atoi(os.Getenv("SMS_MAX_ATTEMPTS")); err == nil && attempts > 0 {
+		cfg.MaxAttempts = attempts
+	}
+	if delay, err := time.ParseDuration(os.Getenv("SMS_SEND_DELAY")); err == nil && delay >= 0 {
+		cfg.SendDelay = delay
+	}
+	if cfg.ChurchName == "" {
+		cfg.ChurchName = DefaultChurchName
+	}
+
+	return cfg
+}
+
+// Enabled reports whether an SMS provider is configured
+func (c Config) Enabled() bool {
+	return c.Provider != ""
+}
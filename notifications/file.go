@@ -0,0 +1,58 @@
+package notifications
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FileProvider is a stand-in gateway for development and testing. It appends each message
+// to a file, or writes it to the log when no file is given, and always succeeds.
+type FileProvider struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// NewFileProvider creates a file provider writing to path
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{Path: path}
+}
+
+// Name identifies the provider
+func (f *FileProvider) Name() string {
+	return ProviderFile
+}
+
+// Send records the message
+func (f *FileProvider) Send(to, message string) (SendResult, error) {
+	result := SendResult{
+		MessageID: "file-" + uuid.New().String(),
+		Status:    "Success",
+		Cost:      "KES 0.0000",
+	}
+
+	entry := fmt.Sprintf("%s\t%s\t%s\t%s\n", time.Now().Format(time.RFC3339), result.MessageID, to, message)
+	if f.Path == "" {
+		log.Printf("📱 SMS to %s: %s", to, message)
+		return result, nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return SendResult{}, err
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(entry); err != nil {
+		return SendResult{}, err
+	}
+
+	return result, nil
+}
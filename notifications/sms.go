@@ -0,0 +1,41 @@
+package notifications
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SMSProvider sends text messages through an SMS gateway
+type SMSProvider interface {
+	// Name identifies the provider on stored notifications
+	Name() string
+	// Send delivers a message to a number in international format (e.g. +254712345678)
+	Send(to, message string) (SendResult, error)
+}
+
+// SendResult is the gateway's response to a single message
+type SendResult struct {
+	// MessageID is the gateway's ID, used to match delivery reports
+	MessageID string
+	// Status is the gateway's status for the message, e.g. Success or Queued
+	Status string
+	// Cost is the charge reported by the gateway, e.g. "KES 0.8000"
+	Cost string
+}
+
+// ErrDisabled is returned when no SMS provider is configured
+var ErrDisabled = errors.New("SMS notifications are not configured")
+
+// NewProvider builds the provider selected in the config
+func NewProvider(cfg Config) (SMSProvider, error) {
+	switch cfg.Provider {
+	case "":
+		return nil, ErrDisabled
+	case ProviderAfricasTalking:
+		return NewAfricasTalking(cfg.AfricasTalking), nil
+	case ProviderFile:
+		return NewFileProvider(cfg.LogFile), nil
+	default:
+		return nil, fmt.Errorf("unknown SMS provider %q", cfg.Provider)
+	}
+}
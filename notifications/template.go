@@ -0,0 +1,75 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultReceiptTemplate is the receipt acknowledgement sent to members
+const DefaultReceiptTemplate = `Dear {{.FirstName}}, we acknowledge with thanks KES {{money .Total}} received on {{date .Date}}:{{range .Lines}} {{.Account}} {{money .Amount}};{{end}} Ref {{.Reference}}. God bless you. - {{.ChurchName}}`
+
+// ReceiptLine is an amount given to one income account
+type ReceiptLine struct {
+	Account string
+	Amount  float64
+}
+
+// ReceiptMessage is the data available to the receipt template
+type ReceiptMessage struct {
+	MemberName string
+	FirstName  string
+	Reference  string
+	Date       time.Time
+	Total      float64
+	Lines      []ReceiptLine
+	ChurchName string
+}
+
+var templateFuncs = template.FuncMap{
+	"money": FormatMoney,
+	"date": func(t time.Time) string {
+		return t.Format("02/01/2006")
+	},
+}
+
+// RenderReceipt renders a receipt acknowledgement with the given template, or the default
+// when tmpl is empty
+func RenderReceipt(tmpl string, msg ReceiptMessage) (string, error) {
+	if strings.TrimSpace(tmpl) == "" {
+		tmpl = DefaultReceiptTemplate
+	}
+	if msg.FirstName == "" {
+		if fields := strings.Fields(msg.MemberName); len(fields) > 0 {
+			msg.FirstName = fields[0]
+		}
+	}
+
+	t, err := template.New("receipt").Funcs(templateFuncs).Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid receipt template: %v", err)
+	}
+
+	var b strings.Builder
+	if err := t.Execute(&b, msg); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// FormatMoney formats an amount with thousands separators, e.g. 12,500.00
+func FormatMoney(amount float64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	s := fmt.Sprintf("%.2f", amount)
+	whole, cents := s[:len(s)-3], s[len(s)-3:]
+	for i := len(whole) - 3; i > 0; i -= 3 {
+		whole = whole[:i] + "," + whole[i:]
+	}
+	return sign + whole + cents
+}
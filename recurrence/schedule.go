@@ -0,0 +1,228 @@
+package recurrence
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchLimit bounds how far ahead Next looks before giving up on a schedule
+// that can never fire, such as 30 February
+const searchLimit = 5
+
+// Shorthand expressions accepted in place of the five cron fields
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+	"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+}
+
+var dayNames = map[string]int{
+	"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+}
+
+// Schedule is a parsed cron expression: minute, hour, day of month, month and day
+// of week. Day of month also accepts L for the last day of the month.
+type Schedule struct {
+	minute   []bool
+	hour     []bool
+	dom      []bool
+	month    []bool
+	dow      []bool
+	lastDay  bool
+	domStar  bool
+	dowStar  bool
+	location *time.Location
+}
+
+// Parse reads a five-field cron expression or one of the @yearly, @monthly, @weekly,
+// @daily and @hourly shorthands. Times are evaluated in the server's local time zone.
+func Parse(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if expanded, ok := descriptors[strings.ToLower(expr)]; ok {
+		expr = expanded
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return Schedule{}, fmt.Errorf("schedule %q must have 5 fields: minute hour day-of-month month day-of-week", expr)
+	}
+
+	s := Schedule{location: time.Local}
+	var err error
+
+	if s.minute, _, err = parseField(fields[0], 0, 59, nil); err != nil {
+		return Schedule{}, fmt.Errorf("minute: %w", err)
+	}
+	if s.hour, _, err = parseField(fields[1], 0, 23, nil); err != nil {
+		return Schedule{}, fmt.Errorf("hour: %w", err)
+	}
+
+	domField := fields[2]
+	if parts := strings.Split(domField, ","); containsLast(parts) {
+		s.lastDay = true
+		domField = strings.Join(removeLast(parts), ",")
+	}
+	if domField == "" {
+		s.dom = make([]bool, 32)
+	} else if s.dom, s.domStar, err = parseField(domField, 1, 31, nil); err != nil {
+		return Schedule{}, fmt.Errorf("day of month: %w", err)
+	}
+
+	if s.month, _, err = parseField(fields[3], 1, 12, monthNames); err != nil {
+		return Schedule{}, fmt.Errorf("month: %w", err)
+	}
+
+	if s.dow, s.dowStar, err = parseField(fields[4], 0, 7, dayNames); err != nil {
+		return Schedule{}, fmt.Errorf("day of week: %w", err)
+	}
+	// 7 is another way of writing Sunday
+	if s.dow[7] {
+		s.dow[0] = true
+	}
+
+	return s, nil
+}
+
+// Next returns the first time after the given time that the schedule fires, or the
+// zero time when it never fires again
+func (s Schedule) Next(after time.Time) time.Time {
+	loc := s.location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	t := after.In(loc).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(searchLimit, 0, 0)
+
+	for t.Before(limit) {
+		if !s.month[t.Month()] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if !s.minute[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+// dayMatches follows cron: when both day fields are restricted either may match,
+// otherwise the restricted one decides
+func (s Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom[t.Day()] || (s.lastDay && t.AddDate(0, 0, 1).Day() == 1)
+	dowMatch := s.dow[t.Weekday()]
+
+	if s.lastDay {
+		if s.dowStar {
+			return domMatch
+		}
+		return domMatch || dowMatch
+	}
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// parseField expands a comma separated list of values, ranges and steps into the set
+// of matching values, reporting whether the field was a plain *
+func parseField(field string, min, max int, names map[string]int) ([]bool, bool, error) {
+	set := make([]bool, max+1)
+	star := field == "*" || field == "?"
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return nil, false, fmt.Errorf("invalid step in %q", part)
+			}
+			rangePart, step = part[:i], n
+		}
+
+		low, high := min, max
+		switch {
+		case rangePart == "*" || rangePart == "?":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if low, err = parseValue(bounds[0], names); err != nil {
+				return nil, false, err
+			}
+			if high, err = parseValue(bounds[1], names); err != nil {
+				return nil, false, err
+			}
+		default:
+			value, err := parseValue(rangePart, names)
+			if err != nil {
+				return nil, false, err
+			}
+			low = value
+			// A single value with a step runs to the end of the range, as in 5/15
+			if !strings.Contains(part, "/") {
+				high = value
+			}
+		}
+
+		if low < min || high > max || low > high {
+			return nil, false, fmt.Errorf("%q is outside %d-%d", part, min, max)
+		}
+		for v := low; v <= high; v += step {
+			set[v] = true
+		}
+	}
+
+	return set, star, nil
+}
+
+func parseValue(value string, names map[string]int) (int, error) {
+	if n, ok := names[strings.ToUpper(value)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	return n, nil
+}
+
+func containsLast(parts []string) bool {
+	for _, p := range parts {
+		if strings.EqualFold(p, "L") {
+			return true
+		}
+	}
+	return false
+}
+
+func removeLast(parts []string) []string {
+	var kept []string
+	for _, p := range parts {
+		if !strings.EqualFold(p, "L") {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
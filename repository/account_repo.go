@@ -1,15 +1,27 @@
 package repository
 
 import (
+	"context"
+	"storeHouse/database"
 	"storeHouse/models"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 )
 
-func executeQuery(db *sqlx.DB, query string, acc models.Account) (models.Account, error) {
-	_, err := db.NamedExec(query, acc)
+// accountSubtree selects the account passed as $1 and every account below it in the chart,
+// so totals asked for a header account roll up its children
+const accountSubtree = `WITH RECURSIVE subtree AS (
+	SELECT id FROM accounts WHERE id = $1
+	UNION ALL
+	SELECT a.id FROM accounts a JOIN subtree s ON a.parent = s.id
+) SELECT id FROM subtree`
+
+func (p *Postgres) executeQuery(ctx context.Context, query string, acc models.Account) (models.Account, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, acc)
 	if err != nil {
 		return models.Account{}, err
 	}
@@ -17,27 +29,27 @@ func executeQuery(db *sqlx.DB, query string, acc models.Account) (models.Account
 	return acc, nil
 }
 
-func CreateAccount(db *sqlx.DB, acc models.Account) (models.Account, error) {
+func (p *Postgres) CreateAccount(ctx context.Context, acc models.Account) (models.Account, error) {
 	acc.ID = uuid.New().String()
 	acc.CreatedAt = time.Now()
 	acc.UpdatedAt = time.Now()
 
-	query := `INSERT INTO accounts (id, account_name, account_type, local_share, notes, is_active, created_at, updated_at)
-              VALUES (:id, :account_name, :account_type, :local_share, :notes, :is_active, :created_at, :updated_at)`
+	query := `INSERT INTO accounts (id, account_name, account_type, code, parent, is_header, local_share, notes, is_active, created_at, updated_at)
+              VALUES (:id, :account_name, :account_type, :code, :parent, :is_header, :local_share, :notes, :is_active, :created_at, :updated_at)`
 
-	return executeQuery(db, query, acc)
+	return p.executeQuery(ctx, query, acc)
 }
 
-func UpdateAccount(db *sqlx.DB, acc models.Account) (models.Account, error) {
+func (p *Postgres) UpdateAccount(ctx context.Context, acc models.Account) (models.Account, error) {
 	acc.UpdatedAt = time.Now()
 
-	query := `UPDATE accounts SET account_name = :account_name, account_type = :account_type, local_share = :local_share, notes = :notes, is_active = :is_active, updated_at = :updated_at 
+	query := `UPDATE accounts SET account_name = :account_name, account_type = :account_type, code = :code, parent = :parent, is_header = :is_header, local_share = :local_share, notes = :notes, is_active = :is_active, updated_at = :updated_at 
 			  WHERE id = :id`
 
-	return executeQuery(db, query, acc)
+	return p.executeQuery(ctx, query, acc)
 }
 
-func DeactivateAccount(db *sqlx.DB, id string) (models.Account, error) {
+func (p *Postgres) DeactivateAccount(ctx context.Context, id string) (models.Account, error) {
 	acc := models.Account{
 		ID:        id,
 		IsActive:  false,
@@ -47,13 +59,15 @@ func DeactivateAccount(db *sqlx.DB, id string) (models.Account, error) {
 	query := `UPDATE accounts SET is_active = :is_active, updated_at = :updated_at
 			  WHERE id = :id`
 
-	return executeQuery(db, query, acc)
+	return p.executeQuery(ctx, query, acc)
 }
 
-func GetAccount(db *sqlx.DB, id string) (models.Account, error) {
+func (p *Postgres) GetAccount(ctx context.Context, id string) (models.Account, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var acc models.Account
-	
-	err := db.Get(&acc, "SELECT * FROM accounts WHERE id = $1", id)
+	err := p.DB.GetContext(ctx, &acc, "SELECT * FROM accounts WHERE id = $1", id)
 	if err != nil {
 		return models.Account{}, err
 	}
@@ -61,9 +75,12 @@ func GetAccount(db *sqlx.DB, id string) (models.Account, error) {
 	return acc, nil
 }
 
-func GetAccountByName(db *sqlx.DB, name string) (models.Account, error) {
+func (p *Postgres) GetAccountByName(ctx context.Context, name string) (models.Account, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var acc models.Account
-	err := db.Get(&acc, "SELECT * FROM accounts WHERE account_name = $1", name)
+	err := p.DB.GetContext(ctx, &acc, "SELECT * FROM accounts WHERE account_name = $1", name)
 	if err != nil {
 		return models.Account{}, err
 	}
@@ -71,12 +88,91 @@ func GetAccountByName(db *sqlx.DB, name string) (models.Account, error) {
 	return acc, nil
 }
 
-func GetAllAccounts(db *sqlx.DB) ([]models.Account, error) {
+func (p *Postgres) GetAllAccounts(ctx context.Context) ([]models.Account, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var accs []models.Account
-	err := db.Select(&accs, "SELECT * FROM accounts WHERE is_active = true ORDER BY account_name ASC")
+	err := p.DB.SelectContext(ctx, &accs, "SELECT * FROM accounts WHERE is_active = true ORDER BY code ASC NULLS LAST, account_name ASC")
 	if err != nil {
 		return nil, err
 	}
 
 	return accs, nil
 }
+
+func (p *Postgres) GetAccountByCode(ctx context.Context, code string) (models.Account, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var acc models.Account
+	err := p.DB.GetContext(ctx, &acc, "SELECT * FROM accounts WHERE code = $1", code)
+	if err != nil {
+		return models.Account{}, err
+	}
+
+	return acc, nil
+}
+
+// GetChartOfAccounts returns the accounts in code order, leaving out inactive ones unless asked
+func (p *Postgres) GetChartOfAccounts(ctx context.Context, includeInactive bool) ([]models.Account, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var accs []models.Account
+	query := "SELECT * FROM accounts WHERE is_active = true OR $1 ORDER BY code ASC NULLS LAST, account_name ASC"
+	err := p.DB.SelectContext(ctx, &accs, query, includeInactive)
+	if err != nil {
+		return nil, err
+	}
+
+	return accs, nil
+}
+
+// GetAccountSubtreeIDs returns the account and every account below it in the chart
+func (p *Postgres) GetAccountSubtreeIDs(ctx context.Context, id string) ([]string, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var ids []string
+	err := p.DB.SelectContext(ctx, &ids, accountSubtree, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// CountChildAccounts returns how many accounts sit directly under an account
+func (p *Postgres) CountChildAccounts(ctx context.Context, id string) (int, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var count int
+	err := p.DB.GetContext(ctx, &count, "SELECT COUNT(*) FROM accounts WHERE parent = $1", id)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// CountAccountPostings returns how many transaction, receipt, expenditure, transfer and
+// budget lines are posted to an account
+func (p *Postgres) CountAccountPostings(ctx context.Context, id string) (int, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var count int
+	query := `SELECT (SELECT COUNT(*) FROM transactions WHERE debit_account = $1)
+			  + (SELECT COUNT(*) FROM receipts WHERE income_account = $1)
+			  + (SELECT COUNT(*) FROM expenditures WHERE bank_account = $1)
+			  + (SELECT COUNT(*) FROM transfers WHERE credit_account = $1)
+			  + (SELECT COUNT(*) FROM budgets WHERE account = $1)`
+	err := p.DB.GetContext(ctx, &count, query, id)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
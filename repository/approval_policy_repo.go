@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"storeHouse/database"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func (p *Postgres) executeApprovalPolicyQuery(ctx context.Context, query string, policy models.ApprovalPolicy) (models.ApprovalPolicy, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	if policy.RequiredRoles == nil {
+		policy.RequiredRoles = pq.StringArray{}
+	}
+
+	_, err := p.DB.NamedExecContext(ctx, query, policy)
+	if err != nil {
+		return models.ApprovalPolicy{}, err
+	}
+
+	return policy, nil
+}
+
+func (p *Postgres) CreateApprovalPolicy(ctx context.Context, policy models.ApprovalPolicy) (models.ApprovalPolicy, error) {
+	policy.ID = uuid.New().String()
+	policy.CreatedAt = time.Now()
+	policy.UpdatedAt = time.Now()
+
+	query := `INSERT INTO approval_policies (id, name, description, transaction_type, account, amount_over, amount_up_to, required_approvals, required_roles, is_active, created_by, created_at, updated_at)
+              VALUES (:id, :name, :description, :transaction_type, :account, :amount_over, :amount_up_to, :required_approvals, :required_roles, :is_active, :created_by, :created_at, :updated_at)`
+
+	return p.executeApprovalPolicyQuery(ctx, query, policy)
+}
+
+func (p *Postgres) UpdateApprovalPolicy(ctx context.Context, policy models.ApprovalPolicy) (models.ApprovalPolicy, error) {
+	policy.UpdatedAt = time.Now()
+
+	query := `UPDATE approval_policies SET name = :name, description = :description, transaction_type = :transaction_type, account = :account, amount_over = :amount_over,
+			  amount_up_to = :amount_up_to, required_approvals = :required_approvals, required_roles = :required_roles, is_active = :is_active, updated_at = :updated_at
+			  WHERE id = :id`
+
+	return p.executeApprovalPolicyQuery(ctx, query, policy)
+}
+
+func (p *Postgres) DeleteApprovalPolicy(ctx context.Context, id string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM approval_policies WHERE id = $1", id)
+	return err
+}
+
+func (p *Postgres) GetApprovalPolicy(ctx context.Context, id string) (models.ApprovalPolicy, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var policy models.ApprovalPolicy
+	err := p.DB.GetContext(ctx, &policy, "SELECT * FROM approval_policies WHERE id = $1", id)
+	if err != nil {
+		return models.ApprovalPolicy{}, err
+	}
+
+	return policy, nil
+}
+
+func (p *Postgres) GetAllApprovalPolicies(ctx context.Context) ([]models.ApprovalPolicy, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var policies []models.ApprovalPolicy
+	err := p.DB.SelectContext(ctx, &policies, "SELECT * FROM approval_policies ORDER BY amount_over, name")
+	if err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+func (p *Postgres) GetActiveApprovalPolicies(ctx context.Context) ([]models.ApprovalPolicy, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var policies []models.ApprovalPolicy
+	err := p.DB.SelectContext(ctx, &policies, "SELECT * FROM approval_policies WHERE is_active = true ORDER BY amount_over, name")
+	if err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
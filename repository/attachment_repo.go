@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"storeHouse/database"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateAttachment records an attachment; the caller sets the ID, which also names the stored file
+func (p *Postgres) CreateAttachment(ctx context.Context, attachment models.Attachment) (models.Attachment, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	if attachment.ID == "" {
+		attachment.ID = uuid.New().String()
+	}
+	attachment.CreatedAt = time.Now()
+	attachment.UpdatedAt = time.Now()
+
+	query := `INSERT INTO attachments (id, transaction_id, expenditure_id, transfer_id, file_name, content_type, size_bytes, checksum_sha256, storage_key, description, uploaded_by, created_at, updated_at)
+              VALUES (:id, :transaction_id, :expenditure_id, :transfer_id, :file_name, :content_type, :size_bytes, :checksum_sha256, :storage_key, :description, :uploaded_by, :created_at, :updated_at)`
+
+	_, err := p.DB.NamedExecContext(ctx, query, attachment)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+
+	return attachment, nil
+}
+
+func (p *Postgres) DeleteAttachment(ctx context.Context, id string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM attachments WHERE id = $1", id)
+	return err
+}
+
+func (p *Postgres) GetAttachment(ctx context.Context, id string) (models.Attachment, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var attachment models.Attachment
+	err := p.DB.GetContext(ctx, &attachment, "SELECT * FROM attachments WHERE id = $1", id)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+
+	return attachment, nil
+}
+
+// GetAttachmentsByEntity returns the attachments linked to a transaction, expenditure or transfer
+func (p *Postgres) GetAttachmentsByEntity(ctx context.Context, entity models.AttachmentEntity, entityID string) ([]models.Attachment, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var attachments []models.Attachment
+	err := p.DB.SelectContext(ctx, &attachments, "SELECT * FROM attachments WHERE "+entity.Column()+" = $1 ORDER BY created_at", entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// GetAttachmentsByTransactionTree returns the attachments of a transaction and of its
+// expenditure and transfer lines
+func (p *Postgres) GetAttachmentsByTransactionTree(ctx context.Context, transactionID string) ([]models.Attachment, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var attachments []models.Attachment
+	err := p.DB.SelectContext(ctx, &attachments, `SELECT * FROM attachments
+		WHERE transaction_id = $1
+		   OR expenditure_id IN (SELECT id FROM expenditures WHERE transaction_id = $1)
+		   OR transfer_id IN (SELECT id FROM transfers WHERE transaction_id = $1)
+		ORDER BY created_at`, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"storeHouse/database"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (p *Postgres) executeBankStatementQuery(ctx context.Context, query string, statement models.BankStatement) (models.BankStatement, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, statement)
+	if err != nil {
+		return models.BankStatement{}, err
+	}
+
+	return statement, nil
+}
+
+func (p *Postgres) CreateBankStatement(ctx context.Context, statement models.BankStatement) (models.BankStatement, error) {
+	statement.ID = uuid.New().String()
+	statement.CreatedAt = time.Now()
+	statement.UpdatedAt = time.Now()
+
+	query := `INSERT INTO bank_statements (id, bank_account, file_name, format, period_start, period_end, opening_balance, closing_balance, created_by, created_at, updated_at)
+              VALUES (:id, :bank_account, :file_name, :format, :period_start, :period_end, :opening_balance, :closing_balance, :created_by, :created_at, :updated_at)`
+
+	return p.executeBankStatementQuery(ctx, query, statement)
+}
+
+func (p *Postgres) UpdateBankStatement(ctx context.Context, statement models.BankStatement) (models.BankStatement, error) {
+	statement.UpdatedAt = time.Now()
+
+	query := `UPDATE bank_statements SET period_start = :period_start, period_end = :period_end, opening_balance = :opening_balance, closing_balance = :closing_balance, updated_at = :updated_at
+			  WHERE id = :id`
+
+	return p.executeBankStatementQuery(ctx, query, statement)
+}
+
+func (p *Postgres) DeleteBankStatement(ctx context.Context, id string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM bank_statements WHERE id = $1", id)
+	return err
+}
+
+func (p *Postgres) GetBankStatement(ctx context.Context, id string) (models.BankStatement, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var statement models.BankStatement
+	err := p.DB.GetContext(ctx, &statement, "SELECT * FROM bank_statements WHERE id = $1", id)
+	if err != nil {
+		return models.BankStatement{}, err
+	}
+
+	return statement, nil
+}
+
+func (p *Postgres) GetBankStatementsByAccount(ctx context.Context, accountID string) ([]models.BankStatement, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var statements []models.BankStatement
+	err := p.DB.SelectContext(ctx, &statements, "SELECT * FROM bank_statements WHERE bank_account = $1 ORDER BY period_end DESC NULLS LAST, created_at DESC", accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return statements, nil
+}
+
+// GetLatestBankStatement returns the most recent statement for an account ending on or before asOf
+func (p *Postgres) GetLatestBankStatement(ctx context.Context, accountID string, asOf time.Time) (models.BankStatement, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var statement models.BankStatement
+	query := `SELECT * FROM bank_statements WHERE bank_account = $1 AND period_end <= $2
+			  ORDER BY period_end DESC, created_at DESC LIMIT 1`
+	err := p.DB.GetContext(ctx, &statement, query, accountID, asOf)
+	if err != nil {
+		return models.BankStatement{}, err
+	}
+
+	return statement, nil
+}
+
+func (p *Postgres) executeStatementLineQuery(ctx context.Context, query string, line models.BankStatementLine) (models.BankStatementLine, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, line)
+	if err != nil {
+		return models.BankStatementLine{}, err
+	}
+
+	return line, nil
+}
+
+func (p *Postgres) CreateStatementLine(ctx context.Context, line models.BankStatementLine) (models.BankStatementLine, error) {
+	line.ID = uuid.New().String()
+	line.CreatedAt = time.Now()
+	line.UpdatedAt = time.Now()
+
+	query := `INSERT INTO bank_statement_lines (id, statement_id, bank_account, line_date, description, reference, amount, balance, fit_id, created_at, updated_at)
+              VALUES (:id, :statement_id, :bank_account, :line_date, :description, :reference, :amount, :balance, :fit_id, :created_at, :updated_at)`
+
+	return p.executeStatementLineQuery(ctx, query, line)
+}
+
+// MatchStatementLine records the transaction a statement line clears
+func (p *Postgres) MatchStatementLine(ctx context.Context, id, transactionID string, matchType models.MatchType) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	query := `UPDATE bank_statement_lines SET transaction_id = $2, match_type = $3, matched_at = $4, updated_at = $4
+			  WHERE id = $1`
+	_, err := p.DB.ExecContext(ctx, query, id, transactionID, string(matchType), time.Now())
+	return err
+}
+
+func (p *Postgres) UnmatchStatementLine(ctx context.Context, id string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	query := `UPDATE bank_statement_lines SET transaction_id = NULL, match_type = NULL, matched_at = NULL, updated_at = $2
+			  WHERE id = $1`
+	_, err := p.DB.ExecContext(ctx, query, id, time.Now())
+	return err
+}
+
+func (p *Postgres) GetStatementLine(ctx context.Context, id string) (models.BankStatementLine, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var line models.BankStatementLine
+	err := p.DB.GetContext(ctx, &line, "SELECT * FROM bank_statement_lines WHERE id = $1", id)
+	if err != nil {
+		return models.BankStatementLine{}, err
+	}
+
+	return line, nil
+}
+
+func (p *Postgres) GetStatementLines(ctx context.Context, statementID string) ([]models.BankStatementLine, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var lines []models.BankStatementLine
+	err := p.DB.SelectContext(ctx, &lines, "SELECT * FROM bank_statement_lines WHERE statement_id = $1 ORDER BY line_date, created_at", statementID)
+	if err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// GetStatementLineByFitID finds a previously imported line by its bank-assigned OFX identifier
+func (p *Postgres) GetStatementLineByFitID(ctx context.Context, accountID, fitID string) (models.BankStatementLine, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var line models.BankStatementLine
+	err := p.DB.GetContext(ctx, &line, "SELECT * FROM bank_statement_lines WHERE bank_account = $1 AND fit_id = $2", accountID, fitID)
+	if err != nil {
+		return models.BankStatementLine{}, err
+	}
+
+	return line, nil
+}
+
+// GetStatementLineByTransaction finds the line, if any, that clears a transaction on an account
+func (p *Postgres) GetStatementLineByTransaction(ctx context.Context, accountID, transactionID string) (models.BankStatementLine, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var line models.BankStatementLine
+	err := p.DB.GetContext(ctx, &line, "SELECT * FROM bank_statement_lines WHERE bank_account = $1 AND transaction_id = $2", accountID, transactionID)
+	if err != nil {
+		return models.BankStatementLine{}, err
+	}
+
+	return line, nil
+}
+
+// GetStatementLinesByAccount returns all statement lines for an account dated on or before asOf
+func (p *Postgres) GetStatementLinesByAccount(ctx context.Context, accountID string, asOf time.Time) ([]models.BankStatementLine, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var lines []models.BankStatementLine
+	query := "SELECT * FROM bank_statement_lines WHERE bank_account = $1 AND line_date <= $2 ORDER BY line_date, created_at"
+	err := p.DB.SelectContext(ctx, &lines, query, accountID, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+func (p *Postgres) GetTotalStatementLines(ctx context.Context, statementID string) (float64, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var total float64
+	err := p.DB.GetContext(ctx, &total, "SELECT COALESCE(SUM(amount), 0) FROM bank_statement_lines WHERE statement_id = $1", statementID)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// GetBankBookEntries returns the net effect of each transaction on a Bank account up to asOf:
+// receipts and transfers debited to the account are money in, while expenditures paid from
+// it and transfers credited out of it are money out. Vouchers still awaiting approval are left out.
+func (p *Postgres) GetBankBookEntries(ctx context.Context, accountID string, asOf time.Time) ([]models.BankBookEntry, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var entries []models.BankBookEntry
+	query := `SELECT t.id AS transaction_id, t.transaction_ref, t.transaction_date, t.transaction_type, t.notes, t.amount
+			  FROM transactions t
+			  WHERE t.debit_account IN (` + accountSubtree + `) AND t.transaction_type IN ('receipts', 'transfer') AND t.status = 'posted' AND t.transaction_date <= $2
+			  UNION ALL
+			  SELECT t.id, t.transaction_ref, t.transaction_date, t.transaction_type, t.notes, -SUM(e.amount)
+			  FROM expenditures e JOIN transactions t ON t.id = e.transaction_id
+			  WHERE e.bank_account IN (` + accountSubtree + `) AND t.status = 'posted' AND t.transaction_date <= $2
+			  GROUP BY t.id
+			  UNION ALL
+			  SELECT t.id, t.transaction_ref, t.transaction_date, t.transaction_type, t.notes, -SUM(tr.amount)
+			  FROM transfers tr JOIN transactions t ON t.id = tr.transaction_id
+			  WHERE tr.credit_account IN (` + accountSubtree + `) AND t.status = 'posted' AND t.transaction_date <= $2
+			  GROUP BY t.id
+			  ORDER BY transaction_date, transaction_id`
+	err := p.DB.SelectContext(ctx, &entries, query, accountID, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// GetMatchedTransactionIDs returns every transaction already cleared by a line on the account
+func (p *Postgres) GetMatchedTransactionIDs(ctx context.Context, accountID string) ([]string, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var ids []string
+	err := p.DB.SelectContext(ctx, &ids, "SELECT transaction_id FROM bank_statement_lines WHERE bank_account = $1 AND transaction_id IS NOT NULL", accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"storeHouse/database"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (p *Postgres) executeBudgetQuery(ctx context.Context, query string, budget models.Budget) (models.Budget, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, budget)
+	if err != nil {
+		return models.Budget{}, err
+	}
+
+	return budget, nil
+}
+
+// SaveBudget inserts a monthly budget line, replacing the amount if the account already
+// has a budget for that month
+func (p *Postgres) SaveBudget(ctx context.Context, budget models.Budget) (models.Budget, error) {
+	budget.ID = uuid.New().String()
+	budget.CreatedAt = time.Now()
+	budget.UpdatedAt = time.Now()
+
+	query := `INSERT INTO budgets (id, account, fiscal_year, month, amount, notes, created_by, created_at, updated_at)
+              VALUES (:id, :account, :fiscal_year, :month, :amount, :notes, :created_by, :created_at, :updated_at)
+              ON CONFLICT (account, fiscal_year, month)
+              DO UPDATE SET amount = EXCLUDED.amount, notes = EXCLUDED.notes, updated_at = EXCLUDED.updated_at`
+
+	if _, err := p.executeBudgetQuery(ctx, query, budget); err != nil {
+		return models.Budget{}, err
+	}
+
+	return p.GetBudgetByMonth(ctx, budget.AccountID, budget.Year, budget.Month)
+}
+
+func (p *Postgres) UpdateBudget(ctx context.Context, budget models.Budget) (models.Budget, error) {
+	budget.UpdatedAt = time.Now()
+
+	query := `UPDATE budgets SET amount = :amount, notes = :notes, updated_at = :updated_at
+			  WHERE id = :id`
+
+	return p.executeBudgetQuery(ctx, query, budget)
+}
+
+func (p *Postgres) DeleteBudgetsByAccount(ctx context.Context, accountID string, year int) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM budgets WHERE account = $1 AND fiscal_year = $2", accountID, year)
+	return err
+}
+
+func (p *Postgres) GetBudget(ctx context.Context, id string) (models.Budget, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var budget models.Budget
+	err := p.DB.GetContext(ctx, &budget, "SELECT * FROM budgets WHERE id = $1", id)
+	if err != nil {
+		return models.Budget{}, err
+	}
+
+	return budget, nil
+}
+
+func (p *Postgres) GetBudgetByMonth(ctx context.Context, accountID string, year, month int) (models.Budget, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var budget models.Budget
+	err := p.DB.GetContext(ctx, &budget, "SELECT * FROM budgets WHERE account = $1 AND fiscal_year = $2 AND month = $3", accountID, year, month)
+	if err != nil {
+		return models.Budget{}, err
+	}
+
+	return budget, nil
+}
+
+func (p *Postgres) GetBudgetsByAccount(ctx context.Context, accountID string, year int) ([]models.Budget, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var budgets []models.Budget
+	err := p.DB.SelectContext(ctx, &budgets, "SELECT * FROM budgets WHERE account = $1 AND fiscal_year = $2 ORDER BY month", accountID, year)
+	if err != nil {
+		return nil, err
+	}
+
+	return budgets, nil
+}
+
+func (p *Postgres) GetBudgetsByYear(ctx context.Context, year int) ([]models.Budget, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var budgets []models.Budget
+	err := p.DB.SelectContext(ctx, &budgets, "SELECT * FROM budgets WHERE fiscal_year = $1 ORDER BY account, month", year)
+	if err != nil {
+		return nil, err
+	}
+
+	return budgets, nil
+}
+
+// GetBudgetTotal returns the budgeted amount for an account over a range of months in a year
+func (p *Postgres) GetBudgetTotal(ctx context.Context, accountID string, year, fromMonth, toMonth int) (float64, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var total float64
+	query := "SELECT COALESCE(SUM(amount), 0) FROM budgets WHERE account IN (" + accountSubtree + ") AND fiscal_year = $2 AND month BETWEEN $3 AND $4"
+	err := p.DB.GetContext(ctx, &total, query, accountID, year, fromMonth, toMonth)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// CountBudgets reports whether an account has any budget lines for a year
+func (p *Postgres) CountBudgets(ctx context.Context, accountID string, year int) (int, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var count int
+	err := p.DB.GetContext(ctx, &count, "SELECT COUNT(*) FROM budgets WHERE account = $1 AND fiscal_year = $2", accountID, year)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
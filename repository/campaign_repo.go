@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"storeHouse/database"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (p *Postgres) executeCampaignQuery(ctx context.Context, query string, campaign models.Campaign) (models.Campaign, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, campaign)
+	if err != nil {
+		return models.Campaign{}, err
+	}
+
+	return campaign, nil
+}
+
+func (p *Postgres) CreateCampaign(ctx context.Context, campaign models.Campaign) (models.Campaign, error) {
+	campaign.ID = uuid.New().String()
+
+	// Set default start date if not provided
+	if campaign.StartDate.IsZero() {
+		campaign.StartDate = time.Now()
+	}
+
+	campaign.CreatedAt = time.Now()
+	campaign.UpdatedAt = time.Now()
+
+	query := `INSERT INTO campaigns (id, campaign_name, income_account, target_amount, start_date, end_date, notes, is_active, created_by, created_at, updated_at)
+              VALUES (:id, :campaign_name, :income_account, :target_amount, :start_date, :end_date, :notes, :is_active, :created_by, :created_at, :updated_at)`
+
+	return p.executeCampaignQuery(ctx, query, campaign)
+}
+
+func (p *Postgres) UpdateCampaign(ctx context.Context, campaign models.Campaign) (models.Campaign, error) {
+	campaign.UpdatedAt = time.Now()
+
+	query := `UPDATE campaigns SET campaign_name = :campaign_name, target_amount = :target_amount, start_date = :start_date, end_date = :end_date, notes = :notes, is_active = :is_active, updated_at = :updated_at 
+			  WHERE id = :id`
+
+	return p.executeCampaignQuery(ctx, query, campaign)
+}
+
+func (p *Postgres) GetCampaign(ctx context.Context, id string) (models.Campaign, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var campaign models.Campaign
+	err := p.DB.GetContext(ctx, &campaign, "SELECT * FROM campaigns WHERE id = $1", id)
+	if err != nil {
+		return models.Campaign{}, err
+	}
+
+	return campaign, nil
+}
+
+func (p *Postgres) GetCampaignByName(ctx context.Context, name string) (models.Campaign, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var campaign models.Campaign
+	err := p.DB.GetContext(ctx, &campaign, "SELECT * FROM campaigns WHERE campaign_name = $1", name)
+	if err != nil {
+		return models.Campaign{}, err
+	}
+
+	return campaign, nil
+}
+
+func (p *Postgres) GetAllCampaigns(ctx context.Context) ([]models.Campaign, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var campaigns []models.Campaign
+	err := p.DB.SelectContext(ctx, &campaigns, "SELECT * FROM campaigns ORDER BY start_date DESC")
+	if err != nil {
+		return nil, err
+	}
+
+	return campaigns, nil
+}
+
+func (p *Postgres) GetCampaignsByAccount(ctx context.Context, accountID string) ([]models.Campaign, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var campaigns []models.Campaign
+	err := p.DB.SelectContext(ctx, &campaigns, "SELECT * FROM campaigns WHERE income_account = $1 ORDER BY start_date DESC", accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return campaigns, nil
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"storeHouse/database"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (p *Postgres) executeChequeQuery(ctx context.Context, query string, cheque models.Cheque) (models.Cheque, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, cheque)
+	if err != nil {
+		return models.Cheque{}, err
+	}
+
+	return cheque, nil
+}
+
+func (p *Postgres) CreateCheque(ctx context.Context, cheque models.Cheque) (models.Cheque, error) {
+	cheque.ID = uuid.New().String()
+	cheque.CreatedAt = time.Now()
+	cheque.UpdatedAt = time.Now()
+
+	query := `INSERT INTO cheques (id, cheque_number, bank_account, transaction_id, payee, payee_name, amount, issue_date, status, notes, created_by, created_at, updated_at)
+              VALUES (:id, :cheque_number, :bank_account, :transaction_id, :payee, :payee_name, :amount, :issue_date, :status, :notes, :created_by, :created_at, :updated_at)`
+
+	return p.executeChequeQuery(ctx, query, cheque)
+}
+
+func (p *Postgres) UpdateCheque(ctx context.Context, cheque models.Cheque) (models.Cheque, error) {
+	cheque.UpdatedAt = time.Now()
+
+	query := `UPDATE cheques SET status = :status, presented_date = :presented_date, cancelled_at = :cancelled_at, cancel_reason = :cancel_reason,
+			  notes = :notes, updated_at = :updated_at
+			  WHERE id = :id`
+
+	return p.executeChequeQuery(ctx, query, cheque)
+}
+
+func (p *Postgres) GetCheque(ctx context.Context, id string) (models.Cheque, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var cheque models.Cheque
+	err := p.DB.GetContext(ctx, &cheque, "SELECT * FROM cheques WHERE id = $1", id)
+	if err != nil {
+		return models.Cheque{}, err
+	}
+
+	return cheque, nil
+}
+
+func (p *Postgres) GetChequeByNumber(ctx context.Context, bankAccountID, chequeNumber string) (models.Cheque, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var cheque models.Cheque
+	err := p.DB.GetContext(ctx, &cheque, "SELECT * FROM cheques WHERE bank_account = $1 AND cheque_number = $2", bankAccountID, chequeNumber)
+	if err != nil {
+		return models.Cheque{}, err
+	}
+
+	return cheque, nil
+}
+
+// GetCheques lists cheques, optionally narrowed to a bank account and status, newest first
+func (p *Postgres) GetCheques(ctx context.Context, bankAccountID, status string) ([]models.Cheque, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var cheques []models.Cheque
+	query := `SELECT * FROM cheques
+			  WHERE ($1 = '' OR bank_account::text = $1) AND ($2 = '' OR status = $2)
+			  ORDER BY issue_date DESC, cheque_number DESC`
+	err := p.DB.SelectContext(ctx, &cheques, query, bankAccountID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	return cheques, nil
+}
+
+func (p *Postgres) GetChequesByTransaction(ctx context.Context, transactionID string) ([]models.Cheque, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var cheques []models.Cheque
+	err := p.DB.SelectContext(ctx, &cheques, "SELECT * FROM cheques WHERE transaction_id = $1 ORDER BY issue_date", transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return cheques, nil
+}
+
+// GetChequesIssuedBy returns the cheques on a bank account issued on or before asOf that were
+// still unpresented and uncancelled at that time
+func (p *Postgres) GetChequesIssuedBy(ctx context.Context, bankAccountID string, asOf time.Time) ([]models.Cheque, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var cheques []models.Cheque
+	query := `SELECT * FROM cheques
+			  WHERE bank_account = $1 AND issue_date <= $2
+			    AND (presented_date IS NULL OR presented_date > $2)
+			    AND (cancelled_at IS NULL OR cancelled_at > $2)
+			  ORDER BY issue_date, cheque_number`
+	err := p.DB.SelectContext(ctx, &cheques, query, bankAccountID, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	return cheques, nil
+}
+
+// GetChequeBankAccounts returns the IDs of the accounts that have cheques
+func (p *Postgres) GetChequeBankAccounts(ctx context.Context) ([]string, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var ids []string
+	err := p.DB.SelectContext(ctx, &ids, "SELECT DISTINCT bank_account FROM cheques")
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// MarkStaleCheques flags issued cheques that can no longer be banked, returning how many changed
+func (p *Postgres) MarkStaleCheques(ctx context.Context, staleBefore time.Time) (int64, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	result, err := p.DB.ExecContext(ctx, "UPDATE cheques SET status = 'stale', updated_at = $2 WHERE status = 'issued' AND issue_date < $1", staleBefore, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
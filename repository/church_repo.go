@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"storeHouse/database"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (p *Postgres) executeChurchQuery(ctx context.Context, query string, church models.Church) (models.Church, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, church)
+	if err != nil {
+		return models.Church{}, err
+	}
+
+	return church, nil
+}
+
+// CreateChurch adds a church and makes the creating user its admin
+func (p *Postgres) CreateChurch(ctx context.Context, church models.Church, adminID string) (models.Church, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	church.ID = uuid.New().String()
+	church.CreatedAt = time.Now()
+	church.UpdatedAt = time.Now()
+
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return models.Church{}, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO churches (id, name, code, mpesa_shortcode, is_active, created_at, updated_at)
+              VALUES (:id, :name, :code, :mpesa_shortcode, :is_active, :created_at, :updated_at)`
+	if _, err := tx.NamedExecContext(ctx, query, church); err != nil {
+		return models.Church{}, err
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO church_users (id, church_id, user_id, role) VALUES ($1, $2, $3, $4)",
+		uuid.New().String(), church.ID, adminID, string(models.RoleAdmin)); err != nil {
+		return models.Church{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Church{}, err
+	}
+
+	return church, nil
+}
+
+func (p *Postgres) UpdateChurch(ctx context.Context, church models.Church) (models.Church, error) {
+	church.UpdatedAt = time.Now()
+
+	query := `UPDATE churches SET name = :name, mpesa_shortcode = :mpesa_shortcode, is_active = :is_active, updated_at = :updated_at
+			  WHERE id = :id`
+
+	return p.executeChurchQuery(ctx, query, church)
+}
+
+func (p *Postgres) GetChurch(ctx context.Context, id string) (models.Church, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var church models.Church
+	err := p.DB.GetContext(ctx, &church, "SELECT * FROM churches WHERE id = $1", id)
+	if err != nil {
+		return models.Church{}, err
+	}
+
+	return church, nil
+}
+
+func (p *Postgres) GetChurchByCode(ctx context.Context, code string) (models.Church, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var church models.Church
+	err := p.DB.GetContext(ctx, &church, "SELECT * FROM churches WHERE code = $1", code)
+	if err != nil {
+		return models.Church{}, err
+	}
+
+	return church, nil
+}
+
+func (p *Postgres) GetChurchByShortcode(ctx context.Context, shortcode string) (models.Church, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var church models.Church
+	err := p.DB.GetContext(ctx, &church, "SELECT * FROM churches WHERE mpesa_shortcode = $1 AND is_active = true", shortcode)
+	if err != nil {
+		return models.Church{}, err
+	}
+
+	return church, nil
+}
+
+func (p *Postgres) GetActiveChurches(ctx context.Context) ([]models.Church, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var churches []models.Church
+	err := p.DB.SelectContext(ctx, &churches, "SELECT * FROM churches WHERE is_active = true ORDER BY name ASC")
+	if err != nil {
+		return nil, err
+	}
+
+	return churches, nil
+}
+
+// GetUserChurches returns the churches a user can work in, with their role in each
+func (p *Postgres) GetUserChurches(ctx context.Context, userID string) ([]models.UserChurch, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var churches []models.UserChurch
+	query := `SELECT c.*, cu.role
+			  FROM church_users cu
+			  JOIN churches c ON c.id = cu.church_id
+			  WHERE cu.user_id = $1
+			  ORDER BY c.name ASC`
+	err := p.DB.SelectContext(ctx, &churches, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return churches, nil
+}
+
+// GetChurchRole returns the role a user works under in a church; it fails when the user
+// is not one of the church's users
+func (p *Postgres) GetChurchRole(ctx context.Context, churchID, userID string) (string, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var role string
+	query := `SELECT role FROM church_users WHERE church_id = $1 AND user_id = $2`
+	err := p.DB.GetContext(ctx, &role, query, churchID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	return role, nil
+}
+
+func (p *Postgres) GetChurchUsers(ctx context.Context, churchID string) ([]models.ChurchUserResponse, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var users []models.ChurchUserResponse
+	query := `SELECT u.id AS user_id, u.username, u.full_name, cu.role, u.is_active
+			  FROM church_users cu JOIN users u ON u.id = cu.user_id
+			  WHERE cu.church_id = $1
+			  ORDER BY u.full_name ASC`
+	err := p.DB.SelectContext(ctx, &users, query, churchID)
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// SaveChurchUser gives a user access to a church, or changes their role there
+func (p *Postgres) SaveChurchUser(ctx context.Context, member models.ChurchUser) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	member.ID = uuid.New().String()
+	member.CreatedAt = time.Now()
+
+	query := `INSERT INTO church_users (id, church_id, user_id, role, created_at)
+              VALUES (:id, :church_id, :user_id, :role, :created_at)
+			  ON CONFLICT (church_id, user_id) DO UPDATE SET role = EXCLUDED.role`
+	_, err := p.DB.NamedExecContext(ctx, query, member)
+	return err
+}
+
+func (p *Postgres) RemoveChurchUser(ctx context.Context, churchID, userID string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM church_users WHERE church_id = $1 AND user_id = $2", churchID, userID)
+	return err
+}
+
+// CountChurchAdmins counts the users who work in a church as admins
+func (p *Postgres) CountChurchAdmins(ctx context.Context, churchID string) (int, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var count int
+	query := `SELECT COUNT(*) FROM church_users cu JOIN users u ON u.id = cu.user_id
+			  WHERE cu.church_id = $1 AND cu.role = 'Admin' AND u.is_active = true`
+	err := p.DB.GetContext(ctx, &count, query, churchID)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"storeHouse/database"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (p *Postgres) executeCollectionSessionQuery(ctx context.Context, query string, session models.CollectionSession) (models.CollectionSession, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, session)
+	if err != nil {
+		return models.CollectionSession{}, err
+	}
+
+	return session, nil
+}
+
+func (p *Postgres) CreateCollectionSession(ctx context.Context, session models.CollectionSession) (models.CollectionSession, error) {
+	session.ID = uuid.New().String()
+	session.CreatedAt = time.Now()
+	session.UpdatedAt = time.Now()
+
+	query := `INSERT INTO collection_sessions (id, service_name, service_date, bank_account, status, notes, created_by, created_at, updated_at)
+              VALUES (:id, :service_name, :service_date, :bank_account, :status, :notes, :created_by, :created_at, :updated_at)`
+
+	return p.executeCollectionSessionQuery(ctx, query, session)
+}
+
+func (p *Postgres) UpdateCollectionSession(ctx context.Context, session models.CollectionSession) (models.CollectionSession, error) {
+	session.UpdatedAt = time.Now()
+
+	query := `UPDATE collection_sessions SET status = :status, notes = :notes, cancelled_by = :cancelled_by, cancelled_at = :cancelled_at, updated_at = :updated_at
+			  WHERE id = :id`
+
+	return p.executeCollectionSessionQuery(ctx, query, session)
+}
+
+func (p *Postgres) GetCollectionSession(ctx context.Context, id string) (models.CollectionSession, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var session models.CollectionSession
+	err := p.DB.GetContext(ctx, &session, "SELECT * FROM collection_sessions WHERE id = $1", id)
+	if err != nil {
+		return models.CollectionSession{}, err
+	}
+
+	return session, nil
+}
+
+// GetCollectionSessions lists sessions, optionally narrowed to a status, latest service first
+func (p *Postgres) GetCollectionSessions(ctx context.Context, status string) ([]models.CollectionSession, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var sessions []models.CollectionSession
+	query := `SELECT * FROM collection_sessions WHERE ($1 = '' OR status = $1)
+			  ORDER BY service_date DESC, created_at DESC`
+	err := p.DB.SelectContext(ctx, &sessions, query, status)
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (p *Postgres) GetTransactionsByCollectionSession(ctx context.Context, sessionID string) ([]models.Transaction, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var txns []models.Transaction
+	err := p.DB.SelectContext(ctx, &txns, "SELECT * FROM transactions WHERE collection_session = $1 ORDER BY created_at", sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return txns, nil
+}
+
+// DeleteCollectionReceipt removes a held receipt and its lines
+func (p *Postgres) DeleteCollectionReceipt(ctx context.Context, transactionID string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM receipts WHERE transaction_id = $1", transactionID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM transactions WHERE id = $1 AND status = 'draft'", transactionID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (p *Postgres) GetCollectionDenominations(ctx context.Context, sessionID string) ([]models.CollectionDenomination, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var denominations []models.CollectionDenomination
+	err := p.DB.SelectContext(ctx, &denominations, "SELECT * FROM collection_denominations WHERE session_id = $1 ORDER BY denomination DESC", sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return denominations, nil
+}
+
+// ReplaceCollectionDenominations swaps a session's cash count for the given one
+func (p *Postgres) ReplaceCollectionDenominations(ctx context.Context, sessionID string, denominations []models.CollectionDenomination) ([]models.CollectionDenomination, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM collection_denominations WHERE session_id = $1", sessionID); err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO collection_denominations (id, session_id, denomination, quantity)
+              VALUES (:id, :session_id, :denomination, :quantity)`
+
+	for i := range denominations {
+		denominations[i].ID = uuid.New().String()
+		denominations[i].SessionID = sessionID
+		if _, err := tx.NamedExecContext(ctx, query, denominations[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return denominations, nil
+}
+
+func (p *Postgres) GetCollectionSignOffs(ctx context.Context, sessionID string) ([]models.CollectionSignOff, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var signOffs []models.CollectionSignOff
+	err := p.DB.SelectContext(ctx, &signOffs, "SELECT * FROM collection_signoffs WHERE session_id = $1 ORDER BY signed_at", sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return signOffs, nil
+}
+
+// ClearCollectionSignOffs drops a session's sign-offs once the figures they confirmed change
+func (p *Postgres) ClearCollectionSignOffs(ctx context.Context, sessionID string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM collection_signoffs WHERE session_id = $1", sessionID)
+	return err
+}
+
+// SignOffCollectionSession records a counter's totals, replacing any earlier sign-off of
+// theirs. Once required counters have confirmed the same totals, the session's held
+// receipts are posted and the session closed in the same transaction. It reports whether
+// the session was posted.
+func (p *Postgres) SignOffCollectionSession(ctx context.Context, signOff models.CollectionSignOff, required int) (bool, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// Counters signing off at the same moment wait for each other here
+	if _, err := tx.ExecContext(ctx, "SELECT id FROM collection_sessions WHERE id = $1 FOR UPDATE", signOff.SessionID); err != nil {
+		return false, err
+	}
+
+	signOff.ID = uuid.New().String()
+	signOff.SignedAt = time.Now()
+
+	query := `INSERT INTO collection_signoffs (id, session_id, user_id, cash_total, receipts_total, signed_at)
+              VALUES (:id, :session_id, :user_id, :cash_total, :receipts_total, :signed_at)
+              ON CONFLICT (session_id, user_id) DO UPDATE
+              SET cash_total = EXCLUDED.cash_total, receipts_total = EXCLUDED.receipts_total, signed_at = EXCLUDED.signed_at`
+	if _, err := tx.NamedExecContext(ctx, query, signOff); err != nil {
+		return false, err
+	}
+
+	var matching int
+	err = tx.GetContext(ctx, &matching, `SELECT COUNT(*) FROM collection_signoffs
+			  WHERE session_id = $1 AND cash_total = $2 AND receipts_total = $3`, signOff.SessionID, signOff.CashTotal, signOff.ReceiptsTotal)
+	if err != nil {
+		return false, err
+	}
+	if matching < required {
+		return false, tx.Commit()
+	}
+
+	now := time.Now()
+	query = `UPDATE transactions SET status = 'posted', posted_by = $2, posted_at = $3, updated_at = $3
+			  WHERE collection_session = $1 AND status = 'draft'`
+	if _, err := tx.ExecContext(ctx, query, signOff.SessionID, signOff.UserID, now); err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE collection_sessions SET status = 'posted', posted_at = $2, updated_at = $2 WHERE id = $1", signOff.SessionID, now); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"storeHouse/database"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateDistrict adds a district and makes the creating user its admin
+func (p *Postgres) CreateDistrict(ctx context.Context, district models.District, adminID string) (models.District, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	district.ID = uuid.New().String()
+	district.CreatedAt = time.Now()
+	district.UpdatedAt = time.Now()
+
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return models.District{}, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO districts (id, name, code, is_active, created_at, updated_at)
+              VALUES (:id, :name, :code, :is_active, :created_at, :updated_at)`
+	if _, err := tx.NamedExecContext(ctx, query, district); err != nil {
+		return models.District{}, err
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO district_users (id, district_id, user_id, role) VALUES ($1, $2, $3, $4)",
+		uuid.New().String(), district.ID, adminID, string(models.DistrictAdmin)); err != nil {
+		return models.District{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.District{}, err
+	}
+
+	return district, nil
+}
+
+func (p *Postgres) GetDistrict(ctx context.Context, id string) (models.District, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var district models.District
+	err := p.DB.GetContext(ctx, &district, "SELECT * FROM districts WHERE id = $1", id)
+	if err != nil {
+		return models.District{}, err
+	}
+
+	return district, nil
+}
+
+func (p *Postgres) GetDistrictByCode(ctx context.Context, code string) (models.District, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var district models.District
+	err := p.DB.GetContext(ctx, &district, "SELECT * FROM districts WHERE code = $1", code)
+	if err != nil {
+		return models.District{}, err
+	}
+
+	return district, nil
+}
+
+// GetUserDistricts returns the districts a user holds a role in
+func (p *Postgres) GetUserDistricts(ctx context.Context, userID string) ([]models.UserDistrict, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var districts []models.UserDistrict
+	query := `SELECT d.*, du.role
+			  FROM district_users du
+			  JOIN districts d ON d.id = du.district_id
+			  WHERE du.user_id = $1
+			  ORDER BY d.name ASC`
+	err := p.DB.SelectContext(ctx, &districts, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return districts, nil
+}
+
+// GetDistrictRole returns a user's role in a district; it fails when the user has none
+func (p *Postgres) GetDistrictRole(ctx context.Context, districtID, userID string) (string, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var role string
+	query := `SELECT du.role FROM district_users du JOIN users u ON u.id = du.user_id
+			  WHERE du.district_id = $1 AND du.user_id = $2 AND u.is_active = true`
+	err := p.DB.GetContext(ctx, &role, query, districtID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	return role, nil
+}
+
+// GetDistrictChurches returns the churches that report to a district
+func (p *Postgres) GetDistrictChurches(ctx context.Context, districtID string) ([]models.Church, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var churches []models.Church
+	err := p.DB.SelectContext(ctx, &churches, "SELECT * FROM churches WHERE district_id = $1 ORDER BY name ASC", districtID)
+	if err != nil {
+		return nil, err
+	}
+
+	return churches, nil
+}
+
+// SetChurchDistrict places a church in a district, or takes it out when districtID is nil
+func (p *Postgres) SetChurchDistrict(ctx context.Context, churchID string, districtID *string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "UPDATE churches SET district_id = $2, updated_at = $3 WHERE id = $1", churchID, districtID, time.Now())
+	return err
+}
+
+func (p *Postgres) GetDistrictUsers(ctx context.Context, districtID string) ([]models.DistrictUserResponse, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var users []models.DistrictUserResponse
+	query := `SELECT u.id AS user_id, u.username, u.full_name, du.role, u.is_active
+			  FROM district_users du JOIN users u ON u.id = du.user_id
+			  WHERE du.district_id = $1
+			  ORDER BY u.full_name ASC`
+	err := p.DB.SelectContext(ctx, &users, query, districtID)
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// SaveDistrictUser gives a user a role in a district, or changes it
+func (p *Postgres) SaveDistrictUser(ctx context.Context, districtID, userID, role string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	query := `INSERT INTO district_users (id, district_id, user_id, role, created_at)
+              VALUES ($1, $2, $3, $4, $5)
+			  ON CONFLICT (district_id, user_id) DO UPDATE SET role = EXCLUDED.role`
+	_, err := p.DB.ExecContext(ctx, query, uuid.New().String(), districtID, userID, role, time.Now())
+	return err
+}
+
+func (p *Postgres) RemoveDistrictUser(ctx context.Context, districtID, userID string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM district_users WHERE district_id = $1 AND user_id = $2", districtID, userID)
+	return err
+}
+
+// CountDistrictAdmins counts the active users who administer a district
+func (p *Postgres) CountDistrictAdmins(ctx context.Context, districtID string) (int, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var count int
+	query := `SELECT COUNT(*) FROM district_users du JOIN users u ON u.id = du.user_id
+			  WHERE du.district_id = $1 AND du.role = 'DistrictAdmin' AND u.is_active = true`
+	err := p.DB.GetContext(ctx, &count, query, districtID)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"storeHouse/database"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (p *Postgres) executeEmailQuery(ctx context.Context, query string, email models.Email) (models.Email, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, email)
+	if err != nil {
+		return models.Email{}, err
+	}
+
+	return email, nil
+}
+
+// CreateEmail queues an email together with its attachments
+func (p *Postgres) CreateEmail(ctx context.Context, email models.Email, attachments []models.EmailAttachment) (models.Email, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	email.ID = uuid.New().String()
+	email.CreatedAt = time.Now()
+	email.UpdatedAt = time.Now()
+
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return models.Email{}, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO email_outbox (id, kind, member, recipient, subject, body_text, body_html, status, attempts, next_attempt_at, created_by, created_at, updated_at)
+              VALUES (:id, :kind, :member, :recipient, :subject, :body_text, :body_html, :status, :attempts, :next_attempt_at, :created_by, :created_at, :updated_at)`
+	if _, err := tx.NamedExecContext(ctx, query, email); err != nil {
+		return models.Email{}, err
+	}
+
+	for _, a := range attachments {
+		a.ID = uuid.New().String()
+		a.EmailID = email.ID
+		a.CreatedAt = time.Now()
+
+		query := `INSERT INTO email_attachments (id, email_id, file_name, content_type, data, created_at)
+                  VALUES (:id, :email_id, :file_name, :content_type, :data, :created_at)`
+		if _, err := tx.NamedExecContext(ctx, query, a); err != nil {
+			return models.Email{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Email{}, err
+	}
+
+	return email, nil
+}
+
+func (p *Postgres) UpdateEmail(ctx context.Context, email models.Email) (models.Email, error) {
+	email.UpdatedAt = time.Now()
+
+	query := `UPDATE email_outbox SET status = :status, attempts = :attempts, last_error = :last_error, next_attempt_at = :next_attempt_at, sent_at = :sent_at, bounced_at = :bounced_at, updated_at = :updated_at
+			  WHERE id = :id`
+
+	return p.executeEmailQuery(ctx, query, email)
+}
+
+func (p *Postgres) GetEmail(ctx context.Context, id string) (models.Email, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var email models.Email
+	err := p.DB.GetContext(ctx, &email, "SELECT * FROM email_outbox WHERE id = $1", id)
+	if err != nil {
+		return models.Email{}, err
+	}
+
+	return email, nil
+}
+
+func (p *Postgres) GetEmailAttachments(ctx context.Context, emailID string) ([]models.EmailAttachment, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var attachments []models.EmailAttachment
+	err := p.DB.SelectContext(ctx, &attachments, "SELECT * FROM email_attachments WHERE email_id = $1 ORDER BY created_at", emailID)
+	if err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// GetDueEmails returns queued emails whose next attempt time has passed
+func (p *Postgres) GetDueEmails(ctx context.Context, asOf time.Time, limit int) ([]models.Email, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var emails []models.Email
+	err := p.DB.SelectContext(ctx, &emails, `SELECT * FROM email_outbox WHERE status = 'queued' AND next_attempt_at <= $1
+			  ORDER BY next_attempt_at LIMIT $2`, asOf, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return emails, nil
+}
+
+func (p *Postgres) GetEmailsByStatus(ctx context.Context, status string) ([]models.Email, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var emails []models.Email
+	err := p.DB.SelectContext(ctx, &emails, "SELECT * FROM email_outbox WHERE status = $1 ORDER BY created_at DESC", status)
+	if err != nil {
+		return nil, err
+	}
+
+	return emails, nil
+}
+
+func (p *Postgres) GetAllEmails(ctx context.Context) ([]models.Email, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var emails []models.Email
+	err := p.DB.SelectContext(ctx, &emails, "SELECT * FROM email_outbox ORDER BY created_at DESC")
+	if err != nil {
+		return nil, err
+	}
+
+	return emails, nil
+}
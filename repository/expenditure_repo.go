@@ -1,15 +1,19 @@
 package repository
 
 import (
+	"context"
+	"storeHouse/database"
 	"storeHouse/models"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 )
 
-func executeExpenditureQuery(db *sqlx.DB, query string, exp models.Expenditure) (models.Expenditure, error) {
-	_, err := db.NamedExec(query, exp)
+func (p *Postgres) executeExpenditureQuery(ctx context.Context, query string, exp models.Expenditure) (models.Expenditure, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, exp)
 	if err != nil {
 		return models.Expenditure{}, err
 	}
@@ -17,34 +21,40 @@ func executeExpenditureQuery(db *sqlx.DB, query string, exp models.Expenditure)
 	return exp, nil
 }
 
-func CreateExpenditure(db *sqlx.DB, exp models.Expenditure) (models.Expenditure, error) {
+func (p *Postgres) CreateExpenditure(ctx context.Context, exp models.Expenditure) (models.Expenditure, error) {
 	exp.ID = uuid.New().String()
 	exp.CreatedAt = time.Now()
 	exp.UpdatedAt = time.Now()
 
-	query := `INSERT INTO expenditures (id, transaction_id, perticulars, bank_account, amount, created_at, updated_at)
-              VALUES (:id, :transaction_id, :particulars, :bank_account_id, :amount, :created_at, :updated_at)`
+	query := `INSERT INTO expenditures (id, transaction_id, particulars, bank_account, payee, fund, amount, created_at, updated_at)
+              VALUES (:id, :transaction_id, :particulars, :bank_account, :payee, :fund, :amount, :created_at, :updated_at)`
 
-	return executeExpenditureQuery(db, query, exp)
+	return p.executeExpenditureQuery(ctx, query, exp)
 }
 
-func UpdateExpenditure(db *sqlx.DB, exp models.Expenditure) (models.Expenditure, error) {
+func (p *Postgres) UpdateExpenditure(ctx context.Context, exp models.Expenditure) (models.Expenditure, error) {
 	exp.UpdatedAt = time.Now()
 
-	query := `UPDATE expenditures SET perticulars = :particulars, bank_account = :bank_account_id, amount = :amount, updated_at = :updated_at 
+	query := `UPDATE expenditures SET particulars = :particulars, bank_account = :bank_account, payee = :payee, fund = :fund, amount = :amount, updated_at = :updated_at 
 			  WHERE id = :id`
 
-	return executeExpenditureQuery(db, query, exp)
+	return p.executeExpenditureQuery(ctx, query, exp)
 }
 
-func DeleteExpenditure(db *sqlx.DB, id string) error {
-	_, err := db.Exec("DELETE FROM expenditures WHERE id = $1", id)
+func (p *Postgres) DeleteExpenditure(ctx context.Context, id string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM expenditures WHERE id = $1", id)
 	return err
 }
 
-func GetExpenditure(db *sqlx.DB, id string) (models.Expenditure, error) {
+func (p *Postgres) GetExpenditure(ctx context.Context, id string) (models.Expenditure, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var exp models.Expenditure
-	err := db.Get(&exp, "SELECT * FROM expenditures WHERE id = $1", id)
+	err := p.DB.GetContext(ctx, &exp, "SELECT * FROM expenditures WHERE id = $1", id)
 	if err != nil {
 		return models.Expenditure{}, err
 	}
@@ -52,9 +62,12 @@ func GetExpenditure(db *sqlx.DB, id string) (models.Expenditure, error) {
 	return exp, nil
 }
 
-func GetExpenditureByTransaction(db *sqlx.DB, transactionID string) ([]models.Expenditure, error) {
+func (p *Postgres) GetExpenditureByTransaction(ctx context.Context, transactionID string) ([]models.Expenditure, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var expenses []models.Expenditure
-	err := db.Select(&expenses, "SELECT * FROM expenditures WHERE transaction_id = $1 ORDER BY created_at DESC", transactionID)
+	err := p.DB.SelectContext(ctx, &expenses, "SELECT * FROM expenditures WHERE transaction_id = $1 ORDER BY created_at DESC", transactionID)
 	if err != nil {
 		return nil, err
 	}
@@ -62,9 +75,12 @@ func GetExpenditureByTransaction(db *sqlx.DB, transactionID string) ([]models.Ex
 	return expenses, nil
 }
 
-func GetExpenditureByAccount(db *sqlx.DB, accountID string) ([]models.Expenditure, error) {
+func (p *Postgres) GetExpenditureByAccount(ctx context.Context, accountID string) ([]models.Expenditure, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var expenses []models.Expenditure
-	err := db.Select(&expenses, "SELECT * FROM expenditures WHERE bank_account = $1 ORDER BY created_at DESC", accountID)
+	err := p.DB.SelectContext(ctx, &expenses, "SELECT * FROM expenditures WHERE bank_account IN ("+accountSubtree+") ORDER BY created_at DESC", accountID)
 	if err != nil {
 		return nil, err
 	}
@@ -72,9 +88,12 @@ func GetExpenditureByAccount(db *sqlx.DB, accountID string) ([]models.Expenditur
 	return expenses, nil
 }
 
-func GetExpenditureByDateRange(db *sqlx.DB, startDate, endDate time.Time) ([]models.Expenditure, error) {
+func (p *Postgres) GetExpenditureByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.Expenditure, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var expenses []models.Expenditure
-	err := db.Select(&expenses, "SELECT * FROM expenditures WHERE created_at BETWEEN $1 AND $2 ORDER BY created_at DESC", startDate, endDate)
+	err := p.DB.SelectContext(ctx, &expenses, "SELECT * FROM expenditures WHERE created_at BETWEEN $1 AND $2 ORDER BY created_at DESC", startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
@@ -82,12 +101,46 @@ func GetExpenditureByDateRange(db *sqlx.DB, startDate, endDate time.Time) ([]mod
 	return expenses, nil
 }
 
-func GetAllExpenditures(db *sqlx.DB) ([]models.Expenditure, error) {
+func (p *Postgres) GetAllExpenditures(ctx context.Context) ([]models.Expenditure, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var expenses []models.Expenditure
-	err := db.Select(&expenses, "SELECT * FROM expenditures ORDER BY created_at DESC")
+	err := p.DB.SelectContext(ctx, &expenses, "SELECT * FROM expenditures ORDER BY created_at DESC")
 	if err != nil {
 		return nil, err
 	}
 
 	return expenses, nil
 }
+
+// GetTotalExpendituresByTransactionDate sums expenditures charged to an expense account (the
+// transaction's debit account) whose transactions fall within the period
+func (p *Postgres) GetTotalExpendituresByTransactionDate(ctx context.Context, accountID string, startDate, endDate time.Time) (float64, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var total float64
+	query := `SELECT COALESCE(SUM(e.amount), 0) FROM expenditures e
+			  JOIN transactions t ON t.id = e.transaction_id
+			  WHERE t.debit_account IN (` + accountSubtree + `) AND t.status = 'posted' AND t.transaction_date BETWEEN $2 AND $3`
+	err := p.DB.GetContext(ctx, &total, query, accountID, startDate, endDate)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (p *Postgres) GetTotalExpendituresByTransaction(ctx context.Context, transactionID string) (float64, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var total float64
+	err := p.DB.GetContext(ctx, &total, "SELECT COALESCE(SUM(amount), 0) FROM expenditures WHERE transaction_id = $1", transactionID)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
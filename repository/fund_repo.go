@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"storeHouse/database"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (p *Postgres) executeFundQuery(ctx context.Context, query string, fund models.Fund) (models.Fund, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, fund)
+	if err != nil {
+		return models.Fund{}, err
+	}
+
+	return fund, nil
+}
+
+func (p *Postgres) CreateFund(ctx context.Context, fund models.Fund) (models.Fund, error) {
+	fund.ID = uuid.New().String()
+	fund.CreatedAt = time.Now()
+	fund.UpdatedAt = time.Now()
+
+	query := `INSERT INTO funds (id, code, name, description, is_restricted, is_active, created_at, updated_at)
+              VALUES (:id, :code, :name, :description, :is_restricted, :is_active, :created_at, :updated_at)`
+
+	return p.executeFundQuery(ctx, query, fund)
+}
+
+func (p *Postgres) UpdateFund(ctx context.Context, fund models.Fund) (models.Fund, error) {
+	fund.UpdatedAt = time.Now()
+
+	query := `UPDATE funds SET name = :name, description = :description, is_restricted = :is_restricted, is_active = :is_active, updated_at = :updated_at
+			  WHERE id = :id`
+
+	return p.executeFundQuery(ctx, query, fund)
+}
+
+func (p *Postgres) GetFund(ctx context.Context, id string) (models.Fund, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var fund models.Fund
+	err := p.DB.GetContext(ctx, &fund, "SELECT * FROM funds WHERE id = $1", id)
+	if err != nil {
+		return models.Fund{}, err
+	}
+
+	return fund, nil
+}
+
+func (p *Postgres) GetFundByCode(ctx context.Context, code string) (models.Fund, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var fund models.Fund
+	err := p.DB.GetContext(ctx, &fund, "SELECT * FROM funds WHERE code = $1", code)
+	if err != nil {
+		return models.Fund{}, err
+	}
+
+	return fund, nil
+}
+
+func (p *Postgres) GetAllFunds(ctx context.Context) ([]models.Fund, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var funds []models.Fund
+	err := p.DB.SelectContext(ctx, &funds, "SELECT * FROM funds ORDER BY code ASC")
+	if err != nil {
+		return nil, err
+	}
+
+	return funds, nil
+}
+
+// GetFundTotals sums the posted receipts and expenditures of every fund up to asOf; lines
+// without a fund come back with a nil FundID
+func (p *Postgres) GetFundTotals(ctx context.Context, asOf time.Time) ([]models.FundTotals, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var totals []models.FundTotals
+	query := `SELECT x.fund_id, COALESCE(SUM(x.receipts), 0) AS receipts, COALESCE(SUM(x.expenditures), 0) AS expenditures
+			  FROM (
+			      SELECT r.fund AS fund_id, r.amount AS receipts, 0 AS expenditures
+			      FROM receipts r JOIN transactions t ON t.id = r.transaction_id
+			      WHERE t.status = 'posted' AND t.transaction_date <= $1
+			      UNION ALL
+			      SELECT e.fund, 0, e.amount
+			      FROM expenditures e JOIN transactions t ON t.id = e.transaction_id
+			      WHERE t.status = 'posted' AND t.transaction_date <= $1
+			  ) x
+			  GROUP BY x.fund_id`
+	err := p.DB.SelectContext(ctx, &totals, query, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// GetFundCommitted sums the fund's expenditure lines on vouchers that are not yet posted
+// or rejected, leaving out one line so it can be re-checked when edited
+func (p *Postgres) GetFundCommitted(ctx context.Context, fundID string, excludeExpenditureID string) (float64, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var total float64
+	query := `SELECT COALESCE(SUM(e.amount), 0)
+			  FROM expenditures e JOIN transactions t ON t.id = e.transaction_id
+			  WHERE e.fund = $1 AND t.status IN ('draft', 'submitted', 'approved') AND e.id::text <> $2`
+	err := p.DB.GetContext(ctx, &total, query, fundID, excludeExpenditureID)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// GetFundSpendByTransaction sums a transaction's expenditure lines per fund
+func (p *Postgres) GetFundSpendByTransaction(ctx context.Context, transactionID string) ([]models.FundTotals, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var totals []models.FundTotals
+	query := `SELECT fund AS fund_id, 0 AS receipts, COALESCE(SUM(amount), 0) AS expenditures
+			  FROM expenditures WHERE transaction_id = $1
+			  GROUP BY fund`
+	err := p.DB.SelectContext(ctx, &totals, query, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// GetFundAccountBalances works out how much of a fund each account holds up to asOf:
+// receipts into the receiving account, expenditures out of the paying account, and
+// transfers from the source account to the destination. A nil fundID means the general fund.
+func (p *Postgres) GetFundAccountBalances(ctx context.Context, fundID *string, asOf time.Time) ([]models.FundAccountBalance, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var balances []models.FundAccountBalance
+	query := `SELECT a.id AS account_id, a.account_name, SUM(x.amount) AS balance
+			  FROM (
+			      SELECT t.debit_account AS account, r.amount
+			      FROM receipts r JOIN transactions t ON t.id = r.transaction_id
+			      WHERE r.fund IS NOT DISTINCT FROM $1 AND t.status = 'posted' AND t.transaction_date <= $2
+			      UNION ALL
+			      SELECT e.bank_account, -e.amount
+			      FROM expenditures e JOIN transactions t ON t.id = e.transaction_id
+			      WHERE e.fund IS NOT DISTINCT FROM $1 AND t.status = 'posted' AND t.transaction_date <= $2
+			      UNION ALL
+			      SELECT t.debit_account, tr.amount
+			      FROM transfers tr JOIN transactions t ON t.id = tr.transaction_id
+			      WHERE tr.fund IS NOT DISTINCT FROM $1 AND t.status = 'posted' AND t.transaction_date <= $2
+			      UNION ALL
+			      SELECT tr.credit_account, -tr.amount
+			      FROM transfers tr JOIN transactions t ON t.id = tr.transaction_id
+			      WHERE tr.fund IS NOT DISTINCT FROM $1 AND t.status = 'posted' AND t.transaction_date <= $2
+			  ) x
+			  JOIN accounts a ON a.id = x.account
+			  GROUP BY a.id, a.account_name
+			  HAVING SUM(x.amount) <> 0
+			  ORDER BY a.account_name ASC`
+	err := p.DB.SelectContext(ctx, &balances, query, fundID, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	return balances, nil
+}
@@ -0,0 +1,394 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"storeHouse/database"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func (p *Postgres) executeImprestQuery(ctx context.Context, query string, imprest models.Imprest) (models.Imprest, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, imprest)
+	if err != nil {
+		return models.Imprest{}, err
+	}
+
+	return imprest, nil
+}
+
+func (p *Postgres) CreateImprest(ctx context.Context, imprest models.Imprest) (models.Imprest, error) {
+	imprest.ID = uuid.New().String()
+	imprest.CreatedAt = time.Now()
+	imprest.UpdatedAt = time.Now()
+
+	query := `INSERT INTO imprests (id, name, account, float_amount, replenish_from, custodian, is_active, created_by, created_at, updated_at)
+              VALUES (:id, :name, :account, :float_amount, :replenish_from, :custodian, :is_active, :created_by, :created_at, :updated_at)`
+
+	return p.executeImprestQuery(ctx, query, imprest)
+}
+
+func (p *Postgres) UpdateImprest(ctx context.Context, imprest models.Imprest) (models.Imprest, error) {
+	imprest.UpdatedAt = time.Now()
+
+	query := `UPDATE imprests SET name = :name, float_amount = :float_amount, replenish_from = :replenish_from, custodian = :custodian, is_active = :is_active, updated_at = :updated_at
+			  WHERE id = :id`
+
+	return p.executeImprestQuery(ctx, query, imprest)
+}
+
+func (p *Postgres) GetImprest(ctx context.Context, id string) (models.Imprest, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var imprest models.Imprest
+	err := p.DB.GetContext(ctx, &imprest, "SELECT * FROM imprests WHERE id = $1", id)
+	if err != nil {
+		return models.Imprest{}, err
+	}
+
+	return imprest, nil
+}
+
+func (p *Postgres) GetImprestByAccount(ctx context.Context, accountID string) (models.Imprest, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var imprest models.Imprest
+	err := p.DB.GetContext(ctx, &imprest, "SELECT * FROM imprests WHERE account = $1", accountID)
+	if err != nil {
+		return models.Imprest{}, err
+	}
+
+	return imprest, nil
+}
+
+func (p *Postgres) GetAllImprests(ctx context.Context) ([]models.Imprest, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var imprests []models.Imprest
+	err := p.DB.SelectContext(ctx, &imprests, "SELECT * FROM imprests ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+
+	return imprests, nil
+}
+
+func (p *Postgres) executePettyCashVoucherQuery(ctx context.Context, query string, voucher models.PettyCashVoucher) (models.PettyCashVoucher, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, voucher)
+	if err != nil {
+		return models.PettyCashVoucher{}, err
+	}
+
+	return voucher, nil
+}
+
+// CreatePettyCashVoucher records a voucher, numbering it after the imprest's last one
+func (p *Postgres) CreatePettyCashVoucher(ctx context.Context, voucher models.PettyCashVoucher) (models.PettyCashVoucher, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var count int
+	if err := p.DB.GetContext(ctx, &count, "SELECT COUNT(*) FROM petty_cash_vouchers WHERE imprest_id = $1", voucher.ImprestID); err != nil {
+		return models.PettyCashVoucher{}, err
+	}
+
+	voucher.ID = uuid.New().String()
+	voucher.VoucherNumber = fmt.Sprintf("PCV-%05d", count+1)
+	voucher.CreatedAt = time.Now()
+	voucher.UpdatedAt = time.Now()
+
+	query := `INSERT INTO petty_cash_vouchers (id, imprest_id, voucher_number, voucher_date, expense_account, particulars, paid_to, amount, status, created_by, created_at, updated_at)
+              VALUES (:id, :imprest_id, :voucher_number, :voucher_date, :expense_account, :particulars, :paid_to, :amount, :status, :created_by, :created_at, :updated_at)`
+
+	return p.executePettyCashVoucherQuery(ctx, query, voucher)
+}
+
+func (p *Postgres) UpdatePettyCashVoucher(ctx context.Context, voucher models.PettyCashVoucher) (models.PettyCashVoucher, error) {
+	voucher.UpdatedAt = time.Now()
+
+	query := `UPDATE petty_cash_vouchers SET status = :status, replenishment_id = :replenishment_id, expense_transaction = :expense_transaction, updated_at = :updated_at
+			  WHERE id = :id`
+
+	return p.executePettyCashVoucherQuery(ctx, query, voucher)
+}
+
+func (p *Postgres) DeletePettyCashVoucher(ctx context.Context, id string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM petty_cash_vouchers WHERE id = $1", id)
+	return err
+}
+
+func (p *Postgres) GetPettyCashVoucher(ctx context.Context, id string) (models.PettyCashVoucher, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var voucher models.PettyCashVoucher
+	err := p.DB.GetContext(ctx, &voucher, "SELECT * FROM petty_cash_vouchers WHERE id = $1", id)
+	if err != nil {
+		return models.PettyCashVoucher{}, err
+	}
+
+	return voucher, nil
+}
+
+func (p *Postgres) GetPettyCashVouchersByImprest(ctx context.Context, imprestID string) ([]models.PettyCashVoucher, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var vouchers []models.PettyCashVoucher
+	err := p.DB.SelectContext(ctx, &vouchers, "SELECT * FROM petty_cash_vouchers WHERE imprest_id = $1 ORDER BY voucher_date DESC, voucher_number DESC", imprestID)
+	if err != nil {
+		return nil, err
+	}
+
+	return vouchers, nil
+}
+
+func (p *Postgres) GetPettyCashVouchersByStatus(ctx context.Context, imprestID, status string) ([]models.PettyCashVoucher, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var vouchers []models.PettyCashVoucher
+	err := p.DB.SelectContext(ctx, &vouchers, "SELECT * FROM petty_cash_vouchers WHERE imprest_id = $1 AND status = $2 ORDER BY voucher_date, voucher_number", imprestID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	return vouchers, nil
+}
+
+func (p *Postgres) GetPettyCashVouchersByReplenishment(ctx context.Context, replenishmentID string) ([]models.PettyCashVoucher, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var vouchers []models.PettyCashVoucher
+	err := p.DB.SelectContext(ctx, &vouchers, "SELECT * FROM petty_cash_vouchers WHERE replenishment_id = $1 ORDER BY voucher_date, voucher_number", replenishmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return vouchers, nil
+}
+
+func (p *Postgres) GetTotalPettyCashVouchersByStatus(ctx context.Context, imprestID, status string) (float64, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var total float64
+	err := p.DB.GetContext(ctx, &total, "SELECT COALESCE(SUM(amount), 0) FROM petty_cash_vouchers WHERE imprest_id = $1 AND status = $2", imprestID, status)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// ClaimPettyCashVouchers attaches every open voucher of an imprest to a replenishment
+func (p *Postgres) ClaimPettyCashVouchers(ctx context.Context, imprestID, replenishmentID string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, `UPDATE petty_cash_vouchers SET status = 'claimed', replenishment_id = $2, updated_at = $3
+			  WHERE imprest_id = $1 AND status = 'open'`, imprestID, replenishmentID, time.Now())
+	return err
+}
+
+// ReleasePettyCashVouchers returns the vouchers of a rejected replenishment to the box
+func (p *Postgres) ReleasePettyCashVouchers(ctx context.Context, replenishmentID string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, `UPDATE petty_cash_vouchers SET status = 'open', replenishment_id = NULL, updated_at = $2
+			  WHERE replenishment_id = $1 AND status = 'claimed'`, replenishmentID, time.Now())
+	return err
+}
+
+func (p *Postgres) executeReplenishmentQuery(ctx context.Context, query string, replenishment models.ImprestReplenishment) (models.ImprestReplenishment, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, replenishment)
+	if err != nil {
+		return models.ImprestReplenishment{}, err
+	}
+
+	return replenishment, nil
+}
+
+func (p *Postgres) CreateImprestReplenishment(ctx context.Context, replenishment models.ImprestReplenishment) (models.ImprestReplenishment, error) {
+	replenishment.ID = uuid.New().String()
+	replenishment.CreatedAt = time.Now()
+	replenishment.UpdatedAt = time.Now()
+
+	query := `INSERT INTO imprest_replenishments (id, imprest_id, bank_account, vouchers_total, amount, status, notes, requested_by, created_at, updated_at)
+              VALUES (:id, :imprest_id, :bank_account, :vouchers_total, :amount, :status, :notes, :requested_by, :created_at, :updated_at)`
+
+	return p.executeReplenishmentQuery(ctx, query, replenishment)
+}
+
+func (p *Postgres) UpdateImprestReplenishment(ctx context.Context, replenishment models.ImprestReplenishment) (models.ImprestReplenishment, error) {
+	replenishment.UpdatedAt = time.Now()
+
+	query := `UPDATE imprest_replenishments SET amount = :amount, status = :status, transfer_transaction = :transfer_transaction, notes = :notes,
+			  reviewed_by = :reviewed_by, reviewed_at = :reviewed_at, updated_at = :updated_at
+			  WHERE id = :id`
+
+	return p.executeReplenishmentQuery(ctx, query, replenishment)
+}
+
+// PostImprestReplenishment writes an approved replenishment's transactions, their approvals
+// and lines, marks its vouchers replenished and the replenishment posted, all in one
+// transaction
+func (p *Postgres) PostImprestReplenishment(ctx context.Context, posting models.ReplenishmentPosting) (models.ImprestReplenishment, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return models.ImprestReplenishment{}, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	txnQuery := `INSERT INTO transactions (id, transaction_date, transaction_type, amount, notes, debit_account, status, submitted_by, submitted_at, posted_by, posted_at,
+              required_approvals, required_roles, created_by, created_at, updated_at)
+              VALUES (:id, :transaction_date, :transaction_type, :amount, :notes, :debit_account, :status, :submitted_by, :submitted_at, :posted_by, :posted_at,
+              :required_approvals, :required_roles, :created_by, :created_at, :updated_at)`
+	approvalQuery := `INSERT INTO voucher_approvals (id, transaction_id, approver, approver_role, decision, comment, created_at)
+              VALUES (:id, :transaction_id, :approver, :approver_role, :decision, :comment, :created_at)`
+	insertTransaction := func(txn *models.Transaction) error {
+		txn.ID = uuid.New().String()
+		if txn.RequiredRoles == nil {
+			txn.RequiredRoles = pq.StringArray{}
+		}
+		txn.CreatedAt = now
+		txn.UpdatedAt = now
+		if _, err := tx.NamedExecContext(ctx, txnQuery, txn); err != nil {
+			return err
+		}
+
+		approval := posting.Approval
+		approval.ID = uuid.New().String()
+		approval.TransactionID = txn.ID
+		approval.CreatedAt = now
+		_, err := tx.NamedExecContext(ctx, approvalQuery, approval)
+		return err
+	}
+
+	expenditureQuery := `INSERT INTO expenditures (id, transaction_id, particulars, bank_account, payee, fund, amount, created_at, updated_at)
+              VALUES (:id, :transaction_id, :particulars, :bank_account, :payee, :fund, :amount, :created_at, :updated_at)`
+	voucherQuery := `UPDATE petty_cash_vouchers SET status = :status, replenishment_id = :replenishment_id, expense_transaction = :expense_transaction, updated_at = :updated_at
+			  WHERE id = :id`
+	for _, expense := range posting.Expenses {
+		if err := insertTransaction(&expense.Transaction); err != nil {
+			return models.ImprestReplenishment{}, err
+		}
+
+		for _, exp := range expense.Expenditures {
+			exp.ID = uuid.New().String()
+			exp.TransactionID = expense.Transaction.ID
+			exp.CreatedAt = now
+			exp.UpdatedAt = now
+			if _, err := tx.NamedExecContext(ctx, expenditureQuery, exp); err != nil {
+				return models.ImprestReplenishment{}, err
+			}
+		}
+
+		for _, voucher := range expense.Vouchers {
+			voucher.Status = string(models.PettyCashReplenished)
+			voucher.ExpenseTransactionID = &expense.Transaction.ID
+			voucher.UpdatedAt = now
+			if _, err := tx.NamedExecContext(ctx, voucherQuery, voucher); err != nil {
+				return models.ImprestReplenishment{}, err
+			}
+		}
+	}
+
+	topUp := posting.TopUp
+	if err := insertTransaction(&topUp); err != nil {
+		return models.ImprestReplenishment{}, err
+	}
+
+	line := posting.TopUpLine
+	line.ID = uuid.New().String()
+	line.TransactionID = topUp.ID
+	line.CreatedAt = now
+	line.UpdatedAt = now
+	transferQuery := `INSERT INTO transfers (id, transaction_id, particulars, credit_account, fund, amount, created_at, updated_at)
+              VALUES (:id, :transaction_id, :particulars, :credit_account, :fund, :amount, :created_at, :updated_at)`
+	if _, err := tx.NamedExecContext(ctx, transferQuery, line); err != nil {
+		return models.ImprestReplenishment{}, err
+	}
+
+	replenishment := posting.Replenishment
+	replenishment.Status = string(models.ReplenishmentPosted)
+	replenishment.TransferTransactionID = &topUp.ID
+	replenishment.UpdatedAt = now
+	replenishmentQuery := `UPDATE imprest_replenishments SET amount = :amount, status = :status, transfer_transaction = :transfer_transaction, notes = :notes,
+			  reviewed_by = :reviewed_by, reviewed_at = :reviewed_at, updated_at = :updated_at
+			  WHERE id = :id`
+	if _, err := tx.NamedExecContext(ctx, replenishmentQuery, replenishment); err != nil {
+		return models.ImprestReplenishment{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.ImprestReplenishment{}, err
+	}
+
+	return replenishment, nil
+}
+
+func (p *Postgres) GetImprestReplenishment(ctx context.Context, id string) (models.ImprestReplenishment, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var replenishment models.ImprestReplenishment
+	err := p.DB.GetContext(ctx, &replenishment, "SELECT * FROM imprest_replenishments WHERE id = $1", id)
+	if err != nil {
+		return models.ImprestReplenishment{}, err
+	}
+
+	return replenishment, nil
+}
+
+func (p *Postgres) GetImprestReplenishments(ctx context.Context, imprestID string) ([]models.ImprestReplenishment, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var replenishments []models.ImprestReplenishment
+	err := p.DB.SelectContext(ctx, &replenishments, "SELECT * FROM imprest_replenishments WHERE imprest_id = $1 ORDER BY created_at DESC", imprestID)
+	if err != nil {
+		return nil, err
+	}
+
+	return replenishments, nil
+}
+
+// GetPendingImprestReplenishment returns the replenishment awaiting review for an imprest
+func (p *Postgres) GetPendingImprestReplenishment(ctx context.Context, imprestID string) (models.ImprestReplenishment, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var replenishment models.ImprestReplenishment
+	err := p.DB.GetContext(ctx, &replenishment, "SELECT * FROM imprest_replenishments WHERE imprest_id = $1 AND status = 'pending'", imprestID)
+	if err != nil {
+		return models.ImprestReplenishment{}, err
+	}
+
+	return replenishment, nil
+}
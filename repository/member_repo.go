@@ -1,15 +1,19 @@
 package repository
 
 import (
+	"context"
+	"storeHouse/database"
 	"storeHouse/models"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 )
 
-func executeMemberQuery(db *sqlx.DB, query string, member models.Member) (models.Member, error) {
-	_, err := db.NamedExec(query, member)
+func (p *Postgres) executeMemberQuery(ctx context.Context, query string, member models.Member) (models.Member, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, member)
 	if err != nil {
 		return models.Member{}, err
 	}
@@ -17,34 +21,48 @@ func executeMemberQuery(db *sqlx.DB, query string, member models.Member) (models
 	return member, nil
 }
 
-func CreateMember(db *sqlx.DB, member models.Member) (models.Member, error) {
+func (p *Postgres) CreateMember(ctx context.Context, member models.Member) (models.Member, error) {
 	member.ID = uuid.New().String()
 	member.CreatedAt = time.Now()
 	member.UpdatedAt = time.Now()
 
-	query := `INSERT INTO members (id, full_name, phone_number, email, notes, group, created_by, created_at, updated_at)
+	query := `INSERT INTO members (id, full_name, phone_number, email, notes, group_id, created_by, created_at, updated_at)
               VALUES (:id, :full_name, :phone_number, :email, :notes, :group_id, :created_by, :created_at, :updated_at)`
 
-	return executeMemberQuery(db, query, member)
+	return p.executeMemberQuery(ctx, query, member)
 }
 
-func UpdateMember(db *sqlx.DB, member models.Member) (models.Member, error) {
+func (p *Postgres) UpdateMember(ctx context.Context, member models.Member) (models.Member, error) {
 	member.UpdatedAt = time.Now()
 
-	query := `UPDATE members SET full_name = :full_name, phone_number = :phone_number, email = :email, notes = :notes, group = :group_id, updated_at = :updated_at 
+	query := `UPDATE members SET full_name = :full_name, phone_number = :phone_number, email = :email, notes = :notes, group_id = :group_id, updated_at = :updated_at 
 			  WHERE id = :id`
 
-	return executeMemberQuery(db, query, member)
+	return p.executeMemberQuery(ctx, query, member)
+}
+
+func (p *Postgres) DeleteMember(ctx context.Context, id string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM members WHERE id = $1", id)
+	return err
 }
 
-func DeleteMember(db *sqlx.DB, id string) error {
-	_, err := db.Exec("DELETE FROM members WHERE id = $1", id)
+func (p *Postgres) SetMemberSMSOptOut(ctx context.Context, memberID string, optOut bool) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "UPDATE members SET sms_opt_out = $1, updated_at = $2 WHERE id = $3", optOut, time.Now(), memberID)
 	return err
 }
 
-func GetMember(db *sqlx.DB, id string) (models.Member, error) {
+func (p *Postgres) GetMember(ctx context.Context, id string) (models.Member, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var member models.Member
-	err := db.Get(&member, "SELECT * FROM members WHERE id = $1", id)
+	err := p.DB.GetContext(ctx, &member, "SELECT * FROM members WHERE id = $1", id)
 	if err != nil {
 		return models.Member{}, err
 	}
@@ -52,9 +70,12 @@ func GetMember(db *sqlx.DB, id string) (models.Member, error) {
 	return member, nil
 }
 
-func GetMemberByPhone(db *sqlx.DB, phoneNumber string) (models.Member, error) {
+func (p *Postgres) GetMemberByPhone(ctx context.Context, phoneNumber string) (models.Member, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var member models.Member
-	err := db.Get(&member, "SELECT * FROM members WHERE phone_number = $1", phoneNumber)
+	err := p.DB.GetContext(ctx, &member, "SELECT * FROM members WHERE phone_number = $1", phoneNumber)
 	if err != nil {
 		return models.Member{}, err
 	}
@@ -62,9 +83,12 @@ func GetMemberByPhone(db *sqlx.DB, phoneNumber string) (models.Member, error) {
 	return member, nil
 }
 
-func GetMemberByEmail(db *sqlx.DB, email string) (models.Member, error) {
+func (p *Postgres) GetMemberByEmail(ctx context.Context, email string) (models.Member, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var member models.Member
-	err := db.Get(&member, "SELECT * FROM members WHERE email = $1", email)
+	err := p.DB.GetContext(ctx, &member, "SELECT * FROM members WHERE email = $1", email)
 	if err != nil {
 		return models.Member{}, err
 	}
@@ -72,9 +96,12 @@ func GetMemberByEmail(db *sqlx.DB, email string) (models.Member, error) {
 	return member, nil
 }
 
-func GetMemberByGroup(db *sqlx.DB, groupID string) ([]models.Member, error) {
+func (p *Postgres) GetMemberByGroup(ctx context.Context, groupID string) ([]models.Member, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var members []models.Member
-	err := db.Select(&members, "SELECT * FROM members WHERE group = $1 ORDER BY full_name ASC", groupID)
+	err := p.DB.SelectContext(ctx, &members, "SELECT * FROM members WHERE group_id = $1 ORDER BY full_name ASC", groupID)
 	if err != nil {
 		return nil, err
 	}
@@ -82,9 +109,12 @@ func GetMemberByGroup(db *sqlx.DB, groupID string) ([]models.Member, error) {
 	return members, nil
 }
 
-func GetAllMembers(db *sqlx.DB) ([]models.Member, error) {
+func (p *Postgres) GetAllMembers(ctx context.Context) ([]models.Member, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var members []models.Member
-	err := db.Select(&members, "SELECT * FROM members ORDER BY full_name ASC")
+	err := p.DB.SelectContext(ctx, &members, "SELECT * FROM members ORDER BY full_name ASC")
 	if err != nil {
 		return nil, err
 	}
@@ -92,10 +122,13 @@ func GetAllMembers(db *sqlx.DB) ([]models.Member, error) {
 	return members, nil
 }
 
-func SearchMembers(db *sqlx.DB, searchTerm string) ([]models.Member, error) {
+func (p *Postgres) SearchMembers(ctx context.Context, searchTerm string) ([]models.Member, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var members []models.Member
 	query := "SELECT * FROM members WHERE full_name ILIKE $1 OR phone_number ILIKE $1 OR email ILIKE $1 ORDER BY full_name ASC"
-	err := db.Select(&members, query, "%"+searchTerm+"%")
+	err := p.DB.SelectContext(ctx, &members, query, "%"+searchTerm+"%")
 	if err != nil {
 		return nil, err
 	}
@@ -1,15 +1,19 @@
 package repository
 
 import (
+	"context"
+	"storeHouse/database"
 	"storeHouse/models"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 )
 
-func executeGroupQuery(db *sqlx.DB, query string, group models.MembersGroup) (models.MembersGroup, error) {
-	_, err := db.NamedExec(query, group)
+func (p *Postgres) executeGroupQuery(ctx context.Context, query string, group models.MembersGroup) (models.MembersGroup, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, group)
 	if err != nil {
 		return models.MembersGroup{}, err
 	}
@@ -17,7 +21,7 @@ func executeGroupQuery(db *sqlx.DB, query string, group models.MembersGroup) (mo
 	return group, nil
 }
 
-func CreateGroup(db *sqlx.DB, group models.MembersGroup) (models.MembersGroup, error) {
+func (p *Postgres) CreateGroup(ctx context.Context, group models.MembersGroup) (models.MembersGroup, error) {
 	group.ID = uuid.New().String()
 	group.CreatedAt = time.Now()
 	group.UpdatedAt = time.Now()
@@ -25,26 +29,32 @@ func CreateGroup(db *sqlx.DB, group models.MembersGroup) (models.MembersGroup, e
 	query := `INSERT INTO members_groups (id, group_name, notes, created_by, created_at, updated_at)
               VALUES (:id, :group_name, :notes, :created_by, :created_at, :updated_at)`
 
-	return executeGroupQuery(db, query, group)
+	return p.executeGroupQuery(ctx, query, group)
 }
 
-func UpdateGroup(db *sqlx.DB, group models.MembersGroup) (models.MembersGroup, error) {
+func (p *Postgres) UpdateGroup(ctx context.Context, group models.MembersGroup) (models.MembersGroup, error) {
 	group.UpdatedAt = time.Now()
 
 	query := `UPDATE members_groups SET group_name = :group_name, notes = :notes, updated_at = :updated_at 
 			  WHERE id = :id`
 
-	return executeGroupQuery(db, query, group)
+	return p.executeGroupQuery(ctx, query, group)
 }
 
-func DeleteGroup(db *sqlx.DB, id string) error {
-	_, err := db.Exec("DELETE FROM members_groups WHERE id = $1", id)
+func (p *Postgres) DeleteGroup(ctx context.Context, id string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM members_groups WHERE id = $1", id)
 	return err
 }
 
-func GetGroup(db *sqlx.DB, id string) (models.MembersGroup, error) {
+func (p *Postgres) GetGroup(ctx context.Context, id string) (models.MembersGroup, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var group models.MembersGroup
-	err := db.Get(&group, "SELECT * FROM members_groups WHERE id = $1", id)
+	err := p.DB.GetContext(ctx, &group, "SELECT * FROM members_groups WHERE id = $1", id)
 	if err != nil {
 		return models.MembersGroup{}, err
 	}
@@ -52,9 +62,12 @@ func GetGroup(db *sqlx.DB, id string) (models.MembersGroup, error) {
 	return group, nil
 }
 
-func GetGroupByName(db *sqlx.DB, name string) (models.MembersGroup, error) {
+func (p *Postgres) GetGroupByName(ctx context.Context, name string) (models.MembersGroup, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var group models.MembersGroup
-	err := db.Get(&group, "SELECT * FROM members_groups WHERE group_name = $1", name)
+	err := p.DB.GetContext(ctx, &group, "SELECT * FROM members_groups WHERE group_name = $1", name)
 	if err != nil {
 		return models.MembersGroup{}, err
 	}
@@ -62,9 +75,12 @@ func GetGroupByName(db *sqlx.DB, name string) (models.MembersGroup, error) {
 	return group, nil
 }
 
-func GetAllGroups(db *sqlx.DB) ([]models.MembersGroup, error) {
+func (p *Postgres) GetAllGroups(ctx context.Context) ([]models.MembersGroup, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var groups []models.MembersGroup
-	err := db.Select(&groups, "SELECT * FROM members_groups ORDER BY group_name ASC")
+	err := p.DB.SelectContext(ctx, &groups, "SELECT * FROM members_groups ORDER BY group_name ASC")
 	if err != nil {
 		return nil, err
 	}
@@ -72,7 +88,10 @@ func GetAllGroups(db *sqlx.DB) ([]models.MembersGroup, error) {
 	return groups, nil
 }
 
-func GetGroupsWithMemberCount(db *sqlx.DB) ([]map[string]interface{}, error) {
+func (p *Postgres) GetGroupsWithMemberCount(ctx context.Context) ([]map[string]interface{}, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var results []map[string]interface{}
 	query := `
 		SELECT 
@@ -84,11 +103,11 @@ func GetGroupsWithMemberCount(db *sqlx.DB) ([]map[string]interface{}, error) {
 			mg.updated_at,
 			COUNT(m.id) as member_count
 		FROM members_groups mg
-		LEFT JOIN members m ON mg.id = m.group
+		LEFT JOIN members m ON mg.id = m.group_id
 		GROUP BY mg.id, mg.group_name, mg.notes, mg.created_by, mg.created_at, mg.updated_at
 		ORDER BY mg.group_name ASC
 	`
-	err := db.Select(&results, query)
+	err := p.DB.SelectContext(ctx, &results, query)
 	if err != nil {
 		return nil, err
 	}
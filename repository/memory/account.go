@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) CreateAccount(ctx context.Context, acc models.Account) (models.Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc.ID = uuid.New().String()
+	acc.CreatedAt = time.Now()
+	acc.UpdatedAt = time.Now()
+
+	if err := s.checkAccountCode(acc); err != nil {
+		return models.Account{}, err
+	}
+	s.accounts = append(s.accounts, acc)
+	return acc, nil
+}
+
+func (s *Store) UpdateAccount(ctx context.Context, acc models.Account) (models.Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc.UpdatedAt = time.Now()
+
+	i := find(s.accounts, func(a models.Account) bool { return a.ID == acc.ID })
+	if i < 0 {
+		return acc, nil
+	}
+	if err := s.checkAccountCode(acc); err != nil {
+		return models.Account{}, err
+	}
+	row := &s.accounts[i]
+	row.AccountName = acc.AccountName
+	row.AccountType = acc.AccountType
+	row.Code = acc.Code
+	row.ParentID = acc.ParentID
+	row.IsHeader = acc.IsHeader
+	row.LocalShare = acc.LocalShare
+	row.Notes = acc.Notes
+	row.IsActive = acc.IsActive
+	row.UpdatedAt = acc.UpdatedAt
+	return acc, nil
+}
+
+// checkAccountCode keeps account codes unique, as the accounts_church_code_key constraint does
+func (s *Store) checkAccountCode(acc models.Account) error {
+	if acc.Code == nil {
+		return nil
+	}
+	taken := find(s.accounts, func(a models.Account) bool {
+		return a.ID != acc.ID && a.Code != nil && *a.Code == *acc.Code
+	})
+	if taken >= 0 {
+		return uniqueViolation("accounts_church_code_key", "code", *acc.Code)
+	}
+	return nil
+}
+
+func (s *Store) DeactivateAccount(ctx context.Context, id string) (models.Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc := models.Account{
+		ID:        id,
+		IsActive:  false,
+		UpdatedAt: time.Now(),
+	}
+
+	if i := find(s.accounts, func(a models.Account) bool { return a.ID == id }); i >= 0 {
+		s.accounts[i].IsActive = false
+		s.accounts[i].UpdatedAt = acc.UpdatedAt
+	}
+	return acc, nil
+}
+
+func (s *Store) GetAccount(ctx context.Context, id string) (models.Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.accounts, func(a models.Account) bool { return a.ID == id })
+}
+
+func (s *Store) GetAccountByName(ctx context.Context, name string) (models.Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.accounts, func(a models.Account) bool { return a.AccountName == name })
+}
+
+func (s *Store) GetAllAccounts(ctx context.Context) ([]models.Account, error) {
+	return s.GetChartOfAccounts(ctx, false)
+}
+
+func (s *Store) GetAccountByCode(ctx context.Context, code string) (models.Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.accounts, func(a models.Account) bool { return a.Code != nil && *a.Code == code })
+}
+
+func (s *Store) GetChartOfAccounts(ctx context.Context, includeInactive bool) ([]models.Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	accs := where(s.accounts, func(a models.Account) bool { return a.IsActive || includeInactive })
+	sortBy(accs, func(a, b models.Account) int {
+		if c := compareNullsLast(a.Code, b.Code); c != 0 {
+			return c
+		}
+		return compare(a.AccountName, b.AccountName)
+	})
+	return accs, nil
+}
+
+func (s *Store) GetAccountSubtreeIDs(ctx context.Context, id string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.accountSubtree(id), nil
+}
+
+// accountSubtree returns the account and every account below it in the chart
+func (s *Store) accountSubtree(id string) []string {
+	if find(s.accounts, func(a models.Account) bool { return a.ID == id }) < 0 {
+		return nil
+	}
+
+	ids := []string{id}
+	for i := 0; i < len(ids); i++ {
+		for _, a := range s.accounts {
+			if a.ParentID != nil && *a.ParentID == ids[i] {
+				ids = append(ids, a.ID)
+			}
+		}
+	}
+	return ids
+}
+
+func (s *Store) CountChildAccounts(ctx context.Context, id string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(where(s.accounts, func(a models.Account) bool { return a.ParentID != nil && *a.ParentID == id })), nil
+}
+
+func (s *Store) CountAccountPostings(ctx context.Context, id string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := len(where(s.transactions, func(t models.Transaction) bool { return t.DebitAccountID == id }))
+	count += len(where(s.receipts, func(r models.Receipt) bool { return r.IncomeAccountID == id }))
+	count += len(where(s.expenditures, func(e models.Expenditure) bool { return e.BankAccountID == id }))
+	count += len(where(s.transfers, func(t models.Transfer) bool { return t.CreditAccountID == id }))
+	count += len(where(s.budgets, func(b models.Budget) bool { return b.AccountID == id }))
+	return count, nil
+}
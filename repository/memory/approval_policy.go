@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func (s *Store) CreateApprovalPolicy(ctx context.Context, policy models.ApprovalPolicy) (models.ApprovalPolicy, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	policy.ID = uuid.New().String()
+	policy.CreatedAt = time.Now()
+	policy.UpdatedAt = time.Now()
+	if policy.RequiredRoles == nil {
+		policy.RequiredRoles = pq.StringArray{}
+	}
+
+	s.approvalPolicies = append(s.approvalPolicies, policy)
+	return policy, nil
+}
+
+func (s *Store) UpdateApprovalPolicy(ctx context.Context, policy models.ApprovalPolicy) (models.ApprovalPolicy, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	policy.UpdatedAt = time.Now()
+	if policy.RequiredRoles == nil {
+		policy.RequiredRoles = pq.StringArray{}
+	}
+
+	if i := find(s.approvalPolicies, func(p models.ApprovalPolicy) bool { return p.ID == policy.ID }); i >= 0 {
+		row := &s.approvalPolicies[i]
+		row.Name = policy.Name
+		row.Description = policy.Description
+		row.TransactionType = policy.TransactionType
+		row.AccountID = policy.AccountID
+		row.AmountOver = policy.AmountOver
+		row.AmountUpTo = policy.AmountUpTo
+		row.RequiredApprovals = policy.RequiredApprovals
+		row.RequiredRoles = policy.RequiredRoles
+		row.IsActive = policy.IsActive
+		row.UpdatedAt = policy.UpdatedAt
+	}
+	return policy, nil
+}
+
+func (s *Store) DeleteApprovalPolicy(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.approvalPolicies = remove(s.approvalPolicies, func(p models.ApprovalPolicy) bool { return p.ID == id })
+	return nil
+}
+
+func (s *Store) GetApprovalPolicy(ctx context.Context, id string) (models.ApprovalPolicy, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.approvalPolicies, func(p models.ApprovalPolicy) bool { return p.ID == id })
+}
+
+func (s *Store) GetAllApprovalPolicies(ctx context.Context) ([]models.ApprovalPolicy, error) {
+	return s.approvalPoliciesWhere(func(models.ApprovalPolicy) bool { return true }), nil
+}
+
+func (s *Store) GetActiveApprovalPolicies(ctx context.Context) ([]models.ApprovalPolicy, error) {
+	return s.approvalPoliciesWhere(func(p models.ApprovalPolicy) bool { return p.IsActive }), nil
+}
+
+// approvalPoliciesWhere returns the matching policies from the lowest threshold up
+func (s *Store) approvalPoliciesWhere(match func(models.ApprovalPolicy) bool) []models.ApprovalPolicy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	policies := where(s.approvalPolicies, match)
+	sortBy(policies, func(a, b models.ApprovalPolicy) int {
+		if c := compare(a.AmountOver, b.AmountOver); c != 0 {
+			return c
+		}
+		return compare(a.Name, b.Name)
+	})
+	return policies
+}
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) CreateAttachment(ctx context.Context, attachment models.Attachment) (models.Attachment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if attachment.ID == "" {
+		attachment.ID = uuid.New().String()
+	}
+	attachment.CreatedAt = time.Now()
+	attachment.UpdatedAt = time.Now()
+
+	if find(s.attachments, func(a models.Attachment) bool { return a.StorageKey == attachment.StorageKey }) >= 0 {
+		return models.Attachment{}, uniqueViolation("attachments_storage_key_key", "storage_key", attachment.StorageKey)
+	}
+	s.attachments = append(s.attachments, attachment)
+	return attachment, nil
+}
+
+func (s *Store) DeleteAttachment(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attachments = remove(s.attachments, func(a models.Attachment) bool { return a.ID == id })
+	return nil
+}
+
+func (s *Store) GetAttachment(ctx context.Context, id string) (models.Attachment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.attachments, func(a models.Attachment) bool { return a.ID == id })
+}
+
+func (s *Store) GetAttachmentsByEntity(ctx context.Context, entity models.AttachmentEntity, entityID string) ([]models.Attachment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attachments := where(s.attachments, func(a models.Attachment) bool {
+		switch entity {
+		case models.AttachmentTransaction:
+			return is(a.TransactionID, entityID)
+		case models.AttachmentExpenditure:
+			return is(a.ExpenditureID, entityID)
+		case models.AttachmentTransfer:
+			return is(a.TransferID, entityID)
+		}
+		return false
+	})
+	sortBy(attachments, func(a, b models.Attachment) int { return compareTime(a.CreatedAt, b.CreatedAt) })
+	return attachments, nil
+}
+
+func (s *Store) GetAttachmentsByTransactionTree(ctx context.Context, transactionID string) ([]models.Attachment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attachments := where(s.attachments, func(a models.Attachment) bool {
+		if is(a.TransactionID, transactionID) {
+			return true
+		}
+		if a.ExpenditureID != nil && find(s.expenditures, func(e models.Expenditure) bool {
+			return e.ID == *a.ExpenditureID && e.TransactionID == transactionID
+		}) >= 0 {
+			return true
+		}
+		return a.TransferID != nil && find(s.transfers, func(t models.Transfer) bool {
+			return t.ID == *a.TransferID && t.TransactionID == transactionID
+		}) >= 0
+	})
+	sortBy(attachments, func(a, b models.Attachment) int { return compareTime(a.CreatedAt, b.CreatedAt) })
+	return attachments, nil
+}
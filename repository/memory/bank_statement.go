@@ -0,0 +1,263 @@
+package memory
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) CreateBankStatement(ctx context.Context, statement models.BankStatement) (models.BankStatement, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statement.ID = uuid.New().String()
+	statement.CreatedAt = time.Now()
+	statement.UpdatedAt = time.Now()
+
+	s.bankStatements = append(s.bankStatements, statement)
+	return statement, nil
+}
+
+func (s *Store) UpdateBankStatement(ctx context.Context, statement models.BankStatement) (models.BankStatement, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statement.UpdatedAt = time.Now()
+
+	if i := find(s.bankStatements, func(b models.BankStatement) bool { return b.ID == statement.ID }); i >= 0 {
+		row := &s.bankStatements[i]
+		row.PeriodStart = statement.PeriodStart
+		row.PeriodEnd = statement.PeriodEnd
+		row.OpeningBalance = statement.OpeningBalance
+		row.ClosingBalance = statement.ClosingBalance
+		row.UpdatedAt = statement.UpdatedAt
+	}
+	return statement, nil
+}
+
+func (s *Store) DeleteBankStatement(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bankStatements = remove(s.bankStatements, func(b models.BankStatement) bool { return b.ID == id })
+	s.statementLines = remove(s.statementLines, func(l models.BankStatementLine) bool { return l.StatementID == id })
+	return nil
+}
+
+func (s *Store) GetBankStatement(ctx context.Context, id string) (models.BankStatement, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.bankStatements, func(b models.BankStatement) bool { return b.ID == id })
+}
+
+func (s *Store) GetBankStatementsByAccount(ctx context.Context, accountID string) ([]models.BankStatement, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statements := where(s.bankStatements, func(b models.BankStatement) bool { return b.BankAccountID == accountID })
+	sortBy(statements, func(a, b models.BankStatement) int {
+		if c := compareTimeDescNullsLast(a.PeriodEnd, b.PeriodEnd); c != 0 {
+			return c
+		}
+		return compareTime(b.CreatedAt, a.CreatedAt)
+	})
+	return statements, nil
+}
+
+func (s *Store) GetLatestBankStatement(ctx context.Context, accountID string, asOf time.Time) (models.BankStatement, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statements := where(s.bankStatements, func(b models.BankStatement) bool {
+		return b.BankAccountID == accountID && b.PeriodEnd != nil && !b.PeriodEnd.After(asOf)
+	})
+	sortBy(statements, func(a, b models.BankStatement) int {
+		if c := compareTime(*b.PeriodEnd, *a.PeriodEnd); c != 0 {
+			return c
+		}
+		return compareTime(b.CreatedAt, a.CreatedAt)
+	})
+	return get(statements, func(models.BankStatement) bool { return true })
+}
+
+func (s *Store) CreateStatementLine(ctx context.Context, line models.BankStatementLine) (models.BankStatementLine, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	line.ID = uuid.New().String()
+	line.CreatedAt = time.Now()
+	line.UpdatedAt = time.Now()
+
+	if line.FitID != nil && find(s.statementLines, func(l models.BankStatementLine) bool {
+		return l.BankAccountID == line.BankAccountID && is(l.FitID, *line.FitID)
+	}) >= 0 {
+		return models.BankStatementLine{}, uniqueViolation("idx_bank_statement_lines_fit_id", "bank_account, fit_id", line.BankAccountID, *line.FitID)
+	}
+	s.statementLines = append(s.statementLines, line)
+	return line, nil
+}
+
+func (s *Store) MatchStatementLine(ctx context.Context, id, transactionID string, matchType models.MatchType) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := find(s.statementLines, func(l models.BankStatementLine) bool { return l.ID == id })
+	if i < 0 {
+		return nil
+	}
+	row := &s.statementLines[i]
+	if find(s.statementLines, func(l models.BankStatementLine) bool {
+		return l.ID != id && l.BankAccountID == row.BankAccountID && is(l.TransactionID, transactionID)
+	}) >= 0 {
+		return uniqueViolation("idx_bank_statement_lines_transaction", "bank_account, transaction_id", row.BankAccountID, transactionID)
+	}
+
+	now := time.Now()
+	match := string(matchType)
+	row.TransactionID = &transactionID
+	row.MatchType = &match
+	row.MatchedAt = &now
+	row.UpdatedAt = now
+	return nil
+}
+
+func (s *Store) UnmatchStatementLine(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i := find(s.statementLines, func(l models.BankStatementLine) bool { return l.ID == id }); i >= 0 {
+		row := &s.statementLines[i]
+		row.TransactionID = nil
+		row.MatchType = nil
+		row.MatchedAt = nil
+		row.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+func (s *Store) GetStatementLine(ctx context.Context, id string) (models.BankStatementLine, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.statementLines, func(l models.BankStatementLine) bool { return l.ID == id })
+}
+
+func (s *Store) GetStatementLines(ctx context.Context, statementID string) ([]models.BankStatementLine, error) {
+	return s.statementLinesWhere(func(l models.BankStatementLine) bool { return l.StatementID == statementID }), nil
+}
+
+func (s *Store) GetStatementLineByFitID(ctx context.Context, accountID, fitID string) (models.BankStatementLine, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.statementLines, func(l models.BankStatementLine) bool {
+		return l.BankAccountID == accountID && is(l.FitID, fitID)
+	})
+}
+
+func (s *Store) GetStatementLineByTransaction(ctx context.Context, accountID, transactionID string) (models.BankStatementLine, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.statementLines, func(l models.BankStatementLine) bool {
+		return l.BankAccountID == accountID && is(l.TransactionID, transactionID)
+	})
+}
+
+func (s *Store) GetStatementLinesByAccount(ctx context.Context, accountID string, asOf time.Time) ([]models.BankStatementLine, error) {
+	return s.statementLinesWhere(func(l models.BankStatementLine) bool {
+		return l.BankAccountID == accountID && !l.LineDate.After(asOf)
+	}), nil
+}
+
+// statementLinesWhere returns the matching statement lines in date order
+func (s *Store) statementLinesWhere(match func(models.BankStatementLine) bool) []models.BankStatementLine {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lines := where(s.statementLines, match)
+	sortBy(lines, func(a, b models.BankStatementLine) int {
+		if c := compareTime(a.LineDate, b.LineDate); c != 0 {
+			return c
+		}
+		return compareTime(a.CreatedAt, b.CreatedAt)
+	})
+	return lines
+}
+
+func (s *Store) GetTotalStatementLines(ctx context.Context, statementID string) (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lines := where(s.statementLines, func(l models.BankStatementLine) bool { return l.StatementID == statementID })
+	return sum(lines, func(l models.BankStatementLine) float64 { return l.Amount }), nil
+}
+
+func (s *Store) GetBankBookEntries(ctx context.Context, accountID string, asOf time.Time) ([]models.BankBookEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	accounts := set(s.accountSubtree(accountID))
+	entry := func(t models.Transaction, amount float64) models.BankBookEntry {
+		return models.BankBookEntry{
+			TransactionID:   t.ID,
+			TransactionRef:  t.TransactionRef,
+			TransactionDate: t.TransactionDate,
+			TransactionType: t.TransactionType,
+			Notes:           t.Notes,
+			Amount:          amount,
+		}
+	}
+
+	var entries []models.BankBookEntry
+	for _, t := range s.transactions {
+		if t.Status != string(models.TransactionPosted) || t.TransactionDate.After(asOf) {
+			continue
+		}
+		if accounts[t.DebitAccountID] && (t.TransactionType == string(models.TransactionReceipts) || t.TransactionType == string(models.TransactionTransfer)) {
+			entries = append(entries, entry(t, t.Amount))
+		}
+	}
+	for _, t := range s.transactions {
+		if t.Status != string(models.TransactionPosted) || t.TransactionDate.After(asOf) {
+			continue
+		}
+		lines := where(s.expenditures, func(e models.Expenditure) bool { return e.TransactionID == t.ID && accounts[e.BankAccountID] })
+		if len(lines) > 0 {
+			entries = append(entries, entry(t, -sum(lines, func(e models.Expenditure) float64 { return e.Amount })))
+		}
+	}
+	for _, t := range s.transactions {
+		if t.Status != string(models.TransactionPosted) || t.TransactionDate.After(asOf) {
+			continue
+		}
+		lines := where(s.transfers, func(tr models.Transfer) bool { return tr.TransactionID == t.ID && accounts[tr.CreditAccountID] })
+		if len(lines) > 0 {
+			entries = append(entries, entry(t, -sum(lines, func(tr models.Transfer) float64 { return tr.Amount })))
+		}
+	}
+
+	sortBy(entries, func(a, b models.BankBookEntry) int {
+		if c := compareTime(a.TransactionDate, b.TransactionDate); c != 0 {
+			return c
+		}
+		return compare(a.TransactionID, b.TransactionID)
+	})
+	return entries, nil
+}
+
+func (s *Store) GetMatchedTransactionIDs(ctx context.Context, accountID string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ids []string
+	for _, l := range s.statementLines {
+		if l.BankAccountID == accountID && l.TransactionID != nil {
+			ids = append(ids, *l.TransactionID)
+		}
+	}
+	return ids, nil
+}
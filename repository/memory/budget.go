@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) SaveBudget(ctx context.Context, budget models.Budget) (models.Budget, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	budget.ID = uuid.New().String()
+	budget.CreatedAt = time.Now()
+	budget.UpdatedAt = time.Now()
+
+	i := find(s.budgets, func(b models.Budget) bool {
+		return b.AccountID == budget.AccountID && b.Year == budget.Year && b.Month == budget.Month
+	})
+	if i < 0 {
+		s.budgets = append(s.budgets, budget)
+		return budget, nil
+	}
+
+	row := &s.budgets[i]
+	row.Amount = budget.Amount
+	row.Notes = budget.Notes
+	row.UpdatedAt = budget.UpdatedAt
+	return *row, nil
+}
+
+func (s *Store) UpdateBudget(ctx context.Context, budget models.Budget) (models.Budget, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	budget.UpdatedAt = time.Now()
+
+	if i := find(s.budgets, func(b models.Budget) bool { return b.ID == budget.ID }); i >= 0 {
+		row := &s.budgets[i]
+		row.Amount = budget.Amount
+		row.Notes = budget.Notes
+		row.UpdatedAt = budget.UpdatedAt
+	}
+	return budget, nil
+}
+
+func (s *Store) DeleteBudgetsByAccount(ctx context.Context, accountID string, year int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.budgets = remove(s.budgets, func(b models.Budget) bool { return b.AccountID == accountID && b.Year == year })
+	return nil
+}
+
+func (s *Store) GetBudget(ctx context.Context, id string) (models.Budget, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.budgets, func(b models.Budget) bool { return b.ID == id })
+}
+
+func (s *Store) GetBudgetByMonth(ctx context.Context, accountID string, year, month int) (models.Budget, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.budgets, func(b models.Budget) bool {
+		return b.AccountID == accountID && b.Year == year && b.Month == month
+	})
+}
+
+func (s *Store) GetBudgetsByAccount(ctx context.Context, accountID string, year int) ([]models.Budget, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	budgets := where(s.budgets, func(b models.Budget) bool { return b.AccountID == accountID && b.Year == year })
+	sortBy(budgets, func(a, b models.Budget) int { return compare(a.Month, b.Month) })
+	return budgets, nil
+}
+
+func (s *Store) GetBudgetsByYear(ctx context.Context, year int) ([]models.Budget, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	budgets := where(s.budgets, func(b models.Budget) bool { return b.Year == year })
+	sortBy(budgets, func(a, b models.Budget) int {
+		if c := compare(a.AccountID, b.AccountID); c != 0 {
+			return c
+		}
+		return compare(a.Month, b.Month)
+	})
+	return budgets, nil
+}
+
+func (s *Store) GetBudgetTotal(ctx context.Context, accountID string, year, fromMonth, toMonth int) (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	accounts := set(s.accountSubtree(accountID))
+	budgets := where(s.budgets, func(b models.Budget) bool {
+		return accounts[b.AccountID] && b.Year == year && b.Month >= fromMonth && b.Month <= toMonth
+	})
+	return sum(budgets, func(b models.Budget) float64 { return b.Amount }), nil
+}
+
+func (s *Store) CountBudgets(ctx context.Context, accountID string, year int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(where(s.budgets, func(b models.Budget) bool { return b.AccountID == accountID && b.Year == year })), nil
+}
@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) CreateCampaign(ctx context.Context, campaign models.Campaign) (models.Campaign, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	campaign.ID = uuid.New().String()
+	if campaign.StartDate.IsZero() {
+		campaign.StartDate = time.Now()
+	}
+	campaign.CreatedAt = time.Now()
+	campaign.UpdatedAt = time.Now()
+
+	s.campaigns = append(s.campaigns, campaign)
+	return campaign, nil
+}
+
+func (s *Store) UpdateCampaign(ctx context.Context, campaign models.Campaign) (models.Campaign, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	campaign.UpdatedAt = time.Now()
+
+	if i := find(s.campaigns, func(c models.Campaign) bool { return c.ID == campaign.ID }); i >= 0 {
+		row := &s.campaigns[i]
+		row.CampaignName = campaign.CampaignName
+		row.TargetAmount = campaign.TargetAmount
+		row.StartDate = campaign.StartDate
+		row.EndDate = campaign.EndDate
+		row.Notes = campaign.Notes
+		row.IsActive = campaign.IsActive
+		row.UpdatedAt = campaign.UpdatedAt
+	}
+	return campaign, nil
+}
+
+func (s *Store) GetCampaign(ctx context.Context, id string) (models.Campaign, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.campaigns, func(c models.Campaign) bool { return c.ID == id })
+}
+
+func (s *Store) GetCampaignByName(ctx context.Context, name string) (models.Campaign, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.campaigns, func(c models.Campaign) bool { return c.CampaignName == name })
+}
+
+func (s *Store) GetAllCampaigns(ctx context.Context) ([]models.Campaign, error) {
+	return s.campaignsWhere(func(models.Campaign) bool { return true }), nil
+}
+
+func (s *Store) GetCampaignsByAccount(ctx context.Context, accountID string) ([]models.Campaign, error) {
+	return s.campaignsWhere(func(c models.Campaign) bool { return c.IncomeAccountID == accountID }), nil
+}
+
+// campaignsWhere returns the matching campaigns, latest start first
+func (s *Store) campaignsWhere(match func(models.Campaign) bool) []models.Campaign {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	campaigns := where(s.campaigns, match)
+	sortBy(campaigns, func(a, b models.Campaign) int { return compareTime(b.StartDate, a.StartDate) })
+	return campaigns
+}
@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) CreateCheque(ctx context.Context, cheque models.Cheque) (models.Cheque, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cheque.ID = uuid.New().String()
+	cheque.CreatedAt = time.Now()
+	cheque.UpdatedAt = time.Now()
+
+	if find(s.cheques, func(c models.Cheque) bool {
+		return c.BankAccountID == cheque.BankAccountID && c.ChequeNumber == cheque.ChequeNumber
+	}) >= 0 {
+		return models.Cheque{}, uniqueViolation("cheques_bank_account_cheque_number_key", "bank_account, cheque_number", cheque.BankAccountID, cheque.ChequeNumber)
+	}
+	s.cheques = append(s.cheques, cheque)
+	return cheque, nil
+}
+
+func (s *Store) UpdateCheque(ctx context.Context, cheque models.Cheque) (models.Cheque, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cheque.UpdatedAt = time.Now()
+
+	if i := find(s.cheques, func(c models.Cheque) bool { return c.ID == cheque.ID }); i >= 0 {
+		row := &s.cheques[i]
+		row.Status = cheque.Status
+		row.PresentedDate = cheque.PresentedDate
+		row.CancelledAt = cheque.CancelledAt
+		row.CancelReason = cheque.CancelReason
+		row.Notes = cheque.Notes
+		row.UpdatedAt = cheque.UpdatedAt
+	}
+	return cheque, nil
+}
+
+func (s *Store) GetCheque(ctx context.Context, id string) (models.Cheque, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.cheques, func(c models.Cheque) bool { return c.ID == id })
+}
+
+func (s *Store) GetChequeByNumber(ctx context.Context, bankAccountID, chequeNumber string) (models.Cheque, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.cheques, func(c models.Cheque) bool {
+		return c.BankAccountID == bankAccountID && c.ChequeNumber == chequeNumber
+	})
+}
+
+func (s *Store) GetCheques(ctx context.Context, bankAccountID, status string) ([]models.Cheque, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cheques := where(s.cheques, func(c models.Cheque) bool {
+		return (bankAccountID == "" || c.BankAccountID == bankAccountID) && (status == "" || c.Status == status)
+	})
+	sortBy(cheques, func(a, b models.Cheque) int {
+		if c := compareTime(b.IssueDate, a.IssueDate); c != 0 {
+			return c
+		}
+		return compare(b.ChequeNumber, a.ChequeNumber)
+	})
+	return cheques, nil
+}
+
+func (s *Store) GetChequesByTransaction(ctx context.Context, transactionID string) ([]models.Cheque, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cheques := where(s.cheques, func(c models.Cheque) bool { return is(c.TransactionID, transactionID) })
+	sortBy(cheques, func(a, b models.Cheque) int { return compareTime(a.IssueDate, b.IssueDate) })
+	return cheques, nil
+}
+
+func (s *Store) GetChequesIssuedBy(ctx context.Context, bankAccountID string, asOf time.Time) ([]models.Cheque, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cheques := where(s.cheques, func(c models.Cheque) bool {
+		return c.BankAccountID == bankAccountID && !c.IssueDate.After(asOf) &&
+			(c.PresentedDate == nil || c.PresentedDate.After(asOf)) &&
+			(c.CancelledAt == nil || c.CancelledAt.After(asOf))
+	})
+	sortBy(cheques, func(a, b models.Cheque) int {
+		if c := compareTime(a.IssueDate, b.IssueDate); c != 0 {
+			return c
+		}
+		return compare(a.ChequeNumber, b.ChequeNumber)
+	})
+	return cheques, nil
+}
+
+func (s *Store) GetChequeBankAccounts(ctx context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ids []string
+	seen := make(map[string]bool)
+	for _, c := range s.cheques {
+		if !seen[c.BankAccountID] {
+			seen[c.BankAccountID] = true
+			ids = append(ids, c.BankAccountID)
+		}
+	}
+	return ids, nil
+}
+
+func (s *Store) MarkStaleCheques(ctx context.Context, staleBefore time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var changed int64
+	for i := range s.cheques {
+		row := &s.cheques[i]
+		if row.Status == string(models.ChequeIssued) && row.IssueDate.Before(staleBefore) {
+			row.Status = string(models.ChequeStale)
+			row.UpdatedAt = time.Now()
+			changed++
+		}
+	}
+	return changed, nil
+}
@@ -0,0 +1,200 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) CreateChurch(ctx context.Context, church models.Church, adminID string) (models.Church, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	church.ID = uuid.New().String()
+	church.CreatedAt = time.Now()
+	church.UpdatedAt = time.Now()
+
+	if err := s.checkChurch(church); err != nil {
+		return models.Church{}, err
+	}
+	s.churches = append(s.churches, church)
+
+	s.churchUsers = append(s.churchUsers, models.ChurchUser{
+		ID:        uuid.New().String(),
+		ChurchID:  church.ID,
+		UserID:    adminID,
+		Role:      string(models.RoleAdmin),
+		CreatedAt: time.Now(),
+	})
+	return church, nil
+}
+
+func (s *Store) UpdateChurch(ctx context.Context, church models.Church) (models.Church, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	church.UpdatedAt = time.Now()
+
+	i := find(s.churches, func(c models.Church) bool { return c.ID == church.ID })
+	if i < 0 {
+		return church, nil
+	}
+	if err := s.checkChurch(church); err != nil {
+		return models.Church{}, err
+	}
+	row := &s.churches[i]
+	row.Name = church.Name
+	row.MpesaShortcode = church.MpesaShortcode
+	row.IsActive = church.IsActive
+	row.UpdatedAt = church.UpdatedAt
+	return church, nil
+}
+
+// checkChurch keeps church codes and M-Pesa short codes unique
+func (s *Store) checkChurch(church models.Church) error {
+	if find(s.churches, func(c models.Church) bool { return c.ID != church.ID && c.Code == church.Code }) >= 0 {
+		return uniqueViolation("churches_code_key", "code", church.Code)
+	}
+	if church.MpesaShortcode != nil && find(s.churches, func(c models.Church) bool {
+		return c.ID != church.ID && is(c.MpesaShortcode, *church.MpesaShortcode)
+	}) >= 0 {
+		return uniqueViolation("churches_mpesa_shortcode_key", "mpesa_shortcode", *church.MpesaShortcode)
+	}
+	return nil
+}
+
+func (s *Store) GetChurch(ctx context.Context, id string) (models.Church, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.churches, func(c models.Church) bool { return c.ID == id })
+}
+
+func (s *Store) GetChurchByCode(ctx context.Context, code string) (models.Church, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.churches, func(c models.Church) bool { return c.Code == code })
+}
+
+func (s *Store) GetChurchByShortcode(ctx context.Context, shortcode string) (models.Church, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.churches, func(c models.Church) bool { return is(c.MpesaShortcode, shortcode) && c.IsActive })
+}
+
+func (s *Store) GetActiveChurches(ctx context.Context) ([]models.Church, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	churches := where(s.churches, func(c models.Church) bool { return c.IsActive })
+	sortBy(churches, func(a, b models.Church) int { return compare(a.Name, b.Name) })
+	return churches, nil
+}
+
+func (s *Store) GetUserChurches(ctx context.Context, userID string) ([]models.UserChurch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var churches []models.UserChurch
+	for _, cu := range s.churchUsers {
+		if cu.UserID != userID {
+			continue
+		}
+		church, err := get(s.churches, func(c models.Church) bool { return c.ID == cu.ChurchID })
+		if err != nil {
+			continue
+		}
+		if find(s.users, func(u models.User) bool { return u.ID == cu.UserID }) < 0 {
+			continue
+		}
+		churches = append(churches, models.UserChurch{Church: church, Role: cu.Role})
+	}
+	sortBy(churches, func(a, b models.UserChurch) int { return compare(a.Name, b.Name) })
+	return churches, nil
+}
+
+func (s *Store) GetChurchRole(ctx context.Context, churchID, userID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cu, err := get(s.churchUsers, func(cu models.ChurchUser) bool { return cu.ChurchID == churchID && cu.UserID == userID })
+	if err != nil {
+		return "", err
+	}
+	if find(s.users, func(u models.User) bool { return u.ID == userID }) < 0 {
+		return "", sql.ErrNoRows
+	}
+	return cu.Role, nil
+}
+
+func (s *Store) GetChurchUsers(ctx context.Context, churchID string) ([]models.ChurchUserResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var users []models.ChurchUserResponse
+	for _, cu := range s.churchUsers {
+		if cu.ChurchID != churchID {
+			continue
+		}
+		user, err := get(s.users, func(u models.User) bool { return u.ID == cu.UserID })
+		if err != nil {
+			continue
+		}
+		users = append(users, models.ChurchUserResponse{
+			UserID:   user.ID,
+			Username: user.Username,
+			FullName: user.FullName,
+			Role:     cu.Role,
+			IsActive: user.IsActive,
+		})
+	}
+	sortBy(users, func(a, b models.ChurchUserResponse) int { return compare(a.FullName, b.FullName) })
+	return users, nil
+}
+
+func (s *Store) SaveChurchUser(ctx context.Context, member models.ChurchUser) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i := find(s.churchUsers, func(cu models.ChurchUser) bool {
+		return cu.ChurchID == member.ChurchID && cu.UserID == member.UserID
+	}); i >= 0 {
+		s.churchUsers[i].Role = member.Role
+		return nil
+	}
+
+	member.ID = uuid.New().String()
+	member.CreatedAt = time.Now()
+	s.churchUsers = append(s.churchUsers, member)
+	return nil
+}
+
+func (s *Store) RemoveChurchUser(ctx context.Context, churchID, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.churchUsers = remove(s.churchUsers, func(cu models.ChurchUser) bool { return cu.ChurchID == churchID && cu.UserID == userID })
+	return nil
+}
+
+func (s *Store) CountChurchAdmins(ctx context.Context, churchID string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for _, cu := range s.churchUsers {
+		if cu.ChurchID != churchID {
+			continue
+		}
+		user, err := get(s.users, func(u models.User) bool { return u.ID == cu.UserID })
+		if err == nil && user.IsActive && cu.Role == string(models.RoleAdmin) {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,167 @@
+package memory
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) CreateCollectionSession(ctx context.Context, session models.CollectionSession) (models.CollectionSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session.ID = uuid.New().String()
+	session.CreatedAt = time.Now()
+	session.UpdatedAt = time.Now()
+
+	s.collectionSessions = append(s.collectionSessions, session)
+	return session, nil
+}
+
+func (s *Store) UpdateCollectionSession(ctx context.Context, session models.CollectionSession) (models.CollectionSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session.UpdatedAt = time.Now()
+
+	if i := find(s.collectionSessions, func(c models.CollectionSession) bool { return c.ID == session.ID }); i >= 0 {
+		row := &s.collectionSessions[i]
+		row.Status = session.Status
+		row.Notes = session.Notes
+		row.CancelledBy = session.CancelledBy
+		row.CancelledAt = session.CancelledAt
+		row.UpdatedAt = session.UpdatedAt
+	}
+	return session, nil
+}
+
+func (s *Store) GetCollectionSession(ctx context.Context, id string) (models.CollectionSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.collectionSessions, func(c models.CollectionSession) bool { return c.ID == id })
+}
+
+func (s *Store) GetCollectionSessions(ctx context.Context, status string) ([]models.CollectionSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions := where(s.collectionSessions, func(c models.CollectionSession) bool { return status == "" || c.Status == status })
+	sortBy(sessions, func(a, b models.CollectionSession) int {
+		if c := compareTime(b.ServiceDate, a.ServiceDate); c != 0 {
+			return c
+		}
+		return compareTime(b.CreatedAt, a.CreatedAt)
+	})
+	return sessions, nil
+}
+
+func (s *Store) GetTransactionsByCollectionSession(ctx context.Context, sessionID string) ([]models.Transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	txns := where(s.transactions, func(t models.Transaction) bool { return is(t.CollectionSessionID, sessionID) })
+	sortBy(txns, func(a, b models.Transaction) int { return compareTime(a.CreatedAt, b.CreatedAt) })
+	return txns, nil
+}
+
+func (s *Store) DeleteCollectionReceipt(ctx context.Context, transactionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteReceipts(func(r models.Receipt) bool { return r.TransactionID == transactionID })
+	return s.deleteTransactions(func(t models.Transaction) bool {
+		return t.ID == transactionID && t.Status == string(models.TransactionDraft)
+	})
+}
+
+func (s *Store) GetCollectionDenominations(ctx context.Context, sessionID string) ([]models.CollectionDenomination, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	denominations := where(s.collectionDenominations, func(d models.CollectionDenomination) bool { return d.SessionID == sessionID })
+	sortBy(denominations, func(a, b models.CollectionDenomination) int { return compare(b.Denomination, a.Denomination) })
+	return denominations, nil
+}
+
+func (s *Store) ReplaceCollectionDenominations(ctx context.Context, sessionID string, denominations []models.CollectionDenomination) ([]models.CollectionDenomination, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := make(map[float64]bool)
+	for i := range denominations {
+		denominations[i].ID = uuid.New().String()
+		denominations[i].SessionID = sessionID
+		if seen[denominations[i].Denomination] {
+			return nil, uniqueViolation("collection_denominations_session_id_denomination_key", "session_id, denomination", sessionID, denominations[i].Denomination)
+		}
+		seen[denominations[i].Denomination] = true
+	}
+
+	s.collectionDenominations = remove(s.collectionDenominations, func(d models.CollectionDenomination) bool { return d.SessionID == sessionID })
+	s.collectionDenominations = append(s.collectionDenominations, denominations...)
+	return denominations, nil
+}
+
+func (s *Store) GetCollectionSignOffs(ctx context.Context, sessionID string) ([]models.CollectionSignOff, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	signOffs := where(s.collectionSignOffs, func(c models.CollectionSignOff) bool { return c.SessionID == sessionID })
+	sortBy(signOffs, func(a, b models.CollectionSignOff) int { return compareTime(a.SignedAt, b.SignedAt) })
+	return signOffs, nil
+}
+
+func (s *Store) ClearCollectionSignOffs(ctx context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.collectionSignOffs = remove(s.collectionSignOffs, func(c models.CollectionSignOff) bool { return c.SessionID == sessionID })
+	return nil
+}
+
+func (s *Store) SignOffCollectionSession(ctx context.Context, signOff models.CollectionSignOff, required int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	signOff.ID = uuid.New().String()
+	signOff.SignedAt = time.Now()
+
+	if i := find(s.collectionSignOffs, func(c models.CollectionSignOff) bool {
+		return c.SessionID == signOff.SessionID && c.UserID == signOff.UserID
+	}); i >= 0 {
+		row := &s.collectionSignOffs[i]
+		row.CashTotal = signOff.CashTotal
+		row.ReceiptsTotal = signOff.ReceiptsTotal
+		row.SignedAt = signOff.SignedAt
+	} else {
+		s.collectionSignOffs = append(s.collectionSignOffs, signOff)
+	}
+
+	matching := where(s.collectionSignOffs, func(c models.CollectionSignOff) bool {
+		return c.SessionID == signOff.SessionID && c.CashTotal == signOff.CashTotal && c.ReceiptsTotal == signOff.ReceiptsTotal
+	})
+	if len(matching) < required {
+		return false, nil
+	}
+
+	now := time.Now()
+	for i := range s.transactions {
+		row := &s.transactions[i]
+		if is(row.CollectionSessionID, signOff.SessionID) && row.Status == string(models.TransactionDraft) {
+			row.Status = string(models.TransactionPosted)
+			row.PostedBy = &signOff.UserID
+			row.PostedAt = &now
+			row.UpdatedAt = now
+		}
+	}
+	if i := find(s.collectionSessions, func(c models.CollectionSession) bool { return c.ID == signOff.SessionID }); i >= 0 {
+		row := &s.collectionSessions[i]
+		row.Status = string(models.CollectionPosted)
+		row.PostedAt = &now
+		row.UpdatedAt = now
+	}
+	return true, nil
+}
@@ -0,0 +1,165 @@
+package memory
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// districtUser is a row of district_users, which has no model of its own
+type districtUser struct {
+	ID         string
+	DistrictID string
+	UserID     string
+	Role       string
+	CreatedAt  time.Time
+}
+
+func (s *Store) CreateDistrict(ctx context.Context, district models.District, adminID string) (models.District, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	district.ID = uuid.New().String()
+	district.CreatedAt = time.Now()
+	district.UpdatedAt = time.Now()
+
+	if find(s.districts, func(d models.District) bool { return d.Code == district.Code }) >= 0 {
+		return models.District{}, uniqueViolation("districts_code_key", "code", district.Code)
+	}
+	s.districts = append(s.districts, district)
+	s.districtUsers = append(s.districtUsers, districtUser{
+		ID:         uuid.New().String(),
+		DistrictID: district.ID,
+		UserID:     adminID,
+		Role:       string(models.DistrictAdmin),
+		CreatedAt:  time.Now(),
+	})
+	return district, nil
+}
+
+func (s *Store) GetDistrict(ctx context.Context, id string) (models.District, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.districts, func(d models.District) bool { return d.ID == id })
+}
+
+func (s *Store) GetDistrictByCode(ctx context.Context, code string) (models.District, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.districts, func(d models.District) bool { return d.Code == code })
+}
+
+func (s *Store) GetUserDistricts(ctx context.Context, userID string) ([]models.UserDistrict, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var districts []models.UserDistrict
+	for _, du := range s.districtUsers {
+		if du.UserID != userID {
+			continue
+		}
+		if district, err := get(s.districts, func(d models.District) bool { return d.ID == du.DistrictID }); err == nil {
+			districts = append(districts, models.UserDistrict{District: district, Role: du.Role})
+		}
+	}
+	sortBy(districts, func(a, b models.UserDistrict) int { return compare(a.Name, b.Name) })
+	return districts, nil
+}
+
+func (s *Store) GetDistrictRole(ctx context.Context, districtID, userID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	du, err := get(s.districtUsers, func(du districtUser) bool {
+		return du.DistrictID == districtID && du.UserID == userID &&
+			find(s.users, func(u models.User) bool { return u.ID == userID && u.IsActive }) >= 0
+	})
+	if err != nil {
+		return "", err
+	}
+	return du.Role, nil
+}
+
+func (s *Store) GetDistrictChurches(ctx context.Context, districtID string) ([]models.Church, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	churches := where(s.churches, func(c models.Church) bool { return is(c.DistrictID, districtID) })
+	sortBy(churches, func(a, b models.Church) int { return compare(a.Name, b.Name) })
+	return churches, nil
+}
+
+func (s *Store) SetChurchDistrict(ctx context.Context, churchID string, districtID *string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i := find(s.churches, func(c models.Church) bool { return c.ID == churchID }); i >= 0 {
+		s.churches[i].DistrictID = districtID
+		s.churches[i].UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+func (s *Store) GetDistrictUsers(ctx context.Context, districtID string) ([]models.DistrictUserResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var users []models.DistrictUserResponse
+	for _, du := range s.districtUsers {
+		if du.DistrictID != districtID {
+			continue
+		}
+		if user, err := get(s.users, func(u models.User) bool { return u.ID == du.UserID }); err == nil {
+			users = append(users, models.DistrictUserResponse{
+				UserID:   user.ID,
+				Username: user.Username,
+				FullName: user.FullName,
+				Role:     du.Role,
+				IsActive: user.IsActive,
+			})
+		}
+	}
+	sortBy(users, func(a, b models.DistrictUserResponse) int { return compare(a.FullName, b.FullName) })
+	return users, nil
+}
+
+func (s *Store) SaveDistrictUser(ctx context.Context, districtID, userID, role string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i := find(s.districtUsers, func(du districtUser) bool { return du.DistrictID == districtID && du.UserID == userID }); i >= 0 {
+		s.districtUsers[i].Role = role
+		return nil
+	}
+	s.districtUsers = append(s.districtUsers, districtUser{
+		ID:         uuid.New().String(),
+		DistrictID: districtID,
+		UserID:     userID,
+		Role:       role,
+		CreatedAt:  time.Now(),
+	})
+	return nil
+}
+
+func (s *Store) RemoveDistrictUser(ctx context.Context, districtID, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.districtUsers = remove(s.districtUsers, func(du districtUser) bool { return du.DistrictID == districtID && du.UserID == userID })
+	return nil
+}
+
+func (s *Store) CountDistrictAdmins(ctx context.Context, districtID string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	admins := where(s.districtUsers, func(du districtUser) bool {
+		return du.DistrictID == districtID && du.Role == string(models.DistrictAdmin) &&
+			find(s.users, func(u models.User) bool { return u.ID == du.UserID && u.IsActive }) >= 0
+	})
+	return len(admins), nil
+}
@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) CreateEmail(ctx context.Context, email models.Email, attachments []models.EmailAttachment) (models.Email, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	email.ID = uuid.New().String()
+	email.CreatedAt = time.Now()
+	email.UpdatedAt = time.Now()
+
+	s.emails = append(s.emails, email)
+	for _, a := range attachments {
+		a.ID = uuid.New().String()
+		a.EmailID = email.ID
+		a.CreatedAt = time.Now()
+		s.emailAttachments = append(s.emailAttachments, a)
+	}
+	return email, nil
+}
+
+func (s *Store) UpdateEmail(ctx context.Context, email models.Email) (models.Email, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	email.UpdatedAt = time.Now()
+
+	if i := find(s.emails, func(e models.Email) bool { return e.ID == email.ID }); i >= 0 {
+		row := &s.emails[i]
+		row.Status = email.Status
+		row.Attempts = email.Attempts
+		row.LastError = email.LastError
+		row.NextAttemptAt = email.NextAttemptAt
+		row.SentAt = email.SentAt
+		row.BouncedAt = email.BouncedAt
+		row.UpdatedAt = email.UpdatedAt
+	}
+	return email, nil
+}
+
+func (s *Store) GetEmail(ctx context.Context, id string) (models.Email, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.emails, func(e models.Email) bool { return e.ID == id })
+}
+
+func (s *Store) GetEmailAttachments(ctx context.Context, emailID string) ([]models.EmailAttachment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attachments := where(s.emailAttachments, func(a models.EmailAttachment) bool { return a.EmailID == emailID })
+	sortBy(attachments, func(a, b models.EmailAttachment) int { return compareTime(a.CreatedAt, b.CreatedAt) })
+	return attachments, nil
+}
+
+func (s *Store) GetDueEmails(ctx context.Context, asOf time.Time, limit int) ([]models.Email, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	emails := where(s.emails, func(e models.Email) bool {
+		return e.Status == string(models.EmailQueued) && !e.NextAttemptAt.After(asOf)
+	})
+	sortBy(emails, func(a, b models.Email) int { return compareTime(a.NextAttemptAt, b.NextAttemptAt) })
+	return limitTo(emails, limit), nil
+}
+
+func (s *Store) GetEmailsByStatus(ctx context.Context, status string) ([]models.Email, error) {
+	return s.emailsWhere(func(e models.Email) bool { return e.Status == status }), nil
+}
+
+func (s *Store) GetAllEmails(ctx context.Context) ([]models.Email, error) {
+	return s.emailsWhere(func(models.Email) bool { return true }), nil
+}
+
+// emailsWhere returns the matching emails, newest first
+func (s *Store) emailsWhere(match func(models.Email) bool) []models.Email {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	emails := where(s.emails, match)
+	sortBy(emails, func(a, b models.Email) int { return compareTime(b.CreatedAt, a.CreatedAt) })
+	return emails
+}
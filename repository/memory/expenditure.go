@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) CreateExpenditure(ctx context.Context, exp models.Expenditure) (models.Expenditure, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exp.ID = uuid.New().String()
+	exp.CreatedAt = time.Now()
+	exp.UpdatedAt = time.Now()
+
+	s.expenditures = append(s.expenditures, exp)
+	return exp, nil
+}
+
+func (s *Store) UpdateExpenditure(ctx context.Context, exp models.Expenditure) (models.Expenditure, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exp.UpdatedAt = time.Now()
+
+	if i := find(s.expenditures, func(e models.Expenditure) bool { return e.ID == exp.ID }); i >= 0 {
+		row := &s.expenditures[i]
+		row.Particulars = exp.Particulars
+		row.BankAccountID = exp.BankAccountID
+		row.PayeeID = exp.PayeeID
+		row.FundID = exp.FundID
+		row.Amount = exp.Amount
+		row.UpdatedAt = exp.UpdatedAt
+	}
+	return exp, nil
+}
+
+func (s *Store) DeleteExpenditure(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteExpenditures(func(e models.Expenditure) bool { return e.ID == id })
+	return nil
+}
+
+// deleteExpenditures drops the matching expenditure lines with the attachments that
+// cascade from them
+func (s *Store) deleteExpenditures(match func(models.Expenditure) bool) {
+	for _, e := range where(s.expenditures, match) {
+		s.attachments = remove(s.attachments, func(a models.Attachment) bool { return is(a.ExpenditureID, e.ID) })
+	}
+	s.expenditures = remove(s.expenditures, match)
+}
+
+func (s *Store) GetExpenditure(ctx context.Context, id string) (models.Expenditure, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.expenditures, func(e models.Expenditure) bool { return e.ID == id })
+}
+
+func (s *Store) GetExpenditureByTransaction(ctx context.Context, transactionID string) ([]models.Expenditure, error) {
+	return s.expendituresWhere(func(e models.Expenditure) bool { return e.TransactionID == transactionID }), nil
+}
+
+func (s *Store) GetExpenditureByAccount(ctx context.Context, accountID string) ([]models.Expenditure, error) {
+	s.mu.Lock()
+	accounts := set(s.accountSubtree(accountID))
+	s.mu.Unlock()
+
+	return s.expendituresWhere(func(e models.Expenditure) bool { return accounts[e.BankAccountID] }), nil
+}
+
+func (s *Store) GetExpenditureByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.Expenditure, error) {
+	return s.expendituresWhere(func(e models.Expenditure) bool { return between(e.CreatedAt, startDate, endDate) }), nil
+}
+
+func (s *Store) GetAllExpenditures(ctx context.Context) ([]models.Expenditure, error) {
+	return s.expendituresWhere(func(models.Expenditure) bool { return true }), nil
+}
+
+// expendituresWhere returns the matching expenditure lines, newest first
+func (s *Store) expendituresWhere(match func(models.Expenditure) bool) []models.Expenditure {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expenses := where(s.expenditures, match)
+	sortBy(expenses, func(a, b models.Expenditure) int { return compareTime(b.CreatedAt, a.CreatedAt) })
+	return expenses
+}
+
+func (s *Store) GetTotalExpendituresByTransactionDate(ctx context.Context, accountID string, startDate, endDate time.Time) (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	accounts := set(s.accountSubtree(accountID))
+	expenses := where(s.expenditures, func(e models.Expenditure) bool {
+		t, err := s.transaction(e.TransactionID)
+		return err == nil && accounts[t.DebitAccountID] && t.Status == string(models.TransactionPosted) &&
+			between(t.TransactionDate, startDate, endDate)
+	})
+	return sum(expenses, func(e models.Expenditure) float64 { return e.Amount }), nil
+}
+
+func (s *Store) GetTotalExpendituresByTransaction(ctx context.Context, transactionID string) (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expenses := where(s.expenditures, func(e models.Expenditure) bool { return e.TransactionID == transactionID })
+	return sum(expenses, func(e models.Expenditure) float64 { return e.Amount }), nil
+}
@@ -0,0 +1,182 @@
+package memory
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) CreateFund(ctx context.Context, fund models.Fund) (models.Fund, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fund.ID = uuid.New().String()
+	fund.CreatedAt = time.Now()
+	fund.UpdatedAt = time.Now()
+
+	if find(s.funds, func(f models.Fund) bool { return f.Code == fund.Code }) >= 0 {
+		return models.Fund{}, uniqueViolation("funds_church_code_key", "code", fund.Code)
+	}
+	s.funds = append(s.funds, fund)
+	return fund, nil
+}
+
+func (s *Store) UpdateFund(ctx context.Context, fund models.Fund) (models.Fund, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fund.UpdatedAt = time.Now()
+
+	if i := find(s.funds, func(f models.Fund) bool { return f.ID == fund.ID }); i >= 0 {
+		row := &s.funds[i]
+		row.Name = fund.Name
+		row.Description = fund.Description
+		row.IsRestricted = fund.IsRestricted
+		row.IsActive = fund.IsActive
+		row.UpdatedAt = fund.UpdatedAt
+	}
+	return fund, nil
+}
+
+func (s *Store) GetFund(ctx context.Context, id string) (models.Fund, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.funds, func(f models.Fund) bool { return f.ID == id })
+}
+
+func (s *Store) GetFundByCode(ctx context.Context, code string) (models.Fund, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.funds, func(f models.Fund) bool { return f.Code == code })
+}
+
+func (s *Store) GetAllFunds(ctx context.Context) ([]models.Fund, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	funds := where(s.funds, func(models.Fund) bool { return true })
+	sortBy(funds, func(a, b models.Fund) int { return compare(a.Code, b.Code) })
+	return funds, nil
+}
+
+func (s *Store) GetFundTotals(ctx context.Context, asOf time.Time) ([]models.FundTotals, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var totals []models.FundTotals
+	add := func(fundID *string, receipts, expenditures float64) {
+		i := find(totals, func(t models.FundTotals) bool { return sameFund(t.FundID, fundID) })
+		if i < 0 {
+			totals = append(totals, models.FundTotals{FundID: fundID})
+			i = len(totals) - 1
+		}
+		totals[i].Receipts += receipts
+		totals[i].Expenditures += expenditures
+	}
+
+	for _, r := range s.receipts {
+		if _, ok := s.postedBy(r.TransactionID, asOf); ok {
+			add(r.FundID, r.Amount, 0)
+		}
+	}
+	for _, e := range s.expenditures {
+		if _, ok := s.postedBy(e.TransactionID, asOf); ok {
+			add(e.FundID, 0, e.Amount)
+		}
+	}
+	return totals, nil
+}
+
+// sameFund compares two fund references, treating two nulls as the same general fund
+func sameFund(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func (s *Store) GetFundCommitted(ctx context.Context, fundID string, excludeExpenditureID string) (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expenses := where(s.expenditures, func(e models.Expenditure) bool {
+		if !is(e.FundID, fundID) || e.ID == excludeExpenditureID {
+			return false
+		}
+		t, err := s.transaction(e.TransactionID)
+		if err != nil {
+			return false
+		}
+		switch models.TransactionStatus(t.Status) {
+		case models.TransactionDraft, models.TransactionSubmitted, models.TransactionApproved:
+			return true
+		}
+		return false
+	})
+	return sum(expenses, func(e models.Expenditure) float64 { return e.Amount }), nil
+}
+
+func (s *Store) GetFundSpendByTransaction(ctx context.Context, transactionID string) ([]models.FundTotals, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var totals []models.FundTotals
+	for _, e := range s.expenditures {
+		if e.TransactionID != transactionID {
+			continue
+		}
+		i := find(totals, func(t models.FundTotals) bool { return sameFund(t.FundID, e.FundID) })
+		if i < 0 {
+			totals = append(totals, models.FundTotals{FundID: e.FundID})
+			i = len(totals) - 1
+		}
+		totals[i].Expenditures += e.Amount
+	}
+	return totals, nil
+}
+
+func (s *Store) GetFundAccountBalances(ctx context.Context, fundID *string, asOf time.Time) ([]models.FundAccountBalance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	balances := make(map[string]float64)
+	var order []string
+	add := func(accountID string, amount float64) {
+		if _, ok := balances[accountID]; !ok {
+			order = append(order, accountID)
+		}
+		balances[accountID] += amount
+	}
+
+	for _, r := range s.receipts {
+		if t, ok := s.postedBy(r.TransactionID, asOf); ok && sameFund(r.FundID, fundID) {
+			add(t.DebitAccountID, r.Amount)
+		}
+	}
+	for _, e := range s.expenditures {
+		if _, ok := s.postedBy(e.TransactionID, asOf); ok && sameFund(e.FundID, fundID) {
+			add(e.BankAccountID, -e.Amount)
+		}
+	}
+	for _, tr := range s.transfers {
+		if t, ok := s.postedBy(tr.TransactionID, asOf); ok && sameFund(tr.FundID, fundID) {
+			add(t.DebitAccountID, tr.Amount)
+			add(tr.CreditAccountID, -tr.Amount)
+		}
+	}
+
+	var result []models.FundAccountBalance
+	for _, id := range order {
+		account, err := get(s.accounts, func(a models.Account) bool { return a.ID == id })
+		if err != nil || balances[id] == 0 {
+			continue
+		}
+		result = append(result, models.FundAccountBalance{AccountID: id, AccountName: account.AccountName, Balance: balances[id]})
+	}
+	sortBy(result, func(a, b models.FundAccountBalance) int { return compare(a.AccountName, b.AccountName) })
+	return result, nil
+}
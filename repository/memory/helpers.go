@@ -0,0 +1,174 @@
+package memory
+
+import (
+	"cmp"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// find returns the index of the first row that matches, or -1
+func find[T any](rows []T, match func(T) bool) int {
+	return slices.IndexFunc(rows, match)
+}
+
+// get returns the first row that matches, or sql.ErrNoRows as a query for one row does
+func get[T any](rows []T, match func(T) bool) (T, error) {
+	if i := find(rows, match); i >= 0 {
+		return rows[i], nil
+	}
+	var zero T
+	return zero, sql.ErrNoRows
+}
+
+// where returns a copy of the rows that match, or nil when none do
+func where[T any](rows []T, match func(T) bool) []T {
+	var matched []T
+	for _, row := range rows {
+		if match(row) {
+			matched = append(matched, row)
+		}
+	}
+	return matched
+}
+
+// remove drops the rows that match and returns what is left
+func remove[T any](rows []T, match func(T) bool) []T {
+	return slices.DeleteFunc(rows, match)
+}
+
+// sortBy orders rows in place, keeping rows that compare equal in the order they were added
+func sortBy[T any](rows []T, order func(a, b T) int) {
+	slices.SortStableFunc(rows, order)
+}
+
+// limitTo keeps at most n rows, as LIMIT does
+func limitTo[T any](rows []T, n int) []T {
+	if n >= 0 && len(rows) > n {
+		return rows[:n]
+	}
+	return rows
+}
+
+// sum adds up a value over the rows
+func sum[T any](rows []T, value func(T) float64) float64 {
+	var total float64
+	for _, row := range rows {
+		total += value(row)
+	}
+	return total
+}
+
+// compare orders two values ascending
+func compare[V cmp.Ordered](a, b V) int {
+	return cmp.Compare(a, b)
+}
+
+// compareTime orders two times ascending
+func compareTime(a, b time.Time) int {
+	return a.Compare(b)
+}
+
+// compareNullsLast orders two nullable values ascending with nulls after every value, as
+// ORDER BY ... ASC NULLS LAST does
+func compareNullsLast[V cmp.Ordered](a, b *V) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	return cmp.Compare(*a, *b)
+}
+
+// compareTimeNullsLast orders two nullable times ascending with nulls last
+func compareTimeNullsLast(a, b *time.Time) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	return a.Compare(*b)
+}
+
+// compareTimeDescNullsLast orders two nullable times descending with nulls last, as
+// ORDER BY ... DESC NULLS LAST does
+func compareTimeDescNullsLast(a, b *time.Time) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	return b.Compare(*a)
+}
+
+// set turns a list of IDs into a set for IN (...) checks
+func set(ids []string) map[string]bool {
+	members := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		members[id] = true
+	}
+	return members
+}
+
+// between reports whether t falls in [start, end], as BETWEEN does
+func between(t, start, end time.Time) bool {
+	return !t.Before(start) && !t.After(end)
+}
+
+// day drops the time of day, as a DATE column stores it
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// is reports whether a nullable value is set to v
+func is[V comparable](p *V, v V) bool {
+	return p != nil && *p == v
+}
+
+// contains reports whether s holds term, ignoring case, as ILIKE '%term%' does
+func contains(s, term string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(term))
+}
+
+// containsPtr is contains for a nullable column; null never matches
+func containsPtr(s *string, term string) bool {
+	return s != nil && contains(*s, term)
+}
+
+// uniqueViolation is the error Postgres returns when a row repeats a unique key
+func uniqueViolation(constraint, columns string, values ...interface{}) error {
+	formatted := make([]string, len(values))
+	for i, v := range values {
+		formatted[i] = fmt.Sprint(v)
+	}
+	return &pq.Error{
+		Code:       "23505",
+		Message:    fmt.Sprintf("duplicate key value violates unique constraint %q", constraint),
+		Detail:     fmt.Sprintf("Key (%s)=(%s) already exists.", columns, strings.Join(formatted, ", ")),
+		Constraint: constraint,
+	}
+}
+
+// stillReferenced is the error Postgres returns when deleting a row that another table
+// still points to
+func stillReferenced(table, column, value, by string) error {
+	return &pq.Error{
+		Code:    "23503",
+		Message: fmt.Sprintf("update or delete on table %q violates foreign key constraint on table %q", table, by),
+		Detail:  fmt.Sprintf("Key (%s)=(%s) is still referenced from table %q.", column, value, by),
+		Table:   table,
+	}
+}
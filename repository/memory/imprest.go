@@ -0,0 +1,328 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func (s *Store) CreateImprest(ctx context.Context, imprest models.Imprest) (models.Imprest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	imprest.ID = uuid.New().String()
+	imprest.CreatedAt = time.Now()
+	imprest.UpdatedAt = time.Now()
+
+	if find(s.imprests, func(i models.Imprest) bool { return i.AccountID == imprest.AccountID }) >= 0 {
+		return models.Imprest{}, uniqueViolation("imprests_account_key", "account", imprest.AccountID)
+	}
+	s.imprests = append(s.imprests, imprest)
+	return imprest, nil
+}
+
+func (s *Store) UpdateImprest(ctx context.Context, imprest models.Imprest) (models.Imprest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	imprest.UpdatedAt = time.Now()
+
+	if i := find(s.imprests, func(i models.Imprest) bool { return i.ID == imprest.ID }); i >= 0 {
+		row := &s.imprests[i]
+		row.Name = imprest.Name
+		row.FloatAmount = imprest.FloatAmount
+		row.ReplenishFromID = imprest.ReplenishFromID
+		row.CustodianID = imprest.CustodianID
+		row.IsActive = imprest.IsActive
+		row.UpdatedAt = imprest.UpdatedAt
+	}
+	return imprest, nil
+}
+
+func (s *Store) GetImprest(ctx context.Context, id string) (models.Imprest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.imprests, func(i models.Imprest) bool { return i.ID == id })
+}
+
+func (s *Store) GetImprestByAccount(ctx context.Context, accountID string) (models.Imprest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.imprests, func(i models.Imprest) bool { return i.AccountID == accountID })
+}
+
+func (s *Store) GetAllImprests(ctx context.Context) ([]models.Imprest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	imprests := where(s.imprests, func(models.Imprest) bool { return true })
+	sortBy(imprests, func(a, b models.Imprest) int { return compare(a.Name, b.Name) })
+	return imprests, nil
+}
+
+func (s *Store) CreatePettyCashVoucher(ctx context.Context, voucher models.PettyCashVoucher) (models.PettyCashVoucher, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := len(where(s.pettyCashVouchers, func(v models.PettyCashVoucher) bool { return v.ImprestID == voucher.ImprestID }))
+
+	voucher.ID = uuid.New().String()
+	voucher.VoucherNumber = fmt.Sprintf("PCV-%05d", count+1)
+	voucher.CreatedAt = time.Now()
+	voucher.UpdatedAt = time.Now()
+
+	if find(s.pettyCashVouchers, func(v models.PettyCashVoucher) bool {
+		return v.ImprestID == voucher.ImprestID && v.VoucherNumber == voucher.VoucherNumber
+	}) >= 0 {
+		return models.PettyCashVoucher{}, uniqueViolation("petty_cash_vouchers_imprest_id_voucher_number_key", "imprest_id, voucher_number", voucher.ImprestID, voucher.VoucherNumber)
+	}
+	s.pettyCashVouchers = append(s.pettyCashVouchers, voucher)
+	return voucher, nil
+}
+
+func (s *Store) UpdatePettyCashVoucher(ctx context.Context, voucher models.PettyCashVoucher) (models.PettyCashVoucher, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	voucher.UpdatedAt = time.Now()
+
+	if i := find(s.pettyCashVouchers, func(v models.PettyCashVoucher) bool { return v.ID == voucher.ID }); i >= 0 {
+		row := &s.pettyCashVouchers[i]
+		row.Status = voucher.Status
+		row.ReplenishmentID = voucher.ReplenishmentID
+		row.ExpenseTransactionID = voucher.ExpenseTransactionID
+		row.UpdatedAt = voucher.UpdatedAt
+	}
+	return voucher, nil
+}
+
+func (s *Store) DeletePettyCashVoucher(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pettyCashVouchers = remove(s.pettyCashVouchers, func(v models.PettyCashVoucher) bool { return v.ID == id })
+	return nil
+}
+
+func (s *Store) GetPettyCashVoucher(ctx context.Context, id string) (models.PettyCashVoucher, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.pettyCashVouchers, func(v models.PettyCashVoucher) bool { return v.ID == id })
+}
+
+func (s *Store) GetPettyCashVouchersByImprest(ctx context.Context, imprestID string) ([]models.PettyCashVoucher, error) {
+	vouchers := s.pettyCashVouchersWhere(func(v models.PettyCashVoucher) bool { return v.ImprestID == imprestID })
+	sortBy(vouchers, func(a, b models.PettyCashVoucher) int { return comparePettyCashVouchers(b, a) })
+	return vouchers, nil
+}
+
+func (s *Store) GetPettyCashVouchersByStatus(ctx context.Context, imprestID, status string) ([]models.PettyCashVoucher, error) {
+	return s.pettyCashVouchersWhere(func(v models.PettyCashVoucher) bool { return v.ImprestID == imprestID && v.Status == status }), nil
+}
+
+func (s *Store) GetPettyCashVouchersByReplenishment(ctx context.Context, replenishmentID string) ([]models.PettyCashVoucher, error) {
+	return s.pettyCashVouchersWhere(func(v models.PettyCashVoucher) bool { return is(v.ReplenishmentID, replenishmentID) }), nil
+}
+
+// pettyCashVouchersWhere returns the matching vouchers in date and number order
+func (s *Store) pettyCashVouchersWhere(match func(models.PettyCashVoucher) bool) []models.PettyCashVoucher {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vouchers := where(s.pettyCashVouchers, match)
+	sortBy(vouchers, comparePettyCashVouchers)
+	return vouchers
+}
+
+// comparePettyCashVouchers orders vouchers by date, then number
+func comparePettyCashVouchers(a, b models.PettyCashVoucher) int {
+	if c := compareTime(a.VoucherDate, b.VoucherDate); c != 0 {
+		return c
+	}
+	return compare(a.VoucherNumber, b.VoucherNumber)
+}
+
+func (s *Store) GetTotalPettyCashVouchersByStatus(ctx context.Context, imprestID, status string) (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vouchers := where(s.pettyCashVouchers, func(v models.PettyCashVoucher) bool { return v.ImprestID == imprestID && v.Status == status })
+	return sum(vouchers, func(v models.PettyCashVoucher) float64 { return v.Amount }), nil
+}
+
+func (s *Store) ClaimPettyCashVouchers(ctx context.Context, imprestID, replenishmentID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.pettyCashVouchers {
+		row := &s.pettyCashVouchers[i]
+		if row.ImprestID == imprestID && row.Status == string(models.PettyCashOpen) {
+			row.Status = string(models.PettyCashClaimed)
+			row.ReplenishmentID = &replenishmentID
+			row.UpdatedAt = time.Now()
+		}
+	}
+	return nil
+}
+
+func (s *Store) ReleasePettyCashVouchers(ctx context.Context, replenishmentID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.pettyCashVouchers {
+		row := &s.pettyCashVouchers[i]
+		if is(row.ReplenishmentID, replenishmentID) && row.Status == string(models.PettyCashClaimed) {
+			row.Status = string(models.PettyCashOpen)
+			row.ReplenishmentID = nil
+			row.UpdatedAt = time.Now()
+		}
+	}
+	return nil
+}
+
+func (s *Store) CreateImprestReplenishment(ctx context.Context, replenishment models.ImprestReplenishment) (models.ImprestReplenishment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	replenishment.ID = uuid.New().String()
+	replenishment.CreatedAt = time.Now()
+	replenishment.UpdatedAt = time.Now()
+
+	s.imprestReplenishments = append(s.imprestReplenishments, replenishment)
+	return replenishment, nil
+}
+
+func (s *Store) UpdateImprestReplenishment(ctx context.Context, replenishment models.ImprestReplenishment) (models.ImprestReplenishment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	replenishment.UpdatedAt = time.Now()
+
+	if i := find(s.imprestReplenishments, func(r models.ImprestReplenishment) bool { return r.ID == replenishment.ID }); i >= 0 {
+		row := &s.imprestReplenishments[i]
+		row.Amount = replenishment.Amount
+		row.Status = replenishment.Status
+		row.TransferTransactionID = replenishment.TransferTransactionID
+		row.Notes = replenishment.Notes
+		row.ReviewedBy = replenishment.ReviewedBy
+		row.ReviewedAt = replenishment.ReviewedAt
+		row.UpdatedAt = replenishment.UpdatedAt
+	}
+	return replenishment, nil
+}
+
+// PostImprestReplenishment writes an approved replenishment's transactions, their approvals
+// and lines, marks its vouchers replenished and the replenishment posted, all together
+func (s *Store) PostImprestReplenishment(ctx context.Context, posting models.ReplenishmentPosting) (models.ImprestReplenishment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := find(s.imprestReplenishments, func(r models.ImprestReplenishment) bool { return r.ID == posting.Replenishment.ID })
+	if i < 0 {
+		return models.ImprestReplenishment{}, sql.ErrNoRows
+	}
+
+	now := time.Now()
+
+	var transactions []models.Transaction
+	var approvals []models.VoucherApproval
+	addTransaction := func(txn models.Transaction) string {
+		txn.ID = uuid.New().String()
+		if txn.RequiredRoles == nil {
+			txn.RequiredRoles = pq.StringArray{}
+		}
+		txn.CreatedAt = now
+		txn.UpdatedAt = now
+		transactions = append(transactions, txn)
+
+		approval := posting.Approval
+		approval.ID = uuid.New().String()
+		approval.TransactionID = txn.ID
+		approval.CreatedAt = now
+		approvals = append(approvals, approval)
+		return txn.ID
+	}
+
+	var expenditures []models.Expenditure
+	replenished := map[string]string{}
+	for _, expense := range posting.Expenses {
+		txnID := addTransaction(expense.Transaction)
+		for _, exp := range expense.Expenditures {
+			exp.ID = uuid.New().String()
+			exp.TransactionID = txnID
+			exp.CreatedAt = now
+			exp.UpdatedAt = now
+			expenditures = append(expenditures, exp)
+		}
+		for _, voucher := range expense.Vouchers {
+			replenished[voucher.ID] = txnID
+		}
+	}
+
+	topUpID := addTransaction(posting.TopUp)
+	line := posting.TopUpLine
+	line.ID = uuid.New().String()
+	line.TransactionID = topUpID
+	line.CreatedAt = now
+	line.UpdatedAt = now
+
+	s.transactions = append(s.transactions, transactions...)
+	s.voucherApprovals = append(s.voucherApprovals, approvals...)
+	s.expenditures = append(s.expenditures, expenditures...)
+	s.transfers = append(s.transfers, line)
+	for j := range s.pettyCashVouchers {
+		row := &s.pettyCashVouchers[j]
+		if txnID, ok := replenished[row.ID]; ok {
+			row.Status = string(models.PettyCashReplenished)
+			row.ExpenseTransactionID = &txnID
+			row.UpdatedAt = now
+		}
+	}
+
+	replenishment := posting.Replenishment
+	replenishment.Status = string(models.ReplenishmentPosted)
+	replenishment.TransferTransactionID = &topUpID
+	replenishment.UpdatedAt = now
+	row := &s.imprestReplenishments[i]
+	row.Amount = replenishment.Amount
+	row.Status = replenishment.Status
+	row.TransferTransactionID = replenishment.TransferTransactionID
+	row.Notes = replenishment.Notes
+	row.ReviewedBy = replenishment.ReviewedBy
+	row.ReviewedAt = replenishment.ReviewedAt
+	row.UpdatedAt = replenishment.UpdatedAt
+	return replenishment, nil
+}
+
+func (s *Store) GetImprestReplenishment(ctx context.Context, id string) (models.ImprestReplenishment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.imprestReplenishments, func(r models.ImprestReplenishment) bool { return r.ID == id })
+}
+
+func (s *Store) GetImprestReplenishments(ctx context.Context, imprestID string) ([]models.ImprestReplenishment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	replenishments := where(s.imprestReplenishments, func(r models.ImprestReplenishment) bool { return r.ImprestID == imprestID })
+	sortBy(replenishments, func(a, b models.ImprestReplenishment) int { return compareTime(b.CreatedAt, a.CreatedAt) })
+	return replenishments, nil
+}
+
+func (s *Store) GetPendingImprestReplenishment(ctx context.Context, imprestID string) (models.ImprestReplenishment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.imprestReplenishments, func(r models.ImprestReplenishment) bool {
+		return r.ImprestID == imprestID && r.Status == string(models.ReplenishmentPending)
+	})
+}
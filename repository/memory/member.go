@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) CreateMember(ctx context.Context, member models.Member) (models.Member, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	member.ID = uuid.New().String()
+	member.CreatedAt = time.Now()
+	member.UpdatedAt = time.Now()
+
+	s.members = append(s.members, member)
+	return member, nil
+}
+
+func (s *Store) UpdateMember(ctx context.Context, member models.Member) (models.Member, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	member.UpdatedAt = time.Now()
+
+	if i := find(s.members, func(m models.Member) bool { return m.ID == member.ID }); i >= 0 {
+		row := &s.members[i]
+		row.FullName = member.FullName
+		row.PhoneNumber = member.PhoneNumber
+		row.Email = member.Email
+		row.Notes = member.Notes
+		row.GroupID = member.GroupID
+		row.UpdatedAt = member.UpdatedAt
+	}
+	return member, nil
+}
+
+func (s *Store) DeleteMember(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if find(s.transactions, func(t models.Transaction) bool { return is(t.MemberID, id) }) >= 0 {
+		return stillReferenced("members", "id", id, "transactions")
+	}
+	if find(s.pledges, func(p models.Pledge) bool { return p.MemberID == id }) >= 0 {
+		return stillReferenced("members", "id", id, "pledges")
+	}
+
+	s.members = remove(s.members, func(m models.Member) bool { return m.ID == id })
+	s.smsNotifications = remove(s.smsNotifications, func(n models.SMSNotification) bool { return is(n.MemberID, id) })
+	for i := range s.emails {
+		if is(s.emails[i].MemberID, id) {
+			s.emails[i].MemberID = nil
+		}
+	}
+	return nil
+}
+
+func (s *Store) SetMemberSMSOptOut(ctx context.Context, memberID string, optOut bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i := find(s.members, func(m models.Member) bool { return m.ID == memberID }); i >= 0 {
+		s.members[i].SMSOptOut = optOut
+		s.members[i].UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+func (s *Store) GetMember(ctx context.Context, id string) (models.Member, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.members, func(m models.Member) bool { return m.ID == id })
+}
+
+func (s *Store) GetMemberByPhone(ctx context.Context, phoneNumber string) (models.Member, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.members, func(m models.Member) bool { return m.PhoneNumber == phoneNumber })
+}
+
+func (s *Store) GetMemberByEmail(ctx context.Context, email string) (models.Member, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.members, func(m models.Member) bool { return is(m.Email, email) })
+}
+
+func (s *Store) GetMemberByGroup(ctx context.Context, groupID string) ([]models.Member, error) {
+	return s.membersWhere(func(m models.Member) bool { return is(m.GroupID, groupID) }), nil
+}
+
+func (s *Store) GetAllMembers(ctx context.Context) ([]models.Member, error) {
+	return s.membersWhere(func(models.Member) bool { return true }), nil
+}
+
+func (s *Store) SearchMembers(ctx context.Context, searchTerm string) ([]models.Member, error) {
+	return s.membersWhere(func(m models.Member) bool {
+		return contains(m.FullName, searchTerm) || contains(m.PhoneNumber, searchTerm) || containsPtr(m.Email, searchTerm)
+	}), nil
+}
+
+// membersWhere returns the matching members by name
+func (s *Store) membersWhere(match func(models.Member) bool) []models.Member {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	members := where(s.members, match)
+	sortBy(members, func(a, b models.Member) int { return compare(a.FullName, b.FullName) })
+	return members
+}
@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) CreateGroup(ctx context.Context, group models.MembersGroup) (models.MembersGroup, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	group.ID = uuid.New().String()
+	group.CreatedAt = time.Now()
+	group.UpdatedAt = time.Now()
+
+	s.groups = append(s.groups, group)
+	return group, nil
+}
+
+func (s *Store) UpdateGroup(ctx context.Context, group models.MembersGroup) (models.MembersGroup, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	group.UpdatedAt = time.Now()
+
+	if i := find(s.groups, func(g models.MembersGroup) bool { return g.ID == group.ID }); i >= 0 {
+		row := &s.groups[i]
+		row.GroupName = group.GroupName
+		row.Notes = group.Notes
+		row.UpdatedAt = group.UpdatedAt
+	}
+	return group, nil
+}
+
+func (s *Store) DeleteGroup(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if find(s.members, func(m models.Member) bool { return is(m.GroupID, id) }) >= 0 {
+		return stillReferenced("members_groups", "id", id, "members")
+	}
+
+	s.groups = remove(s.groups, func(g models.MembersGroup) bool { return g.ID == id })
+	return nil
+}
+
+func (s *Store) GetGroup(ctx context.Context, id string) (models.MembersGroup, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.groups, func(g models.MembersGroup) bool { return g.ID == id })
+}
+
+func (s *Store) GetGroupByName(ctx context.Context, name string) (models.MembersGroup, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.groups, func(g models.MembersGroup) bool { return g.GroupName == name })
+}
+
+func (s *Store) GetAllGroups(ctx context.Context) ([]models.MembersGroup, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	groups := where(s.groups, func(models.MembersGroup) bool { return true })
+	sortBy(groups, func(a, b models.MembersGroup) int { return compare(a.GroupName, b.GroupName) })
+	return groups, nil
+}
+
+func (s *Store) GetGroupsWithMemberCount(ctx context.Context) ([]map[string]interface{}, error) {
+	groups, err := s.GetAllGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var results []map[string]interface{}
+	for _, g := range groups {
+		var notes interface{}
+		if g.Notes != nil {
+			notes = *g.Notes
+		}
+		members := where(s.members, func(m models.Member) bool { return is(m.GroupID, g.ID) })
+		results = append(results, map[string]interface{}{
+			"id":           g.ID,
+			"group_name":   g.GroupName,
+			"notes":        notes,
+			"created_by":   g.CreatedBy,
+			"created_at":   g.CreatedAt,
+			"updated_at":   g.UpdatedAt,
+			"member_count": int64(len(members)),
+		})
+	}
+	return results, nil
+}
@@ -0,0 +1,166 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// CreateMobileMoneyImport writes an imported statement and its lines together
+func (s *Store) CreateMobileMoneyImport(ctx context.Context, imp models.MobileMoneyImport, lines []models.MobileMoneyLine) (models.MobileMoneyImport, []models.MobileMoneyLine, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	imp.ID = uuid.New().String()
+	imp.CreatedAt = now
+	imp.UpdatedAt = now
+
+	for i := range lines {
+		line := lines[i]
+		if find(s.mobileMoneyLines, func(l models.MobileMoneyLine) bool {
+			return l.Provider == line.Provider && l.TransCode == line.TransCode
+		}) >= 0 || find(lines[:i], func(l models.MobileMoneyLine) bool {
+			return l.Provider == line.Provider && l.TransCode == line.TransCode
+		}) >= 0 {
+			return models.MobileMoneyImport{}, nil, uniqueViolation("mobile_money_lines_provider_trans_code_key", "provider, trans_code", line.Provider, line.TransCode)
+		}
+		lines[i].ID = uuid.New().String()
+		lines[i].ImportID = imp.ID
+		lines[i].CreatedAt = now
+		lines[i].UpdatedAt = now
+	}
+
+	s.mobileMoneyImports = append(s.mobileMoneyImports, imp)
+	s.mobileMoneyLines = append(s.mobileMoneyLines, lines...)
+	return imp, lines, nil
+}
+
+func (s *Store) GetMobileMoneyImport(ctx context.Context, id string) (models.MobileMoneyImport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.mobileMoneyImports, func(i models.MobileMoneyImport) bool { return i.ID == id })
+}
+
+func (s *Store) GetAllMobileMoneyImports(ctx context.Context) ([]models.MobileMoneyImport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	imports := where(s.mobileMoneyImports, func(models.MobileMoneyImport) bool { return true })
+	sortBy(imports, func(a, b models.MobileMoneyImport) int { return compareTime(b.CreatedAt, a.CreatedAt) })
+	return imports, nil
+}
+
+func (s *Store) DeleteMobileMoneyImport(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.mobileMoneyImports = remove(s.mobileMoneyImports, func(i models.MobileMoneyImport) bool { return i.ID == id })
+	s.mobileMoneyLines = remove(s.mobileMoneyLines, func(l models.MobileMoneyLine) bool { return l.ImportID == id })
+	return nil
+}
+
+func (s *Store) UpdateMobileMoneyLine(ctx context.Context, line models.MobileMoneyLine) (models.MobileMoneyLine, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	line.UpdatedAt = time.Now()
+
+	if i := find(s.mobileMoneyLines, func(l models.MobileMoneyLine) bool { return l.ID == line.ID }); i >= 0 {
+		row := &s.mobileMoneyLines[i]
+		row.MemberID = line.MemberID
+		row.IncomeAccountID = line.IncomeAccountID
+		row.Status = line.Status
+		row.TransactionID = line.TransactionID
+		row.Notes = line.Notes
+		row.ReviewedBy = line.ReviewedBy
+		row.ReviewedAt = line.ReviewedAt
+		row.UpdatedAt = line.UpdatedAt
+	}
+	return line, nil
+}
+
+// PostMobileMoneyLine writes a line's receipts transaction and its receipt line and marks
+// the line posted against them together
+func (s *Store) PostMobileMoneyLine(ctx context.Context, line models.MobileMoneyLine, txn models.Transaction, receipt models.Receipt) (models.MobileMoneyLine, models.Receipt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := find(s.mobileMoneyLines, func(l models.MobileMoneyLine) bool { return l.ID == line.ID })
+	if i < 0 {
+		return models.MobileMoneyLine{}, models.Receipt{}, sql.ErrNoRows
+	}
+
+	now := time.Now()
+
+	txn.ID = uuid.New().String()
+	if txn.TransactionDate.IsZero() {
+		txn.TransactionDate = now
+	}
+	txn.Status = string(models.TransactionPosted)
+	if txn.RequiredRoles == nil {
+		txn.RequiredRoles = pq.StringArray{}
+	}
+	txn.CreatedAt = now
+	txn.UpdatedAt = now
+
+	receipt.ID = uuid.New().String()
+	receipt.TransactionID = txn.ID
+	receipt.CreatedAt = now
+	receipt.UpdatedAt = now
+
+	line.TransactionID = &txn.ID
+	line.Status = string(models.LinePosted)
+	line.UpdatedAt = now
+
+	s.transactions = append(s.transactions, txn)
+	s.receipts = append(s.receipts, receipt)
+	row := &s.mobileMoneyLines[i]
+	row.MemberID = line.MemberID
+	row.IncomeAccountID = line.IncomeAccountID
+	row.Status = line.Status
+	row.TransactionID = line.TransactionID
+	row.Notes = line.Notes
+	row.ReviewedBy = line.ReviewedBy
+	row.ReviewedAt = line.ReviewedAt
+	row.UpdatedAt = line.UpdatedAt
+	return line, receipt, nil
+}
+
+func (s *Store) GetMobileMoneyLine(ctx context.Context, id string) (models.MobileMoneyLine, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.mobileMoneyLines, func(l models.MobileMoneyLine) bool { return l.ID == id })
+}
+
+func (s *Store) GetMobileMoneyLineByCode(ctx context.Context, provider, transCode string) (models.MobileMoneyLine, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.mobileMoneyLines, func(l models.MobileMoneyLine) bool { return l.Provider == provider && l.TransCode == transCode })
+}
+
+func (s *Store) GetMobileMoneyLinesByImport(ctx context.Context, importID string) ([]models.MobileMoneyLine, error) {
+	return s.mobileMoneyLinesWhere(func(l models.MobileMoneyLine) bool { return l.ImportID == importID }), nil
+}
+
+func (s *Store) GetMobileMoneyLinesByStatus(ctx context.Context, status string) ([]models.MobileMoneyLine, error) {
+	return s.mobileMoneyLinesWhere(func(l models.MobileMoneyLine) bool { return l.Status == status }), nil
+}
+
+// mobileMoneyLinesWhere returns the matching lines in the order they were paid
+func (s *Store) mobileMoneyLinesWhere(match func(models.MobileMoneyLine) bool) []models.MobileMoneyLine {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lines := where(s.mobileMoneyLines, match)
+	sortBy(lines, func(a, b models.MobileMoneyLine) int { return compareTime(a.TransTime, b.TransTime) })
+	return lines
+}
@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func (s *Store) CreateMpesaTransaction(ctx context.Context, m models.MpesaTransaction) (models.MpesaTransaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m.ID = uuid.New().String()
+	m.CreatedAt = time.Now()
+	m.UpdatedAt = time.Now()
+
+	if find(s.mpesaTransactions, func(t models.MpesaTransaction) bool { return t.TransID == m.TransID }) >= 0 {
+		return models.MpesaTransaction{}, uniqueViolation("mpesa_transactions_trans_id_key", "trans_id", m.TransID)
+	}
+	s.mpesaTransactions = append(s.mpesaTransactions, m)
+	return m, nil
+}
+
+func (s *Store) UpdateMpesaTransaction(ctx context.Context, m models.MpesaTransaction) (models.MpesaTransaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m.UpdatedAt = time.Now()
+
+	if i := find(s.mpesaTransactions, func(t models.MpesaTransaction) bool { return t.ID == m.ID }); i >= 0 {
+		row := &s.mpesaTransactions[i]
+		row.MemberID = m.MemberID
+		row.IncomeAccountID = m.IncomeAccountID
+		row.TransactionID = m.TransactionID
+		row.Status = m.Status
+		row.Error = m.Error
+		row.UpdatedAt = m.UpdatedAt
+	}
+	return m, nil
+}
+
+// PostMpesaPayment writes a payment's receipts transaction and its receipt line and marks
+// the payment posted against them together
+func (s *Store) PostMpesaPayment(ctx context.Context, m models.MpesaTransaction, txn models.Transaction, receipt models.Receipt) (models.MpesaTransaction, models.Receipt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := find(s.mpesaTransactions, func(t models.MpesaTransaction) bool { return t.ID == m.ID })
+	if i < 0 {
+		return models.MpesaTransaction{}, models.Receipt{}, sql.ErrNoRows
+	}
+
+	now := time.Now()
+
+	txn.ID = uuid.New().String()
+	if txn.TransactionDate.IsZero() {
+		txn.TransactionDate = now
+	}
+	txn.Status = string(models.TransactionPosted)
+	if txn.RequiredRoles == nil {
+		txn.RequiredRoles = pq.StringArray{}
+	}
+	txn.CreatedAt = now
+	txn.UpdatedAt = now
+
+	receipt.ID = uuid.New().String()
+	receipt.TransactionID = txn.ID
+	receipt.CreatedAt = now
+	receipt.UpdatedAt = now
+
+	m.TransactionID = &txn.ID
+	m.Status = string(models.MpesaPosted)
+	m.Error = nil
+	m.UpdatedAt = now
+
+	s.transactions = append(s.transactions, txn)
+	s.receipts = append(s.receipts, receipt)
+	row := &s.mpesaTransactions[i]
+	row.MemberID = m.MemberID
+	row.IncomeAccountID = m.IncomeAccountID
+	row.TransactionID = m.TransactionID
+	row.Status = m.Status
+	row.Error = m.Error
+	row.UpdatedAt = m.UpdatedAt
+	return m, receipt, nil
+}
+
+func (s *Store) GetMpesaTransaction(ctx context.Context, id string) (models.MpesaTransaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.mpesaTransactions, func(t models.MpesaTransaction) bool { return t.ID == id })
+}
+
+func (s *Store) GetMpesaTransactionByTransID(ctx context.Context, transID string) (models.MpesaTransaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.mpesaTransactions, func(t models.MpesaTransaction) bool { return t.TransID == transID })
+}
+
+func (s *Store) GetMpesaTransactionsByStatus(ctx context.Context, status string) ([]models.MpesaTransaction, error) {
+	return s.mpesaTransactionsWhere(func(t models.MpesaTransaction) bool { return t.Status == status }), nil
+}
+
+func (s *Store) GetAllMpesaTransactions(ctx context.Context) ([]models.MpesaTransaction, error) {
+	return s.mpesaTransactionsWhere(func(models.MpesaTransaction) bool { return true }), nil
+}
+
+// mpesaTransactionsWhere returns the matching payments, latest first
+func (s *Store) mpesaTransactionsWhere(match func(models.MpesaTransaction) bool) []models.MpesaTransaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	txns := where(s.mpesaTransactions, match)
+	sortBy(txns, func(a, b models.MpesaTransaction) int { return compareTime(b.TransTime, a.TransTime) })
+	return txns
+}
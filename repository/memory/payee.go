@@ -0,0 +1,240 @@
+package memory
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) CreatePayee(ctx context.Context, payee models.Payee) (models.Payee, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payee.ID = uuid.New().String()
+	payee.CreatedAt = time.Now()
+	payee.UpdatedAt = time.Now()
+
+	if err := s.checkKRAPin(payee); err != nil {
+		return models.Payee{}, err
+	}
+	s.payees = append(s.payees, payee)
+	return payee, nil
+}
+
+func (s *Store) UpdatePayee(ctx context.Context, payee models.Payee) (models.Payee, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payee.UpdatedAt = time.Now()
+
+	if err := s.checkKRAPin(payee); err != nil {
+		return models.Payee{}, err
+	}
+	if i := find(s.payees, func(p models.Payee) bool { return p.ID == payee.ID }); i >= 0 {
+		row := &s.payees[i]
+		row.Name = payee.Name
+		row.PhoneNumber = payee.PhoneNumber
+		row.Email = payee.Email
+		row.KRAPin = payee.KRAPin
+		row.BankName = payee.BankName
+		row.BankBranch = payee.BankBranch
+		row.BankAccountNumber = payee.BankAccountNumber
+		row.MpesaPhone = payee.MpesaPhone
+		row.MpesaPaybill = payee.MpesaPaybill
+		row.MpesaAccount = payee.MpesaAccount
+		row.MpesaTill = payee.MpesaTill
+		row.Notes = payee.Notes
+		row.IsActive = payee.IsActive
+		row.UpdatedAt = payee.UpdatedAt
+	}
+	return payee, nil
+}
+
+// checkKRAPin keeps a KRA PIN to one payee
+func (s *Store) checkKRAPin(payee models.Payee) error {
+	if payee.KRAPin == nil {
+		return nil
+	}
+	if find(s.payees, func(p models.Payee) bool { return p.ID != payee.ID && is(p.KRAPin, *payee.KRAPin) }) >= 0 {
+		return uniqueViolation("idx_payees_kra_pin", "church_id, kra_pin", payee.ChurchID, *payee.KRAPin)
+	}
+	return nil
+}
+
+func (s *Store) DeletePayee(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.payees = remove(s.payees, func(p models.Payee) bool { return p.ID == id })
+	for i := range s.expenditures {
+		if is(s.expenditures[i].PayeeID, id) {
+			s.expenditures[i].PayeeID = nil
+		}
+	}
+	for i := range s.cheques {
+		if is(s.cheques[i].PayeeID, id) {
+			s.cheques[i].PayeeID = nil
+		}
+	}
+	return nil
+}
+
+func (s *Store) GetPayee(ctx context.Context, id string) (models.Payee, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.payees, func(p models.Payee) bool { return p.ID == id })
+}
+
+func (s *Store) GetPayeeByKRAPin(ctx context.Context, kraPin string) (models.Payee, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.payees, func(p models.Payee) bool { return is(p.KRAPin, kraPin) })
+}
+
+func (s *Store) GetAllPayees(ctx context.Context) ([]models.Payee, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payees := where(s.payees, func(models.Payee) bool { return true })
+	sortBy(payees, func(a, b models.Payee) int { return compare(a.Name, b.Name) })
+	return payees, nil
+}
+
+func (s *Store) SearchPayees(ctx context.Context, searchTerm string) ([]models.Payee, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payees := where(s.payees, func(p models.Payee) bool {
+		return contains(p.Name, searchTerm) || containsPtr(p.PhoneNumber, searchTerm) || containsPtr(p.MpesaPhone, searchTerm) ||
+			containsPtr(p.KRAPin, searchTerm) || containsPtr(p.Email, searchTerm)
+	})
+	sortBy(payees, func(a, b models.Payee) int {
+		if a.IsActive != b.IsActive {
+			if a.IsActive {
+				return -1
+			}
+			return 1
+		}
+		return compare(a.Name, b.Name)
+	})
+	return payees, nil
+}
+
+// GetPayeeSpend totals the posted expenditures paid to each payee within the period, largest first
+func (s *Store) GetPayeeSpend(ctx context.Context, startDate, endDate time.Time) ([]models.PayeeSpend, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var spend []models.PayeeSpend
+	for _, p := range s.payees {
+		row := models.PayeeSpend{PayeeID: p.ID, PayeeName: p.Name, KRAPin: p.KRAPin}
+		for _, e := range s.payeePayments(p.ID, startDate, endDate) {
+			t, _ := s.transaction(e.TransactionID)
+			row.Payments++
+			row.Total += e.Amount
+			if row.LastPaidDate == nil || t.TransactionDate.After(*row.LastPaidDate) {
+				paid := t.TransactionDate
+				row.LastPaidDate = &paid
+			}
+		}
+		if row.Payments > 0 {
+			spend = append(spend, row)
+		}
+	}
+	sortBy(spend, func(a, b models.PayeeSpend) int {
+		if c := compare(b.Total, a.Total); c != 0 {
+			return c
+		}
+		return compare(a.PayeeName, b.PayeeName)
+	})
+	return spend, nil
+}
+
+// GetPayeeSpendByAccount totals a payee's posted expenditures per expense account (the
+// transaction's debit account) within the period
+func (s *Store) GetPayeeSpendByAccount(ctx context.Context, payeeID string, startDate, endDate time.Time) ([]models.PayeeAccountSpend, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var spend []models.PayeeAccountSpend
+	index := make(map[string]int)
+	for _, e := range s.payeePayments(payeeID, startDate, endDate) {
+		t, _ := s.transaction(e.TransactionID)
+		i, ok := index[t.DebitAccountID]
+		if !ok {
+			account, err := get(s.accounts, func(a models.Account) bool { return a.ID == t.DebitAccountID })
+			if err != nil {
+				continue
+			}
+			i = len(spend)
+			index[t.DebitAccountID] = i
+			spend = append(spend, models.PayeeAccountSpend{AccountID: account.ID, AccountName: account.AccountName})
+		}
+		spend[i].Payments++
+		spend[i].Total += e.Amount
+	}
+	sortBy(spend, func(a, b models.PayeeAccountSpend) int {
+		if c := compare(b.Total, a.Total); c != 0 {
+			return c
+		}
+		return compare(a.AccountName, b.AccountName)
+	})
+	return spend, nil
+}
+
+// GetPayeePayments lists a payee's posted expenditure lines within the period
+func (s *Store) GetPayeePayments(ctx context.Context, payeeID string, startDate, endDate time.Time) ([]models.PayeePayment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var payments []models.PayeePayment
+	created := make(map[string]time.Time)
+	for _, e := range s.payeePayments(payeeID, startDate, endDate) {
+		t, _ := s.transaction(e.TransactionID)
+		account, err := get(s.accounts, func(a models.Account) bool { return a.ID == t.DebitAccountID })
+		if err != nil {
+			continue
+		}
+		created[e.ID] = e.CreatedAt
+		payments = append(payments, models.PayeePayment{
+			ExpenditureID:   e.ID,
+			TransactionID:   t.ID,
+			TransactionRef:  t.TransactionRef,
+			TransactionDate: t.TransactionDate,
+			AccountID:       account.ID,
+			AccountName:     account.AccountName,
+			Particulars:     e.Particulars,
+			Amount:          e.Amount,
+		})
+	}
+	sortBy(payments, func(a, b models.PayeePayment) int {
+		if c := compareTime(b.TransactionDate, a.TransactionDate); c != 0 {
+			return c
+		}
+		return compareTime(created[b.ExpenditureID], created[a.ExpenditureID])
+	})
+	return payments, nil
+}
+
+// payeePayments returns the posted expenditure lines paid to a payee within the period
+func (s *Store) payeePayments(payeeID string, startDate, endDate time.Time) []models.Expenditure {
+	return where(s.expenditures, func(e models.Expenditure) bool {
+		if !is(e.PayeeID, payeeID) {
+			return false
+		}
+		t, err := s.transaction(e.TransactionID)
+		return err == nil && t.Status == string(models.TransactionPosted) && between(t.TransactionDate, startDate, endDate)
+	})
+}
+
+// CountExpendituresByPayee counts the expenditure lines that reference a payee
+func (s *Store) CountExpendituresByPayee(ctx context.Context, payeeID string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(where(s.expenditures, func(e models.Expenditure) bool { return is(e.PayeeID, payeeID) })), nil
+}
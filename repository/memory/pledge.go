@@ -0,0 +1,191 @@
+package memory
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) CreatePledge(ctx context.Context, pledge models.Pledge) (models.Pledge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pledge.ID = uuid.New().String()
+
+	// Set default start date if not provided
+	if pledge.StartDate.IsZero() {
+		pledge.StartDate = time.Now()
+	}
+
+	pledge.CreatedAt = time.Now()
+	pledge.UpdatedAt = time.Now()
+
+	s.pledges = append(s.pledges, pledge)
+	return pledge, nil
+}
+
+func (s *Store) UpdatePledge(ctx context.Context, pledge models.Pledge) (models.Pledge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pledge.UpdatedAt = time.Now()
+
+	if i := find(s.pledges, func(p models.Pledge) bool { return p.ID == pledge.ID }); i >= 0 {
+		row := &s.pledges[i]
+		row.Amount = pledge.Amount
+		row.Frequency = pledge.Frequency
+		row.Installments = pledge.Installments
+		row.StartDate = pledge.StartDate
+		row.Status = pledge.Status
+		row.Notes = pledge.Notes
+		row.UpdatedAt = pledge.UpdatedAt
+	}
+	return pledge, nil
+}
+
+func (s *Store) UpdatePledgeStatus(ctx context.Context, id string, status string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i := find(s.pledges, func(p models.Pledge) bool { return p.ID == id }); i >= 0 {
+		s.pledges[i].Status = status
+		s.pledges[i].UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+func (s *Store) DeletePledge(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pledges = remove(s.pledges, func(p models.Pledge) bool { return p.ID == id })
+	s.pledgePayments = remove(s.pledgePayments, func(p models.PledgePayment) bool { return p.PledgeID == id })
+	return nil
+}
+
+func (s *Store) GetPledge(ctx context.Context, id string) (models.Pledge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pledge, err := get(s.pledges, func(p models.Pledge) bool { return p.ID == id })
+	if err != nil {
+		return models.Pledge{}, err
+	}
+	return s.withAmountPaid(pledge), nil
+}
+
+func (s *Store) GetPledgesByCampaign(ctx context.Context, campaignID string) ([]models.Pledge, error) {
+	return s.pledgesWhere(func(p models.Pledge) bool { return p.CampaignID == campaignID }), nil
+}
+
+func (s *Store) GetPledgesByMember(ctx context.Context, memberID string) ([]models.Pledge, error) {
+	return s.pledgesWhere(func(p models.Pledge) bool { return p.MemberID == memberID }), nil
+}
+
+// pledgesWhere returns the matching pledges with their amounts paid, newest first
+func (s *Store) pledgesWhere(match func(models.Pledge) bool) []models.Pledge {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pledges := where(s.pledges, match)
+	for i := range pledges {
+		pledges[i] = s.withAmountPaid(pledges[i])
+	}
+	sortBy(pledges, func(a, b models.Pledge) int { return compareTime(b.CreatedAt, a.CreatedAt) })
+	return pledges
+}
+
+// withAmountPaid fills in what has been paid towards a pledge so far
+func (s *Store) withAmountPaid(pledge models.Pledge) models.Pledge {
+	payments := where(s.pledgePayments, func(p models.PledgePayment) bool { return p.PledgeID == pledge.ID })
+	pledge.AmountPaid = sum(payments, func(p models.PledgePayment) float64 { return p.Amount })
+	return pledge
+}
+
+// GetOpenPledgesForReceipt returns a member's open pledges on campaigns that
+// collect into the given income account, oldest first
+func (s *Store) GetOpenPledgesForReceipt(ctx context.Context, memberID, incomeAccountID string) ([]models.Pledge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pledges := where(s.pledges, func(p models.Pledge) bool {
+		campaign, err := get(s.campaigns, func(c models.Campaign) bool { return c.ID == p.CampaignID })
+		return err == nil && p.MemberID == memberID && campaign.IncomeAccountID == incomeAccountID &&
+			p.Status == string(models.PledgeOpen) && campaign.IsActive
+	})
+	for i := range pledges {
+		pledges[i] = s.withAmountPaid(pledges[i])
+	}
+	sortBy(pledges, func(a, b models.Pledge) int {
+		if c := compareTime(a.StartDate, b.StartDate); c != 0 {
+			return c
+		}
+		return compareTime(a.CreatedAt, b.CreatedAt)
+	})
+	return pledges, nil
+}
+
+// GetPledgeDetailsByCampaign returns pledges joined with member and group names
+func (s *Store) GetPledgeDetailsByCampaign(ctx context.Context, campaignID string) ([]models.PledgeDetail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var details []models.PledgeDetail
+	for _, p := range s.pledges {
+		if p.CampaignID != campaignID || p.Status == string(models.PledgeCancelled) {
+			continue
+		}
+		member, err := get(s.members, func(m models.Member) bool { return m.ID == p.MemberID })
+		if err != nil {
+			continue
+		}
+		detail := models.PledgeDetail{Pledge: s.withAmountPaid(p), MemberName: member.FullName, GroupID: member.GroupID}
+		if member.GroupID != nil {
+			if group, err := get(s.groups, func(g models.MembersGroup) bool { return g.ID == *member.GroupID }); err == nil {
+				detail.GroupName = &group.GroupName
+			}
+		}
+		details = append(details, detail)
+	}
+	sortBy(details, func(a, b models.PledgeDetail) int { return compare(a.MemberName, b.MemberName) })
+	return details, nil
+}
+
+func (s *Store) CreatePledgePayment(ctx context.Context, payment models.PledgePayment) (models.PledgePayment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payment.ID = uuid.New().String()
+	payment.CreatedAt = time.Now()
+
+	s.pledgePayments = append(s.pledgePayments, payment)
+	return payment, nil
+}
+
+func (s *Store) GetPledgePaymentsByPledge(ctx context.Context, pledgeID string) ([]models.PledgePayment, error) {
+	return s.pledgePaymentsWhere(func(p models.PledgePayment) bool { return p.PledgeID == pledgeID }), nil
+}
+
+func (s *Store) GetPledgePaymentsByReceipt(ctx context.Context, receiptID string) ([]models.PledgePayment, error) {
+	return s.pledgePaymentsWhere(func(p models.PledgePayment) bool { return p.ReceiptID == receiptID }), nil
+}
+
+// pledgePaymentsWhere returns the matching payments in the order they were made
+func (s *Store) pledgePaymentsWhere(match func(models.PledgePayment) bool) []models.PledgePayment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payments := where(s.pledgePayments, match)
+	sortBy(payments, func(a, b models.PledgePayment) int { return compareTime(a.CreatedAt, b.CreatedAt) })
+	return payments
+}
+
+func (s *Store) DeletePledgePaymentsByReceipt(ctx context.Context, receiptID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pledgePayments = remove(s.pledgePayments, func(p models.PledgePayment) bool { return p.ReceiptID == receiptID })
+	return nil
+}
@@ -0,0 +1,220 @@
+package memory
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) CreateReceipt(ctx context.Context, receipt models.Receipt) (models.Receipt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	receipt.ID = uuid.New().String()
+	receipt.CreatedAt = time.Now()
+	receipt.UpdatedAt = time.Now()
+
+	s.receipts = append(s.receipts, receipt)
+	return receipt, nil
+}
+
+func (s *Store) UpdateReceipt(ctx context.Context, receipt models.Receipt) (models.Receipt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	receipt.UpdatedAt = time.Now()
+
+	if i := find(s.receipts, func(r models.Receipt) bool { return r.ID == receipt.ID }); i >= 0 {
+		row := &s.receipts[i]
+		row.IncomeAccountID = receipt.IncomeAccountID
+		row.FundID = receipt.FundID
+		row.Amount = receipt.Amount
+		row.UpdatedAt = receipt.UpdatedAt
+	}
+	return receipt, nil
+}
+
+func (s *Store) DeleteReceipt(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteReceipts(func(r models.Receipt) bool { return r.ID == id })
+	return nil
+}
+
+// deleteReceipts removes the matching receipts along with the pledge payments they made
+func (s *Store) deleteReceipts(match func(models.Receipt) bool) {
+	ids := make(map[string]bool)
+	for _, r := range where(s.receipts, match) {
+		ids[r.ID] = true
+	}
+
+	s.receipts = remove(s.receipts, match)
+	s.pledgePayments = remove(s.pledgePayments, func(p models.PledgePayment) bool { return ids[p.ReceiptID] })
+}
+
+func (s *Store) GetReceipt(ctx context.Context, id string) (models.Receipt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.receipts, func(r models.Receipt) bool { return r.ID == id })
+}
+
+func (s *Store) GetReceiptByTransaction(ctx context.Context, transactionID string) ([]models.Receipt, error) {
+	return s.receiptsWhere(func(r models.Receipt) bool { return r.TransactionID == transactionID }), nil
+}
+
+func (s *Store) GetReceiptByAccount(ctx context.Context, accountID string) ([]models.Receipt, error) {
+	s.mu.Lock()
+	accounts := set(s.accountSubtree(accountID))
+	s.mu.Unlock()
+
+	return s.receiptsWhere(func(r models.Receipt) bool { return accounts[r.IncomeAccountID] }), nil
+}
+
+func (s *Store) GetReceiptByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.Receipt, error) {
+	return s.receiptsWhere(func(r models.Receipt) bool { return between(r.CreatedAt, startDate, endDate) }), nil
+}
+
+func (s *Store) GetAllReceipts(ctx context.Context) ([]models.Receipt, error) {
+	return s.receiptsWhere(func(models.Receipt) bool { return true }), nil
+}
+
+// receiptsWhere returns the matching receipts, newest first
+func (s *Store) receiptsWhere(match func(models.Receipt) bool) []models.Receipt {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	receipts := where(s.receipts, match)
+	sortBy(receipts, func(a, b models.Receipt) int { return compareTime(b.CreatedAt, a.CreatedAt) })
+	return receipts
+}
+
+func (s *Store) GetTotalReceiptsByAccount(ctx context.Context, accountID string) (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	accounts := set(s.accountSubtree(accountID))
+	receipts := where(s.receipts, func(r models.Receipt) bool { return accounts[r.IncomeAccountID] && s.posted(r.TransactionID) })
+	return sum(receipts, func(r models.Receipt) float64 { return r.Amount }), nil
+}
+
+func (s *Store) GetTotalReceiptsByDateRange(ctx context.Context, accountID string, startDate, endDate time.Time) (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	accounts := set(s.accountSubtree(accountID))
+	receipts := where(s.receipts, func(r models.Receipt) bool {
+		return accounts[r.IncomeAccountID] && s.posted(r.TransactionID) && between(r.CreatedAt, startDate, endDate)
+	})
+	return sum(receipts, func(r models.Receipt) float64 { return r.Amount }), nil
+}
+
+// GetTotalReceiptsByTransactionDate sums posted receipts to an account whose transactions fall within the period
+func (s *Store) GetTotalReceiptsByTransactionDate(ctx context.Context, accountID string, startDate, endDate time.Time) (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	accounts := set(s.accountSubtree(accountID))
+	receipts := where(s.receipts, func(r models.Receipt) bool {
+		t, err := s.transaction(r.TransactionID)
+		return err == nil && accounts[r.IncomeAccountID] && t.Status == string(models.TransactionPosted) && between(t.TransactionDate, startDate, endDate)
+	})
+	return sum(receipts, func(r models.Receipt) float64 { return r.Amount }), nil
+}
+
+// GetMemberGivingLines returns the receipt lines on a member's posted transactions within the period
+func (s *Store) GetMemberGivingLines(ctx context.Context, memberID string, startDate, endDate time.Time) ([]models.MemberGivingLine, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var lines []models.MemberGivingLine
+	for _, r := range s.receipts {
+		t, err := s.transaction(r.TransactionID)
+		if err != nil || !is(t.MemberID, memberID) || t.Status != string(models.TransactionPosted) || !between(t.TransactionDate, startDate, endDate) {
+			continue
+		}
+		account, err := get(s.accounts, func(a models.Account) bool { return a.ID == r.IncomeAccountID })
+		if err != nil {
+			continue
+		}
+		lines = append(lines, models.MemberGivingLine{
+			TransactionID:   t.ID,
+			TransactionRef:  t.TransactionRef,
+			TransactionDate: t.TransactionDate,
+			IncomeAccountID: r.IncomeAccountID,
+			AccountName:     account.AccountName,
+			Amount:          r.Amount,
+		})
+	}
+	sortBy(lines, func(a, b models.MemberGivingLine) int {
+		if c := compareTime(a.TransactionDate, b.TransactionDate); c != 0 {
+			return c
+		}
+		return compare(a.TransactionID, b.TransactionID)
+	})
+	return lines, nil
+}
+
+// GetIncomeLines sums the posted income to each account over the period, with the
+// top-level account each one rolls up to
+func (s *Store) GetIncomeLines(ctx context.Context, startDate, endDate time.Time) ([]models.IncomeLine, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var lines []models.IncomeLine
+	index := make(map[string]int)
+	for _, r := range s.receipts {
+		t, err := s.transaction(r.TransactionID)
+		if err != nil || t.Status != string(models.TransactionPosted) || !between(t.TransactionDate, startDate, endDate) {
+			continue
+		}
+		i, ok := index[r.IncomeAccountID]
+		if !ok {
+			account, err := get(s.accounts, func(a models.Account) bool { return a.ID == r.IncomeAccountID })
+			if err != nil {
+				continue
+			}
+			category := s.accountRoot(account)
+			i = len(lines)
+			index[account.ID] = i
+			lines = append(lines, models.IncomeLine{
+				AccountID:    account.ID,
+				Code:         account.Code,
+				AccountName:  account.AccountName,
+				LocalShare:   account.LocalShare,
+				CategoryID:   category.ID,
+				CategoryCode: category.Code,
+				CategoryName: category.AccountName,
+			})
+		}
+		lines[i].Amount += r.Amount
+	}
+	sortBy(lines, func(a, b models.IncomeLine) int {
+		if c := compareNullsLast(a.CategoryCode, b.CategoryCode); c != 0 {
+			return c
+		}
+		if c := compare(a.CategoryName, b.CategoryName); c != 0 {
+			return c
+		}
+		if c := compareNullsLast(a.Code, b.Code); c != 0 {
+			return c
+		}
+		return compare(a.AccountName, b.AccountName)
+	})
+	return lines, nil
+}
+
+// accountRoot returns the top-level account an account rolls up to
+func (s *Store) accountRoot(account models.Account) models.Account {
+	for account.ParentID != nil {
+		parent, err := get(s.accounts, func(a models.Account) bool { return a.ID == *account.ParentID })
+		if err != nil {
+			break
+		}
+		account = parent
+	}
+	return account
+}
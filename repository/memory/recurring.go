@@ -0,0 +1,171 @@
+package memory
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) CreateRecurringTemplate(ctx context.Context, template models.RecurringTemplate) (models.RecurringTemplate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	template.ID = uuid.New().String()
+	template.CreatedAt = time.Now()
+	template.UpdatedAt = time.Now()
+
+	s.recurringTemplates = append(s.recurringTemplates, template)
+	return template, nil
+}
+
+func (s *Store) UpdateRecurringTemplate(ctx context.Context, template models.RecurringTemplate) (models.RecurringTemplate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	template.UpdatedAt = time.Now()
+
+	if i := find(s.recurringTemplates, func(t models.RecurringTemplate) bool { return t.ID == template.ID }); i >= 0 {
+		row := &s.recurringTemplates[i]
+		row.Name = template.Name
+		row.DebitAccountID = template.DebitAccountID
+		row.MemberID = template.MemberID
+		row.Notes = template.Notes
+		row.Schedule = template.Schedule
+		row.StartDate = template.StartDate
+		row.EndDate = template.EndDate
+		row.PostMode = template.PostMode
+		row.NextRunAt = template.NextRunAt
+		row.LastRunAt = template.LastRunAt
+		row.IsActive = template.IsActive
+		row.UpdatedAt = template.UpdatedAt
+	}
+	return template, nil
+}
+
+func (s *Store) DeleteRecurringTemplate(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.recurringTemplates = remove(s.recurringTemplates, func(t models.RecurringTemplate) bool { return t.ID == id })
+	s.recurringLines = remove(s.recurringLines, func(l models.RecurringTemplateLine) bool { return l.TemplateID == id })
+	s.recurringRuns = remove(s.recurringRuns, func(r models.RecurringRun) bool { return r.TemplateID == id })
+	return nil
+}
+
+func (s *Store) GetRecurringTemplate(ctx context.Context, id string) (models.RecurringTemplate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.recurringTemplates, func(t models.RecurringTemplate) bool { return t.ID == id })
+}
+
+func (s *Store) GetAllRecurringTemplates(ctx context.Context) ([]models.RecurringTemplate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	templates := where(s.recurringTemplates, func(models.RecurringTemplate) bool { return true })
+	sortBy(templates, func(a, b models.RecurringTemplate) int { return compare(a.Name, b.Name) })
+	return templates, nil
+}
+
+// GetDueRecurringTemplates returns the active templates whose next run is at or before the given time
+func (s *Store) GetDueRecurringTemplates(ctx context.Context, now time.Time) ([]models.RecurringTemplate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	templates := where(s.recurringTemplates, func(t models.RecurringTemplate) bool {
+		return t.IsActive && t.NextRunAt != nil && !t.NextRunAt.After(now)
+	})
+	sortBy(templates, func(a, b models.RecurringTemplate) int { return compareTime(*a.NextRunAt, *b.NextRunAt) })
+	return templates, nil
+}
+
+// ReplaceRecurringTemplateLines swaps a template's lines for the given ones
+func (s *Store) ReplaceRecurringTemplateLines(ctx context.Context, templateID string, lines []models.RecurringTemplateLine) ([]models.RecurringTemplateLine, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.recurringLines = remove(s.recurringLines, func(l models.RecurringTemplateLine) bool { return l.TemplateID == templateID })
+
+	for i := range lines {
+		lines[i].ID = uuid.New().String()
+		lines[i].TemplateID = templateID
+		lines[i].CreatedAt = time.Now()
+		lines[i].UpdatedAt = time.Now()
+		s.recurringLines = append(s.recurringLines, lines[i])
+	}
+
+	return lines, nil
+}
+
+func (s *Store) GetRecurringTemplateLines(ctx context.Context, templateID string) ([]models.RecurringTemplateLine, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lines := where(s.recurringLines, func(l models.RecurringTemplateLine) bool { return l.TemplateID == templateID })
+	sortBy(lines, func(a, b models.RecurringTemplateLine) int {
+		if c := compareTime(a.CreatedAt, b.CreatedAt); c != 0 {
+			return c
+		}
+		return compare(a.ID, b.ID)
+	})
+	return lines, nil
+}
+
+// ClaimRecurringRun records a pending run for an occurrence. It reports false when the
+// occurrence already has a run, so the same date is never generated twice.
+func (s *Store) ClaimRecurringRun(ctx context.Context, templateID string, scheduledFor time.Time) (models.RecurringRun, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run := models.RecurringRun{
+		ID:           uuid.New().String(),
+		TemplateID:   templateID,
+		ScheduledFor: scheduledFor,
+		Status:       string(models.RecurringRunPending),
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	if find(s.recurringRuns, func(r models.RecurringRun) bool {
+		return r.TemplateID == templateID && r.ScheduledFor.Equal(scheduledFor)
+	}) >= 0 {
+		return run, false, nil
+	}
+	s.recurringRuns = append(s.recurringRuns, run)
+	return run, true, nil
+}
+
+func (s *Store) UpdateRecurringRun(ctx context.Context, run models.RecurringRun) (models.RecurringRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run.UpdatedAt = time.Now()
+
+	if i := find(s.recurringRuns, func(r models.RecurringRun) bool { return r.ID == run.ID }); i >= 0 {
+		row := &s.recurringRuns[i]
+		row.Status = run.Status
+		row.TransactionID = run.TransactionID
+		row.Error = run.Error
+		row.UpdatedAt = run.UpdatedAt
+	}
+	return run, nil
+}
+
+func (s *Store) GetRecurringRun(ctx context.Context, id string) (models.RecurringRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.recurringRuns, func(r models.RecurringRun) bool { return r.ID == id })
+}
+
+func (s *Store) GetRecurringRunsByTemplate(ctx context.Context, templateID string) ([]models.RecurringRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	runs := where(s.recurringRuns, func(r models.RecurringRun) bool { return r.TemplateID == templateID })
+	sortBy(runs, func(a, b models.RecurringRun) int { return compareTime(b.ScheduledFor, a.ScheduledFor) })
+	return runs, nil
+}
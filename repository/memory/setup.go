@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetSetupStatus counts the users and churches already in the store
+func (s *Store) GetSetupStatus(ctx context.Context) (models.SetupStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := models.SetupStatus{Users: len(s.users), Churches: len(s.churches)}
+	status.Completed = status.Users > 0
+	return status, nil
+}
+
+// SeedChurch creates the first church, its admin, the member groups and the chart of
+// accounts together, keeping the account IDs it is given
+func (s *Store) SeedChurch(ctx context.Context, church models.Church, admin models.User, groups []models.MembersGroup, accounts []models.Account) (models.Church, models.User, []models.MembersGroup, []models.Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	church.ID = uuid.New().String()
+	church.CreatedAt = now
+	church.UpdatedAt = now
+	if err := s.checkChurch(church); err != nil {
+		return models.Church{}, models.User{}, nil, nil, err
+	}
+
+	admin.ID = uuid.New().String()
+	admin.CreatedAt = now
+	admin.UpdatedAt = now
+	admin.CreatedIn = &church.ID
+	if err := s.checkUser(admin); err != nil {
+		return models.Church{}, models.User{}, nil, nil, err
+	}
+
+	for i := range groups {
+		groups[i].ID = uuid.New().String()
+		groups[i].ChurchID = church.ID
+		groups[i].CreatedBy = admin.ID
+		groups[i].CreatedAt = now
+		groups[i].UpdatedAt = now
+	}
+
+	for i := range accounts {
+		accounts[i].ChurchID = church.ID
+		accounts[i].CreatedBy = admin.ID
+		accounts[i].CreatedAt = now
+		accounts[i].UpdatedAt = now
+	}
+
+	s.churches = append(s.churches, church)
+	s.users = append(s.users, admin)
+	s.churchUsers = append(s.churchUsers, models.ChurchUser{
+		ID:        uuid.New().String(),
+		ChurchID:  church.ID,
+		UserID:    admin.ID,
+		Role:      string(models.RoleAdmin),
+		CreatedAt: now,
+	})
+	s.groups = append(s.groups, groups...)
+	s.accounts = append(s.accounts, accounts...)
+
+	return church, admin, groups, accounts, nil
+}
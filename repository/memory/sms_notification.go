@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) CreateSMSNotification(ctx context.Context, n models.SMSNotification) (models.SMSNotification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n.ID = uuid.New().String()
+	n.CreatedAt = time.Now()
+	n.UpdatedAt = time.Now()
+
+	if n.TransactionID != nil && find(s.smsNotifications, func(o models.SMSNotification) bool {
+		return is(o.TransactionID, *n.TransactionID) && o.Kind == n.Kind
+	}) >= 0 {
+		return models.SMSNotification{}, uniqueViolation("idx_sms_notifications_transaction", "transaction_id, kind", *n.TransactionID, n.Kind)
+	}
+	s.smsNotifications = append(s.smsNotifications, n)
+	return n, nil
+}
+
+func (s *Store) UpdateSMSNotification(ctx context.Context, n models.SMSNotification) (models.SMSNotification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n.UpdatedAt = time.Now()
+
+	if i := find(s.smsNotifications, func(o models.SMSNotification) bool { return o.ID == n.ID }); i >= 0 {
+		row := &s.smsNotifications[i]
+		row.Message = n.Message
+		row.Status = n.Status
+		row.Provider = n.Provider
+		row.ProviderMessageID = n.ProviderMessageID
+		row.Attempts = n.Attempts
+		row.LastError = n.LastError
+		row.SendAfter = n.SendAfter
+		row.SentAt = n.SentAt
+		row.DeliveredAt = n.DeliveredAt
+		row.UpdatedAt = n.UpdatedAt
+	}
+	return n, nil
+}
+
+func (s *Store) GetSMSNotification(ctx context.Context, id string) (models.SMSNotification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.smsNotifications, func(n models.SMSNotification) bool { return n.ID == id })
+}
+
+func (s *Store) GetSMSNotificationByTransaction(ctx context.Context, transactionID, kind string) (models.SMSNotification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.smsNotifications, func(n models.SMSNotification) bool { return is(n.TransactionID, transactionID) && n.Kind == kind })
+}
+
+func (s *Store) GetSMSNotificationByProviderID(ctx context.Context, providerMessageID string) (models.SMSNotification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.smsNotifications, func(n models.SMSNotification) bool { return is(n.ProviderMessageID, providerMessageID) })
+}
+
+// GetDueSMSNotifications returns queued notifications whose send time has passed
+func (s *Store) GetDueSMSNotifications(ctx context.Context, asOf time.Time, limit int) ([]models.SMSNotification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	notifications := where(s.smsNotifications, func(n models.SMSNotification) bool {
+		return n.Status == string(models.SMSQueued) && !n.SendAfter.After(asOf)
+	})
+	sortBy(notifications, func(a, b models.SMSNotification) int { return compareTime(a.SendAfter, b.SendAfter) })
+	return limitTo(notifications, limit), nil
+}
+
+func (s *Store) GetSMSNotificationsByStatus(ctx context.Context, status string) ([]models.SMSNotification, error) {
+	return s.smsNotificationsWhere(func(n models.SMSNotification) bool { return n.Status == status }), nil
+}
+
+func (s *Store) GetSMSNotificationsByMember(ctx context.Context, memberID string) ([]models.SMSNotification, error) {
+	return s.smsNotificationsWhere(func(n models.SMSNotification) bool { return is(n.MemberID, memberID) }), nil
+}
+
+func (s *Store) GetAllSMSNotifications(ctx context.Context) ([]models.SMSNotification, error) {
+	return s.smsNotificationsWhere(func(models.SMSNotification) bool { return true }), nil
+}
+
+// smsNotificationsWhere returns the matching notifications, newest first
+func (s *Store) smsNotificationsWhere(match func(models.SMSNotification) bool) []models.SMSNotification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	notifications := where(s.smsNotifications, match)
+	sortBy(notifications, func(a, b models.SMSNotification) int { return compareTime(b.CreatedAt, a.CreatedAt) })
+	return notifications
+}
@@ -0,0 +1,63 @@
+// Package memory keeps a church's records in memory, behaving like the Postgres
+// repository closely enough for the services to be tested without a database: list
+// orderings, unique keys, cascading deletes and foreign keys that refuse a delete all
+// match the schema.
+package memory
+
+import (
+	"storeHouse/models"
+	"storeHouse/repository"
+	"sync"
+)
+
+// Store is an in-memory repository.Store. The zero value is empty and ready to use.
+type Store struct {
+	mu sync.Mutex
+
+	accounts                []models.Account
+	approvalPolicies        []models.ApprovalPolicy
+	attachments             []models.Attachment
+	bankStatements          []models.BankStatement
+	statementLines          []models.BankStatementLine
+	budgets                 []models.Budget
+	campaigns               []models.Campaign
+	cheques                 []models.Cheque
+	churches                []models.Church
+	churchUsers             []models.ChurchUser
+	collectionSessions      []models.CollectionSession
+	collectionDenominations []models.CollectionDenomination
+	collectionSignOffs      []models.CollectionSignOff
+	districts               []models.District
+	districtUsers           []districtUser
+	emails                  []models.Email
+	emailAttachments        []models.EmailAttachment
+	expenditures            []models.Expenditure
+	funds                   []models.Fund
+	imprests                []models.Imprest
+	pettyCashVouchers       []models.PettyCashVoucher
+	imprestReplenishments   []models.ImprestReplenishment
+	members                 []models.Member
+	groups                  []models.MembersGroup
+	mobileMoneyImports      []models.MobileMoneyImport
+	mobileMoneyLines        []models.MobileMoneyLine
+	mpesaTransactions       []models.MpesaTransaction
+	payees                  []models.Payee
+	pledges                 []models.Pledge
+	pledgePayments          []models.PledgePayment
+	receipts                []models.Receipt
+	recurringTemplates      []models.RecurringTemplate
+	recurringLines          []models.RecurringTemplateLine
+	recurringRuns           []models.RecurringRun
+	smsNotifications        []models.SMSNotification
+	transactions            []models.Transaction
+	transfers               []models.Transfer
+	users                   []models.User
+	voucherApprovals        []models.VoucherApproval
+}
+
+// Create a new instance of Store
+func New() *Store {
+	return &Store{}
+}
+
+var _ repository.Store = (*Store)(nil)
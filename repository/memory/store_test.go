@@ -0,0 +1,215 @@
+package memory_test
+
+import (
+	"context"
+	"os"
+	"storeHouse/database"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"storeHouse/repository/memory"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+// The same checks run against the in-memory store and, when TEST_DATABASE_URL names a
+// scratch database, against Postgres, so the fake cannot drift from the real store.
+
+func TestMemoryStore(t *testing.T) {
+	checkStore(t, func(t *testing.T) (repository.Store, string) {
+		store := memory.New()
+		_, admin := seed(t, store)
+		return store, admin
+	})
+}
+
+func TestPostgresStore(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := sqlx.Connect("postgres", url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	driver, err := postgres.WithInstance(db.DB, &postgres.Config{})
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	m, err := migrate.NewWithDatabaseInstance("file://../../database/migrations", "postgres", driver)
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	// Church pools read the connection string from the environment
+	t.Setenv("DATABASE_URL", url)
+	tenants := database.NewTenants(db)
+	t.Cleanup(tenants.Close)
+
+	checkStore(t, func(t *testing.T) (repository.Store, string) {
+		church, admin := seed(t, repository.NewPostgres(db))
+		churchDB, err := tenants.ForChurch(church)
+		if err != nil {
+			t.Fatalf("church connection: %v", err)
+		}
+		return repository.NewPostgres(churchDB), admin
+	})
+}
+
+// seed creates a church with its admin, returning the church and admin IDs
+func seed(t *testing.T, store repository.Store) (string, string) {
+	t.Helper()
+	suffix := strings.ToUpper(uuid.New().String()[:8])
+	church, admin, _, _, err := store.SeedChurch(context.Background(),
+		models.Church{Name: "Test church " + suffix, Code: "T" + suffix, IsActive: true},
+		models.User{Username: "admin-" + suffix, Email: "admin-" + suffix + "@example.com", PasswordHash: "x", FullName: "Admin", Role: string(models.RoleAdmin), IsActive: true},
+		nil, nil)
+	if err != nil {
+		t.Fatalf("seed church: %v", err)
+	}
+	return church.ID, admin.ID
+}
+
+func checkStore(t *testing.T, open func(t *testing.T) (repository.Store, string)) {
+	t.Run("totals count only posted transactions", func(t *testing.T) {
+		store, admin := open(t)
+		b := books{t: t, store: store, admin: admin}
+		bank := b.account("Bank", models.AccountBank, nil, false)
+		income := b.account("Tithe", models.AccountIncome, nil, false)
+		expense := b.account("Utilities", models.AccountExpense, nil, false)
+
+		b.receipt(bank, income, models.TransactionPosted, 1000)
+		b.receipt(bank, income, models.TransactionDraft, 500)
+		b.expense(expense, bank, models.TransactionPosted, 300)
+		b.expense(expense, bank, models.TransactionSubmitted, 200)
+
+		b.wantReceipts(income, 1000)
+		b.wantExpenses(expense, 300)
+	})
+
+	t.Run("totals roll up through the account tree", func(t *testing.T) {
+		store, admin := open(t)
+		b := books{t: t, store: store, admin: admin}
+		bank := b.account("Bank", models.AccountBank, nil, false)
+		offerings := b.account("Offerings", models.AccountIncome, nil, true)
+		tithe := b.account("Tithe", models.AccountIncome, &offerings, false)
+		expenses := b.account("Expenses", models.AccountExpense, nil, true)
+		utilities := b.account("Utilities", models.AccountExpense, &expenses, true)
+		power := b.account("Electricity", models.AccountExpense, &utilities, false)
+		rent := b.account("Rent", models.AccountExpense, &expenses, false)
+
+		b.receipt(bank, tithe, models.TransactionPosted, 1000)
+		b.expense(power, bank, models.TransactionPosted, 300)
+		b.expense(rent, bank, models.TransactionPosted, 700)
+
+		b.wantReceipts(offerings, 1000)
+		b.wantReceipts(tithe, 1000)
+		b.wantExpenses(expenses, 1000)
+		b.wantExpenses(utilities, 300)
+		b.wantExpenses(power, 300)
+		b.wantExpenses(rent, 700)
+	})
+}
+
+// books writes transactions dated mid-March of last year into a store
+type books struct {
+	t     *testing.T
+	store repository.Store
+	admin string
+}
+
+var (
+	bookDate  = time.Date(time.Now().Year()-1, time.March, 15, 0, 0, 0, 0, time.UTC)
+	yearStart = time.Date(bookDate.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	yearEnd   = time.Date(bookDate.Year(), time.December, 31, 0, 0, 0, 0, time.UTC)
+)
+
+func (b books) account(name string, accountType models.AccountType, parent *string, header bool) string {
+	b.t.Helper()
+	acc, err := b.store.CreateAccount(context.Background(), models.Account{
+		AccountName: name,
+		AccountType: string(accountType),
+		ParentID:    parent,
+		IsHeader:    header,
+		IsActive:    true,
+		CreatedBy:   b.admin,
+	})
+	if err != nil {
+		b.t.Fatalf("create account %s: %v", name, err)
+	}
+	return acc.ID
+}
+
+func (b books) transaction(txnType models.TransactionType, debit string, status models.TransactionStatus, amount float64) string {
+	b.t.Helper()
+	txn, err := b.store.CreateTransaction(context.Background(), models.Transaction{
+		TransactionDate: bookDate,
+		TransactionType: string(txnType),
+		Amount:          amount,
+		DebitAccountID:  debit,
+		Status:          string(status),
+		CreatedBy:       b.admin,
+	})
+	if err != nil {
+		b.t.Fatalf("create transaction: %v", err)
+	}
+	return txn.ID
+}
+
+func (b books) receipt(bank, income string, status models.TransactionStatus, amount float64) {
+	b.t.Helper()
+	txn := b.transaction(models.TransactionReceipts, bank, status, amount)
+	_, err := b.store.CreateReceipt(context.Background(), models.Receipt{TransactionID: txn, IncomeAccountID: income, Amount: amount})
+	if err != nil {
+		b.t.Fatalf("create receipt: %v", err)
+	}
+}
+
+func (b books) expense(expense, bank string, status models.TransactionStatus, amount float64) {
+	b.t.Helper()
+	txn := b.transaction(models.TransactionExpenses, expense, status, amount)
+	_, err := b.store.CreateExpenditure(context.Background(), models.Expenditure{TransactionID: txn, Particulars: "Bill", BankAccountID: bank, Amount: amount})
+	if err != nil {
+		b.t.Fatalf("create expenditure: %v", err)
+	}
+}
+
+func (b books) wantReceipts(account string, want float64) {
+	b.t.Helper()
+	ctx := context.Background()
+	total, err := b.store.GetTotalReceiptsByAccount(ctx, account)
+	if err != nil {
+		b.t.Fatalf("receipts total: %v", err)
+	}
+	dated, err := b.store.GetTotalReceiptsByTransactionDate(ctx, account, yearStart, yearEnd)
+	if err != nil {
+		b.t.Fatalf("receipts total by date: %v", err)
+	}
+	if total != want || dated != want {
+		b.t.Errorf("receipts to %s: got %.2f, %.2f by date, want %.2f", account, total, dated, want)
+	}
+}
+
+func (b books) wantExpenses(account string, want float64) {
+	b.t.Helper()
+	total, err := b.store.GetTotalExpendituresByTransactionDate(context.Background(), account, yearStart, yearEnd)
+	if err != nil {
+		b.t.Fatalf("expenditures total: %v", err)
+	}
+	if total != want {
+		b.t.Errorf("expenditures on %s: got %.2f, want %.2f", account, total, want)
+	}
+}
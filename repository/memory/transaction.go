@@ -0,0 +1,246 @@
+package memory
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func (s *Store) CreateTransaction(ctx context.Context, txn models.Transaction) (models.Transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	txn.ID = uuid.New().String()
+
+	// Set default transaction date if not provided
+	if txn.TransactionDate.IsZero() {
+		txn.TransactionDate = time.Now()
+	}
+
+	// Anything not going through the voucher workflow is in the books immediately
+	if txn.Status == "" {
+		txn.Status = string(models.TransactionPosted)
+	}
+	if txn.RequiredRoles == nil {
+		txn.RequiredRoles = pq.StringArray{}
+	}
+
+	txn.CreatedAt = time.Now()
+	txn.UpdatedAt = time.Now()
+
+	s.transactions = append(s.transactions, txn)
+	return txn, nil
+}
+
+func (s *Store) UpdateTransaction(ctx context.Context, txn models.Transaction) (models.Transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	txn.UpdatedAt = time.Now()
+
+	if i := find(s.transactions, func(t models.Transaction) bool { return t.ID == txn.ID }); i >= 0 {
+		row := &s.transactions[i]
+		row.TransactionRef = txn.TransactionRef
+		row.TransactionDate = txn.TransactionDate
+		row.TransactionType = txn.TransactionType
+		row.Amount = txn.Amount
+		row.Notes = txn.Notes
+		row.DebitAccountID = txn.DebitAccountID
+		row.MemberID = txn.MemberID
+		row.PaymentMethod = txn.PaymentMethod
+		row.PaymentReference = txn.PaymentReference
+		row.UpdatedAt = txn.UpdatedAt
+	}
+	return txn, nil
+}
+
+// UpdateTransactionStatus moves a transaction through the approval workflow
+func (s *Store) UpdateTransactionStatus(ctx context.Context, txn models.Transaction) (models.Transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	txn.UpdatedAt = time.Now()
+	if txn.RequiredRoles == nil {
+		txn.RequiredRoles = pq.StringArray{}
+	}
+
+	if i := find(s.transactions, func(t models.Transaction) bool { return t.ID == txn.ID }); i >= 0 {
+		row := &s.transactions[i]
+		row.Status = txn.Status
+		row.SubmittedBy = txn.SubmittedBy
+		row.SubmittedAt = txn.SubmittedAt
+		row.PostedBy = txn.PostedBy
+		row.PostedAt = txn.PostedAt
+		row.RequiredApprovals = txn.RequiredApprovals
+		row.RequiredRoles = txn.RequiredRoles
+		row.UpdatedAt = txn.UpdatedAt
+	}
+	return txn, nil
+}
+
+func (s *Store) DeleteTransaction(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.deleteTransactions(func(t models.Transaction) bool { return t.ID == id })
+}
+
+// deleteTransactions removes the matching transactions as Postgres would: refused while
+// lines still point at them, cascading to their attachments, notifications and approvals,
+// and clearing the links other records keep to them
+func (s *Store) deleteTransactions(match func(models.Transaction) bool) error {
+	ids := make(map[string]bool)
+	for _, t := range where(s.transactions, match) {
+		ids[t.ID] = true
+	}
+
+	for id := range ids {
+		if find(s.receipts, func(r models.Receipt) bool { return r.TransactionID == id }) >= 0 {
+			return stillReferenced("transactions", "id", id, "receipts")
+		}
+		if find(s.expenditures, func(e models.Expenditure) bool { return e.TransactionID == id }) >= 0 {
+			return stillReferenced("transactions", "id", id, "expenditure")
+		}
+		if find(s.transfers, func(t models.Transfer) bool { return t.TransactionID == id }) >= 0 {
+			return stillReferenced("transactions", "id", id, "transfers")
+		}
+	}
+
+	linked := func(id *string) bool { return id != nil && ids[*id] }
+
+	s.transactions = remove(s.transactions, match)
+	s.attachments = remove(s.attachments, func(a models.Attachment) bool { return linked(a.TransactionID) })
+	s.smsNotifications = remove(s.smsNotifications, func(n models.SMSNotification) bool { return linked(n.TransactionID) })
+	s.voucherApprovals = remove(s.voucherApprovals, func(a models.VoucherApproval) bool { return ids[a.TransactionID] })
+
+	for i := range s.statementLines {
+		if linked(s.statementLines[i].TransactionID) {
+			s.statementLines[i].TransactionID = nil
+		}
+	}
+	for i := range s.mpesaTransactions {
+		if linked(s.mpesaTransactions[i].TransactionID) {
+			s.mpesaTransactions[i].TransactionID = nil
+		}
+	}
+	for i := range s.mobileMoneyLines {
+		if linked(s.mobileMoneyLines[i].TransactionID) {
+			s.mobileMoneyLines[i].TransactionID = nil
+		}
+	}
+	for i := range s.cheques {
+		if linked(s.cheques[i].TransactionID) {
+			s.cheques[i].TransactionID = nil
+		}
+	}
+	for i := range s.pettyCashVouchers {
+		if linked(s.pettyCashVouchers[i].ExpenseTransactionID) {
+			s.pettyCashVouchers[i].ExpenseTransactionID = nil
+		}
+	}
+	for i := range s.imprestReplenishments {
+		if linked(s.imprestReplenishments[i].TransferTransactionID) {
+			s.imprestReplenishments[i].TransferTransactionID = nil
+		}
+	}
+	for i := range s.recurringRuns {
+		if linked(s.recurringRuns[i].TransactionID) {
+			s.recurringRuns[i].TransactionID = nil
+		}
+	}
+	return nil
+}
+
+func (s *Store) GetTransaction(ctx context.Context, id string) (models.Transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.transaction(id)
+}
+
+// transaction looks up one transaction
+func (s *Store) transaction(id string) (models.Transaction, error) {
+	return get(s.transactions, func(t models.Transaction) bool { return t.ID == id })
+}
+
+// posted reports whether the transaction is in the books
+func (s *Store) posted(id string) bool {
+	t, err := s.transaction(id)
+	return err == nil && t.Status == string(models.TransactionPosted)
+}
+
+// postedBy returns a transaction when it was posted with a date on or before asOf
+func (s *Store) postedBy(id string, asOf time.Time) (models.Transaction, bool) {
+	t, err := s.transaction(id)
+	if err != nil || t.Status != string(models.TransactionPosted) || t.TransactionDate.After(asOf) {
+		return models.Transaction{}, false
+	}
+	return t, true
+}
+
+func (s *Store) GetTransactionByRef(ctx context.Context, ref string) (models.Transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.transactions, func(t models.Transaction) bool { return is(t.TransactionRef, ref) })
+}
+
+func (s *Store) GetTransactionsByAccount(ctx context.Context, accountID string) ([]models.Transaction, error) {
+	s.mu.Lock()
+	accounts := set(s.accountSubtree(accountID))
+	s.mu.Unlock()
+
+	return s.transactionsWhere(func(t models.Transaction) bool { return accounts[t.DebitAccountID] }), nil
+}
+
+func (s *Store) GetTransactionsByMember(ctx context.Context, memberID string) ([]models.Transaction, error) {
+	return s.transactionsWhere(func(t models.Transaction) bool { return is(t.MemberID, memberID) }), nil
+}
+
+func (s *Store) GetTransactionsByType(ctx context.Context, txnType string) ([]models.Transaction, error) {
+	return s.transactionsWhere(func(t models.Transaction) bool { return t.TransactionType == txnType }), nil
+}
+
+func (s *Store) GetAllTransactions(ctx context.Context) ([]models.Transaction, error) {
+	return s.transactionsWhere(func(models.Transaction) bool { return true }), nil
+}
+
+func (s *Store) GetTransactionsByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.Transaction, error) {
+	return s.transactionsWhere(func(t models.Transaction) bool { return between(t.TransactionDate, startDate, endDate) }), nil
+}
+
+func (s *Store) GetTransactionsByStatus(ctx context.Context, status string) ([]models.Transaction, error) {
+	return s.transactionsWhere(func(t models.Transaction) bool { return t.Status == status }), nil
+}
+
+// GetVouchers returns expense, withdrawal and transfer transactions, newest first
+func (s *Store) GetVouchers(ctx context.Context) ([]models.Transaction, error) {
+	return s.transactionsWhere(func(t models.Transaction) bool { return t.IsVoucher() }), nil
+}
+
+// transactionsWhere returns the matching transactions, latest first
+func (s *Store) transactionsWhere(match func(models.Transaction) bool) []models.Transaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	txns := where(s.transactions, match)
+	sortBy(txns, func(a, b models.Transaction) int { return compareTime(b.TransactionDate, a.TransactionDate) })
+	return txns
+}
+
+// UpdateTransactionPayment records how a transaction was paid, whatever its status
+func (s *Store) UpdateTransactionPayment(ctx context.Context, id string, method, reference *string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i := find(s.transactions, func(t models.Transaction) bool { return t.ID == id }); i >= 0 {
+		row := &s.transactions[i]
+		row.PaymentMethod = method
+		row.PaymentReference = reference
+		row.UpdatedAt = time.Now()
+	}
+	return nil
+}
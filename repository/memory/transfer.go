@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) CreateTransfer(ctx context.Context, transfer models.Transfer) (models.Transfer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	transfer.ID = uuid.New().String()
+	transfer.CreatedAt = time.Now()
+	transfer.UpdatedAt = time.Now()
+
+	s.transfers = append(s.transfers, transfer)
+	return transfer, nil
+}
+
+func (s *Store) UpdateTransfer(ctx context.Context, transfer models.Transfer) (models.Transfer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	transfer.UpdatedAt = time.Now()
+
+	if i := find(s.transfers, func(t models.Transfer) bool { return t.ID == transfer.ID }); i >= 0 {
+		row := &s.transfers[i]
+		row.Particulars = transfer.Particulars
+		row.CreditAccountID = transfer.CreditAccountID
+		row.FundID = transfer.FundID
+		row.Amount = transfer.Amount
+		row.UpdatedAt = transfer.UpdatedAt
+	}
+	return transfer, nil
+}
+
+func (s *Store) DeleteTransfer(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.transfers = remove(s.transfers, func(t models.Transfer) bool { return t.ID == id })
+	s.attachments = remove(s.attachments, func(a models.Attachment) bool { return is(a.TransferID, id) })
+	return nil
+}
+
+func (s *Store) GetTransfer(ctx context.Context, id string) (models.Transfer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.transfers, func(t models.Transfer) bool { return t.ID == id })
+}
+
+func (s *Store) GetTransferByTransaction(ctx context.Context, transactionID string) ([]models.Transfer, error) {
+	return s.transfersWhere(func(t models.Transfer) bool { return t.TransactionID == transactionID }), nil
+}
+
+func (s *Store) GetTransferByCreditAccount(ctx context.Context, accountID string) ([]models.Transfer, error) {
+	s.mu.Lock()
+	accounts := set(s.accountSubtree(accountID))
+	s.mu.Unlock()
+
+	return s.transfersWhere(func(t models.Transfer) bool { return accounts[t.CreditAccountID] }), nil
+}
+
+func (s *Store) GetTransferByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.Transfer, error) {
+	return s.transfersWhere(func(t models.Transfer) bool { return between(t.CreatedAt, startDate, endDate) }), nil
+}
+
+func (s *Store) GetAllTransfers(ctx context.Context) ([]models.Transfer, error) {
+	return s.transfersWhere(func(models.Transfer) bool { return true }), nil
+}
+
+// transfersWhere returns the matching transfer lines, newest first
+func (s *Store) transfersWhere(match func(models.Transfer) bool) []models.Transfer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	transfers := where(s.transfers, match)
+	sortBy(transfers, func(a, b models.Transfer) int { return compareTime(b.CreatedAt, a.CreatedAt) })
+	return transfers
+}
+
+func (s *Store) GetTotalTransfersByCreditAccount(ctx context.Context, accountID string) (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	accounts := set(s.accountSubtree(accountID))
+	transfers := where(s.transfers, func(tr models.Transfer) bool {
+		t, err := s.transaction(tr.TransactionID)
+		return err == nil && accounts[tr.CreditAccountID] && t.Status == string(models.TransactionPosted)
+	})
+	return sum(transfers, func(tr models.Transfer) float64 { return tr.Amount }), nil
+}
+
+func (s *Store) GetTotalTransfersByDateRange(ctx context.Context, accountID string, startDate, endDate time.Time) (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	accounts := set(s.accountSubtree(accountID))
+	transfers := where(s.transfers, func(tr models.Transfer) bool {
+		t, err := s.transaction(tr.TransactionID)
+		return err == nil && accounts[tr.CreditAccountID] && t.Status == string(models.TransactionPosted) &&
+			between(tr.CreatedAt, startDate, endDate)
+	})
+	return sum(transfers, func(tr models.Transfer) float64 { return tr.Amount }), nil
+}
+
+func (s *Store) GetTotalTransfersByTransaction(ctx context.Context, transactionID string) (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	transfers := where(s.transfers, func(tr models.Transfer) bool { return tr.TransactionID == transactionID })
+	return sum(transfers, func(tr models.Transfer) float64 { return tr.Amount }), nil
+}
@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) CreateUser(ctx context.Context, user models.User) (models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user.ID = uuid.New().String()
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = time.Now()
+
+	if err := s.checkUser(user); err != nil {
+		return models.User{}, err
+	}
+	s.users = append(s.users, user)
+	return user, nil
+}
+
+func (s *Store) UpdateUser(ctx context.Context, user models.User) (models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user.UpdatedAt = time.Now()
+
+	if err := s.checkUser(user); err != nil {
+		return models.User{}, err
+	}
+	if i := find(s.users, func(u models.User) bool { return u.ID == user.ID }); i >= 0 {
+		row := &s.users[i]
+		row.Username = user.Username
+		row.Email = user.Email
+		row.FullName = user.FullName
+		row.Role = user.Role
+		row.PhoneNumber = user.PhoneNumber
+		row.IsActive = user.IsActive
+		row.UpdatedAt = user.UpdatedAt
+	}
+	return user, nil
+}
+
+// checkUser keeps usernames and emails to one user each
+func (s *Store) checkUser(user models.User) error {
+	if find(s.users, func(u models.User) bool { return u.ID != user.ID && u.Username == user.Username }) >= 0 {
+		return uniqueViolation("users_username_key", "username", user.Username)
+	}
+	if find(s.users, func(u models.User) bool { return u.ID != user.ID && u.Email == user.Email }) >= 0 {
+		return uniqueViolation("users_email_key", "email", user.Email)
+	}
+	return nil
+}
+
+func (s *Store) DeactivateUser(ctx context.Context, id string) (models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user := models.User{
+		ID:        id,
+		IsActive:  false,
+		UpdatedAt: time.Now(),
+	}
+
+	if i := find(s.users, func(u models.User) bool { return u.ID == id }); i >= 0 {
+		s.users[i].IsActive = user.IsActive
+		s.users[i].UpdatedAt = user.UpdatedAt
+	}
+	return user, nil
+}
+
+func (s *Store) GetUser(ctx context.Context, id string) (models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.users, func(u models.User) bool { return u.ID == id })
+}
+
+func (s *Store) GetUserByUsername(ctx context.Context, username string) (models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.users, func(u models.User) bool { return u.Username == username })
+}
+
+func (s *Store) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return get(s.users, func(u models.User) bool { return u.Email == email })
+}
+
+func (s *Store) UpdateLastLogin(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i := find(s.users, func(u models.User) bool { return u.ID == id }); i >= 0 {
+		now := time.Now()
+		s.users[i].LastLogin = &now
+	}
+	return nil
+}
+
+func (s *Store) UpdateUserPassword(ctx context.Context, id, passwordHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i := find(s.users, func(u models.User) bool { return u.ID == id }); i >= 0 {
+		s.users[i].PasswordHash = passwordHash
+		s.users[i].UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+func (s *Store) DeleteUser(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users = remove(s.users, func(u models.User) bool { return u.ID == id })
+	s.churchUsers = remove(s.churchUsers, func(cu models.ChurchUser) bool { return cu.UserID == id })
+	s.districtUsers = remove(s.districtUsers, func(du districtUser) bool { return du.UserID == id })
+	return nil
+}
+
+func (s *Store) GetAllUsers(ctx context.Context) ([]models.User, error) {
+	return s.usersWhere(func(models.User) bool { return true }), nil
+}
+
+func (s *Store) GetUsersByRole(ctx context.Context, role string) ([]models.User, error) {
+	return s.usersWhere(func(u models.User) bool { return u.Role == role }), nil
+}
+
+func (s *Store) GetActiveUsers(ctx context.Context) ([]models.User, error) {
+	return s.usersWhere(func(u models.User) bool { return u.IsActive }), nil
+}
+
+// usersWhere returns the matching users, newest first
+func (s *Store) usersWhere(match func(models.User) bool) []models.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users := where(s.users, match)
+	sortBy(users, func(a, b models.User) int { return compareTime(b.CreatedAt, a.CreatedAt) })
+	return users
+}
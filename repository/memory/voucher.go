@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) CreateVoucherApproval(ctx context.Context, approval models.VoucherApproval) (models.VoucherApproval, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	approval.ID = uuid.New().String()
+	approval.CreatedAt = time.Now()
+
+	if find(s.voucherApprovals, func(a models.VoucherApproval) bool {
+		return a.TransactionID == approval.TransactionID && a.ApproverID == approval.ApproverID
+	}) >= 0 {
+		return models.VoucherApproval{}, uniqueViolation("voucher_approvals_transaction_id_approver_key", "transaction_id, approver", approval.TransactionID, approval.ApproverID)
+	}
+	s.voucherApprovals = append(s.voucherApprovals, approval)
+	return approval, nil
+}
+
+func (s *Store) GetVoucherApprovals(ctx context.Context, transactionID string) ([]models.VoucherApproval, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	approvals := where(s.voucherApprovals, func(a models.VoucherApproval) bool { return a.TransactionID == transactionID })
+	sortBy(approvals, func(a, b models.VoucherApproval) int { return compareTime(a.CreatedAt, b.CreatedAt) })
+	return approvals, nil
+}
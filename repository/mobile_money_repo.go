@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"storeHouse/database"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (p *Postgres) executeMobileMoneyImportQuery(ctx context.Context, query string, imp models.MobileMoneyImport) (models.MobileMoneyImport, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, imp)
+	if err != nil {
+		return models.MobileMoneyImport{}, err
+	}
+
+	return imp, nil
+}
+
+// CreateMobileMoneyImport writes an imported statement and its lines in one transaction
+func (p *Postgres) CreateMobileMoneyImport(ctx context.Context, imp models.MobileMoneyImport, lines []models.MobileMoneyLine) (models.MobileMoneyImport, []models.MobileMoneyLine, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return models.MobileMoneyImport{}, nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	imp.ID = uuid.New().String()
+	imp.CreatedAt = now
+	imp.UpdatedAt = now
+	importQuery := `INSERT INTO mobile_money_imports (id, provider, file_name, receiving_account, income_account, created_by, created_at, updated_at)
+              VALUES (:id, :provider, :file_name, :receiving_account, :income_account, :created_by, :created_at, :updated_at)`
+	if _, err := tx.NamedExecContext(ctx, importQuery, imp); err != nil {
+		return models.MobileMoneyImport{}, nil, err
+	}
+
+	lineQuery := `INSERT INTO mobile_money_lines (id, import_id, provider, trans_code, trans_time, amount, msisdn, payer_name, account_ref, details, suggested_member, member, income_account, status, transaction_id, notes, created_at, updated_at)
+              VALUES (:id, :import_id, :provider, :trans_code, :trans_time, :amount, :msisdn, :payer_name, :account_ref, :details, :suggested_member, :member, :income_account, :status, :transaction_id, :notes, :created_at, :updated_at)`
+	for i := range lines {
+		lines[i].ID = uuid.New().String()
+		lines[i].ImportID = imp.ID
+		lines[i].CreatedAt = now
+		lines[i].UpdatedAt = now
+		if _, err := tx.NamedExecContext(ctx, lineQuery, lines[i]); err != nil {
+			return models.MobileMoneyImport{}, nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.MobileMoneyImport{}, nil, err
+	}
+
+	return imp, lines, nil
+}
+
+func (p *Postgres) GetMobileMoneyImport(ctx context.Context, id string) (models.MobileMoneyImport, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var imp models.MobileMoneyImport
+	err := p.DB.GetContext(ctx, &imp, "SELECT * FROM mobile_money_imports WHERE id = $1", id)
+	if err != nil {
+		return models.MobileMoneyImport{}, err
+	}
+
+	return imp, nil
+}
+
+func (p *Postgres) GetAllMobileMoneyImports(ctx context.Context) ([]models.MobileMoneyImport, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var imports []models.MobileMoneyImport
+	err := p.DB.SelectContext(ctx, &imports, "SELECT * FROM mobile_money_imports ORDER BY created_at DESC")
+	if err != nil {
+		return nil, err
+	}
+
+	return imports, nil
+}
+
+func (p *Postgres) DeleteMobileMoneyImport(ctx context.Context, id string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM mobile_money_imports WHERE id = $1", id)
+	return err
+}
+
+func (p *Postgres) executeMobileMoneyLineQuery(ctx context.Context, query string, line models.MobileMoneyLine) (models.MobileMoneyLine, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, line)
+	if err != nil {
+		return models.MobileMoneyLine{}, err
+	}
+
+	return line, nil
+}
+
+func (p *Postgres) UpdateMobileMoneyLine(ctx context.Context, line models.MobileMoneyLine) (models.MobileMoneyLine, error) {
+	line.UpdatedAt = time.Now()
+
+	query := `UPDATE mobile_money_lines SET member = :member, income_account = :income_account, status = :status, transaction_id = :transaction_id, notes = :notes, reviewed_by = :reviewed_by, reviewed_at = :reviewed_at, updated_at = :updated_at
+			  WHERE id = :id`
+
+	return p.executeMobileMoneyLineQuery(ctx, query, line)
+}
+
+// PostMobileMoneyLine writes a line's receipts transaction and its receipt line and marks
+// the line posted against them in one transaction
+func (p *Postgres) PostMobileMoneyLine(ctx context.Context, line models.MobileMoneyLine, txn models.Transaction, receipt models.Receipt) (models.MobileMoneyLine, models.Receipt, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return models.MobileMoneyLine{}, models.Receipt{}, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	txn.ID = uuid.New().String()
+	if txn.TransactionDate.IsZero() {
+		txn.TransactionDate = now
+	}
+	txn.Status = string(models.TransactionPosted)
+	txn.CreatedAt = now
+	txn.UpdatedAt = now
+	txnQuery := `INSERT INTO transactions (id, transaction_ref, transaction_date, transaction_type, amount, notes, debit_account, member, payment_method, payment_reference, collection_session, status, created_by, created_at, updated_at)
+              VALUES (:id, :transaction_ref, :transaction_date, :transaction_type, :amount, :notes, :debit_account, :member, :payment_method, :payment_reference, :collection_session, :status, :created_by, :created_at, :updated_at)`
+	if _, err := tx.NamedExecContext(ctx, txnQuery, txn); err != nil {
+		return models.MobileMoneyLine{}, models.Receipt{}, err
+	}
+
+	receipt.ID = uuid.New().String()
+	receipt.TransactionID = txn.ID
+	receipt.CreatedAt = now
+	receipt.UpdatedAt = now
+	receiptQuery := `INSERT INTO receipts (id, transaction_id, income_account, fund, amount, created_at, updated_at)
+              VALUES (:id, :transaction_id, :income_account, :fund, :amount, :created_at, :updated_at)`
+	if _, err := tx.NamedExecContext(ctx, receiptQuery, receipt); err != nil {
+		return models.MobileMoneyLine{}, models.Receipt{}, err
+	}
+
+	line.TransactionID = &txn.ID
+	line.Status = string(models.LinePosted)
+	line.UpdatedAt = now
+	lineQuery := `UPDATE mobile_money_lines SET member = :member, income_account = :income_account, status = :status, transaction_id = :transaction_id, notes = :notes, reviewed_by = :reviewed_by, reviewed_at = :reviewed_at, updated_at = :updated_at
+			  WHERE id = :id`
+	if _, err := tx.NamedExecContext(ctx, lineQuery, line); err != nil {
+		return models.MobileMoneyLine{}, models.Receipt{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.MobileMoneyLine{}, models.Receipt{}, err
+	}
+
+	return line, receipt, nil
+}
+
+func (p *Postgres) GetMobileMoneyLine(ctx context.Context, id string) (models.MobileMoneyLine, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var line models.MobileMoneyLine
+	err := p.DB.GetContext(ctx, &line, "SELECT * FROM mobile_money_lines WHERE id = $1", id)
+	if err != nil {
+		return models.MobileMoneyLine{}, err
+	}
+
+	return line, nil
+}
+
+func (p *Postgres) GetMobileMoneyLineByCode(ctx context.Context, provider, transCode string) (models.MobileMoneyLine, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var line models.MobileMoneyLine
+	err := p.DB.GetContext(ctx, &line, "SELECT * FROM mobile_money_lines WHERE provider = $1 AND trans_code = $2", provider, transCode)
+	if err != nil {
+		return models.MobileMoneyLine{}, err
+	}
+
+	return line, nil
+}
+
+func (p *Postgres) GetMobileMoneyLinesByImport(ctx context.Context, importID string) ([]models.MobileMoneyLine, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var lines []models.MobileMoneyLine
+	err := p.DB.SelectContext(ctx, &lines, "SELECT * FROM mobile_money_lines WHERE import_id = $1 ORDER BY trans_time", importID)
+	if err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+func (p *Postgres) GetMobileMoneyLinesByStatus(ctx context.Context, status string) ([]models.MobileMoneyLine, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var lines []models.MobileMoneyLine
+	err := p.DB.SelectContext(ctx, &lines, "SELECT * FROM mobile_money_lines WHERE status = $1 ORDER BY trans_time", status)
+	if err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
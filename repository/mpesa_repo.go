@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"storeHouse/database"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (p *Postgres) executeMpesaQuery(ctx context.Context, query string, m models.MpesaTransaction) (models.MpesaTransaction, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, m)
+	if err != nil {
+		return models.MpesaTransaction{}, err
+	}
+
+	return m, nil
+}
+
+func (p *Postgres) CreateMpesaTransaction(ctx context.Context, m models.MpesaTransaction) (models.MpesaTransaction, error) {
+	m.ID = uuid.New().String()
+	m.CreatedAt = time.Now()
+	m.UpdatedAt = time.Now()
+
+	query := `INSERT INTO mpesa_transactions (id, trans_id, trans_type, trans_time, amount, business_short_code, bill_ref_number, msisdn, payer_name, member, income_account, transaction_id, status, error, payload, created_at, updated_at)
+              VALUES (:id, :trans_id, :trans_type, :trans_time, :amount, :business_short_code, :bill_ref_number, :msisdn, :payer_name, :member, :income_account, :transaction_id, :status, :error, :payload, :created_at, :updated_at)`
+
+	return p.executeMpesaQuery(ctx, query, m)
+}
+
+func (p *Postgres) UpdateMpesaTransaction(ctx context.Context, m models.MpesaTransaction) (models.MpesaTransaction, error) {
+	m.UpdatedAt = time.Now()
+
+	query := `UPDATE mpesa_transactions SET member = :member, income_account = :income_account, transaction_id = :transaction_id, status = :status, error = :error, updated_at = :updated_at
+			  WHERE id = :id`
+
+	return p.executeMpesaQuery(ctx, query, m)
+}
+
+// PostMpesaPayment writes a payment's receipts transaction and its receipt line and marks
+// the payment posted against them in one transaction
+func (p *Postgres) PostMpesaPayment(ctx context.Context, m models.MpesaTransaction, txn models.Transaction, receipt models.Receipt) (models.MpesaTransaction, models.Receipt, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return models.MpesaTransaction{}, models.Receipt{}, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	txn.ID = uuid.New().String()
+	if txn.TransactionDate.IsZero() {
+		txn.TransactionDate = now
+	}
+	txn.Status = string(models.TransactionPosted)
+	txn.CreatedAt = now
+	txn.UpdatedAt = now
+	txnQuery := `INSERT INTO transactions (id, transaction_ref, transaction_date, transaction_type, amount, notes, debit_account, member, payment_method, payment_reference, collection_session, status, created_by, created_at, updated_at)
+              VALUES (:id, :transaction_ref, :transaction_date, :transaction_type, :amount, :notes, :debit_account, :member, :payment_method, :payment_reference, :collection_session, :status, :created_by, :created_at, :updated_at)`
+	if _, err := tx.NamedExecContext(ctx, txnQuery, txn); err != nil {
+		return models.MpesaTransaction{}, models.Receipt{}, err
+	}
+
+	receipt.ID = uuid.New().String()
+	receipt.TransactionID = txn.ID
+	receipt.CreatedAt = now
+	receipt.UpdatedAt = now
+	receiptQuery := `INSERT INTO receipts (id, transaction_id, income_account, fund, amount, created_at, updated_at)
+              VALUES (:id, :transaction_id, :income_account, :fund, :amount, :created_at, :updated_at)`
+	if _, err := tx.NamedExecContext(ctx, receiptQuery, receipt); err != nil {
+		return models.MpesaTransaction{}, models.Receipt{}, err
+	}
+
+	m.TransactionID = &txn.ID
+	m.Status = string(models.MpesaPosted)
+	m.Error = nil
+	m.UpdatedAt = now
+	mpesaQuery := `UPDATE mpesa_transactions SET member = :member, income_account = :income_account, transaction_id = :transaction_id, status = :status, error = :error, updated_at = :updated_at
+			  WHERE id = :id`
+	if _, err := tx.NamedExecContext(ctx, mpesaQuery, m); err != nil {
+		return models.MpesaTransaction{}, models.Receipt{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.MpesaTransaction{}, models.Receipt{}, err
+	}
+
+	return m, receipt, nil
+}
+
+func (p *Postgres) GetMpesaTransaction(ctx context.Context, id string) (models.MpesaTransaction, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var m models.MpesaTransaction
+	err := p.DB.GetContext(ctx, &m, "SELECT * FROM mpesa_transactions WHERE id = $1", id)
+	if err != nil {
+		return models.MpesaTransaction{}, err
+	}
+
+	return m, nil
+}
+
+func (p *Postgres) GetMpesaTransactionByTransID(ctx context.Context, transID string) (models.MpesaTransaction, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var m models.MpesaTransaction
+	err := p.DB.GetContext(ctx, &m, "SELECT * FROM mpesa_transactions WHERE trans_id = $1", transID)
+	if err != nil {
+		return models.MpesaTransaction{}, err
+	}
+
+	return m, nil
+}
+
+func (p *Postgres) GetMpesaTransactionsByStatus(ctx context.Context, status string) ([]models.MpesaTransaction, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var txns []models.MpesaTransaction
+	err := p.DB.SelectContext(ctx, &txns, "SELECT * FROM mpesa_transactions WHERE status = $1 ORDER BY trans_time DESC", status)
+	if err != nil {
+		return nil, err
+	}
+
+	return txns, nil
+}
+
+func (p *Postgres) GetAllMpesaTransactions(ctx context.Context) ([]models.MpesaTransaction, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var txns []models.MpesaTransaction
+	err := p.DB.SelectContext(ctx, &txns, "SELECT * FROM mpesa_transactions ORDER BY trans_time DESC")
+	if err != nil {
+		return nil, err
+	}
+
+	return txns, nil
+}
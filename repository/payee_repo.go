@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"storeHouse/database"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (p *Postgres) executePayeeQuery(ctx context.Context, query string, payee models.Payee) (models.Payee, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, payee)
+	if err != nil {
+		return models.Payee{}, err
+	}
+
+	return payee, nil
+}
+
+func (p *Postgres) CreatePayee(ctx context.Context, payee models.Payee) (models.Payee, error) {
+	payee.ID = uuid.New().String()
+	payee.CreatedAt = time.Now()
+	payee.UpdatedAt = time.Now()
+
+	query := `INSERT INTO payees (id, name, phone_number, email, kra_pin, bank_name, bank_branch, bank_account_number, mpesa_phone, mpesa_paybill, mpesa_account, mpesa_till, notes, is_active, created_by, created_at, updated_at)
+              VALUES (:id, :name, :phone_number, :email, :kra_pin, :bank_name, :bank_branch, :bank_account_number, :mpesa_phone, :mpesa_paybill, :mpesa_account, :mpesa_till, :notes, :is_active, :created_by, :created_at, :updated_at)`
+
+	return p.executePayeeQuery(ctx, query, payee)
+}
+
+func (p *Postgres) UpdatePayee(ctx context.Context, payee models.Payee) (models.Payee, error) {
+	payee.UpdatedAt = time.Now()
+
+	query := `UPDATE payees SET name = :name, phone_number = :phone_number, email = :email, kra_pin = :kra_pin, bank_name = :bank_name, bank_branch = :bank_branch,
+			  bank_account_number = :bank_account_number, mpesa_phone = :mpesa_phone, mpesa_paybill = :mpesa_paybill, mpesa_account = :mpesa_account,
+			  mpesa_till = :mpesa_till, notes = :notes, is_active = :is_active, updated_at = :updated_at
+			  WHERE id = :id`
+
+	return p.executePayeeQuery(ctx, query, payee)
+}
+
+func (p *Postgres) DeletePayee(ctx context.Context, id string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM payees WHERE id = $1", id)
+	return err
+}
+
+func (p *Postgres) GetPayee(ctx context.Context, id string) (models.Payee, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var payee models.Payee
+	err := p.DB.GetContext(ctx, &payee, "SELECT * FROM payees WHERE id = $1", id)
+	if err != nil {
+		return models.Payee{}, err
+	}
+
+	return payee, nil
+}
+
+func (p *Postgres) GetPayeeByKRAPin(ctx context.Context, kraPin string) (models.Payee, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var payee models.Payee
+	err := p.DB.GetContext(ctx, &payee, "SELECT * FROM payees WHERE kra_pin = $1", kraPin)
+	if err != nil {
+		return models.Payee{}, err
+	}
+
+	return payee, nil
+}
+
+func (p *Postgres) GetAllPayees(ctx context.Context) ([]models.Payee, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var payees []models.Payee
+	err := p.DB.SelectContext(ctx, &payees, "SELECT * FROM payees ORDER BY name ASC")
+	if err != nil {
+		return nil, err
+	}
+
+	return payees, nil
+}
+
+func (p *Postgres) SearchPayees(ctx context.Context, searchTerm string) ([]models.Payee, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var payees []models.Payee
+	query := `SELECT * FROM payees
+			  WHERE name ILIKE $1 OR phone_number ILIKE $1 OR mpesa_phone ILIKE $1 OR kra_pin ILIKE $1 OR email ILIKE $1
+			  ORDER BY is_active DESC, name ASC`
+	err := p.DB.SelectContext(ctx, &payees, query, "%"+searchTerm+"%")
+	if err != nil {
+		return nil, err
+	}
+
+	return payees, nil
+}
+
+// GetPayeeSpend totals the posted expenditures paid to each payee within the period, largest first
+func (p *Postgres) GetPayeeSpend(ctx context.Context, startDate, endDate time.Time) ([]models.PayeeSpend, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var spend []models.PayeeSpend
+	query := `SELECT p.id AS payee_id, p.name AS payee_name, p.kra_pin, COUNT(e.id) AS payments,
+			  COALESCE(SUM(e.amount), 0) AS total, MAX(t.transaction_date) AS last_paid_date
+			  FROM payees p
+			  JOIN expenditures e ON e.payee = p.id
+			  JOIN transactions t ON t.id = e.transaction_id
+			  WHERE t.status = 'posted' AND t.transaction_date BETWEEN $1 AND $2
+			  GROUP BY p.id, p.name, p.kra_pin
+			  ORDER BY total DESC, p.name ASC`
+	err := p.DB.SelectContext(ctx, &spend, query, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return spend, nil
+}
+
+// GetPayeeSpendByAccount totals a payee's posted expenditures per expense account (the
+// transaction's debit account) within the period
+func (p *Postgres) GetPayeeSpendByAccount(ctx context.Context, payeeID string, startDate, endDate time.Time) ([]models.PayeeAccountSpend, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var spend []models.PayeeAccountSpend
+	query := `SELECT a.id AS account_id, a.account_name, COUNT(e.id) AS payments, COALESCE(SUM(e.amount), 0) AS total
+			  FROM expenditures e
+			  JOIN transactions t ON t.id = e.transaction_id
+			  JOIN accounts a ON a.id = t.debit_account
+			  WHERE e.payee = $1 AND t.status = 'posted' AND t.transaction_date BETWEEN $2 AND $3
+			  GROUP BY a.id, a.account_name
+			  ORDER BY total DESC, a.account_name ASC`
+	err := p.DB.SelectContext(ctx, &spend, query, payeeID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return spend, nil
+}
+
+// GetPayeePayments lists a payee's posted expenditure lines within the period
+func (p *Postgres) GetPayeePayments(ctx context.Context, payeeID string, startDate, endDate time.Time) ([]models.PayeePayment, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var payments []models.PayeePayment
+	query := `SELECT e.id AS expenditure_id, t.id AS transaction_id, t.transaction_ref, t.transaction_date,
+			  a.id AS account_id, a.account_name, e.particulars, e.amount
+			  FROM expenditures e
+			  JOIN transactions t ON t.id = e.transaction_id
+			  JOIN accounts a ON a.id = t.debit_account
+			  WHERE e.payee = $1 AND t.status = 'posted' AND t.transaction_date BETWEEN $2 AND $3
+			  ORDER BY t.transaction_date DESC, e.created_at DESC`
+	err := p.DB.SelectContext(ctx, &payments, query, payeeID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return payments, nil
+}
+
+// CountExpendituresByPayee counts the expenditure lines that reference a payee
+func (p *Postgres) CountExpendituresByPayee(ctx context.Context, payeeID string) (int, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var count int
+	err := p.DB.GetContext(ctx, &count, "SELECT COUNT(*) FROM expenditures WHERE payee = $1", payeeID)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
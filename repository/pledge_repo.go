@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"storeHouse/database"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// pledgeSelect returns pledges together with the amount paid so far
+const pledgeSelect = `SELECT p.*, COALESCE((SELECT SUM(pp.amount) FROM pledge_payments pp WHERE pp.pledge_id = p.id), 0) AS amount_paid
+	FROM pledges p`
+
+func (p *Postgres) executePledgeQuery(ctx context.Context, query string, pledge models.Pledge) (models.Pledge, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, pledge)
+	if err != nil {
+		return models.Pledge{}, err
+	}
+
+	return pledge, nil
+}
+
+func (p *Postgres) CreatePledge(ctx context.Context, pledge models.Pledge) (models.Pledge, error) {
+	pledge.ID = uuid.New().String()
+
+	// Set default start date if not provided
+	if pledge.StartDate.IsZero() {
+		pledge.StartDate = time.Now()
+	}
+
+	pledge.CreatedAt = time.Now()
+	pledge.UpdatedAt = time.Now()
+
+	query := `INSERT INTO pledges (id, campaign_id, member, amount, frequency, installments, start_date, status, notes, created_by, created_at, updated_at)
+              VALUES (:id, :campaign_id, :member, :amount, :frequency, :installments, :start_date, :status, :notes, :created_by, :created_at, :updated_at)`
+
+	return p.executePledgeQuery(ctx, query, pledge)
+}
+
+func (p *Postgres) UpdatePledge(ctx context.Context, pledge models.Pledge) (models.Pledge, error) {
+	pledge.UpdatedAt = time.Now()
+
+	query := `UPDATE pledges SET amount = :amount, frequency = :frequency, installments = :installments, start_date = :start_date, status = :status, notes = :notes, updated_at = :updated_at 
+			  WHERE id = :id`
+
+	return p.executePledgeQuery(ctx, query, pledge)
+}
+
+func (p *Postgres) UpdatePledgeStatus(ctx context.Context, id string, status string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "UPDATE pledges SET status = $1, updated_at = $2 WHERE id = $3", status, time.Now(), id)
+	return err
+}
+
+func (p *Postgres) DeletePledge(ctx context.Context, id string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM pledges WHERE id = $1", id)
+	return err
+}
+
+func (p *Postgres) GetPledge(ctx context.Context, id string) (models.Pledge, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var pledge models.Pledge
+	err := p.DB.GetContext(ctx, &pledge, pledgeSelect+" WHERE p.id = $1", id)
+	if err != nil {
+		return models.Pledge{}, err
+	}
+
+	return pledge, nil
+}
+
+func (p *Postgres) GetPledgesByCampaign(ctx context.Context, campaignID string) ([]models.Pledge, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var pledges []models.Pledge
+	err := p.DB.SelectContext(ctx, &pledges, pledgeSelect+" WHERE p.campaign_id = $1 ORDER BY p.created_at DESC", campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pledges, nil
+}
+
+func (p *Postgres) GetPledgesByMember(ctx context.Context, memberID string) ([]models.Pledge, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var pledges []models.Pledge
+	err := p.DB.SelectContext(ctx, &pledges, pledgeSelect+" WHERE p.member = $1 ORDER BY p.created_at DESC", memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pledges, nil
+}
+
+// GetOpenPledgesForReceipt returns a member's open pledges on campaigns that
+// collect into the given income account, oldest first
+func (p *Postgres) GetOpenPledgesForReceipt(ctx context.Context, memberID, incomeAccountID string) ([]models.Pledge, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var pledges []models.Pledge
+	query := pledgeSelect + `
+		JOIN campaigns c ON c.id = p.campaign_id
+		WHERE p.member = $1 AND c.income_account = $2 AND p.status = 'open' AND c.is_active = true
+		ORDER BY p.start_date ASC, p.created_at ASC`
+	err := p.DB.SelectContext(ctx, &pledges, query, memberID, incomeAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pledges, nil
+}
+
+// GetPledgeDetailsByCampaign returns pledges joined with member and group names
+func (p *Postgres) GetPledgeDetailsByCampaign(ctx context.Context, campaignID string) ([]models.PledgeDetail, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var details []models.PledgeDetail
+	query := `
+		SELECT 
+			p.*,
+			COALESCE((SELECT SUM(pp.amount) FROM pledge_payments pp WHERE pp.pledge_id = p.id), 0) AS amount_paid,
+			m.full_name AS member_name,
+			m.group_id AS group_id,
+			mg.group_name AS group_name
+		FROM pledges p
+		JOIN members m ON m.id = p.member
+		LEFT JOIN members_groups mg ON mg.id = m.group_id
+		WHERE p.campaign_id = $1 AND p.status <> 'cancelled'
+		ORDER BY m.full_name ASC
+	`
+	err := p.DB.SelectContext(ctx, &details, query, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	return details, nil
+}
+
+func (p *Postgres) CreatePledgePayment(ctx context.Context, payment models.PledgePayment) (models.PledgePayment, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	payment.ID = uuid.New().String()
+	payment.CreatedAt = time.Now()
+
+	query := `INSERT INTO pledge_payments (id, pledge_id, receipt_id, amount, created_at)
+              VALUES (:id, :pledge_id, :receipt_id, :amount, :created_at)`
+
+	_, err := p.DB.NamedExecContext(ctx, query, payment)
+	if err != nil {
+		return models.PledgePayment{}, err
+	}
+
+	return payment, nil
+}
+
+func (p *Postgres) GetPledgePaymentsByPledge(ctx context.Context, pledgeID string) ([]models.PledgePayment, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var payments []models.PledgePayment
+	err := p.DB.SelectContext(ctx, &payments, "SELECT * FROM pledge_payments WHERE pledge_id = $1 ORDER BY created_at ASC", pledgeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return payments, nil
+}
+
+func (p *Postgres) GetPledgePaymentsByReceipt(ctx context.Context, receiptID string) ([]models.PledgePayment, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var payments []models.PledgePayment
+	err := p.DB.SelectContext(ctx, &payments, "SELECT * FROM pledge_payments WHERE receipt_id = $1 ORDER BY created_at ASC", receiptID)
+	if err != nil {
+		return nil, err
+	}
+
+	return payments, nil
+}
+
+func (p *Postgres) DeletePledgePaymentsByReceipt(ctx context.Context, receiptID string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM pledge_payments WHERE receipt_id = $1", receiptID)
+	return err
+}
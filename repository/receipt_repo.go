@@ -1,15 +1,19 @@
 package repository
 
 import (
+	"context"
+	"storeHouse/database"
 	"storeHouse/models"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 )
 
-func executeReceiptQuery(db *sqlx.DB, query string, receipt models.Receipt) (models.Receipt, error) {
-	_, err := db.NamedExec(query, receipt)
+func (p *Postgres) executeReceiptQuery(ctx context.Context, query string, receipt models.Receipt) (models.Receipt, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, receipt)
 	if err != nil {
 		return models.Receipt{}, err
 	}
@@ -17,34 +21,40 @@ func executeReceiptQuery(db *sqlx.DB, query string, receipt models.Receipt) (mod
 	return receipt, nil
 }
 
-func CreateReceipt(db *sqlx.DB, receipt models.Receipt) (models.Receipt, error) {
+func (p *Postgres) CreateReceipt(ctx context.Context, receipt models.Receipt) (models.Receipt, error) {
 	receipt.ID = uuid.New().String()
 	receipt.CreatedAt = time.Now()
 	receipt.UpdatedAt = time.Now()
 
-	query := `INSERT INTO receipts (id, transaction_id, income_account, amount, created_at, updated_at)
-              VALUES (:id, :transaction_id, :income_account_id, :amount, :created_at, :updated_at)`
+	query := `INSERT INTO receipts (id, transaction_id, income_account, fund, amount, created_at, updated_at)
+              VALUES (:id, :transaction_id, :income_account, :fund, :amount, :created_at, :updated_at)`
 
-	return executeReceiptQuery(db, query, receipt)
+	return p.executeReceiptQuery(ctx, query, receipt)
 }
 
-func UpdateReceipt(db *sqlx.DB, receipt models.Receipt) (models.Receipt, error) {
+func (p *Postgres) UpdateReceipt(ctx context.Context, receipt models.Receipt) (models.Receipt, error) {
 	receipt.UpdatedAt = time.Now()
 
-	query := `UPDATE receipts SET income_account = :income_account_id, amount = :amount, updated_at = :updated_at 
+	query := `UPDATE receipts SET income_account = :income_account, fund = :fund, amount = :amount, updated_at = :updated_at 
 			  WHERE id = :id`
 
-	return executeReceiptQuery(db, query, receipt)
+	return p.executeReceiptQuery(ctx, query, receipt)
 }
 
-func DeleteReceipt(db *sqlx.DB, id string) error {
-	_, err := db.Exec("DELETE FROM receipts WHERE id = $1", id)
+func (p *Postgres) DeleteReceipt(ctx context.Context, id string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM receipts WHERE id = $1", id)
 	return err
 }
 
-func GetReceipt(db *sqlx.DB, id string) (models.Receipt, error) {
+func (p *Postgres) GetReceipt(ctx context.Context, id string) (models.Receipt, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var receipt models.Receipt
-	err := db.Get(&receipt, "SELECT * FROM receipts WHERE id = $1", id)
+	err := p.DB.GetContext(ctx, &receipt, "SELECT * FROM receipts WHERE id = $1", id)
 	if err != nil {
 		return models.Receipt{}, err
 	}
@@ -52,9 +62,12 @@ func GetReceipt(db *sqlx.DB, id string) (models.Receipt, error) {
 	return receipt, nil
 }
 
-func GetReceiptByTransaction(db *sqlx.DB, transactionID string) ([]models.Receipt, error) {
+func (p *Postgres) GetReceiptByTransaction(ctx context.Context, transactionID string) ([]models.Receipt, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var receipts []models.Receipt
-	err := db.Select(&receipts, "SELECT * FROM receipts WHERE transaction_id = $1 ORDER BY created_at DESC", transactionID)
+	err := p.DB.SelectContext(ctx, &receipts, "SELECT * FROM receipts WHERE transaction_id = $1 ORDER BY created_at DESC", transactionID)
 	if err != nil {
 		return nil, err
 	}
@@ -62,9 +75,12 @@ func GetReceiptByTransaction(db *sqlx.DB, transactionID string) ([]models.Receip
 	return receipts, nil
 }
 
-func GetReceiptByAccount(db *sqlx.DB, accountID string) ([]models.Receipt, error) {
+func (p *Postgres) GetReceiptByAccount(ctx context.Context, accountID string) ([]models.Receipt, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var receipts []models.Receipt
-	err := db.Select(&receipts, "SELECT * FROM receipts WHERE income_account = $1 ORDER BY created_at DESC", accountID)
+	err := p.DB.SelectContext(ctx, &receipts, "SELECT * FROM receipts WHERE income_account IN ("+accountSubtree+") ORDER BY created_at DESC", accountID)
 	if err != nil {
 		return nil, err
 	}
@@ -72,9 +88,12 @@ func GetReceiptByAccount(db *sqlx.DB, accountID string) ([]models.Receipt, error
 	return receipts, nil
 }
 
-func GetReceiptByDateRange(db *sqlx.DB, startDate, endDate time.Time) ([]models.Receipt, error) {
+func (p *Postgres) GetReceiptByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.Receipt, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var receipts []models.Receipt
-	err := db.Select(&receipts, "SELECT * FROM receipts WHERE created_at BETWEEN $1 AND $2 ORDER BY created_at DESC", startDate, endDate)
+	err := p.DB.SelectContext(ctx, &receipts, "SELECT * FROM receipts WHERE created_at BETWEEN $1 AND $2 ORDER BY created_at DESC", startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
@@ -82,9 +101,12 @@ func GetReceiptByDateRange(db *sqlx.DB, startDate, endDate time.Time) ([]models.
 	return receipts, nil
 }
 
-func GetAllReceipts(db *sqlx.DB) ([]models.Receipt, error) {
+func (p *Postgres) GetAllReceipts(ctx context.Context) ([]models.Receipt, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var receipts []models.Receipt
-	err := db.Select(&receipts, "SELECT * FROM receipts ORDER BY created_at DESC")
+	err := p.DB.SelectContext(ctx, &receipts, "SELECT * FROM receipts ORDER BY created_at DESC")
 	if err != nil {
 		return nil, err
 	}
@@ -92,9 +114,31 @@ func GetAllReceipts(db *sqlx.DB) ([]models.Receipt, error) {
 	return receipts, nil
 }
 
-func GetTotalReceiptsByAccount(db *sqlx.DB, accountID string) (float64, error) {
+func (p *Postgres) GetTotalReceiptsByAccount(ctx context.Context, accountID string) (float64, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var total float64
+	query := `SELECT COALESCE(SUM(r.amount), 0) FROM receipts r
+			  JOIN transactions t ON t.id = r.transaction_id
+			  WHERE r.income_account IN (` + accountSubtree + `) AND t.status = 'posted'`
+	err := p.DB.GetContext(ctx, &total, query, accountID)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (p *Postgres) GetTotalReceiptsByDateRange(ctx context.Context, accountID string, startDate, endDate time.Time) (float64, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var total float64
-	err := db.Get(&total, "SELECT COALESCE(SUM(amount), 0) FROM receipts WHERE income_account = $1", accountID)
+	query := `SELECT COALESCE(SUM(r.amount), 0) FROM receipts r
+			  JOIN transactions t ON t.id = r.transaction_id
+			  WHERE r.income_account IN (` + accountSubtree + `) AND t.status = 'posted' AND r.created_at BETWEEN $2 AND $3`
+	err := p.DB.GetContext(ctx, &total, query, accountID, startDate, endDate)
 	if err != nil {
 		return 0, err
 	}
@@ -102,13 +146,70 @@ func GetTotalReceiptsByAccount(db *sqlx.DB, accountID string) (float64, error) {
 	return total, nil
 }
 
-func GetTotalReceiptsByDateRange(db *sqlx.DB, accountID string, startDate, endDate time.Time) (float64, error) {
+// GetTotalReceiptsByTransactionDate sums posted receipts to an account whose transactions fall within the period
+func (p *Postgres) GetTotalReceiptsByTransactionDate(ctx context.Context, accountID string, startDate, endDate time.Time) (float64, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var total float64
-	query := "SELECT COALESCE(SUM(amount), 0) FROM receipts WHERE income_account = $1 AND created_at BETWEEN $2 AND $3"
-	err := db.Get(&total, query, accountID, startDate, endDate)
+	query := `SELECT COALESCE(SUM(r.amount), 0) FROM receipts r
+			  JOIN transactions t ON t.id = r.transaction_id
+			  WHERE r.income_account IN (` + accountSubtree + `) AND t.status = 'posted' AND t.transaction_date BETWEEN $2 AND $3`
+	err := p.DB.GetContext(ctx, &total, query, accountID, startDate, endDate)
 	if err != nil {
 		return 0, err
 	}
 
 	return total, nil
 }
+
+// GetMemberGivingLines returns the receipt lines on a member's posted transactions within the period
+func (p *Postgres) GetMemberGivingLines(ctx context.Context, memberID string, startDate, endDate time.Time) ([]models.MemberGivingLine, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var lines []models.MemberGivingLine
+	query := `SELECT t.id AS transaction_id, t.transaction_ref, t.transaction_date, r.income_account, a.account_name, r.amount
+			  FROM receipts r
+			  JOIN transactions t ON t.id = r.transaction_id
+			  JOIN accounts a ON a.id = r.income_account
+			  WHERE t.member = $1 AND t.status = 'posted' AND t.transaction_date BETWEEN $2 AND $3
+			  ORDER BY t.transaction_date, t.id`
+	err := p.DB.SelectContext(ctx, &lines, query, memberID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// GetIncomeLines sums the posted income to each account over the period, with the
+// top-level account each one rolls up to
+func (p *Postgres) GetIncomeLines(ctx context.Context, startDate, endDate time.Time) ([]models.IncomeLine, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var lines []models.IncomeLine
+	query := `WITH RECURSIVE roots AS (
+				  SELECT id, id AS root FROM accounts WHERE parent IS NULL
+				  UNION ALL
+				  SELECT a.id, r.root FROM accounts a JOIN roots r ON a.parent = r.id
+			  )
+			  SELECT a.id AS account_id, a.code, a.account_name, a.local_share,
+			         c.id AS category_id, c.code AS category_code, c.account_name AS category_name,
+			         SUM(r.amount) AS amount
+			  FROM receipts r
+			  JOIN transactions t ON t.id = r.transaction_id
+			  JOIN accounts a ON a.id = r.income_account
+			  JOIN roots ON roots.id = a.id
+			  JOIN accounts c ON c.id = roots.root
+			  WHERE t.status = 'posted' AND t.transaction_date BETWEEN $1 AND $2
+			  GROUP BY a.id, c.id
+			  ORDER BY c.code ASC NULLS LAST, c.account_name ASC, a.code ASC NULLS LAST, a.account_name ASC`
+	err := p.DB.SelectContext(ctx, &lines, query, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
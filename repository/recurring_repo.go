@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"storeHouse/database"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (p *Postgres) executeRecurringTemplateQuery(ctx context.Context, query string, template models.RecurringTemplate) (models.RecurringTemplate, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, template)
+	if err != nil {
+		return models.RecurringTemplate{}, err
+	}
+
+	return template, nil
+}
+
+func (p *Postgres) CreateRecurringTemplate(ctx context.Context, template models.RecurringTemplate) (models.RecurringTemplate, error) {
+	template.ID = uuid.New().String()
+	template.CreatedAt = time.Now()
+	template.UpdatedAt = time.Now()
+
+	query := `INSERT INTO recurring_templates (id, name, transaction_type, debit_account, member, notes, schedule, start_date, end_date, post_mode, next_run_at, is_active, created_by, created_at, updated_at)
+              VALUES (:id, :name, :transaction_type, :debit_account, :member, :notes, :schedule, :start_date, :end_date, :post_mode, :next_run_at, :is_active, :created_by, :created_at, :updated_at)`
+
+	return p.executeRecurringTemplateQuery(ctx, query, template)
+}
+
+func (p *Postgres) UpdateRecurringTemplate(ctx context.Context, template models.RecurringTemplate) (models.RecurringTemplate, error) {
+	template.UpdatedAt = time.Now()
+
+	query := `UPDATE recurring_templates SET name = :name, debit_account = :debit_account, member = :member, notes = :notes, schedule = :schedule,
+			  start_date = :start_date, end_date = :end_date, post_mode = :post_mode, next_run_at = :next_run_at, last_run_at = :last_run_at,
+			  is_active = :is_active, updated_at = :updated_at
+			  WHERE id = :id`
+
+	return p.executeRecurringTemplateQuery(ctx, query, template)
+}
+
+func (p *Postgres) DeleteRecurringTemplate(ctx context.Context, id string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM recurring_templates WHERE id = $1", id)
+	return err
+}
+
+func (p *Postgres) GetRecurringTemplate(ctx context.Context, id string) (models.RecurringTemplate, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var template models.RecurringTemplate
+	err := p.DB.GetContext(ctx, &template, "SELECT * FROM recurring_templates WHERE id = $1", id)
+	if err != nil {
+		return models.RecurringTemplate{}, err
+	}
+
+	return template, nil
+}
+
+func (p *Postgres) GetAllRecurringTemplates(ctx context.Context) ([]models.RecurringTemplate, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var templates []models.RecurringTemplate
+	err := p.DB.SelectContext(ctx, &templates, "SELECT * FROM recurring_templates ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// GetDueRecurringTemplates returns the active templates whose next run is at or before the given time
+func (p *Postgres) GetDueRecurringTemplates(ctx context.Context, now time.Time) ([]models.RecurringTemplate, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var templates []models.RecurringTemplate
+	err := p.DB.SelectContext(ctx, &templates, `SELECT * FROM recurring_templates
+		WHERE is_active = true AND next_run_at IS NOT NULL AND next_run_at <= $1
+		ORDER BY next_run_at`, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// ReplaceRecurringTemplateLines swaps a template's lines for the given ones
+func (p *Postgres) ReplaceRecurringTemplateLines(ctx context.Context, templateID string, lines []models.RecurringTemplateLine) ([]models.RecurringTemplateLine, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM recurring_template_lines WHERE template_id = $1", templateID); err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO recurring_template_lines (id, template_id, account, particulars, amount, created_at, updated_at)
+              VALUES (:id, :template_id, :account, :particulars, :amount, :created_at, :updated_at)`
+
+	for i := range lines {
+		lines[i].ID = uuid.New().String()
+		lines[i].TemplateID = templateID
+		lines[i].CreatedAt = time.Now()
+		lines[i].UpdatedAt = time.Now()
+		if _, err := tx.NamedExecContext(ctx, query, lines[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+func (p *Postgres) GetRecurringTemplateLines(ctx context.Context, templateID string) ([]models.RecurringTemplateLine, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var lines []models.RecurringTemplateLine
+	err := p.DB.SelectContext(ctx, &lines, "SELECT * FROM recurring_template_lines WHERE template_id = $1 ORDER BY created_at, id", templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// ClaimRecurringRun records a pending run for an occurrence. It reports false when the
+// occurrence already has a run, so the same date is never generated twice.
+func (p *Postgres) ClaimRecurringRun(ctx context.Context, templateID string, scheduledFor time.Time) (models.RecurringRun, bool, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	run := models.RecurringRun{
+		ID:           uuid.New().String(),
+		TemplateID:   templateID,
+		ScheduledFor: scheduledFor,
+		Status:       string(models.RecurringRunPending),
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	result, err := p.DB.NamedExecContext(ctx, `INSERT INTO recurring_runs (id, template_id, scheduled_for, status, created_at, updated_at)
+              VALUES (:id, :template_id, :scheduled_for, :status, :created_at, :updated_at)
+              ON CONFLICT (template_id, scheduled_for) DO NOTHING`, run)
+	if err != nil {
+		return models.RecurringRun{}, false, err
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return models.RecurringRun{}, false, err
+	}
+
+	return run, inserted == 1, nil
+}
+
+func (p *Postgres) UpdateRecurringRun(ctx context.Context, run models.RecurringRun) (models.RecurringRun, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	run.UpdatedAt = time.Now()
+
+	_, err := p.DB.NamedExecContext(ctx, `UPDATE recurring_runs SET status = :status, transaction_id = :transaction_id, error = :error, updated_at = :updated_at
+			  WHERE id = :id`, run)
+	if err != nil {
+		return models.RecurringRun{}, err
+	}
+
+	return run, nil
+}
+
+func (p *Postgres) GetRecurringRun(ctx context.Context, id string) (models.RecurringRun, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var run models.RecurringRun
+	err := p.DB.GetContext(ctx, &run, "SELECT * FROM recurring_runs WHERE id = $1", id)
+	if err != nil {
+		return models.RecurringRun{}, err
+	}
+
+	return run, nil
+}
+
+func (p *Postgres) GetRecurringRunsByTemplate(ctx context.Context, templateID string) ([]models.RecurringRun, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var runs []models.RecurringRun
+	err := p.DB.SelectContext(ctx, &runs, "SELECT * FROM recurring_runs WHERE template_id = $1 ORDER BY scheduled_for DESC", templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
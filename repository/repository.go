@@ -0,0 +1,443 @@
+package repository
+
+import (
+	"context"
+	"storeHouse/models"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Postgres keeps the church's records in its Postgres database
+type Postgres struct {
+	DB *sqlx.DB
+}
+
+// Create a new instance of Postgres
+func NewPostgres(db *sqlx.DB) *Postgres {
+	return &Postgres{DB: db}
+}
+
+// Store is every repository the services use. Postgres is the one the app runs on; the
+// memory package holds another for tests.
+type Store interface {
+	AccountRepository
+	ApprovalPolicyRepository
+	AttachmentRepository
+	BankStatementRepository
+	BudgetRepository
+	CampaignRepository
+	ChequeRepository
+	ChurchRepository
+	CollectionRepository
+	DistrictRepository
+	EmailRepository
+	ExpenditureRepository
+	FundRepository
+	ImprestRepository
+	MemberRepository
+	MembersGroupRepository
+	MobileMoneyRepository
+	MpesaRepository
+	PayeeRepository
+	PledgeRepository
+	ReceiptRepository
+	RecurringRepository
+	SetupRepository
+	SMSNotificationRepository
+	TransactionRepository
+	TransferRepository
+	UserRepository
+	VoucherRepository
+}
+
+var _ Store = (*Postgres)(nil)
+
+// AccountRepository stores the chart of accounts
+type AccountRepository interface {
+	CreateAccount(ctx context.Context, acc models.Account) (models.Account, error)
+	UpdateAccount(ctx context.Context, acc models.Account) (models.Account, error)
+	DeactivateAccount(ctx context.Context, id string) (models.Account, error)
+	GetAccount(ctx context.Context, id string) (models.Account, error)
+	GetAccountByName(ctx context.Context, name string) (models.Account, error)
+	GetAllAccounts(ctx context.Context) ([]models.Account, error)
+	GetAccountByCode(ctx context.Context, code string) (models.Account, error)
+	GetChartOfAccounts(ctx context.Context, includeInactive bool) ([]models.Account, error)
+	GetAccountSubtreeIDs(ctx context.Context, id string) ([]string, error)
+	CountChildAccounts(ctx context.Context, id string) (int, error)
+	CountAccountPostings(ctx context.Context, id string) (int, error)
+}
+
+// ApprovalPolicyRepository stores the policies that decide who must approve a voucher
+type ApprovalPolicyRepository interface {
+	CreateApprovalPolicy(ctx context.Context, policy models.ApprovalPolicy) (models.ApprovalPolicy, error)
+	UpdateApprovalPolicy(ctx context.Context, policy models.ApprovalPolicy) (models.ApprovalPolicy, error)
+	DeleteApprovalPolicy(ctx context.Context, id string) error
+	GetApprovalPolicy(ctx context.Context, id string) (models.ApprovalPolicy, error)
+	GetAllApprovalPolicies(ctx context.Context) ([]models.ApprovalPolicy, error)
+	GetActiveApprovalPolicies(ctx context.Context) ([]models.ApprovalPolicy, error)
+}
+
+// AttachmentRepository stores the files linked to transactions, expenditures and transfers
+type AttachmentRepository interface {
+	CreateAttachment(ctx context.Context, attachment models.Attachment) (models.Attachment, error)
+	DeleteAttachment(ctx context.Context, id string) error
+	GetAttachment(ctx context.Context, id string) (models.Attachment, error)
+	GetAttachmentsByEntity(ctx context.Context, entity models.AttachmentEntity, entityID string) ([]models.Attachment, error)
+	GetAttachmentsByTransactionTree(ctx context.Context, transactionID string) ([]models.Attachment, error)
+}
+
+// BankStatementRepository stores bank statements, their lines and the bank book they are reconciled against
+type BankStatementRepository interface {
+	CreateBankStatement(ctx context.Context, statement models.BankStatement) (models.BankStatement, error)
+	UpdateBankStatement(ctx context.Context, statement models.BankStatement) (models.BankStatement, error)
+	DeleteBankStatement(ctx context.Context, id string) error
+	GetBankStatement(ctx context.Context, id string) (models.BankStatement, error)
+	GetBankStatementsByAccount(ctx context.Context, accountID string) ([]models.BankStatement, error)
+	GetLatestBankStatement(ctx context.Context, accountID string, asOf time.Time) (models.BankStatement, error)
+	CreateStatementLine(ctx context.Context, line models.BankStatementLine) (models.BankStatementLine, error)
+	MatchStatementLine(ctx context.Context, id, transactionID string, matchType models.MatchType) error
+	UnmatchStatementLine(ctx context.Context, id string) error
+	GetStatementLine(ctx context.Context, id string) (models.BankStatementLine, error)
+	GetStatementLines(ctx context.Context, statementID string) ([]models.BankStatementLine, error)
+	GetStatementLineByFitID(ctx context.Context, accountID, fitID string) (models.BankStatementLine, error)
+	GetStatementLineByTransaction(ctx context.Context, accountID, transactionID string) (models.BankStatementLine, error)
+	GetStatementLinesByAccount(ctx context.Context, accountID string, asOf time.Time) ([]models.BankStatementLine, error)
+	GetTotalStatementLines(ctx context.Context, statementID string) (float64, error)
+	GetBankBookEntries(ctx context.Context, accountID string, asOf time.Time) ([]models.BankBookEntry, error)
+	GetMatchedTransactionIDs(ctx context.Context, accountID string) ([]string, error)
+}
+
+// BudgetRepository stores monthly budgets per account
+type BudgetRepository interface {
+	SaveBudget(ctx context.Context, budget models.Budget) (models.Budget, error)
+	UpdateBudget(ctx context.Context, budget models.Budget) (models.Budget, error)
+	DeleteBudgetsByAccount(ctx context.Context, accountID string, year int) error
+	GetBudget(ctx context.Context, id string) (models.Budget, error)
+	GetBudgetByMonth(ctx context.Context, accountID string, year, month int) (models.Budget, error)
+	GetBudgetsByAccount(ctx context.Context, accountID string, year int) ([]models.Budget, error)
+	GetBudgetsByYear(ctx context.Context, year int) ([]models.Budget, error)
+	GetBudgetTotal(ctx context.Context, accountID string, year, fromMonth, toMonth int) (float64, error)
+	CountBudgets(ctx context.Context, accountID string, year int) (int, error)
+}
+
+// CampaignRepository stores fundraising campaigns
+type CampaignRepository interface {
+	CreateCampaign(ctx context.Context, campaign models.Campaign) (models.Campaign, error)
+	UpdateCampaign(ctx context.Context, campaign models.Campaign) (models.Campaign, error)
+	GetCampaign(ctx context.Context, id string) (models.Campaign, error)
+	GetCampaignByName(ctx context.Context, name string) (models.Campaign, error)
+	GetAllCampaigns(ctx context.Context) ([]models.Campaign, error)
+	GetCampaignsByAccount(ctx context.Context, accountID string) ([]models.Campaign, error)
+}
+
+// ChequeRepository stores the cheque register
+type ChequeRepository interface {
+	CreateCheque(ctx context.Context, cheque models.Cheque) (models.Cheque, error)
+	UpdateCheque(ctx context.Context, cheque models.Cheque) (models.Cheque, error)
+	GetCheque(ctx context.Context, id string) (models.Cheque, error)
+	GetChequeByNumber(ctx context.Context, bankAccountID, chequeNumber string) (models.Cheque, error)
+	GetCheques(ctx context.Context, bankAccountID, status string) ([]models.Cheque, error)
+	GetChequesByTransaction(ctx context.Context, transactionID string) ([]models.Cheque, error)
+	GetChequesIssuedBy(ctx context.Context, bankAccountID string, asOf time.Time) ([]models.Cheque, error)
+	GetChequeBankAccounts(ctx context.Context) ([]string, error)
+	MarkStaleCheques(ctx context.Context, staleBefore time.Time) (int64, error)
+}
+
+// ChurchRepository stores churches and the users who hold a role in them
+type ChurchRepository interface {
+	CreateChurch(ctx context.Context, church models.Church, adminID string) (models.Church, error)
+	UpdateChurch(ctx context.Context, church models.Church) (models.Church, error)
+	GetChurch(ctx context.Context, id string) (models.Church, error)
+	GetChurchByCode(ctx context.Context, code string) (models.Church, error)
+	GetChurchByShortcode(ctx context.Context, shortcode string) (models.Church, error)
+	GetActiveChurches(ctx context.Context) ([]models.Church, error)
+	GetUserChurches(ctx context.Context, userID string) ([]models.UserChurch, error)
+	GetChurchRole(ctx context.Context, churchID, userID string) (string, error)
+	GetChurchUsers(ctx context.Context, churchID string) ([]models.ChurchUserResponse, error)
+	SaveChurchUser(ctx context.Context, member models.ChurchUser) error
+	RemoveChurchUser(ctx context.Context, churchID, userID string) error
+	CountChurchAdmins(ctx context.Context, churchID string) (int, error)
+}
+
+// CollectionRepository stores collection sessions, their denominations and sign-offs
+type CollectionRepository interface {
+	CreateCollectionSession(ctx context.Context, session models.CollectionSession) (models.CollectionSession, error)
+	UpdateCollectionSession(ctx context.Context, session models.CollectionSession) (models.CollectionSession, error)
+	GetCollectionSession(ctx context.Context, id string) (models.CollectionSession, error)
+	GetCollectionSessions(ctx context.Context, status string) ([]models.CollectionSession, error)
+	GetTransactionsByCollectionSession(ctx context.Context, sessionID string) ([]models.Transaction, error)
+	DeleteCollectionReceipt(ctx context.Context, transactionID string) error
+	GetCollectionDenominations(ctx context.Context, sessionID string) ([]models.CollectionDenomination, error)
+	ReplaceCollectionDenominations(ctx context.Context, sessionID string, denominations []models.CollectionDenomination) ([]models.CollectionDenomination, error)
+	GetCollectionSignOffs(ctx context.Context, sessionID string) ([]models.CollectionSignOff, error)
+	ClearCollectionSignOffs(ctx context.Context, sessionID string) error
+	SignOffCollectionSession(ctx context.Context, signOff models.CollectionSignOff, required int) (bool, error)
+}
+
+// DistrictRepository stores districts and the users who hold a role in them
+type DistrictRepository interface {
+	CreateDistrict(ctx context.Context, district models.District, adminID string) (models.District, error)
+	GetDistrict(ctx context.Context, id string) (models.District, error)
+	GetDistrictByCode(ctx context.Context, code string) (models.District, error)
+	GetUserDistricts(ctx context.Context, userID string) ([]models.UserDistrict, error)
+	GetDistrictRole(ctx context.Context, districtID, userID string) (string, error)
+	GetDistrictChurches(ctx context.Context, districtID string) ([]models.Church, error)
+	SetChurchDistrict(ctx context.Context, churchID string, districtID *string) error
+	GetDistrictUsers(ctx context.Context, districtID string) ([]models.DistrictUserResponse, error)
+	SaveDistrictUser(ctx context.Context, districtID, userID, role string) error
+	RemoveDistrictUser(ctx context.Context, districtID, userID string) error
+	CountDistrictAdmins(ctx context.Context, districtID string) (int, error)
+}
+
+// EmailRepository stores the outgoing email queue
+type EmailRepository interface {
+	CreateEmail(ctx context.Context, email models.Email, attachments []models.EmailAttachment) (models.Email, error)
+	UpdateEmail(ctx context.Context, email models.Email) (models.Email, error)
+	GetEmail(ctx context.Context, id string) (models.Email, error)
+	GetEmailAttachments(ctx context.Context, emailID string) ([]models.EmailAttachment, error)
+	GetDueEmails(ctx context.Context, asOf time.Time, limit int) ([]models.Email, error)
+	GetEmailsByStatus(ctx context.Context, status string) ([]models.Email, error)
+	GetAllEmails(ctx context.Context) ([]models.Email, error)
+}
+
+// ExpenditureRepository stores expenditure lines
+type ExpenditureRepository interface {
+	CreateExpenditure(ctx context.Context, exp models.Expenditure) (models.Expenditure, error)
+	UpdateExpenditure(ctx context.Context, exp models.Expenditure) (models.Expenditure, error)
+	DeleteExpenditure(ctx context.Context, id string) error
+	GetExpenditure(ctx context.Context, id string) (models.Expenditure, error)
+	GetExpenditureByTransaction(ctx context.Context, transactionID string) ([]models.Expenditure, error)
+	GetExpenditureByAccount(ctx context.Context, accountID string) ([]models.Expenditure, error)
+	GetExpenditureByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.Expenditure, error)
+	GetAllExpenditures(ctx context.Context) ([]models.Expenditure, error)
+	GetTotalExpendituresByTransactionDate(ctx context.Context, accountID string, startDate, endDate time.Time) (float64, error)
+	GetTotalExpendituresByTransaction(ctx context.Context, transactionID string) (float64, error)
+}
+
+// FundRepository stores restricted funds and their balances
+type FundRepository interface {
+	CreateFund(ctx context.Context, fund models.Fund) (models.Fund, error)
+	UpdateFund(ctx context.Context, fund models.Fund) (models.Fund, error)
+	GetFund(ctx context.Context, id string) (models.Fund, error)
+	GetFundByCode(ctx context.Context, code string) (models.Fund, error)
+	GetAllFunds(ctx context.Context) ([]models.Fund, error)
+	GetFundTotals(ctx context.Context, asOf time.Time) ([]models.FundTotals, error)
+	GetFundCommitted(ctx context.Context, fundID string, excludeExpenditureID string) (float64, error)
+	GetFundSpendByTransaction(ctx context.Context, transactionID string) ([]models.FundTotals, error)
+	GetFundAccountBalances(ctx context.Context, fundID *string, asOf time.Time) ([]models.FundAccountBalance, error)
+}
+
+// ImprestRepository stores imprest floats, petty cash vouchers and replenishments
+type ImprestRepository interface {
+	CreateImprest(ctx context.Context, imprest models.Imprest) (models.Imprest, error)
+	UpdateImprest(ctx context.Context, imprest models.Imprest) (models.Imprest, error)
+	GetImprest(ctx context.Context, id string) (models.Imprest, error)
+	GetImprestByAccount(ctx context.Context, accountID string) (models.Imprest, error)
+	GetAllImprests(ctx context.Context) ([]models.Imprest, error)
+	CreatePettyCashVoucher(ctx context.Context, voucher models.PettyCashVoucher) (models.PettyCashVoucher, error)
+	UpdatePettyCashVoucher(ctx context.Context, voucher models.PettyCashVoucher) (models.PettyCashVoucher, error)
+	DeletePettyCashVoucher(ctx context.Context, id string) error
+	GetPettyCashVoucher(ctx context.Context, id string) (models.PettyCashVoucher, error)
+	GetPettyCashVouchersByImprest(ctx context.Context, imprestID string) ([]models.PettyCashVoucher, error)
+	GetPettyCashVouchersByStatus(ctx context.Context, imprestID, status string) ([]models.PettyCashVoucher, error)
+	GetPettyCashVouchersByReplenishment(ctx context.Context, replenishmentID string) ([]models.PettyCashVoucher, error)
+	GetTotalPettyCashVouchersByStatus(ctx context.Context, imprestID, status string) (float64, error)
+	ClaimPettyCashVouchers(ctx context.Context, imprestID, replenishmentID string) error
+	ReleasePettyCashVouchers(ctx context.Context, replenishmentID string) error
+	CreateImprestReplenishment(ctx context.Context, replenishment models.ImprestReplenishment) (models.ImprestReplenishment, error)
+	UpdateImprestReplenishment(ctx context.Context, replenishment models.ImprestReplenishment) (models.ImprestReplenishment, error)
+	PostImprestReplenishment(ctx context.Context, posting models.ReplenishmentPosting) (models.ImprestReplenishment, error)
+	GetImprestReplenishment(ctx context.Context, id string) (models.ImprestReplenishment, error)
+	GetImprestReplenishments(ctx context.Context, imprestID string) ([]models.ImprestReplenishment, error)
+	GetPendingImprestReplenishment(ctx context.Context, imprestID string) (models.ImprestReplenishment, error)
+}
+
+// MemberRepository stores church members
+type MemberRepository interface {
+	CreateMember(ctx context.Context, member models.Member) (models.Member, error)
+	UpdateMember(ctx context.Context, member models.Member) (models.Member, error)
+	DeleteMember(ctx context.Context, id string) error
+	SetMemberSMSOptOut(ctx context.Context, memberID string, optOut bool) error
+	GetMember(ctx context.Context, id string) (models.Member, error)
+	GetMemberByPhone(ctx context.Context, phoneNumber string) (models.Member, error)
+	GetMemberByEmail(ctx context.Context, email string) (models.Member, error)
+	GetMemberByGroup(ctx context.Context, groupID string) ([]models.Member, error)
+	GetAllMembers(ctx context.Context) ([]models.Member, error)
+	SearchMembers(ctx context.Context, searchTerm string) ([]models.Member, error)
+}
+
+// MembersGroupRepository stores member groups
+type MembersGroupRepository interface {
+	CreateGroup(ctx context.Context, group models.MembersGroup) (models.MembersGroup, error)
+	UpdateGroup(ctx context.Context, group models.MembersGroup) (models.MembersGroup, error)
+	DeleteGroup(ctx context.Context, id string) error
+	GetGroup(ctx context.Context, id string) (models.MembersGroup, error)
+	GetGroupByName(ctx context.Context, name string) (models.MembersGroup, error)
+	GetAllGroups(ctx context.Context) ([]models.MembersGroup, error)
+	GetGroupsWithMemberCount(ctx context.Context) ([]map[string]interface{}, error)
+}
+
+// MobileMoneyRepository stores imported mobile money statements and their lines
+type MobileMoneyRepository interface {
+	CreateMobileMoneyImport(ctx context.Context, imp models.MobileMoneyImport, lines []models.MobileMoneyLine) (models.MobileMoneyImport, []models.MobileMoneyLine, error)
+	GetMobileMoneyImport(ctx context.Context, id string) (models.MobileMoneyImport, error)
+	GetAllMobileMoneyImports(ctx context.Context) ([]models.MobileMoneyImport, error)
+	DeleteMobileMoneyImport(ctx context.Context, id string) error
+	UpdateMobileMoneyLine(ctx context.Context, line models.MobileMoneyLine) (models.MobileMoneyLine, error)
+	PostMobileMoneyLine(ctx context.Context, line models.MobileMoneyLine, txn models.Transaction, receipt models.Receipt) (models.MobileMoneyLine, models.Receipt, error)
+	GetMobileMoneyLine(ctx context.Context, id string) (models.MobileMoneyLine, error)
+	GetMobileMoneyLineByCode(ctx context.Context, provider, transCode string) (models.MobileMoneyLine, error)
+	GetMobileMoneyLinesByImport(ctx context.Context, importID string) ([]models.MobileMoneyLine, error)
+	GetMobileMoneyLinesByStatus(ctx context.Context, status string) ([]models.MobileMoneyLine, error)
+}
+
+// MpesaRepository stores M-Pesa payments received by callback
+type MpesaRepository interface {
+	CreateMpesaTransaction(ctx context.Context, m models.MpesaTransaction) (models.MpesaTransaction, error)
+	UpdateMpesaTransaction(ctx context.Context, m models.MpesaTransaction) (models.MpesaTransaction, error)
+	PostMpesaPayment(ctx context.Context, m models.MpesaTransaction, txn models.Transaction, receipt models.Receipt) (models.MpesaTransaction, models.Receipt, error)
+	GetMpesaTransaction(ctx context.Context, id string) (models.MpesaTransaction, error)
+	GetMpesaTransactionByTransID(ctx context.Context, transID string) (models.MpesaTransaction, error)
+	GetMpesaTransactionsByStatus(ctx context.Context, status string) ([]models.MpesaTransaction, error)
+	GetAllMpesaTransactions(ctx context.Context) ([]models.MpesaTransaction, error)
+}
+
+// PayeeRepository stores payees and what was paid to them
+type PayeeRepository interface {
+	CreatePayee(ctx context.Context, payee models.Payee) (models.Payee, error)
+	UpdatePayee(ctx context.Context, payee models.Payee) (models.Payee, error)
+	DeletePayee(ctx context.Context, id string) error
+	GetPayee(ctx context.Context, id string) (models.Payee, error)
+	GetPayeeByKRAPin(ctx context.Context, kraPin string) (models.Payee, error)
+	GetAllPayees(ctx context.Context) ([]models.Payee, error)
+	SearchPayees(ctx context.Context, searchTerm string) ([]models.Payee, error)
+	GetPayeeSpend(ctx context.Context, startDate, endDate time.Time) ([]models.PayeeSpend, error)
+	GetPayeeSpendByAccount(ctx context.Context, payeeID string, startDate, endDate time.Time) ([]models.PayeeAccountSpend, error)
+	GetPayeePayments(ctx context.Context, payeeID string, startDate, endDate time.Time) ([]models.PayeePayment, error)
+	CountExpendituresByPayee(ctx context.Context, payeeID string) (int, error)
+}
+
+// PledgeRepository stores pledges and the receipts that pay them
+type PledgeRepository interface {
+	CreatePledge(ctx context.Context, pledge models.Pledge) (models.Pledge, error)
+	UpdatePledge(ctx context.Context, pledge models.Pledge) (models.Pledge, error)
+	UpdatePledgeStatus(ctx context.Context, id string, status string) error
+	DeletePledge(ctx context.Context, id string) error
+	GetPledge(ctx context.Context, id string) (models.Pledge, error)
+	GetPledgesByCampaign(ctx context.Context, campaignID string) ([]models.Pledge, error)
+	GetPledgesByMember(ctx context.Context, memberID string) ([]models.Pledge, error)
+	GetOpenPledgesForReceipt(ctx context.Context, memberID, incomeAccountID string) ([]models.Pledge, error)
+	GetPledgeDetailsByCampaign(ctx context.Context, campaignID string) ([]models.PledgeDetail, error)
+	CreatePledgePayment(ctx context.Context, payment models.PledgePayment) (models.PledgePayment, error)
+	GetPledgePaymentsByPledge(ctx context.Context, pledgeID string) ([]models.PledgePayment, error)
+	GetPledgePaymentsByReceipt(ctx context.Context, receiptID string) ([]models.PledgePayment, error)
+	DeletePledgePaymentsByReceipt(ctx context.Context, receiptID string) error
+}
+
+// ReceiptRepository stores receipt lines and the income reports built from them
+type ReceiptRepository interface {
+	CreateReceipt(ctx context.Context, receipt models.Receipt) (models.Receipt, error)
+	UpdateReceipt(ctx context.Context, receipt models.Receipt) (models.Receipt, error)
+	DeleteReceipt(ctx context.Context, id string) error
+	GetReceipt(ctx context.Context, id string) (models.Receipt, error)
+	GetReceiptByTransaction(ctx context.Context, transactionID string) ([]models.Receipt, error)
+	GetReceiptByAccount(ctx context.Context, accountID string) ([]models.Receipt, error)
+	GetReceiptByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.Receipt, error)
+	GetAllReceipts(ctx context.Context) ([]models.Receipt, error)
+	GetTotalReceiptsByAccount(ctx context.Context, accountID string) (float64, error)
+	GetTotalReceiptsByDateRange(ctx context.Context, accountID string, startDate, endDate time.Time) (float64, error)
+	GetTotalReceiptsByTransactionDate(ctx context.Context, accountID string, startDate, endDate time.Time) (float64, error)
+	GetMemberGivingLines(ctx context.Context, memberID string, startDate, endDate time.Time) ([]models.MemberGivingLine, error)
+	GetIncomeLines(ctx context.Context, startDate, endDate time.Time) ([]models.IncomeLine, error)
+}
+
+// RecurringRepository stores recurring transaction templates and their runs
+type RecurringRepository interface {
+	CreateRecurringTemplate(ctx context.Context, template models.RecurringTemplate) (models.RecurringTemplate, error)
+	UpdateRecurringTemplate(ctx context.Context, template models.RecurringTemplate) (models.RecurringTemplate, error)
+	DeleteRecurringTemplate(ctx context.Context, id string) error
+	GetRecurringTemplate(ctx context.Context, id string) (models.RecurringTemplate, error)
+	GetAllRecurringTemplates(ctx context.Context) ([]models.RecurringTemplate, error)
+	GetDueRecurringTemplates(ctx context.Context, now time.Time) ([]models.RecurringTemplate, error)
+	ReplaceRecurringTemplateLines(ctx context.Context, templateID string, lines []models.RecurringTemplateLine) ([]models.RecurringTemplateLine, error)
+	GetRecurringTemplateLines(ctx context.Context, templateID string) ([]models.RecurringTemplateLine, error)
+	ClaimRecurringRun(ctx context.Context, templateID string, scheduledFor time.Time) (models.RecurringRun, bool, error)
+	UpdateRecurringRun(ctx context.Context, run models.RecurringRun) (models.RecurringRun, error)
+	GetRecurringRun(ctx context.Context, id string) (models.RecurringRun, error)
+	GetRecurringRunsByTemplate(ctx context.Context, templateID string) ([]models.RecurringRun, error)
+}
+
+// SetupRepository reports and performs the first-run setup
+type SetupRepository interface {
+	GetSetupStatus(ctx context.Context) (models.SetupStatus, error)
+	SeedChurch(ctx context.Context, church models.Church, admin models.User, groups []models.MembersGroup, accounts []models.Account) (models.Church, models.User, []models.MembersGroup, []models.Account, error)
+}
+
+// SMSNotificationRepository stores the outgoing SMS queue
+type SMSNotificationRepository interface {
+	CreateSMSNotification(ctx context.Context, n models.SMSNotification) (models.SMSNotification, error)
+	UpdateSMSNotification(ctx context.Context, n models.SMSNotification) (models.SMSNotification, error)
+	GetSMSNotification(ctx context.Context, id string) (models.SMSNotification, error)
+	GetSMSNotificationByTransaction(ctx context.Context, transactionID, kind string) (models.SMSNotification, error)
+	GetSMSNotificationByProviderID(ctx context.Context, providerMessageID string) (models.SMSNotification, error)
+	GetDueSMSNotifications(ctx context.Context, asOf time.Time, limit int) ([]models.SMSNotification, error)
+	GetSMSNotificationsByStatus(ctx context.Context, status string) ([]models.SMSNotification, error)
+	GetSMSNotificationsByMember(ctx context.Context, memberID string) ([]models.SMSNotification, error)
+	GetAllSMSNotifications(ctx context.Context) ([]models.SMSNotification, error)
+}
+
+// TransactionRepository stores transactions
+type TransactionRepository interface {
+	CreateTransaction(ctx context.Context, txn models.Transaction) (models.Transaction, error)
+	UpdateTransaction(ctx context.Context, txn models.Transaction) (models.Transaction, error)
+	UpdateTransactionStatus(ctx context.Context, txn models.Transaction) (models.Transaction, error)
+	DeleteTransaction(ctx context.Context, id string) error
+	GetTransaction(ctx context.Context, id string) (models.Transaction, error)
+	GetTransactionByRef(ctx context.Context, ref string) (models.Transaction, error)
+	GetTransactionsByAccount(ctx context.Context, accountID string) ([]models.Transaction, error)
+	GetTransactionsByMember(ctx context.Context, memberID string) ([]models.Transaction, error)
+	GetTransactionsByType(ctx context.Context, txnType string) ([]models.Transaction, error)
+	GetAllTransactions(ctx context.Context) ([]models.Transaction, error)
+	GetTransactionsByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.Transaction, error)
+	GetTransactionsByStatus(ctx context.Context, status string) ([]models.Transaction, error)
+	GetVouchers(ctx context.Context) ([]models.Transaction, error)
+	UpdateTransactionPayment(ctx context.Context, id string, method, reference *string) error
+}
+
+// TransferRepository stores transfer lines
+type TransferRepository interface {
+	CreateTransfer(ctx context.Context, transfer models.Transfer) (models.Transfer, error)
+	UpdateTransfer(ctx context.Context, transfer models.Transfer) (models.Transfer, error)
+	DeleteTransfer(ctx context.Context, id string) error
+	GetTransfer(ctx context.Context, id string) (models.Transfer, error)
+	GetTransferByTransaction(ctx context.Context, transactionID string) ([]models.Transfer, error)
+	GetTransferByCreditAccount(ctx context.Context, accountID string) ([]models.Transfer, error)
+	GetTransferByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.Transfer, error)
+	GetAllTransfers(ctx context.Context) ([]models.Transfer, error)
+	GetTotalTransfersByCreditAccount(ctx context.Context, accountID string) (float64, error)
+	GetTotalTransfersByDateRange(ctx context.Context, accountID string, startDate, endDate time.Time) (float64, error)
+	GetTotalTransfersByTransaction(ctx context.Context, transactionID string) (float64, error)
+}
+
+// UserRepository stores user accounts
+type UserRepository interface {
+	CreateUser(ctx context.Context, user models.User) (models.User, error)
+	UpdateUser(ctx context.Context, user models.User) (models.User, error)
+	DeactivateUser(ctx context.Context, id string) (models.User, error)
+	GetUser(ctx context.Context, id string) (models.User, error)
+	GetUserByUsername(ctx context.Context, username string) (models.User, error)
+	GetUserByEmail(ctx context.Context, email string) (models.User, error)
+	UpdateLastLogin(ctx context.Context, id string) error
+	UpdateUserPassword(ctx context.Context, id, passwordHash string) error
+	DeleteUser(ctx context.Context, id string) error
+	GetAllUsers(ctx context.Context) ([]models.User, error)
+	GetUsersByRole(ctx context.Context, role string) ([]models.User, error)
+	GetActiveUsers(ctx context.Context) ([]models.User, error)
+}
+
+// VoucherRepository stores voucher approvals
+type VoucherRepository interface {
+	CreateVoucherApproval(ctx context.Context, approval models.VoucherApproval) (models.VoucherApproval, error)
+	GetVoucherApprovals(ctx context.Context, transactionID string) ([]models.VoucherApproval, error)
+}
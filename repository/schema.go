@@ -0,0 +1,453 @@
+package repository
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"regexp"
+	"sort"
+	"storeHouse/database"
+	"storeHouse/models"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// The repository sources are embedded so the queries they run can be checked against the
+// database the binary is pointed at
+//
+//go:embed *_repo.go
+var sources embed.FS
+
+// schemaModels maps each table to the model its rows are scanned into
+var schemaModels = map[string]any{
+	"accounts":                 models.Account{},
+	"approval_policies":        models.ApprovalPolicy{},
+	"attachments":              models.Attachment{},
+	"bank_statements":          models.BankStatement{},
+	"bank_statement_lines":     models.BankStatementLine{},
+	"budgets":                  models.Budget{},
+	"campaigns":                models.Campaign{},
+	"cheques":                  models.Cheque{},
+	"churches":                 models.Church{},
+	"church_users":             models.ChurchUser{},
+	"collection_sessions":      models.CollectionSession{},
+	"collection_denominations": models.CollectionDenomination{},
+	"collection_signoffs":      models.CollectionSignOff{},
+	"districts":                models.District{},
+	"district_users":           models.DistrictUser{},
+	"email_outbox":             models.Email{},
+	"email_attachments":        models.EmailAttachment{},
+	"expenditures":             models.Expenditure{},
+	"funds":                    models.Fund{},
+	"imprests":                 models.Imprest{},
+	"petty_cash_vouchers":      models.PettyCashVoucher{},
+	"imprest_replenishments":   models.ImprestReplenishment{},
+	"members":                  models.Member{},
+	"members_groups":           models.MembersGroup{},
+	"mobile_money_imports":     models.MobileMoneyImport{},
+	"mobile_money_lines":       models.MobileMoneyLine{},
+	"mpesa_transactions":       models.MpesaTransaction{},
+	"payees":                   models.Payee{},
+	"pledges":                  models.Pledge{},
+	"pledge_payments":          models.PledgePayment{},
+	"receipts":                 models.Receipt{},
+	"recurring_templates":      models.RecurringTemplate{},
+	"recurring_template_lines": models.RecurringTemplateLine{},
+	"recurring_runs":           models.RecurringRun{},
+	"sms_notifications":        models.SMSNotification{},
+	"transactions":             models.Transaction{},
+	"transfers":                models.Transfer{},
+	"users":                    models.User{},
+	"voucher_approvals":        models.VoucherApproval{},
+}
+
+// computedColumns are model fields the queries fill from expressions rather than columns
+var computedColumns = map[string][]string{
+	"pledges": {"amount_paid"},
+}
+
+// sharedTables hold rows of every church, so they have no church_id of their own
+var sharedTables = map[string]bool{
+	"churches":       true,
+	"districts":      true,
+	"district_users": true,
+	"users":          true,
+}
+
+// SchemaError lists every way the database differs from what the models and queries expect
+type SchemaError struct {
+	Problems []string
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("database schema does not match the code (%d problems):\n  - %s", len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// CheckSchema compares the tables and columns of the database with the db tags of the
+// models and the tables and columns the repository queries use. It returns a SchemaError
+// describing every difference, so the app can refuse to start on a drifted schema.
+func CheckSchema(ctx context.Context, db *sqlx.DB) error {
+	queries, err := repositoryQueries()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var rows []struct {
+		Table  string `db:"table_name"`
+		Column string `db:"column_name"`
+	}
+	query := `SELECT table_name, column_name FROM information_schema.columns WHERE table_schema = current_schema()`
+	if err := db.SelectContext(ctx, &rows, query); err != nil {
+		return err
+	}
+
+	schema := make(map[string]map[string]bool)
+	for _, r := range rows {
+		if schema[r.Table] == nil {
+			schema[r.Table] = make(map[string]bool)
+		}
+		schema[r.Table][r.Column] = true
+	}
+
+	if problems := compareSchema(schema, queries); len(problems) > 0 {
+		return &SchemaError{Problems: problems}
+	}
+	return nil
+}
+
+// compareSchema returns the differences between the schema, given as the columns of each
+// table, and the models and queries
+func compareSchema(schema map[string]map[string]bool, queries []sqlQuery) []string {
+	var problems []string
+	add := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	tables := make([]string, 0, len(schemaModels))
+	for table := range schemaModels {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	for _, table := range tables {
+		model := reflect.TypeOf(schemaModels[table])
+		columns, ok := schema[table]
+		if !ok {
+			add("table %s for models.%s does not exist", table, model.Name())
+			continue
+		}
+
+		tags := dbTags(model)
+		for _, tag := range tags {
+			if !columns[tag] && !contains(computedColumns[table], tag) {
+				add("models.%s has db tag %q but %s has no such column", model.Name(), tag, table)
+			}
+		}
+		for _, column := range sortedKeys(columns) {
+			if !contains(tags, column) {
+				add("%s.%s has no field in models.%s", table, column, model.Name())
+			}
+		}
+		if !sharedTables[table] && !columns["church_id"] {
+			add("%s has no church_id", table)
+		}
+	}
+
+	for _, q := range queries {
+		for _, problem := range q.check(schema) {
+			add("%s: %s", q.pos, problem)
+		}
+	}
+
+	return problems
+}
+
+// dbTags returns the column names a model is scanned from
+func dbTags(model reflect.Type) []string {
+	var tags []string
+	for i := 0; i < model.NumField(); i++ {
+		field := model.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			tags = append(tags, dbTags(field.Type)...)
+			continue
+		}
+		tag := strings.Split(field.Tag.Get("db"), ",")[0]
+		if tag != "" && tag != "-" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// sqlQuery is a query found in the repository sources, with where it was written
+type sqlQuery struct {
+	pos string
+	sql string
+}
+
+var (
+	sqlStart       = regexp.MustCompile(`^\s*(SELECT|INSERT|UPDATE|DELETE|WITH)\b`)
+	quoted         = regexp.MustCompile(`'[^']*'`)
+	notTableFrom   = regexp.MustCompile(`\b(EXTRACT\s*\(\s*\w+|DISTINCT)\s+FROM\b`)
+	cteName        = regexp.MustCompile(`(?:\bWITH(?:\s+RECURSIVE)?|,)\s+([a-z_]\w*)\s+AS\s+\(`)
+	tableRef       = regexp.MustCompile(`\b(?:FROM|JOIN|INTO|UPDATE)\s+([a-z_]\w*)([.(]?)(?:\s+(?:AS\s+)?([a-z_]\w*))?`)
+	columnRef      = regexp.MustCompile(`\b([a-z_]\w*)\.([a-z_]\w*)\b`)
+	insertColumns  = regexp.MustCompile(`\bINSERT\s+INTO\s+([a-z_]\w*)\s*\(([^)]*)\)`)
+	updateSet      = regexp.MustCompile(`(?s)\bUPDATE\s+([a-z_]\w*)(?:\s+[a-z_]\w*)?\s+SET\s+(.*?)(?:\bWHERE\b|\bFROM\b|\bRETURNING\b|$)`)
+	namedParam     = regexp.MustCompile(`(?:^|[^:]):([a-z_]\w*)`)
+	writtenTable   = regexp.MustCompile(`^\s*(?:INSERT\s+INTO|UPDATE)\s+([a-z_]\w*)`)
+	bareComparison = regexp.MustCompile(`(?:^|[\s(,])([a-z_]\w*)\s*(?:=|<>|!=|<=|>=|<|>|\bIN\b|\bIS\b|\bLIKE\b|\bILIKE\b|\bBETWEEN\b)`)
+)
+
+// check returns the tables, columns and named parameters of the query that the schema or
+// the models lack
+func (q sqlQuery) check(schema map[string]map[string]bool) []string {
+	var problems []string
+	sql := notTableFrom.ReplaceAllString(quoted.ReplaceAllString(q.sql, "''"), "$1")
+
+	ctes := make(map[string]bool)
+	for _, m := range cteName.FindAllStringSubmatch(sql, -1) {
+		ctes[m[1]] = true
+	}
+
+	// Aliases may be reused across subqueries, so each stands for every table it names;
+	// one that also names a CTE cannot be checked
+	aliases := make(map[string][]string)
+	tables := make(map[string]bool)
+	unchecked := make(map[string]bool)
+	for _, m := range tableRef.FindAllStringSubmatch(sql, -1) {
+		table, next, alias := m[1], m[2], m[3]
+		if ctes[table] {
+			unchecked[table], unchecked[alias] = true, true
+			continue
+		}
+		if next != "" {
+			continue
+		}
+		if _, ok := schema[table]; !ok {
+			problems = append(problems, fmt.Sprintf("table %s does not exist", table))
+			continue
+		}
+		tables[table] = true
+		aliases[table] = append(aliases[table], table)
+		if alias != "" {
+			aliases[alias] = append(aliases[alias], table)
+		}
+	}
+
+	for _, m := range columnRef.FindAllStringSubmatch(sql, -1) {
+		alias, column := m[1], m[2]
+		candidates, ok := aliases[alias]
+		if !ok || unchecked[alias] {
+			continue
+		}
+		found := false
+		for _, table := range candidates {
+			found = found || schema[table][column]
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("column %s.%s does not exist in %s", alias, column, strings.Join(candidates, " or ")))
+		}
+	}
+
+	var written []string
+	for _, m := range insertColumns.FindAllStringSubmatch(sql, -1) {
+		for _, column := range strings.Split(m[2], ",") {
+			written = append(written, m[1]+"."+strings.TrimSpace(column))
+		}
+	}
+	for _, m := range updateSet.FindAllStringSubmatch(sql, -1) {
+		for _, assignment := range splitTopLevel(m[2]) {
+			if column, _, ok := strings.Cut(assignment, "="); ok {
+				written = append(written, m[1]+"."+strings.TrimSpace(column))
+			}
+		}
+	}
+
+	// A query on a single table may name its columns without an alias
+	if len(tables) == 1 && len(ctes) == 0 && !strings.Contains(sql, "(SELECT") {
+		for table := range tables {
+			if _, where, ok := strings.Cut(sql, "WHERE"); ok {
+				for _, m := range bareComparison.FindAllStringSubmatch(where, -1) {
+					written = append(written, table+"."+m[1])
+				}
+			}
+		}
+	}
+
+	for _, ref := range written {
+		table, column, _ := strings.Cut(ref, ".")
+		if columns, ok := schema[table]; ok && !columns[column] {
+			problems = append(problems, fmt.Sprintf("column %s does not exist in %s", column, table))
+		}
+	}
+
+	if m := writtenTable.FindStringSubmatch(sql); m != nil {
+		if model, ok := schemaModels[m[1]]; ok {
+			tags := dbTags(reflect.TypeOf(model))
+			for _, p := range namedParam.FindAllStringSubmatch(sql, -1) {
+				if !contains(tags, p[1]) {
+					problems = append(problems, fmt.Sprintf("named parameter :%s is not a db tag of models.%s", p[1], reflect.TypeOf(model).Name()))
+				}
+			}
+		}
+	}
+
+	return unique(problems)
+}
+
+// splitTopLevel splits a list on the commas outside parentheses
+func splitTopLevel(list string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range list {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, list[start:])
+}
+
+// repositoryQueries returns the SQL written in the repository sources, parsed once
+var repositoryQueries = sync.OnceValues(func() ([]sqlQuery, error) {
+	names, err := sources.ReadDir(".")
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	consts := make(map[string]string)
+	for _, entry := range names {
+		src, err := sources.ReadFile(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		file, err := parser.ParseFile(fset, entry.Name(), src, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				value := spec.(*ast.ValueSpec)
+				for i, name := range value.Names {
+					if i < len(value.Values) {
+						if s, ok := stringValue(value.Values[i], consts); ok {
+							consts[name.Name] = s
+						}
+					}
+				}
+			}
+		}
+	}
+
+	var queries []sqlQuery
+	for _, file := range files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			expr, ok := n.(ast.Expr)
+			if !ok {
+				return true
+			}
+			switch expr.(type) {
+			case *ast.BasicLit, *ast.BinaryExpr:
+			default:
+				return true
+			}
+			s, ok := stringValue(expr, consts)
+			if !ok {
+				return true
+			}
+			if sqlStart.MatchString(s) {
+				queries = append(queries, sqlQuery{pos: fset.Position(expr.Pos()).String(), sql: s})
+			}
+			return false
+		})
+	}
+
+	return queries, nil
+})
+
+// stringValue folds a string literal, a string constant, or a concatenation of them
+func stringValue(expr ast.Expr, consts map[string]string) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		s, err := strconv.Unquote(e.Value)
+		return s, err == nil
+	case *ast.Ident:
+		s, ok := consts[e.Name]
+		return s, ok
+	case *ast.ParenExpr:
+		return stringValue(e.X, consts)
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+		left, ok := stringValue(e.X, consts)
+		if !ok {
+			return "", false
+		}
+		right, ok := stringValue(e.Y, consts)
+		if !ok {
+			return "", false
+		}
+		return left + right, true
+	}
+	return "", false
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func unique(list []string) []string {
+	seen := make(map[string]bool, len(list))
+	var kept []string
+	for _, item := range list {
+		if !seen[item] {
+			seen[item] = true
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
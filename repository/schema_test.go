@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"go/parser"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// modelSchema is the schema the models describe: every db tag as a column, with church_id
+// on the church-owned tables
+func modelSchema() map[string]map[string]bool {
+	schema := make(map[string]map[string]bool)
+	for table, model := range schemaModels {
+		columns := make(map[string]bool)
+		for _, tag := range dbTags(reflect.TypeOf(model)) {
+			if !contains(computedColumns[table], tag) {
+				columns[tag] = true
+			}
+		}
+		if !sharedTables[table] {
+			columns["church_id"] = true
+		}
+		schema[table] = columns
+	}
+	return schema
+}
+
+func TestQueryCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want []string
+	}{
+		{
+			name: "bare columns of a single table",
+			sql:  "SELECT * FROM funds WHERE code = $1 AND is_active = true",
+		},
+		{
+			name: "missing bare column",
+			sql:  "SELECT * FROM funds WHERE kode = $1",
+			want: []string{"column kode does not exist in funds"},
+		},
+		{
+			name: "missing table",
+			sql:  "SELECT * FROM fundz WHERE id = $1",
+			want: []string{"table fundz does not exist"},
+		},
+		{
+			name: "aliased columns",
+			sql:  "SELECT t.id, r.amount FROM transactions t JOIN receipts AS r ON r.transaction_id = t.id",
+		},
+		{
+			name: "missing aliased column",
+			sql:  "SELECT t.id, r.amout FROM transactions t JOIN receipts r ON r.transaction_id = t.id",
+			want: []string{"column r.amout does not exist in receipts"},
+		},
+		{
+			name: "alias reused in a subquery",
+			sql:  "SELECT a.id FROM accounts a WHERE a.id IN (SELECT a.account FROM budgets a)",
+		},
+		{
+			name: "recursive CTE",
+			sql: `WITH RECURSIVE subtree AS (SELECT id FROM accounts WHERE id = $1
+				  UNION ALL SELECT a.id FROM accounts a JOIN subtree s ON a.parent = s.id) SELECT id FROM subtree`,
+		},
+		{
+			name: "CTE over a missing column",
+			sql:  "WITH open AS (SELECT p.id FROM pledges p WHERE p.state = 'open') SELECT o.id FROM open o",
+			want: []string{"column p.state does not exist in pledges"},
+		},
+		{
+			name: "insert with returning",
+			sql:  "INSERT INTO funds (id, code, name) VALUES (:id, :code, :name) RETURNING id",
+		},
+		{
+			name: "insert of a missing column",
+			sql:  "INSERT INTO funds (id, kode) VALUES (:id, :kode) RETURNING id",
+			want: []string{"column kode does not exist in funds", "named parameter :kode is not a db tag of models.Fund"},
+		},
+		{
+			name: "update with returning",
+			sql:  "UPDATE funds SET name = :name, updated_at = :updated_at WHERE id = :id RETURNING id",
+		},
+		{
+			name: "update of a missing column",
+			sql:  "UPDATE funds SET nam = $1, is_active = COALESCE($2, is_active) WHERE id = $3 RETURNING id",
+			want: []string{"column nam does not exist in funds"},
+		},
+		{
+			name: "quoted text and EXTRACT are not table references",
+			sql:  "SELECT t.id FROM transactions t WHERE t.notes <> 'from x.y' AND EXTRACT(YEAR FROM t.transaction_date) = $1",
+		},
+	}
+
+	schema := modelSchema()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sqlQuery{pos: "test", sql: tt.sql}.check(schema)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got problems %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(schema map[string]map[string]bool)
+		want   []string
+	}{
+		{
+			name:   "matching schema",
+			change: func(map[string]map[string]bool) {},
+		},
+		{
+			name:   "missing table",
+			change: func(schema map[string]map[string]bool) { delete(schema, "funds") },
+			want:   []string{"table funds for models.Fund does not exist"},
+		},
+		{
+			name:   "missing column",
+			change: func(schema map[string]map[string]bool) { delete(schema["funds"], "description") },
+			want:   []string{`models.Fund has db tag "description" but funds has no such column`},
+		},
+		{
+			name:   "column without a field",
+			change: func(schema map[string]map[string]bool) { schema["funds"]["legacy_code"] = true },
+			want:   []string{"funds.legacy_code has no field in models.Fund"},
+		},
+		{
+			name:   "church table without church_id",
+			change: func(schema map[string]map[string]bool) { delete(schema["payees"], "church_id") },
+			want:   []string{`models.Payee has db tag "church_id" but payees has no such column`, "payees has no church_id"},
+		},
+		{
+			name:   "shared table without church_id",
+			change: func(schema map[string]map[string]bool) { delete(schema["district_users"], "church_id") },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := modelSchema()
+			tt.change(schema)
+			if got := compareSchema(schema, nil); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got problems %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// The repository's own queries only use the tables and columns the models describe
+func TestRepositoryQueriesMatchModels(t *testing.T) {
+	queries, err := repositoryQueries()
+	if err != nil {
+		t.Fatalf("read repository queries: %v", err)
+	}
+	if len(queries) == 0 {
+		t.Fatal("found no repository queries")
+	}
+	for _, problem := range compareSchema(modelSchema(), queries) {
+		t.Error(problem)
+	}
+}
+
+func TestStringValue(t *testing.T) {
+	consts := map[string]string{"subtree": "SELECT id FROM accounts"}
+	tests := []struct {
+		expr string
+		want string
+		ok   bool
+	}{
+		{expr: `"SELECT 1"`, want: "SELECT 1", ok: true},
+		{expr: "`SELECT *\n FROM funds`", want: "SELECT *\n FROM funds", ok: true},
+		{expr: `subtree`, want: "SELECT id FROM accounts", ok: true},
+		{expr: `"SELECT * FROM budgets WHERE account IN (" + subtree + ")"`, want: "SELECT * FROM budgets WHERE account IN (SELECT id FROM accounts)", ok: true},
+		{expr: `("SELECT " + "1")`, want: "SELECT 1", ok: true},
+		{expr: `query`},
+		{expr: `42`},
+		{expr: `"SELECT " + name`},
+	}
+
+	for _, tt := range tests {
+		expr, err := parser.ParseExpr(tt.expr)
+		if err != nil {
+			t.Fatalf("parse %s: %v", tt.expr, err)
+		}
+		got, ok := stringValue(expr, consts)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("stringValue(%s) = %q, %v; want %q, %v", strings.ReplaceAll(tt.expr, "\n", `\n`), got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"storeHouse/database"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetSetupStatus counts the users and churches already in the database
+func (p *Postgres) GetSetupStatus(ctx context.Context) (models.SetupStatus, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var status models.SetupStatus
+	query := `SELECT (SELECT COUNT(*) FROM users) AS users, (SELECT COUNT(*) FROM churches) AS churches`
+	err := p.DB.GetContext(ctx, &status, query)
+	if err != nil {
+		return models.SetupStatus{}, err
+	}
+
+	status.Completed = status.Users > 0
+	return status, nil
+}
+
+// SeedChurch creates the first church, its admin, the member groups and the chart of
+// accounts in one transaction. Accounts keep the IDs they are given so children can point
+// at their parents, and are inserted in order, parents first.
+func (p *Postgres) SeedChurch(ctx context.Context, church models.Church, admin models.User, groups []models.MembersGroup, accounts []models.Account) (models.Church, models.User, []models.MembersGroup, []models.Account, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return models.Church{}, models.User{}, nil, nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	church.ID = uuid.New().String()
+	church.CreatedAt = now
+	church.UpdatedAt = now
+	churchQuery := `INSERT INTO churches (id, name, code, mpesa_shortcode, is_active, created_at, updated_at)
+              VALUES (:id, :name, :code, :mpesa_shortcode, :is_active, :created_at, :updated_at)`
+	if _, err := tx.NamedExecContext(ctx, churchQuery, church); err != nil {
+		return models.Church{}, models.User{}, nil, nil, err
+	}
+
+	// The church's rows are written under its row level security policy
+	if _, err := tx.ExecContext(ctx, `SELECT set_config('app.church_id', $1, true)`, church.ID); err != nil {
+		return models.Church{}, models.User{}, nil, nil, err
+	}
+
+	admin.ID = uuid.New().String()
+	admin.CreatedAt = now
+	admin.UpdatedAt = now
+	admin.CreatedIn = &church.ID
+	userQuery := `INSERT INTO users (id, username, email, password_hash, full_name, role, phone_number, is_active, created_at, updated_at)
+              VALUES (:id, :username, :email, :password_hash, :full_name, :role, :phone_number, :is_active, :created_at, :updated_at)`
+	if _, err := tx.NamedExecContext(ctx, userQuery, admin); err != nil {
+		return models.Church{}, models.User{}, nil, nil, err
+	}
+
+	memberQuery := `INSERT INTO church_users (id, church_id, user_id, role, created_at) VALUES ($1, $2, $3, $4, $5)`
+	if _, err := tx.ExecContext(ctx, memberQuery, uuid.New().String(), church.ID, admin.ID, string(models.RoleAdmin), now); err != nil {
+		return models.Church{}, models.User{}, nil, nil, err
+	}
+
+	groupQuery := `INSERT INTO members_groups (id, church_id, group_name, notes, created_by, created_at, updated_at)
+              VALUES (:id, :church_id, :group_name, :notes, :created_by, :created_at, :updated_at)`
+	for i := range groups {
+		groups[i].ID = uuid.New().String()
+		groups[i].ChurchID = church.ID
+		groups[i].CreatedBy = admin.ID
+		groups[i].CreatedAt = now
+		groups[i].UpdatedAt = now
+		if _, err := tx.NamedExecContext(ctx, groupQuery, groups[i]); err != nil {
+			return models.Church{}, models.User{}, nil, nil, err
+		}
+	}
+
+	accountQuery := `INSERT INTO accounts (id, church_id, account_name, account_type, code, parent, is_header, local_share, notes, is_active, created_by, created_at, updated_at)
+              VALUES (:id, :church_id, :account_name, :account_type, :code, :parent, :is_header, :local_share, :notes, :is_active, :created_by, :created_at, :updated_at)`
+	for i := range accounts {
+		accounts[i].ChurchID = church.ID
+		accounts[i].CreatedBy = admin.ID
+		accounts[i].CreatedAt = now
+		accounts[i].UpdatedAt = now
+		if _, err := tx.NamedExecContext(ctx, accountQuery, accounts[i]); err != nil {
+			return models.Church{}, models.User{}, nil, nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Church{}, models.User{}, nil, nil, err
+	}
+
+	return church, admin, groups, accounts, nil
+}
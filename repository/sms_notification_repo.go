@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"storeHouse/database"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (p *Postgres) executeSMSNotificationQuery(ctx context.Context, query string, n models.SMSNotification) (models.SMSNotification, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, n)
+	if err != nil {
+		return models.SMSNotification{}, err
+	}
+
+	return n, nil
+}
+
+func (p *Postgres) CreateSMSNotification(ctx context.Context, n models.SMSNotification) (models.SMSNotification, error) {
+	n.ID = uuid.New().String()
+	n.CreatedAt = time.Now()
+	n.UpdatedAt = time.Now()
+
+	query := `INSERT INTO sms_notifications (id, kind, member, phone_number, transaction_id, message, status, attempts, send_after, created_at, updated_at)
+              VALUES (:id, :kind, :member, :phone_number, :transaction_id, :message, :status, :attempts, :send_after, :created_at, :updated_at)`
+
+	return p.executeSMSNotificationQuery(ctx, query, n)
+}
+
+func (p *Postgres) UpdateSMSNotification(ctx context.Context, n models.SMSNotification) (models.SMSNotification, error) {
+	n.UpdatedAt = time.Now()
+
+	query := `UPDATE sms_notifications SET message = :message, status = :status, provider = :provider, provider_message_id = :provider_message_id, attempts = :attempts, last_error = :last_error, send_after = :send_after, sent_at = :sent_at, delivered_at = :delivered_at, updated_at = :updated_at
+			  WHERE id = :id`
+
+	return p.executeSMSNotificationQuery(ctx, query, n)
+}
+
+func (p *Postgres) GetSMSNotification(ctx context.Context, id string) (models.SMSNotification, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var n models.SMSNotification
+	err := p.DB.GetContext(ctx, &n, "SELECT * FROM sms_notifications WHERE id = $1", id)
+	if err != nil {
+		return models.SMSNotification{}, err
+	}
+
+	return n, nil
+}
+
+func (p *Postgres) GetSMSNotificationByTransaction(ctx context.Context, transactionID, kind string) (models.SMSNotification, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var n models.SMSNotification
+	err := p.DB.GetContext(ctx, &n, "SELECT * FROM sms_notifications WHERE transaction_id = $1 AND kind = $2", transactionID, kind)
+	if err != nil {
+		return models.SMSNotification{}, err
+	}
+
+	return n, nil
+}
+
+func (p *Postgres) GetSMSNotificationByProviderID(ctx context.Context, providerMessageID string) (models.SMSNotification, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var n models.SMSNotification
+	err := p.DB.GetContext(ctx, &n, "SELECT * FROM sms_notifications WHERE provider_message_id = $1", providerMessageID)
+	if err != nil {
+		return models.SMSNotification{}, err
+	}
+
+	return n, nil
+}
+
+// GetDueSMSNotifications returns queued notifications whose send time has passed
+func (p *Postgres) GetDueSMSNotifications(ctx context.Context, asOf time.Time, limit int) ([]models.SMSNotification, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var notifications []models.SMSNotification
+	err := p.DB.SelectContext(ctx, &notifications, `SELECT * FROM sms_notifications WHERE status = 'queued' AND send_after <= $1
+			  ORDER BY send_after LIMIT $2`, asOf, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func (p *Postgres) GetSMSNotificationsByStatus(ctx context.Context, status string) ([]models.SMSNotification, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var notifications []models.SMSNotification
+	err := p.DB.SelectContext(ctx, &notifications, "SELECT * FROM sms_notifications WHERE status = $1 ORDER BY created_at DESC", status)
+	if err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func (p *Postgres) GetSMSNotificationsByMember(ctx context.Context, memberID string) ([]models.SMSNotification, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var notifications []models.SMSNotification
+	err := p.DB.SelectContext(ctx, &notifications, "SELECT * FROM sms_notifications WHERE member = $1 ORDER BY created_at DESC", memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func (p *Postgres) GetAllSMSNotifications(ctx context.Context) ([]models.SMSNotification, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var notifications []models.SMSNotification
+	err := p.DB.SelectContext(ctx, &notifications, "SELECT * FROM sms_notifications ORDER BY created_at DESC")
+	if err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
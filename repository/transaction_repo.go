@@ -1,15 +1,20 @@
 package repository
 
 import (
+	"context"
+	"storeHouse/database"
 	"storeHouse/models"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
-func executeTransactionQuery(db *sqlx.DB, query string, txn models.Transaction) (models.Transaction, error) {
-	_, err := db.NamedExec(query, txn)
+func (p *Postgres) executeTransactionQuery(ctx context.Context, query string, txn models.Transaction) (models.Transaction, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, txn)
 	if err != nil {
 		return models.Transaction{}, err
 	}
@@ -17,7 +22,7 @@ func executeTransactionQuery(db *sqlx.DB, query string, txn models.Transaction)
 	return txn, nil
 }
 
-func CreateTransaction(db *sqlx.DB, txn models.Transaction) (models.Transaction, error) {
+func (p *Postgres) CreateTransaction(ctx context.Context, txn models.Transaction) (models.Transaction, error) {
 	txn.ID = uuid.New().String()
 
 	// Set default transaction date if not provided
@@ -25,32 +30,57 @@ func CreateTransaction(db *sqlx.DB, txn models.Transaction) (models.Transaction,
 		txn.TransactionDate = time.Now()
 	}
 
+	// Anything not going through the voucher workflow is in the books immediately
+	if txn.Status == "" {
+		txn.Status = string(models.TransactionPosted)
+	}
+
 	txn.CreatedAt = time.Now()
 	txn.UpdatedAt = time.Now()
 
-	query := `INSERT INTO transactions (id, transaction_ref, transaction_date, transaction_type, amount, notes, debit_account, member, created_by, created_at, updated_at)
-              VALUES (:id, :transaction_ref, :transaction_date, :transaction_type, :amount, :notes, :debit_account_id, :member_id, :created_by, :created_at, :updated_at)`
+	query := `INSERT INTO transactions (id, transaction_ref, transaction_date, transaction_type, amount, notes, debit_account, member, payment_method, payment_reference, collection_session, status, created_by, created_at, updated_at)
+              VALUES (:id, :transaction_ref, :transaction_date, :transaction_type, :amount, :notes, :debit_account, :member, :payment_method, :payment_reference, :collection_session, :status, :created_by, :created_at, :updated_at)`
+
+	return p.executeTransactionQuery(ctx, query, txn)
+}
+
+func (p *Postgres) UpdateTransaction(ctx context.Context, txn models.Transaction) (models.Transaction, error) {
+	txn.UpdatedAt = time.Now()
+
+	query := `UPDATE transactions SET transaction_ref = :transaction_ref, transaction_date = :transaction_date, transaction_type = :transaction_type, amount = :amount, notes = :notes, debit_account = :debit_account, member = :member, payment_method = :payment_method, payment_reference = :payment_reference, updated_at = :updated_at 
+			  WHERE id = :id`
 
-	return executeTransactionQuery(db, query, txn)
+	return p.executeTransactionQuery(ctx, query, txn)
 }
 
-func UpdateTransaction(db *sqlx.DB, txn models.Transaction) (models.Transaction, error) {
+// UpdateTransactionStatus moves a transaction through the approval workflow
+func (p *Postgres) UpdateTransactionStatus(ctx context.Context, txn models.Transaction) (models.Transaction, error) {
 	txn.UpdatedAt = time.Now()
+	if txn.RequiredRoles == nil {
+		txn.RequiredRoles = pq.StringArray{}
+	}
 
-	query := `UPDATE transactions SET transaction_ref = :transaction_ref, transaction_date = :transaction_date, transaction_type = :transaction_type, amount = :amount, notes = :notes, debit_account = :debit_account_id, member = :member_id, updated_at = :updated_at 
+	query := `UPDATE transactions SET status = :status, submitted_by = :submitted_by, submitted_at = :submitted_at, posted_by = :posted_by, posted_at = :posted_at,
+			  required_approvals = :required_approvals, required_roles = :required_roles, updated_at = :updated_at
 			  WHERE id = :id`
 
-	return executeTransactionQuery(db, query, txn)
+	return p.executeTransactionQuery(ctx, query, txn)
 }
 
-func DeleteTransaction(db *sqlx.DB, id string) error {
-	_, err := db.Exec("DELETE FROM transactions WHERE id = $1", id)
+func (p *Postgres) DeleteTransaction(ctx context.Context, id string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM transactions WHERE id = $1", id)
 	return err
 }
 
-func GetTransaction(db *sqlx.DB, id string) (models.Transaction, error) {
+func (p *Postgres) GetTransaction(ctx context.Context, id string) (models.Transaction, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var txn models.Transaction
-	err := db.Get(&txn, "SELECT * FROM transactions WHERE id = $1", id)
+	err := p.DB.GetContext(ctx, &txn, "SELECT * FROM transactions WHERE id = $1", id)
 	if err != nil {
 		return models.Transaction{}, err
 	}
@@ -58,9 +88,12 @@ func GetTransaction(db *sqlx.DB, id string) (models.Transaction, error) {
 	return txn, nil
 }
 
-func GetTransactionByRef(db *sqlx.DB, ref string) (models.Transaction, error) {
+func (p *Postgres) GetTransactionByRef(ctx context.Context, ref string) (models.Transaction, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var txn models.Transaction
-	err := db.Get(&txn, "SELECT * FROM transactions WHERE transaction_ref = $1", ref)
+	err := p.DB.GetContext(ctx, &txn, "SELECT * FROM transactions WHERE transaction_ref = $1", ref)
 	if err != nil {
 		return models.Transaction{}, err
 	}
@@ -68,9 +101,38 @@ func GetTransactionByRef(db *sqlx.DB, ref string) (models.Transaction, error) {
 	return txn, nil
 }
 
-func GetTransactionsByAccount(db *sqlx.DB, accountID string) ([]models.Transaction, error) {
+func (p *Postgres) GetTransactionsByAccount(ctx context.Context, accountID string) ([]models.Transaction, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var txns []models.Transaction
+	err := p.DB.SelectContext(ctx, &txns, "SELECT * FROM transactions WHERE debit_account IN ("+accountSubtree+") ORDER BY transaction_date DESC", accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return txns, nil
+}
+
+func (p *Postgres) GetTransactionsByMember(ctx context.Context, memberID string) ([]models.Transaction, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var txns []models.Transaction
+	err := p.DB.SelectContext(ctx, &txns, "SELECT * FROM transactions WHERE member = $1 ORDER BY transaction_date DESC", memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	return txns, nil
+}
+
+func (p *Postgres) GetTransactionsByType(ctx context.Context, txnType string) ([]models.Transaction, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var txns []models.Transaction
-	err := db.Select(&txns, "SELECT * FROM transactions WHERE debit_account = $1 ORDER BY transaction_date DESC", accountID)
+	err := p.DB.SelectContext(ctx, &txns, "SELECT * FROM transactions WHERE transaction_type = $1 ORDER BY transaction_date DESC", txnType)
 	if err != nil {
 		return nil, err
 	}
@@ -78,9 +140,12 @@ func GetTransactionsByAccount(db *sqlx.DB, accountID string) ([]models.Transacti
 	return txns, nil
 }
 
-func GetTransactionsByMember(db *sqlx.DB, memberID string) ([]models.Transaction, error) {
+func (p *Postgres) GetAllTransactions(ctx context.Context) ([]models.Transaction, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var txns []models.Transaction
-	err := db.Select(&txns, "SELECT * FROM transactions WHERE member = $1 ORDER BY transaction_date DESC", memberID)
+	err := p.DB.SelectContext(ctx, &txns, "SELECT * FROM transactions ORDER BY transaction_date DESC")
 	if err != nil {
 		return nil, err
 	}
@@ -88,9 +153,12 @@ func GetTransactionsByMember(db *sqlx.DB, memberID string) ([]models.Transaction
 	return txns, nil
 }
 
-func GetTransactionsByType(db *sqlx.DB, txnType string) ([]models.Transaction, error) {
+func (p *Postgres) GetTransactionsByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.Transaction, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var txns []models.Transaction
-	err := db.Select(&txns, "SELECT * FROM transactions WHERE transaction_type = $1 ORDER BY transaction_date DESC", txnType)
+	err := p.DB.SelectContext(ctx, &txns, "SELECT * FROM transactions WHERE transaction_date BETWEEN $1 AND $2 ORDER BY transaction_date DESC", startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
@@ -98,9 +166,12 @@ func GetTransactionsByType(db *sqlx.DB, txnType string) ([]models.Transaction, e
 	return txns, nil
 }
 
-func GetAllTransactions(db *sqlx.DB) ([]models.Transaction, error) {
+func (p *Postgres) GetTransactionsByStatus(ctx context.Context, status string) ([]models.Transaction, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var txns []models.Transaction
-	err := db.Select(&txns, "SELECT * FROM transactions ORDER BY transaction_date DESC")
+	err := p.DB.SelectContext(ctx, &txns, "SELECT * FROM transactions WHERE status = $1 ORDER BY transaction_date DESC", status)
 	if err != nil {
 		return nil, err
 	}
@@ -108,12 +179,27 @@ func GetAllTransactions(db *sqlx.DB) ([]models.Transaction, error) {
 	return txns, nil
 }
 
-func GetTransactionsByDateRange(db *sqlx.DB, startDate, endDate time.Time) ([]models.Transaction, error) {
+// GetVouchers returns expense, withdrawal and transfer transactions, newest first
+func (p *Postgres) GetVouchers(ctx context.Context) ([]models.Transaction, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var txns []models.Transaction
-	err := db.Select(&txns, "SELECT * FROM transactions WHERE transaction_date BETWEEN $1 AND $2 ORDER BY transaction_date DESC", startDate, endDate)
+	query := `SELECT * FROM transactions WHERE transaction_type IN ('expenses', 'withdrawal', 'transfer')
+			  ORDER BY transaction_date DESC`
+	err := p.DB.SelectContext(ctx, &txns, query)
 	if err != nil {
 		return nil, err
 	}
 
 	return txns, nil
 }
+
+// UpdateTransactionPayment records how a transaction was paid, whatever its status
+func (p *Postgres) UpdateTransactionPayment(ctx context.Context, id string, method, reference *string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "UPDATE transactions SET payment_method = $2, payment_reference = $3, updated_at = $4 WHERE id = $1", id, method, reference, time.Now())
+	return err
+}
@@ -1,15 +1,19 @@
 package repository
 
 import (
+	"context"
+	"storeHouse/database"
 	"storeHouse/models"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 )
 
-func executeTransferQuery(db *sqlx.DB, query string, transfer models.Transfer) (models.Transfer, error) {
-	_, err := db.NamedExec(query, transfer)
+func (p *Postgres) executeTransferQuery(ctx context.Context, query string, transfer models.Transfer) (models.Transfer, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, transfer)
 	if err != nil {
 		return models.Transfer{}, err
 	}
@@ -17,34 +21,40 @@ func executeTransferQuery(db *sqlx.DB, query string, transfer models.Transfer) (
 	return transfer, nil
 }
 
-func CreateTransfer(db *sqlx.DB, transfer models.Transfer) (models.Transfer, error) {
+func (p *Postgres) CreateTransfer(ctx context.Context, transfer models.Transfer) (models.Transfer, error) {
 	transfer.ID = uuid.New().String()
 	transfer.CreatedAt = time.Now()
 	transfer.UpdatedAt = time.Now()
 
-	query := `INSERT INTO transfers (id, transaction_id, perticulars, credit_account, amount, created_at, updated_at)
-              VALUES (:id, :transaction_id, :particulars, :credit_account_id, :amount, :created_at, :updated_at)`
+	query := `INSERT INTO transfers (id, transaction_id, particulars, credit_account, fund, amount, created_at, updated_at)
+              VALUES (:id, :transaction_id, :particulars, :credit_account, :fund, :amount, :created_at, :updated_at)`
 
-	return executeTransferQuery(db, query, transfer)
+	return p.executeTransferQuery(ctx, query, transfer)
 }
 
-func UpdateTransfer(db *sqlx.DB, transfer models.Transfer) (models.Transfer, error) {
+func (p *Postgres) UpdateTransfer(ctx context.Context, transfer models.Transfer) (models.Transfer, error) {
 	transfer.UpdatedAt = time.Now()
 
-	query := `UPDATE transfers SET perticulars = :particulars, credit_account = :credit_account_id, amount = :amount, updated_at = :updated_at 
+	query := `UPDATE transfers SET particulars = :particulars, credit_account = :credit_account, fund = :fund, amount = :amount, updated_at = :updated_at 
 			  WHERE id = :id`
 
-	return executeTransferQuery(db, query, transfer)
+	return p.executeTransferQuery(ctx, query, transfer)
 }
 
-func DeleteTransfer(db *sqlx.DB, id string) error {
-	_, err := db.Exec("DELETE FROM transfers WHERE id = $1", id)
+func (p *Postgres) DeleteTransfer(ctx context.Context, id string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM transfers WHERE id = $1", id)
 	return err
 }
 
-func GetTransfer(db *sqlx.DB, id string) (models.Transfer, error) {
+func (p *Postgres) GetTransfer(ctx context.Context, id string) (models.Transfer, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var transfer models.Transfer
-	err := db.Get(&transfer, "SELECT * FROM transfers WHERE id = $1", id)
+	err := p.DB.GetContext(ctx, &transfer, "SELECT * FROM transfers WHERE id = $1", id)
 	if err != nil {
 		return models.Transfer{}, err
 	}
@@ -52,9 +62,12 @@ func GetTransfer(db *sqlx.DB, id string) (models.Transfer, error) {
 	return transfer, nil
 }
 
-func GetTransferByTransaction(db *sqlx.DB, transactionID string) ([]models.Transfer, error) {
+func (p *Postgres) GetTransferByTransaction(ctx context.Context, transactionID string) ([]models.Transfer, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var transfers []models.Transfer
-	err := db.Select(&transfers, "SELECT * FROM transfers WHERE transaction_id = $1 ORDER BY created_at DESC", transactionID)
+	err := p.DB.SelectContext(ctx, &transfers, "SELECT * FROM transfers WHERE transaction_id = $1 ORDER BY created_at DESC", transactionID)
 	if err != nil {
 		return nil, err
 	}
@@ -62,9 +75,12 @@ func GetTransferByTransaction(db *sqlx.DB, transactionID string) ([]models.Trans
 	return transfers, nil
 }
 
-func GetTransferByCreditAccount(db *sqlx.DB, accountID string) ([]models.Transfer, error) {
+func (p *Postgres) GetTransferByCreditAccount(ctx context.Context, accountID string) ([]models.Transfer, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var transfers []models.Transfer
-	err := db.Select(&transfers, "SELECT * FROM transfers WHERE credit_account = $1 ORDER BY created_at DESC", accountID)
+	err := p.DB.SelectContext(ctx, &transfers, "SELECT * FROM transfers WHERE credit_account IN ("+accountSubtree+") ORDER BY created_at DESC", accountID)
 	if err != nil {
 		return nil, err
 	}
@@ -72,9 +88,12 @@ func GetTransferByCreditAccount(db *sqlx.DB, accountID string) ([]models.Transfe
 	return transfers, nil
 }
 
-func GetTransferByDateRange(db *sqlx.DB, startDate, endDate time.Time) ([]models.Transfer, error) {
+func (p *Postgres) GetTransferByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.Transfer, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var transfers []models.Transfer
-	err := db.Select(&transfers, "SELECT * FROM transfers WHERE created_at BETWEEN $1 AND $2 ORDER BY created_at DESC", startDate, endDate)
+	err := p.DB.SelectContext(ctx, &transfers, "SELECT * FROM transfers WHERE created_at BETWEEN $1 AND $2 ORDER BY created_at DESC", startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
@@ -82,9 +101,12 @@ func GetTransferByDateRange(db *sqlx.DB, startDate, endDate time.Time) ([]models
 	return transfers, nil
 }
 
-func GetAllTransfers(db *sqlx.DB) ([]models.Transfer, error) {
+func (p *Postgres) GetAllTransfers(ctx context.Context) ([]models.Transfer, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var transfers []models.Transfer
-	err := db.Select(&transfers, "SELECT * FROM transfers ORDER BY created_at DESC")
+	err := p.DB.SelectContext(ctx, &transfers, "SELECT * FROM transfers ORDER BY created_at DESC")
 	if err != nil {
 		return nil, err
 	}
@@ -92,9 +114,31 @@ func GetAllTransfers(db *sqlx.DB) ([]models.Transfer, error) {
 	return transfers, nil
 }
 
-func GetTotalTransfersByCreditAccount(db *sqlx.DB, accountID string) (float64, error) {
+func (p *Postgres) GetTotalTransfersByCreditAccount(ctx context.Context, accountID string) (float64, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var total float64
+	query := `SELECT COALESCE(SUM(tr.amount), 0) FROM transfers tr
+			  JOIN transactions t ON t.id = tr.transaction_id
+			  WHERE tr.credit_account IN (` + accountSubtree + `) AND t.status = 'posted'`
+	err := p.DB.GetContext(ctx, &total, query, accountID)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (p *Postgres) GetTotalTransfersByDateRange(ctx context.Context, accountID string, startDate, endDate time.Time) (float64, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var total float64
-	err := db.Get(&total, "SELECT COALESCE(SUM(amount), 0) FROM transfers WHERE credit_account = $1", accountID)
+	query := `SELECT COALESCE(SUM(tr.amount), 0) FROM transfers tr
+			  JOIN transactions t ON t.id = tr.transaction_id
+			  WHERE tr.credit_account IN (` + accountSubtree + `) AND t.status = 'posted' AND tr.created_at BETWEEN $2 AND $3`
+	err := p.DB.GetContext(ctx, &total, query, accountID, startDate, endDate)
 	if err != nil {
 		return 0, err
 	}
@@ -102,10 +146,12 @@ func GetTotalTransfersByCreditAccount(db *sqlx.DB, accountID string) (float64, e
 	return total, nil
 }
 
-func GetTotalTransfersByDateRange(db *sqlx.DB, accountID string, startDate, endDate time.Time) (float64, error) {
+func (p *Postgres) GetTotalTransfersByTransaction(ctx context.Context, transactionID string) (float64, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var total float64
-	query := "SELECT COALESCE(SUM(amount), 0) FROM transfers WHERE credit_account = $1 AND created_at BETWEEN $2 AND $3"
-	err := db.Get(&total, query, accountID, startDate, endDate)
+	err := p.DB.GetContext(ctx, &total, "SELECT COALESCE(SUM(amount), 0) FROM transfers WHERE transaction_id = $1", transactionID)
 	if err != nil {
 		return 0, err
 	}
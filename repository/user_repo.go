@@ -1,15 +1,19 @@
 package repository
 
 import (
+	"context"
+	"storeHouse/database"
 	"storeHouse/models"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 )
 
-func executeUserQuery(db *sqlx.DB, query string, user models.User) (models.User, error) {
-	_, err := db.NamedExec(query, user)
+func (p *Postgres) executeUserQuery(ctx context.Context, query string, user models.User) (models.User, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.NamedExecContext(ctx, query, user)
 	if err != nil {
 		return models.User{}, err
 	}
@@ -17,27 +21,51 @@ func executeUserQuery(db *sqlx.DB, query string, user models.User) (models.User,
 	return user, nil
 }
 
-func CreateUser(db *sqlx.DB, user models.User) (models.User, error) {
+func (p *Postgres) CreateUser(ctx context.Context, user models.User) (models.User, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	user.ID = uuid.New().String()
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
 
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return models.User{}, err
+	}
+	defer tx.Rollback()
+
 	query := `INSERT INTO users (id, username, email, password_hash, full_name, role, phone_number, is_active, created_at, updated_at)
               VALUES (:id, :username, :email, :password_hash, :full_name, :role, :phone_number, :is_active, :created_at, :updated_at)`
+	if _, err := tx.NamedExecContext(ctx, query, user); err != nil {
+		return models.User{}, err
+	}
 
-	return executeUserQuery(db, query, user)
+	// A user created on a church connection works in that church under their role
+	memberQuery := `INSERT INTO church_users (id, church_id, user_id, role)
+              SELECT $1, current_setting('app.church_id', true)::uuid, $2, $3
+              WHERE COALESCE(current_setting('app.church_id', true), '') <> ''`
+	if _, err := tx.ExecContext(ctx, memberQuery, uuid.New().String(), user.ID, user.Role); err != nil {
+		return models.User{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.User{}, err
+	}
+
+	return user, nil
 }
 
-func UpdateUser(db *sqlx.DB, user models.User) (models.User, error) {
+func (p *Postgres) UpdateUser(ctx context.Context, user models.User) (models.User, error) {
 	user.UpdatedAt = time.Now()
 
 	query := `UPDATE users SET username = :username, email = :email, full_name = :full_name, role = :role, phone_number = :phone_number, is_active = :is_active, updated_at = :updated_at 
 			  WHERE id = :id`
 
-	return executeUserQuery(db, query, user)
+	return p.executeUserQuery(ctx, query, user)
 }
 
-func DeactivateUser(db *sqlx.DB, id string) (models.User, error) {
+func (p *Postgres) DeactivateUser(ctx context.Context, id string) (models.User, error) {
 	user := models.User{
 		ID:        id,
 		IsActive:  false,
@@ -46,12 +74,15 @@ func DeactivateUser(db *sqlx.DB, id string) (models.User, error) {
 
 	query := `UPDATE users SET is_active = :is_active, updated_at = :updated_at WHERE id = :id`
 
-	return executeUserQuery(db, query, user)
+	return p.executeUserQuery(ctx, query, user)
 }
 
-func GetUser(db *sqlx.DB, id string) (models.User, error) {
+func (p *Postgres) GetUser(ctx context.Context, id string) (models.User, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var user models.User
-	err := db.Get(&user, "SELECT * FROM users WHERE id = $1", id)
+	err := p.DB.GetContext(ctx, &user, "SELECT * FROM users WHERE id = $1", id)
 	if err != nil {
 		return models.User{}, err
 	}
@@ -59,9 +90,12 @@ func GetUser(db *sqlx.DB, id string) (models.User, error) {
 	return user, nil
 }
 
-func GetUserByUsername(db *sqlx.DB, username string) (models.User, error) {
+func (p *Postgres) GetUserByUsername(ctx context.Context, username string) (models.User, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var user models.User
-	err := db.Get(&user, "SELECT * FROM users WHERE username = $1", username)
+	err := p.DB.GetContext(ctx, &user, "SELECT * FROM users WHERE username = $1", username)
 	if err != nil {
 		return models.User{}, err
 	}
@@ -69,9 +103,12 @@ func GetUserByUsername(db *sqlx.DB, username string) (models.User, error) {
 	return user, nil
 }
 
-func GetUserByEmail(db *sqlx.DB, email string) (models.User, error) {
+func (p *Postgres) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var user models.User
-	err := db.Get(&user, "SELECT * FROM users WHERE email = $1", email)
+	err := p.DB.GetContext(ctx, &user, "SELECT * FROM users WHERE email = $1", email)
 	if err != nil {
 		return models.User{}, err
 	}
@@ -79,14 +116,36 @@ func GetUserByEmail(db *sqlx.DB, email string) (models.User, error) {
 	return user, nil
 }
 
-func UpdateLastLogin(db *sqlx.DB, id string) error {
-	_, err := db.Exec("UPDATE users SET last_login = $1 WHERE id = $2", time.Now(), id)
+func (p *Postgres) UpdateLastLogin(ctx context.Context, id string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "UPDATE users SET last_login = $1 WHERE id = $2", time.Now(), id)
+	return err
+}
+
+func (p *Postgres) UpdateUserPassword(ctx context.Context, id, passwordHash string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "UPDATE users SET password_hash = $1, updated_at = $2 WHERE id = $3", passwordHash, time.Now(), id)
 	return err
 }
 
-func GetAllUsers(db *sqlx.DB) ([]models.User, error) {
+func (p *Postgres) DeleteUser(ctx context.Context, id string) error {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	_, err := p.DB.ExecContext(ctx, "DELETE FROM users WHERE id = $1", id)
+	return err
+}
+
+func (p *Postgres) GetAllUsers(ctx context.Context) ([]models.User, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var users []models.User
-	err := db.Select(&users, "SELECT * FROM users ORDER BY created_at DESC")
+	err := p.DB.SelectContext(ctx, &users, "SELECT * FROM users ORDER BY created_at DESC")
 	if err != nil {
 		return nil, err
 	}
@@ -94,9 +153,12 @@ func GetAllUsers(db *sqlx.DB) ([]models.User, error) {
 	return users, nil
 }
 
-func GetUsersByRole(db *sqlx.DB, role string) ([]models.User, error) {
+func (p *Postgres) GetUsersByRole(ctx context.Context, role string) ([]models.User, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var users []models.User
-	err := db.Select(&users, "SELECT * FROM users WHERE role = $1 ORDER BY created_at DESC", role)
+	err := p.DB.SelectContext(ctx, &users, "SELECT * FROM users WHERE role = $1 ORDER BY created_at DESC", role)
 	if err != nil {
 		return nil, err
 	}
@@ -104,9 +166,12 @@ func GetUsersByRole(db *sqlx.DB, role string) ([]models.User, error) {
 	return users, nil
 }
 
-func GetActiveUsers(db *sqlx.DB) ([]models.User, error) {
+func (p *Postgres) GetActiveUsers(ctx context.Context) ([]models.User, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
 	var users []models.User
-	err := db.Select(&users, "SELECT * FROM users WHERE is_active = true ORDER BY created_at DESC")
+	err := p.DB.SelectContext(ctx, &users, "SELECT * FROM users WHERE is_active = true ORDER BY created_at DESC")
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"storeHouse/database"
+	"storeHouse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (p *Postgres) CreateVoucherApproval(ctx context.Context, approval models.VoucherApproval) (models.VoucherApproval, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	approval.ID = uuid.New().String()
+	approval.CreatedAt = time.Now()
+
+	query := `INSERT INTO voucher_approvals (id, transaction_id, approver, approver_role, decision, comment, created_at)
+              VALUES (:id, :transaction_id, :approver, :approver_role, :decision, :comment, :created_at)`
+
+	_, err := p.DB.NamedExecContext(ctx, query, approval)
+	if err != nil {
+		return models.VoucherApproval{}, err
+	}
+
+	return approval, nil
+}
+
+func (p *Postgres) GetVoucherApprovals(ctx context.Context, transactionID string) ([]models.VoucherApproval, error) {
+	ctx, cancel := database.QueryContext(ctx)
+	defer cancel()
+
+	var approvals []models.VoucherApproval
+	err := p.DB.SelectContext(ctx, &approvals, "SELECT * FROM voucher_approvals WHERE transaction_id = $1 ORDER BY created_at", transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return approvals, nil
+}
@@ -1,25 +1,24 @@
 package services
 
 import (
-	"errors"
+	"context"
 	"storeHouse/models"
 	"storeHouse/repository"
+	"strings"
 	"time"
-
-	"github.com/jmoiron/sqlx"
 )
 
 type AccountService struct {
-	DB *sqlx.DB
+	Repo repository.Store
 }
 
 // Create a new instance of AccountService
-func NewAccountService(db *sqlx.DB) *AccountService {
-	return &AccountService{DB: db}
+func NewAccountService(repo repository.Store) *AccountService {
+	return &AccountService{Repo: repo}
 }
 
 // Create Account handles account creation business logic
-func (s *AccountService) CreateAccount(req models.CreateAccountRequest, createdBy string) (*models.AccountResponse, error) {
+func (s *AccountService) CreateAccount(ctx context.Context, req models.CreateAccountRequest, createdBy string) (*models.AccountResponse, error) {
 	// Validate account type
 	if err := (&models.Account{AccountType: req.AccountType}).ValidateAccountType(); err != nil {
 		return nil, err
@@ -29,6 +28,7 @@ func (s *AccountService) CreateAccount(req models.CreateAccountRequest, createdB
 	account := models.Account{
 		AccountName: req.AccountName,
 		AccountType: req.AccountType,
+		IsHeader:    req.IsHeader,
 		LocalShare:  req.LocalShare,
 		Notes:       req.Notes,
 		IsActive:    true,
@@ -38,12 +38,20 @@ func (s *AccountService) CreateAccount(req models.CreateAccountRequest, createdB
 	}
 
 	// Check for duplicates before creating
-	if _, err := repository.GetAccountByName(s.DB, req.AccountName); err == nil {
-		return nil, errors.New("account name already exists")
+	if _, err := s.Repo.GetAccountByName(ctx, req.AccountName); err == nil {
+		return nil, models.Conflict("account name already exists")
+	}
+
+	// Place the account in the chart
+	if err := s.setCode(ctx, &account, req.Code); err != nil {
+		return nil, err
+	}
+	if err := s.setParent(ctx, &account, req.ParentID); err != nil {
+		return nil, err
 	}
 
 	// Save to DB
-	newAcc, err := repository.CreateAccount(s.DB, account)
+	newAcc, err := s.Repo.CreateAccount(ctx, account)
 	if err != nil {
 		return nil, err
 	}
@@ -52,22 +60,50 @@ func (s *AccountService) CreateAccount(req models.CreateAccountRequest, createdB
 }
 
 // UpdateAccount handles update logic
-func (s *AccountService) UpdateAccount(id string, req models.UpdateAccountRequest) (*models.AccountResponse, error) {
+func (s *AccountService) UpdateAccount(ctx context.Context, id string, req models.UpdateAccountRequest) (*models.AccountResponse, error) {
 	// Fetch existing record
-	existing, err := repository.GetAccount(s.DB, id)
+	existing, err := s.Repo.GetAccount(ctx, id)
 	if err != nil {
-		return nil, errors.New("account not found")
+		return nil, models.ErrAccountNotFound
 	}
 
 	// Apply updates only if fields are provided
 	if req.AccountName != nil {
 		existing.AccountName = *req.AccountName
 	}
-	if req.AccountType != nil {
+	if req.AccountType != nil && *req.AccountType != existing.AccountType {
 		existing.AccountType = *req.AccountType
 		if err := existing.ValidateAccountType(); err != nil {
 			return nil, err
 		}
+		children, err := s.Repo.CountChildAccounts(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if children > 0 {
+			return nil, models.Invalid("account type cannot change while the account has child accounts")
+		}
+	}
+	if req.Code != nil {
+		if err := s.setCode(ctx, &existing, req.Code); err != nil {
+			return nil, err
+		}
+	}
+	if req.ParentID != nil {
+		if err := s.setParent(ctx, &existing, req.ParentID); err != nil {
+			return nil, err
+		}
+	} else if req.AccountType != nil {
+		// A new type must still match the parent's
+		if err := s.setParent(ctx, &existing, existing.ParentID); err != nil {
+			return nil, err
+		}
+	}
+	if req.IsHeader != nil && *req.IsHeader != existing.IsHeader {
+		if err := s.checkHeaderChange(ctx, existing, *req.IsHeader); err != nil {
+			return nil, err
+		}
+		existing.IsHeader = *req.IsHeader
 	}
 	if req.LocalShare != nil {
 		existing.LocalShare = req.LocalShare
@@ -82,7 +118,7 @@ func (s *AccountService) UpdateAccount(id string, req models.UpdateAccountReques
 	existing.UpdatedAt = time.Now()
 
 	// Persist update
-	updated, err := repository.UpdateAccount(s.DB, existing)
+	updated, err := s.Repo.UpdateAccount(ctx, existing)
 	if err != nil {
 		return nil, err
 	}
@@ -91,28 +127,28 @@ func (s *AccountService) UpdateAccount(id string, req models.UpdateAccountReques
 }
 
 // DeactivateAccount sets is_active = false
-func (s *AccountService) DeactivateAccount(id string) error {
+func (s *AccountService) DeactivateAccount(ctx context.Context, id string) error {
 	// Ensure exists before disabling
-	if _, err := repository.GetAccount(s.DB, id); err != nil {
-		return errors.New("account not found")
+	if _, err := s.Repo.GetAccount(ctx, id); err != nil {
+		return models.ErrAccountNotFound
 	}
 
-	_, err := repository.DeactivateAccount(s.DB, id)
+	_, err := s.Repo.DeactivateAccount(ctx, id)
 	return err
 }
 
 // GetAccount returns single account details
-func (s *AccountService) GetAccount(id string) (*models.AccountResponse, error) {
-	acc, err := repository.GetAccount(s.DB, id)
+func (s *AccountService) GetAccount(ctx context.Context, id string) (*models.AccountResponse, error) {
+	acc, err := s.Repo.GetAccount(ctx, id)
 	if err != nil {
-		return nil, errors.New("account not found")
+		return nil, models.ErrAccountNotFound
 	}
 	return acc.ToResponse(), nil
 }
 
 // List all active accounts
-func (s *AccountService) GetAllAccounts() ([]models.AccountResponse, error) {
-	accounts, err := repository.GetAllAccounts(s.DB)
+func (s *AccountService) GetAllAccounts(ctx context.Context) ([]models.AccountResponse, error) {
+	accounts, err := s.Repo.GetAllAccounts(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -126,3 +162,130 @@ func (s *AccountService) GetAllAccounts() ([]models.AccountResponse, error) {
 	return responses, nil
 }
 
+// GetAccountTree returns the chart of accounts as a tree in code order. Accounts whose
+// parent is left out (inactive) appear at the top level.
+func (s *AccountService) GetAccountTree(ctx context.Context, includeInactive bool) ([]models.AccountNode, error) {
+	accounts, err := s.Repo.GetChartOfAccounts(ctx, includeInactive)
+	if err != nil {
+		return nil, err
+	}
+
+	included := make(map[string]bool, len(accounts))
+	for _, a := range accounts {
+		included[a.ID] = true
+	}
+
+	children := make(map[string][]models.Account)
+	var roots []models.Account
+	for _, a := range accounts {
+		if a.ParentID != nil && included[*a.ParentID] {
+			children[*a.ParentID] = append(children[*a.ParentID], a)
+		} else {
+			roots = append(roots, a)
+		}
+	}
+
+	var build func(list []models.Account) []models.AccountNode
+	build = func(list []models.Account) []models.AccountNode {
+		nodes := make([]models.AccountNode, 0, len(list))
+		for _, a := range list {
+			nodes = append(nodes, models.AccountNode{
+				AccountResponse: *a.ToResponse(),
+				Children:        build(children[a.ID]),
+			})
+		}
+		return nodes
+	}
+
+	return build(roots), nil
+}
+
+// setCode normalizes an account code and makes sure no other account uses it; an empty
+// code clears it
+func (s *AccountService) setCode(ctx context.Context, account *models.Account, code *string) error {
+	if code == nil || strings.TrimSpace(*code) == "" {
+		account.Code = nil
+		return nil
+	}
+
+	normalized, err := models.NormalizeAccountCode(*code)
+	if err != nil {
+		return err
+	}
+	if other, err := s.Repo.GetAccountByCode(ctx, normalized); err == nil && other.ID != account.ID {
+		return models.Conflict("account code " + normalized + " already exists")
+	}
+
+	account.Code = &normalized
+	return nil
+}
+
+// setParent places an account under a header account of the same type, refusing moves
+// that would put an account below itself; an empty parent moves it to the top level
+func (s *AccountService) setParent(ctx context.Context, account *models.Account, parentID *string) error {
+	if parentID == nil || *parentID == "" {
+		account.ParentID = nil
+		return nil
+	}
+
+	parent, err := s.Repo.GetAccount(ctx, *parentID)
+	if err != nil {
+		return models.Wrap(models.ErrAccountNotFound, "parent account not found")
+	}
+	if !parent.IsHeader {
+		return models.Invalid("parent account " + parent.AccountName + " is not a header account")
+	}
+	if parent.AccountType != account.AccountType {
+		return models.Invalid("parent account " + parent.AccountName + " is not a " + account.AccountType + " account")
+	}
+
+	if account.ID != "" {
+		subtree, err := s.Repo.GetAccountSubtreeIDs(ctx, account.ID)
+		if err != nil {
+			return err
+		}
+		if containsString(subtree, parent.ID) {
+			return models.Invalid("an account cannot be placed under itself or one of its children")
+		}
+	}
+
+	account.ParentID = &parent.ID
+	return nil
+}
+
+// checkHeaderChange makes sure an account only becomes a header before anything is posted
+// to it, and only stops being one once it has no children
+func (s *AccountService) checkHeaderChange(ctx context.Context, account models.Account, isHeader bool) error {
+	if isHeader {
+		postings, err := s.Repo.CountAccountPostings(ctx, account.ID)
+		if err != nil {
+			return err
+		}
+		if postings > 0 {
+			return models.Invalid("account " + account.AccountName + " has postings and cannot become a header account")
+		}
+		return nil
+	}
+
+	children, err := s.Repo.CountChildAccounts(ctx, account.ID)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return models.Invalid("account " + account.AccountName + " has child accounts and must stay a header account")
+	}
+	return nil
+}
+
+// getPostingAccount fetches an account that lines can be posted to; header accounts only
+// group their children for reporting
+func getPostingAccount(ctx context.Context, accounts repository.AccountRepository, id, name string) (models.Account, error) {
+	account, err := accounts.GetAccount(ctx, id)
+	if err != nil {
+		return models.Account{}, models.Wrap(models.ErrAccountNotFound, name+" not found")
+	}
+	if account.IsHeader {
+		return models.Account{}, models.Invalid("account " + account.AccountName + " is a header account and cannot be posted to")
+	}
+	return account, nil
+}
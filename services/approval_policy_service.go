@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type ApprovalPolicyService struct {
+	Repo repository.Store
+}
+
+// Create a new instance of ApprovalPolicyService
+func NewApprovalPolicyService(repo repository.Store) *ApprovalPolicyService {
+	return &ApprovalPolicyService{Repo: repo}
+}
+
+// CreatePolicy handles approval policy creation business logic
+func (s *ApprovalPolicyService) CreatePolicy(ctx context.Context, req models.CreateApprovalPolicyRequest, createdBy string) (*models.ApprovalPolicyResponse, error) {
+	// Prepare model for DB
+	policy := models.ApprovalPolicy{
+		Name:              req.Name,
+		Description:       req.Description,
+		TransactionType:   emptyToNil(req.TransactionType),
+		AccountID:         emptyToNil(req.AccountID),
+		AmountOver:        req.AmountOver,
+		AmountUpTo:        req.AmountUpTo,
+		RequiredApprovals: req.RequiredApprovals,
+		RequiredRoles:     pq.StringArray(req.RequiredRoles),
+		IsActive:          true,
+		CreatedBy:         &createdBy,
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
+	}
+	if req.IsActive != nil {
+		policy.IsActive = *req.IsActive
+	}
+
+	if err := s.validate(ctx, policy); err != nil {
+		return nil, err
+	}
+
+	// Save to DB
+	newPolicy, err := s.Repo.CreateApprovalPolicy(ctx, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPolicy.ToResponse(), nil
+}
+
+// UpdatePolicy handles update logic. Vouchers already submitted keep the requirement
+// they were submitted under.
+func (s *ApprovalPolicyService) UpdatePolicy(ctx context.Context, id string, req models.UpdateApprovalPolicyRequest) (*models.ApprovalPolicyResponse, error) {
+	// Fetch existing record
+	existing, err := s.Repo.GetApprovalPolicy(ctx, id)
+	if err != nil {
+		return nil, models.ErrApprovalPolicyNotFound
+	}
+
+	// Apply updates only if fields are provided
+	if req.Name != nil {
+		existing.Name = *req.Name
+	}
+	if req.Description != nil {
+		existing.Description = req.Description
+	}
+	if req.TransactionType != nil {
+		existing.TransactionType = emptyToNil(req.TransactionType)
+	}
+	if req.AccountID != nil {
+		existing.AccountID = emptyToNil(req.AccountID)
+	}
+	if req.AmountOver != nil {
+		existing.AmountOver = *req.AmountOver
+	}
+	if req.AmountUpTo != nil {
+		// Zero removes the upper bound
+		if *req.AmountUpTo == 0 {
+			existing.AmountUpTo = nil
+		} else {
+			existing.AmountUpTo = req.AmountUpTo
+		}
+	}
+	if req.RequiredApprovals != nil {
+		existing.RequiredApprovals = *req.RequiredApprovals
+	}
+	if req.RequiredRoles != nil {
+		existing.RequiredRoles = pq.StringArray(req.RequiredRoles)
+	}
+	if req.IsActive != nil {
+		existing.IsActive = *req.IsActive
+	}
+
+	if err := s.validate(ctx, existing); err != nil {
+		return nil, err
+	}
+
+	// Persist update
+	updated, err := s.Repo.UpdateApprovalPolicy(ctx, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.ToResponse(), nil
+}
+
+// DeletePolicy removes an approval policy
+func (s *ApprovalPolicyService) DeletePolicy(ctx context.Context, id string) error {
+	// Ensure exists before deleting
+	if _, err := s.Repo.GetApprovalPolicy(ctx, id); err != nil {
+		return models.ErrApprovalPolicyNotFound
+	}
+
+	return s.Repo.DeleteApprovalPolicy(ctx, id)
+}
+
+// GetPolicy returns single approval policy details
+func (s *ApprovalPolicyService) GetPolicy(ctx context.Context, id string) (*models.ApprovalPolicyResponse, error) {
+	policy, err := s.Repo.GetApprovalPolicy(ctx, id)
+	if err != nil {
+		return nil, models.ErrApprovalPolicyNotFound
+	}
+	return policy.ToResponse(), nil
+}
+
+// GetAllPolicies returns every approval policy
+func (s *ApprovalPolicyService) GetAllPolicies(ctx context.Context) ([]models.ApprovalPolicyResponse, error) {
+	policies, err := s.Repo.GetAllApprovalPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response list
+	responses := make([]models.ApprovalPolicyResponse, 0, len(policies))
+	for _, p := range policies {
+		responses = append(responses, *p.ToResponse())
+	}
+
+	return responses, nil
+}
+
+// Evaluate works out the approvals a voucher needs. Every active policy that applies
+// contributes: the voucher needs the highest approval count among them and an approval
+// from each role any of them names. Without a matching policy one approval is enough.
+func (s *ApprovalPolicyService) Evaluate(ctx context.Context, transactionType string, accounts []string, amount float64) (models.ApprovalRequirement, error) {
+	policies, err := s.Repo.GetActiveApprovalPolicies(ctx)
+	if err != nil {
+		return models.ApprovalRequirement{}, err
+	}
+
+	requirement := models.ApprovalRequirement{
+		RequiredApprovals: 1,
+		RequiredRoles:     []string{},
+		Policies:          []string{},
+	}
+	// A policy on a header account covers vouchers on the accounts under it
+	subtrees := make(map[string][]string)
+	for _, p := range policies {
+		var subtree []string
+		if p.AccountID != nil {
+			if _, ok := subtrees[*p.AccountID]; !ok {
+				ids, err := s.Repo.GetAccountSubtreeIDs(ctx, *p.AccountID)
+				if err != nil {
+					return models.ApprovalRequirement{}, err
+				}
+				subtrees[*p.AccountID] = ids
+			}
+			subtree = subtrees[*p.AccountID]
+		}
+		if !p.Applies(transactionType, accounts, amount, subtree) {
+			continue
+		}
+
+		requirement.Policies = append(requirement.Policies, p.Name)
+		if p.RequiredApprovals > requirement.RequiredApprovals {
+			requirement.RequiredApprovals = p.RequiredApprovals
+		}
+		for _, role := range p.RequiredRoles {
+			if !containsString(requirement.RequiredRoles, role) {
+				requirement.RequiredRoles = append(requirement.RequiredRoles, role)
+			}
+		}
+	}
+
+	// Each named role takes an approval of its own
+	if len(requirement.RequiredRoles) > requirement.RequiredApprovals {
+		requirement.RequiredApprovals = len(requirement.RequiredRoles)
+	}
+
+	return requirement, nil
+}
+
+// EvaluateRequest previews the approvals a voucher would need
+func (s *ApprovalPolicyService) EvaluateRequest(ctx context.Context, req models.EvaluateApprovalPolicyRequest) (models.ApprovalRequirement, error) {
+	txn := models.Transaction{TransactionType: req.TransactionType}
+	if !txn.IsVoucher() {
+		return models.ApprovalRequirement{}, models.Invalid("transaction_type must be expenses, withdrawal or transfer")
+	}
+	if req.Amount <= 0 {
+		return models.ApprovalRequirement{}, models.Invalid("amount must be greater than zero")
+	}
+
+	return s.Evaluate(ctx, req.TransactionType, req.AccountIDs, req.Amount)
+}
+
+// validate checks the policy and that its account exists
+func (s *ApprovalPolicyService) validate(ctx context.Context, policy models.ApprovalPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	if policy.AccountID != nil {
+		if _, err := s.Repo.GetAccount(ctx, *policy.AccountID); err != nil {
+			return models.ErrAccountNotFound
+		}
+	}
+	return nil
+}
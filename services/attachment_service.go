@@ -0,0 +1,313 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"storeHouse/storage"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// allowedAttachmentTypes are the sniffed content types accepted as supporting documents
+var allowedAttachmentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"text/plain":      true,
+}
+
+type AttachmentService struct {
+	Repo   repository.Store
+	Config storage.Config
+	Store  storage.Store
+}
+
+// Create a new instance of AttachmentService
+func NewAttachmentService(repo repository.Store) *AttachmentService {
+	cfg := storage.LoadConfig()
+	store, err := storage.NewStore(cfg)
+	if err != nil {
+		log.Printf("⚠️  Attachment storage unavailable: %v", err)
+	}
+	return &AttachmentService{Repo: repo, Config: cfg, Store: store}
+}
+
+// MaxSize returns the largest file accepted, in bytes
+func (s *AttachmentService) MaxSize() int64 {
+	return s.Config.MaxSize
+}
+
+// UploadAttachment stores a file and links it to a transaction, expenditure or transfer.
+// The content type is sniffed from the file itself rather than trusted from the client.
+func (s *AttachmentService) UploadAttachment(ctx context.Context, req models.UploadAttachmentRequest, content io.Reader, uploadedBy *string) (*models.AttachmentResponse, error) {
+	if s.Store == nil {
+		return nil, models.Unavailable("attachment storage is not configured")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	attachment := models.Attachment{
+		ID:          uuid.New().String(),
+		FileName:    cleanFileName(req.FileName),
+		Description: emptyToNil(req.Description),
+		UploadedBy:  uploadedBy,
+	}
+	if attachment.FileName == "" {
+		return nil, models.Invalid("file name is required")
+	}
+	if err := s.linkEntity(ctx, &attachment, models.AttachmentEntity(req.EntityType), req.EntityID); err != nil {
+		return nil, err
+	}
+
+	// Read one byte past the limit so an oversized file is caught without trusting Content-Length
+	data, err := io.ReadAll(io.LimitReader(content, s.Config.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, models.Invalid("file is empty")
+	}
+	if int64(len(data)) > s.Config.MaxSize {
+		return nil, models.NewError(http.StatusRequestEntityTooLarge, models.CodeTooLarge, fmt.Sprintf("file is larger than the %d MB limit", s.Config.MaxSize>>20))
+	}
+
+	contentType, err := sniffContentType(data)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum := sha256.Sum256(data)
+	attachment.ChecksumSHA256 = hex.EncodeToString(checksum[:])
+	attachment.ContentType = contentType
+	attachment.SizeBytes = int64(len(data))
+
+	// The same document attached twice to one record is almost always a double upload
+	existing, err := s.Repo.GetAttachmentsByEntity(ctx, attachment.EntityType(), attachment.EntityID())
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range existing {
+		if a.ChecksumSHA256 == attachment.ChecksumSHA256 {
+			return nil, models.Conflict(fmt.Sprintf("file is already attached as %s", a.FileName))
+		}
+	}
+
+	attachment.StorageKey = fmt.Sprintf("%s/%s%s", time.Now().Format("2006/01"), attachment.ID, strings.ToLower(filepath.Ext(attachment.FileName)))
+	if _, err := s.Store.Put(attachment.StorageKey, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	// Save to DB
+	newAttachment, err := s.Repo.CreateAttachment(ctx, attachment)
+	if err != nil {
+		if delErr := s.Store.Delete(attachment.StorageKey); delErr != nil {
+			log.Printf("⚠️  Failed to remove stored file %s: %v", attachment.StorageKey, delErr)
+		}
+		return nil, err
+	}
+
+	return newAttachment.ToResponse(), nil
+}
+
+// GetAttachment returns an attachment's details
+func (s *AttachmentService) GetAttachment(ctx context.Context, id string) (*models.AttachmentResponse, error) {
+	attachment, err := s.Repo.GetAttachment(ctx, id)
+	if err != nil {
+		return nil, models.ErrAttachmentNotFound
+	}
+
+	return attachment.ToResponse(), nil
+}
+
+// OpenAttachment returns an attachment with its stored content, for download
+func (s *AttachmentService) OpenAttachment(ctx context.Context, id string) (models.Attachment, io.ReadCloser, error) {
+	if s.Store == nil {
+		return models.Attachment{}, nil, models.Unavailable("attachment storage is not configured")
+	}
+
+	attachment, err := s.Repo.GetAttachment(ctx, id)
+	if err != nil {
+		return models.Attachment{}, nil, models.ErrAttachmentNotFound
+	}
+
+	content, err := s.Store.Open(attachment.StorageKey)
+	if err == storage.ErrNotFound {
+		return models.Attachment{}, nil, models.Wrap(models.ErrAttachmentNotFound, "attachment file not found")
+	}
+	if err != nil {
+		return models.Attachment{}, nil, err
+	}
+
+	return attachment, content, nil
+}
+
+// GetAttachments returns the files attached to a record. For a transaction this includes
+// the files attached to its expenditure and transfer lines.
+func (s *AttachmentService) GetAttachments(ctx context.Context, entityType, entityID string) ([]models.AttachmentResponse, error) {
+	if err := models.ValidateAttachmentEntity(entityType); err != nil {
+		return nil, err
+	}
+
+	var probe models.Attachment
+	if err := s.linkEntity(ctx, &probe, models.AttachmentEntity(entityType), entityID); err != nil {
+		return nil, err
+	}
+
+	var attachments []models.Attachment
+	var err error
+	if models.AttachmentEntity(entityType) == models.AttachmentTransaction {
+		attachments, err = s.Repo.GetAttachmentsByTransactionTree(ctx, entityID)
+	} else {
+		attachments, err = s.Repo.GetAttachmentsByEntity(ctx, models.AttachmentEntity(entityType), entityID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response list
+	responses := make([]models.AttachmentResponse, 0, len(attachments))
+	for _, attachment := range attachments {
+		responses = append(responses, *attachment.ToResponse())
+	}
+
+	return responses, nil
+}
+
+// DeleteAttachment removes an attachment and its file. Attachments of posted transactions
+// are kept for the audit trail.
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, id string) error {
+	// Ensure exists before deleting
+	attachment, err := s.Repo.GetAttachment(ctx, id)
+	if err != nil {
+		return models.ErrAttachmentNotFound
+	}
+
+	txnID, err := s.transactionOf(ctx, attachment.EntityType(), attachment.EntityID())
+	if err != nil {
+		return err
+	}
+	txn, err := s.Repo.GetTransaction(ctx, txnID)
+	if err != nil {
+		return models.ErrTransactionNotFound
+	}
+	if txn.Status == string(models.TransactionPosted) {
+		return models.Conflict("only attachments of unposted transactions can be deleted")
+	}
+
+	if err := s.Repo.DeleteAttachment(ctx, id); err != nil {
+		return err
+	}
+	s.removeFiles([]models.Attachment{attachment})
+
+	return nil
+}
+
+// deleteWithAttachments runs a delete of a record whose attachments cascade with it,
+// then removes their stored files once the rows are gone
+func deleteWithAttachments(ctx context.Context, repo repository.Store, entity models.AttachmentEntity, id string, del func() error) error {
+	attachments, err := repo.GetAttachmentsByEntity(ctx, entity, id)
+	if err != nil {
+		return err
+	}
+
+	if err := del(); err != nil {
+		return err
+	}
+
+	NewAttachmentService(repo).removeFiles(attachments)
+	return nil
+}
+
+func (s *AttachmentService) removeFiles(attachments []models.Attachment) {
+	if s.Store == nil {
+		return
+	}
+	for _, attachment := range attachments {
+		if err := s.Store.Delete(attachment.StorageKey); err != nil {
+			log.Printf("⚠️  Failed to remove stored file %s: %v", attachment.StorageKey, err)
+		}
+	}
+}
+
+// linkEntity checks the record exists and points the attachment at it
+func (s *AttachmentService) linkEntity(ctx context.Context, attachment *models.Attachment, entity models.AttachmentEntity, id string) error {
+	switch entity {
+	case models.AttachmentTransaction:
+		if _, err := s.Repo.GetTransaction(ctx, id); err != nil {
+			return models.ErrTransactionNotFound
+		}
+		attachment.TransactionID = &id
+	case models.AttachmentExpenditure:
+		if _, err := s.Repo.GetExpenditure(ctx, id); err != nil {
+			return models.ErrExpenditureNotFound
+		}
+		attachment.ExpenditureID = &id
+	case models.AttachmentTransfer:
+		if _, err := s.Repo.GetTransfer(ctx, id); err != nil {
+			return models.ErrTransferNotFound
+		}
+		attachment.TransferID = &id
+	default:
+		return models.ValidateAttachmentEntity(string(entity))
+	}
+	return nil
+}
+
+// transactionOf returns the transaction a record belongs to
+func (s *AttachmentService) transactionOf(ctx context.Context, entity models.AttachmentEntity, id string) (string, error) {
+	switch entity {
+	case models.AttachmentExpenditure:
+		expenditure, err := s.Repo.GetExpenditure(ctx, id)
+		if err != nil {
+			return "", models.ErrExpenditureNotFound
+		}
+		return expenditure.TransactionID, nil
+	case models.AttachmentTransfer:
+		transfer, err := s.Repo.GetTransfer(ctx, id)
+		if err != nil {
+			return "", models.ErrTransferNotFound
+		}
+		return transfer.TransactionID, nil
+	default:
+		return id, nil
+	}
+}
+
+// sniffContentType detects the file type from its first bytes and checks it is allowed
+func sniffContentType(data []byte) (string, error) {
+	detected := http.DetectContentType(data)
+	mediaType, _, err := mime.ParseMediaType(detected)
+	if err != nil {
+		mediaType = detected
+	}
+
+	if !allowedAttachmentTypes[mediaType] {
+		return "", models.NewError(http.StatusUnsupportedMediaType, models.CodeUnsupportedMedia, fmt.Sprintf("file type %s is not allowed; upload a PDF, image or text file", mediaType))
+	}
+	return mediaType, nil
+}
+
+// cleanFileName keeps only the base name of an uploaded file, as sent by the browser
+func cleanFileName(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	name = strings.TrimSpace(filepath.Base(name))
+	if name == "." || name == "/" {
+		return ""
+	}
+	return truncate(name, 255)
+}
@@ -0,0 +1,448 @@
+package services
+
+import (
+	"context"
+	"io"
+	"math"
+	"path/filepath"
+	"sort"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"strings"
+	"time"
+)
+
+// DefaultMatchWindowDays is how far apart, in days, a statement line and a transaction
+// may be dated and still be auto-matched
+const DefaultMatchWindowDays = 3
+
+type BankStatementService struct {
+	Repo repository.Store
+}
+
+// Create a new instance of BankStatementService
+func NewBankStatementService(repo repository.Store) *BankStatementService {
+	return &BankStatementService{Repo: repo}
+}
+
+// ImportStatement parses an uploaded statement for a Bank account, stores its lines and
+// auto-matches them to the account's transactions
+func (s *BankStatementService) ImportStatement(ctx context.Context, req models.ImportBankStatementRequest, file io.Reader, createdBy string) (*models.BankStatementResponse, error) {
+	// Infer the format from the file extension when not given
+	if req.Format == "" {
+		req.Format = strings.TrimPrefix(strings.ToLower(filepath.Ext(req.FileName)), ".")
+	}
+	req.Format = strings.ToLower(req.Format)
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getBankAccount(ctx, req.BankAccountID); err != nil {
+		return nil, err
+	}
+
+	parsed, err := parseStatement(req.Format, file)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare model for DB
+	statement := models.BankStatement{
+		BankAccountID:  req.BankAccountID,
+		FileName:       optionalString(req.FileName),
+		Format:         req.Format,
+		PeriodStart:    parsed.PeriodStart,
+		PeriodEnd:      parsed.PeriodEnd,
+		OpeningBalance: req.OpeningBalance,
+		ClosingBalance: parsed.ClosingBalance,
+		CreatedBy:      createdBy,
+	}
+	if req.ClosingBalance != nil {
+		statement.ClosingBalance = req.ClosingBalance
+	}
+
+	// Save to DB
+	newStatement, err := s.Repo.CreateBankStatement(ctx, statement)
+	if err != nil {
+		return nil, err
+	}
+
+	skipped := 0
+	for _, line := range parsed.Lines {
+		// Lines already imported from an overlapping OFX statement are skipped
+		if line.FitID != nil {
+			if _, err := s.Repo.GetStatementLineByFitID(ctx, req.BankAccountID, *line.FitID); err == nil {
+				skipped++
+				continue
+			}
+		}
+
+		line.StatementID = newStatement.ID
+		line.BankAccountID = req.BankAccountID
+		if _, err := s.Repo.CreateStatementLine(ctx, line); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := s.AutoMatch(ctx, newStatement.ID, req.WindowDays); err != nil {
+		return nil, err
+	}
+
+	response, err := s.GetStatement(ctx, newStatement.ID)
+	if err != nil {
+		return nil, err
+	}
+	response.SkippedCount = skipped
+
+	return response, nil
+}
+
+// AutoMatch matches a statement's unmatched lines to uncleared transactions on the same
+// account with the same amount, dated within windowDays of the line. A reference found on
+// both sides wins; otherwise the closest date wins, and ties are left for manual matching.
+func (s *BankStatementService) AutoMatch(ctx context.Context, statementID string, windowDays int) (*models.AutoMatchResult, error) {
+	statement, err := s.Repo.GetBankStatement(ctx, statementID)
+	if err != nil {
+		return nil, models.ErrStatementNotFound
+	}
+	if windowDays <= 0 {
+		windowDays = DefaultMatchWindowDays
+	}
+
+	lines, err := s.Repo.GetStatementLines(ctx, statementID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect the account's transactions not yet cleared by any statement line
+	asOf := time.Now()
+	if statement.PeriodEnd != nil && statement.PeriodEnd.After(asOf) {
+		asOf = *statement.PeriodEnd
+	}
+	entries, err := s.Repo.GetBankBookEntries(ctx, statement.BankAccountID, asOf.AddDate(0, 0, windowDays))
+	if err != nil {
+		return nil, err
+	}
+	matchedIDs, err := s.Repo.GetMatchedTransactionIDs(ctx, statement.BankAccountID)
+	if err != nil {
+		return nil, err
+	}
+	cleared := make(map[string]bool, len(matchedIDs))
+	for _, id := range matchedIDs {
+		cleared[id] = true
+	}
+
+	result := &models.AutoMatchResult{}
+	for _, line := range lines {
+		if line.IsMatched() {
+			continue
+		}
+
+		entry := bestBookEntry(line, entries, cleared, windowDays)
+		if entry == nil {
+			result.Unmatched++
+			continue
+		}
+
+		if err := s.Repo.MatchStatementLine(ctx, line.ID, entry.TransactionID, models.MatchAuto); err != nil {
+			return nil, err
+		}
+		cleared[entry.TransactionID] = true
+		result.Matched++
+	}
+
+	return result, nil
+}
+
+// MatchLine manually matches a statement line to a transaction on the same Bank account
+func (s *BankStatementService) MatchLine(ctx context.Context, lineID string, req models.MatchStatementLineRequest) (*models.BankStatementLineResponse, error) {
+	line, err := s.Repo.GetStatementLine(ctx, lineID)
+	if err != nil {
+		return nil, models.ErrStatementLineNotFound
+	}
+	if line.IsMatched() {
+		return nil, models.Conflict("statement line is already matched")
+	}
+
+	txn, err := s.Repo.GetTransaction(ctx, req.TransactionID)
+	if err != nil {
+		return nil, models.ErrTransactionNotFound
+	}
+
+	// The transaction must move money through this account by the same amount
+	entries, err := s.Repo.GetBankBookEntries(ctx, line.BankAccountID, txn.TransactionDate)
+	if err != nil {
+		return nil, err
+	}
+	var entry *models.BankBookEntry
+	for i := range entries {
+		if entries[i].TransactionID == txn.ID {
+			entry = &entries[i]
+			break
+		}
+	}
+	if entry == nil {
+		return nil, models.Invalid("transaction does not affect this bank account")
+	}
+	if !amountsEqual(entry.Amount, line.Amount) {
+		return nil, models.Invalid("transaction amount does not match statement line")
+	}
+
+	if _, err := s.Repo.GetStatementLineByTransaction(ctx, line.BankAccountID, txn.ID); err == nil {
+		return nil, models.Conflict("transaction is already matched to another statement line")
+	}
+
+	if err := s.Repo.MatchStatementLine(ctx, line.ID, txn.ID, models.MatchManual); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.Repo.GetStatementLine(ctx, line.ID)
+	if err != nil {
+		return nil, err
+	}
+	return updated.ToResponse(), nil
+}
+
+// UnmatchLine clears the match on a statement line
+func (s *BankStatementService) UnmatchLine(ctx context.Context, lineID string) (*models.BankStatementLineResponse, error) {
+	line, err := s.Repo.GetStatementLine(ctx, lineID)
+	if err != nil {
+		return nil, models.ErrStatementLineNotFound
+	}
+	if !line.IsMatched() {
+		return nil, models.Invalid("statement line is not matched")
+	}
+
+	if err := s.Repo.UnmatchStatementLine(ctx, line.ID); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.Repo.GetStatementLine(ctx, line.ID)
+	if err != nil {
+		return nil, err
+	}
+	return updated.ToResponse(), nil
+}
+
+// GetStatement returns a statement with its lines
+func (s *BankStatementService) GetStatement(ctx context.Context, id string) (*models.BankStatementResponse, error) {
+	statement, err := s.Repo.GetBankStatement(ctx, id)
+	if err != nil {
+		return nil, models.ErrStatementNotFound
+	}
+
+	lines, err := s.Repo.GetStatementLines(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := statement.ToResponse()
+	response.Lines = make([]models.BankStatementLineResponse, 0, len(lines))
+	for _, l := range lines {
+		response.Lines = append(response.Lines, *l.ToResponse())
+		if l.IsMatched() {
+			response.MatchedCount++
+		}
+	}
+	response.LineCount = len(lines)
+
+	return response, nil
+}
+
+// GetStatementsByAccount returns the statements imported for a Bank account
+func (s *BankStatementService) GetStatementsByAccount(ctx context.Context, accountID string) ([]models.BankStatementResponse, error) {
+	statements, err := s.Repo.GetBankStatementsByAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response list
+	responses := make([]models.BankStatementResponse, 0, len(statements))
+	for _, st := range statements {
+		responses = append(responses, *st.ToResponse())
+	}
+
+	return responses, nil
+}
+
+// DeleteStatement removes a statement and its lines, un-clearing any matched transactions
+func (s *BankStatementService) DeleteStatement(ctx context.Context, id string) error {
+	// Ensure exists before deleting
+	if _, err := s.Repo.GetBankStatement(ctx, id); err != nil {
+		return models.ErrStatementNotFound
+	}
+
+	return s.Repo.DeleteBankStatement(ctx, id)
+}
+
+// GetReconciliation reconciles a Bank account's books against its latest statement as of a date
+func (s *BankStatementService) GetReconciliation(ctx context.Context, accountID string, asOf time.Time) (*models.BankReconciliation, error) {
+	account, err := s.getBankAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.BankReconciliation{
+		AccountID:               account.ID,
+		AccountName:             account.AccountName,
+		AsOf:                    asOf,
+		UnclearedItems:          make([]models.BankBookEntry, 0),
+		UnmatchedStatementLines: make([]models.BankStatementLineResponse, 0),
+	}
+
+	// Statement balance from the latest statement ending by the reconciliation date
+	if statement, err := s.Repo.GetLatestBankStatement(ctx, accountID, asOf); err == nil {
+		report.StatementID = &statement.ID
+		if statement.ClosingBalance != nil {
+			report.StatementBalance = *statement.ClosingBalance
+		} else {
+			total, err := s.Repo.GetTotalStatementLines(ctx, statement.ID)
+			if err != nil {
+				return nil, err
+			}
+			if statement.OpeningBalance != nil {
+				total += *statement.OpeningBalance
+			}
+			report.StatementBalance = total
+		}
+	}
+
+	// Book items not yet cleared by a statement line are in transit
+	entries, err := s.Repo.GetBankBookEntries(ctx, accountID, asOf)
+	if err != nil {
+		return nil, err
+	}
+	cleared, err := s.clearedTransactions(ctx, accountID, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		report.BookBalance += entry.Amount
+		if cleared[entry.TransactionID] {
+			continue
+		}
+		report.UnclearedItems = append(report.UnclearedItems, entry)
+		if entry.Amount > 0 {
+			report.DepositsInTransit += entry.Amount
+		} else {
+			report.OutstandingPayments -= entry.Amount
+		}
+	}
+
+	// Statement lines with no matching transaction (e.g. bank charges) are not yet in the books
+	lines, err := s.Repo.GetStatementLinesByAccount(ctx, accountID, asOf)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range lines {
+		if line.IsMatched() {
+			continue
+		}
+		report.UnmatchedStatementLines = append(report.UnmatchedStatementLines, *line.ToResponse())
+		report.UnrecordedBankItems += line.Amount
+	}
+
+	report.ReconciledBalance = roundAmount(report.StatementBalance + report.DepositsInTransit - report.OutstandingPayments)
+	report.BookBalance = roundAmount(report.BookBalance)
+	report.DepositsInTransit = roundAmount(report.DepositsInTransit)
+	report.OutstandingPayments = roundAmount(report.OutstandingPayments)
+	report.UnrecordedBankItems = roundAmount(report.UnrecordedBankItems)
+	report.AdjustedBookBalance = roundAmount(report.BookBalance + report.UnrecordedBankItems)
+	report.Difference = roundAmount(report.ReconciledBalance - report.AdjustedBookBalance)
+	report.IsReconciled = amountsEqual(report.Difference, 0)
+
+	return report, nil
+}
+
+// clearedTransactions returns the transactions matched to statement lines dated on or before asOf
+func (s *BankStatementService) clearedTransactions(ctx context.Context, accountID string, asOf time.Time) (map[string]bool, error) {
+	lines, err := s.Repo.GetStatementLinesByAccount(ctx, accountID, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	cleared := make(map[string]bool)
+	for _, line := range lines {
+		if line.IsMatched() {
+			cleared[*line.TransactionID] = true
+		}
+	}
+	return cleared, nil
+}
+
+// getBankAccount fetches an account and checks that it is a Bank account
+func (s *BankStatementService) getBankAccount(ctx context.Context, accountID string) (models.Account, error) {
+	account, err := s.Repo.GetAccount(ctx, accountID)
+	if err != nil {
+		return models.Account{}, models.Wrap(models.ErrAccountNotFound, "bank account not found")
+	}
+	if account.AccountType != string(models.AccountBank) {
+		return models.Account{}, models.Invalid("statements can only be reconciled against a Bank account")
+	}
+	return account, nil
+}
+
+// bestBookEntry picks the uncleared book entry that best matches a statement line, or nil
+// when there is no candidate or the best candidates cannot be told apart
+func bestBookEntry(line models.BankStatementLine, entries []models.BankBookEntry, cleared map[string]bool, windowDays int) *models.BankBookEntry {
+	type candidate struct {
+		entry    *models.BankBookEntry
+		refMatch bool
+		days     int
+	}
+
+	candidates := make([]candidate, 0)
+	for i := range entries {
+		entry := &entries[i]
+		if cleared[entry.TransactionID] || !amountsEqual(entry.Amount, line.Amount) {
+			continue
+		}
+
+		days := int(math.Abs(entry.TransactionDate.Sub(line.LineDate).Hours()) / 24)
+		if days > windowDays {
+			continue
+		}
+
+		candidates = append(candidates, candidate{entry: entry, refMatch: referencesMatch(line, *entry), days: days})
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].refMatch != candidates[j].refMatch {
+			return candidates[i].refMatch
+		}
+		return candidates[i].days < candidates[j].days
+	})
+
+	if len(candidates) > 1 && candidates[1].refMatch == candidates[0].refMatch && candidates[1].days == candidates[0].days {
+		return nil
+	}
+	return candidates[0].entry
+}
+
+// referencesMatch reports whether the transaction reference appears on the statement line
+func referencesMatch(line models.BankStatementLine, entry models.BankBookEntry) bool {
+	if entry.TransactionRef == nil || strings.TrimSpace(*entry.TransactionRef) == "" {
+		return false
+	}
+	ref := strings.ToLower(strings.TrimSpace(*entry.TransactionRef))
+
+	if line.Reference != nil && strings.Contains(strings.ToLower(*line.Reference), ref) {
+		return true
+	}
+	return line.Description != nil && strings.Contains(strings.ToLower(*line.Description), ref)
+}
+
+// amountsEqual compares two money amounts to the cent
+func amountsEqual(a, b float64) bool {
+	return math.Abs(a-b) < 0.005
+}
+
+// roundAmount rounds a money amount to the cent
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,412 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type BudgetService struct {
+	Repo repository.Store
+}
+
+// Create a new instance of BudgetService
+func NewBudgetService(repo repository.Store) *BudgetService {
+	return &BudgetService{Repo: repo}
+}
+
+// SetBudget sets an account's budget for a year, replacing any existing monthly amounts
+func (s *BudgetService) SetBudget(ctx context.Context, req models.SetBudgetRequest, createdBy string) (*models.AccountBudgetResponse, error) {
+	account, err := s.getBudgetAccount(ctx, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save one line per month
+	for i, amount := range req.MonthlyAmounts() {
+		budget := models.Budget{
+			AccountID: account.ID,
+			Year:      req.Year,
+			Month:     i + 1,
+			Amount:    amount,
+			Notes:     req.Notes,
+			CreatedBy: createdBy,
+		}
+		if _, err := s.Repo.SaveBudget(ctx, budget); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetAccountBudget(ctx, account.ID, req.Year)
+}
+
+// ImportBudgetsCSV imports budgets from CSV with the header account,year,month,amount[,notes].
+// The account column may hold an account ID or name; an empty month spreads the amount
+// over the whole year. Nothing is imported if any row is invalid.
+func (s *BudgetService) ImportBudgetsCSV(ctx context.Context, r io.Reader, createdBy string) (*models.BudgetImportResult, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, models.Invalid(fmt.Sprintf("invalid CSV: %v", err))
+	}
+	if len(rows) < 2 {
+		return nil, models.Invalid("CSV file has no budget rows")
+	}
+
+	// Map header columns
+	columns := make(map[string]int)
+	for i, name := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"account", "year", "month", "amount"} {
+		if _, ok := columns[required]; !ok {
+			return nil, models.Invalid(fmt.Sprintf("CSV header is missing the %s column", required))
+		}
+	}
+
+	field := func(row []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	// Validate every row before saving anything
+	result := &models.BudgetImportResult{}
+	budgets := make([]models.Budget, 0, len(rows)-1)
+	accounts := make(map[string]models.Account)
+
+	for i, row := range rows[1:] {
+		line := i + 2
+
+		accountRef := field(row, "account")
+		account, ok := accounts[accountRef]
+		if !ok {
+			account, err = s.resolveBudgetAccount(ctx, accountRef)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("line %d: %v", line, err))
+				continue
+			}
+			accounts[accountRef] = account
+		}
+
+		year, err := strconv.Atoi(field(row, "year"))
+		if err != nil || year < 2000 || year > 2100 {
+			result.Errors = append(result.Errors, fmt.Sprintf("line %d: invalid budget year", line))
+			continue
+		}
+
+		amount, err := strconv.ParseFloat(strings.ReplaceAll(field(row, "amount"), ",", ""), 64)
+		if err != nil || amount < 0 {
+			result.Errors = append(result.Errors, fmt.Sprintf("line %d: invalid amount", line))
+			continue
+		}
+
+		var notes *string
+		if n := field(row, "notes"); n != "" {
+			notes = &n
+		}
+
+		// An empty month spreads the amount across the year
+		monthStr := field(row, "month")
+		if monthStr == "" {
+			req := models.SetBudgetRequest{AnnualAmount: &amount}
+			for m, monthly := range req.MonthlyAmounts() {
+				budgets = append(budgets, models.Budget{AccountID: account.ID, Year: year, Month: m + 1, Amount: monthly, Notes: notes, CreatedBy: createdBy})
+			}
+			continue
+		}
+
+		month, err := strconv.Atoi(monthStr)
+		if err != nil || month < 1 || month > 12 {
+			result.Errors = append(result.Errors, fmt.Sprintf("line %d: month must be between 1 and 12", line))
+			continue
+		}
+
+		budgets = append(budgets, models.Budget{AccountID: account.ID, Year: year, Month: month, Amount: amount, Notes: notes, CreatedBy: createdBy})
+	}
+
+	if len(result.Errors) > 0 {
+		return result, models.Invalid("budget import failed")
+	}
+
+	// Save to DB
+	for _, budget := range budgets {
+		if _, err := s.Repo.SaveBudget(ctx, budget); err != nil {
+			return nil, err
+		}
+		result.Imported++
+	}
+
+	return result, nil
+}
+
+// UpdateBudget handles updating a single monthly budget line
+func (s *BudgetService) UpdateBudget(ctx context.Context, id string, req models.UpdateBudgetRequest) (*models.BudgetResponse, error) {
+	// Fetch existing record
+	existing, err := s.Repo.GetBudget(ctx, id)
+	if err != nil {
+		return nil, models.ErrBudgetNotFound
+	}
+
+	// Apply updates only if fields are provided
+	if req.Amount != nil {
+		if *req.Amount < 0 {
+			return nil, models.Invalid("budget amount cannot be negative")
+		}
+		existing.Amount = *req.Amount
+	}
+	if req.Notes != nil {
+		existing.Notes = req.Notes
+	}
+
+	existing.UpdatedAt = time.Now()
+
+	// Persist update
+	updated, err := s.Repo.UpdateBudget(ctx, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.ToResponse(), nil
+}
+
+// DeleteAccountBudget removes an account's budget for a year
+func (s *BudgetService) DeleteAccountBudget(ctx context.Context, accountID string, year int) error {
+	// Ensure exists before deleting
+	count, err := s.Repo.CountBudgets(ctx, accountID, year)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return models.ErrBudgetNotFound
+	}
+
+	return s.Repo.DeleteBudgetsByAccount(ctx, accountID, year)
+}
+
+// GetAccountBudget returns an account's monthly budget for a year
+func (s *BudgetService) GetAccountBudget(ctx context.Context, accountID string, year int) (*models.AccountBudgetResponse, error) {
+	account, err := s.Repo.GetAccount(ctx, accountID)
+	if err != nil {
+		return nil, models.ErrAccountNotFound
+	}
+
+	budgets, err := s.Repo.GetBudgetsByAccount(ctx, accountID, year)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildAccountBudget(account, year, budgets), nil
+}
+
+// GetBudgetsByYear returns every account's budget for a year
+func (s *BudgetService) GetBudgetsByYear(ctx context.Context, year int) ([]models.AccountBudgetResponse, error) {
+	budgets, err := s.Repo.GetBudgetsByYear(ctx, year)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group the monthly lines by account
+	byAccount := make(map[string][]models.Budget)
+	order := make([]string, 0)
+	for _, b := range budgets {
+		if _, ok := byAccount[b.AccountID]; !ok {
+			order = append(order, b.AccountID)
+		}
+		byAccount[b.AccountID] = append(byAccount[b.AccountID], b)
+	}
+
+	responses := make([]models.AccountBudgetResponse, 0, len(order))
+	for _, accountID := range order {
+		account, err := s.Repo.GetAccount(ctx, accountID)
+		if err != nil {
+			continue
+		}
+		responses = append(responses, *buildAccountBudget(account, year, byAccount[accountID]))
+	}
+
+	return responses, nil
+}
+
+// GetVarianceReport compares budgeted amounts against actual receipts and expenditures
+// for a year, or for a single month when month is between 1 and 12
+func (s *BudgetService) GetVarianceReport(ctx context.Context, year, month int) (*models.BudgetVarianceReport, error) {
+	fromMonth, toMonth := 1, 12
+	if month != 0 {
+		if month < 1 || month > 12 {
+			return nil, models.Invalid("month must be between 1 and 12")
+		}
+		fromMonth, toMonth = month, month
+	}
+
+	accounts, err := s.Repo.GetAllAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.BudgetVarianceReport{
+		Year:     year,
+		Month:    month,
+		Income:   make([]models.BudgetVariance, 0),
+		Expenses: make([]models.BudgetVariance, 0),
+	}
+
+	for _, account := range accounts {
+		if account.AccountType != string(models.AccountIncome) && account.AccountType != string(models.AccountExpense) {
+			continue
+		}
+
+		variance, err := s.accountVariance(ctx, account, year, fromMonth, toMonth)
+		if err != nil {
+			return nil, err
+		}
+
+		// Skip accounts with neither a budget nor any activity
+		if variance.Budget == 0 && variance.Actual == 0 {
+			continue
+		}
+
+		if account.AccountType == string(models.AccountIncome) {
+			report.Income = append(report.Income, variance)
+		} else {
+			report.Expenses = append(report.Expenses, variance)
+		}
+	}
+
+	return report, nil
+}
+
+// CheckExpenditure returns a warning when spending amount from an account on the given
+// date would exceed what remains of the account's budget for that year
+func (s *BudgetService) CheckExpenditure(ctx context.Context, accountID string, amount float64, date time.Time) (*string, error) {
+	account, err := s.Repo.GetAccount(ctx, accountID)
+	if err != nil {
+		return nil, models.ErrAccountNotFound
+	}
+
+	// Accounts without a budget are not checked
+	count, err := s.Repo.CountBudgets(ctx, accountID, date.Year())
+	if err != nil || count == 0 {
+		return nil, err
+	}
+
+	variance, err := s.accountVariance(ctx, account, date.Year(), 1, 12)
+	if err != nil {
+		return nil, err
+	}
+
+	if amount <= variance.Remaining {
+		return nil, nil
+	}
+
+	warning := fmt.Sprintf("expenditure of %.2f exceeds the remaining %d budget for %s (%.2f of %.2f remaining)",
+		amount, date.Year(), account.AccountName, math.Max(variance.Remaining, 0), variance.Budget)
+	return &warning, nil
+}
+
+// accountVariance computes budget vs actual for an account over a range of months; a header
+// account rolls up the budgets and actuals of every account below it
+func (s *BudgetService) accountVariance(ctx context.Context, account models.Account, year, fromMonth, toMonth int) (models.BudgetVariance, error) {
+	budget, err := s.Repo.GetBudgetTotal(ctx, account.ID, year, fromMonth, toMonth)
+	if err != nil {
+		return models.BudgetVariance{}, err
+	}
+
+	startDate := time.Date(year, time.Month(fromMonth), 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(year, time.Month(toMonth)+1, 0, 0, 0, 0, 0, time.UTC)
+
+	var actual float64
+	if account.AccountType == string(models.AccountIncome) {
+		actual, err = s.Repo.GetTotalReceiptsByTransactionDate(ctx, account.ID, startDate, endDate)
+	} else {
+		actual, err = s.Repo.GetTotalExpendituresByTransactionDate(ctx, account.ID, startDate, endDate)
+	}
+	if err != nil {
+		return models.BudgetVariance{}, err
+	}
+
+	variance := models.BudgetVariance{
+		AccountID:   account.ID,
+		AccountName: account.AccountName,
+		AccountType: account.AccountType,
+		Code:        account.Code,
+		ParentID:    account.ParentID,
+		IsHeader:    account.IsHeader,
+		Budget:      budget,
+		Actual:      actual,
+		Remaining:   math.Round((budget-actual)*100) / 100,
+		OverBudget:  actual > budget,
+	}
+
+	// Positive variance is favourable: income above budget, expenses below it
+	if account.AccountType == string(models.AccountIncome) {
+		variance.Variance = math.Round((actual-budget)*100) / 100
+	} else {
+		variance.Variance = math.Round((budget-actual)*100) / 100
+	}
+
+	if budget > 0 {
+		variance.PercentConsumed = math.Round(actual/budget*10000) / 100
+	}
+
+	return variance, nil
+}
+
+// getBudgetAccount fetches an account and checks that it can carry a budget
+func (s *BudgetService) getBudgetAccount(ctx context.Context, accountID string) (models.Account, error) {
+	account, err := getPostingAccount(ctx, s.Repo, accountID, "account")
+	if err != nil {
+		return models.Account{}, err
+	}
+	if account.AccountType != string(models.AccountIncome) && account.AccountType != string(models.AccountExpense) {
+		return models.Account{}, models.Invalid("budgets can only be set for Income or Expense accounts")
+	}
+	return account, nil
+}
+
+// resolveBudgetAccount looks up a budget account by ID, falling back to its name
+func (s *BudgetService) resolveBudgetAccount(ctx context.Context, ref string) (models.Account, error) {
+	if ref == "" {
+		return models.Account{}, models.Invalid("account is required")
+	}
+	if account, err := s.getBudgetAccount(ctx, ref); err == nil {
+		return account, nil
+	}
+
+	account, err := s.Repo.GetAccountByName(ctx, ref)
+	if err != nil {
+		return models.Account{}, models.Wrap(models.ErrAccountNotFound, fmt.Sprintf("account %q not found", ref))
+	}
+	return s.getBudgetAccount(ctx, account.ID)
+}
+
+// buildAccountBudget combines an account's monthly lines into a yearly response
+func buildAccountBudget(account models.Account, year int, budgets []models.Budget) *models.AccountBudgetResponse {
+	response := &models.AccountBudgetResponse{
+		AccountID:   account.ID,
+		AccountName: account.AccountName,
+		AccountType: account.AccountType,
+		Year:        year,
+		Months:      make([]models.BudgetResponse, 0, len(budgets)),
+	}
+
+	for _, b := range budgets {
+		response.Annual += b.Amount
+		response.Months = append(response.Months, *b.ToResponse())
+	}
+	response.Annual = math.Round(response.Annual*100) / 100
+
+	return response
+}
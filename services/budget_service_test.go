@@ -0,0 +1,85 @@
+package services
+
+import (
+	"storeHouse/models"
+	"testing"
+	"time"
+)
+
+func (f *fixture) budget(accountID string, annual float64) {
+	f.t.Helper()
+	_, err := NewBudgetService(f.repo).SetBudget(f.ctx, models.SetBudgetRequest{
+		AccountID:    accountID,
+		Year:         time.Now().Year(),
+		AnnualAmount: &annual,
+	}, "admin")
+	if err != nil {
+		f.t.Fatalf("set budget: %v", err)
+	}
+}
+
+func TestExpenditureOverBudgetIsWarned(t *testing.T) {
+	f := newFixture(t)
+	bank := f.account("Bank", models.AccountBank)
+	expense := f.account("Utilities", models.AccountExpense)
+	f.budget(expense, 12000)
+	f.post(f.voucher(expense, bank, nil, 10000))
+
+	txn, err := NewTransactionService(f.repo).CreateTransaction(f.ctx, models.CreateTransactionRequest{
+		TransactionType: string(models.TransactionExpenses),
+		Amount:          4500,
+		DebitAccountID:  expense,
+	}, "clerk")
+	if err != nil {
+		t.Fatalf("create expenses voucher: %v", err)
+	}
+
+	within, err := f.expenditure(txn.ID, bank, nil, 1500)
+	if err != nil {
+		t.Fatalf("expenditure within budget: %v", err)
+	}
+	if within.BudgetWarning != nil {
+		t.Fatalf("got warning %q for 1500 of 2000 remaining, want none", *within.BudgetWarning)
+	}
+
+	// The warning does not block the expenditure
+	over, err := f.expenditure(txn.ID, bank, nil, 3000)
+	if err != nil {
+		t.Fatalf("expenditure over budget: %v", err)
+	}
+	if over.BudgetWarning == nil {
+		t.Fatal("got no warning for 3000 of 2000 remaining")
+	}
+}
+
+func TestUnpostedVouchersDoNotUseBudget(t *testing.T) {
+	f := newFixture(t)
+	bank := f.account("Bank", models.AccountBank)
+	expense := f.account("Utilities", models.AccountExpense)
+	f.budget(expense, 12000)
+	f.voucher(expense, bank, nil, 10000)
+
+	txn := f.voucher(expense, bank, nil, 500)
+	line, err := f.expenditure(txn, bank, nil, 2000)
+	if err != nil {
+		t.Fatalf("create expenditure: %v", err)
+	}
+	if line.BudgetWarning != nil {
+		t.Fatalf("got warning %q with only a draft voucher against the budget", *line.BudgetWarning)
+	}
+}
+
+func TestAccountWithoutBudgetIsNotWarned(t *testing.T) {
+	f := newFixture(t)
+	bank := f.account("Bank", models.AccountBank)
+	expense := f.account("Utilities", models.AccountExpense)
+
+	txn := f.voucher(expense, bank, nil, 500)
+	line, err := f.expenditure(txn, bank, nil, 50000)
+	if err != nil {
+		t.Fatalf("create expenditure: %v", err)
+	}
+	if line.BudgetWarning != nil {
+		t.Fatalf("got warning %q for an account without a budget", *line.BudgetWarning)
+	}
+}
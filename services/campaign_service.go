@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"time"
+)
+
+type CampaignService struct {
+	Repo repository.Store
+}
+
+// Create a new instance of CampaignService
+func NewCampaignService(repo repository.Store) *CampaignService {
+	return &CampaignService{Repo: repo}
+}
+
+// CreateCampaign handles campaign creation business logic
+func (s *CampaignService) CreateCampaign(ctx context.Context, req models.CreateCampaignRequest, createdBy string) (*models.CampaignResponse, error) {
+	// Campaigns must collect into an Income account
+	account, err := getPostingAccount(ctx, s.Repo, req.IncomeAccountID, "income account")
+	if err != nil {
+		return nil, err
+	}
+	if account.AccountType != string(models.AccountIncome) {
+		return nil, models.Invalid("campaign account must be an Income account")
+	}
+
+	// Check for duplicate campaign name
+	if _, err := s.Repo.GetCampaignByName(ctx, req.CampaignName); err == nil {
+		return nil, models.Conflict("campaign name already exists")
+	}
+
+	// Prepare model for DB
+	campaign := models.Campaign{
+		CampaignName:    req.CampaignName,
+		IncomeAccountID: req.IncomeAccountID,
+		TargetAmount:    req.TargetAmount,
+		EndDate:         req.EndDate,
+		Notes:           req.Notes,
+		IsActive:        true,
+		CreatedBy:       createdBy,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+
+	if req.StartDate != nil && !req.StartDate.IsZero() {
+		campaign.StartDate = *req.StartDate
+	}
+
+	// Save to DB
+	newCampaign, err := s.Repo.CreateCampaign(ctx, campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCampaign.ToResponse(), nil
+}
+
+// UpdateCampaign handles update logic
+func (s *CampaignService) UpdateCampaign(ctx context.Context, id string, req models.UpdateCampaignRequest) (*models.CampaignResponse, error) {
+	// Fetch existing record
+	existing, err := s.Repo.GetCampaign(ctx, id)
+	if err != nil {
+		return nil, models.ErrCampaignNotFound
+	}
+
+	// Apply updates only if fields are provided
+	if req.CampaignName != nil {
+		// Check for duplicate campaign name if different
+		if existing.CampaignName != *req.CampaignName {
+			if _, err := s.Repo.GetCampaignByName(ctx, *req.CampaignName); err == nil {
+				return nil, models.Conflict("campaign name already exists")
+			}
+		}
+		existing.CampaignName = *req.CampaignName
+	}
+	if req.TargetAmount != nil {
+		if *req.TargetAmount <= 0 {
+			return nil, models.Invalid("target amount must be greater than zero")
+		}
+		existing.TargetAmount = req.TargetAmount
+	}
+	if req.StartDate != nil && !req.StartDate.IsZero() {
+		existing.StartDate = *req.StartDate
+	}
+	if req.EndDate != nil {
+		existing.EndDate = req.EndDate
+	}
+	if existing.EndDate != nil && existing.EndDate.Before(existing.StartDate) {
+		return nil, models.Invalid("end date must be after start date")
+	}
+	if req.Notes != nil {
+		existing.Notes = req.Notes
+	}
+	if req.IsActive != nil {
+		existing.IsActive = *req.IsActive
+	}
+
+	existing.UpdatedAt = time.Now()
+
+	// Persist update
+	updated, err := s.Repo.UpdateCampaign(ctx, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.ToResponse(), nil
+}
+
+// GetCampaign returns single campaign details
+func (s *CampaignService) GetCampaign(ctx context.Context, id string) (*models.CampaignResponse, error) {
+	campaign, err := s.Repo.GetCampaign(ctx, id)
+	if err != nil {
+		return nil, models.ErrCampaignNotFound
+	}
+	return campaign.ToResponse(), nil
+}
+
+// GetAllCampaigns returns all campaigns
+func (s *CampaignService) GetAllCampaigns(ctx context.Context) ([]models.CampaignResponse, error) {
+	campaigns, err := s.Repo.GetAllCampaigns(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response list
+	responses := make([]models.CampaignResponse, 0, len(campaigns))
+	for _, c := range campaigns {
+		responses = append(responses, *c.ToResponse())
+	}
+
+	return responses, nil
+}
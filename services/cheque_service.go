@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"strings"
+	"time"
+)
+
+type ChequeService struct {
+	Repo repository.Store
+}
+
+// Create a new instance of ChequeService
+func NewChequeService(repo repository.Store) *ChequeService {
+	return &ChequeService{Repo: repo}
+}
+
+// IssueCheque records a cheque written from a Bank account. When linked to an expense or
+// withdrawal voucher the bank account, amount and payee default to the voucher's, and the
+// voucher's payment method is set to cheque with the cheque number as its reference.
+func (s *ChequeService) IssueCheque(ctx context.Context, req models.IssueChequeRequest, createdBy *string) (*models.ChequeResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Prepare model for DB
+	cheque := models.Cheque{
+		ChequeNumber:  strings.TrimSpace(req.ChequeNumber),
+		BankAccountID: req.BankAccountID,
+		TransactionID: emptyToNil(req.TransactionID),
+		PayeeID:       emptyToNil(req.PayeeID),
+		PayeeName:     strings.TrimSpace(req.PayeeName),
+		Amount:        req.Amount,
+		IssueDate:     time.Now(),
+		Status:        string(models.ChequeIssued),
+		Notes:         trimToNil(req.Notes),
+		CreatedBy:     createdBy,
+	}
+	if req.IssueDate != nil {
+		cheque.IssueDate = *req.IssueDate
+	}
+
+	if cheque.TransactionID != nil {
+		if err := s.applyTransaction(ctx, &cheque); err != nil {
+			return nil, err
+		}
+	}
+
+	if cheque.PayeeID != nil {
+		payee, err := s.Repo.GetPayee(ctx, *cheque.PayeeID)
+		if err != nil {
+			return nil, models.ErrPayeeNotFound
+		}
+		if cheque.PayeeName == "" {
+			cheque.PayeeName = payee.Name
+		}
+	}
+
+	if cheque.BankAccountID == "" {
+		return nil, models.Invalid("bank_account_id is required")
+	}
+	if cheque.PayeeName == "" {
+		return nil, models.Invalid("payee_name is required")
+	}
+	if cheque.Amount <= 0 {
+		return nil, models.Invalid("amount must be greater than zero")
+	}
+	cheque.Amount = roundAmount(cheque.Amount)
+
+	account, err := getPostingAccount(ctx, s.Repo, cheque.BankAccountID, "bank account")
+	if err != nil {
+		return nil, err
+	}
+	if account.AccountType != string(models.AccountBank) {
+		return nil, models.Invalid("cheques can only be issued from a Bank account")
+	}
+
+	if _, err := s.Repo.GetChequeByNumber(ctx, cheque.BankAccountID, cheque.ChequeNumber); err == nil {
+		return nil, models.Conflict("cheque number " + cheque.ChequeNumber + " is already recorded for this bank account")
+	}
+
+	// Save to DB
+	newCheque, err := s.Repo.CreateCheque(ctx, cheque)
+	if err != nil {
+		return nil, err
+	}
+
+	if newCheque.TransactionID != nil {
+		method := string(models.PaymentCheque)
+		if err := s.Repo.UpdateTransactionPayment(ctx, *newCheque.TransactionID, &method, &newCheque.ChequeNumber); err != nil {
+			return nil, err
+		}
+	}
+
+	return newCheque.ToResponse(), nil
+}
+
+// applyTransaction fills the bank account, amount and payee from the linked voucher
+func (s *ChequeService) applyTransaction(ctx context.Context, cheque *models.Cheque) error {
+	txn, err := s.Repo.GetTransaction(ctx, *cheque.TransactionID)
+	if err != nil {
+		return models.ErrTransactionNotFound
+	}
+	if txn.TransactionType == string(models.TransactionReceipts) {
+		return models.Invalid("cheques can only be linked to payments, not receipts")
+	}
+
+	lines, err := s.Repo.GetExpenditureByTransaction(ctx, txn.ID)
+	if err != nil {
+		return err
+	}
+	if cheque.BankAccountID == "" && len(lines) > 0 {
+		cheque.BankAccountID = lines[0].BankAccountID
+	}
+	if cheque.Amount == 0 {
+		cheque.Amount = txn.Amount
+	}
+	if cheque.PayeeID == nil {
+		for _, line := range lines {
+			if line.PayeeID != nil {
+				cheque.PayeeID = line.PayeeID
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// PresentCheque records that an issued cheque was banked by the payee
+func (s *ChequeService) PresentCheque(ctx context.Context, id string, req models.PresentChequeRequest) (*models.ChequeResponse, error) {
+	// Fetch existing record
+	cheque, err := s.Repo.GetCheque(ctx, id)
+	if err != nil {
+		return nil, models.ErrChequeNotFound
+	}
+
+	if cheque.Status != string(models.ChequeIssued) {
+		return nil, models.Conflict("only issued cheques can be presented; this cheque is " + cheque.Status)
+	}
+
+	presented := time.Now()
+	if req.PresentedDate != nil {
+		presented = *req.PresentedDate
+	}
+	if presented.Before(cheque.IssueDate) {
+		return nil, models.Invalid("presented_date must not be before the issue date")
+	}
+	cheque.Status = string(models.ChequePresented)
+	cheque.PresentedDate = &presented
+
+	// Persist update
+	updated, err := s.Repo.UpdateCheque(ctx, cheque)
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.ToResponse(), nil
+}
+
+// CancelCheque voids an issued or stale cheque
+func (s *ChequeService) CancelCheque(ctx context.Context, id string, req models.CancelChequeRequest) (*models.ChequeResponse, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, models.Invalid("reason is required")
+	}
+
+	// Fetch existing record
+	cheque, err := s.Repo.GetCheque(ctx, id)
+	if err != nil {
+		return nil, models.ErrChequeNotFound
+	}
+
+	if cheque.Status != string(models.ChequeIssued) && cheque.Status != string(models.ChequeStale) {
+		return nil, models.Conflict("only issued or stale cheques can be cancelled; this cheque is " + cheque.Status)
+	}
+
+	now := time.Now()
+	cheque.Status = string(models.ChequeCancelled)
+	cheque.CancelledAt = &now
+	cheque.CancelReason = &reason
+
+	// Persist update
+	updated, err := s.Repo.UpdateCheque(ctx, cheque)
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.ToResponse(), nil
+}
+
+// GetCheque returns a cheque by ID
+func (s *ChequeService) GetCheque(ctx context.Context, id string) (*models.ChequeResponse, error) {
+	if err := s.markStale(ctx); err != nil {
+		return nil, err
+	}
+
+	cheque, err := s.Repo.GetCheque(ctx, id)
+	if err != nil {
+		return nil, models.ErrChequeNotFound
+	}
+
+	return cheque.ToResponse(), nil
+}
+
+// GetCheques lists cheques, optionally for one bank account and status
+func (s *ChequeService) GetCheques(ctx context.Context, bankAccountID, status string) ([]models.ChequeResponse, error) {
+	switch models.ChequeStatus(status) {
+	case "", models.ChequeIssued, models.ChequePresented, models.ChequeCancelled, models.ChequeStale:
+	default:
+		return nil, models.Invalid("invalid cheque status")
+	}
+
+	if err := s.markStale(ctx); err != nil {
+		return nil, err
+	}
+
+	cheques, err := s.Repo.GetCheques(ctx, bankAccountID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response list
+	responses := make([]models.ChequeResponse, 0, len(cheques))
+	for _, c := range cheques {
+		responses = append(responses, *c.ToResponse())
+	}
+	return responses, nil
+}
+
+// GetOutstandingCheques reports the cheques that had been issued but not presented or
+// cancelled as of the given date, per Bank account. Cheques past their stale date are
+// listed separately since they can no longer clear.
+func (s *ChequeService) GetOutstandingCheques(ctx context.Context, bankAccountID string, asOf time.Time) ([]models.OutstandingChequesReport, error) {
+	if err := s.markStale(ctx); err != nil {
+		return nil, err
+	}
+
+	accountIDs := []string{bankAccountID}
+	if bankAccountID == "" {
+		ids, err := s.Repo.GetChequeBankAccounts(ctx)
+		if err != nil {
+			return nil, err
+		}
+		accountIDs = ids
+	}
+
+	reports := make([]models.OutstandingChequesReport, 0, len(accountIDs))
+	for _, accountID := range accountIDs {
+		account, err := s.Repo.GetAccount(ctx, accountID)
+		if err != nil {
+			return nil, models.Wrap(models.ErrAccountNotFound, "bank account not found")
+		}
+		if account.AccountType != string(models.AccountBank) {
+			return nil, models.Invalid("account is not a Bank account")
+		}
+
+		cheques, err := s.Repo.GetChequesIssuedBy(ctx, accountID, asOf)
+		if err != nil {
+			return nil, err
+		}
+
+		report := models.OutstandingChequesReport{
+			BankAccountID:   account.ID,
+			BankAccountName: account.AccountName,
+			AsOf:            asOf,
+			Cheques:         []models.ChequeResponse{},
+			StaleCheques:    []models.ChequeResponse{},
+		}
+		for _, c := range cheques {
+			if !c.OutstandingAt(asOf) {
+				continue
+			}
+			if c.StaleDate().Before(asOf) {
+				report.StaleCount++
+				report.StaleTotal += c.Amount
+				report.StaleCheques = append(report.StaleCheques, *c.ToResponse())
+				continue
+			}
+			report.OutstandingCount++
+			report.OutstandingTotal += c.Amount
+			report.Cheques = append(report.Cheques, *c.ToResponse())
+		}
+		report.OutstandingTotal = roundAmount(report.OutstandingTotal)
+		report.StaleTotal = roundAmount(report.StaleTotal)
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// markStale flags issued cheques older than the stale period
+func (s *ChequeService) markStale(ctx context.Context) error {
+	_, err := s.Repo.MarkStaleCheques(ctx, time.Now().AddDate(0, -models.ChequeStaleAfterMonths, 0))
+	return err
+}
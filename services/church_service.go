@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"strings"
+)
+
+type ChurchService struct {
+	Repo repository.Store
+}
+
+// Create a new instance of ChurchService
+func NewChurchService(repo repository.Store) *ChurchService {
+	return &ChurchService{Repo: repo}
+}
+
+// GetMyChurches returns the churches a user works in, with their role in each
+func (s *ChurchService) GetMyChurches(ctx context.Context, userID string) ([]models.ChurchResponse, error) {
+	churches, err := s.Repo.GetUserChurches(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response list
+	responses := make([]models.ChurchResponse, 0, len(churches))
+	for _, c := range churches {
+		response := c.Church.ToResponse()
+		response.Role = c.Role
+		responses = append(responses, *response)
+	}
+	return responses, nil
+}
+
+// CreateChurch adds a church; the user who creates it becomes its admin
+func (s *ChurchService) CreateChurch(ctx context.Context, req models.CreateChurchRequest, userID string) (*models.ChurchResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	code, _ := models.NormalizeChurchCode(req.Code)
+	if _, err := s.Repo.GetChurchByCode(ctx, code); err == nil {
+		return nil, models.Conflict("church code " + code + " already exists")
+	}
+
+	// Prepare model for DB
+	church := models.Church{
+		Name:     strings.TrimSpace(req.Name),
+		Code:     code,
+		IsActive: true,
+	}
+	if err := s.setShortcode(ctx, &church, req.MpesaShortcode); err != nil {
+		return nil, err
+	}
+
+	// Save to DB
+	newChurch, err := s.Repo.CreateChurch(ctx, church, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := newChurch.ToResponse()
+	response.Role = string(models.RoleAdmin)
+	return response, nil
+}
+
+// GetChurch returns a church the user works in
+func (s *ChurchService) GetChurch(ctx context.Context, id, userID string) (*models.ChurchResponse, error) {
+	role, err := s.Repo.GetChurchRole(ctx, id, userID)
+	if err != nil {
+		return nil, models.ErrChurchNotFound
+	}
+	church, err := s.Repo.GetChurch(ctx, id)
+	if err != nil {
+		return nil, models.ErrChurchNotFound
+	}
+
+	response := church.ToResponse()
+	response.Role = role
+	return response, nil
+}
+
+// UpdateChurch handles update logic; only the church's admins can change it
+func (s *ChurchService) UpdateChurch(ctx context.Context, id string, req models.UpdateChurchRequest, userID string) (*models.ChurchResponse, error) {
+	if err := s.requireChurchAdmin(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	// Fetch existing record
+	existing, err := s.Repo.GetChurch(ctx, id)
+	if err != nil {
+		return nil, models.ErrChurchNotFound
+	}
+
+	// Apply updates only if fields are provided
+	if req.Name != nil {
+		if strings.TrimSpace(*req.Name) == "" {
+			return nil, models.Invalid("name is required")
+		}
+		existing.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.MpesaShortcode != nil {
+		if err := s.setShortcode(ctx, &existing, req.MpesaShortcode); err != nil {
+			return nil, err
+		}
+	}
+	if req.IsActive != nil {
+		existing.IsActive = *req.IsActive
+	}
+
+	// Persist update
+	updated, err := s.Repo.UpdateChurch(ctx, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	response := updated.ToResponse()
+	response.Role = string(models.RoleAdmin)
+	return response, nil
+}
+
+// GetChurchUsers lists the users who work in a church
+func (s *ChurchService) GetChurchUsers(ctx context.Context, id, userID string) ([]models.ChurchUserResponse, error) {
+	if err := s.requireChurchAdmin(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	users, err := s.Repo.GetChurchUsers(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if users == nil {
+		users = []models.ChurchUserResponse{}
+	}
+	return users, nil
+}
+
+// SaveChurchUser gives a user access to a church or changes their role there. A role left
+// out means the user's own role. Church admins can only add users who already work in the
+// church or were created through it; adding a user of another church takes a deployment
+// admin.
+func (s *ChurchService) SaveChurchUser(ctx context.Context, id string, req models.AddChurchUserRequest, userID string) ([]models.ChurchUserResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.requireChurchAdmin(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.Repo.GetUser(ctx, req.UserID)
+	if err != nil {
+		return nil, models.ErrUserNotFound
+	}
+	if err := s.requireLinkable(ctx, id, user, userID); err != nil {
+		return nil, err
+	}
+
+	newRole := user.Role
+	if req.Role != nil {
+		newRole = *req.Role
+	}
+	if newRole != string(models.RoleAdmin) {
+		if err := s.ensureAnotherAdmin(ctx, id, req.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	member := models.ChurchUser{ChurchID: id, UserID: req.UserID, Role: newRole}
+	if err := s.Repo.SaveChurchUser(ctx, member); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.GetChurchUsers(ctx, id)
+}
+
+// RemoveChurchUser takes a user's access to a church away
+func (s *ChurchService) RemoveChurchUser(ctx context.Context, id, memberID, userID string) error {
+	if err := s.requireChurchAdmin(ctx, id, userID); err != nil {
+		return err
+	}
+	if _, err := s.Repo.GetChurchRole(ctx, id, memberID); err != nil {
+		return models.Invalid("user does not work in this church")
+	}
+	if err := s.ensureAnotherAdmin(ctx, id, memberID); err != nil {
+		return err
+	}
+
+	return s.Repo.RemoveChurchUser(ctx, id, memberID)
+}
+
+// requireChurchAdmin makes sure the user is an admin in the church
+func (s *ChurchService) requireChurchAdmin(ctx context.Context, churchID, userID string) error {
+	role, err := s.Repo.GetChurchRole(ctx, churchID, userID)
+	if err != nil {
+		return models.ErrChurchNotFound
+	}
+	if role != string(models.RoleAdmin) {
+		return models.ErrChurchAdminOnly
+	}
+	return nil
+}
+
+// requireLinkable makes sure the user already works in the church or was created through
+// it, unless the caller is a deployment admin
+func (s *ChurchService) requireLinkable(ctx context.Context, churchID string, user models.User, callerID string) error {
+	if _, err := s.Repo.GetChurchRole(ctx, churchID, user.ID); err == nil {
+		return nil
+	}
+	if user.CreatedIn != nil && *user.CreatedIn == churchID {
+		return nil
+	}
+
+	caller, err := s.Repo.GetUser(ctx, callerID)
+	if err != nil || caller.Role != string(models.RoleAdmin) {
+		return models.ErrUserOfOtherChurch
+	}
+	return nil
+}
+
+// ensureAnotherAdmin stops the last admin of a church from losing the role
+func (s *ChurchService) ensureAnotherAdmin(ctx context.Context, churchID, memberID string) error {
+	role, err := s.Repo.GetChurchRole(ctx, churchID, memberID)
+	if err != nil || role != string(models.RoleAdmin) {
+		return nil
+	}
+
+	admins, err := s.Repo.CountChurchAdmins(ctx, churchID)
+	if err != nil {
+		return err
+	}
+	if admins <= 1 {
+		return models.Conflict("a church needs at least one admin")
+	}
+	return nil
+}
+
+// setShortcode sets the church's paybill short code, which no other church may use; an
+// empty value clears it
+func (s *ChurchService) setShortcode(ctx context.Context, church *models.Church, shortcode *string) error {
+	code := trimToNil(shortcode)
+	if code != nil {
+		if other, err := s.Repo.GetChurchByShortcode(ctx, *code); err == nil && other.ID != church.ID {
+			return models.Conflict("mpesa_shortcode " + *code + " already exists")
+		}
+	}
+	church.MpesaShortcode = code
+	return nil
+}
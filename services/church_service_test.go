@@ -0,0 +1,68 @@
+package services
+
+import (
+	"net/http"
+	"storeHouse/models"
+	"testing"
+)
+
+// church seeds a church whose first admin is a deployment admin, returning both IDs
+func (f *fixture) church(code string) (string, string) {
+	f.t.Helper()
+	church, admin, _, _, err := f.repo.SeedChurch(f.ctx,
+		models.Church{Name: code + " church", Code: code, IsActive: true},
+		models.User{Username: code + "-admin", Email: code + "@example.com", FullName: code + " admin", Role: string(models.RoleAdmin), IsActive: true},
+		nil, nil)
+	if err != nil {
+		f.t.Fatalf("seed church %s: %v", code, err)
+	}
+	return church.ID, admin.ID
+}
+
+// user adds a clerk who was created through the given church, or none
+func (f *fixture) user(username string, createdIn *string) string {
+	f.t.Helper()
+	user, err := f.repo.CreateUser(f.ctx, models.User{
+		Username:  username,
+		Email:     username + "@example.com",
+		FullName:  username,
+		Role:      string(models.RoleClerk),
+		IsActive:  true,
+		CreatedIn: createdIn,
+	})
+	if err != nil {
+		f.t.Fatalf("create user %s: %v", username, err)
+	}
+	return user.ID
+}
+
+func TestChurchAdminOnlyLinksTheirOwnUsers(t *testing.T) {
+	f := newFixture(t)
+	churchA, deploymentAdmin := f.church("A")
+	churchB, _ := f.church("B")
+	churches := NewChurchService(f.repo)
+
+	// A clerk of the deployment who is an admin of church A only
+	admin := f.user("pastor", nil)
+	if err := f.repo.SaveChurchUser(f.ctx, models.ChurchUser{ChurchID: churchA, UserID: admin, Role: string(models.RoleAdmin)}); err != nil {
+		t.Fatalf("link church admin: %v", err)
+	}
+
+	outsider := f.user("outsider", &churchB)
+	_, err := churches.SaveChurchUser(f.ctx, churchA, models.AddChurchUserRequest{UserID: outsider}, admin)
+	wantStatus(t, err, http.StatusForbidden)
+
+	// Users created through the church can be given access again
+	former := f.user("former", &churchA)
+	if _, err := churches.SaveChurchUser(f.ctx, churchA, models.AddChurchUserRequest{UserID: former}, admin); err != nil {
+		t.Fatalf("link user created through the church: %v", err)
+	}
+
+	if _, err := churches.SaveChurchUser(f.ctx, churchA, models.AddChurchUserRequest{UserID: outsider}, deploymentAdmin); err != nil {
+		t.Fatalf("deployment admin links user of another church: %v", err)
+	}
+	role, err := f.repo.GetChurchRole(f.ctx, churchA, outsider)
+	if err != nil || role != string(models.RoleClerk) {
+		t.Fatalf("got role %q (%v), want the user's own Clerk role", role, err)
+	}
+}
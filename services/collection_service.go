@@ -0,0 +1,385 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"strings"
+	"time"
+)
+
+type CollectionService struct {
+	Repo repository.Store
+}
+
+// Create a new instance of CollectionService
+func NewCollectionService(repo repository.Store) *CollectionService {
+	return &CollectionService{Repo: repo}
+}
+
+// CreateSession opens a collection session for a service, banked into the given account
+func (s *CollectionService) CreateSession(ctx context.Context, req models.CreateCollectionSessionRequest, createdBy *string) (*models.CollectionSessionResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	account, err := getPostingAccount(ctx, s.Repo, req.BankAccountID, "bank account")
+	if err != nil {
+		return nil, err
+	}
+	if account.AccountType != string(models.AccountBank) {
+		return nil, models.Invalid("offering can only be banked into a Bank account")
+	}
+
+	// Prepare model for DB
+	session := models.CollectionSession{
+		ServiceName:   strings.TrimSpace(req.ServiceName),
+		ServiceDate:   time.Now(),
+		BankAccountID: req.BankAccountID,
+		Status:        string(models.CollectionOpen),
+		Notes:         trimToNil(req.Notes),
+		CreatedBy:     createdBy,
+	}
+	if req.ServiceDate != nil && !req.ServiceDate.IsZero() {
+		session.ServiceDate = *req.ServiceDate
+	}
+
+	// Save to DB
+	newSession, err := s.Repo.CreateCollectionSession(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(ctx, newSession)
+}
+
+// GetSession returns a session with its receipts, cash count and sign-offs
+func (s *CollectionService) GetSession(ctx context.Context, id string) (*models.CollectionSessionResponse, error) {
+	session, err := s.Repo.GetCollectionSession(ctx, id)
+	if err != nil {
+		return nil, models.ErrCollectionNotFound
+	}
+
+	return s.toResponse(ctx, session)
+}
+
+// GetSessions lists sessions, optionally with one status
+func (s *CollectionService) GetSessions(ctx context.Context, status string) ([]models.CollectionSessionResponse, error) {
+	switch models.CollectionSessionStatus(status) {
+	case "", models.CollectionOpen, models.CollectionPosted, models.CollectionCancelled:
+	default:
+		return nil, models.Invalid("invalid collection session status")
+	}
+
+	sessions, err := s.Repo.GetCollectionSessions(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response list
+	responses := make([]models.CollectionSessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		resp, err := s.toResponse(ctx, session)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, *resp)
+	}
+	return responses, nil
+}
+
+// AddReceipt records an envelope or the loose offering in an open session. The receipt is
+// held as a draft, out of the books, until the session is posted.
+func (s *CollectionService) AddReceipt(ctx context.Context, sessionID string, req models.AddCollectionReceiptRequest, createdBy string) (*models.TransactionResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	session, err := s.openSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	memberID := emptyToNil(req.MemberID)
+	if memberID != nil {
+		if _, err := s.Repo.GetMember(ctx, *memberID); err != nil {
+			return nil, models.ErrMemberNotFound
+		}
+	}
+
+	method := string(models.PaymentCash)
+	if m := emptyToNil(req.PaymentMethod); m != nil {
+		if err := models.ValidatePaymentMethod(*m); err != nil {
+			return nil, err
+		}
+		method = *m
+	}
+
+	var total float64
+	for _, line := range req.Lines {
+		if _, err := getPostingAccount(ctx, s.Repo, line.IncomeAccountID, "income account"); err != nil {
+			return nil, err
+		}
+		if err := NewFundService(s.Repo).CheckFund(ctx, emptyToNil(line.FundID)); err != nil {
+			return nil, err
+		}
+		total += line.Amount
+	}
+
+	// Prepare model for DB
+	txn := models.Transaction{
+		TransactionDate:     session.ServiceDate,
+		TransactionType:     string(models.TransactionReceipts),
+		Amount:              roundAmount(total),
+		Notes:               trimToNil(req.Notes),
+		DebitAccountID:      session.BankAccountID,
+		MemberID:            memberID,
+		PaymentMethod:       &method,
+		PaymentReference:    trimToNil(req.PaymentReference),
+		CollectionSessionID: &session.ID,
+		Status:              string(models.TransactionDraft),
+		CreatedBy:           createdBy,
+	}
+
+	// Save to DB
+	newTxn, err := s.Repo.CreateTransaction(ctx, txn)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range req.Lines {
+		receipt := models.Receipt{
+			TransactionID:   newTxn.ID,
+			IncomeAccountID: line.IncomeAccountID,
+			FundID:          emptyToNil(line.FundID),
+			Amount:          roundAmount(line.Amount),
+		}
+		if _, err := s.Repo.CreateReceipt(ctx, receipt); err != nil {
+			return nil, err
+		}
+	}
+
+	// The counters confirmed different figures, so they count again
+	if err := s.Repo.ClearCollectionSignOffs(ctx, session.ID); err != nil {
+		return nil, err
+	}
+
+	return newTxn.ToResponse(), nil
+}
+
+// RemoveReceipt takes a held receipt out of an open session
+func (s *CollectionService) RemoveReceipt(ctx context.Context, sessionID, transactionID string) error {
+	session, err := s.openSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	txn, err := s.Repo.GetTransaction(ctx, transactionID)
+	if err != nil || txn.CollectionSessionID == nil || *txn.CollectionSessionID != session.ID {
+		return models.ErrReceiptNotFound
+	}
+
+	if err := s.Repo.DeleteCollectionReceipt(ctx, txn.ID); err != nil {
+		return err
+	}
+
+	return s.Repo.ClearCollectionSignOffs(ctx, session.ID)
+}
+
+// SetDenominations records the cash count by note and coin, replacing any earlier count
+func (s *CollectionService) SetDenominations(ctx context.Context, sessionID string, req models.SetDenominationsRequest) (*models.CollectionSessionResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	session, err := s.openSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	denominations := make([]models.CollectionDenomination, 0, len(req.Denominations))
+	for _, d := range req.Denominations {
+		if d.Quantity == 0 {
+			continue
+		}
+		denominations = append(denominations, models.CollectionDenomination{
+			Denomination: d.Denomination,
+			Quantity:     d.Quantity,
+		})
+	}
+
+	if _, err := s.Repo.ReplaceCollectionDenominations(ctx, session.ID, denominations); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.ClearCollectionSignOffs(ctx, session.ID); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(ctx, session)
+}
+
+// SignOff records a counter's confirmation of the cash counted and the receipts total.
+// Once two different counters have confirmed the current figures, the session's receipts
+// are posted to the ledger in the same database transaction as the last sign-off.
+func (s *CollectionService) SignOff(ctx context.Context, sessionID string, req models.SignOffCollectionRequest, userID string) (*models.CollectionSessionResponse, error) {
+	session, err := s.openSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.toResponse(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Receipts) == 0 {
+		return nil, models.Invalid("add the session's receipts before signing off")
+	}
+	if resp.CashVariance != 0 {
+		return nil, models.Conflict(fmt.Sprintf("count does not balance: cash counted %.2f, cash receipts %.2f", resp.CashCounted, resp.CashReceipts))
+	}
+	if roundAmount(req.CashTotal) != resp.CashCounted {
+		return nil, models.Conflict(fmt.Sprintf("count does not match: cash_total %.2f, cash counted %.2f", req.CashTotal, resp.CashCounted))
+	}
+	if roundAmount(req.ReceiptsTotal) != resp.ReceiptsTotal {
+		return nil, models.Conflict(fmt.Sprintf("count does not match: receipts_total %.2f, receipts entered %.2f", req.ReceiptsTotal, resp.ReceiptsTotal))
+	}
+
+	signOff := models.CollectionSignOff{
+		SessionID:     session.ID,
+		UserID:        userID,
+		CashTotal:     roundAmount(req.CashTotal),
+		ReceiptsTotal: roundAmount(req.ReceiptsTotal),
+	}
+	posted, err := s.Repo.SignOffCollectionSession(ctx, signOff, models.CollectionSignOffsRequired)
+	if err != nil {
+		return nil, err
+	}
+	if posted {
+		s.acknowledge(ctx, resp.Receipts)
+	}
+
+	return s.GetSession(ctx, session.ID)
+}
+
+// acknowledge applies a posted session's receipts to pledges and queues the members'
+// acknowledgements as a directly entered receipt would
+func (s *CollectionService) acknowledge(ctx context.Context, receipts []models.TransactionResponse) {
+	pledgeService := NewPledgeService(s.Repo)
+	smsService := NewSMSService(s.Repo)
+	for _, txn := range receipts {
+		lines, err := s.Repo.GetReceiptByTransaction(ctx, txn.ID)
+		if err != nil {
+			log.Printf("⚠️  Failed to load receipt lines for %s: %v", txn.ID, err)
+			continue
+		}
+		for _, line := range lines {
+			if err := pledgeService.MatchReceipt(ctx, line); err != nil {
+				log.Printf("⚠️  Failed to match receipt %s to pledges: %v", line.ID, err)
+			}
+		}
+		if err := smsService.QueueReceiptAcknowledgement(ctx, txn.ID); err != nil {
+			log.Printf("⚠️  Failed to queue SMS for receipt %s: %v", txn.ID, err)
+		}
+	}
+}
+
+// CancelSession abandons an open session and discards its held receipts
+func (s *CollectionService) CancelSession(ctx context.Context, sessionID string, cancelledBy *string) (*models.CollectionSessionResponse, error) {
+	session, err := s.openSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	txns, err := s.Repo.GetTransactionsByCollectionSession(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, txn := range txns {
+		if err := s.Repo.DeleteCollectionReceipt(ctx, txn.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	session.Status = string(models.CollectionCancelled)
+	session.CancelledBy = cancelledBy
+	session.CancelledAt = &now
+
+	// Persist update
+	updated, err := s.Repo.UpdateCollectionSession(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(ctx, updated)
+}
+
+// ensureOutsideSession stops changes to a transaction held in, or posted by, a collection
+// session other than through the session, whose figures are counted and signed off as a whole
+func ensureOutsideSession(txn models.Transaction) error {
+	if txn.CollectionSessionID != nil {
+		return models.ErrCollectionReceipt
+	}
+	return nil
+}
+
+// openSession fetches a session that can still be changed
+func (s *CollectionService) openSession(ctx context.Context, id string) (models.CollectionSession, error) {
+	session, err := s.Repo.GetCollectionSession(ctx, id)
+	if err != nil {
+		return models.CollectionSession{}, models.ErrCollectionNotFound
+	}
+	if session.Status != string(models.CollectionOpen) {
+		return models.CollectionSession{}, models.Conflict("only open collection sessions can be changed; this session is " + session.Status)
+	}
+
+	return session, nil
+}
+
+// toResponse loads a session's receipts, cash count and sign-offs and works out its totals
+func (s *CollectionService) toResponse(ctx context.Context, session models.CollectionSession) (*models.CollectionSessionResponse, error) {
+	resp := session.ToResponse()
+
+	txns, err := s.Repo.GetTransactionsByCollectionSession(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, txn := range txns {
+		resp.Receipts = append(resp.Receipts, *txn.ToResponse())
+		resp.ReceiptsTotal += txn.Amount
+		if txn.PaymentMethod != nil && *txn.PaymentMethod == string(models.PaymentCash) {
+			resp.CashReceipts += txn.Amount
+		}
+	}
+
+	denominations, err := s.Repo.GetCollectionDenominations(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp.Denominations = append(resp.Denominations, denominations...)
+	for _, d := range denominations {
+		resp.CashCounted += d.Denomination * float64(d.Quantity)
+	}
+
+	signOffs, err := s.Repo.GetCollectionSignOffs(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp.SignOffs = append(resp.SignOffs, signOffs...)
+
+	resp.ReceiptsTotal = roundAmount(resp.ReceiptsTotal)
+	resp.CashReceipts = roundAmount(resp.CashReceipts)
+	resp.CashCounted = roundAmount(resp.CashCounted)
+	resp.CashVariance = roundAmount(resp.CashCounted - resp.CashReceipts)
+	if session.Status == string(models.CollectionOpen) {
+		resp.SignOffsNeeded = models.CollectionSignOffsRequired
+		for _, so := range signOffs {
+			if so.CashTotal == resp.CashCounted && so.ReceiptsTotal == resp.ReceiptsTotal && resp.SignOffsNeeded > 0 {
+				resp.SignOffsNeeded--
+			}
+		}
+	}
+
+	return resp, nil
+}
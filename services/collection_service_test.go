@@ -0,0 +1,162 @@
+package services
+
+import (
+	"storeHouse/models"
+	"testing"
+)
+
+// collection opens a session holding one cash envelope of 1500 counted as a 1000 and a 500 note
+func (f *fixture) collection(bank, income string, memberID *string) string {
+	f.t.Helper()
+	collections := NewCollectionService(f.repo)
+	clerk := "clerk"
+	session, err := collections.CreateSession(f.ctx, models.CreateCollectionSessionRequest{
+		ServiceName:   "Sabbath service",
+		BankAccountID: bank,
+	}, &clerk)
+	if err != nil {
+		f.t.Fatalf("create session: %v", err)
+	}
+
+	_, err = collections.AddReceipt(f.ctx, session.ID, models.AddCollectionReceiptRequest{
+		MemberID: memberID,
+		Lines:    []models.CollectionReceiptLine{{IncomeAccountID: income, Amount: 1500}},
+	}, "clerk")
+	if err != nil {
+		f.t.Fatalf("add receipt: %v", err)
+	}
+
+	_, err = collections.SetDenominations(f.ctx, session.ID, models.SetDenominationsRequest{
+		Denominations: []models.DenominationCount{{Denomination: 1000, Quantity: 1}, {Denomination: 500, Quantity: 1}},
+	})
+	if err != nil {
+		f.t.Fatalf("set denominations: %v", err)
+	}
+	return session.ID
+}
+
+var counted = models.SignOffCollectionRequest{CashTotal: 1500, ReceiptsTotal: 1500}
+
+func TestCollectionPostsAfterTwoCountersSignOff(t *testing.T) {
+	f := newFixture(t)
+	bank := f.account("Bank", models.AccountBank)
+	income := f.account("Offering", models.AccountIncome)
+	id := f.collection(bank, income, nil)
+	collections := NewCollectionService(f.repo)
+
+	first, err := collections.SignOff(f.ctx, id, counted, "counter-1")
+	if err != nil {
+		t.Fatalf("first sign-off: %v", err)
+	}
+	if first.Status != string(models.CollectionOpen) || first.SignOffsNeeded != 1 {
+		t.Fatalf("after one sign-off got status %s needing %d, want open needing 1", first.Status, first.SignOffsNeeded)
+	}
+
+	// Signing again does not count as a second counter
+	again, err := collections.SignOff(f.ctx, id, counted, "counter-1")
+	if err != nil {
+		t.Fatalf("repeat sign-off: %v", err)
+	}
+	if again.Status != string(models.CollectionOpen) {
+		t.Fatalf("after a repeat sign-off got status %s, want open", again.Status)
+	}
+
+	// Held receipts stay out of the books until the session posts
+	if total := f.receiptsTotal(income); total != 0 {
+		t.Fatalf("before posting got receipts of %.2f, want none", total)
+	}
+
+	second, err := collections.SignOff(f.ctx, id, counted, "counter-2")
+	if err != nil {
+		t.Fatalf("second sign-off: %v", err)
+	}
+	if second.Status != string(models.CollectionPosted) {
+		t.Fatalf("after two sign-offs got status %s, want posted", second.Status)
+	}
+	for _, receipt := range second.Receipts {
+		if receipt.Status != string(models.TransactionPosted) {
+			t.Fatalf("receipt %s has status %s, want posted", receipt.ID, receipt.Status)
+		}
+	}
+	if total := f.receiptsTotal(income); total != 1500 {
+		t.Fatalf("after posting got receipts of %.2f, want 1500", total)
+	}
+}
+
+func TestCollectionSignOffMustMatchTheCount(t *testing.T) {
+	f := newFixture(t)
+	bank := f.account("Bank", models.AccountBank)
+	income := f.account("Offering", models.AccountIncome)
+	id := f.collection(bank, income, nil)
+	collections := NewCollectionService(f.repo)
+
+	_, err := collections.SignOff(f.ctx, id, models.SignOffCollectionRequest{CashTotal: 1400, ReceiptsTotal: 1500}, "counter-1")
+	wantConflict(t, err)
+
+	_, err = collections.SignOff(f.ctx, id, models.SignOffCollectionRequest{CashTotal: 1500, ReceiptsTotal: 1000}, "counter-1")
+	wantConflict(t, err)
+}
+
+func TestCollectionRecountClearsSignOffs(t *testing.T) {
+	f := newFixture(t)
+	bank := f.account("Bank", models.AccountBank)
+	income := f.account("Offering", models.AccountIncome)
+	id := f.collection(bank, income, nil)
+	collections := NewCollectionService(f.repo)
+
+	if _, err := collections.SignOff(f.ctx, id, counted, "counter-1"); err != nil {
+		t.Fatalf("first sign-off: %v", err)
+	}
+
+	// Counting the same cash again means both counters confirm it afresh
+	recount, err := collections.SetDenominations(f.ctx, id, models.SetDenominationsRequest{
+		Denominations: []models.DenominationCount{{Denomination: 500, Quantity: 3}},
+	})
+	if err != nil {
+		t.Fatalf("recount: %v", err)
+	}
+	if len(recount.SignOffs) != 0 {
+		t.Fatalf("after a recount got %d sign-offs, want none", len(recount.SignOffs))
+	}
+
+	second, err := collections.SignOff(f.ctx, id, counted, "counter-2")
+	if err != nil {
+		t.Fatalf("second sign-off: %v", err)
+	}
+	if second.Status != string(models.CollectionOpen) {
+		t.Fatalf("got status %s, want open until two counters confirm the recount", second.Status)
+	}
+}
+
+func TestCollectionReceiptsCannotBeChangedDirectly(t *testing.T) {
+	f := newFixture(t)
+	bank := f.account("Bank", models.AccountBank)
+	income := f.account("Offering", models.AccountIncome)
+	id := f.collection(bank, income, nil)
+
+	session, err := NewCollectionService(f.repo).GetSession(f.ctx, id)
+	if err != nil {
+		t.Fatalf("get session: %v", err)
+	}
+	txn := session.Receipts[0].ID
+
+	err = NewTransactionService(f.repo).DeleteTransaction(f.ctx, txn)
+	wantConflict(t, err)
+
+	_, err = NewReceiptService(f.repo).CreateReceipt(f.ctx, models.CreateReceiptRequest{
+		TransactionID:   txn,
+		IncomeAccountID: income,
+		Amount:          100,
+	})
+	wantConflict(t, err)
+}
+
+// receiptsTotal is what the books hold in posted receipts to an income account
+func (f *fixture) receiptsTotal(income string) float64 {
+	f.t.Helper()
+	total, err := f.repo.GetTotalReceiptsByAccount(f.ctx, income)
+	if err != nil {
+		f.t.Fatalf("receipts total: %v", err)
+	}
+	return total
+}
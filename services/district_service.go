@@ -0,0 +1,360 @@
+package services
+
+import (
+	"context"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"strings"
+	"time"
+)
+
+type DistrictService struct {
+	Repo repository.Store
+	// ForChurch opens the records of one church in the district, for the consolidated reports
+	ForChurch func(churchID string) (repository.Store, error)
+}
+
+// Create a new instance of DistrictService
+func NewDistrictService(repo repository.Store, forChurch func(churchID string) (repository.Store, error)) *DistrictService {
+	return &DistrictService{Repo: repo, ForChurch: forChurch}
+}
+
+// GetMyDistricts returns the districts a user holds a role in
+func (s *DistrictService) GetMyDistricts(ctx context.Context, userID string) ([]models.DistrictResponse, error) {
+	districts, err := s.Repo.GetUserDistricts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response list
+	responses := make([]models.DistrictResponse, 0, len(districts))
+	for _, d := range districts {
+		response := d.District.ToResponse()
+		response.Role = d.Role
+		responses = append(responses, *response)
+	}
+	return responses, nil
+}
+
+// CreateDistrict adds a district; the user who creates it becomes its admin
+func (s *DistrictService) CreateDistrict(ctx context.Context, req models.CreateDistrictRequest, userID string) (*models.DistrictResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	code, _ := models.NormalizeChurchCode(req.Code)
+	if _, err := s.Repo.GetDistrictByCode(ctx, code); err == nil {
+		return nil, models.Conflict("district code " + code + " already exists")
+	}
+
+	// Prepare model for DB
+	district := models.District{
+		Name:     strings.TrimSpace(req.Name),
+		Code:     code,
+		IsActive: true,
+	}
+
+	// Save to DB
+	newDistrict, err := s.Repo.CreateDistrict(ctx, district, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := newDistrict.ToResponse()
+	response.Role = string(models.DistrictAdmin)
+	return response, nil
+}
+
+// GetDistrict returns a district the user holds a role in, with its churches
+func (s *DistrictService) GetDistrict(ctx context.Context, id, userID string) (*models.DistrictResponse, error) {
+	role, err := s.Repo.GetDistrictRole(ctx, id, userID)
+	if err != nil {
+		return nil, models.ErrDistrictNotFound
+	}
+	return s.districtResponse(ctx, id, role)
+}
+
+// AddChurch places a church in the district. Both sides agree to it: the user must
+// administer the district and the church.
+func (s *DistrictService) AddChurch(ctx context.Context, id string, req models.AddDistrictChurchRequest, userID string) (*models.DistrictResponse, error) {
+	if req.ChurchID == "" {
+		return nil, models.Invalid("church_id is required")
+	}
+	if err := s.requireDistrictAdmin(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	role, err := s.Repo.GetChurchRole(ctx, req.ChurchID, userID)
+	if err != nil {
+		return nil, models.ErrChurchNotFound
+	}
+	if role != string(models.RoleAdmin) {
+		return nil, models.Forbidden("only the church's admins can place it in a district")
+	}
+
+	church, err := s.Repo.GetChurch(ctx, req.ChurchID)
+	if err != nil {
+		return nil, models.ErrChurchNotFound
+	}
+	if church.DistrictID != nil && *church.DistrictID != id {
+		return nil, models.Conflict("church " + church.Code + " already belongs to another district")
+	}
+
+	if err := s.Repo.SetChurchDistrict(ctx, church.ID, &id); err != nil {
+		return nil, err
+	}
+
+	return s.districtResponse(ctx, id, string(models.DistrictAdmin))
+}
+
+// RemoveChurch takes a church out of the district; the district's or the church's admins
+// can do this
+func (s *DistrictService) RemoveChurch(ctx context.Context, id, churchID, userID string) error {
+	church, err := s.Repo.GetChurch(ctx, churchID)
+	if err != nil || church.DistrictID == nil || *church.DistrictID != id {
+		return models.ErrChurchNotFound
+	}
+
+	if err := s.requireDistrictAdmin(ctx, id, userID); err != nil {
+		role, roleErr := s.Repo.GetChurchRole(ctx, churchID, userID)
+		if roleErr != nil || role != string(models.RoleAdmin) {
+			return err
+		}
+	}
+
+	return s.Repo.SetChurchDistrict(ctx, churchID, nil)
+}
+
+// GetDistrictUsers lists the users with a role in the district
+func (s *DistrictService) GetDistrictUsers(ctx context.Context, id, userID string) ([]models.DistrictUserResponse, error) {
+	if err := s.requireDistrictAdmin(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	users, err := s.Repo.GetDistrictUsers(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if users == nil {
+		users = []models.DistrictUserResponse{}
+	}
+	return users, nil
+}
+
+// SaveDistrictUser gives a user a role in the district, or changes it
+func (s *DistrictService) SaveDistrictUser(ctx context.Context, id string, req models.AddDistrictUserRequest, userID string) ([]models.DistrictUserResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.requireDistrictAdmin(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.Repo.GetUser(ctx, req.UserID); err != nil {
+		return nil, models.ErrUserNotFound
+	}
+	if req.Role != string(models.DistrictAdmin) {
+		if err := s.ensureAnotherAdmin(ctx, id, req.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.Repo.SaveDistrictUser(ctx, id, req.UserID, req.Role); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.GetDistrictUsers(ctx, id)
+}
+
+// RemoveDistrictUser takes a user's district role away
+func (s *DistrictService) RemoveDistrictUser(ctx context.Context, id, memberID, userID string) error {
+	if err := s.requireDistrictAdmin(ctx, id, userID); err != nil {
+		return err
+	}
+	if _, err := s.Repo.GetDistrictRole(ctx, id, memberID); err != nil {
+		return models.Invalid("user has no role in this district")
+	}
+	if err := s.ensureAnotherAdmin(ctx, id, memberID); err != nil {
+		return err
+	}
+
+	return s.Repo.RemoveDistrictUser(ctx, id, memberID)
+}
+
+// GetConsolidatedReport totals the posted income of every church in the district over the
+// period, by account category, with the local share each church keeps and the rest it
+// remits. Each church is read on its own connection, so only its rows are seen.
+func (s *DistrictService) GetConsolidatedReport(ctx context.Context, id, userID string, startDate, endDate time.Time) (*models.DistrictReport, error) {
+	if endDate.Before(startDate) {
+		return nil, models.Invalid("end_date must not be before start_date")
+	}
+
+	role, err := s.Repo.GetDistrictRole(ctx, id, userID)
+	if err != nil {
+		return nil, models.ErrDistrictNotFound
+	}
+	district, err := s.districtResponse(ctx, id, role)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.DistrictReport{
+		District:   *district,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Categories: []models.DistrictCategoryTotal{},
+		Churches:   make([]models.DistrictChurchReport, 0, len(district.Churches)),
+	}
+
+	consolidated := make(map[string]*models.DistrictCategoryTotal)
+	var order []string
+
+	for _, church := range district.Churches {
+		churchRepo, err := s.ForChurch(church.ID)
+		if err != nil {
+			return nil, models.Internal("church " + church.Code + ": " + err.Error())
+		}
+		lines, err := churchRepo.GetIncomeLines(ctx, startDate, endDate)
+		if err != nil {
+			return nil, models.Internal("church " + church.Code + ": " + err.Error())
+		}
+
+		churchReport := models.DistrictChurchReport{
+			ChurchID:   church.ID,
+			Code:       church.Code,
+			Name:       church.Name,
+			Categories: []models.DistrictCategoryTotal{},
+		}
+		categories := make(map[string]int)
+
+		for _, line := range lines {
+			retained := line.Amount * localShare(line.LocalShare)
+			remitted := line.Amount - retained
+
+			i, ok := categories[line.CategoryID]
+			if !ok {
+				i = len(churchReport.Categories)
+				categories[line.CategoryID] = i
+				churchReport.Categories = append(churchReport.Categories, models.DistrictCategoryTotal{
+					Code: line.CategoryCode,
+					Name: line.CategoryName,
+				})
+			}
+			addToCategory(&churchReport.Categories[i], line.Amount, retained, remitted)
+
+			// Categories are matched across churches by code, or by name when uncoded
+			key := strings.ToLower(line.CategoryName)
+			if line.CategoryCode != nil {
+				key = "code:" + *line.CategoryCode
+			}
+			total, ok := consolidated[key]
+			if !ok {
+				total = &models.DistrictCategoryTotal{Code: line.CategoryCode, Name: line.CategoryName}
+				consolidated[key] = total
+				order = append(order, key)
+			}
+			addToCategory(total, line.Amount, retained, remitted)
+
+			churchReport.Income += line.Amount
+			churchReport.Retained += retained
+			churchReport.Remitted += remitted
+		}
+
+		for i := range churchReport.Categories {
+			roundCategory(&churchReport.Categories[i])
+		}
+		report.Income += churchReport.Income
+		report.Retained += churchReport.Retained
+		report.Remitted += churchReport.Remitted
+		churchReport.Income = roundAmount(churchReport.Income)
+		churchReport.Retained = roundAmount(churchReport.Retained)
+		churchReport.Remitted = roundAmount(churchReport.Remitted)
+		report.Churches = append(report.Churches, churchReport)
+	}
+
+	for _, key := range order {
+		roundCategory(consolidated[key])
+		report.Categories = append(report.Categories, *consolidated[key])
+	}
+	report.Income = roundAmount(report.Income)
+	report.Retained = roundAmount(report.Retained)
+	report.Remitted = roundAmount(report.Remitted)
+
+	return report, nil
+}
+
+// districtResponse builds a district's response with its churches
+func (s *DistrictService) districtResponse(ctx context.Context, id, role string) (*models.DistrictResponse, error) {
+	district, err := s.Repo.GetDistrict(ctx, id)
+	if err != nil {
+		return nil, models.ErrDistrictNotFound
+	}
+	churches, err := s.Repo.GetDistrictChurches(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := district.ToResponse()
+	response.Role = role
+	response.Churches = make([]models.ChurchResponse, 0, len(churches))
+	for _, c := range churches {
+		response.Churches = append(response.Churches, *c.ToResponse())
+	}
+	return response, nil
+}
+
+// requireDistrictAdmin makes sure the user administers the district
+func (s *DistrictService) requireDistrictAdmin(ctx context.Context, districtID, userID string) error {
+	role, err := s.Repo.GetDistrictRole(ctx, districtID, userID)
+	if err != nil {
+		return models.ErrDistrictNotFound
+	}
+	if role != string(models.DistrictAdmin) {
+		return models.ErrDistrictAdminOnly
+	}
+	return nil
+}
+
+// ensureAnotherAdmin stops the last admin of a district from losing the role
+func (s *DistrictService) ensureAnotherAdmin(ctx context.Context, districtID, memberID string) error {
+	role, err := s.Repo.GetDistrictRole(ctx, districtID, memberID)
+	if err != nil || role != string(models.DistrictAdmin) {
+		return nil
+	}
+
+	admins, err := s.Repo.CountDistrictAdmins(ctx, districtID)
+	if err != nil {
+		return err
+	}
+	if admins <= 1 {
+		return models.Conflict("a district needs at least one admin")
+	}
+	return nil
+}
+
+// localShare returns the fraction of an account's income the church keeps; accounts
+// without a local share keep it all
+func localShare(share *float64) float64 {
+	if share == nil {
+		return 1
+	}
+	if *share < 0 {
+		return 0
+	}
+	if *share > 1 {
+		return 1
+	}
+	return *share
+}
+
+func addToCategory(total *models.DistrictCategoryTotal, income, retained, remitted float64) {
+	total.Income += income
+	total.Retained += retained
+	total.Remitted += remitted
+}
+
+func roundCategory(total *models.DistrictCategoryTotal) {
+	total.Income = roundAmount(total.Income)
+	total.Retained = roundAmount(total.Retained)
+	total.Remitted = roundAmount(total.Remitted)
+}
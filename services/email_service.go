@@ -0,0 +1,485 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/mail"
+	"storeHouse/mailer"
+	"storeHouse/models"
+	"storeHouse/notifications"
+	"storeHouse/repository"
+	"strings"
+	"time"
+)
+
+// emailBatchSize caps how many queued emails one dispatcher run sends
+const emailBatchSize = 20
+
+type EmailService struct {
+	Repo       repository.Store
+	Config     mailer.Config
+	Mailer     mailer.Mailer
+	ChurchName string
+}
+
+// Create a new instance of EmailService
+func NewEmailService(repo repository.Store) *EmailService {
+	cfg := mailer.LoadConfig()
+	return &EmailService{
+		Repo:       repo,
+		Config:     cfg,
+		Mailer:     mailer.NewSMTPMailer(cfg),
+		ChurchName: notifications.LoadConfig().ChurchName,
+	}
+}
+
+// SendReceipt queues an emailed receipt, with a PDF copy, to the member on a receipts transaction
+func (s *EmailService) SendReceipt(ctx context.Context, transactionID, createdBy string) (*models.EmailResponse, error) {
+	txn, err := s.Repo.GetTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, models.ErrTransactionNotFound
+	}
+	if txn.TransactionType != string(models.TransactionReceipts) {
+		return nil, models.Conflict("only receipts transactions can be emailed as receipts")
+	}
+	member, err := s.getMemberWithEmail(ctx, txn.MemberID)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, total, err := receiptTotalsByAccount(ctx, s.Repo, txn.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := mailer.ReceiptEmail{
+		MemberName: member.FullName,
+		Reference:  transactionReference(txn),
+		Date:       txn.TransactionDate,
+		Total:      total,
+		ChurchName: s.ChurchName,
+	}
+	pdfLines := []string{
+		fmt.Sprintf("Received from: %s", member.FullName),
+		fmt.Sprintf("Date:          %s", txn.TransactionDate.Format("02 Jan 2006")),
+		fmt.Sprintf("Reference:     %s", data.Reference),
+		"",
+		fmt.Sprintf("%-40s %16s", "Account", "Amount (KES)"),
+		strings.Repeat("-", 57),
+	}
+	for _, t := range totals {
+		data.Lines = append(data.Lines, mailer.AmountLine{Account: t.Account, Amount: t.Amount})
+		pdfLines = append(pdfLines, fmt.Sprintf("%-40s %16s", truncate(t.Account, 40), notifications.FormatMoney(t.Amount)))
+	}
+	pdfLines = append(pdfLines, strings.Repeat("-", 57), fmt.Sprintf("%-40s %16s", "Total", notifications.FormatMoney(total)))
+
+	attachments := []models.EmailAttachment{{
+		FileName:    fmt.Sprintf("receipt-%s.pdf", fileSafe(data.Reference)),
+		ContentType: "application/pdf",
+		Data:        mailer.TextPDF(s.ChurchName+" - Official Receipt", pdfLines),
+	}}
+
+	return s.queue(ctx, models.EmailReceipt, &member, member.Email, mailer.TemplateReceipt, data, attachments, createdBy)
+}
+
+// SendStatement queues a member's giving statement for the period, attached as PDF and CSV
+func (s *EmailService) SendStatement(ctx context.Context, memberID string, req models.SendStatementRequest, createdBy string) (*models.EmailResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	member, err := s.getMemberWithEmail(ctx, &memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := s.Repo.GetMemberGivingLines(ctx, memberID, req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	data := mailer.StatementEmail{
+		MemberName: member.FullName,
+		StartDate:  req.StartDate,
+		EndDate:    req.EndDate,
+		ChurchName: s.ChurchName,
+	}
+
+	var csvBuf bytes.Buffer
+	w := csv.NewWriter(&csvBuf)
+	w.Write([]string{"date", "reference", "account", "amount"})
+
+	pdfLines := []string{
+		fmt.Sprintf("Member: %s", member.FullName),
+		fmt.Sprintf("Period: %s to %s", req.StartDate.Format("02 Jan 2006"), req.EndDate.Format("02 Jan 2006")),
+		"",
+		fmt.Sprintf("%-12s %-20s %-30s %14s", "Date", "Reference", "Account", "Amount (KES)"),
+		strings.Repeat("-", 79),
+	}
+	for _, l := range lines {
+		date := l.TransactionDate
+		ref := transactionReference(models.Transaction{ID: l.TransactionID, TransactionRef: l.TransactionRef})
+		data.Lines = append(data.Lines, mailer.AmountLine{Date: &date, Ref: ref, Account: l.AccountName, Amount: l.Amount})
+		data.Total += l.Amount
+
+		w.Write([]string{date.Format("2006-01-02"), ref, l.AccountName, fmt.Sprintf("%.2f", l.Amount)})
+		pdfLines = append(pdfLines, fmt.Sprintf("%-12s %-20s %-30s %14s",
+			date.Format("02/01/2006"), truncate(ref, 20), truncate(l.AccountName, 30), notifications.FormatMoney(l.Amount)))
+	}
+	pdfLines = append(pdfLines, strings.Repeat("-", 79), fmt.Sprintf("%-64s %14s", "Total", notifications.FormatMoney(data.Total)))
+	w.Flush()
+
+	period := req.StartDate.Format("20060102") + "-" + req.EndDate.Format("20060102")
+	attachments := []models.EmailAttachment{
+		{
+			FileName:    "statement-" + period + ".pdf",
+			ContentType: "application/pdf",
+			Data:        mailer.TextPDF(s.ChurchName+" - Giving Statement", pdfLines),
+		},
+		{
+			FileName:    "statement-" + period + ".csv",
+			ContentType: "text/csv",
+			Data:        csvBuf.Bytes(),
+		},
+	}
+
+	return s.queue(ctx, models.EmailStatement, &member, member.Email, mailer.TemplateStatement, data, attachments, createdBy)
+}
+
+// SendMonthlyReport queues the monthly treasurer report (budget vs actual for income and
+// expenses) to the given recipients, or to every active Treasurer with an email address
+func (s *EmailService) SendMonthlyReport(ctx context.Context, req models.SendMonthlyReportRequest, createdBy string) ([]models.EmailResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	recipients := req.Recipients
+	if len(recipients) == 0 {
+		treasurers, err := s.Repo.GetUsersByRole(ctx, string(models.RoleTreasurer))
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range treasurers {
+			if u.IsActive && u.Email != "" {
+				recipients = append(recipients, u.Email)
+			}
+		}
+	}
+	if len(recipients) == 0 {
+		return nil, models.Invalid("no recipients: pass recipients or add a Treasurer with an email address")
+	}
+	for _, r := range recipients {
+		if _, err := mail.ParseAddress(r); err != nil {
+			return nil, models.Invalid(fmt.Sprintf("invalid recipient %q", r))
+		}
+	}
+
+	report, err := NewBudgetService(s.Repo).GetVarianceReport(ctx, req.Year, req.Month)
+	if err != nil {
+		return nil, err
+	}
+
+	data := mailer.MonthlyReportEmail{
+		Period:     time.Month(req.Month).String() + " " + fmt.Sprint(req.Year),
+		ChurchName: s.ChurchName,
+	}
+
+	var csvBuf bytes.Buffer
+	w := csv.NewWriter(&csvBuf)
+	w.Write([]string{"section", "account", "budget", "actual", "variance"})
+
+	pdfLines := []string{fmt.Sprintf("%-34s %14s %14s %14s", "Account", "Budget", "Actual", "Variance")}
+	section := func(name string, rows []models.BudgetVariance, target *[]mailer.ReportLine) float64 {
+		var total float64
+		pdfLines = append(pdfLines, "", strings.ToUpper(name), strings.Repeat("-", 79))
+		for _, v := range rows {
+			*target = append(*target, mailer.ReportLine{Account: v.AccountName, Budget: v.Budget, Actual: v.Actual, Variance: v.Variance})
+			// Header rows already roll up their children
+			if !v.IsHeader {
+				total += v.Actual
+			}
+			w.Write([]string{name, v.AccountName, fmt.Sprintf("%.2f", v.Budget), fmt.Sprintf("%.2f", v.Actual), fmt.Sprintf("%.2f", v.Variance)})
+			pdfLines = append(pdfLines, fmt.Sprintf("%-34s %14s %14s %14s", truncate(v.AccountName, 34),
+				notifications.FormatMoney(v.Budget), notifications.FormatMoney(v.Actual), notifications.FormatMoney(v.Variance)))
+		}
+		pdfLines = append(pdfLines, fmt.Sprintf("%-34s %14s %14s", "Total "+name, "", notifications.FormatMoney(total)))
+		return total
+	}
+	data.TotalIncome = section("Income", report.Income, &data.Income)
+	data.TotalExpenses = section("Expenses", report.Expenses, &data.Expenses)
+	data.Net = data.TotalIncome - data.TotalExpenses
+	pdfLines = append(pdfLines, "", fmt.Sprintf("%-34s %14s %14s", "Net", "", notifications.FormatMoney(data.Net)))
+	w.Flush()
+
+	period := fmt.Sprintf("%d-%02d", req.Year, req.Month)
+	pdf := mailer.TextPDF(s.ChurchName+" - Treasurer's Report, "+data.Period, pdfLines)
+
+	responses := make([]models.EmailResponse, 0, len(recipients))
+	for _, r := range recipients {
+		attachments := []models.EmailAttachment{
+			{FileName: "treasurer-report-" + period + ".pdf", ContentType: "application/pdf", Data: pdf},
+			{FileName: "treasurer-report-" + period + ".csv", ContentType: "text/csv", Data: csvBuf.Bytes()},
+		}
+		recipient := r
+		email, err := s.queue(ctx, models.EmailMonthlyReport, nil, &recipient, mailer.TemplateMonthlyReport, data, attachments, createdBy)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, *email)
+	}
+
+	return responses, nil
+}
+
+// ProcessQueue sends the queued emails that are due and returns how many were sent
+func (s *EmailService) ProcessQueue(ctx context.Context) (int, error) {
+	due, err := s.Repo.GetDueEmails(ctx, time.Now(), emailBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, e := range due {
+		updated, err := s.deliver(ctx, e)
+		if err != nil {
+			log.Printf("⚠️  Failed to send email %s: %v", e.ID, err)
+			continue
+		}
+		if updated.Status == string(models.EmailSent) {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// StartDispatcher sends queued emails in the background every interval. Emails are still
+// queued when no SMTP server is configured, and go out once one is.
+func (s *EmailService) StartDispatcher(interval time.Duration) {
+	if !s.Config.Enabled() {
+		log.Println("📭 Email delivery disabled (SMTP_HOST not set)")
+		return
+	}
+
+	log.Printf("📧 Email delivery enabled via %s:%d", s.Config.Host, s.Config.Port)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if _, err := s.ProcessQueue(context.Background()); err != nil {
+				log.Printf("⚠️  Email dispatcher: %v", err)
+			}
+		}
+	}()
+}
+
+// RetryEmail sends a failed or bounced email again straight away, with a fresh set of attempts
+func (s *EmailService) RetryEmail(ctx context.Context, id string) (*models.EmailResponse, error) {
+	email, err := s.Repo.GetEmail(ctx, id)
+	if err != nil {
+		return nil, models.ErrEmailNotFound
+	}
+	if email.Status == string(models.EmailSent) {
+		return nil, models.Conflict("email has already been sent")
+	}
+	if !s.Config.Enabled() {
+		return nil, models.Unavailable("email delivery is not configured")
+	}
+
+	email.Attempts = 0
+	email.BouncedAt = nil
+	updated, err := s.deliver(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.ToResponse(), nil
+}
+
+// MarkBounced records a bounce reported after the server accepted the email, such as a
+// delivery status notification received by the sender mailbox
+func (s *EmailService) MarkBounced(ctx context.Context, id string, req models.MarkBouncedRequest) (*models.EmailResponse, error) {
+	email, err := s.Repo.GetEmail(ctx, id)
+	if err != nil {
+		return nil, models.ErrEmailNotFound
+	}
+
+	now := time.Now()
+	email.Status = string(models.EmailBounced)
+	email.BouncedAt = &now
+	if req.Reason != "" {
+		email.LastError = &req.Reason
+	}
+
+	// Persist update
+	updated, err := s.Repo.UpdateEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.ToResponse(), nil
+}
+
+// GetEmail returns an outbox email with its attachment details
+func (s *EmailService) GetEmail(ctx context.Context, id string) (*models.EmailResponse, error) {
+	email, err := s.Repo.GetEmail(ctx, id)
+	if err != nil {
+		return nil, models.ErrEmailNotFound
+	}
+
+	attachments, err := s.Repo.GetEmailAttachments(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := email.ToResponse()
+	for _, a := range attachments {
+		response.Attachments = append(response.Attachments, *a.ToResponse())
+	}
+
+	return response, nil
+}
+
+// GetEmails returns outbox emails, optionally filtered by status
+func (s *EmailService) GetEmails(ctx context.Context, status string) ([]models.EmailResponse, error) {
+	var emails []models.Email
+	var err error
+	if status != "" {
+		emails, err = s.Repo.GetEmailsByStatus(ctx, status)
+	} else {
+		emails, err = s.Repo.GetAllEmails(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response list
+	responses := make([]models.EmailResponse, 0, len(emails))
+	for _, e := range emails {
+		responses = append(responses, *e.ToResponse())
+	}
+
+	return responses, nil
+}
+
+// queue renders a template and stores the email in the outbox for the dispatcher
+func (s *EmailService) queue(ctx context.Context, kind models.EmailKind, member *models.Member, recipient *string, template string, data interface{}, attachments []models.EmailAttachment, createdBy string) (*models.EmailResponse, error) {
+	rendered, err := mailer.Render(template, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare model for DB
+	email := models.Email{
+		Kind:          string(kind),
+		Recipient:     *recipient,
+		Subject:       rendered.Subject,
+		BodyText:      rendered.Text,
+		Status:        string(models.EmailQueued),
+		NextAttemptAt: time.Now(),
+		CreatedBy:     createdBy,
+	}
+	if member != nil {
+		email.MemberID = &member.ID
+	}
+	if rendered.HTML != "" {
+		email.BodyHTML = &rendered.HTML
+	}
+
+	// Save to DB
+	newEmail, err := s.Repo.CreateEmail(ctx, email, attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetEmail(ctx, newEmail.ID)
+}
+
+// deliver makes one delivery attempt, recording the outcome. Temporary failures are
+// re-queued with a growing delay; a permanent rejection marks the email bounced.
+func (s *EmailService) deliver(ctx context.Context, e models.Email) (models.Email, error) {
+	attachments, err := s.Repo.GetEmailAttachments(ctx, e.ID)
+	if err != nil {
+		return models.Email{}, err
+	}
+
+	msg := mailer.Message{
+		To:      e.Recipient,
+		Subject: e.Subject,
+		Text:    e.BodyText,
+	}
+	if e.BodyHTML != nil {
+		msg.HTML = *e.BodyHTML
+	}
+	for _, a := range attachments {
+		msg.Attachments = append(msg.Attachments, mailer.Attachment{FileName: a.FileName, ContentType: a.ContentType, Data: a.Data})
+	}
+
+	e.Attempts++
+	now := time.Now()
+	if sendErr := s.Mailer.Send(msg); sendErr != nil {
+		reason := sendErr.Error()
+		e.LastError = &reason
+		switch {
+		case mailer.IsPermanent(sendErr):
+			e.Status = string(models.EmailBounced)
+			e.BouncedAt = &now
+		case e.Attempts >= s.Config.MaxAttempts:
+			e.Status = string(models.EmailFailed)
+		default:
+			e.Status = string(models.EmailQueued)
+			e.NextAttemptAt = now.Add(time.Duration(e.Attempts*e.Attempts) * time.Minute)
+		}
+	} else {
+		e.Status = string(models.EmailSent)
+		e.SentAt = &now
+		e.LastError = nil
+	}
+
+	// Persist update
+	return s.Repo.UpdateEmail(ctx, e)
+}
+
+func (s *EmailService) getMemberWithEmail(ctx context.Context, memberID *string) (models.Member, error) {
+	if memberID == nil {
+		return models.Member{}, models.Invalid("transaction has no member")
+	}
+	member, err := s.Repo.GetMember(ctx, *memberID)
+	if err != nil {
+		return models.Member{}, models.ErrMemberNotFound
+	}
+	if member.Email == nil || *member.Email == "" {
+		return models.Member{}, models.Invalid("member has no email address")
+	}
+	return member, nil
+}
+
+// transactionReference is the reference shown to members: the transaction ref when there
+// is one, otherwise the start of the transaction ID
+func transactionReference(txn models.Transaction) string {
+	if txn.TransactionRef != nil && *txn.TransactionRef != "" {
+		return *txn.TransactionRef
+	}
+	if len(txn.ID) > 8 {
+		return strings.ToUpper(txn.ID[:8])
+	}
+	return txn.ID
+}
+
+func truncate(s string, n int) string {
+	if len([]rune(s)) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "."
+}
+
+func fileSafe(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' {
+			return r
+		}
+		return '-'
+	}, s)
+}
@@ -1,38 +1,62 @@
 package services
 
 import (
-	"errors"
+	"context"
 	"storeHouse/models"
 	"storeHouse/repository"
 	"time"
-
-	"github.com/jmoiron/sqlx"
 )
 
 type ExpenditureService struct {
-	DB *sqlx.DB
+	Repo repository.Store
 }
 
 // Create a new instance of ExpenditureService
-func NewExpenditureService(db *sqlx.DB) *ExpenditureService {
-	return &ExpenditureService{DB: db}
+func NewExpenditureService(repo repository.Store) *ExpenditureService {
+	return &ExpenditureService{Repo: repo}
 }
 
 // CreateExpenditure handles expenditure creation business logic
-func (s *ExpenditureService) CreateExpenditure(req models.CreateExpenditureRequest, createdBy string) (*models.ExpenditureResponse, error) {
+func (s *ExpenditureService) CreateExpenditure(ctx context.Context, req models.CreateExpenditureRequest, createdBy string) (*models.ExpenditureResponse, error) {
 	// Validate that amount is positive
 	if req.Amount <= 0 {
-		return nil, errors.New("amount must be greater than zero")
+		return nil, models.Invalid("amount must be greater than zero")
 	}
 
 	// Check if bank account exists
-	if _, err := repository.GetAccount(s.DB, req.BankAccountID); err != nil {
-		return nil, errors.New("bank account not found")
+	if _, err := getPostingAccount(ctx, s.Repo, req.BankAccountID, "bank account"); err != nil {
+		return nil, err
+	}
+
+	// Check if payee exists if provided
+	payeeID := emptyToNil(req.PayeeID)
+	if err := s.checkPayee(ctx, payeeID); err != nil {
+		return nil, err
+	}
+
+	// Check the fund, and that a restricted fund can cover the expenditure
+	fundID := emptyToNil(req.FundID)
+	fundService := NewFundService(s.Repo)
+	if err := fundService.CheckFund(ctx, fundID); err != nil {
+		return nil, err
+	}
+	if err := fundService.CheckExpenditure(ctx, fundID, req.Amount, ""); err != nil {
+		return nil, err
 	}
 
 	// Check if transaction exists
-	if _, err := repository.GetTransaction(s.DB, req.TransactionID); err != nil {
-		return nil, errors.New("transaction not found")
+	txn, err := s.Repo.GetTransaction(ctx, req.TransactionID)
+	if err != nil {
+		return nil, models.ErrTransactionNotFound
+	}
+	if err := ensureEditable(txn); err != nil {
+		return nil, err
+	}
+
+	// Warn, without blocking, when the expenditure exceeds the remaining budget of the account being charged
+	budgetWarning, err := NewBudgetService(s.Repo).CheckExpenditure(ctx, txn.DebitAccountID, req.Amount, txn.TransactionDate)
+	if err != nil {
+		return nil, err
 	}
 
 	// Prepare model for DB
@@ -40,26 +64,34 @@ func (s *ExpenditureService) CreateExpenditure(req models.CreateExpenditureReque
 		TransactionID: req.TransactionID,
 		Particulars:   req.Particulars,
 		BankAccountID: req.BankAccountID,
+		PayeeID:       payeeID,
+		FundID:        fundID,
 		Amount:        req.Amount,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
 
 	// Save to DB
-	newExpenditure, err := repository.CreateExpenditure(s.DB, expenditure)
+	newExpenditure, err := s.Repo.CreateExpenditure(ctx, expenditure)
 	if err != nil {
 		return nil, err
 	}
 
-	return newExpenditure.ToResponse(), nil
+	response := newExpenditure.ToResponse()
+	response.BudgetWarning = budgetWarning
+
+	return response, nil
 }
 
 // UpdateExpenditure handles update logic
-func (s *ExpenditureService) UpdateExpenditure(id string, req models.UpdateExpenditureRequest) (*models.ExpenditureResponse, error) {
+func (s *ExpenditureService) UpdateExpenditure(ctx context.Context, id string, req models.UpdateExpenditureRequest) (*models.ExpenditureResponse, error) {
 	// Fetch existing record
-	existing, err := repository.GetExpenditure(s.DB, id)
+	existing, err := s.Repo.GetExpenditure(ctx, id)
 	if err != nil {
-		return nil, errors.New("expenditure not found")
+		return nil, models.ErrExpenditureNotFound
+	}
+	if err := ensureLinesEditable(ctx, s.Repo, existing.TransactionID); err != nil {
+		return nil, err
 	}
 
 	// Apply updates only if fields are provided
@@ -68,22 +100,43 @@ func (s *ExpenditureService) UpdateExpenditure(id string, req models.UpdateExpen
 	}
 	if req.BankAccountID != nil {
 		// Check if new bank account exists
-		if _, err := repository.GetAccount(s.DB, *req.BankAccountID); err != nil {
-			return nil, errors.New("bank account not found")
+		if _, err := getPostingAccount(ctx, s.Repo, *req.BankAccountID, "bank account"); err != nil {
+			return nil, err
 		}
 		existing.BankAccountID = *req.BankAccountID
 	}
+	if req.PayeeID != nil {
+		// An empty payee_id clears the payee
+		payeeID := emptyToNil(req.PayeeID)
+		if err := s.checkPayee(ctx, payeeID); err != nil {
+			return nil, err
+		}
+		existing.PayeeID = payeeID
+	}
 	if req.Amount != nil {
 		if *req.Amount <= 0 {
-			return nil, errors.New("amount must be greater than zero")
+			return nil, models.Invalid("amount must be greater than zero")
 		}
 		existing.Amount = *req.Amount
 	}
+	if req.FundID != nil {
+		// An empty fund_id moves the line to the general fund
+		fundID := emptyToNil(req.FundID)
+		if err := NewFundService(s.Repo).CheckFund(ctx, fundID); err != nil {
+			return nil, err
+		}
+		existing.FundID = fundID
+	}
+	if req.FundID != nil || req.Amount != nil {
+		if err := NewFundService(s.Repo).CheckExpenditure(ctx, existing.FundID, existing.Amount, existing.ID); err != nil {
+			return nil, err
+		}
+	}
 
 	existing.UpdatedAt = time.Now()
 
 	// Persist update
-	updated, err := repository.UpdateExpenditure(s.DB, existing)
+	updated, err := s.Repo.UpdateExpenditure(ctx, existing)
 	if err != nil {
 		return nil, err
 	}
@@ -92,27 +145,33 @@ func (s *ExpenditureService) UpdateExpenditure(id string, req models.UpdateExpen
 }
 
 // DeleteExpenditure removes an expenditure record
-func (s *ExpenditureService) DeleteExpenditure(id string) error {
+func (s *ExpenditureService) DeleteExpenditure(ctx context.Context, id string) error {
 	// Ensure exists before deleting
-	if _, err := repository.GetExpenditure(s.DB, id); err != nil {
-		return errors.New("expenditure not found")
+	existing, err := s.Repo.GetExpenditure(ctx, id)
+	if err != nil {
+		return models.ErrExpenditureNotFound
+	}
+	if err := ensureLinesEditable(ctx, s.Repo, existing.TransactionID); err != nil {
+		return err
 	}
 
-	return repository.DeleteExpenditure(s.DB, id)
+	return deleteWithAttachments(ctx, s.Repo, models.AttachmentExpenditure, id, func() error {
+		return s.Repo.DeleteExpenditure(ctx, id)
+	})
 }
 
 // GetExpenditure returns single expenditure details
-func (s *ExpenditureService) GetExpenditure(id string) (*models.ExpenditureResponse, error) {
-	expenditure, err := repository.GetExpenditure(s.DB, id)
+func (s *ExpenditureService) GetExpenditure(ctx context.Context, id string) (*models.ExpenditureResponse, error) {
+	expenditure, err := s.Repo.GetExpenditure(ctx, id)
 	if err != nil {
-		return nil, errors.New("expenditure not found")
+		return nil, models.ErrExpenditureNotFound
 	}
 	return expenditure.ToResponse(), nil
 }
 
 // GetAllExpenditures returns all expenditures
-func (s *ExpenditureService) GetAllExpenditures() ([]models.ExpenditureResponse, error) {
-	expenditures, err := repository.GetAllExpenditures(s.DB)
+func (s *ExpenditureService) GetAllExpenditures(ctx context.Context) ([]models.ExpenditureResponse, error) {
+	expenditures, err := s.Repo.GetAllExpenditures(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -127,8 +186,8 @@ func (s *ExpenditureService) GetAllExpenditures() ([]models.ExpenditureResponse,
 }
 
 // GetExpendituresByTransaction returns expenditures for a specific transaction
-func (s *ExpenditureService) GetExpendituresByTransaction(transactionID string) ([]models.ExpenditureResponse, error) {
-	expenditures, err := repository.GetExpenditureByTransaction(s.DB, transactionID)
+func (s *ExpenditureService) GetExpendituresByTransaction(ctx context.Context, transactionID string) ([]models.ExpenditureResponse, error) {
+	expenditures, err := s.Repo.GetExpenditureByTransaction(ctx, transactionID)
 	if err != nil {
 		return nil, err
 	}
@@ -141,3 +200,19 @@ func (s *ExpenditureService) GetExpendituresByTransaction(transactionID string)
 
 	return responses, nil
 }
+
+// checkPayee ensures a payee, when given, exists and is still in use
+func (s *ExpenditureService) checkPayee(ctx context.Context, payeeID *string) error {
+	if payeeID == nil {
+		return nil
+	}
+
+	payee, err := s.Repo.GetPayee(ctx, *payeeID)
+	if err != nil {
+		return models.ErrPayeeNotFound
+	}
+	if !payee.IsActive {
+		return models.Invalid("payee is inactive")
+	}
+	return nil
+}
@@ -0,0 +1,313 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"strings"
+	"time"
+)
+
+type FundService struct {
+	Repo repository.Store
+}
+
+// Create a new instance of FundService
+func NewFundService(repo repository.Store) *FundService {
+	return &FundService{Repo: repo}
+}
+
+// CreateFund handles business logic for creating a new fund
+func (s *FundService) CreateFund(ctx context.Context, req models.CreateFundRequest) (*models.FundResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	code, _ := models.NormalizeFundCode(req.Code)
+	if code == models.GeneralFundCode {
+		return nil, models.Invalid("code " + code + " is reserved for lines without a fund")
+	}
+	if _, err := s.Repo.GetFundByCode(ctx, code); err == nil {
+		return nil, models.Conflict("fund code " + code + " already exists")
+	}
+
+	// Prepare model for DB
+	fund := models.Fund{
+		Code:         code,
+		Name:         strings.TrimSpace(req.Name),
+		Description:  trimToNil(req.Description),
+		IsRestricted: true,
+		IsActive:     true,
+	}
+	if req.IsRestricted != nil {
+		fund.IsRestricted = *req.IsRestricted
+	}
+
+	// Save to DB
+	newFund, err := s.Repo.CreateFund(ctx, fund)
+	if err != nil {
+		return nil, err
+	}
+
+	return newFund.ToResponse(), nil
+}
+
+// UpdateFund handles update logic; the code is fixed once created
+func (s *FundService) UpdateFund(ctx context.Context, id string, req models.UpdateFundRequest) (*models.FundResponse, error) {
+	// Fetch existing record
+	existing, err := s.Repo.GetFund(ctx, id)
+	if err != nil {
+		return nil, models.ErrFundNotFound
+	}
+
+	// Apply updates only if fields are provided
+	if req.Name != nil {
+		if strings.TrimSpace(*req.Name) == "" {
+			return nil, models.Invalid("name is required")
+		}
+		existing.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		existing.Description = trimToNil(req.Description)
+	}
+	if req.IsRestricted != nil {
+		existing.IsRestricted = *req.IsRestricted
+	}
+	if req.IsActive != nil {
+		existing.IsActive = *req.IsActive
+	}
+
+	// Persist update
+	updated, err := s.Repo.UpdateFund(ctx, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.ToResponse(), nil
+}
+
+// GetFund returns a fund by ID
+func (s *FundService) GetFund(ctx context.Context, id string) (*models.FundResponse, error) {
+	fund, err := s.Repo.GetFund(ctx, id)
+	if err != nil {
+		return nil, models.ErrFundNotFound
+	}
+
+	return fund.ToResponse(), nil
+}
+
+// GetAllFunds returns every fund
+func (s *FundService) GetAllFunds(ctx context.Context) ([]models.FundResponse, error) {
+	funds, err := s.Repo.GetAllFunds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response list
+	responses := make([]models.FundResponse, 0, len(funds))
+	for _, f := range funds {
+		responses = append(responses, *f.ToResponse())
+	}
+	return responses, nil
+}
+
+// GetBalances reports the posted balance of the general fund and every other fund at asOf,
+// with what each account holds and what open vouchers have committed
+func (s *FundService) GetBalances(ctx context.Context, asOf time.Time) (*models.FundBalancesReport, error) {
+	funds, err := s.Repo.GetAllFunds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := s.Repo.GetFundTotals(ctx, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	byFund := make(map[string]models.FundTotals)
+	for _, t := range totals {
+		key := ""
+		if t.FundID != nil {
+			key = *t.FundID
+		}
+		byFund[key] = t
+	}
+
+	report := &models.FundBalancesReport{AsOf: asOf, Funds: []models.FundBalance{}}
+
+	general, err := s.balance(ctx, nil, models.GeneralFundCode, models.GeneralFundName, false, byFund[""], asOf)
+	if err != nil {
+		return nil, err
+	}
+	report.Funds = append(report.Funds, *general)
+
+	for _, f := range funds {
+		fund := f
+		balance, err := s.balance(ctx, &fund.ID, fund.Code, fund.Name, fund.IsRestricted, byFund[fund.ID], asOf)
+		if err != nil {
+			return nil, err
+		}
+		report.Funds = append(report.Funds, *balance)
+	}
+
+	for _, b := range report.Funds {
+		if b.IsRestricted {
+			report.RestrictedTotal += b.Balance
+		} else {
+			report.UnrestrictedTotal += b.Balance
+		}
+	}
+	report.RestrictedTotal = roundAmount(report.RestrictedTotal)
+	report.UnrestrictedTotal = roundAmount(report.UnrestrictedTotal)
+	report.Total = roundAmount(report.RestrictedTotal + report.UnrestrictedTotal)
+
+	return report, nil
+}
+
+// GetBalance reports one fund's balance at asOf
+func (s *FundService) GetBalance(ctx context.Context, id string, asOf time.Time) (*models.FundBalance, error) {
+	fund, err := s.Repo.GetFund(ctx, id)
+	if err != nil {
+		return nil, models.ErrFundNotFound
+	}
+
+	totals, err := s.Repo.GetFundTotals(ctx, asOf)
+	if err != nil {
+		return nil, err
+	}
+	var fundTotals models.FundTotals
+	for _, t := range totals {
+		if t.FundID != nil && *t.FundID == fund.ID {
+			fundTotals = t
+		}
+	}
+
+	return s.balance(ctx, &fund.ID, fund.Code, fund.Name, fund.IsRestricted, fundTotals, asOf)
+}
+
+// balance builds one fund's balance line from its totals
+func (s *FundService) balance(ctx context.Context, fundID *string, code, name string, restricted bool, totals models.FundTotals, asOf time.Time) (*models.FundBalance, error) {
+	accounts, err := s.Repo.GetFundAccountBalances(ctx, fundID, asOf)
+	if err != nil {
+		return nil, err
+	}
+	if accounts == nil {
+		accounts = []models.FundAccountBalance{}
+	}
+
+	balance := &models.FundBalance{
+		FundID:       fundID,
+		Code:         code,
+		Name:         name,
+		IsRestricted: restricted,
+		Receipts:     roundAmount(totals.Receipts),
+		Expenditures: roundAmount(totals.Expenditures),
+		Balance:      roundAmount(totals.Receipts - totals.Expenditures),
+		Accounts:     accounts,
+	}
+	if fundID != nil {
+		committed, err := s.Repo.GetFundCommitted(ctx, *fundID, "")
+		if err != nil {
+			return nil, err
+		}
+		balance.Committed = roundAmount(committed)
+	}
+	balance.Available = roundAmount(balance.Balance - balance.Committed)
+
+	return balance, nil
+}
+
+// CheckFund makes sure a fund put on a new or changed line exists and is still in use
+func (s *FundService) CheckFund(ctx context.Context, fundID *string) error {
+	if fundID == nil {
+		return nil
+	}
+
+	fund, err := s.Repo.GetFund(ctx, *fundID)
+	if err != nil {
+		return models.ErrFundNotFound
+	}
+	if !fund.IsActive {
+		return models.Invalid("fund " + fund.Code + " is inactive")
+	}
+
+	return nil
+}
+
+// CheckExpenditure stops an expenditure line from taking a restricted fund below zero once
+// every other open voucher on the fund is paid. excludeID leaves out the line being edited.
+func (s *FundService) CheckExpenditure(ctx context.Context, fundID *string, amount float64, excludeID string) error {
+	if fundID == nil {
+		return nil
+	}
+
+	fund, err := s.Repo.GetFund(ctx, *fundID)
+	if err != nil {
+		return models.ErrFundNotFound
+	}
+	if !fund.IsRestricted {
+		return nil
+	}
+
+	available, err := s.postedBalance(ctx, fund.ID)
+	if err != nil {
+		return err
+	}
+	committed, err := s.Repo.GetFundCommitted(ctx, fund.ID, excludeID)
+	if err != nil {
+		return err
+	}
+	available = roundAmount(available - committed)
+
+	if roundAmount(available-amount) < 0 {
+		return models.Conflict(fmt.Sprintf("restricted fund %s has only %.2f available", fund.Code, available))
+	}
+
+	return nil
+}
+
+// CheckVoucher makes sure posting a voucher leaves none of its restricted funds below zero
+func (s *FundService) CheckVoucher(ctx context.Context, transactionID string) error {
+	spend, err := s.Repo.GetFundSpendByTransaction(ctx, transactionID)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range spend {
+		if line.FundID == nil {
+			continue
+		}
+		fund, err := s.Repo.GetFund(ctx, *line.FundID)
+		if err != nil {
+			return models.ErrFundNotFound
+		}
+		if !fund.IsRestricted {
+			continue
+		}
+
+		available, err := s.postedBalance(ctx, fund.ID)
+		if err != nil {
+			return err
+		}
+		if roundAmount(available-line.Expenditures) < 0 {
+			return models.Conflict(fmt.Sprintf("restricted fund %s has only %.2f available", fund.Code, roundAmount(available)))
+		}
+	}
+
+	return nil
+}
+
+// postedBalance is a fund's posted receipts less its posted expenditures, as of now
+func (s *FundService) postedBalance(ctx context.Context, fundID string) (float64, error) {
+	totals, err := s.Repo.GetFundTotals(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	for _, t := range totals {
+		if t.FundID != nil && *t.FundID == fundID {
+			return t.Receipts - t.Expenditures, nil
+		}
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"net/http"
+	"storeHouse/models"
+	"testing"
+)
+
+func TestRestrictedFundCannotBeOverspent(t *testing.T) {
+	f := newFixture(t)
+	bank := f.account("Bank", models.AccountBank)
+	income := f.account("Building offering", models.AccountIncome)
+	expense := f.account("Building works", models.AccountExpense)
+	fund := f.fund("BUILD", true)
+	f.receipt(bank, income, nil, &fund, 1000)
+
+	txn := f.voucher(expense, bank, nil, 1500)
+	_, err := f.expenditure(txn, bank, &fund, 1500)
+	wantConflict(t, err)
+
+	if _, err := f.expenditure(txn, bank, &fund, 800); err != nil {
+		t.Fatalf("expenditure within the fund: %v", err)
+	}
+
+	// The open voucher already holds 800 of the 1000
+	other := f.voucher(expense, bank, nil, 300)
+	_, err = f.expenditure(other, bank, &fund, 300)
+	wantConflict(t, err)
+}
+
+func TestUnrestrictedFundCanBeOverspent(t *testing.T) {
+	f := newFixture(t)
+	bank := f.account("Bank", models.AccountBank)
+	expense := f.account("Utilities", models.AccountExpense)
+	fund := f.fund("GEN", false)
+
+	txn := f.voucher(expense, bank, nil, 500)
+	if _, err := f.expenditure(txn, bank, &fund, 500); err != nil {
+		t.Fatalf("expenditure on an unrestricted fund: %v", err)
+	}
+}
+
+func TestPostVoucherRechecksRestrictedFund(t *testing.T) {
+	f := newFixture(t)
+	bank := f.account("Bank", models.AccountBank)
+	income := f.account("Building offering", models.AccountIncome)
+	expense := f.account("Building works", models.AccountExpense)
+	fund := f.fund("BUILD", true)
+	receipt := f.receipt(bank, income, nil, &fund, 1000)
+
+	txn, err := NewTransactionService(f.repo).CreateTransaction(f.ctx, models.CreateTransactionRequest{
+		TransactionType: string(models.TransactionExpenses),
+		Amount:          800,
+		DebitAccountID:  expense,
+	}, "clerk")
+	if err != nil {
+		t.Fatalf("create expenses voucher: %v", err)
+	}
+	if _, err := f.expenditure(txn.ID, bank, &fund, 800); err != nil {
+		t.Fatalf("create expenditure: %v", err)
+	}
+
+	vouchers := NewVoucherService(f.repo)
+	if _, err := vouchers.SubmitVoucher(f.ctx, txn.ID, "clerk"); err != nil {
+		t.Fatalf("submit voucher: %v", err)
+	}
+	if _, err := vouchers.ApproveVoucher(f.ctx, txn.ID, "treasurer", string(models.RoleTreasurer), models.ReviewVoucherRequest{}); err != nil {
+		t.Fatalf("approve voucher: %v", err)
+	}
+
+	// The money the voucher was raised against is no longer in the fund
+	if err := NewReceiptService(f.repo).DeleteReceipt(f.ctx, receipt.ID); err != nil {
+		t.Fatalf("delete receipt: %v", err)
+	}
+
+	_, err = vouchers.PostVoucher(f.ctx, txn.ID, "treasurer")
+	wantConflict(t, err)
+}
+
+func TestInactiveFundCannotBeUsed(t *testing.T) {
+	f := newFixture(t)
+	bank := f.account("Bank", models.AccountBank)
+	expense := f.account("Utilities", models.AccountExpense)
+	fund := f.fund("OLD", false)
+
+	inactive := false
+	if _, err := NewFundService(f.repo).UpdateFund(f.ctx, fund, models.UpdateFundRequest{IsActive: &inactive}); err != nil {
+		t.Fatalf("deactivate fund: %v", err)
+	}
+
+	txn := f.voucher(expense, bank, nil, 500)
+	_, err := f.expenditure(txn, bank, &fund, 500)
+	wantStatus(t, err, http.StatusBadRequest)
+}
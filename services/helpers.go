@@ -0,0 +1,19 @@
+package services
+
+// emptyToNil treats an empty string as not set
+func emptyToNil(value *string) *string {
+	if value == nil || *value == "" {
+		return nil
+	}
+	return value
+}
+
+// containsString reports whether list holds value
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"storeHouse/models"
+	"storeHouse/repository/memory"
+	"testing"
+)
+
+// fixture is a church's books in memory with helpers for the records the tests need
+type fixture struct {
+	t    *testing.T
+	ctx  context.Context
+	repo *memory.Store
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	return &fixture{t: t, ctx: context.Background(), repo: memory.New()}
+}
+
+func (f *fixture) account(name string, accountType models.AccountType) string {
+	f.t.Helper()
+	account, err := NewAccountService(f.repo).CreateAccount(f.ctx, models.CreateAccountRequest{
+		AccountName: name,
+		AccountType: string(accountType),
+	}, "admin")
+	if err != nil {
+		f.t.Fatalf("create account %s: %v", name, err)
+	}
+	return account.ID
+}
+
+func (f *fixture) fund(code string, restricted bool) string {
+	f.t.Helper()
+	fund, err := NewFundService(f.repo).CreateFund(f.ctx, models.CreateFundRequest{
+		Code:         code,
+		Name:         code + " fund",
+		IsRestricted: &restricted,
+	})
+	if err != nil {
+		f.t.Fatalf("create fund %s: %v", code, err)
+	}
+	return fund.ID
+}
+
+func (f *fixture) member(name, phone string) string {
+	f.t.Helper()
+	member, err := NewMemberService(f.repo).CreateMember(f.ctx, models.CreateMemberRequest{
+		FullName:    name,
+		PhoneNumber: phone,
+	}, "admin")
+	if err != nil {
+		f.t.Fatalf("create member %s: %v", name, err)
+	}
+	return member.ID
+}
+
+// receipt posts a receipt of amount into bank with a single line to income
+func (f *fixture) receipt(bank, income string, memberID, fundID *string, amount float64) models.ReceiptResponse {
+	f.t.Helper()
+	txn, err := NewTransactionService(f.repo).CreateTransaction(f.ctx, models.CreateTransactionRequest{
+		TransactionType: string(models.TransactionReceipts),
+		Amount:          amount,
+		DebitAccountID:  bank,
+		MemberID:        memberID,
+	}, "clerk")
+	if err != nil {
+		f.t.Fatalf("create receipts transaction: %v", err)
+	}
+	receipt, err := NewReceiptService(f.repo).CreateReceipt(f.ctx, models.CreateReceiptRequest{
+		TransactionID:   txn.ID,
+		IncomeAccountID: income,
+		FundID:          fundID,
+		Amount:          amount,
+	})
+	if err != nil {
+		f.t.Fatalf("create receipt: %v", err)
+	}
+	return *receipt
+}
+
+// voucher raises a draft expenses voucher charged to expense and paid from bank
+func (f *fixture) voucher(expense, bank string, fundID *string, amount float64) string {
+	f.t.Helper()
+	txn, err := NewTransactionService(f.repo).CreateTransaction(f.ctx, models.CreateTransactionRequest{
+		TransactionType: string(models.TransactionExpenses),
+		Amount:          amount,
+		DebitAccountID:  expense,
+	}, "clerk")
+	if err != nil {
+		f.t.Fatalf("create expenses voucher: %v", err)
+	}
+	if _, err := f.expenditure(txn.ID, bank, fundID, amount); err != nil {
+		f.t.Fatalf("create expenditure: %v", err)
+	}
+	return txn.ID
+}
+
+func (f *fixture) expenditure(transactionID, bank string, fundID *string, amount float64) (*models.ExpenditureResponse, error) {
+	return NewExpenditureService(f.repo).CreateExpenditure(f.ctx, models.CreateExpenditureRequest{
+		TransactionID: transactionID,
+		Particulars:   "Supplies",
+		BankAccountID: bank,
+		FundID:        fundID,
+		Amount:        amount,
+	}, "clerk")
+}
+
+// post takes a voucher through submission, a treasurer's approval and posting
+func (f *fixture) post(id string) {
+	f.t.Helper()
+	vouchers := NewVoucherService(f.repo)
+	if _, err := vouchers.SubmitVoucher(f.ctx, id, "clerk"); err != nil {
+		f.t.Fatalf("submit voucher: %v", err)
+	}
+	if _, err := vouchers.ApproveVoucher(f.ctx, id, "treasurer", string(models.RoleTreasurer), models.ReviewVoucherRequest{}); err != nil {
+		f.t.Fatalf("approve voucher: %v", err)
+	}
+	if _, err := vouchers.PostVoucher(f.ctx, id, "treasurer"); err != nil {
+		f.t.Fatalf("post voucher: %v", err)
+	}
+}
+
+// wantStatus fails the test unless err is an AppError returned with the given HTTP status
+func wantStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	var appErr *models.AppError
+	if !errors.As(err, &appErr) {
+		t.Fatalf("got error %v, want an error with status %d", err, status)
+	}
+	if appErr.Status != status {
+		t.Fatalf("got status %d (%v), want %d", appErr.Status, err, status)
+	}
+}
+
+// wantConflict fails the test unless err is a Conflict
+func wantConflict(t *testing.T, err error) {
+	t.Helper()
+	wantStatus(t, err, http.StatusConflict)
+}
@@ -0,0 +1,569 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"time"
+)
+
+type ImprestService struct {
+	Repo repository.Store
+}
+
+// Create a new instance of ImprestService
+func NewImprestService(repo repository.Store) *ImprestService {
+	return &ImprestService{Repo: repo}
+}
+
+// CreateImprest sets up a petty cash account with a fixed float. The float is funded by
+// the first replenishment.
+func (s *ImprestService) CreateImprest(ctx context.Context, req models.CreateImprestRequest, createdBy *string) (*models.ImprestResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Petty cash is held in a cash (Asset) or Bank account that is not already an imprest
+	account, err := getPostingAccount(ctx, s.Repo, req.AccountID, "account")
+	if err != nil {
+		return nil, err
+	}
+	if account.AccountType != string(models.AccountAsset) && account.AccountType != string(models.AccountBank) {
+		return nil, models.Invalid("imprest account must be an Asset or Bank account")
+	}
+	if _, err := s.Repo.GetImprestByAccount(ctx, req.AccountID); err == nil {
+		return nil, models.Conflict("account is already an imprest")
+	}
+
+	if err := s.checkReplenishFrom(ctx, req.ReplenishFromID, req.AccountID); err != nil {
+		return nil, err
+	}
+	if err := s.checkCustodian(ctx, req.CustodianID); err != nil {
+		return nil, err
+	}
+
+	// Prepare model for DB
+	imprest := models.Imprest{
+		Name:            req.Name,
+		AccountID:       req.AccountID,
+		FloatAmount:     req.FloatAmount,
+		ReplenishFromID: emptyToNil(req.ReplenishFromID),
+		CustodianID:     emptyToNil(req.CustodianID),
+		IsActive:        true,
+		CreatedBy:       createdBy,
+	}
+
+	// Save to DB
+	newImprest, err := s.Repo.CreateImprest(ctx, imprest)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.imprestResponse(ctx, newImprest)
+}
+
+// UpdateImprest handles update logic. A changed float takes effect at the next replenishment.
+func (s *ImprestService) UpdateImprest(ctx context.Context, id string, req models.UpdateImprestRequest) (*models.ImprestResponse, error) {
+	// Fetch existing record
+	existing, err := s.Repo.GetImprest(ctx, id)
+	if err != nil {
+		return nil, models.ErrImprestNotFound
+	}
+
+	// Apply updates only if fields are provided
+	if req.Name != nil {
+		if *req.Name == "" {
+			return nil, models.Invalid("name is required")
+		}
+		existing.Name = *req.Name
+	}
+	if req.FloatAmount != nil {
+		if *req.FloatAmount <= 0 {
+			return nil, models.Invalid("float_amount must be greater than zero")
+		}
+		existing.FloatAmount = *req.FloatAmount
+	}
+	if req.ReplenishFromID != nil {
+		if err := s.checkReplenishFrom(ctx, req.ReplenishFromID, existing.AccountID); err != nil {
+			return nil, err
+		}
+		existing.ReplenishFromID = emptyToNil(req.ReplenishFromID)
+	}
+	if req.CustodianID != nil {
+		if err := s.checkCustodian(ctx, req.CustodianID); err != nil {
+			return nil, err
+		}
+		existing.CustodianID = emptyToNil(req.CustodianID)
+	}
+	if req.IsActive != nil {
+		existing.IsActive = *req.IsActive
+	}
+
+	// Persist update
+	updated, err := s.Repo.UpdateImprest(ctx, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.imprestResponse(ctx, updated)
+}
+
+// GetImprest returns an imprest with its cash position
+func (s *ImprestService) GetImprest(ctx context.Context, id string) (*models.ImprestResponse, error) {
+	imprest, err := s.Repo.GetImprest(ctx, id)
+	if err != nil {
+		return nil, models.ErrImprestNotFound
+	}
+
+	return s.imprestResponse(ctx, imprest)
+}
+
+// GetAllImprests returns every imprest with its cash position
+func (s *ImprestService) GetAllImprests(ctx context.Context) ([]models.ImprestResponse, error) {
+	imprests, err := s.Repo.GetAllImprests(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response list
+	responses := make([]models.ImprestResponse, 0, len(imprests))
+	for _, i := range imprests {
+		response, err := s.imprestResponse(ctx, i)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, *response)
+	}
+
+	return responses, nil
+}
+
+// CreateVoucher records a payment out of the petty cash box against an Expense account.
+// It cannot pay out more cash than is in the box.
+func (s *ImprestService) CreateVoucher(ctx context.Context, imprestID string, req models.CreatePettyCashVoucherRequest, createdBy *string) (*models.PettyCashVoucherResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	imprest, err := s.Repo.GetImprest(ctx, imprestID)
+	if err != nil {
+		return nil, models.ErrImprestNotFound
+	}
+	if !imprest.IsActive {
+		return nil, models.Invalid("imprest is not active")
+	}
+
+	account, err := getPostingAccount(ctx, s.Repo, req.ExpenseAccountID, "expense account")
+	if err != nil {
+		return nil, err
+	}
+	if account.AccountType != string(models.AccountExpense) {
+		return nil, models.Invalid("petty cash vouchers must be charged to an Expense account")
+	}
+
+	position, err := s.imprestResponse(ctx, imprest)
+	if err != nil {
+		return nil, err
+	}
+	if req.Amount > roundAmount(position.CashOnHand) {
+		return nil, models.Invalid(fmt.Sprintf("amount exceeds the cash on hand of %.2f", position.CashOnHand))
+	}
+
+	// Prepare model for DB
+	voucher := models.PettyCashVoucher{
+		ImprestID:        imprest.ID,
+		VoucherDate:      time.Now(),
+		ExpenseAccountID: req.ExpenseAccountID,
+		Particulars:      req.Particulars,
+		PaidTo:           req.PaidTo,
+		Amount:           req.Amount,
+		Status:           string(models.PettyCashOpen),
+		CreatedBy:        createdBy,
+	}
+	if req.VoucherDate != nil && !req.VoucherDate.IsZero() {
+		voucher.VoucherDate = *req.VoucherDate
+	}
+
+	// Save to DB
+	newVoucher, err := s.Repo.CreatePettyCashVoucher(ctx, voucher)
+	if err != nil {
+		return nil, err
+	}
+
+	return newVoucher.ToResponse(), nil
+}
+
+// DeleteVoucher removes a voucher that has not been claimed in a replenishment
+func (s *ImprestService) DeleteVoucher(ctx context.Context, id string) error {
+	// Ensure exists before deleting
+	voucher, err := s.Repo.GetPettyCashVoucher(ctx, id)
+	if err != nil {
+		return models.ErrPettyCashVoucherNotFound
+	}
+	if voucher.Status != string(models.PettyCashOpen) {
+		return models.Conflict("only open petty cash vouchers can be deleted")
+	}
+
+	return s.Repo.DeletePettyCashVoucher(ctx, id)
+}
+
+// GetVouchers returns an imprest's vouchers, optionally filtered by status
+func (s *ImprestService) GetVouchers(ctx context.Context, imprestID, status string) ([]models.PettyCashVoucherResponse, error) {
+	if _, err := s.Repo.GetImprest(ctx, imprestID); err != nil {
+		return nil, models.ErrImprestNotFound
+	}
+
+	var vouchers []models.PettyCashVoucher
+	var err error
+	if status != "" {
+		vouchers, err = s.Repo.GetPettyCashVouchersByStatus(ctx, imprestID, status)
+	} else {
+		vouchers, err = s.Repo.GetPettyCashVouchersByImprest(ctx, imprestID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toPettyCashVoucherResponses(vouchers), nil
+}
+
+// RequestReplenishment claims the open vouchers and works out the top-up that restores the
+// float. It is posted once someone other than the requester approves it.
+func (s *ImprestService) RequestReplenishment(ctx context.Context, imprestID string, req models.RequestReplenishmentRequest, requestedBy string) (*models.ImprestReplenishmentResponse, error) {
+	imprest, err := s.Repo.GetImprest(ctx, imprestID)
+	if err != nil {
+		return nil, models.ErrImprestNotFound
+	}
+	if !imprest.IsActive {
+		return nil, models.Invalid("imprest is not active")
+	}
+
+	if _, err := s.Repo.GetPendingImprestReplenishment(ctx, imprestID); err == nil {
+		return nil, models.Conflict("imprest already has a replenishment awaiting approval")
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	bankAccountID := imprest.ReplenishFromID
+	if req.BankAccountID != nil && *req.BankAccountID != "" {
+		bankAccountID = req.BankAccountID
+	}
+	if bankAccountID == nil {
+		return nil, models.Invalid("bank_account_id is required when the imprest has no replenish_from account")
+	}
+	if err := s.checkReplenishFrom(ctx, bankAccountID, imprest.AccountID); err != nil {
+		return nil, err
+	}
+
+	position, err := s.imprestResponse(ctx, imprest)
+	if err != nil {
+		return nil, err
+	}
+	if position.TopUpDue <= 0 {
+		return nil, models.Conflict("imprest is already at its float")
+	}
+
+	// Prepare model for DB
+	replenishment := models.ImprestReplenishment{
+		ImprestID:     imprest.ID,
+		BankAccountID: *bankAccountID,
+		VouchersTotal: position.UnclaimedVouchers,
+		Amount:        position.TopUpDue,
+		Status:        string(models.ReplenishmentPending),
+		Notes:         req.Notes,
+		RequestedBy:   &requestedBy,
+	}
+
+	// Save to DB
+	newReplenishment, err := s.Repo.CreateImprestReplenishment(ctx, replenishment)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Repo.ClaimPettyCashVouchers(ctx, imprest.ID, newReplenishment.ID); err != nil {
+		return nil, err
+	}
+
+	return s.replenishmentResponse(ctx, newReplenishment)
+}
+
+// ApproveReplenishment posts a replenishment: one expenses transaction per Expense account
+// paid from the imprest, and a transfer from the Bank account that restores the float
+func (s *ImprestService) ApproveReplenishment(ctx context.Context, id, approverID, approverRole string) (*models.ImprestReplenishmentResponse, error) {
+	replenishment, imprest, err := s.getPendingReplenishment(ctx, id, approverID)
+	if err != nil {
+		return nil, err
+	}
+
+	vouchers, err := s.Repo.GetPettyCashVouchersByReplenishment(ctx, replenishment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The books may have moved since the request, so work the top-up out again
+	bookBalance, err := s.bookBalance(ctx, imprest.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	claimed := 0.0
+	for _, v := range vouchers {
+		claimed += v.Amount
+	}
+	topUp := roundAmount(imprest.FloatAmount - (bookBalance - claimed))
+	if topUp <= 0 {
+		return nil, models.Conflict("imprest is already at its float")
+	}
+
+	requestedBy := approverID
+	if replenishment.RequestedBy != nil {
+		requestedBy = *replenishment.RequestedBy
+	}
+	notes := fmt.Sprintf("Petty cash replenishment - %s", imprest.Name)
+
+	now := time.Now()
+	posted := func(txnType models.TransactionType, debitAccountID string, amount float64) models.Transaction {
+		return models.Transaction{
+			TransactionDate: now,
+			TransactionType: string(txnType),
+			Amount:          roundAmount(amount),
+			Notes:           &notes,
+			DebitAccountID:  debitAccountID,
+			Status:          string(models.TransactionPosted),
+			SubmittedBy:     &requestedBy,
+			SubmittedAt:     &now,
+			PostedBy:        &approverID,
+			PostedAt:        &now,
+			CreatedBy:       requestedBy,
+		}
+	}
+
+	// Expenses paid out of the box, one transaction per Expense account
+	byAccount := map[string][]models.PettyCashVoucher{}
+	accounts := []string{}
+	for _, v := range vouchers {
+		if _, ok := byAccount[v.ExpenseAccountID]; !ok {
+			accounts = append(accounts, v.ExpenseAccountID)
+		}
+		byAccount[v.ExpenseAccountID] = append(byAccount[v.ExpenseAccountID], v)
+	}
+	expenses := make([]models.ReplenishmentExpense, 0, len(accounts))
+	for _, accountID := range accounts {
+		lines := byAccount[accountID]
+		total := 0.0
+		expenditures := make([]models.Expenditure, 0, len(lines))
+		for _, v := range lines {
+			total += v.Amount
+			expenditures = append(expenditures, models.Expenditure{
+				Particulars:   truncate(fmt.Sprintf("%s %s", v.VoucherNumber, v.Particulars), 255),
+				BankAccountID: imprest.AccountID,
+				Amount:        v.Amount,
+			})
+		}
+
+		expenses = append(expenses, models.ReplenishmentExpense{
+			Transaction:  posted(models.TransactionExpenses, accountID, total),
+			Expenditures: expenditures,
+			Vouchers:     lines,
+		})
+	}
+
+	replenishment.Amount = topUp
+	replenishment.ReviewedBy = &approverID
+	replenishment.ReviewedAt = &now
+
+	// Everything goes into the books in one database transaction, with the top-up from the
+	// bank back to the float
+	updated, err := s.Repo.PostImprestReplenishment(ctx, models.ReplenishmentPosting{
+		Replenishment: replenishment,
+		Expenses:      expenses,
+		TopUp:         posted(models.TransactionTransfer, imprest.AccountID, topUp),
+		TopUpLine: models.Transfer{
+			Particulars:     fmt.Sprintf("Top-up of %s to its float", imprest.Name),
+			CreditAccountID: replenishment.BankAccountID,
+			Amount:          topUp,
+		},
+		Approval: models.VoucherApproval{
+			ApproverID:   approverID,
+			ApproverRole: &approverRole,
+			Decision:     string(models.VoucherApproved),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.replenishmentResponse(ctx, updated)
+}
+
+// RejectReplenishment turns a replenishment down and returns its vouchers to the box
+func (s *ImprestService) RejectReplenishment(ctx context.Context, id, reviewerID string, req models.ReviewVoucherRequest) (*models.ImprestReplenishmentResponse, error) {
+	replenishment, _, err := s.getPendingReplenishment(ctx, id, reviewerID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Comment == nil || *req.Comment == "" {
+		return nil, models.Invalid("comment is required when rejecting a replenishment")
+	}
+
+	if err := s.Repo.ReleasePettyCashVouchers(ctx, replenishment.ID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	replenishment.Status = string(models.ReplenishmentRejected)
+	replenishment.Notes = req.Comment
+	replenishment.ReviewedBy = &reviewerID
+	replenishment.ReviewedAt = &now
+
+	// Persist update
+	updated, err := s.Repo.UpdateImprestReplenishment(ctx, replenishment)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.replenishmentResponse(ctx, updated)
+}
+
+// GetReplenishment returns a replenishment with the vouchers it covers
+func (s *ImprestService) GetReplenishment(ctx context.Context, id string) (*models.ImprestReplenishmentResponse, error) {
+	replenishment, err := s.Repo.GetImprestReplenishment(ctx, id)
+	if err != nil {
+		return nil, models.ErrReplenishmentNotFound
+	}
+
+	return s.replenishmentResponse(ctx, replenishment)
+}
+
+// GetReplenishments returns an imprest's replenishments, newest first
+func (s *ImprestService) GetReplenishments(ctx context.Context, imprestID string) ([]models.ImprestReplenishmentResponse, error) {
+	if _, err := s.Repo.GetImprest(ctx, imprestID); err != nil {
+		return nil, models.ErrImprestNotFound
+	}
+
+	replenishments, err := s.Repo.GetImprestReplenishments(ctx, imprestID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response list
+	responses := make([]models.ImprestReplenishmentResponse, 0, len(replenishments))
+	for _, r := range replenishments {
+		responses = append(responses, *r.ToResponse())
+	}
+
+	return responses, nil
+}
+
+// getPendingReplenishment fetches a replenishment awaiting review by someone other than its requester
+func (s *ImprestService) getPendingReplenishment(ctx context.Context, id, reviewerID string) (models.ImprestReplenishment, models.Imprest, error) {
+	replenishment, err := s.Repo.GetImprestReplenishment(ctx, id)
+	if err != nil {
+		return models.ImprestReplenishment{}, models.Imprest{}, models.ErrReplenishmentNotFound
+	}
+	if replenishment.Status != string(models.ReplenishmentPending) {
+		return models.ImprestReplenishment{}, models.Imprest{}, models.Conflict("only pending replenishments can be approved or rejected")
+	}
+	if replenishment.RequestedBy != nil && *replenishment.RequestedBy == reviewerID {
+		return models.ImprestReplenishment{}, models.Imprest{}, models.ErrSelfApproval
+	}
+
+	imprest, err := s.Repo.GetImprest(ctx, replenishment.ImprestID)
+	if err != nil {
+		return models.ImprestReplenishment{}, models.Imprest{}, models.ErrImprestNotFound
+	}
+
+	return replenishment, imprest, nil
+}
+
+// imprestResponse adds the imprest's cash position: what the books hold, less the vouchers
+// paid out but not yet replenished
+func (s *ImprestService) imprestResponse(ctx context.Context, imprest models.Imprest) (*models.ImprestResponse, error) {
+	bookBalance, err := s.bookBalance(ctx, imprest.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	unclaimed, err := s.Repo.GetTotalPettyCashVouchersByStatus(ctx, imprest.ID, string(models.PettyCashOpen))
+	if err != nil {
+		return nil, err
+	}
+	claimed, err := s.Repo.GetTotalPettyCashVouchersByStatus(ctx, imprest.ID, string(models.PettyCashClaimed))
+	if err != nil {
+		return nil, err
+	}
+
+	response := imprest.ToResponse()
+	response.BookBalance = roundAmount(bookBalance)
+	response.UnclaimedVouchers = roundAmount(unclaimed)
+	response.ClaimedVouchers = roundAmount(claimed)
+	response.CashOnHand = roundAmount(bookBalance - unclaimed - claimed)
+	response.TopUpDue = roundAmount(imprest.FloatAmount - response.CashOnHand)
+
+	return response, nil
+}
+
+// replenishmentResponse builds the replenishment response with its vouchers
+func (s *ImprestService) replenishmentResponse(ctx context.Context, replenishment models.ImprestReplenishment) (*models.ImprestReplenishmentResponse, error) {
+	vouchers, err := s.Repo.GetPettyCashVouchersByReplenishment(ctx, replenishment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := replenishment.ToResponse()
+	response.Vouchers = toPettyCashVoucherResponses(vouchers)
+	return response, nil
+}
+
+// bookBalance returns the posted balance of the imprest account
+func (s *ImprestService) bookBalance(ctx context.Context, accountID string) (float64, error) {
+	entries, err := s.Repo.GetBankBookEntries(ctx, accountID, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	balance := 0.0
+	for _, e := range entries {
+		balance += e.Amount
+	}
+	return balance, nil
+}
+
+// checkReplenishFrom checks that top-ups come from a Bank account other than the imprest's own
+func (s *ImprestService) checkReplenishFrom(ctx context.Context, accountID *string, imprestAccountID string) error {
+	if accountID == nil || *accountID == "" {
+		return nil
+	}
+
+	account, err := s.Repo.GetAccount(ctx, *accountID)
+	if err != nil {
+		return models.Wrap(models.ErrAccountNotFound, "bank account not found")
+	}
+	if account.AccountType != string(models.AccountBank) {
+		return models.Invalid("imprests are replenished from a Bank account")
+	}
+	if account.ID == imprestAccountID {
+		return models.Invalid("an imprest cannot be replenished from its own account")
+	}
+	return nil
+}
+
+// checkCustodian checks that the custodian is a known user
+func (s *ImprestService) checkCustodian(ctx context.Context, userID *string) error {
+	if userID == nil || *userID == "" {
+		return nil
+	}
+	if _, err := s.Repo.GetUser(ctx, *userID); err != nil {
+		return models.Wrap(models.ErrUserNotFound, "custodian not found")
+	}
+	return nil
+}
+
+// toPettyCashVoucherResponses converts vouchers to their responses
+func toPettyCashVoucherResponses(vouchers []models.PettyCashVoucher) []models.PettyCashVoucherResponse {
+	responses := make([]models.PettyCashVoucherResponse, 0, len(vouchers))
+	for _, v := range vouchers {
+		responses = append(responses, *v.ToResponse())
+	}
+	return responses
+}
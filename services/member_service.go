@@ -1,47 +1,45 @@
 package services
 
 import (
-	"errors"
+	"context"
 	"regexp"
 	"storeHouse/models"
 	"storeHouse/repository"
 	"time"
-
-	"github.com/jmoiron/sqlx"
 )
 
 type MemberService struct {
-	DB *sqlx.DB
+	Repo repository.Store
 }
 
 // Create a new instance of MemberService
-func NewMemberService(db *sqlx.DB) *MemberService {
-	return &MemberService{DB: db}
+func NewMemberService(repo repository.Store) *MemberService {
+	return &MemberService{Repo: repo}
 }
 
 // CreateMember handles member creation business logic
-func (s *MemberService) CreateMember(req models.CreateMemberRequest, createdBy string) (*models.MemberResponse, error) {
+func (s *MemberService) CreateMember(ctx context.Context, req models.CreateMemberRequest, createdBy string) (*models.MemberResponse, error) {
 	// Validate phone number format (basic validation)
 	if !isValidPhoneNumber(req.PhoneNumber) {
-		return nil, errors.New("invalid phone number format")
+		return nil, models.ErrInvalidPhone
 	}
 
 	// Check for duplicate phone number
-	if _, err := repository.GetMemberByPhone(s.DB, req.PhoneNumber); err == nil {
-		return nil, errors.New("member with this phone number already exists")
+	if _, err := s.Repo.GetMemberByPhone(ctx, req.PhoneNumber); err == nil {
+		return nil, models.Conflict("member with this phone number already exists")
 	}
 
 	// Check for duplicate email if provided
 	if req.Email != nil {
-		if _, err := repository.GetMemberByEmail(s.DB, *req.Email); err == nil {
-			return nil, errors.New("member with this email already exists")
+		if _, err := s.Repo.GetMemberByEmail(ctx, *req.Email); err == nil {
+			return nil, models.Conflict("member with this email already exists")
 		}
 	}
 
 	// Validate group if provided
 	if req.GroupID != nil {
-		if _, err := repository.GetGroup(s.DB, *req.GroupID); err != nil {
-			return nil, errors.New("specified group not found")
+		if _, err := s.Repo.GetGroup(ctx, *req.GroupID); err != nil {
+			return nil, models.Wrap(models.ErrGroupNotFound, "specified group not found")
 		}
 	}
 
@@ -58,7 +56,7 @@ func (s *MemberService) CreateMember(req models.CreateMemberRequest, createdBy s
 	}
 
 	// Save to DB
-	newMember, err := repository.CreateMember(s.DB, member)
+	newMember, err := s.Repo.CreateMember(ctx, member)
 	if err != nil {
 		return nil, err
 	}
@@ -67,11 +65,11 @@ func (s *MemberService) CreateMember(req models.CreateMemberRequest, createdBy s
 }
 
 // UpdateMember handles update logic
-func (s *MemberService) UpdateMember(id string, req models.UpdateMemberRequest) (*models.MemberResponse, error) {
+func (s *MemberService) UpdateMember(ctx context.Context, id string, req models.UpdateMemberRequest) (*models.MemberResponse, error) {
 	// Fetch existing record
-	existing, err := repository.GetMember(s.DB, id)
+	existing, err := s.Repo.GetMember(ctx, id)
 	if err != nil {
-		return nil, errors.New("member not found")
+		return nil, models.ErrMemberNotFound
 	}
 
 	// Apply updates only if fields are provided
@@ -81,12 +79,12 @@ func (s *MemberService) UpdateMember(id string, req models.UpdateMemberRequest)
 	if req.PhoneNumber != nil {
 		// Validate phone number format
 		if !isValidPhoneNumber(*req.PhoneNumber) {
-			return nil, errors.New("invalid phone number format")
+			return nil, models.ErrInvalidPhone
 		}
 		// Check for duplicate phone number
 		if existing.PhoneNumber != *req.PhoneNumber {
-			if _, err := repository.GetMemberByPhone(s.DB, *req.PhoneNumber); err == nil {
-				return nil, errors.New("member with this phone number already exists")
+			if _, err := s.Repo.GetMemberByPhone(ctx, *req.PhoneNumber); err == nil {
+				return nil, models.Conflict("member with this phone number already exists")
 			}
 		}
 		existing.PhoneNumber = *req.PhoneNumber
@@ -94,8 +92,8 @@ func (s *MemberService) UpdateMember(id string, req models.UpdateMemberRequest)
 	if req.Email != nil {
 		// Check for duplicate email if different
 		if existing.Email == nil || *existing.Email != *req.Email {
-			if _, err := repository.GetMemberByEmail(s.DB, *req.Email); err == nil {
-				return nil, errors.New("member with this email already exists")
+			if _, err := s.Repo.GetMemberByEmail(ctx, *req.Email); err == nil {
+				return nil, models.Conflict("member with this email already exists")
 			}
 		}
 		existing.Email = req.Email
@@ -106,8 +104,8 @@ func (s *MemberService) UpdateMember(id string, req models.UpdateMemberRequest)
 	if req.GroupID != nil {
 		// Validate group if provided
 		if *req.GroupID != "" {
-			if _, err := repository.GetGroup(s.DB, *req.GroupID); err != nil {
-				return nil, errors.New("specified group not found")
+			if _, err := s.Repo.GetGroup(ctx, *req.GroupID); err != nil {
+				return nil, models.Wrap(models.ErrGroupNotFound, "specified group not found")
 			}
 		}
 		existing.GroupID = req.GroupID
@@ -116,7 +114,7 @@ func (s *MemberService) UpdateMember(id string, req models.UpdateMemberRequest)
 	existing.UpdatedAt = time.Now()
 
 	// Persist update
-	updated, err := repository.UpdateMember(s.DB, existing)
+	updated, err := s.Repo.UpdateMember(ctx, existing)
 	if err != nil {
 		return nil, err
 	}
@@ -125,27 +123,27 @@ func (s *MemberService) UpdateMember(id string, req models.UpdateMemberRequest)
 }
 
 // DeleteMember removes a member record
-func (s *MemberService) DeleteMember(id string) error {
+func (s *MemberService) DeleteMember(ctx context.Context, id string) error {
 	// Ensure exists before deleting
-	if _, err := repository.GetMember(s.DB, id); err != nil {
-		return errors.New("member not found")
+	if _, err := s.Repo.GetMember(ctx, id); err != nil {
+		return models.ErrMemberNotFound
 	}
 
-	return repository.DeleteMember(s.DB, id)
+	return s.Repo.DeleteMember(ctx, id)
 }
 
 // GetMember returns single member details
-func (s *MemberService) GetMember(id string) (*models.MemberResponse, error) {
-	member, err := repository.GetMember(s.DB, id)
+func (s *MemberService) GetMember(ctx context.Context, id string) (*models.MemberResponse, error) {
+	member, err := s.Repo.GetMember(ctx, id)
 	if err != nil {
-		return nil, errors.New("member not found")
+		return nil, models.ErrMemberNotFound
 	}
 	return member.ToResponse(), nil
 }
 
 // GetAllMembers returns all members
-func (s *MemberService) GetAllMembers() ([]models.MemberResponse, error) {
-	members, err := repository.GetAllMembers(s.DB)
+func (s *MemberService) GetAllMembers(ctx context.Context) ([]models.MemberResponse, error) {
+	members, err := s.Repo.GetAllMembers(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -160,8 +158,8 @@ func (s *MemberService) GetAllMembers() ([]models.MemberResponse, error) {
 }
 
 // GetMembersByGroup returns members in a specific group
-func (s *MemberService) GetMembersByGroup(groupID string) ([]models.MemberResponse, error) {
-	members, err := repository.GetMemberByGroup(s.DB, groupID)
+func (s *MemberService) GetMembersByGroup(ctx context.Context, groupID string) ([]models.MemberResponse, error) {
+	members, err := s.Repo.GetMemberByGroup(ctx, groupID)
 	if err != nil {
 		return nil, err
 	}
@@ -176,8 +174,8 @@ func (s *MemberService) GetMembersByGroup(groupID string) ([]models.MemberRespon
 }
 
 // SearchMembers searches for members by name, phone number, or email
-func (s *MemberService) SearchMembers(searchTerm string) ([]models.MemberResponse, error) {
-	members, err := repository.SearchMembers(s.DB, searchTerm)
+func (s *MemberService) SearchMembers(ctx context.Context, searchTerm string) ([]models.MemberResponse, error) {
+	members, err := s.Repo.SearchMembers(ctx, searchTerm)
 	if err != nil {
 		return nil, err
 	}
@@ -192,19 +190,19 @@ func (s *MemberService) SearchMembers(searchTerm string) ([]models.MemberRespons
 }
 
 // GetMemberByPhone returns member by phone number
-func (s *MemberService) GetMemberByPhone(phoneNumber string) (*models.MemberResponse, error) {
-	member, err := repository.GetMemberByPhone(s.DB, phoneNumber)
+func (s *MemberService) GetMemberByPhone(ctx context.Context, phoneNumber string) (*models.MemberResponse, error) {
+	member, err := s.Repo.GetMemberByPhone(ctx, phoneNumber)
 	if err != nil {
-		return nil, errors.New("member not found")
+		return nil, models.ErrMemberNotFound
 	}
 	return member.ToResponse(), nil
 }
 
 // GetMemberByEmail returns member by email
-func (s *MemberService) GetMemberByEmail(email string) (*models.MemberResponse, error) {
-	member, err := repository.GetMemberByEmail(s.DB, email)
+func (s *MemberService) GetMemberByEmail(ctx context.Context, email string) (*models.MemberResponse, error) {
+	member, err := s.Repo.GetMemberByEmail(ctx, email)
 	if err != nil {
-		return nil, errors.New("member not found")
+		return nil, models.ErrMemberNotFound
 	}
 	return member.ToResponse(), nil
 }
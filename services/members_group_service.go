@@ -1,28 +1,26 @@
 package services
 
 import (
-	"errors"
+	"context"
 	"storeHouse/models"
 	"storeHouse/repository"
 	"time"
-
-	"github.com/jmoiron/sqlx"
 )
 
 type MembersGroupService struct {
-	DB *sqlx.DB
+	Repo repository.Store
 }
 
 // Create a new instance of MembersGroupService
-func NewMembersGroupService(db *sqlx.DB) *MembersGroupService {
-	return &MembersGroupService{DB: db}
+func NewMembersGroupService(repo repository.Store) *MembersGroupService {
+	return &MembersGroupService{Repo: repo}
 }
 
 // CreateGroup handles group creation business logic
-func (s *MembersGroupService) CreateGroup(req models.CreateGroupRequest, createdBy string) (*models.GroupResponse, error) {
+func (s *MembersGroupService) CreateGroup(ctx context.Context, req models.CreateGroupRequest, createdBy string) (*models.GroupResponse, error) {
 	// Check for duplicate group name
-	if _, err := repository.GetGroupByName(s.DB, req.GroupName); err == nil {
-		return nil, errors.New("group name already exists")
+	if _, err := s.Repo.GetGroupByName(ctx, req.GroupName); err == nil {
+		return nil, models.Conflict("group name already exists")
 	}
 
 	// Prepare model for DB
@@ -35,7 +33,7 @@ func (s *MembersGroupService) CreateGroup(req models.CreateGroupRequest, created
 	}
 
 	// Save to DB
-	newGroup, err := repository.CreateGroup(s.DB, group)
+	newGroup, err := s.Repo.CreateGroup(ctx, group)
 	if err != nil {
 		return nil, err
 	}
@@ -44,19 +42,19 @@ func (s *MembersGroupService) CreateGroup(req models.CreateGroupRequest, created
 }
 
 // UpdateGroup handles update logic
-func (s *MembersGroupService) UpdateGroup(id string, req models.UpdateGroupRequest) (*models.GroupResponse, error) {
+func (s *MembersGroupService) UpdateGroup(ctx context.Context, id string, req models.UpdateGroupRequest) (*models.GroupResponse, error) {
 	// Fetch existing record
-	existing, err := repository.GetGroup(s.DB, id)
+	existing, err := s.Repo.GetGroup(ctx, id)
 	if err != nil {
-		return nil, errors.New("group not found")
+		return nil, models.ErrGroupNotFound
 	}
 
 	// Apply updates only if fields are provided
 	if req.GroupName != nil {
 		// Check for duplicate group name if different
 		if existing.GroupName != *req.GroupName {
-			if _, err := repository.GetGroupByName(s.DB, *req.GroupName); err == nil {
-				return nil, errors.New("group name already exists")
+			if _, err := s.Repo.GetGroupByName(ctx, *req.GroupName); err == nil {
+				return nil, models.Conflict("group name already exists")
 			}
 		}
 		existing.GroupName = *req.GroupName
@@ -68,7 +66,7 @@ func (s *MembersGroupService) UpdateGroup(id string, req models.UpdateGroupReque
 	existing.UpdatedAt = time.Now()
 
 	// Persist update
-	updated, err := repository.UpdateGroup(s.DB, existing)
+	updated, err := s.Repo.UpdateGroup(ctx, existing)
 	if err != nil {
 		return nil, err
 	}
@@ -77,33 +75,33 @@ func (s *MembersGroupService) UpdateGroup(id string, req models.UpdateGroupReque
 }
 
 // DeleteGroup removes a group record
-func (s *MembersGroupService) DeleteGroup(id string) error {
+func (s *MembersGroupService) DeleteGroup(ctx context.Context, id string) error {
 	// Ensure exists before deleting
-	if _, err := repository.GetGroup(s.DB, id); err != nil {
-		return errors.New("group not found")
+	if _, err := s.Repo.GetGroup(ctx, id); err != nil {
+		return models.ErrGroupNotFound
 	}
 
 	// Check if there are members in this group
-	members, err := repository.GetMemberByGroup(s.DB, id)
+	members, err := s.Repo.GetMemberByGroup(ctx, id)
 	if err == nil && len(members) > 0 {
-		return errors.New("cannot delete group with existing members")
+		return models.Conflict("cannot delete group with existing members")
 	}
 
-	return repository.DeleteGroup(s.DB, id)
+	return s.Repo.DeleteGroup(ctx, id)
 }
 
 // GetGroup returns single group details
-func (s *MembersGroupService) GetGroup(id string) (*models.GroupResponse, error) {
-	group, err := repository.GetGroup(s.DB, id)
+func (s *MembersGroupService) GetGroup(ctx context.Context, id string) (*models.GroupResponse, error) {
+	group, err := s.Repo.GetGroup(ctx, id)
 	if err != nil {
-		return nil, errors.New("group not found")
+		return nil, models.ErrGroupNotFound
 	}
 	return group.ToResponse(), nil
 }
 
 // GetAllGroups returns all groups
-func (s *MembersGroupService) GetAllGroups() ([]models.GroupResponse, error) {
-	groups, err := repository.GetAllGroups(s.DB)
+func (s *MembersGroupService) GetAllGroups(ctx context.Context) ([]models.GroupResponse, error) {
+	groups, err := s.Repo.GetAllGroups(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -118,22 +116,22 @@ func (s *MembersGroupService) GetAllGroups() ([]models.GroupResponse, error) {
 }
 
 // GetGroupByName returns group by name
-func (s *MembersGroupService) GetGroupByName(name string) (*models.GroupResponse, error) {
-	group, err := repository.GetGroupByName(s.DB, name)
+func (s *MembersGroupService) GetGroupByName(ctx context.Context, name string) (*models.GroupResponse, error) {
+	group, err := s.Repo.GetGroupByName(ctx, name)
 	if err != nil {
-		return nil, errors.New("group not found")
+		return nil, models.ErrGroupNotFound
 	}
 	return group.ToResponse(), nil
 }
 
 // GetGroupsWithMemberCount returns all groups with their member counts
-func (s *MembersGroupService) GetGroupsWithMemberCount() ([]map[string]interface{}, error) {
-	return repository.GetGroupsWithMemberCount(s.DB)
+func (s *MembersGroupService) GetGroupsWithMemberCount(ctx context.Context) ([]map[string]interface{}, error) {
+	return s.Repo.GetGroupsWithMemberCount(ctx)
 }
 
 // GetGroupMemberCount returns the number of members in a specific group
-func (s *MembersGroupService) GetGroupMemberCount(groupID string) (int, error) {
-	members, err := repository.GetMemberByGroup(s.DB, groupID)
+func (s *MembersGroupService) GetGroupMemberCount(ctx context.Context, groupID string) (int, error) {
+	members, err := s.Repo.GetMemberByGroup(ctx, groupID)
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"storeHouse/models"
+	"strings"
+	"time"
+)
+
+// mobileMoneyColumns maps the header names used in M-Pesa and Airtel Money statement exports
+// to the fields we read
+var mobileMoneyColumns = map[string][]string{
+	"code":        {"receipt no.", "receipt no", "receipt", "transaction id", "transaction code", "txn id", "tid", "reference no"},
+	"time":        {"completion time", "transaction date", "transaction time", "date", "date time", "initiation time"},
+	"details":     {"details", "description", "transaction details", "remarks"},
+	"status":      {"transaction status", "status"},
+	"paid_in":     {"paid in", "credit", "amount in"},
+	"amount":      {"amount", "transaction amount"},
+	"direction":   {"transaction type", "type", "dr/cr"},
+	"party":       {"other party info", "other party", "sender", "sender details"},
+	"msisdn":      {"sender msisdn", "msisdn", "phone number", "from", "sender number", "mobile number"},
+	"name":        {"sender name", "customer name", "name"},
+	"account_ref": {"a/c no.", "a/c no", "account no", "account number", "reference", "bill reference"},
+}
+
+// mobileMoneyTimeLayouts lists the timestamp formats found in mobile money statement exports
+var mobileMoneyTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"02-01-2006 15:04:05",
+	"02/01/2006 15:04:05",
+	"02/01/2006 15:04",
+	"02-01-2006 15:04",
+	"2006-01-02T15:04:05",
+	"02-Jan-2006 15:04:05",
+	"02-Jan-2006 03:04:05 PM",
+}
+
+// partyPattern splits "254712345678 - JOHN DOE" into phone and name
+var partyPattern = regexp.MustCompile(`^\s*(\+?[\d*]{9,13})\s*-\s*(.+?)\s*$`)
+
+// detailsPhonePattern finds a phone number and name inside free-text details such as
+// "Pay Bill from 254712345678 - JOHN DOE Acc. TITHE"
+var detailsPhonePattern = regexp.MustCompile(`(\+?254\d{9}|0[17]\d{8})\s*-\s*([A-Za-z][A-Za-z .']*?)(?:\s+Acc\.|$)`)
+
+// detailsAccountPattern finds the account reference in M-Pesa details text
+var detailsAccountPattern = regexp.MustCompile(`(?i)\bAcc\.?\s*(\S+)`)
+
+// parseMobileMoneyStatement reads the incoming, completed payments from a mobile money
+// statement CSV. Withdrawals, charges and failed transactions are ignored.
+func parseMobileMoneyStatement(provider string, r io.Reader) ([]models.MobileMoneyLine, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.LazyQuotes = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, models.Invalid(fmt.Sprintf("invalid CSV: %v", err))
+	}
+
+	// Statement exports often start with a few summary rows; find the header row
+	headerRow := -1
+	columns := make(map[string]int)
+	for i, row := range rows {
+		columns = mapMobileMoneyColumns(row)
+		if _, hasCode := columns["code"]; hasCode {
+			if _, hasTime := columns["time"]; hasTime {
+				headerRow = i
+				break
+			}
+		}
+	}
+	if headerRow < 0 {
+		return nil, models.Invalid("statement header must include a transaction code and date column")
+	}
+	_, hasPaidIn := columns["paid_in"]
+	_, hasAmount := columns["amount"]
+	if !hasPaidIn && !hasAmount {
+		return nil, models.Invalid("statement header is missing a paid in or amount column")
+	}
+
+	field := func(row []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	lines := make([]models.MobileMoneyLine, 0)
+	for i, row := range rows[headerRow+1:] {
+		lineNo := headerRow + i + 2
+
+		code := strings.ToUpper(field(row, "code"))
+		if code == "" {
+			continue
+		}
+
+		// Only completed transactions
+		if status := strings.ToLower(field(row, "status")); status != "" && status != "completed" && status != "success" && status != "successful" {
+			continue
+		}
+
+		// Only money received
+		var amount float64
+		if hasPaidIn {
+			amount, err = parseStatementAmount(field(row, "paid_in"))
+		} else {
+			amount, err = parseStatementAmount(field(row, "amount"))
+			if direction := strings.ToLower(field(row, "direction")); strings.HasPrefix(direction, "d") || strings.Contains(direction, "debit") {
+				amount = -amount
+			}
+		}
+		if err != nil {
+			return nil, models.Invalid(fmt.Sprintf("line %d: %v", lineNo, err))
+		}
+		if amount <= 0 {
+			continue
+		}
+
+		transTime, err := parseMobileMoneyTime(field(row, "time"))
+		if err != nil {
+			return nil, models.Invalid(fmt.Sprintf("line %d: %v", lineNo, err))
+		}
+
+		details := field(row, "details")
+		msisdn, name := field(row, "msisdn"), field(row, "name")
+		if m := partyPattern.FindStringSubmatch(field(row, "party")); m != nil {
+			msisdn, name = firstNonEmpty(msisdn, m[1]), firstNonEmpty(name, m[2])
+		}
+		if m := detailsPhonePattern.FindStringSubmatch(details); m != nil {
+			msisdn, name = firstNonEmpty(msisdn, m[1]), firstNonEmpty(name, strings.TrimSpace(m[2]))
+		}
+
+		accountRef := field(row, "account_ref")
+		if accountRef == "" {
+			if m := detailsAccountPattern.FindStringSubmatch(details); m != nil {
+				accountRef = m[1]
+			}
+		}
+
+		lines = append(lines, models.MobileMoneyLine{
+			Provider:   provider,
+			TransCode:  code,
+			TransTime:  transTime,
+			Amount:     amount,
+			MSISDN:     optionalString(msisdn),
+			PayerName:  optionalString(name),
+			AccountRef: optionalString(accountRef),
+			Details:    optionalString(details),
+		})
+	}
+
+	if len(lines) == 0 {
+		return nil, models.Invalid("statement has no incoming payments")
+	}
+
+	return lines, nil
+}
+
+// mapMobileMoneyColumns maps the recognised columns of a candidate header row
+func mapMobileMoneyColumns(row []string) map[string]int {
+	columns := make(map[string]int)
+	for i, name := range row {
+		name = strings.ToLower(strings.TrimSpace(name))
+		for field, aliases := range mobileMoneyColumns {
+			if _, ok := columns[field]; ok {
+				continue
+			}
+			for _, alias := range aliases {
+				if name == alias {
+					columns[field] = i
+				}
+			}
+		}
+	}
+	return columns
+}
+
+// parseMobileMoneyTime parses a statement timestamp, read as East Africa Time
+func parseMobileMoneyTime(value string) (time.Time, error) {
+	eat := time.FixedZone("EAT", 3*60*60)
+	for _, layout := range mobileMoneyTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, eat); err == nil {
+			return t, nil
+		}
+	}
+	if date, err := parseStatementDate(value); err == nil {
+		return date, nil
+	}
+	return time.Time{}, models.Invalid(fmt.Sprintf("unrecognised date %q", value))
+}
+
+// firstNonEmpty returns the first of the values that is not blank
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,418 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"storeHouse/models"
+	"storeHouse/mpesa"
+	"storeHouse/repository"
+	"strings"
+	"time"
+)
+
+type MobileMoneyService struct {
+	Repo repository.Store
+}
+
+// Create a new instance of MobileMoneyService
+func NewMobileMoneyService(repo repository.Store) *MobileMoneyService {
+	return &MobileMoneyService{Repo: repo}
+}
+
+// ImportStatement parses an M-Pesa or Airtel Money statement CSV into the review queue.
+// Payments already posted (by callback or an earlier import) are flagged as duplicates, and
+// each line gets a suggested member matched by phone number.
+func (s *MobileMoneyService) ImportStatement(ctx context.Context, req models.ImportMobileMoneyRequest, file io.Reader, createdBy string) (*models.MobileMoneyImportResponse, error) {
+	req.Provider = strings.ToLower(strings.TrimSpace(req.Provider))
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getReceivingAccount(ctx, req.ReceivingAccountID); err != nil {
+		return nil, err
+	}
+	if req.IncomeAccountID != "" {
+		if _, err := s.getIncomeAccount(ctx, req.IncomeAccountID); err != nil {
+			return nil, err
+		}
+	}
+
+	parsed, err := parseMobileMoneyStatement(req.Provider, file)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare model for DB
+	statement := models.MobileMoneyImport{
+		Provider:           req.Provider,
+		FileName:           optionalString(req.FileName),
+		ReceivingAccountID: req.ReceivingAccountID,
+		IncomeAccountID:    optionalString(req.IncomeAccountID),
+		CreatedBy:          createdBy,
+	}
+
+	skipped := 0
+	seen := make(map[string]bool)
+	lines := make([]models.MobileMoneyLine, 0, len(parsed))
+	for _, line := range parsed {
+		// Lines already in the queue from an overlapping statement, or repeated in this one,
+		// are skipped
+		if _, err := s.Repo.GetMobileMoneyLineByCode(ctx, line.Provider, line.TransCode); err == nil || seen[line.TransCode] {
+			skipped++
+			continue
+		}
+		seen[line.TransCode] = true
+
+		line.Status = string(models.LinePending)
+		if txnID, ok := s.findPostedReceipt(ctx, line); ok {
+			line.Status = string(models.LineDuplicate)
+			line.TransactionID = &txnID
+		}
+
+		if member, ok := s.findMember(ctx, line.AccountRef, line.MSISDN); ok {
+			line.SuggestedMemberID = &member.ID
+			line.MemberID = &member.ID
+		}
+		line.IncomeAccountID = s.suggestIncomeAccount(ctx, line.AccountRef, statement.IncomeAccountID)
+
+		lines = append(lines, line)
+	}
+
+	// Save to DB; the statement and its lines are written in one database transaction
+	newImport, _, err := s.Repo.CreateMobileMoneyImport(ctx, statement, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.GetImport(ctx, newImport.ID)
+	if err != nil {
+		return nil, err
+	}
+	response.SkippedCount = skipped
+
+	return response, nil
+}
+
+// GetImport returns an import with its lines and review counts
+func (s *MobileMoneyService) GetImport(ctx context.Context, id string) (*models.MobileMoneyImportResponse, error) {
+	statement, err := s.Repo.GetMobileMoneyImport(ctx, id)
+	if err != nil {
+		return nil, models.ErrImportNotFound
+	}
+
+	lines, err := s.Repo.GetMobileMoneyLinesByImport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := statement.ToResponse()
+	response.Lines = make([]models.MobileMoneyLineResponse, 0, len(lines))
+	for _, l := range lines {
+		response.Lines = append(response.Lines, *l.ToResponse())
+		switch models.MobileMoneyLineStatus(l.Status) {
+		case models.LinePending:
+			response.PendingCount++
+		case models.LineDuplicate:
+			response.DuplicateCount++
+		case models.LinePosted:
+			response.PostedCount++
+		case models.LineRejected:
+			response.RejectedCount++
+		}
+	}
+	response.LineCount = len(lines)
+
+	return response, nil
+}
+
+// GetImports returns all mobile money imports, newest first
+func (s *MobileMoneyService) GetImports(ctx context.Context) ([]models.MobileMoneyImportResponse, error) {
+	imports, err := s.Repo.GetAllMobileMoneyImports(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response list
+	responses := make([]models.MobileMoneyImportResponse, 0, len(imports))
+	for _, imp := range imports {
+		responses = append(responses, *imp.ToResponse())
+	}
+
+	return responses, nil
+}
+
+// GetLines returns the lines in a review state, defaulting to the pending queue
+func (s *MobileMoneyService) GetLines(ctx context.Context, status string) ([]models.MobileMoneyLineResponse, error) {
+	if status == "" {
+		status = string(models.LinePending)
+	}
+
+	lines, err := s.Repo.GetMobileMoneyLinesByStatus(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response list
+	responses := make([]models.MobileMoneyLineResponse, 0, len(lines))
+	for _, l := range lines {
+		responses = append(responses, *l.ToResponse())
+	}
+
+	return responses, nil
+}
+
+// UpdateLine reassigns the member or income account of a pending line
+func (s *MobileMoneyService) UpdateLine(ctx context.Context, id string, req models.UpdateMobileMoneyLineRequest) (*models.MobileMoneyLineResponse, error) {
+	// Fetch existing record
+	line, err := s.getPendingLine(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply updates only if fields are provided
+	if err := s.assignLine(ctx, &line, req.MemberID, req.IncomeAccountID, req.Notes); err != nil {
+		return nil, err
+	}
+
+	// Persist update
+	updated, err := s.Repo.UpdateMobileMoneyLine(ctx, line)
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.ToResponse(), nil
+}
+
+// ConfirmLine posts a pending line as a receipt, applying any reassignment first
+func (s *MobileMoneyService) ConfirmLine(ctx context.Context, id string, req models.ReviewMobileMoneyLineRequest, reviewedBy string) (*models.MobileMoneyLineResponse, error) {
+	line, err := s.getPendingLine(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.assignLine(ctx, &line, req.MemberID, req.IncomeAccountID, req.Notes); err != nil {
+		return nil, err
+	}
+	if line.IncomeAccountID == nil {
+		return nil, models.Invalid("an income account is required to post the line")
+	}
+
+	statement, err := s.Repo.GetMobileMoneyImport(ctx, line.ImportID)
+	if err != nil {
+		return nil, models.ErrImportNotFound
+	}
+
+	// The payment may have been posted since the statement was imported
+	if txnID, ok := s.findPostedReceipt(ctx, line); ok {
+		line.Status = string(models.LineDuplicate)
+		line.TransactionID = &txnID
+		if _, err := s.Repo.UpdateMobileMoneyLine(ctx, line); err != nil {
+			return nil, err
+		}
+		return nil, models.Conflict("payment has already been posted")
+	}
+
+	if _, err := s.getReceivingAccount(ctx, statement.ReceivingAccountID); err != nil {
+		return nil, err
+	}
+	if _, err := s.getIncomeAccount(ctx, *line.IncomeAccountID); err != nil {
+		return nil, err
+	}
+	if line.Amount <= 0 {
+		return nil, models.Invalid("amount must be greater than zero")
+	}
+
+	now := time.Now()
+	line.ReviewedBy = &reviewedBy
+	line.ReviewedAt = &now
+
+	// The receipt and the line's posted status are written in one database transaction
+	txn, receipt := s.prepareReceipt(line, statement.ReceivingAccountID, reviewedBy)
+	posted, receipt, err := s.Repo.PostMobileMoneyLine(ctx, line, txn, receipt)
+	if err != nil {
+		return nil, err
+	}
+	s.acknowledge(ctx, receipt)
+
+	return posted.ToResponse(), nil
+}
+
+// RejectLine removes a pending line from the queue without posting it
+func (s *MobileMoneyService) RejectLine(ctx context.Context, id string, req models.ReviewMobileMoneyLineRequest, reviewedBy string) (*models.MobileMoneyLineResponse, error) {
+	line, err := s.getPendingLine(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	line.Status = string(models.LineRejected)
+	line.ReviewedBy = &reviewedBy
+	line.ReviewedAt = &now
+	if req.Notes != nil {
+		line.Notes = req.Notes
+	}
+
+	// Persist update
+	updated, err := s.Repo.UpdateMobileMoneyLine(ctx, line)
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.ToResponse(), nil
+}
+
+// prepareReceipt builds the receipts transaction into the receiving account and its single
+// receipt line to the chosen income account
+func (s *MobileMoneyService) prepareReceipt(line models.MobileMoneyLine, receivingAccountID, createdBy string) (models.Transaction, models.Receipt) {
+	provider := "M-Pesa"
+	if line.Provider == string(models.ProviderAirtel) {
+		provider = "Airtel Money"
+	}
+	notes := provider + " payment"
+	if line.PayerName != nil {
+		notes += " from " + *line.PayerName
+	}
+	if line.MSISDN != nil {
+		notes += fmt.Sprintf(" (%s)", *line.MSISDN)
+	}
+	if line.AccountRef != nil {
+		notes += ", account " + *line.AccountRef
+	}
+
+	transCode := line.TransCode
+	txn := models.Transaction{
+		TransactionRef:   &transCode,
+		TransactionDate:  line.TransTime,
+		TransactionType:  string(models.TransactionReceipts),
+		Amount:           line.Amount,
+		Notes:            &notes,
+		DebitAccountID:   receivingAccountID,
+		MemberID:         line.MemberID,
+		PaymentReference: &transCode,
+		CreatedBy:        createdBy,
+	}
+	if line.Provider == string(models.ProviderMpesa) {
+		paymentMethod := string(models.PaymentMpesa)
+		txn.PaymentMethod = &paymentMethod
+	}
+	receipt := models.Receipt{
+		IncomeAccountID: *line.IncomeAccountID,
+		Amount:          line.Amount,
+	}
+
+	return txn, receipt
+}
+
+// acknowledge applies a posted receipt to the member's open pledges and queues their SMS
+// acknowledgement, as a directly entered receipt would
+func (s *MobileMoneyService) acknowledge(ctx context.Context, receipt models.Receipt) {
+	if err := NewPledgeService(s.Repo).MatchReceipt(ctx, receipt); err != nil {
+		log.Printf("⚠️  Failed to match receipt %s to pledges: %v", receipt.ID, err)
+	}
+	if err := NewSMSService(s.Repo).QueueReceiptAcknowledgement(ctx, receipt.TransactionID); err != nil {
+		log.Printf("⚠️  Failed to queue SMS for receipt %s: %v", receipt.ID, err)
+	}
+}
+
+// assignLine applies a member, income account or notes change to a line
+func (s *MobileMoneyService) assignLine(ctx context.Context, line *models.MobileMoneyLine, memberID, incomeAccountID, notes *string) error {
+	if memberID != nil {
+		if *memberID == "" {
+			line.MemberID = nil
+		} else {
+			if _, err := s.Repo.GetMember(ctx, *memberID); err != nil {
+				return models.ErrMemberNotFound
+			}
+			line.MemberID = memberID
+		}
+	}
+	if incomeAccountID != nil {
+		if _, err := s.getIncomeAccount(ctx, *incomeAccountID); err != nil {
+			return err
+		}
+		line.IncomeAccountID = incomeAccountID
+	}
+	if notes != nil {
+		line.Notes = notes
+	}
+	return nil
+}
+
+// findPostedReceipt looks for a transaction already posted for the line's transaction code,
+// either by an M-Pesa callback or by confirming an earlier import
+func (s *MobileMoneyService) findPostedReceipt(ctx context.Context, line models.MobileMoneyLine) (string, bool) {
+	if txn, err := s.Repo.GetTransactionByRef(ctx, line.TransCode); err == nil {
+		return txn.ID, true
+	}
+	if line.Provider == string(models.ProviderMpesa) {
+		if payment, err := s.Repo.GetMpesaTransactionByTransID(ctx, line.TransCode); err == nil && payment.TransactionID != nil {
+			return *payment.TransactionID, true
+		}
+	}
+	return "", false
+}
+
+// findMember suggests a member for a payer: a phone number entered as the account reference
+// takes precedence over the paying number, as with Paybill callbacks
+func (s *MobileMoneyService) findMember(ctx context.Context, accountRef, msisdn *string) (models.Member, bool) {
+	phones := make([]string, 0)
+	if accountRef != nil {
+		phones = append(phones, mpesa.PhoneVariants(*accountRef)...)
+	}
+	if msisdn != nil {
+		phones = append(phones, mpesa.PhoneVariants(*msisdn)...)
+	}
+
+	for _, phone := range phones {
+		if member, err := s.Repo.GetMemberByPhone(ctx, phone); err == nil {
+			return member, true
+		}
+	}
+	return models.Member{}, false
+}
+
+// suggestIncomeAccount uses the Income account named by the account reference (e.g. TITHE),
+// falling back to the import's default income account
+func (s *MobileMoneyService) suggestIncomeAccount(ctx context.Context, accountRef, fallback *string) *string {
+	if accountRef != nil {
+		if account, err := s.Repo.GetAccountByName(ctx, strings.TrimSpace(*accountRef)); err == nil && account.AccountType == string(models.AccountIncome) {
+			return &account.ID
+		}
+	}
+	return fallback
+}
+
+func (s *MobileMoneyService) getPendingLine(ctx context.Context, id string) (models.MobileMoneyLine, error) {
+	line, err := s.Repo.GetMobileMoneyLine(ctx, id)
+	if err != nil {
+		return models.MobileMoneyLine{}, models.ErrImportLineNotFound
+	}
+	if line.Status != string(models.LinePending) {
+		return models.MobileMoneyLine{}, models.Conflict(fmt.Sprintf("mobile money line is already %s", line.Status))
+	}
+	return line, nil
+}
+
+func (s *MobileMoneyService) getReceivingAccount(ctx context.Context, accountID string) (models.Account, error) {
+	account, err := getPostingAccount(ctx, s.Repo, accountID, "receiving account")
+	if err != nil {
+		return models.Account{}, err
+	}
+	if account.AccountType != string(models.AccountBank) {
+		return models.Account{}, models.Invalid("receiving account must be a Bank account")
+	}
+	return account, nil
+}
+
+func (s *MobileMoneyService) getIncomeAccount(ctx context.Context, accountID string) (models.Account, error) {
+	account, err := getPostingAccount(ctx, s.Repo, accountID, "income account")
+	if err != nil {
+		return models.Account{}, err
+	}
+	if account.AccountType != string(models.AccountIncome) {
+		return models.Account{}, models.Invalid("income account must be an Income account")
+	}
+	return account, nil
+}
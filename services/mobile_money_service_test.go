@@ -0,0 +1,58 @@
+package services
+
+import (
+	"storeHouse/models"
+	"strings"
+	"testing"
+)
+
+const statement = `Receipt No.,Completion Time,Details,Transaction Status,Paid In,Other Party Info,A/C No.
+QA11,2025-03-01 09:00:00,Pay Bill from 0712345678 - JANE WANJIRU,Completed,1000,0712345678 - JANE WANJIRU,TITHE
+QA12,2025-03-01 10:00:00,Pay Bill from 0722345678 - JOHN OTIENO,Completed,500,0722345678 - JOHN OTIENO,TITHE
+QA11,2025-03-01 09:00:00,Pay Bill from 0712345678 - JANE WANJIRU,Completed,1000,0712345678 - JANE WANJIRU,TITHE
+`
+
+func TestConfirmedStatementLinePostsReceipt(t *testing.T) {
+	f := newFixture(t)
+	bank := f.account("M-Pesa", models.AccountBank)
+	income := f.account("Tithe", models.AccountIncome)
+	member := f.member("Jane Wanjiru", "0712345678")
+	mobileMoney := NewMobileMoneyService(f.repo)
+
+	imported, err := mobileMoney.ImportStatement(f.ctx, models.ImportMobileMoneyRequest{
+		Provider:           string(models.ProviderMpesa),
+		ReceivingAccountID: bank,
+		IncomeAccountID:    income,
+	}, strings.NewReader(statement), "clerk")
+	if err != nil {
+		t.Fatalf("import statement: %v", err)
+	}
+	if imported.LineCount != 2 || imported.SkippedCount != 1 {
+		t.Fatalf("got %d lines and %d skipped, want 2 and the repeated code skipped", imported.LineCount, imported.SkippedCount)
+	}
+
+	var line models.MobileMoneyLineResponse
+	for _, l := range imported.Lines {
+		if l.TransCode == "QA11" {
+			line = l
+		}
+	}
+	if line.MemberID == nil || *line.MemberID != member {
+		t.Fatalf("got member %v, want the payer matched by phone", line.MemberID)
+	}
+
+	posted, err := mobileMoney.ConfirmLine(f.ctx, line.ID, models.ReviewMobileMoneyLineRequest{}, "treasurer")
+	if err != nil {
+		t.Fatalf("confirm line: %v", err)
+	}
+	if posted.Status != string(models.LinePosted) || posted.TransactionID == nil {
+		t.Fatalf("got status %s, want posted against a transaction", posted.Status)
+	}
+	if total := f.receiptsTotal(income); total != 1000 {
+		t.Fatalf("got receipts of %.2f, want 1000", total)
+	}
+
+	// The payment cannot be posted twice
+	_, err = mobileMoney.ConfirmLine(f.ctx, line.ID, models.ReviewMobileMoneyLineRequest{}, "treasurer")
+	wantConflict(t, err)
+}
@@ -0,0 +1,319 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"storeHouse/models"
+	"storeHouse/mpesa"
+	"storeHouse/repository"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type MpesaService struct {
+	Repo   repository.Store
+	Config mpesa.Config
+}
+
+// Create a new instance of MpesaService
+func NewMpesaService(repo repository.Store, cfg mpesa.Config) *MpesaService {
+	return &MpesaService{Repo: repo, Config: cfg}
+}
+
+// ValidatePayment decides whether Daraja should accept a C2B payment
+func (s *MpesaService) ValidatePayment(ctx context.Context, callback mpesa.C2BCallback) mpesa.C2BResponse {
+	if s.Config.ShortCode != "" && callback.BusinessShortCode != s.Config.ShortCode {
+		return mpesa.Rejected(mpesa.ResultInvalidShortCode, "Rejected")
+	}
+
+	if amount, err := strconv.ParseFloat(callback.TransAmount, 64); err != nil || amount <= 0 {
+		return mpesa.Rejected(mpesa.ResultInvalidAmount, "Rejected")
+	}
+
+	// Without somewhere to post the payment we cannot accept it
+	if _, err := s.resolveIncomeAccount(ctx, callback.BillRefNumber); err != nil {
+		return mpesa.Rejected(mpesa.ResultInvalidAccount, "Rejected")
+	}
+
+	return mpesa.Accepted()
+}
+
+// ConfirmPayment records a confirmed C2B payment and posts it as a receipt. Repeated
+// callbacks for the same M-Pesa transaction return the existing record.
+func (s *MpesaService) ConfirmPayment(ctx context.Context, callback mpesa.C2BCallback, createdBy string) (*models.MpesaTransactionResponse, error) {
+	if callback.TransID == "" {
+		return nil, models.Invalid("TransID is required")
+	}
+
+	// Daraja retries callbacks it believes failed; never post the same payment twice
+	if existing, err := s.Repo.GetMpesaTransactionByTransID(ctx, callback.TransID); err == nil {
+		return existing.ToResponse(), nil
+	}
+
+	amount, err := strconv.ParseFloat(callback.TransAmount, 64)
+	if err != nil || amount <= 0 {
+		return nil, models.Invalid("invalid TransAmount")
+	}
+
+	transTime, err := mpesa.ParseTransTime(callback.TransTime)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(callback)
+	if err != nil {
+		return nil, err
+	}
+	payloadStr := string(payload)
+
+	// Prepare model for DB
+	payment := models.MpesaTransaction{
+		TransID:           callback.TransID,
+		TransType:         optionalString(callback.TransactionType),
+		TransTime:         transTime,
+		Amount:            amount,
+		BusinessShortCode: optionalString(callback.BusinessShortCode),
+		BillRefNumber:     optionalString(strings.TrimSpace(callback.BillRefNumber)),
+		MSISDN:            optionalString(callback.MSISDN),
+		PayerName:         optionalString(callback.PayerName()),
+		Status:            string(models.MpesaReceived),
+		Payload:           &payloadStr,
+	}
+
+	// Save to DB before posting so the payment is never lost
+	payment, err = s.Repo.CreateMpesaTransaction(ctx, payment)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.postPayment(ctx, payment, createdBy)
+}
+
+// RetryPayment re-attempts posting a payment that previously failed
+func (s *MpesaService) RetryPayment(ctx context.Context, id, createdBy string) (*models.MpesaTransactionResponse, error) {
+	payment, err := s.Repo.GetMpesaTransaction(ctx, id)
+	if err != nil {
+		return nil, models.ErrMpesaNotFound
+	}
+	if payment.Status == string(models.MpesaPosted) {
+		return nil, models.Conflict("mpesa transaction is already posted")
+	}
+
+	return s.postPayment(ctx, payment, createdBy)
+}
+
+// CallbackUser returns the church's M-Pesa user, who records the payments Paybill callbacks
+// post. It is created on first use, on the church's connection so it belongs to the church,
+// and is inactive so nobody can sign in as it.
+func (s *MpesaService) CallbackUser(ctx context.Context, churchCode string) (string, error) {
+	username := "mpesa-" + strings.ToLower(churchCode)
+	if user, err := s.Repo.GetUserByUsername(ctx, username); err == nil {
+		return user.ID, nil
+	}
+
+	user := models.User{
+		Username:     username,
+		Email:        username + "@storehouse.invalid",
+		PasswordHash: uuid.New().String(),
+		FullName:     "M-Pesa Paybill",
+		Role:         string(models.RoleClerk),
+		IsActive:     false,
+	}
+	created, err := s.Repo.CreateUser(ctx, user)
+	if err != nil {
+		// Another callback may have created it first
+		if existing, getErr := s.Repo.GetUserByUsername(ctx, username); getErr == nil {
+			return existing.ID, nil
+		}
+		return "", err
+	}
+
+	return created.ID, nil
+}
+
+// GetMpesaTransaction returns a single M-Pesa payment
+func (s *MpesaService) GetMpesaTransaction(ctx context.Context, id string) (*models.MpesaTransactionResponse, error) {
+	payment, err := s.Repo.GetMpesaTransaction(ctx, id)
+	if err != nil {
+		return nil, models.ErrMpesaNotFound
+	}
+	return payment.ToResponse(), nil
+}
+
+// GetMpesaTransactions returns M-Pesa payments, optionally filtered by status
+func (s *MpesaService) GetMpesaTransactions(ctx context.Context, status string) ([]models.MpesaTransactionResponse, error) {
+	var payments []models.MpesaTransaction
+	var err error
+	if status != "" {
+		payments, err = s.Repo.GetMpesaTransactionsByStatus(ctx, status)
+	} else {
+		payments, err = s.Repo.GetAllMpesaTransactions(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response list
+	responses := make([]models.MpesaTransactionResponse, 0, len(payments))
+	for _, p := range payments {
+		responses = append(responses, *p.ToResponse())
+	}
+
+	return responses, nil
+}
+
+// postPayment posts the payment as a receipt, recording the outcome on it. The receipt and
+// the payment's posted status are written in one database transaction.
+func (s *MpesaService) postPayment(ctx context.Context, payment models.MpesaTransaction, createdBy string) (*models.MpesaTransactionResponse, error) {
+	txn, receipt, err := s.prepareReceipt(ctx, &payment, createdBy)
+	if err == nil {
+		var posted models.MpesaTransaction
+		if posted, receipt, err = s.Repo.PostMpesaPayment(ctx, payment, txn, receipt); err == nil {
+			s.acknowledge(ctx, receipt)
+			return posted.ToResponse(), nil
+		}
+	}
+
+	log.Printf("⚠️  Failed to post M-Pesa payment %s: %v", payment.TransID, err)
+	message := err.Error()
+	payment.Status = string(models.MpesaFailed)
+	payment.Error = &message
+
+	// Persist update
+	updated, err := s.Repo.UpdateMpesaTransaction(ctx, payment)
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.ToResponse(), nil
+}
+
+// prepareReceipt builds the receipts transaction into the receiving account and its single
+// receipt line to the income account, checking them as a directly entered receipt would
+func (s *MpesaService) prepareReceipt(ctx context.Context, payment *models.MpesaTransaction, createdBy string) (models.Transaction, models.Receipt, error) {
+	billRef := ""
+	if payment.BillRefNumber != nil {
+		billRef = *payment.BillRefNumber
+	}
+
+	receivingAccount, err := s.resolveAccount(ctx, s.Config.ReceivingAccount)
+	if err != nil {
+		return models.Transaction{}, models.Receipt{}, models.Unavailable("M-Pesa receiving account is not configured")
+	}
+	if _, err := getPostingAccount(ctx, s.Repo, receivingAccount.ID, "debit account"); err != nil {
+		return models.Transaction{}, models.Receipt{}, err
+	}
+	incomeAccount, err := s.resolveIncomeAccount(ctx, billRef)
+	if err != nil {
+		return models.Transaction{}, models.Receipt{}, err
+	}
+	if _, err := getPostingAccount(ctx, s.Repo, incomeAccount.ID, "income account"); err != nil {
+		return models.Transaction{}, models.Receipt{}, err
+	}
+	payment.IncomeAccountID = &incomeAccount.ID
+
+	if payment.Amount <= 0 {
+		return models.Transaction{}, models.Receipt{}, models.Invalid("amount must be greater than zero")
+	}
+
+	// The M-Pesa code is the transaction reference, so a payment is only ever posted once
+	if _, err := s.Repo.GetTransactionByRef(ctx, payment.TransID); err == nil {
+		return models.Transaction{}, models.Receipt{}, models.Conflict("transaction reference already exists")
+	}
+
+	// Match the payer to a member; anonymous receipts are still posted
+	payment.MemberID = nil
+	if member, ok := s.findMember(ctx, billRef, payment.MSISDN); ok {
+		payment.MemberID = &member.ID
+	}
+
+	notes := "M-Pesa payment"
+	if payment.PayerName != nil {
+		notes += " from " + *payment.PayerName
+	}
+	if payment.MSISDN != nil {
+		notes += fmt.Sprintf(" (%s)", *payment.MSISDN)
+	}
+	if billRef != "" {
+		notes += ", account " + billRef
+	}
+
+	transID := payment.TransID
+	paymentMethod := string(models.PaymentMpesa)
+	txn := models.Transaction{
+		TransactionRef:   &transID,
+		TransactionDate:  payment.TransTime,
+		TransactionType:  string(models.TransactionReceipts),
+		Amount:           payment.Amount,
+		Notes:            &notes,
+		DebitAccountID:   receivingAccount.ID,
+		MemberID:         payment.MemberID,
+		PaymentMethod:    &paymentMethod,
+		PaymentReference: &transID,
+		CreatedBy:        createdBy,
+	}
+	receipt := models.Receipt{
+		IncomeAccountID: incomeAccount.ID,
+		Amount:          payment.Amount,
+	}
+
+	return txn, receipt, nil
+}
+
+// acknowledge applies a posted receipt to the member's open pledges and queues their SMS
+// acknowledgement, as a directly entered receipt would
+func (s *MpesaService) acknowledge(ctx context.Context, receipt models.Receipt) {
+	if err := NewPledgeService(s.Repo).MatchReceipt(ctx, receipt); err != nil {
+		log.Printf("⚠️  Failed to match receipt %s to pledges: %v", receipt.ID, err)
+	}
+	if err := NewSMSService(s.Repo).QueueReceiptAcknowledgement(ctx, receipt.TransactionID); err != nil {
+		log.Printf("⚠️  Failed to queue SMS for receipt %s: %v", receipt.ID, err)
+	}
+}
+
+// resolveIncomeAccount uses the Income account named by the account reference (e.g. TITHE),
+// falling back to the configured default income account
+func (s *MpesaService) resolveIncomeAccount(ctx context.Context, billRef string) (models.Account, error) {
+	if billRef = strings.TrimSpace(billRef); billRef != "" {
+		if account, err := s.Repo.GetAccountByName(ctx, billRef); err == nil && account.AccountType == string(models.AccountIncome) {
+			return account, nil
+		}
+	}
+
+	account, err := s.resolveAccount(ctx, s.Config.IncomeAccount)
+	if err != nil || account.AccountType != string(models.AccountIncome) {
+		return models.Account{}, models.Unavailable("M-Pesa income account is not configured")
+	}
+	return account, nil
+}
+
+// resolveAccount looks up an account by ID, falling back to its name
+func (s *MpesaService) resolveAccount(ctx context.Context, ref string) (models.Account, error) {
+	if ref == "" {
+		return models.Account{}, models.ErrAccountNotFound
+	}
+	if account, err := s.Repo.GetAccount(ctx, ref); err == nil {
+		return account, nil
+	}
+	return s.Repo.GetAccountByName(ctx, ref)
+}
+
+// findMember matches a payer to a member: a phone number entered as the account reference
+// (paying on behalf of a member) takes precedence over the paying MSISDN
+func (s *MpesaService) findMember(ctx context.Context, billRef string, msisdn *string) (models.Member, bool) {
+	phones := mpesa.PhoneVariants(billRef)
+	if msisdn != nil {
+		phones = append(phones, mpesa.PhoneVariants(*msisdn)...)
+	}
+
+	for _, phone := range phones {
+		if member, err := s.Repo.GetMemberByPhone(ctx, phone); err == nil {
+			return member, true
+		}
+	}
+	return models.Member{}, false
+}
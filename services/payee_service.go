@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"net/mail"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"strings"
+	"time"
+)
+
+type PayeeService struct {
+	Repo repository.Store
+}
+
+// Create a new instance of PayeeService
+func NewPayeeService(repo repository.Store) *PayeeService {
+	return &PayeeService{Repo: repo}
+}
+
+// CreatePayee handles business logic for creating a new payee
+func (s *PayeeService) CreatePayee(ctx context.Context, req models.CreatePayeeRequest, createdBy *string) (*models.PayeeResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Prepare model for DB
+	payee := models.Payee{
+		Name:              strings.TrimSpace(req.Name),
+		PhoneNumber:       trimToNil(req.PhoneNumber),
+		Email:             trimToNil(req.Email),
+		KRAPin:            trimToNil(req.KRAPin),
+		BankName:          trimToNil(req.BankName),
+		BankBranch:        trimToNil(req.BankBranch),
+		BankAccountNumber: trimToNil(req.BankAccountNumber),
+		MpesaPhone:        trimToNil(req.MpesaPhone),
+		MpesaPaybill:      trimToNil(req.MpesaPaybill),
+		MpesaAccount:      trimToNil(req.MpesaAccount),
+		MpesaTill:         trimToNil(req.MpesaTill),
+		Notes:             trimToNil(req.Notes),
+		IsActive:          true,
+		CreatedBy:         createdBy,
+	}
+
+	if err := s.validate(ctx, &payee); err != nil {
+		return nil, err
+	}
+
+	// Save to DB
+	newPayee, err := s.Repo.CreatePayee(ctx, payee)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPayee.ToResponse(), nil
+}
+
+// UpdatePayee handles update logic
+func (s *PayeeService) UpdatePayee(ctx context.Context, id string, req models.UpdatePayeeRequest) (*models.PayeeResponse, error) {
+	// Fetch existing record
+	existing, err := s.Repo.GetPayee(ctx, id)
+	if err != nil {
+		return nil, models.ErrPayeeNotFound
+	}
+
+	// Apply updates only if fields are provided
+	if req.Name != nil {
+		if strings.TrimSpace(*req.Name) == "" {
+			return nil, models.Invalid("name is required")
+		}
+		existing.Name = strings.TrimSpace(*req.Name)
+	}
+	for _, field := range []struct {
+		value  *string
+		target **string
+	}{
+		{req.PhoneNumber, &existing.PhoneNumber},
+		{req.Email, &existing.Email},
+		{req.KRAPin, &existing.KRAPin},
+		{req.BankName, &existing.BankName},
+		{req.BankBranch, &existing.BankBranch},
+		{req.BankAccountNumber, &existing.BankAccountNumber},
+		{req.MpesaPhone, &existing.MpesaPhone},
+		{req.MpesaPaybill, &existing.MpesaPaybill},
+		{req.MpesaAccount, &existing.MpesaAccount},
+		{req.MpesaTill, &existing.MpesaTill},
+		{req.Notes, &existing.Notes},
+	} {
+		if field.value != nil {
+			*field.target = trimToNil(field.value)
+		}
+	}
+	if req.IsActive != nil {
+		existing.IsActive = *req.IsActive
+	}
+
+	if err := s.validate(ctx, &existing); err != nil {
+		return nil, err
+	}
+
+	// Persist update
+	updated, err := s.Repo.UpdatePayee(ctx, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.ToResponse(), nil
+}
+
+// GetPayee returns a payee by ID
+func (s *PayeeService) GetPayee(ctx context.Context, id string) (*models.PayeeResponse, error) {
+	payee, err := s.Repo.GetPayee(ctx, id)
+	if err != nil {
+		return nil, models.ErrPayeeNotFound
+	}
+
+	return payee.ToResponse(), nil
+}
+
+// GetAllPayees returns every payee
+func (s *PayeeService) GetAllPayees(ctx context.Context) ([]models.PayeeResponse, error) {
+	payees, err := s.Repo.GetAllPayees(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPayeeResponses(payees), nil
+}
+
+// SearchPayees finds payees by name, phone number, M-Pesa number, KRA PIN or email
+func (s *PayeeService) SearchPayees(ctx context.Context, searchTerm string) ([]models.PayeeResponse, error) {
+	payees, err := s.Repo.SearchPayees(ctx, strings.TrimSpace(searchTerm))
+	if err != nil {
+		return nil, err
+	}
+
+	return toPayeeResponses(payees), nil
+}
+
+// DeletePayee removes a payee that has never been paid; paid payees are deactivated instead
+func (s *PayeeService) DeletePayee(ctx context.Context, id string) error {
+	// Ensure exists before deleting
+	if _, err := s.Repo.GetPayee(ctx, id); err != nil {
+		return models.ErrPayeeNotFound
+	}
+
+	count, err := s.Repo.CountExpendituresByPayee(ctx, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return models.Conflict("only payees without expenditures can be deleted; set is_active to false instead")
+	}
+
+	return s.Repo.DeletePayee(ctx, id)
+}
+
+// GetSpendSummary totals the posted expenditures paid to each payee over the period
+func (s *PayeeService) GetSpendSummary(ctx context.Context, startDate, endDate time.Time) (*models.PayeeSpendSummary, error) {
+	if endDate.Before(startDate) {
+		return nil, models.Invalid("end_date must not be before start_date")
+	}
+
+	spend, err := s.Repo.GetPayeeSpend(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.PayeeSpendSummary{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Payees:    spend,
+	}
+	if summary.Payees == nil {
+		summary.Payees = []models.PayeeSpend{}
+	}
+	for _, p := range spend {
+		summary.Total += p.Total
+	}
+	summary.Total = roundAmount(summary.Total)
+
+	return summary, nil
+}
+
+// GetSpendReport lists what was paid to a payee over the period, by expense account and line
+func (s *PayeeService) GetSpendReport(ctx context.Context, id string, startDate, endDate time.Time) (*models.PayeeSpendReport, error) {
+	if endDate.Before(startDate) {
+		return nil, models.Invalid("end_date must not be before start_date")
+	}
+
+	payee, err := s.Repo.GetPayee(ctx, id)
+	if err != nil {
+		return nil, models.ErrPayeeNotFound
+	}
+
+	byAccount, err := s.Repo.GetPayeeSpendByAccount(ctx, id, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := s.Repo.GetPayeePayments(ctx, id, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.PayeeSpendReport{
+		Payee:     payee.ToResponse(),
+		StartDate: startDate,
+		EndDate:   endDate,
+		Payments:  len(lines),
+		ByAccount: byAccount,
+		Lines:     lines,
+	}
+	if report.ByAccount == nil {
+		report.ByAccount = []models.PayeeAccountSpend{}
+	}
+	if report.Lines == nil {
+		report.Lines = []models.PayeePayment{}
+	}
+	for _, line := range lines {
+		report.Total += line.Amount
+	}
+	report.Total = roundAmount(report.Total)
+
+	return report, nil
+}
+
+// validate normalises the KRA PIN and checks the contact details and that the PIN is not
+// already registered to another payee
+func (s *PayeeService) validate(ctx context.Context, payee *models.Payee) error {
+	if payee.KRAPin != nil {
+		pin, err := models.NormalizeKRAPin(*payee.KRAPin)
+		if err != nil {
+			return err
+		}
+		payee.KRAPin = &pin
+
+		if other, err := s.Repo.GetPayeeByKRAPin(ctx, pin); err == nil && other.ID != payee.ID {
+			return models.Conflict("kra_pin is already registered to " + other.Name)
+		}
+	}
+
+	for name, phone := range map[string]*string{"phone_number": payee.PhoneNumber, "mpesa_phone": payee.MpesaPhone} {
+		if phone != nil && !isValidPhoneNumber(*phone) {
+			return models.Invalid("invalid " + name + " format")
+		}
+	}
+
+	if payee.Email != nil {
+		if _, err := mail.ParseAddress(*payee.Email); err != nil {
+			return models.ErrInvalidEmail
+		}
+	}
+
+	if payee.MpesaAccount != nil && payee.MpesaPaybill == nil {
+		return models.Invalid("mpesa_account needs an mpesa_paybill")
+	}
+
+	return nil
+}
+
+// trimToNil trims an optional string, treating blank as not set
+func trimToNil(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	return emptyToNil(&trimmed)
+}
+
+func toPayeeResponses(payees []models.Payee) []models.PayeeResponse {
+	responses := make([]models.PayeeResponse, 0, len(payees))
+	for _, p := range payees {
+		responses = append(responses, *p.ToResponse())
+	}
+	return responses
+}
@@ -0,0 +1,355 @@
+package services
+
+import (
+	"context"
+	"math"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"time"
+)
+
+type PledgeService struct {
+	Repo repository.Store
+}
+
+// Create a new instance of PledgeService
+func NewPledgeService(repo repository.Store) *PledgeService {
+	return &PledgeService{Repo: repo}
+}
+
+// CreatePledge handles pledge creation business logic
+func (s *PledgeService) CreatePledge(ctx context.Context, req models.CreatePledgeRequest, createdBy string) (*models.PledgeResponse, error) {
+	// Check if campaign exists and is still running
+	campaign, err := s.Repo.GetCampaign(ctx, req.CampaignID)
+	if err != nil {
+		return nil, models.ErrCampaignNotFound
+	}
+	if !campaign.IsActive {
+		return nil, models.Invalid("campaign is closed")
+	}
+
+	// Check if member exists
+	if _, err := s.Repo.GetMember(ctx, req.MemberID); err != nil {
+		return nil, models.ErrMemberNotFound
+	}
+
+	// Default to a single payment
+	frequency := req.Frequency
+	if frequency == "" {
+		frequency = string(models.PledgeOnce)
+	}
+	installments := req.Installments
+	if installments == 0 || frequency == string(models.PledgeOnce) {
+		installments = 1
+	}
+
+	// Prepare model for DB
+	pledge := models.Pledge{
+		CampaignID:   req.CampaignID,
+		MemberID:     req.MemberID,
+		Amount:       req.Amount,
+		Frequency:    frequency,
+		Installments: installments,
+		StartDate:    campaign.StartDate,
+		Status:       string(models.PledgeOpen),
+		Notes:        req.Notes,
+		CreatedBy:    createdBy,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	if req.StartDate != nil && !req.StartDate.IsZero() {
+		pledge.StartDate = *req.StartDate
+	}
+
+	// Save to DB
+	newPledge, err := s.Repo.CreatePledge(ctx, pledge)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPledge.ToResponse(), nil
+}
+
+// UpdatePledge handles update logic
+func (s *PledgeService) UpdatePledge(ctx context.Context, id string, req models.UpdatePledgeRequest) (*models.PledgeResponse, error) {
+	// Fetch existing record
+	existing, err := s.Repo.GetPledge(ctx, id)
+	if err != nil {
+		return nil, models.ErrPledgeNotFound
+	}
+
+	// Apply updates only if fields are provided
+	if req.Amount != nil {
+		if *req.Amount <= 0 {
+			return nil, models.Invalid("amount must be greater than zero")
+		}
+		existing.Amount = *req.Amount
+	}
+	if req.Frequency != nil {
+		existing.Frequency = *req.Frequency
+		if err := existing.ValidateFrequency(); err != nil {
+			return nil, err
+		}
+	}
+	if req.Installments != nil {
+		if *req.Installments <= 0 {
+			return nil, models.Invalid("installments must be greater than zero")
+		}
+		existing.Installments = *req.Installments
+	}
+	if req.StartDate != nil && !req.StartDate.IsZero() {
+		existing.StartDate = *req.StartDate
+	}
+	if req.Notes != nil {
+		existing.Notes = req.Notes
+	}
+
+	// Only cancellation and re-opening can be requested; fulfilment is derived from payments
+	if req.Status != nil {
+		switch models.PledgeStatus(*req.Status) {
+		case models.PledgeCancelled:
+			existing.Status = string(models.PledgeCancelled)
+		case models.PledgeOpen:
+			existing.Status = string(models.PledgeOpen)
+			existing.Status = pledgeStatusFor(existing)
+		default:
+			return nil, models.Invalid("pledge status can only be set to open or cancelled")
+		}
+	} else if existing.Status != string(models.PledgeCancelled) {
+		existing.Status = pledgeStatusFor(existing)
+	}
+
+	existing.UpdatedAt = time.Now()
+
+	// Persist update
+	updated, err := s.Repo.UpdatePledge(ctx, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.ToResponse(), nil
+}
+
+// DeletePledge removes a pledge and its payment allocations
+func (s *PledgeService) DeletePledge(ctx context.Context, id string) error {
+	// Ensure exists before deleting
+	if _, err := s.Repo.GetPledge(ctx, id); err != nil {
+		return models.ErrPledgeNotFound
+	}
+
+	return s.Repo.DeletePledge(ctx, id)
+}
+
+// GetPledge returns single pledge details
+func (s *PledgeService) GetPledge(ctx context.Context, id string) (*models.PledgeResponse, error) {
+	pledge, err := s.Repo.GetPledge(ctx, id)
+	if err != nil {
+		return nil, models.ErrPledgeNotFound
+	}
+	return pledge.ToResponse(), nil
+}
+
+// GetPledgesByCampaign returns pledges made towards a campaign
+func (s *PledgeService) GetPledgesByCampaign(ctx context.Context, campaignID string) ([]models.PledgeResponse, error) {
+	pledges, err := s.Repo.GetPledgesByCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response list
+	responses := make([]models.PledgeResponse, 0, len(pledges))
+	for _, p := range pledges {
+		responses = append(responses, *p.ToResponse())
+	}
+
+	return responses, nil
+}
+
+// GetPledgesByMember returns pledges made by a member
+func (s *PledgeService) GetPledgesByMember(ctx context.Context, memberID string) ([]models.PledgeResponse, error) {
+	pledges, err := s.Repo.GetPledgesByMember(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response list
+	responses := make([]models.PledgeResponse, 0, len(pledges))
+	for _, p := range pledges {
+		responses = append(responses, *p.ToResponse())
+	}
+
+	return responses, nil
+}
+
+// GetPledgePayments returns the receipts applied to a pledge
+func (s *PledgeService) GetPledgePayments(ctx context.Context, pledgeID string) ([]models.PledgePayment, error) {
+	if _, err := s.Repo.GetPledge(ctx, pledgeID); err != nil {
+		return nil, models.ErrPledgeNotFound
+	}
+	return s.Repo.GetPledgePaymentsByPledge(ctx, pledgeID)
+}
+
+// MatchReceipt applies a receipt to the member's open pledges on campaigns
+// collecting into the receipt's income account, oldest pledge first. Receipts count
+// towards a pledge once their transaction is posted.
+func (s *PledgeService) MatchReceipt(ctx context.Context, receipt models.Receipt) error {
+	txn, err := s.Repo.GetTransaction(ctx, receipt.TransactionID)
+	if err != nil {
+		return models.ErrTransactionNotFound
+	}
+	if txn.Status != string(models.TransactionPosted) {
+		return nil
+	}
+
+	// Anonymous receipts cannot be matched to a pledge
+	if txn.MemberID == nil || *txn.MemberID == "" {
+		return nil
+	}
+
+	pledges, err := s.Repo.GetOpenPledgesForReceipt(ctx, *txn.MemberID, receipt.IncomeAccountID)
+	if err != nil {
+		return err
+	}
+
+	remaining := receipt.Amount
+	for _, pledge := range pledges {
+		if remaining <= 0 {
+			break
+		}
+
+		allocation := math.Min(remaining, pledge.Balance())
+		if allocation <= 0 {
+			continue
+		}
+
+		payment := models.PledgePayment{
+			PledgeID:  pledge.ID,
+			ReceiptID: receipt.ID,
+			Amount:    allocation,
+		}
+		if _, err := s.Repo.CreatePledgePayment(ctx, payment); err != nil {
+			return err
+		}
+
+		pledge.AmountPaid += allocation
+		remaining -= allocation
+
+		if status := pledgeStatusFor(pledge); status != pledge.Status {
+			if err := s.Repo.UpdatePledgeStatus(ctx, pledge.ID, status); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// UnmatchReceipt removes a receipt's pledge allocations and re-opens any
+// pledges that are no longer fully paid
+func (s *PledgeService) UnmatchReceipt(ctx context.Context, receiptID string) error {
+	payments, err := s.Repo.GetPledgePaymentsByReceipt(ctx, receiptID)
+	if err != nil {
+		return err
+	}
+	if len(payments) == 0 {
+		return nil
+	}
+
+	if err := s.Repo.DeletePledgePaymentsByReceipt(ctx, receiptID); err != nil {
+		return err
+	}
+
+	for _, payment := range payments {
+		pledge, err := s.Repo.GetPledge(ctx, payment.PledgeID)
+		if err != nil {
+			continue
+		}
+		if status := pledgeStatusFor(pledge); status != pledge.Status {
+			if err := s.Repo.UpdatePledgeStatus(ctx, pledge.ID, status); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetFulfilmentByMember reports pledged vs paid amounts per member for a campaign
+func (s *PledgeService) GetFulfilmentByMember(ctx context.Context, campaignID string, asOf time.Time) ([]models.PledgeFulfilment, error) {
+	details, err := s.getPledgeDetails(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	return summarisePledges(details, asOf, func(d models.PledgeDetail) (string, string) {
+		return d.MemberID, d.MemberName
+	}), nil
+}
+
+// GetFulfilmentByGroup reports pledged vs paid amounts per members group for a campaign
+func (s *PledgeService) GetFulfilmentByGroup(ctx context.Context, campaignID string, asOf time.Time) ([]models.PledgeFulfilment, error) {
+	details, err := s.getPledgeDetails(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	return summarisePledges(details, asOf, func(d models.PledgeDetail) (string, string) {
+		if d.GroupID == nil || d.GroupName == nil {
+			return "", "Ungrouped"
+		}
+		return *d.GroupID, *d.GroupName
+	}), nil
+}
+
+func (s *PledgeService) getPledgeDetails(ctx context.Context, campaignID string) ([]models.PledgeDetail, error) {
+	if _, err := s.Repo.GetCampaign(ctx, campaignID); err != nil {
+		return nil, models.ErrCampaignNotFound
+	}
+	return s.Repo.GetPledgeDetailsByCampaign(ctx, campaignID)
+}
+
+// summarisePledges aggregates pledge details by the key returned from keyFn,
+// preserving the order in which keys are first seen
+func summarisePledges(details []models.PledgeDetail, asOf time.Time, keyFn func(models.PledgeDetail) (string, string)) []models.PledgeFulfilment {
+	index := make(map[string]int)
+	results := make([]models.PledgeFulfilment, 0)
+
+	for _, d := range details {
+		id, name := keyFn(d)
+		i, ok := index[id]
+		if !ok {
+			i = len(results)
+			index[id] = i
+			results = append(results, models.PledgeFulfilment{ID: id, Name: name})
+		}
+
+		row := &results[i]
+		row.Pledges++
+		row.Pledged += d.Amount
+		row.Paid += d.AmountPaid
+		row.ExpectedToDate += d.ExpectedToDate(asOf)
+	}
+
+	for i := range results {
+		row := &results[i]
+		row.Balance = math.Max(row.Pledged-row.Paid, 0)
+		row.Arrears = math.Max(row.ExpectedToDate-row.Paid, 0)
+		if row.Pledged > 0 {
+			row.PercentPaid = math.Round(row.Paid/row.Pledged*10000) / 100
+		}
+	}
+
+	return results
+}
+
+// pledgeStatusFor derives open/fulfilled from the amount paid, leaving cancelled pledges alone
+func pledgeStatusFor(pledge models.Pledge) string {
+	if pledge.Status == string(models.PledgeCancelled) {
+		return pledge.Status
+	}
+	if pledge.AmountPaid >= pledge.Amount {
+		return string(models.PledgeFulfilled)
+	}
+	return string(models.PledgeOpen)
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"storeHouse/models"
+	"testing"
+)
+
+// pledge opens a building campaign on income and pledges amount to it for the member
+func (f *fixture) pledge(income, memberID string, amount float64) string {
+	f.t.Helper()
+	campaign, err := NewCampaignService(f.repo).CreateCampaign(f.ctx, models.CreateCampaignRequest{
+		CampaignName:    "Church building",
+		IncomeAccountID: income,
+	}, "admin")
+	if err != nil {
+		f.t.Fatalf("create campaign: %v", err)
+	}
+
+	pledge, err := NewPledgeService(f.repo).CreatePledge(f.ctx, models.CreatePledgeRequest{
+		CampaignID: campaign.ID,
+		MemberID:   memberID,
+		Amount:     amount,
+	}, "admin")
+	if err != nil {
+		f.t.Fatalf("create pledge: %v", err)
+	}
+	return pledge.ID
+}
+
+func (f *fixture) getPledge(id string) *models.PledgeResponse {
+	f.t.Helper()
+	pledge, err := NewPledgeService(f.repo).GetPledge(f.ctx, id)
+	if err != nil {
+		f.t.Fatalf("get pledge: %v", err)
+	}
+	return pledge
+}
+
+func TestReceiptsFulfilPledge(t *testing.T) {
+	f := newFixture(t)
+	bank := f.account("Bank", models.AccountBank)
+	income := f.account("Building offering", models.AccountIncome)
+	member := f.member("Jane Wanjiru", "0712345678")
+	id := f.pledge(income, member, 1000)
+
+	f.receipt(bank, income, &member, nil, 600)
+	pledge := f.getPledge(id)
+	if pledge.AmountPaid != 600 || pledge.Status != string(models.PledgeOpen) {
+		t.Fatalf("after 600 got %.2f paid and status %s, want 600 and open", pledge.AmountPaid, pledge.Status)
+	}
+
+	// Only the 400 outstanding is applied; the rest is an ordinary offering
+	f.receipt(bank, income, &member, nil, 500)
+	pledge = f.getPledge(id)
+	if pledge.AmountPaid != 1000 || pledge.Status != string(models.PledgeFulfilled) {
+		t.Fatalf("after 1100 got %.2f paid and status %s, want 1000 and fulfilled", pledge.AmountPaid, pledge.Status)
+	}
+}
+
+func TestPledgeIgnoresOtherReceipts(t *testing.T) {
+	f := newFixture(t)
+	bank := f.account("Bank", models.AccountBank)
+	income := f.account("Building offering", models.AccountIncome)
+	tithe := f.account("Tithe", models.AccountIncome)
+	member := f.member("Jane Wanjiru", "0712345678")
+	other := f.member("John Otieno", "0722345678")
+	id := f.pledge(income, member, 1000)
+
+	f.receipt(bank, tithe, &member, nil, 500)
+	f.receipt(bank, income, &other, nil, 500)
+	f.receipt(bank, income, nil, nil, 500)
+
+	if pledge := f.getPledge(id); pledge.AmountPaid != 0 {
+		t.Fatalf("got %.2f paid, want nothing from other accounts, members or loose offering", pledge.AmountPaid)
+	}
+}
+
+func TestDeletingReceiptReopensPledge(t *testing.T) {
+	f := newFixture(t)
+	bank := f.account("Bank", models.AccountBank)
+	income := f.account("Building offering", models.AccountIncome)
+	member := f.member("Jane Wanjiru", "0712345678")
+	id := f.pledge(income, member, 1000)
+
+	receipt := f.receipt(bank, income, &member, nil, 1000)
+	if pledge := f.getPledge(id); pledge.Status != string(models.PledgeFulfilled) {
+		t.Fatalf("got status %s, want fulfilled", pledge.Status)
+	}
+
+	if err := NewReceiptService(f.repo).DeleteReceipt(f.ctx, receipt.ID); err != nil {
+		t.Fatalf("delete receipt: %v", err)
+	}
+	pledge := f.getPledge(id)
+	if pledge.AmountPaid != 0 || pledge.Status != string(models.PledgeOpen) {
+		t.Fatalf("after deleting the receipt got %.2f paid and status %s, want 0 and open", pledge.AmountPaid, pledge.Status)
+	}
+}
+
+func TestCollectionReceiptsFulfilPledgeOncePosted(t *testing.T) {
+	f := newFixture(t)
+	bank := f.account("Bank", models.AccountBank)
+	income := f.account("Building offering", models.AccountIncome)
+	member := f.member("Jane Wanjiru", "0712345678")
+	id := f.pledge(income, member, 1500)
+	session := f.collection(bank, income, &member)
+	collections := NewCollectionService(f.repo)
+
+	if _, err := collections.SignOff(f.ctx, session, counted, "counter-1"); err != nil {
+		t.Fatalf("first sign-off: %v", err)
+	}
+	if pledge := f.getPledge(id); pledge.AmountPaid != 0 {
+		t.Fatalf("before posting got %.2f paid, want nothing", pledge.AmountPaid)
+	}
+
+	if _, err := collections.SignOff(f.ctx, session, counted, "counter-2"); err != nil {
+		t.Fatalf("second sign-off: %v", err)
+	}
+	if pledge := f.getPledge(id); pledge.Status != string(models.PledgeFulfilled) {
+		t.Fatalf("after posting got status %s, want fulfilled", pledge.Status)
+	}
+}
+
+func TestUnpostedReceiptsDoNotFulfilPledge(t *testing.T) {
+	f := newFixture(t)
+	bank := f.account("Bank", models.AccountBank)
+	income := f.account("Building offering", models.AccountIncome)
+	member := f.member("Jane Wanjiru", "0712345678")
+	id := f.pledge(income, member, 1000)
+
+	txn, err := f.repo.CreateTransaction(f.ctx, models.Transaction{
+		TransactionType: string(models.TransactionReceipts),
+		Amount:          1000,
+		DebitAccountID:  bank,
+		MemberID:        &member,
+		Status:          string(models.TransactionDraft),
+		CreatedBy:       "clerk",
+	})
+	if err != nil {
+		t.Fatalf("create transaction: %v", err)
+	}
+	receipt, err := f.repo.CreateReceipt(f.ctx, models.Receipt{TransactionID: txn.ID, IncomeAccountID: income, Amount: 1000})
+	if err != nil {
+		t.Fatalf("create receipt: %v", err)
+	}
+
+	if err := NewPledgeService(f.repo).MatchReceipt(f.ctx, receipt); err != nil {
+		t.Fatalf("match receipt: %v", err)
+	}
+	if pledge := f.getPledge(id); pledge.AmountPaid != 0 {
+		t.Fatalf("got %.2f paid from a draft transaction, want nothing", pledge.AmountPaid)
+	}
+}
+
+func TestChangingReceiptMemberMovesPledgePayment(t *testing.T) {
+	f := newFixture(t)
+	bank := f.account("Bank", models.AccountBank)
+	income := f.account("Building offering", models.AccountIncome)
+	jane := f.member("Jane Wanjiru", "0712345678")
+	john := f.member("John Otieno", "0722345678")
+	janes := f.pledge(income, jane, 1000)
+	pledge, err := NewPledgeService(f.repo).CreatePledge(f.ctx, models.CreatePledgeRequest{
+		CampaignID: f.getPledge(janes).CampaignID,
+		MemberID:   john,
+		Amount:     1000,
+	}, "admin")
+	if err != nil {
+		t.Fatalf("create pledge: %v", err)
+	}
+	johns := pledge.ID
+
+	receipt := f.receipt(bank, income, &jane, nil, 600)
+	if _, err := NewTransactionService(f.repo).UpdateTransaction(f.ctx, receipt.TransactionID, models.UpdateTransactionRequest{MemberID: &john}); err != nil {
+		t.Fatalf("update transaction: %v", err)
+	}
+
+	if pledge := f.getPledge(janes); pledge.AmountPaid != 0 {
+		t.Fatalf("previous member's pledge has %.2f paid, want nothing", pledge.AmountPaid)
+	}
+	if pledge := f.getPledge(johns); pledge.AmountPaid != 600 {
+		t.Fatalf("new member's pledge has %.2f paid, want 600", pledge.AmountPaid)
+	}
+}
@@ -1,114 +1,162 @@
 package services
 
 import (
-	"errors"
+	"context"
+	"log"
 	"storeHouse/models"
 	"storeHouse/repository"
 	"time"
-
-	"github.com/jmoiron/sqlx"
 )
 
 type ReceiptService struct {
-	DB *sqlx.DB
+	Repo repository.Store
 }
 
 // Create a new instance of ReceiptService
-func NewReceiptService(db *sqlx.DB) *ReceiptService {
-	return &ReceiptService{DB: db}
+func NewReceiptService(repo repository.Store) *ReceiptService {
+	return &ReceiptService{Repo: repo}
 }
 
 // CreateReceipt handles receipt creation business logic
-func (s *ReceiptService) CreateReceipt(req models.CreateReceiptRequest) (*models.ReceiptResponse, error) {
+func (s *ReceiptService) CreateReceipt(ctx context.Context, req models.CreateReceiptRequest) (*models.ReceiptResponse, error) {
 	// Validate that amount is positive
 	if req.Amount <= 0 {
-		return nil, errors.New("amount must be greater than zero")
+		return nil, models.Invalid("amount must be greater than zero")
 	}
 
 	// Check if income account exists
-	if _, err := repository.GetAccount(s.DB, req.IncomeAccountID); err != nil {
-		return nil, errors.New("income account not found")
+	if _, err := getPostingAccount(ctx, s.Repo, req.IncomeAccountID, "income account"); err != nil {
+		return nil, err
+	}
+
+	// Check the fund if provided
+	fundID := emptyToNil(req.FundID)
+	if err := NewFundService(s.Repo).CheckFund(ctx, fundID); err != nil {
+		return nil, err
 	}
 
 	// Check if transaction exists
-	if _, err := repository.GetTransaction(s.DB, req.TransactionID); err != nil {
-		return nil, errors.New("transaction not found")
+	txn, err := s.Repo.GetTransaction(ctx, req.TransactionID)
+	if err != nil {
+		return nil, models.ErrTransactionNotFound
+	}
+	if err := ensureOutsideSession(txn); err != nil {
+		return nil, err
 	}
 
 	// Prepare model for DB
 	receipt := models.Receipt{
 		TransactionID:   req.TransactionID,
 		IncomeAccountID: req.IncomeAccountID,
+		FundID:          fundID,
 		Amount:          req.Amount,
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
 	}
 
 	// Save to DB
-	newReceipt, err := repository.CreateReceipt(s.DB, receipt)
+	newReceipt, err := s.Repo.CreateReceipt(ctx, receipt)
 	if err != nil {
 		return nil, err
 	}
 
+	// Apply the receipt to any open pledges the member has on this account
+	if err := NewPledgeService(s.Repo).MatchReceipt(ctx, newReceipt); err != nil {
+		log.Printf("⚠️  Failed to match receipt %s to pledges: %v", newReceipt.ID, err)
+	}
+
+	// Acknowledge the offering to the member by SMS
+	if err := NewSMSService(s.Repo).QueueReceiptAcknowledgement(ctx, newReceipt.TransactionID); err != nil {
+		log.Printf("⚠️  Failed to queue SMS for receipt %s: %v", newReceipt.ID, err)
+	}
+
 	return newReceipt.ToResponse(), nil
 }
 
 // UpdateReceipt handles update logic
-func (s *ReceiptService) UpdateReceipt(id string, req models.UpdateReceiptRequest) (*models.ReceiptResponse, error) {
+func (s *ReceiptService) UpdateReceipt(ctx context.Context, id string, req models.UpdateReceiptRequest) (*models.ReceiptResponse, error) {
 	// Fetch existing record
-	existing, err := repository.GetReceipt(s.DB, id)
+	existing, err := s.Repo.GetReceipt(ctx, id)
 	if err != nil {
-		return nil, errors.New("receipt not found")
+		return nil, models.ErrReceiptNotFound
+	}
+	if err := s.ensureOutsideSession(ctx, existing); err != nil {
+		return nil, err
 	}
 
 	// Apply updates only if fields are provided
 	if req.IncomeAccountID != nil {
 		// Check if new income account exists
-		if _, err := repository.GetAccount(s.DB, *req.IncomeAccountID); err != nil {
-			return nil, errors.New("income account not found")
+		if _, err := getPostingAccount(ctx, s.Repo, *req.IncomeAccountID, "income account"); err != nil {
+			return nil, err
 		}
 		existing.IncomeAccountID = *req.IncomeAccountID
 	}
 	if req.Amount != nil {
 		if *req.Amount <= 0 {
-			return nil, errors.New("amount must be greater than zero")
+			return nil, models.Invalid("amount must be greater than zero")
 		}
 		existing.Amount = *req.Amount
 	}
+	if req.FundID != nil {
+		// An empty fund_id moves the line to the general fund
+		fundID := emptyToNil(req.FundID)
+		if err := NewFundService(s.Repo).CheckFund(ctx, fundID); err != nil {
+			return nil, err
+		}
+		existing.FundID = fundID
+	}
 
 	existing.UpdatedAt = time.Now()
 
 	// Persist update
-	updated, err := repository.UpdateReceipt(s.DB, existing)
+	updated, err := s.Repo.UpdateReceipt(ctx, existing)
 	if err != nil {
 		return nil, err
 	}
 
+	// Re-match pledges since the amount or account may have changed
+	pledgeService := NewPledgeService(s.Repo)
+	if err := pledgeService.UnmatchReceipt(ctx, updated.ID); err != nil {
+		log.Printf("⚠️  Failed to unmatch receipt %s from pledges: %v", updated.ID, err)
+	} else if err := pledgeService.MatchReceipt(ctx, updated); err != nil {
+		log.Printf("⚠️  Failed to match receipt %s to pledges: %v", updated.ID, err)
+	}
+
 	return updated.ToResponse(), nil
 }
 
 // DeleteReceipt removes a receipt record
-func (s *ReceiptService) DeleteReceipt(id string) error {
+func (s *ReceiptService) DeleteReceipt(ctx context.Context, id string) error {
 	// Ensure exists before deleting
-	if _, err := repository.GetReceipt(s.DB, id); err != nil {
-		return errors.New("receipt not found")
+	existing, err := s.Repo.GetReceipt(ctx, id)
+	if err != nil {
+		return models.ErrReceiptNotFound
+	}
+	if err := s.ensureOutsideSession(ctx, existing); err != nil {
+		return err
+	}
+
+	// Release any pledge allocations so the pledges re-open
+	if err := NewPledgeService(s.Repo).UnmatchReceipt(ctx, id); err != nil {
+		return err
 	}
 
-	return repository.DeleteReceipt(s.DB, id)
+	return s.Repo.DeleteReceipt(ctx, id)
 }
 
 // GetReceipt returns single receipt details
-func (s *ReceiptService) GetReceipt(id string) (*models.ReceiptResponse, error) {
-	receipt, err := repository.GetReceipt(s.DB, id)
+func (s *ReceiptService) GetReceipt(ctx context.Context, id string) (*models.ReceiptResponse, error) {
+	receipt, err := s.Repo.GetReceipt(ctx, id)
 	if err != nil {
-		return nil, errors.New("receipt not found")
+		return nil, models.ErrReceiptNotFound
 	}
 	return receipt.ToResponse(), nil
 }
 
 // GetAllReceipts returns all receipts
-func (s *ReceiptService) GetAllReceipts() ([]models.ReceiptResponse, error) {
-	receipts, err := repository.GetAllReceipts(s.DB)
+func (s *ReceiptService) GetAllReceipts(ctx context.Context) ([]models.ReceiptResponse, error) {
+	receipts, err := s.Repo.GetAllReceipts(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -123,8 +171,8 @@ func (s *ReceiptService) GetAllReceipts() ([]models.ReceiptResponse, error) {
 }
 
 // GetReceiptsByTransaction returns receipts for a specific transaction
-func (s *ReceiptService) GetReceiptsByTransaction(transactionID string) ([]models.ReceiptResponse, error) {
-	receipts, err := repository.GetReceiptByTransaction(s.DB, transactionID)
+func (s *ReceiptService) GetReceiptsByTransaction(ctx context.Context, transactionID string) ([]models.ReceiptResponse, error) {
+	receipts, err := s.Repo.GetReceiptByTransaction(ctx, transactionID)
 	if err != nil {
 		return nil, err
 	}
@@ -139,8 +187,8 @@ func (s *ReceiptService) GetReceiptsByTransaction(transactionID string) ([]model
 }
 
 // GetReceiptsByAccount returns receipts for a specific income account
-func (s *ReceiptService) GetReceiptsByAccount(accountID string) ([]models.ReceiptResponse, error) {
-	receipts, err := repository.GetReceiptByAccount(s.DB, accountID)
+func (s *ReceiptService) GetReceiptsByAccount(ctx context.Context, accountID string) ([]models.ReceiptResponse, error) {
+	receipts, err := s.Repo.GetReceiptByAccount(ctx, accountID)
 	if err != nil {
 		return nil, err
 	}
@@ -155,13 +203,13 @@ func (s *ReceiptService) GetReceiptsByAccount(accountID string) ([]models.Receip
 }
 
 // GetTotalReceiptsByAccount returns the total amount for a specific account
-func (s *ReceiptService) GetTotalReceiptsByAccount(accountID string) (float64, error) {
-	return repository.GetTotalReceiptsByAccount(s.DB, accountID)
+func (s *ReceiptService) GetTotalReceiptsByAccount(ctx context.Context, accountID string) (float64, error) {
+	return s.Repo.GetTotalReceiptsByAccount(ctx, accountID)
 }
 
 // GetReceiptsByDateRange returns receipts within a date range
-func (s *ReceiptService) GetReceiptsByDateRange(startDate, endDate time.Time) ([]models.ReceiptResponse, error) {
-	receipts, err := repository.GetReceiptByDateRange(s.DB, startDate, endDate)
+func (s *ReceiptService) GetReceiptsByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.ReceiptResponse, error) {
+	receipts, err := s.Repo.GetReceiptByDateRange(ctx, startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
@@ -176,6 +224,53 @@ func (s *ReceiptService) GetReceiptsByDateRange(startDate, endDate time.Time) ([
 }
 
 // GetTotalReceiptsByDateRange returns the total receipts for an account within a date range
-func (s *ReceiptService) GetTotalReceiptsByDateRange(accountID string, startDate, endDate time.Time) (float64, error) {
-	return repository.GetTotalReceiptsByDateRange(s.DB, accountID, startDate, endDate)
+func (s *ReceiptService) GetTotalReceiptsByDateRange(ctx context.Context, accountID string, startDate, endDate time.Time) (float64, error) {
+	return s.Repo.GetTotalReceiptsByDateRange(ctx, accountID, startDate, endDate)
+}
+
+// ensureOutsideSession stops changes to a receipt line of a collection session's transaction
+func (s *ReceiptService) ensureOutsideSession(ctx context.Context, receipt models.Receipt) error {
+	txn, err := s.Repo.GetTransaction(ctx, receipt.TransactionID)
+	if err != nil {
+		return models.ErrTransactionNotFound
+	}
+	return ensureOutsideSession(txn)
+}
+
+// accountTotal is the amount a transaction's receipts credit to one income account
+type accountTotal struct {
+	Account string
+	Amount  float64
+}
+
+// receiptTotalsByAccount sums a transaction's receipt lines per income account, in the order
+// first recorded, for acknowledgements that list what was given to each account
+func receiptTotalsByAccount(ctx context.Context, repo repository.Store, transactionID string) ([]accountTotal, float64, error) {
+	receipts, err := repo.GetReceiptByTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	totals := make([]accountTotal, 0)
+	index := make(map[string]int)
+	var total float64
+	for _, r := range receipts {
+		i, ok := index[r.IncomeAccountID]
+		if !ok {
+			name := "Offering"
+			if account, err := repo.GetAccount(ctx, r.IncomeAccountID); err == nil {
+				name = account.AccountName
+			}
+			i = len(totals)
+			index[r.IncomeAccountID] = i
+			totals = append(totals, accountTotal{Account: name})
+		}
+		totals[i].Amount += r.Amount
+		total += r.Amount
+	}
+	if len(totals) == 0 {
+		return nil, 0, models.Invalid("transaction has no receipts")
+	}
+
+	return totals, total, nil
 }
@@ -0,0 +1,551 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"storeHouse/models"
+	"storeHouse/recurrence"
+	"storeHouse/repository"
+	"time"
+)
+
+// maxCatchUpRuns caps how many missed occurrences of one template are generated in a
+// single pass; the rest follow on the next tick
+const maxCatchUpRuns = 100
+
+type RecurringService struct {
+	Repo repository.Store
+}
+
+// Create a new instance of RecurringService
+func NewRecurringService(repo repository.Store) *RecurringService {
+	return &RecurringService{Repo: repo}
+}
+
+// CreateTemplate sets up a recurring template, scheduling its first run on or after the
+// start date. Its creator is recorded as the creator of every transaction it generates.
+func (s *RecurringService) CreateTemplate(ctx context.Context, req models.CreateRecurringTemplateRequest, createdBy string) (*models.RecurringTemplateResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if createdBy == "" {
+		return nil, models.ErrRecurringNoCreator
+	}
+
+	// Prepare model for DB
+	template := models.RecurringTemplate{
+		Name:            req.Name,
+		TransactionType: req.TransactionType,
+		DebitAccountID:  req.DebitAccountID,
+		MemberID:        emptyToNil(req.MemberID),
+		Notes:           req.Notes,
+		Schedule:        req.Schedule,
+		StartDate:       req.StartDate,
+		EndDate:         req.EndDate,
+		PostMode:        req.PostMode,
+		IsActive:        true,
+		CreatedBy:       &createdBy,
+	}
+	if template.PostMode == "" {
+		template.PostMode = string(models.RecurringDraft)
+		if template.TransactionType == string(models.TransactionReceipts) {
+			template.PostMode = string(models.RecurringPosted)
+		}
+	}
+
+	lines := toRecurringTemplateLines(req.Lines)
+	schedule, err := s.validate(ctx, template, lines)
+	if err != nil {
+		return nil, err
+	}
+	template.NextRunAt = nextOccurrence(schedule, template, template.StartDate.Add(-time.Minute))
+	if template.NextRunAt == nil {
+		return nil, models.Invalid("schedule has no occurrence between start_date and end_date")
+	}
+
+	// Save to DB
+	newTemplate, err := s.Repo.CreateRecurringTemplate(ctx, template)
+	if err != nil {
+		return nil, err
+	}
+	if lines, err = s.Repo.ReplaceRecurringTemplateLines(ctx, newTemplate.ID, lines); err != nil {
+		return nil, err
+	}
+
+	return recurringTemplateResponse(newTemplate, lines), nil
+}
+
+// UpdateTemplate updates a recurring template. Changing the schedule or start date
+// reschedules from the last generated occurrence; reactivating a paused template
+// resumes from now rather than catching up the pause.
+func (s *RecurringService) UpdateTemplate(ctx context.Context, id string, req models.UpdateRecurringTemplateRequest) (*models.RecurringTemplateResponse, error) {
+	// Fetch existing record
+	existing, err := s.Repo.GetRecurringTemplate(ctx, id)
+	if err != nil {
+		return nil, models.ErrRecurringNotFound
+	}
+
+	lines, err := s.Repo.GetRecurringTemplateLines(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	reschedule := false
+	resume := false
+
+	// Apply updates only if fields are provided
+	if req.Name != nil {
+		if *req.Name == "" {
+			return nil, models.Invalid("name is required")
+		}
+		existing.Name = *req.Name
+	}
+	if req.DebitAccountID != nil {
+		existing.DebitAccountID = *req.DebitAccountID
+	}
+	if req.MemberID != nil {
+		existing.MemberID = emptyToNil(req.MemberID)
+	}
+	if req.Notes != nil {
+		existing.Notes = req.Notes
+	}
+	if req.Schedule != nil && *req.Schedule != existing.Schedule {
+		existing.Schedule = *req.Schedule
+		reschedule = true
+	}
+	if req.StartDate != nil && !req.StartDate.Equal(existing.StartDate) {
+		existing.StartDate = *req.StartDate
+		reschedule = true
+	}
+	if req.EndDate != nil {
+		existing.EndDate = req.EndDate
+		reschedule = true
+	}
+	if req.PostMode != nil {
+		existing.PostMode = *req.PostMode
+	}
+	if req.IsActive != nil {
+		resume = *req.IsActive && !existing.IsActive
+		existing.IsActive = *req.IsActive
+	}
+	if req.Lines != nil {
+		if len(req.Lines) == 0 {
+			return nil, models.Invalid("at least one line is required")
+		}
+		for i := range req.Lines {
+			if err := req.Lines[i].Validate(); err != nil {
+				return nil, err
+			}
+		}
+		lines = toRecurringTemplateLines(req.Lines)
+	}
+
+	schedule, err := s.validate(ctx, existing, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	if reschedule || resume {
+		from := existing.StartDate.Add(-time.Minute)
+		runs, err := s.Repo.GetRecurringRunsByTemplate(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if len(runs) > 0 && runs[0].ScheduledFor.After(from) {
+			from = runs[0].ScheduledFor
+		}
+		if resume && time.Now().After(from) {
+			from = time.Now()
+		}
+		existing.NextRunAt = nextOccurrence(schedule, existing, from)
+	}
+
+	// Persist update
+	updated, err := s.Repo.UpdateRecurringTemplate(ctx, existing)
+	if err != nil {
+		return nil, err
+	}
+	if req.Lines != nil {
+		if lines, err = s.Repo.ReplaceRecurringTemplateLines(ctx, id, lines); err != nil {
+			return nil, err
+		}
+	}
+
+	return recurringTemplateResponse(updated, lines), nil
+}
+
+// GetTemplate returns a recurring template with its lines
+func (s *RecurringService) GetTemplate(ctx context.Context, id string) (*models.RecurringTemplateResponse, error) {
+	template, err := s.Repo.GetRecurringTemplate(ctx, id)
+	if err != nil {
+		return nil, models.ErrRecurringNotFound
+	}
+
+	lines, err := s.Repo.GetRecurringTemplateLines(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return recurringTemplateResponse(template, lines), nil
+}
+
+// GetAllTemplates returns every recurring template with its lines
+func (s *RecurringService) GetAllTemplates(ctx context.Context) ([]models.RecurringTemplateResponse, error) {
+	templates, err := s.Repo.GetAllRecurringTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response list
+	responses := make([]models.RecurringTemplateResponse, 0, len(templates))
+	for _, template := range templates {
+		lines, err := s.Repo.GetRecurringTemplateLines(ctx, template.ID)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, *recurringTemplateResponse(template, lines))
+	}
+
+	return responses, nil
+}
+
+// DeleteTemplate removes a recurring template and its run history. Transactions it
+// already generated are kept.
+func (s *RecurringService) DeleteTemplate(ctx context.Context, id string) error {
+	// Ensure exists before deleting
+	if _, err := s.Repo.GetRecurringTemplate(ctx, id); err != nil {
+		return models.ErrRecurringNotFound
+	}
+
+	return s.Repo.DeleteRecurringTemplate(ctx, id)
+}
+
+// GetRuns returns the run history of a template, newest first
+func (s *RecurringService) GetRuns(ctx context.Context, templateID string) ([]models.RecurringRunResponse, error) {
+	if _, err := s.Repo.GetRecurringTemplate(ctx, templateID); err != nil {
+		return nil, models.ErrRecurringNotFound
+	}
+
+	runs, err := s.Repo.GetRecurringRunsByTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toRecurringRunResponses(runs), nil
+}
+
+// StartScheduler generates due transactions in the background every interval. The
+// first pass runs straight away so occurrences missed while the server was down are
+// caught up at startup.
+func (s *RecurringService) StartScheduler(interval time.Duration) {
+	log.Printf("🔁 Recurring transaction scheduler running every %s", interval)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if summary, err := s.RunDue(context.Background(), time.Now()); err != nil {
+				log.Printf("⚠️  Recurring scheduler: %v", err)
+			} else if summary.Created > 0 || summary.Failed > 0 {
+				log.Printf("🔁 Recurring scheduler generated %d transaction(s), %d failed", summary.Created, summary.Failed)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// RunDue generates a transaction for every occurrence of every active template that
+// is due at the given time, catching up occurrences missed since the last pass
+func (s *RecurringService) RunDue(ctx context.Context, now time.Time) (*models.RecurringRunSummary, error) {
+	templates, err := s.Repo.GetDueRecurringTemplates(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.RecurringRunSummary{Runs: []models.RecurringRunResponse{}}
+	for _, template := range templates {
+		runs, err := s.runTemplate(ctx, template, now)
+		if err != nil {
+			log.Printf("⚠️  Recurring template %s: %v", template.ID, err)
+		}
+		for _, run := range runs {
+			if run.Status == string(models.RecurringRunCreated) {
+				summary.Created++
+			} else {
+				summary.Failed++
+			}
+			summary.Runs = append(summary.Runs, *run.ToResponse())
+		}
+	}
+
+	return summary, nil
+}
+
+// RetryRun generates the transaction of a failed run again
+func (s *RecurringService) RetryRun(ctx context.Context, runID string) (*models.RecurringRunResponse, error) {
+	run, err := s.Repo.GetRecurringRun(ctx, runID)
+	if err != nil {
+		return nil, models.ErrRecurringRunNotFound
+	}
+	if run.Status != string(models.RecurringRunFailed) {
+		return nil, models.Conflict("only failed runs can be retried")
+	}
+	if run.TransactionID != nil {
+		return nil, models.Conflict(fmt.Sprintf("only runs without a transaction can be retried; review transaction %s instead", *run.TransactionID))
+	}
+
+	template, err := s.Repo.GetRecurringTemplate(ctx, run.TemplateID)
+	if err != nil {
+		return nil, models.ErrRecurringNotFound
+	}
+
+	run = s.generate(ctx, template, run)
+	return run.ToResponse(), nil
+}
+
+// runTemplate works through a template's due occurrences, claiming a run for each
+// before generating it so a date already handled is skipped
+func (s *RecurringService) runTemplate(ctx context.Context, template models.RecurringTemplate, now time.Time) ([]models.RecurringRun, error) {
+	// Without a creator its transactions cannot be recorded, so its runs are not claimed
+	if template.CreatedBy == nil {
+		return nil, models.ErrRecurringNoCreator
+	}
+
+	schedule, err := recurrence.Parse(template.Schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []models.RecurringRun
+	next := template.NextRunAt
+	for next != nil && !next.After(now) && len(runs) < maxCatchUpRuns {
+		run, claimed, err := s.Repo.ClaimRecurringRun(ctx, template.ID, *next)
+		if err != nil {
+			return runs, err
+		}
+		if claimed {
+			runs = append(runs, s.generate(ctx, template, run))
+		}
+		next = nextOccurrence(schedule, template, *next)
+	}
+
+	// Re-read so a concurrent edit to the template is not overwritten
+	latest, err := s.Repo.GetRecurringTemplate(ctx, template.ID)
+	if err != nil {
+		return runs, err
+	}
+	if latest.Schedule == template.Schedule && equalTimes(latest.NextRunAt, template.NextRunAt) {
+		latest.NextRunAt = next
+	}
+	if len(runs) > 0 {
+		ranAt := time.Now()
+		latest.LastRunAt = &ranAt
+	}
+	if _, err := s.Repo.UpdateRecurringTemplate(ctx, latest); err != nil {
+		return runs, err
+	}
+
+	return runs, nil
+}
+
+// generate creates the transaction and lines for one occurrence and records the outcome on the run
+func (s *RecurringService) generate(ctx context.Context, template models.RecurringTemplate, run models.RecurringRun) models.RecurringRun {
+	txnID, err := s.createTransaction(ctx, template, run.ScheduledFor)
+
+	run.TransactionID = txnID
+	run.Status = string(models.RecurringRunCreated)
+	run.Error = nil
+	if err != nil {
+		message := err.Error()
+		run.Status = string(models.RecurringRunFailed)
+		run.Error = &message
+	}
+
+	updated, updateErr := s.Repo.UpdateRecurringRun(ctx, run)
+	if updateErr != nil {
+		log.Printf("⚠️  Failed to record recurring run %s: %v", run.ID, updateErr)
+		return run
+	}
+	return updated
+}
+
+// createTransaction books one occurrence of a template. Vouchers are left as drafts
+// unless the template posts them; receipts are always posted.
+func (s *RecurringService) createTransaction(ctx context.Context, template models.RecurringTemplate, scheduledFor time.Time) (*string, error) {
+	lines, err := s.Repo.GetRecurringTemplateLines(ctx, template.ID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.validate(ctx, template, lines); err != nil {
+		return nil, err
+	}
+
+	if template.CreatedBy == nil {
+		return nil, models.ErrRecurringNoCreator
+	}
+	createdBy := *template.CreatedBy
+
+	notes := "Recurring: " + template.Name
+	if template.Notes != nil && *template.Notes != "" {
+		notes = *template.Notes
+	}
+
+	txn, err := NewTransactionService(s.Repo).CreateTransaction(ctx, models.CreateTransactionRequest{
+		TransactionDate: &scheduledFor,
+		TransactionType: template.TransactionType,
+		Amount:          recurringLinesTotal(lines),
+		Notes:           &notes,
+		DebitAccountID:  template.DebitAccountID,
+		MemberID:        template.MemberID,
+	}, createdBy)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range lines {
+		switch models.TransactionType(template.TransactionType) {
+		case models.TransactionReceipts:
+			_, err = NewReceiptService(s.Repo).CreateReceipt(ctx, models.CreateReceiptRequest{
+				TransactionID:   txn.ID,
+				IncomeAccountID: line.AccountID,
+				Amount:          line.Amount,
+			})
+		case models.TransactionTransfer:
+			_, err = NewTransferService(s.Repo).CreateTransfer(ctx, models.CreateTransferRequest{
+				TransactionID:   txn.ID,
+				Particulars:     line.Particulars,
+				CreditAccountID: line.AccountID,
+				Amount:          line.Amount,
+			})
+		default:
+			_, err = NewExpenditureService(s.Repo).CreateExpenditure(ctx, models.CreateExpenditureRequest{
+				TransactionID: txn.ID,
+				Particulars:   line.Particulars,
+				BankAccountID: line.AccountID,
+				Amount:        line.Amount,
+			}, createdBy)
+		}
+		if err != nil {
+			return &txn.ID, err
+		}
+	}
+
+	if template.PostMode == string(models.RecurringPosted) && txn.Status != string(models.TransactionPosted) {
+		posted, err := s.Repo.GetTransaction(ctx, txn.ID)
+		if err != nil {
+			return &txn.ID, err
+		}
+		now := time.Now()
+		posted.Status = string(models.TransactionPosted)
+		posted.SubmittedBy = &createdBy
+		posted.SubmittedAt = &now
+		posted.PostedBy = &createdBy
+		posted.PostedAt = &now
+		if _, err := s.Repo.UpdateTransactionStatus(ctx, posted); err != nil {
+			return &txn.ID, err
+		}
+	}
+
+	return &txn.ID, nil
+}
+
+// validate checks a template and its lines against the schedule syntax and the accounts they use
+func (s *RecurringService) validate(ctx context.Context, template models.RecurringTemplate, lines []models.RecurringTemplateLine) (recurrence.Schedule, error) {
+	txn := models.Transaction{TransactionType: template.TransactionType}
+	if err := txn.ValidateTransactionType(); err != nil {
+		return recurrence.Schedule{}, err
+	}
+
+	schedule, err := recurrence.Parse(template.Schedule)
+	if err != nil {
+		return recurrence.Schedule{}, models.Invalid(err.Error())
+	}
+
+	switch models.RecurringPostMode(template.PostMode) {
+	case models.RecurringDraft:
+		if !txn.IsVoucher() {
+			return recurrence.Schedule{}, models.Invalid("receipt templates must use post_mode posted")
+		}
+	case models.RecurringPosted:
+	default:
+		return recurrence.Schedule{}, models.Invalid("post_mode must be draft or posted")
+	}
+
+	if template.EndDate != nil && template.EndDate.Before(template.StartDate) {
+		return recurrence.Schedule{}, models.Invalid("end_date must not be before start_date")
+	}
+
+	if _, err := getPostingAccount(ctx, s.Repo, template.DebitAccountID, "debit account"); err != nil {
+		return recurrence.Schedule{}, err
+	}
+	if template.MemberID != nil {
+		if _, err := s.Repo.GetMember(ctx, *template.MemberID); err != nil {
+			return recurrence.Schedule{}, models.ErrMemberNotFound
+		}
+	}
+	for _, line := range lines {
+		if _, err := getPostingAccount(ctx, s.Repo, line.AccountID, "line account "+line.AccountID); err != nil {
+			return recurrence.Schedule{}, err
+		}
+	}
+
+	return schedule, nil
+}
+
+// nextOccurrence returns the template's first occurrence after the given time, or nil
+// once the schedule has passed the template's end date
+func nextOccurrence(schedule recurrence.Schedule, template models.RecurringTemplate, after time.Time) *time.Time {
+	next := schedule.Next(after)
+	if next.IsZero() {
+		return nil
+	}
+	if template.EndDate != nil && next.After(*template.EndDate) {
+		return nil
+	}
+	return &next
+}
+
+func equalTimes(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func recurringLinesTotal(lines []models.RecurringTemplateLine) float64 {
+	var total float64
+	for _, line := range lines {
+		total += line.Amount
+	}
+	return roundAmount(total)
+}
+
+func recurringTemplateResponse(template models.RecurringTemplate, lines []models.RecurringTemplateLine) *models.RecurringTemplateResponse {
+	response := template.ToResponse()
+	response.Amount = recurringLinesTotal(lines)
+	response.Lines = make([]models.RecurringTemplateLineResponse, 0, len(lines))
+	for _, line := range lines {
+		response.Lines = append(response.Lines, *line.ToResponse())
+	}
+	return response
+}
+
+func toRecurringTemplateLines(reqs []models.RecurringTemplateLineRequest) []models.RecurringTemplateLine {
+	lines := make([]models.RecurringTemplateLine, 0, len(reqs))
+	for _, req := range reqs {
+		lines = append(lines, models.RecurringTemplateLine{
+			AccountID:   req.AccountID,
+			Particulars: req.Particulars,
+			Amount:      req.Amount,
+		})
+	}
+	return lines
+}
+
+func toRecurringRunResponses(runs []models.RecurringRun) []models.RecurringRunResponse {
+	responses := make([]models.RecurringRunResponse, 0, len(runs))
+	for _, run := range runs {
+		responses = append(responses, *run.ToResponse())
+	}
+	return responses
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type SetupService struct {
+	Repo repository.Store
+}
+
+// Create a new instance of SetupService
+func NewSetupService(repo repository.Store) *SetupService {
+	return &SetupService{Repo: repo}
+}
+
+// GetTemplates returns the built-in chart of accounts templates
+func (s *SetupService) GetTemplates() []models.ChartTemplate {
+	return models.ChartTemplates
+}
+
+// GetTemplate returns one chart of accounts template
+func (s *SetupService) GetTemplate(key string) (*models.ChartTemplate, error) {
+	template, ok := models.FindChartTemplate(key)
+	if !ok {
+		return nil, models.ErrTemplateNotFound
+	}
+	return &template, nil
+}
+
+// GetStatus reports whether the installation has been set up
+func (s *SetupService) GetStatus(ctx context.Context) (*models.SetupStatus, error) {
+	status, err := s.Repo.GetSetupStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Setup seeds a new installation with the first church, its admin, member groups and a
+// chart of accounts from a template. It only runs while there are no users.
+func (s *SetupService) Setup(ctx context.Context, req models.SetupRequest) (*models.SetupResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	status, err := s.Repo.GetSetupStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if status.Completed {
+		return nil, models.Conflict("setup has already been completed")
+	}
+
+	if err := NewUserService(s.Repo).validatePasswordStrength(req.Admin.Password); err != nil {
+		return nil, err
+	}
+
+	// Prepare models for DB
+	code, _ := models.NormalizeChurchCode(req.Church.Code)
+	church := models.Church{
+		Name:           strings.TrimSpace(req.Church.Name),
+		Code:           code,
+		MpesaShortcode: trimToNil(req.Church.MpesaShortcode),
+		IsActive:       true,
+	}
+
+	admin := models.User{
+		Username:     strings.TrimSpace(req.Admin.Username),
+		Email:        strings.TrimSpace(req.Admin.Email),
+		PasswordHash: req.Admin.Password,
+		FullName:     strings.TrimSpace(req.Admin.FullName),
+		Role:         string(models.RoleAdmin),
+		PhoneNumber:  req.Admin.PhoneNumber,
+		IsActive:     true,
+	}
+
+	groups := make([]models.MembersGroup, 0, len(req.Groups))
+	for _, name := range req.Groups {
+		groups = append(groups, models.MembersGroup{GroupName: strings.TrimSpace(name)})
+	}
+
+	var accounts []models.Account
+	if req.Template != "" {
+		template, _ := models.FindChartTemplate(req.Template)
+		accounts = templateAccounts(template)
+	}
+
+	// Save to DB
+	newChurch, newAdmin, newGroups, newAccounts, err := s.Repo.SeedChurch(ctx, church, admin, groups, accounts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response lists
+	response := &models.SetupResponse{
+		Template: req.Template,
+		Church:   *newChurch.ToResponse(),
+		Admin:    *newAdmin.ToResponse(),
+		Groups:   make([]models.GroupResponse, 0, len(newGroups)),
+		Accounts: make([]models.AccountResponse, 0, len(newAccounts)),
+	}
+	for _, g := range newGroups {
+		response.Groups = append(response.Groups, *g.ToResponse())
+	}
+	for _, a := range newAccounts {
+		response.Accounts = append(response.Accounts, *a.ToResponse())
+	}
+
+	return response, nil
+}
+
+// templateAccounts turns a template into accounts, giving each an ID up front so children
+// can refer to their parent
+func templateAccounts(template models.ChartTemplate) []models.Account {
+	ids := make(map[string]string, len(template.Accounts))
+	accounts := make([]models.Account, 0, len(template.Accounts))
+
+	for _, t := range template.Accounts {
+		code := t.Code
+		account := models.Account{
+			ID:          uuid.New().String(),
+			AccountName: t.Name,
+			AccountType: t.Type,
+			Code:        &code,
+			IsHeader:    t.IsHeader,
+			LocalShare:  t.LocalShare,
+			Notes:       trimToNil(&t.Notes),
+			IsActive:    true,
+		}
+		if parentID, ok := ids[t.ParentCode]; ok {
+			account.ParentID = &parentID
+		}
+		ids[t.Code] = account.ID
+		accounts = append(accounts, account)
+	}
+
+	return accounts
+}
@@ -0,0 +1,333 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"storeHouse/models"
+	"storeHouse/mpesa"
+	"storeHouse/notifications"
+	"storeHouse/repository"
+	"time"
+)
+
+// smsBatchSize caps how many queued messages one dispatcher run sends
+const smsBatchSize = 50
+
+type SMSService struct {
+	Repo   repository.Store
+	Config notifications.Config
+}
+
+// Create a new instance of SMSService
+func NewSMSService(repo repository.Store) *SMSService {
+	return &SMSService{Repo: repo, Config: notifications.LoadConfig()}
+}
+
+// QueueReceiptAcknowledgement queues a thank-you SMS for a member's receipt transaction.
+// The message is held back briefly and rendered when sent, so a transaction posted as
+// several receipt lines gets one message listing every income account.
+func (s *SMSService) QueueReceiptAcknowledgement(ctx context.Context, transactionID string) error {
+	if !s.Config.Enabled() {
+		return nil
+	}
+
+	txn, err := s.Repo.GetTransaction(ctx, transactionID)
+	if err != nil {
+		return models.ErrTransactionNotFound
+	}
+	if txn.TransactionType != string(models.TransactionReceipts) || txn.MemberID == nil {
+		return nil
+	}
+
+	member, err := s.Repo.GetMember(ctx, *txn.MemberID)
+	if err != nil {
+		return models.ErrMemberNotFound
+	}
+	if member.SMSOptOut || member.PhoneNumber == "" {
+		return nil
+	}
+
+	// Already queued by an earlier receipt line on the same transaction
+	if _, err := s.Repo.GetSMSNotificationByTransaction(ctx, txn.ID, string(models.SMSReceipt)); err == nil {
+		return nil
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	// Prepare model for DB
+	notification := models.SMSNotification{
+		Kind:          string(models.SMSReceipt),
+		MemberID:      &member.ID,
+		PhoneNumber:   member.PhoneNumber,
+		TransactionID: &txn.ID,
+		Status:        string(models.SMSQueued),
+		SendAfter:     time.Now().Add(s.Config.SendDelay),
+	}
+
+	// Save to DB
+	_, err = s.Repo.CreateSMSNotification(ctx, notification)
+	return err
+}
+
+// ProcessQueue sends the queued messages that are due and returns how many were sent
+func (s *SMSService) ProcessQueue(ctx context.Context) (int, error) {
+	provider, err := notifications.NewProvider(s.Config)
+	if err != nil {
+		return 0, err
+	}
+
+	due, err := s.Repo.GetDueSMSNotifications(ctx, time.Now(), smsBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, n := range due {
+		updated, err := s.send(ctx, provider, n)
+		if err != nil {
+			log.Printf("⚠️  Failed to send SMS %s: %v", n.ID, err)
+			continue
+		}
+		if updated.Status == string(models.SMSSent) {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// StartDispatcher sends queued messages in the background every interval. It does nothing
+// when no SMS provider is configured.
+func (s *SMSService) StartDispatcher(interval time.Duration) {
+	if !s.Config.Enabled() {
+		log.Println("📵 SMS notifications disabled (SMS_PROVIDER not set)")
+		return
+	}
+	if _, err := notifications.NewProvider(s.Config); err != nil {
+		log.Printf("⚠️  SMS notifications disabled: %v", err)
+		return
+	}
+
+	log.Printf("📱 SMS notifications enabled via %s", s.Config.Provider)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if _, err := s.ProcessQueue(context.Background()); err != nil {
+				log.Printf("⚠️  SMS dispatcher: %v", err)
+			}
+		}
+	}()
+}
+
+// RetryNotification sends a failed message again straight away, with a fresh set of attempts
+func (s *SMSService) RetryNotification(ctx context.Context, id string) (*models.SMSNotificationResponse, error) {
+	notification, err := s.Repo.GetSMSNotification(ctx, id)
+	if err != nil {
+		return nil, models.ErrSMSNotFound
+	}
+	if notification.Status != string(models.SMSFailed) && notification.Status != string(models.SMSQueued) {
+		return nil, models.Conflict("only failed or queued messages can be retried")
+	}
+
+	provider, err := notifications.NewProvider(s.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	notification.Attempts = 0
+	updated, err := s.send(ctx, provider, notification)
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.ToResponse(), nil
+}
+
+// RecordDeliveryReport applies a gateway delivery report to the message it refers to
+func (s *SMSService) RecordDeliveryReport(ctx context.Context, providerMessageID, status, failureReason string) (*models.SMSNotificationResponse, error) {
+	notification, err := s.Repo.GetSMSNotificationByProviderID(ctx, providerMessageID)
+	if err != nil {
+		return nil, models.ErrSMSNotFound
+	}
+
+	switch notifications.DeliveryStatus(status) {
+	case string(models.SMSDelivered):
+		now := time.Now()
+		notification.Status = string(models.SMSDelivered)
+		notification.DeliveredAt = &now
+		notification.LastError = nil
+	case string(models.SMSFailed):
+		reason := status
+		if failureReason != "" {
+			reason += ": " + failureReason
+		}
+		notification.Status = string(models.SMSFailed)
+		notification.LastError = &reason
+	default:
+		notification.Status = string(models.SMSSent)
+	}
+
+	// Persist update
+	updated, err := s.Repo.UpdateSMSNotification(ctx, notification)
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.ToResponse(), nil
+}
+
+// GetNotification returns a single SMS notification
+func (s *SMSService) GetNotification(ctx context.Context, id string) (*models.SMSNotificationResponse, error) {
+	notification, err := s.Repo.GetSMSNotification(ctx, id)
+	if err != nil {
+		return nil, models.ErrSMSNotFound
+	}
+	return notification.ToResponse(), nil
+}
+
+// GetNotifications returns SMS notifications, optionally filtered by status
+func (s *SMSService) GetNotifications(ctx context.Context, status string) ([]models.SMSNotificationResponse, error) {
+	var list []models.SMSNotification
+	var err error
+	if status != "" {
+		list, err = s.Repo.GetSMSNotificationsByStatus(ctx, status)
+	} else {
+		list, err = s.Repo.GetAllSMSNotifications(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toSMSResponses(list), nil
+}
+
+// GetMemberNotifications returns the messages sent to a member
+func (s *SMSService) GetMemberNotifications(ctx context.Context, memberID string) ([]models.SMSNotificationResponse, error) {
+	list, err := s.Repo.GetSMSNotificationsByMember(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toSMSResponses(list), nil
+}
+
+// SetMemberOptOut records whether a member receives SMS notifications
+func (s *SMSService) SetMemberOptOut(ctx context.Context, memberID string, req models.SetSMSOptOutRequest) (*models.MemberResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Ensure exists before updating
+	if _, err := s.Repo.GetMember(ctx, memberID); err != nil {
+		return nil, models.ErrMemberNotFound
+	}
+
+	if err := s.Repo.SetMemberSMSOptOut(ctx, memberID, *req.OptOut); err != nil {
+		return nil, err
+	}
+
+	member, err := s.Repo.GetMember(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	return member.ToResponse(), nil
+}
+
+// send makes one delivery attempt, recording the outcome. Failed attempts are re-queued
+// with a growing delay until the configured number of attempts is used up.
+func (s *SMSService) send(ctx context.Context, provider notifications.SMSProvider, n models.SMSNotification) (models.SMSNotification, error) {
+	// The member may have opted out since the message was queued
+	if n.MemberID != nil {
+		if member, err := s.Repo.GetMember(ctx, *n.MemberID); err == nil && member.SMSOptOut {
+			n.Status = string(models.SMSSkipped)
+			return s.Repo.UpdateSMSNotification(ctx, n)
+		}
+	}
+
+	message, err := s.renderMessage(ctx, n)
+	if err != nil {
+		return models.SMSNotification{}, err
+	}
+	n.Message = &message
+
+	to := n.PhoneNumber
+	if variants := mpesa.PhoneVariants(n.PhoneNumber); len(variants) > 1 {
+		to = variants[1]
+	}
+
+	providerName := provider.Name()
+	n.Provider = &providerName
+	n.Attempts++
+
+	result, sendErr := provider.Send(to, message)
+	if sendErr != nil {
+		reason := sendErr.Error()
+		n.LastError = &reason
+		if n.Attempts >= s.Config.MaxAttempts {
+			n.Status = string(models.SMSFailed)
+		} else {
+			n.Status = string(models.SMSQueued)
+			n.SendAfter = time.Now().Add(time.Duration(n.Attempts*n.Attempts) * time.Minute)
+		}
+	} else {
+		now := time.Now()
+		n.Status = string(models.SMSSent)
+		n.SentAt = &now
+		n.LastError = nil
+		if result.MessageID != "" {
+			n.ProviderMessageID = &result.MessageID
+		}
+	}
+
+	// Persist update
+	return s.Repo.UpdateSMSNotification(ctx, n)
+}
+
+// renderMessage builds the message text from the notification's current records
+func (s *SMSService) renderMessage(ctx context.Context, n models.SMSNotification) (string, error) {
+	if n.TransactionID == nil {
+		if n.Message != nil {
+			return *n.Message, nil
+		}
+		return "", models.Invalid("notification has no message")
+	}
+
+	txn, err := s.Repo.GetTransaction(ctx, *n.TransactionID)
+	if err != nil {
+		return "", models.ErrTransactionNotFound
+	}
+	msg := notifications.ReceiptMessage{
+		Reference:  transactionReference(txn),
+		Date:       txn.TransactionDate,
+		ChurchName: s.Config.ChurchName,
+	}
+	if n.MemberID != nil {
+		if member, err := s.Repo.GetMember(ctx, *n.MemberID); err == nil {
+			msg.MemberName = member.FullName
+		}
+	}
+
+	totals, total, err := receiptTotalsByAccount(ctx, s.Repo, txn.ID)
+	if err != nil {
+		return "", err
+	}
+	for _, t := range totals {
+		msg.Lines = append(msg.Lines, notifications.ReceiptLine{Account: t.Account, Amount: t.Amount})
+	}
+	msg.Total = total
+
+	return notifications.RenderReceipt(s.Config.ReceiptTemplate, msg)
+}
+
+// toSMSResponses converts notifications to their responses
+func toSMSResponses(list []models.SMSNotification) []models.SMSNotificationResponse {
+	responses := make([]models.SMSNotificationResponse, 0, len(list))
+	for _, n := range list {
+		responses = append(responses, *n.ToResponse())
+	}
+	return responses
+}
@@ -0,0 +1,346 @@
+package services
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"storeHouse/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parsedStatement holds the lines and period details read from a statement file
+type parsedStatement struct {
+	Lines          []models.BankStatementLine
+	PeriodStart    *time.Time
+	PeriodEnd      *time.Time
+	ClosingBalance *float64
+}
+
+// statementDateLayouts lists the date formats accepted in CSV statements
+var statementDateLayouts = []string{
+	"2006-01-02",
+	"02/01/2006",
+	"02-01-2006",
+	"2006/01/02",
+	"02 Jan 2006",
+	"02-Jan-2006",
+	"02-Jan-06",
+	"2 Jan 2006",
+	"Jan 2, 2006",
+	time.RFC3339,
+}
+
+// csvStatementColumns maps the header names banks commonly use to the fields we read
+var csvStatementColumns = map[string][]string{
+	"date":        {"date", "transaction date", "trans date", "posting date", "value date", "completion time"},
+	"description": {"description", "details", "narration", "narrative", "particulars", "transaction details"},
+	"reference":   {"reference", "ref", "ref no", "reference number", "cheque no", "cheque number", "receipt no."},
+	"amount":      {"amount", "transaction amount"},
+	"debit":       {"debit", "withdrawal", "withdrawals", "money out", "withdrawn"},
+	"credit":      {"credit", "deposit", "deposits", "money in", "paid in"},
+	"balance":     {"balance", "running balance", "book balance"},
+}
+
+// parseStatement reads a statement file in the given format
+func parseStatement(format string, r io.Reader) (*parsedStatement, error) {
+	switch models.StatementFormat(format) {
+	case models.StatementCSV:
+		return parseCSVStatement(r)
+	case models.StatementOFX:
+		return parseOFXStatement(r)
+	default:
+		return nil, models.ErrInvalidStatementFormat
+	}
+}
+
+// parseCSVStatement reads a CSV statement with a header row. Amounts are read either from a
+// single signed amount column or from separate debit and credit columns.
+func parseCSVStatement(r io.Reader) (*parsedStatement, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, models.Invalid(fmt.Sprintf("invalid CSV: %v", err))
+	}
+	if len(rows) < 2 {
+		return nil, models.Invalid("statement has no lines")
+	}
+
+	// Map header columns
+	columns := make(map[string]int)
+	for i, name := range rows[0] {
+		name = strings.ToLower(strings.TrimSpace(name))
+		for field, aliases := range csvStatementColumns {
+			if _, ok := columns[field]; ok {
+				continue
+			}
+			for _, alias := range aliases {
+				if name == alias {
+					columns[field] = i
+				}
+			}
+		}
+	}
+
+	if _, ok := columns["date"]; !ok {
+		return nil, models.Invalid("statement header is missing a date column")
+	}
+	_, hasAmount := columns["amount"]
+	_, hasDebit := columns["debit"]
+	_, hasCredit := columns["credit"]
+	if !hasAmount && !(hasDebit || hasCredit) {
+		return nil, models.Invalid("statement header is missing an amount or debit/credit columns")
+	}
+
+	field := func(row []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	parsed := &parsedStatement{}
+	for i, row := range rows[1:] {
+		line := i + 2
+
+		// Skip blank rows and trailing summary rows without a date
+		dateStr := field(row, "date")
+		if dateStr == "" {
+			continue
+		}
+		date, err := parseStatementDate(dateStr)
+		if err != nil {
+			return nil, models.Invalid(fmt.Sprintf("line %d: %v", line, err))
+		}
+
+		var amount float64
+		if hasAmount {
+			amount, err = parseStatementAmount(field(row, "amount"))
+			if err != nil {
+				return nil, models.Invalid(fmt.Sprintf("line %d: %v", line, err))
+			}
+		} else {
+			credit, err := parseStatementAmount(field(row, "credit"))
+			if err != nil {
+				return nil, models.Invalid(fmt.Sprintf("line %d: %v", line, err))
+			}
+			debit, err := parseStatementAmount(field(row, "debit"))
+			if err != nil {
+				return nil, models.Invalid(fmt.Sprintf("line %d: %v", line, err))
+			}
+			if debit < 0 {
+				debit = -debit
+			}
+			amount = credit - debit
+		}
+		if amount == 0 {
+			continue
+		}
+
+		statementLine := models.BankStatementLine{
+			LineDate:    date,
+			Description: optionalString(field(row, "description")),
+			Reference:   optionalString(field(row, "reference")),
+			Amount:      amount,
+		}
+
+		if balanceStr := field(row, "balance"); balanceStr != "" {
+			balance, err := parseStatementAmount(balanceStr)
+			if err != nil {
+				return nil, models.Invalid(fmt.Sprintf("line %d: %v", line, err))
+			}
+			statementLine.Balance = &balance
+		}
+
+		parsed.Lines = append(parsed.Lines, statementLine)
+	}
+
+	if len(parsed.Lines) == 0 {
+		return nil, models.Invalid("statement has no lines")
+	}
+
+	// Derive the period from the line dates
+	start, end := parsed.Lines[0].LineDate, parsed.Lines[0].LineDate
+	for _, l := range parsed.Lines {
+		if l.LineDate.Before(start) {
+			start = l.LineDate
+		}
+		if l.LineDate.After(end) {
+			end = l.LineDate
+		}
+	}
+	parsed.PeriodStart = &start
+	parsed.PeriodEnd = &end
+
+	// The running balance on the latest line is the closing balance
+	last := parsed.Lines[0]
+	for _, l := range parsed.Lines {
+		if !l.LineDate.Before(last.LineDate) {
+			last = l
+		}
+	}
+	parsed.ClosingBalance = last.Balance
+
+	return parsed, nil
+}
+
+// ofxTagPattern matches an OFX element and its value; OFX 1.x (SGML) leaves leaf elements unclosed
+var ofxTagPattern = regexp.MustCompile(`<(/?)([A-Za-z0-9.]+)>([^<]*)`)
+
+// parseOFXStatement reads the transactions, period and ledger balance from an OFX statement
+func parseOFXStatement(r io.Reader) (*parsedStatement, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := &parsedStatement{}
+	var current map[string]string
+	var inLedgerBalance bool
+
+	for _, match := range ofxTagPattern.FindAllStringSubmatch(string(data), -1) {
+		closing := match[1] == "/"
+		tag := strings.ToUpper(match[2])
+		value := strings.TrimSpace(match[3])
+
+		switch {
+		case tag == "STMTTRN" && !closing:
+			current = make(map[string]string)
+		case tag == "STMTTRN" && closing:
+			line, err := ofxStatementLine(current)
+			if err != nil {
+				return nil, err
+			}
+			parsed.Lines = append(parsed.Lines, line)
+			current = nil
+		case tag == "LEDGERBAL":
+			inLedgerBalance = !closing
+		case closing:
+			// Closing tags of leaf elements carry no value
+		case current != nil:
+			current[tag] = value
+		case tag == "DTSTART" && value != "":
+			if date, err := parseOFXDate(value); err == nil {
+				parsed.PeriodStart = &date
+			}
+		case tag == "DTEND" && value != "":
+			if date, err := parseOFXDate(value); err == nil {
+				parsed.PeriodEnd = &date
+			}
+		case tag == "BALAMT" && inLedgerBalance && value != "":
+			if balance, err := parseStatementAmount(value); err == nil {
+				parsed.ClosingBalance = &balance
+			}
+		}
+	}
+
+	if len(parsed.Lines) == 0 {
+		return nil, models.Invalid("statement has no transactions")
+	}
+
+	return parsed, nil
+}
+
+// ofxStatementLine converts the fields of an OFX STMTTRN element to a statement line
+func ofxStatementLine(fields map[string]string) (models.BankStatementLine, error) {
+	date, err := parseOFXDate(fields["DTPOSTED"])
+	if err != nil {
+		return models.BankStatementLine{}, err
+	}
+
+	amount, err := parseStatementAmount(fields["TRNAMT"])
+	if err != nil {
+		return models.BankStatementLine{}, err
+	}
+
+	description := fields["NAME"]
+	if memo := fields["MEMO"]; memo != "" {
+		if description != "" {
+			description += " - "
+		}
+		description += memo
+	}
+
+	reference := fields["CHECKNUM"]
+	if reference == "" {
+		reference = fields["REFNUM"]
+	}
+
+	return models.BankStatementLine{
+		LineDate:    date,
+		Description: optionalString(description),
+		Reference:   optionalString(reference),
+		Amount:      amount,
+		FitID:       optionalString(fields["FITID"]),
+	}, nil
+}
+
+// parseOFXDate reads the date part of an OFX datetime such as 20250131120000[+3:EAT]
+func parseOFXDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, models.Invalid(fmt.Sprintf("invalid OFX date %q", value))
+	}
+	date, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, models.Invalid(fmt.Sprintf("invalid OFX date %q", value))
+	}
+	return date, nil
+}
+
+// parseStatementDate tries each accepted layout in turn
+func parseStatementDate(value string) (time.Time, error) {
+	for _, layout := range statementDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC), nil
+		}
+	}
+	// Accept a trailing time on any layout, e.g. "2025-01-31 14:05:00"
+	if i := strings.Index(value, " "); i > 0 {
+		for _, layout := range statementDateLayouts {
+			if date, err := time.Parse(layout, value[:i]); err == nil {
+				return date, nil
+			}
+		}
+	}
+	return time.Time{}, models.Invalid(fmt.Sprintf("unrecognised date %q", value))
+}
+
+// parseStatementAmount parses amounts such as "1,250.00", "-300" or "(300.00)"; blank is zero
+func parseStatementAmount(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "-" {
+		return 0, nil
+	}
+
+	negative := false
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		negative = true
+		value = strings.Trim(value, "()")
+	}
+	value = strings.ReplaceAll(value, ",", "")
+	value = strings.TrimPrefix(value, "KES")
+	value = strings.TrimSpace(value)
+
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, models.Invalid(fmt.Sprintf("invalid amount %q", value))
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// optionalString returns nil for an empty string
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -1,25 +1,24 @@
 package services
 
 import (
-	"errors"
+	"context"
+	"log"
 	"storeHouse/models"
 	"storeHouse/repository"
 	"time"
-
-	"github.com/jmoiron/sqlx"
 )
 
 type TransactionService struct {
-	DB *sqlx.DB
+	Repo repository.Store
 }
 
 // Create a new instance of TransactionService
-func NewTransactionService(db *sqlx.DB) *TransactionService {
-	return &TransactionService{DB: db}
+func NewTransactionService(repo repository.Store) *TransactionService {
+	return &TransactionService{Repo: repo}
 }
 
 // CreateTransaction handles transaction creation business logic
-func (s *TransactionService) CreateTransaction(req models.CreateTransactionRequest, createdBy string) (*models.TransactionResponse, error) {
+func (s *TransactionService) CreateTransaction(ctx context.Context, req models.CreateTransactionRequest, createdBy string) (*models.TransactionResponse, error) {
 	// Validate transaction type
 	transaction := models.Transaction{
 		TransactionType: req.TransactionType,
@@ -30,40 +29,56 @@ func (s *TransactionService) CreateTransaction(req models.CreateTransactionReque
 
 	// Validate that amount is positive
 	if req.Amount <= 0 {
-		return nil, errors.New("amount must be greater than zero")
+		return nil, models.Invalid("amount must be greater than zero")
 	}
 
 	// Check if debit account exists
-	if _, err := repository.GetAccount(s.DB, req.DebitAccountID); err != nil {
-		return nil, errors.New("debit account not found")
+	if _, err := getPostingAccount(ctx, s.Repo, req.DebitAccountID, "debit account"); err != nil {
+		return nil, err
 	}
 
 	// Validate member if provided
 	if req.MemberID != nil {
-		if _, err := repository.GetMember(s.DB, *req.MemberID); err != nil {
-			return nil, errors.New("member not found")
+		if _, err := s.Repo.GetMember(ctx, *req.MemberID); err != nil {
+			return nil, models.ErrMemberNotFound
+		}
+	}
+
+	// Validate payment method if provided
+	paymentMethod := emptyToNil(req.PaymentMethod)
+	if paymentMethod != nil {
+		if err := models.ValidatePaymentMethod(*paymentMethod); err != nil {
+			return nil, err
 		}
 	}
 
 	// Check for duplicate transaction reference if provided
 	if req.TransactionRef != nil {
-		if _, err := repository.GetTransactionByRef(s.DB, *req.TransactionRef); err == nil {
-			return nil, errors.New("transaction reference already exists")
+		if _, err := s.Repo.GetTransactionByRef(ctx, *req.TransactionRef); err == nil {
+			return nil, models.Conflict("transaction reference already exists")
 		}
 	}
 
 	// Prepare model for DB
 	transactionModel := models.Transaction{
-		TransactionRef:  req.TransactionRef,
-		TransactionDate: time.Now(),
-		TransactionType: req.TransactionType,
-		Amount:          req.Amount,
-		Notes:           req.Notes,
-		DebitAccountID:  req.DebitAccountID,
-		MemberID:        req.MemberID,
-		CreatedBy:       createdBy,
-		CreatedAt:       time.Now(),
-		UpdatedAt:       time.Now(),
+		TransactionRef:   req.TransactionRef,
+		TransactionDate:  time.Now(),
+		TransactionType:  req.TransactionType,
+		Amount:           req.Amount,
+		Notes:            req.Notes,
+		DebitAccountID:   req.DebitAccountID,
+		MemberID:         req.MemberID,
+		PaymentMethod:    paymentMethod,
+		PaymentReference: trimToNil(req.PaymentReference),
+		Status:           string(models.TransactionPosted),
+		CreatedBy:        createdBy,
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
+	}
+
+	// Payments out start as drafts and only reach the books once approved and posted
+	if transactionModel.IsVoucher() {
+		transactionModel.Status = string(models.TransactionDraft)
 	}
 
 	// Use provided transaction date if available
@@ -72,7 +87,7 @@ func (s *TransactionService) CreateTransaction(req models.CreateTransactionReque
 	}
 
 	// Save to DB
-	newTransaction, err := repository.CreateTransaction(s.DB, transactionModel)
+	newTransaction, err := s.Repo.CreateTransaction(ctx, transactionModel)
 	if err != nil {
 		return nil, err
 	}
@@ -81,19 +96,25 @@ func (s *TransactionService) CreateTransaction(req models.CreateTransactionReque
 }
 
 // UpdateTransaction handles update logic
-func (s *TransactionService) UpdateTransaction(id string, req models.UpdateTransactionRequest) (*models.TransactionResponse, error) {
+func (s *TransactionService) UpdateTransaction(ctx context.Context, id string, req models.UpdateTransactionRequest) (*models.TransactionResponse, error) {
 	// Fetch existing record
-	existing, err := repository.GetTransaction(s.DB, id)
+	existing, err := s.Repo.GetTransaction(ctx, id)
 	if err != nil {
-		return nil, errors.New("transaction not found")
+		return nil, models.ErrTransactionNotFound
+	}
+	if err := ensureEditable(existing); err != nil {
+		return nil, err
+	}
+	if err := ensureOutsideSession(existing); err != nil {
+		return nil, err
 	}
 
 	// Apply updates only if fields are provided
 	if req.TransactionRef != nil {
 		// Check for duplicate transaction reference if different
 		if existing.TransactionRef == nil || *existing.TransactionRef != *req.TransactionRef {
-			if _, err := repository.GetTransactionByRef(s.DB, *req.TransactionRef); err == nil {
-				return nil, errors.New("transaction reference already exists")
+			if _, err := s.Repo.GetTransactionByRef(ctx, *req.TransactionRef); err == nil {
+				return nil, models.Conflict("transaction reference already exists")
 			}
 		}
 		existing.TransactionRef = req.TransactionRef
@@ -102,15 +123,20 @@ func (s *TransactionService) UpdateTransaction(id string, req models.UpdateTrans
 		existing.TransactionDate = *req.TransactionDate
 	}
 	if req.TransactionType != nil {
+		wasVoucher := existing.IsVoucher()
 		existing.TransactionType = *req.TransactionType
 		// Validate transaction type
 		if err := existing.ValidateTransactionType(); err != nil {
 			return nil, err
 		}
+		// Receipts are posted on entry, so a transaction cannot switch in or out of the voucher workflow
+		if existing.IsVoucher() != wasVoucher {
+			return nil, models.Invalid("transaction type cannot be changed between receipts and vouchers")
+		}
 	}
 	if req.Amount != nil {
 		if *req.Amount <= 0 {
-			return nil, errors.New("amount must be greater than zero")
+			return nil, models.Invalid("amount must be greater than zero")
 		}
 		existing.Amount = *req.Amount
 	}
@@ -119,63 +145,111 @@ func (s *TransactionService) UpdateTransaction(id string, req models.UpdateTrans
 	}
 	if req.DebitAccountID != nil {
 		// Check if new debit account exists
-		if _, err := repository.GetAccount(s.DB, *req.DebitAccountID); err != nil {
-			return nil, errors.New("debit account not found")
+		if _, err := getPostingAccount(ctx, s.Repo, *req.DebitAccountID, "debit account"); err != nil {
+			return nil, err
 		}
 		existing.DebitAccountID = *req.DebitAccountID
 	}
+	memberChanged := false
 	if req.MemberID != nil {
 		// Validate member if provided
 		if *req.MemberID != "" {
-			if _, err := repository.GetMember(s.DB, *req.MemberID); err != nil {
-				return nil, errors.New("member not found")
+			if _, err := s.Repo.GetMember(ctx, *req.MemberID); err != nil {
+				return nil, models.ErrMemberNotFound
 			}
 		}
+		previous := ""
+		if existing.MemberID != nil {
+			previous = *existing.MemberID
+		}
+		memberChanged = previous != *req.MemberID
 		existing.MemberID = req.MemberID
 	}
+	if req.PaymentMethod != nil || req.PaymentReference != nil {
+		if err := applyPayment(&existing, req.PaymentMethod, req.PaymentReference); err != nil {
+			return nil, err
+		}
+	}
 
 	existing.UpdatedAt = time.Now()
 
 	// Persist update
-	updated, err := repository.UpdateTransaction(s.DB, existing)
+	updated, err := s.Repo.UpdateTransaction(ctx, existing)
 	if err != nil {
 		return nil, err
 	}
 
+	// The receipts now count towards the new member's pledges instead
+	if memberChanged && updated.TransactionType == string(models.TransactionReceipts) {
+		s.rematchPledges(ctx, updated.ID)
+	}
+
 	return updated.ToResponse(), nil
 }
 
+// rematchPledges moves a transaction's receipts from the pledges they were applied to onto
+// the current member's open pledges
+func (s *TransactionService) rematchPledges(ctx context.Context, transactionID string) {
+	receipts, err := s.Repo.GetReceiptByTransaction(ctx, transactionID)
+	if err != nil {
+		log.Printf("⚠️  Failed to load receipts of transaction %s: %v", transactionID, err)
+		return
+	}
+
+	pledgeService := NewPledgeService(s.Repo)
+	for _, receipt := range receipts {
+		if err := pledgeService.UnmatchReceipt(ctx, receipt.ID); err != nil {
+			log.Printf("⚠️  Failed to unmatch receipt %s from pledges: %v", receipt.ID, err)
+		} else if err := pledgeService.MatchReceipt(ctx, receipt); err != nil {
+			log.Printf("⚠️  Failed to match receipt %s to pledges: %v", receipt.ID, err)
+		}
+	}
+}
+
 // DeleteTransaction removes a transaction record
-func (s *TransactionService) DeleteTransaction(id string) error {
+func (s *TransactionService) DeleteTransaction(ctx context.Context, id string) error {
 	// Ensure exists before deleting
-	if _, err := repository.GetTransaction(s.DB, id); err != nil {
-		return errors.New("transaction not found")
+	existing, err := s.Repo.GetTransaction(ctx, id)
+	if err != nil {
+		return models.ErrTransactionNotFound
+	}
+	if err := ensureOutsideSession(existing); err != nil {
+		return err
+	}
+
+	// Rejected vouchers are discarded; anything further along stays for the audit trail
+	if existing.Status != string(models.TransactionRejected) {
+		if err := ensureEditable(existing); err != nil {
+			return err
+		}
 	}
 
-	return repository.DeleteTransaction(s.DB, id)
+	return deleteWithAttachments(ctx, s.Repo, models.AttachmentTransaction, id, func() error {
+		return s.Repo.DeleteTransaction(ctx, id)
+	})
 }
 
 // GetTransaction returns single transaction details
-func (s *TransactionService) GetTransaction(id string) (*models.TransactionResponse, error) {
-	transaction, err := repository.GetTransaction(s.DB, id)
+func (s *TransactionService) GetTransaction(ctx context.Context, id string) (*models.TransactionResponse, error) {
+	transaction, err := s.Repo.GetTransaction(ctx, id)
 	if err != nil {
-		return nil, errors.New("transaction not found")
+		return nil, models.ErrTransactionNotFound
 	}
 	return transaction.ToResponse(), nil
 }
 
 // GetTransactionByRef returns transaction by reference
-func (s *TransactionService) GetTransactionByRef(ref string) (*models.TransactionResponse, error) {
-	transaction, err := repository.GetTransactionByRef(s.DB, ref)
+func (s *TransactionService) GetTransactionByRef(ctx context.Context, ref string) (*models.TransactionResponse, error) {
+	transaction, err := s.Repo.GetTransactionByRef(ctx, ref)
 	if err != nil {
-		return nil, errors.New("transaction not found")
+		return nil, models.ErrTransactionNotFound
 	}
 	return transaction.ToResponse(), nil
 }
 
 // GetAllTransactions returns all transactions
-func (s *TransactionService) GetAllTransactions() ([]models.TransactionResponse, error) {
-	transactions, err := repository.GetAllTransactions(s.DB)
+func (s *TransactionService) GetAllTransactions(ctx context.Context) ([]models.TransactionResponse, error) {
+	transactions, err := s.Repo.GetAllTransactions(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -190,8 +264,8 @@ func (s *TransactionService) GetAllTransactions() ([]models.TransactionResponse,
 }
 
 // GetTransactionsByAccount returns transactions for a specific debit account
-func (s *TransactionService) GetTransactionsByAccount(accountID string) ([]models.TransactionResponse, error) {
-	transactions, err := repository.GetTransactionsByAccount(s.DB, accountID)
+func (s *TransactionService) GetTransactionsByAccount(ctx context.Context, accountID string) ([]models.TransactionResponse, error) {
+	transactions, err := s.Repo.GetTransactionsByAccount(ctx, accountID)
 	if err != nil {
 		return nil, err
 	}
@@ -206,8 +280,8 @@ func (s *TransactionService) GetTransactionsByAccount(accountID string) ([]model
 }
 
 // GetTransactionsByMember returns transactions for a specific member
-func (s *TransactionService) GetTransactionsByMember(memberID string) ([]models.TransactionResponse, error) {
-	transactions, err := repository.GetTransactionsByMember(s.DB, memberID)
+func (s *TransactionService) GetTransactionsByMember(ctx context.Context, memberID string) ([]models.TransactionResponse, error) {
+	transactions, err := s.Repo.GetTransactionsByMember(ctx, memberID)
 	if err != nil {
 		return nil, err
 	}
@@ -222,14 +296,14 @@ func (s *TransactionService) GetTransactionsByMember(memberID string) ([]models.
 }
 
 // GetTransactionsByType returns transactions of a specific type
-func (s *TransactionService) GetTransactionsByType(transactionType string) ([]models.TransactionResponse, error) {
+func (s *TransactionService) GetTransactionsByType(ctx context.Context, transactionType string) ([]models.TransactionResponse, error) {
 	// Validate transaction type
 	transaction := models.Transaction{TransactionType: transactionType}
 	if err := transaction.ValidateTransactionType(); err != nil {
 		return nil, err
 	}
 
-	transactions, err := repository.GetTransactionsByType(s.DB, transactionType)
+	transactions, err := s.Repo.GetTransactionsByType(ctx, transactionType)
 	if err != nil {
 		return nil, err
 	}
@@ -244,8 +318,8 @@ func (s *TransactionService) GetTransactionsByType(transactionType string) ([]mo
 }
 
 // GetTransactionsByDateRange returns transactions within a date range
-func (s *TransactionService) GetTransactionsByDateRange(startDate, endDate time.Time) ([]models.TransactionResponse, error) {
-	transactions, err := repository.GetTransactionsByDateRange(s.DB, startDate, endDate)
+func (s *TransactionService) GetTransactionsByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.TransactionResponse, error) {
+	transactions, err := s.Repo.GetTransactionsByDateRange(ctx, startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
@@ -258,3 +332,47 @@ func (s *TransactionService) GetTransactionsByDateRange(startDate, endDate time.
 
 	return responses, nil
 }
+
+// UpdatePayment records how a transaction was paid. Unlike other fields this can be set
+// after a voucher is posted, since cheques are usually written once payment is approved.
+func (s *TransactionService) UpdatePayment(ctx context.Context, id string, req models.UpdateTransactionPaymentRequest) (*models.TransactionResponse, error) {
+	// Fetch existing record
+	existing, err := s.Repo.GetTransaction(ctx, id)
+	if err != nil {
+		return nil, models.ErrTransactionNotFound
+	}
+	if err := ensureOutsideSession(existing); err != nil {
+		return nil, err
+	}
+
+	if err := applyPayment(&existing, req.PaymentMethod, req.PaymentReference); err != nil {
+		return nil, err
+	}
+
+	// Persist update
+	if err := s.Repo.UpdateTransactionPayment(ctx, id, existing.PaymentMethod, existing.PaymentReference); err != nil {
+		return nil, err
+	}
+
+	existing.UpdatedAt = time.Now()
+	return existing.ToResponse(), nil
+}
+
+// applyPayment sets the payment method and reference; an empty string clears either
+func applyPayment(txn *models.Transaction, method, reference *string) error {
+	if method != nil {
+		txn.PaymentMethod = emptyToNil(method)
+		if txn.PaymentMethod != nil {
+			if err := models.ValidatePaymentMethod(*txn.PaymentMethod); err != nil {
+				return err
+			}
+		}
+	}
+	if reference != nil {
+		txn.PaymentReference = trimToNil(reference)
+		if txn.PaymentReference != nil && len(*txn.PaymentReference) > 100 {
+			return models.Invalid("payment_reference must be at most 100 characters")
+		}
+	}
+	return nil
+}
@@ -1,38 +1,46 @@
 package services
 
 import (
-	"errors"
+	"context"
 	"storeHouse/models"
 	"storeHouse/repository"
 	"time"
-
-	"github.com/jmoiron/sqlx"
 )
 
 type TransferService struct {
-	DB *sqlx.DB
+	Repo repository.Store
 }
 
 // Create a new instance of TransferService
-func NewTransferService(db *sqlx.DB) *TransferService {
-	return &TransferService{DB: db}
+func NewTransferService(repo repository.Store) *TransferService {
+	return &TransferService{Repo: repo}
 }
 
 // CreateTransfer handles transfer creation business logic
-func (s *TransferService) CreateTransfer(req models.CreateTransferRequest) (*models.TransferResponse, error) {
+func (s *TransferService) CreateTransfer(ctx context.Context, req models.CreateTransferRequest) (*models.TransferResponse, error) {
 	// Validate that amount is positive
 	if req.Amount <= 0 {
-		return nil, errors.New("amount must be greater than zero")
+		return nil, models.Invalid("amount must be greater than zero")
 	}
 
 	// Check if credit account exists
-	if _, err := repository.GetAccount(s.DB, req.CreditAccountID); err != nil {
-		return nil, errors.New("credit account not found")
+	if _, err := getPostingAccount(ctx, s.Repo, req.CreditAccountID, "credit account"); err != nil {
+		return nil, err
+	}
+
+	// Check the fund if provided
+	fundID := emptyToNil(req.FundID)
+	if err := NewFundService(s.Repo).CheckFund(ctx, fundID); err != nil {
+		return nil, err
 	}
 
 	// Check if transaction exists
-	if _, err := repository.GetTransaction(s.DB, req.TransactionID); err != nil {
-		return nil, errors.New("transaction not found")
+	txn, err := s.Repo.GetTransaction(ctx, req.TransactionID)
+	if err != nil {
+		return nil, models.ErrTransactionNotFound
+	}
+	if err := ensureEditable(txn); err != nil {
+		return nil, err
 	}
 
 	// Prepare model for DB
@@ -40,13 +48,14 @@ func (s *TransferService) CreateTransfer(req models.CreateTransferRequest) (*mod
 		TransactionID:   req.TransactionID,
 		Particulars:     req.Particulars,
 		CreditAccountID: req.CreditAccountID,
+		FundID:          fundID,
 		Amount:          req.Amount,
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
 	}
 
 	// Save to DB
-	newTransfer, err := repository.CreateTransfer(s.DB, transfer)
+	newTransfer, err := s.Repo.CreateTransfer(ctx, transfer)
 	if err != nil {
 		return nil, err
 	}
@@ -55,11 +64,14 @@ func (s *TransferService) CreateTransfer(req models.CreateTransferRequest) (*mod
 }
 
 // UpdateTransfer handles update logic
-func (s *TransferService) UpdateTransfer(id string, req models.UpdateTransferRequest) (*models.TransferResponse, error) {
+func (s *TransferService) UpdateTransfer(ctx context.Context, id string, req models.UpdateTransferRequest) (*models.TransferResponse, error) {
 	// Fetch existing record
-	existing, err := repository.GetTransfer(s.DB, id)
+	existing, err := s.Repo.GetTransfer(ctx, id)
 	if err != nil {
-		return nil, errors.New("transfer not found")
+		return nil, models.ErrTransferNotFound
+	}
+	if err := ensureLinesEditable(ctx, s.Repo, existing.TransactionID); err != nil {
+		return nil, err
 	}
 
 	// Apply updates only if fields are provided
@@ -68,22 +80,30 @@ func (s *TransferService) UpdateTransfer(id string, req models.UpdateTransferReq
 	}
 	if req.CreditAccountID != nil {
 		// Check if new credit account exists
-		if _, err := repository.GetAccount(s.DB, *req.CreditAccountID); err != nil {
-			return nil, errors.New("credit account not found")
+		if _, err := getPostingAccount(ctx, s.Repo, *req.CreditAccountID, "credit account"); err != nil {
+			return nil, err
 		}
 		existing.CreditAccountID = *req.CreditAccountID
 	}
 	if req.Amount != nil {
 		if *req.Amount <= 0 {
-			return nil, errors.New("amount must be greater than zero")
+			return nil, models.Invalid("amount must be greater than zero")
 		}
 		existing.Amount = *req.Amount
 	}
+	if req.FundID != nil {
+		// An empty fund_id moves the line to the general fund
+		fundID := emptyToNil(req.FundID)
+		if err := NewFundService(s.Repo).CheckFund(ctx, fundID); err != nil {
+			return nil, err
+		}
+		existing.FundID = fundID
+	}
 
 	existing.UpdatedAt = time.Now()
 
 	// Persist update
-	updated, err := repository.UpdateTransfer(s.DB, existing)
+	updated, err := s.Repo.UpdateTransfer(ctx, existing)
 	if err != nil {
 		return nil, err
 	}
@@ -92,27 +112,33 @@ func (s *TransferService) UpdateTransfer(id string, req models.UpdateTransferReq
 }
 
 // DeleteTransfer removes a transfer record
-func (s *TransferService) DeleteTransfer(id string) error {
+func (s *TransferService) DeleteTransfer(ctx context.Context, id string) error {
 	// Ensure exists before deleting
-	if _, err := repository.GetTransfer(s.DB, id); err != nil {
-		return errors.New("transfer not found")
+	existing, err := s.Repo.GetTransfer(ctx, id)
+	if err != nil {
+		return models.ErrTransferNotFound
+	}
+	if err := ensureLinesEditable(ctx, s.Repo, existing.TransactionID); err != nil {
+		return err
 	}
 
-	return repository.DeleteTransfer(s.DB, id)
+	return deleteWithAttachments(ctx, s.Repo, models.AttachmentTransfer, id, func() error {
+		return s.Repo.DeleteTransfer(ctx, id)
+	})
 }
 
 // GetTransfer returns single transfer details
-func (s *TransferService) GetTransfer(id string) (*models.TransferResponse, error) {
-	transfer, err := repository.GetTransfer(s.DB, id)
+func (s *TransferService) GetTransfer(ctx context.Context, id string) (*models.TransferResponse, error) {
+	transfer, err := s.Repo.GetTransfer(ctx, id)
 	if err != nil {
-		return nil, errors.New("transfer not found")
+		return nil, models.ErrTransferNotFound
 	}
 	return transfer.ToResponse(), nil
 }
 
 // GetAllTransfers returns all transfers
-func (s *TransferService) GetAllTransfers() ([]models.TransferResponse, error) {
-	transfers, err := repository.GetAllTransfers(s.DB)
+func (s *TransferService) GetAllTransfers(ctx context.Context) ([]models.TransferResponse, error) {
+	transfers, err := s.Repo.GetAllTransfers(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -127,8 +153,8 @@ func (s *TransferService) GetAllTransfers() ([]models.TransferResponse, error) {
 }
 
 // GetTransfersByTransaction returns transfers for a specific transaction
-func (s *TransferService) GetTransfersByTransaction(transactionID string) ([]models.TransferResponse, error) {
-	transfers, err := repository.GetTransferByTransaction(s.DB, transactionID)
+func (s *TransferService) GetTransfersByTransaction(ctx context.Context, transactionID string) ([]models.TransferResponse, error) {
+	transfers, err := s.Repo.GetTransferByTransaction(ctx, transactionID)
 	if err != nil {
 		return nil, err
 	}
@@ -143,8 +169,8 @@ func (s *TransferService) GetTransfersByTransaction(transactionID string) ([]mod
 }
 
 // GetTransfersByCreditAccount returns transfers for a specific credit account
-func (s *TransferService) GetTransfersByCreditAccount(accountID string) ([]models.TransferResponse, error) {
-	transfers, err := repository.GetTransferByCreditAccount(s.DB, accountID)
+func (s *TransferService) GetTransfersByCreditAccount(ctx context.Context, accountID string) ([]models.TransferResponse, error) {
+	transfers, err := s.Repo.GetTransferByCreditAccount(ctx, accountID)
 	if err != nil {
 		return nil, err
 	}
@@ -159,13 +185,13 @@ func (s *TransferService) GetTransfersByCreditAccount(accountID string) ([]model
 }
 
 // GetTotalTransfersByCreditAccount returns the total amount for a specific credit account
-func (s *TransferService) GetTotalTransfersByCreditAccount(accountID string) (float64, error) {
-	return repository.GetTotalTransfersByCreditAccount(s.DB, accountID)
+func (s *TransferService) GetTotalTransfersByCreditAccount(ctx context.Context, accountID string) (float64, error) {
+	return s.Repo.GetTotalTransfersByCreditAccount(ctx, accountID)
 }
 
 // GetTransfersByDateRange returns transfers within a date range
-func (s *TransferService) GetTransfersByDateRange(startDate, endDate time.Time) ([]models.TransferResponse, error) {
-	transfers, err := repository.GetTransferByDateRange(s.DB, startDate, endDate)
+func (s *TransferService) GetTransfersByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.TransferResponse, error) {
+	transfers, err := s.Repo.GetTransferByDateRange(ctx, startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
@@ -180,6 +206,6 @@ func (s *TransferService) GetTransfersByDateRange(startDate, endDate time.Time)
 }
 
 // GetTotalTransfersByDateRange returns the total transfers for an account within a date range
-func (s *TransferService) GetTotalTransfersByDateRange(accountID string, startDate, endDate time.Time) (float64, error) {
-	return repository.GetTotalTransfersByDateRange(s.DB, accountID, startDate, endDate)
+func (s *TransferService) GetTotalTransfersByDateRange(ctx context.Context, accountID string, startDate, endDate time.Time) (float64, error) {
+	return s.Repo.GetTotalTransfersByDateRange(ctx, accountID, startDate, endDate)
 }
\ No newline at end of file
@@ -1,39 +1,37 @@
 package services
 
 import (
-	"errors"
+	"context"
 	"regexp"
 	"storeHouse/models"
 	"storeHouse/repository"
 	"time"
-
-	"github.com/jmoiron/sqlx"
 )
 
 type UserService struct {
-	DB *sqlx.DB
+	Repo repository.Store
 }
 
 // Create a new instance of UserService
-func NewUserService(db *sqlx.DB) *UserService {
-	return &UserService{DB: db}
+func NewUserService(repo repository.Store) *UserService {
+	return &UserService{Repo: repo}
 }
 
 // CreateUser handles user creation business logic
-func (s *UserService) CreateUser(req models.CreateUserRequest) (*models.UserResponse, error) {
+func (s *UserService) CreateUser(ctx context.Context, req models.CreateUserRequest) (*models.UserResponse, error) {
 	// Validate password strength
 	if err := s.validatePasswordStrength(req.Password); err != nil {
 		return nil, err
 	}
 
 	// Check for duplicate username
-	if _, err := repository.GetUserByUsername(s.DB, req.Username); err == nil {
-		return nil, errors.New("username already exists")
+	if _, err := s.Repo.GetUserByUsername(ctx, req.Username); err == nil {
+		return nil, models.Conflict("username already exists")
 	}
 
 	// Check for duplicate email
-	if _, err := repository.GetUserByEmail(s.DB, req.Email); err == nil {
-		return nil, errors.New("email already exists")
+	if _, err := s.Repo.GetUserByEmail(ctx, req.Email); err == nil {
+		return nil, models.Conflict("email already exists")
 	}
 
 	// Store password (in production, this should be hashed)
@@ -59,7 +57,7 @@ func (s *UserService) CreateUser(req models.CreateUserRequest) (*models.UserResp
 	}
 
 	// Save to DB
-	newUser, err := repository.CreateUser(s.DB, userModel)
+	newUser, err := s.Repo.CreateUser(ctx, userModel)
 	if err != nil {
 		return nil, err
 	}
@@ -68,19 +66,19 @@ func (s *UserService) CreateUser(req models.CreateUserRequest) (*models.UserResp
 }
 
 // UpdateUser handles update logic
-func (s *UserService) UpdateUser(id string, req models.UpdateUserRequest) (*models.UserResponse, error) {
+func (s *UserService) UpdateUser(ctx context.Context, id string, req models.UpdateUserRequest) (*models.UserResponse, error) {
 	// Fetch existing record
-	existing, err := repository.GetUser(s.DB, id)
+	existing, err := s.Repo.GetUser(ctx, id)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, models.ErrUserNotFound
 	}
 
 	// Apply updates only if fields are provided
 	if req.Username != nil {
 		// Check for duplicate username if different
 		if existing.Username != *req.Username {
-			if _, err := repository.GetUserByUsername(s.DB, *req.Username); err == nil {
-				return nil, errors.New("username already exists")
+			if _, err := s.Repo.GetUserByUsername(ctx, *req.Username); err == nil {
+				return nil, models.Conflict("username already exists")
 			}
 		}
 		existing.Username = *req.Username
@@ -88,8 +86,8 @@ func (s *UserService) UpdateUser(id string, req models.UpdateUserRequest) (*mode
 	if req.Email != nil {
 		// Check for duplicate email if different
 		if existing.Email != *req.Email {
-			if _, err := repository.GetUserByEmail(s.DB, *req.Email); err == nil {
-				return nil, errors.New("email already exists")
+			if _, err := s.Repo.GetUserByEmail(ctx, *req.Email); err == nil {
+				return nil, models.Conflict("email already exists")
 			}
 		}
 		existing.Email = *req.Email
@@ -114,7 +112,7 @@ func (s *UserService) UpdateUser(id string, req models.UpdateUserRequest) (*mode
 	existing.UpdatedAt = time.Now()
 
 	// Persist update
-	updated, err := repository.UpdateUser(s.DB, existing)
+	updated, err := s.Repo.UpdateUser(ctx, existing)
 	if err != nil {
 		return nil, err
 	}
@@ -123,57 +121,56 @@ func (s *UserService) UpdateUser(id string, req models.UpdateUserRequest) (*mode
 }
 
 // DeactivateUser sets user as inactive
-func (s *UserService) DeactivateUser(id string) error {
+func (s *UserService) DeactivateUser(ctx context.Context, id string) error {
 	// Ensure exists before deactivating
-	if _, err := repository.GetUser(s.DB, id); err != nil {
-		return errors.New("user not found")
+	if _, err := s.Repo.GetUser(ctx, id); err != nil {
+		return models.ErrUserNotFound
 	}
 
-	_, err := repository.DeactivateUser(s.DB, id)
+	_, err := s.Repo.DeactivateUser(ctx, id)
 	return err
 }
 
 // DeleteUser removes a user record
-func (s *UserService) DeleteUser(id string) error {
+func (s *UserService) DeleteUser(ctx context.Context, id string) error {
 	// Ensure exists before deleting
-	if _, err := repository.GetUser(s.DB, id); err != nil {
-		return errors.New("user not found")
+	if _, err := s.Repo.GetUser(ctx, id); err != nil {
+		return models.ErrUserNotFound
 	}
 
-	_, err := s.DB.Exec("DELETE FROM users WHERE id = $1", id)
-	return err
+	return s.Repo.DeleteUser(ctx, id)
 }
 
 // GetUser returns single user details
-func (s *UserService) GetUser(id string) (*models.UserResponse, error) {
-	user, err := repository.GetUser(s.DB, id)
+func (s *UserService) GetUser(ctx context.Context, id string) (*models.UserResponse, error) {
+	user, err := s.Repo.GetUser(ctx, id)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, models.ErrUserNotFound
 	}
 	return user.ToResponse(), nil
 }
 
 // GetUserByUsername returns user by username
-func (s *UserService) GetUserByUsername(username string) (*models.UserResponse, error) {
-	user, err := repository.GetUserByUsername(s.DB, username)
+func (s *UserService) GetUserByUsername(ctx context.Context, username string) (*models.UserResponse, error) {
+	user, err := s.Repo.GetUserByUsername(ctx, username)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, models.ErrUserNotFound
 	}
 	return user.ToResponse(), nil
 }
 
 // GetUserByEmail returns user by email
-func (s *UserService) GetUserByEmail(email string) (*models.UserResponse, error) {
-	user, err := repository.GetUserByEmail(s.DB, email)
+func (s *UserService) GetUserByEmail(ctx context.Context, email string) (*models.UserResponse, error) {
+	user, err := s.Repo.GetUserByEmail(ctx, email)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, models.ErrUserNotFound
 	}
 	return user.ToResponse(), nil
 }
 
 // GetAllUsers returns all users
-func (s *UserService) GetAllUsers() ([]models.UserResponse, error) {
-	users, err := repository.GetAllUsers(s.DB)
+func (s *UserService) GetAllUsers(ctx context.Context) ([]models.UserResponse, error) {
+	users, err := s.Repo.GetAllUsers(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -188,14 +185,14 @@ func (s *UserService) GetAllUsers() ([]models.UserResponse, error) {
 }
 
 // GetUsersByRole returns users with a specific role
-func (s *UserService) GetUsersByRole(role string) ([]models.UserResponse, error) {
+func (s *UserService) GetUsersByRole(ctx context.Context, role string) ([]models.UserResponse, error) {
 	// Validate user role
 	user := models.User{Role: role}
 	if err := user.ValidateRole(); err != nil {
 		return nil, err
 	}
 
-	users, err := repository.GetUsersByRole(s.DB, role)
+	users, err := s.Repo.GetUsersByRole(ctx, role)
 	if err != nil {
 		return nil, err
 	}
@@ -210,8 +207,8 @@ func (s *UserService) GetUsersByRole(role string) ([]models.UserResponse, error)
 }
 
 // GetActiveUsers returns all active users
-func (s *UserService) GetActiveUsers() ([]models.UserResponse, error) {
-	users, err := repository.GetActiveUsers(s.DB)
+func (s *UserService) GetActiveUsers(ctx context.Context) ([]models.UserResponse, error) {
+	users, err := s.Repo.GetActiveUsers(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -226,35 +223,35 @@ func (s *UserService) GetActiveUsers() ([]models.UserResponse, error) {
 }
 
 // UpdateLastLogin updates the last login timestamp for a user
-func (s *UserService) UpdateLastLogin(id string) error {
+func (s *UserService) UpdateLastLogin(ctx context.Context, id string) error {
 	// Ensure user exists
-	if _, err := repository.GetUser(s.DB, id); err != nil {
-		return errors.New("user not found")
+	if _, err := s.Repo.GetUser(ctx, id); err != nil {
+		return models.ErrUserNotFound
 	}
 
-	return repository.UpdateLastLogin(s.DB, id)
+	return s.Repo.UpdateLastLogin(ctx, id)
 }
 
 // AuthenticateUser validates user credentials
-func (s *UserService) AuthenticateUser(username, password string) (*models.UserResponse, error) {
+func (s *UserService) AuthenticateUser(ctx context.Context, username, password string) (*models.UserResponse, error) {
 	// Get user by username
-	user, err := repository.GetUserByUsername(s.DB, username)
+	user, err := s.Repo.GetUserByUsername(ctx, username)
 	if err != nil {
-		return nil, errors.New("invalid username or password")
+		return nil, models.ErrBadCredentials
 	}
 
 	// Check if user is active
 	if !user.IsActive {
-		return nil, errors.New("user account is deactivated")
+		return nil, models.Unauthorized("user account is deactivated")
 	}
 
 	// Verify password (simple comparison - use proper hashing in production)
 	if user.PasswordHash != password {
-		return nil, errors.New("invalid username or password")
+		return nil, models.ErrBadCredentials
 	}
 
 	// Update last login
-	if err := repository.UpdateLastLogin(s.DB, user.ID); err != nil {
+	if err := s.Repo.UpdateLastLogin(ctx, user.ID); err != nil {
 		// Don't return error for last login update failure
 		// Just log it or handle it as a warning
 	}
@@ -263,16 +260,16 @@ func (s *UserService) AuthenticateUser(username, password string) (*models.UserR
 }
 
 // ChangePassword changes user's password
-func (s *UserService) ChangePassword(id string, oldPassword, newPassword string) error {
+func (s *UserService) ChangePassword(ctx context.Context, id string, oldPassword, newPassword string) error {
 	// Get user
-	user, err := repository.GetUser(s.DB, id)
+	user, err := s.Repo.GetUser(ctx, id)
 	if err != nil {
-		return errors.New("user not found")
+		return models.ErrUserNotFound
 	}
 
 	// Verify old password (simple comparison - use proper hashing in production)
 	if user.PasswordHash != oldPassword {
-		return errors.New("invalid old password")
+		return models.Invalid("invalid old password")
 	}
 
 	// Validate new password strength
@@ -281,29 +278,28 @@ func (s *UserService) ChangePassword(id string, oldPassword, newPassword string)
 	}
 
 	// Update password (simple storage - use proper hashing in production)
-	_, err = s.DB.Exec("UPDATE users SET password_hash = $1, updated_at = $2 WHERE id = $3", newPassword, time.Now(), id)
-	return err
+	return s.Repo.UpdateUserPassword(ctx, id, newPassword)
 }
 
 // validatePasswordStrength validates password meets security requirements
 func (s *UserService) validatePasswordStrength(password string) error {
 	if len(password) < 8 {
-		return errors.New("password must be at least 8 characters long")
+		return models.Invalid("password must be at least 8 characters long")
 	}
 
 	// Check for at least one uppercase letter
 	if !regexp.MustCompile(`[A-Z]`).MatchString(password) {
-		return errors.New("password must contain at least one uppercase letter")
+		return models.Invalid("password must contain at least one uppercase letter")
 	}
 
 	// Check for at least one lowercase letter
 	if !regexp.MustCompile(`[a-z]`).MatchString(password) {
-		return errors.New("password must contain at least one lowercase letter")
+		return models.Invalid("password must contain at least one lowercase letter")
 	}
 
 	// Check for at least one digit
 	if !regexp.MustCompile(`[0-9]`).MatchString(password) {
-		return errors.New("password must contain at least one digit")
+		return models.Invalid("password must contain at least one digit")
 	}
 
 	return nil
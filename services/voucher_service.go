@@ -0,0 +1,369 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"storeHouse/models"
+	"storeHouse/repository"
+	"strings"
+	"time"
+)
+
+type VoucherService struct {
+	Repo repository.Store
+}
+
+// Create a new instance of VoucherService
+func NewVoucherService(repo repository.Store) *VoucherService {
+	return &VoucherService{Repo: repo}
+}
+
+// SubmitVoucher sends a draft voucher for approval once its lines add up to its amount
+func (s *VoucherService) SubmitVoucher(ctx context.Context, id, userID string) (*models.VoucherResponse, error) {
+	txn, err := s.getVoucher(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if txn.Status != string(models.TransactionDraft) {
+		return nil, models.Conflict("only draft vouchers can be submitted")
+	}
+
+	total, err := voucherLinesTotal(ctx, s.Repo, txn)
+	if err != nil {
+		return nil, err
+	}
+	if total == 0 {
+		return nil, models.Invalid("voucher has no lines")
+	}
+	if math.Abs(total-txn.Amount) > 0.005 {
+		return nil, models.Invalid("voucher lines do not add up to the voucher amount")
+	}
+
+	// Fix what the approval policies require at the moment of submission
+	accounts, err := voucherAccounts(ctx, s.Repo, txn)
+	if err != nil {
+		return nil, err
+	}
+	requirement, err := NewApprovalPolicyService(s.Repo).Evaluate(ctx, txn.TransactionType, accounts, txn.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	txn.Status = string(models.TransactionSubmitted)
+	txn.SubmittedBy = &userID
+	txn.SubmittedAt = &now
+	txn.RequiredApprovals = requirement.RequiredApprovals
+	txn.RequiredRoles = requirement.RequiredRoles
+
+	// Persist update
+	if _, err := s.Repo.UpdateTransactionStatus(ctx, txn); err != nil {
+		return nil, err
+	}
+
+	return s.voucherResponse(ctx, txn)
+}
+
+// ApproveVoucher records an approval on a submitted voucher. The person who raised the
+// voucher cannot approve it, and it becomes approved once the approvals its policies
+// require are all in.
+func (s *VoucherService) ApproveVoucher(ctx context.Context, id, approverID, approverRole string, req models.ReviewVoucherRequest) (*models.VoucherResponse, error) {
+	txn, err := s.getSubmittedVoucher(ctx, id, approverID)
+	if err != nil {
+		return nil, err
+	}
+	if !models.IsApproverRole(approverRole) {
+		return nil, models.Forbidden("only Admin, Treasurer or BoardChair users can approve vouchers")
+	}
+
+	approvals, err := s.Repo.GetVoucherApprovals(ctx, txn.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Once the remaining approvals are all spoken for by named roles, nobody else can fill them
+	remaining, missing := approvalProgress(txn, approvals)
+	if remaining <= len(missing) && !containsString(missing, approverRole) {
+		return nil, models.Conflict(fmt.Sprintf("voucher is waiting for approval from %s", strings.Join(missing, ", ")))
+	}
+
+	if err := s.recordDecision(ctx, txn, approverID, approverRole, models.VoucherApproved, req.Comment); err != nil {
+		return nil, err
+	}
+
+	approvals, err = s.Repo.GetVoucherApprovals(ctx, txn.ID)
+	if err != nil {
+		return nil, err
+	}
+	if remaining, _ := approvalProgress(txn, approvals); remaining > 0 {
+		return s.voucherResponse(ctx, txn)
+	}
+
+	txn.Status = string(models.TransactionApproved)
+
+	// Persist update
+	if _, err := s.Repo.UpdateTransactionStatus(ctx, txn); err != nil {
+		return nil, err
+	}
+
+	return s.voucherResponse(ctx, txn)
+}
+
+// RejectVoucher records a rejection on a submitted voucher, which then can only be deleted
+func (s *VoucherService) RejectVoucher(ctx context.Context, id, approverID, approverRole string, req models.ReviewVoucherRequest) (*models.VoucherResponse, error) {
+	txn, err := s.getSubmittedVoucher(ctx, id, approverID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Comment == nil || *req.Comment == "" {
+		return nil, models.Invalid("comment is required when rejecting a voucher")
+	}
+
+	if err := s.recordDecision(ctx, txn, approverID, approverRole, models.VoucherRejected, req.Comment); err != nil {
+		return nil, err
+	}
+
+	txn.Status = string(models.TransactionRejected)
+
+	// Persist update
+	if _, err := s.Repo.UpdateTransactionStatus(ctx, txn); err != nil {
+		return nil, err
+	}
+
+	return s.voucherResponse(ctx, txn)
+}
+
+// PostVoucher posts an approved voucher to the books so it starts affecting balances
+func (s *VoucherService) PostVoucher(ctx context.Context, id, userID string) (*models.VoucherResponse, error) {
+	txn, err := s.getVoucher(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if txn.Status != string(models.TransactionApproved) {
+		return nil, models.Conflict("only approved vouchers can be posted")
+	}
+
+	// Restricted funds may have been spent since the voucher was raised
+	if err := NewFundService(s.Repo).CheckVoucher(ctx, txn.ID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	txn.Status = string(models.TransactionPosted)
+	txn.PostedBy = &userID
+	txn.PostedAt = &now
+
+	// Persist update
+	if _, err := s.Repo.UpdateTransactionStatus(ctx, txn); err != nil {
+		return nil, err
+	}
+
+	return s.voucherResponse(ctx, txn)
+}
+
+// GetVoucher returns a voucher with its lines total and approval trail
+func (s *VoucherService) GetVoucher(ctx context.Context, id string) (*models.VoucherResponse, error) {
+	txn, err := s.getVoucher(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.voucherResponse(ctx, txn)
+}
+
+// GetVouchers returns vouchers, optionally filtered by status
+func (s *VoucherService) GetVouchers(ctx context.Context, status string) ([]models.TransactionResponse, error) {
+	var transactions []models.Transaction
+	var err error
+	if status != "" {
+		transactions, err = s.Repo.GetTransactionsByStatus(ctx, status)
+	} else {
+		transactions, err = s.Repo.GetVouchers(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response list
+	responses := make([]models.TransactionResponse, 0, len(transactions))
+	for _, t := range transactions {
+		if !t.IsVoucher() {
+			continue
+		}
+		responses = append(responses, *t.ToResponse())
+	}
+
+	return responses, nil
+}
+
+// getVoucher fetches a transaction and checks that it goes through the approval workflow
+func (s *VoucherService) getVoucher(ctx context.Context, id string) (models.Transaction, error) {
+	txn, err := s.Repo.GetTransaction(ctx, id)
+	if err != nil {
+		return models.Transaction{}, models.ErrVoucherNotFound
+	}
+	if !txn.IsVoucher() {
+		return models.Transaction{}, models.Invalid("transaction is not an expenses, withdrawal or transfer voucher")
+	}
+
+	return txn, nil
+}
+
+// getSubmittedVoucher fetches a voucher awaiting a decision from the given approver
+func (s *VoucherService) getSubmittedVoucher(ctx context.Context, id, approverID string) (models.Transaction, error) {
+	txn, err := s.getVoucher(ctx, id)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+	if txn.Status != string(models.TransactionSubmitted) {
+		return models.Transaction{}, models.Conflict("only submitted vouchers can be approved or rejected")
+	}
+	if txn.CreatedBy == approverID {
+		return models.Transaction{}, models.ErrSelfApproval
+	}
+
+	approvals, err := s.Repo.GetVoucherApprovals(ctx, txn.ID)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+	for _, a := range approvals {
+		if a.ApproverID == approverID {
+			return models.Transaction{}, models.Conflict("only one decision per approver is allowed on a voucher")
+		}
+	}
+
+	return txn, nil
+}
+
+// recordDecision saves an approver's decision on a voucher
+func (s *VoucherService) recordDecision(ctx context.Context, txn models.Transaction, approverID, approverRole string, decision models.VoucherDecision, comment *string) error {
+	approval := models.VoucherApproval{
+		TransactionID: txn.ID,
+		ApproverID:    approverID,
+		ApproverRole:  &approverRole,
+		Decision:      string(decision),
+		Comment:       comment,
+	}
+
+	_, err := s.Repo.CreateVoucherApproval(ctx, approval)
+	return err
+}
+
+// voucherResponse builds the voucher response with its lines total and approvals
+func (s *VoucherService) voucherResponse(ctx context.Context, txn models.Transaction) (*models.VoucherResponse, error) {
+	total, err := voucherLinesTotal(ctx, s.Repo, txn)
+	if err != nil {
+		return nil, err
+	}
+
+	approvals, err := s.Repo.GetVoucherApprovals(ctx, txn.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining, missing := approvalProgress(txn, approvals)
+	requiredRoles := []string(txn.RequiredRoles)
+	if requiredRoles == nil {
+		requiredRoles = []string{}
+	}
+
+	response := &models.VoucherResponse{
+		TransactionResponse: txn.ToResponse(),
+		LinesTotal:          total,
+		RequiredApprovals:   txn.RequiredApprovals,
+		RequiredRoles:       requiredRoles,
+		ApprovalsRemaining:  remaining,
+		MissingRoles:        missing,
+		Approvals:           make([]models.VoucherApprovalResponse, 0, len(approvals)),
+	}
+	for _, a := range approvals {
+		response.Approvals = append(response.Approvals, *a.ToResponse())
+	}
+
+	return response, nil
+}
+
+// approvalProgress returns how many more approvals a voucher needs and which required
+// roles have not approved it yet
+func approvalProgress(txn models.Transaction, approvals []models.VoucherApproval) (int, []string) {
+	approved := 0
+	roles := []string{}
+	for _, a := range approvals {
+		if a.Decision != string(models.VoucherApproved) {
+			continue
+		}
+		approved++
+		if a.ApproverRole != nil {
+			roles = append(roles, *a.ApproverRole)
+		}
+	}
+
+	missing := []string{}
+	for _, role := range txn.RequiredRoles {
+		if !containsString(roles, role) {
+			missing = append(missing, role)
+		}
+	}
+
+	remaining := txn.RequiredApprovals - approved
+	if remaining < len(missing) {
+		remaining = len(missing)
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return remaining, missing
+}
+
+// voucherAccounts returns the accounts a voucher touches: its debit account and the
+// accounts on its lines
+func voucherAccounts(ctx context.Context, repo repository.Store, txn models.Transaction) ([]string, error) {
+	accounts := []string{txn.DebitAccountID}
+
+	if txn.TransactionType == string(models.TransactionTransfer) {
+		transfers, err := repo.GetTransferByTransaction(ctx, txn.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range transfers {
+			accounts = append(accounts, t.CreditAccountID)
+		}
+		return accounts, nil
+	}
+
+	expenditures, err := repo.GetExpenditureByTransaction(ctx, txn.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range expenditures {
+		accounts = append(accounts, e.BankAccountID)
+	}
+	return accounts, nil
+}
+
+// voucherLinesTotal sums the expenditure or transfer lines of a voucher
+func voucherLinesTotal(ctx context.Context, repo repository.Store, txn models.Transaction) (float64, error) {
+	if txn.TransactionType == string(models.TransactionTransfer) {
+		return repo.GetTotalTransfersByTransaction(ctx, txn.ID)
+	}
+	return repo.GetTotalExpendituresByTransaction(ctx, txn.ID)
+}
+
+// ensureEditable stops changes to a voucher, or its lines, once it has left draft
+func ensureEditable(txn models.Transaction) error {
+	if txn.IsVoucher() && txn.Status != string(models.TransactionDraft) {
+		return models.ErrVoucherNotEditable
+	}
+	return nil
+}
+
+// ensureLinesEditable stops changes to the lines of a voucher that has left draft
+func ensureLinesEditable(ctx context.Context, transactions repository.TransactionRepository, transactionID string) error {
+	txn, err := transactions.GetTransaction(ctx, transactionID)
+	if err != nil {
+		return models.ErrTransactionNotFound
+	}
+	return ensureEditable(txn)
+}
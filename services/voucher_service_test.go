@@ -0,0 +1,203 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"storeHouse/models"
+	"testing"
+)
+
+func TestApproveVoucherRefusesItsCreator(t *testing.T) {
+	f := newFixture(t)
+	bank := f.account("Bank", models.AccountBank)
+	expense := f.account("Utilities", models.AccountExpense)
+	id := f.voucher(expense, bank, nil, 2000)
+
+	vouchers := NewVoucherService(f.repo)
+	if _, err := vouchers.SubmitVoucher(f.ctx, id, "clerk"); err != nil {
+		t.Fatalf("submit voucher: %v", err)
+	}
+
+	_, err := vouchers.ApproveVoucher(f.ctx, id, "clerk", string(models.RoleTreasurer), models.ReviewVoucherRequest{})
+	if !errors.Is(err, models.ErrSelfApproval) {
+		t.Fatalf("approve by creator: got %v, want %v", err, models.ErrSelfApproval)
+	}
+}
+
+func TestApproveVoucherNeedsAnApproverRole(t *testing.T) {
+	f := newFixture(t)
+	bank := f.account("Bank", models.AccountBank)
+	expense := f.account("Utilities", models.AccountExpense)
+	id := f.voucher(expense, bank, nil, 2000)
+
+	vouchers := NewVoucherService(f.repo)
+	if _, err := vouchers.SubmitVoucher(f.ctx, id, "clerk"); err != nil {
+		t.Fatalf("submit voucher: %v", err)
+	}
+
+	_, err := vouchers.ApproveVoucher(f.ctx, id, "another-clerk", string(models.RoleClerk), models.ReviewVoucherRequest{})
+	wantStatus(t, err, http.StatusForbidden)
+}
+
+func TestSubmitVoucherRequiresBalancedLines(t *testing.T) {
+	f := newFixture(t)
+	bank := f.account("Bank", models.AccountBank)
+	expense := f.account("Utilities", models.AccountExpense)
+	id := f.voucher(expense, bank, nil, 2000)
+
+	if _, err := f.expenditure(id, bank, nil, 500); err != nil {
+		t.Fatalf("create expenditure: %v", err)
+	}
+
+	_, err := NewVoucherService(f.repo).SubmitVoucher(f.ctx, id, "clerk")
+	wantStatus(t, err, http.StatusBadRequest)
+}
+
+func TestVoucherBelowPolicyThresholdNeedsOneApproval(t *testing.T) {
+	f := newFixture(t)
+	bank := f.account("Bank", models.AccountBank)
+	expense := f.account("Utilities", models.AccountExpense)
+	f.policy(10000, 2, models.RoleBoardChair)
+	id := f.voucher(expense, bank, nil, 10000)
+
+	vouchers := NewVoucherService(f.repo)
+	submitted, err := vouchers.SubmitVoucher(f.ctx, id, "clerk")
+	if err != nil {
+		t.Fatalf("submit voucher: %v", err)
+	}
+	if submitted.RequiredApprovals != 1 || len(submitted.RequiredRoles) != 0 {
+		t.Fatalf("got %d approvals from %v, want 1 from any approver", submitted.RequiredApprovals, submitted.RequiredRoles)
+	}
+
+	approved, err := vouchers.ApproveVoucher(f.ctx, id, "treasurer", string(models.RoleTreasurer), models.ReviewVoucherRequest{})
+	if err != nil {
+		t.Fatalf("approve voucher: %v", err)
+	}
+	if approved.Status != string(models.TransactionApproved) {
+		t.Fatalf("got status %s, want %s", approved.Status, models.TransactionApproved)
+	}
+}
+
+func TestVoucherOverPolicyThresholdNeedsItsRoles(t *testing.T) {
+	f := newFixture(t)
+	bank := f.account("Bank", models.AccountBank)
+	expense := f.account("Building works", models.AccountExpense)
+	f.policy(10000, 2, models.RoleBoardChair)
+	id := f.voucher(expense, bank, nil, 25000)
+
+	vouchers := NewVoucherService(f.repo)
+	submitted, err := vouchers.SubmitVoucher(f.ctx, id, "clerk")
+	if err != nil {
+		t.Fatalf("submit voucher: %v", err)
+	}
+	if submitted.RequiredApprovals != 2 || !containsString(submitted.RequiredRoles, string(models.RoleBoardChair)) {
+		t.Fatalf("got %d approvals from %v, want 2 including BoardChair", submitted.RequiredApprovals, submitted.RequiredRoles)
+	}
+
+	first, err := vouchers.ApproveVoucher(f.ctx, id, "treasurer", string(models.RoleTreasurer), models.ReviewVoucherRequest{})
+	if err != nil {
+		t.Fatalf("first approval: %v", err)
+	}
+	if first.Status != string(models.TransactionSubmitted) || first.ApprovalsRemaining != 1 {
+		t.Fatalf("after one approval got status %s with %d remaining, want submitted with 1", first.Status, first.ApprovalsRemaining)
+	}
+
+	// The last approval is reserved for the board chair
+	_, err = vouchers.ApproveVoucher(f.ctx, id, "admin", string(models.RoleAdmin), models.ReviewVoucherRequest{})
+	wantConflict(t, err)
+
+	// Nobody approves twice
+	_, err = vouchers.ApproveVoucher(f.ctx, id, "treasurer", string(models.RoleBoardChair), models.ReviewVoucherRequest{})
+	wantConflict(t, err)
+
+	approved, err := vouchers.ApproveVoucher(f.ctx, id, "chair", string(models.RoleBoardChair), models.ReviewVoucherRequest{})
+	if err != nil {
+		t.Fatalf("board chair approval: %v", err)
+	}
+	if approved.Status != string(models.TransactionApproved) {
+		t.Fatalf("got status %s, want %s", approved.Status, models.TransactionApproved)
+	}
+}
+
+func TestPostVoucherOnlyOnceApproved(t *testing.T) {
+	f := newFixture(t)
+	bank := f.account("Bank", models.AccountBank)
+	expense := f.account("Utilities", models.AccountExpense)
+	id := f.voucher(expense, bank, nil, 2000)
+
+	vouchers := NewVoucherService(f.repo)
+	if _, err := vouchers.SubmitVoucher(f.ctx, id, "clerk"); err != nil {
+		t.Fatalf("submit voucher: %v", err)
+	}
+
+	_, err := vouchers.PostVoucher(f.ctx, id, "treasurer")
+	wantConflict(t, err)
+}
+
+// policy adds an active policy on expenses vouchers over amountOver
+func (f *fixture) policy(amountOver float64, approvals int, roles ...models.UserRole) {
+	f.t.Helper()
+	txnType := string(models.TransactionExpenses)
+	required := make([]string, 0, len(roles))
+	for _, role := range roles {
+		required = append(required, string(role))
+	}
+	_, err := NewApprovalPolicyService(f.repo).CreatePolicy(f.ctx, models.CreateApprovalPolicyRequest{
+		Name:              "Large expenses",
+		TransactionType:   &txnType,
+		AmountOver:        amountOver,
+		RequiredApprovals: approvals,
+		RequiredRoles:     required,
+	}, "admin")
+	if err != nil {
+		f.t.Fatalf("create approval policy: %v", err)
+	}
+}
+
+func TestPolicyOnHeaderAccountCoversItsChildren(t *testing.T) {
+	f := newFixture(t)
+	accounts := NewAccountService(f.repo)
+	header, err := accounts.CreateAccount(f.ctx, models.CreateAccountRequest{
+		AccountName: "Building",
+		AccountType: string(models.AccountExpense),
+		IsHeader:    true,
+	}, "admin")
+	if err != nil {
+		t.Fatalf("create header account: %v", err)
+	}
+	works, err := accounts.CreateAccount(f.ctx, models.CreateAccountRequest{
+		AccountName: "Building works",
+		AccountType: string(models.AccountExpense),
+		ParentID:    &header.ID,
+	}, "admin")
+	if err != nil {
+		t.Fatalf("create child account: %v", err)
+	}
+	utilities := f.account("Utilities", models.AccountExpense)
+
+	policies := NewApprovalPolicyService(f.repo)
+	if _, err := policies.CreatePolicy(f.ctx, models.CreateApprovalPolicyRequest{
+		Name:              "Building project",
+		AccountID:         &header.ID,
+		RequiredApprovals: 2,
+	}, "admin"); err != nil {
+		t.Fatalf("create approval policy: %v", err)
+	}
+
+	for _, tt := range []struct {
+		account string
+		want    int
+	}{{works.ID, 2}, {utilities, 1}} {
+		requirement, err := policies.EvaluateRequest(f.ctx, models.EvaluateApprovalPolicyRequest{
+			TransactionType: string(models.TransactionExpenses),
+			AccountIDs:      []string{tt.account},
+			Amount:          5000,
+		})
+		if err != nil {
+			t.Fatalf("evaluate: %v", err)
+		}
+		if requirement.RequiredApprovals != tt.want {
+			t.Errorf("account %s needs %d approvals, want %d", tt.account, requirement.RequiredApprovals, tt.want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Supported storage backends
+const (
+	BackendLocal = "local"
+)
+
+// Defaults used when the environment does not override them
+const (
+	DefaultDir       = "uploads/attachments"
+	DefaultMaxSizeMB = 10
+)
+
+// Config holds the attachment storage settings
+type Config struct {
+	// Backend selects where files are kept; only local is built in
+	Backend string
+	// Dir is the root directory of the local backend
+	Dir string
+	// MaxSize is the largest file accepted, in bytes
+	MaxSize int64
+}
+
+// LoadConfig reads the attachment storage settings from environment variables
+func LoadConfig() Config {
+	cfg := Config{
+		Backend: strings.ToLower(strings.TrimSpace(os.Getenv("ATTACHMENT_STORAGE"))),
+		Dir:     strings.TrimSpace(os.Getenv("ATTACHMENT_DIR")),
+		MaxSize: DefaultMaxSizeMB << 20,
+	}
+
+	if cfg.Backend == "" {
+		cfg.Backend = BackendLocal
+	}
+	if cfg.Dir == "" {
+		cfg.Dir = DefaultDir
+	}
+	if size, err := strconv.Atoi(os.Getenv("ATTACHMENT_MAX_SIZE_MB")); err == nil && size > 0 {
+		cfg.MaxSize = int64(size) << 20
+	}
+
+	return cfg
+}
@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrNotFound is returned when no file is stored under a key
+var ErrNotFound = errors.New("stored file not found")
+
+// Store keeps attachment files under opaque keys chosen by the caller
+type Store interface {
+	// Put writes the content under the key; writing to an existing key is an error
+	Put(key string, content io.Reader) (int64, error)
+	// Open returns the content stored under the key
+	Open(key string) (io.ReadCloser, error)
+	// Delete removes the content stored under the key; a missing key is not an error
+	Delete(key string) error
+}
+
+// NewStore returns the store selected by the configuration
+func NewStore(cfg Config) (Store, error) {
+	switch cfg.Backend {
+	case BackendLocal:
+		return NewLocalStore(cfg.Dir), nil
+	default:
+		return nil, fmt.Errorf("unsupported ATTACHMENT_STORAGE %q", cfg.Backend)
+	}
+}